@@ -8,6 +8,7 @@ require (
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20200420212212-258d9bec320e
 	github.com/go-gl/mathgl v0.0.0-20190713194549-592312d8590a
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
+	github.com/jfreymuth/oggvorbis v1.0.5
 	github.com/udhos/gwob v0.0.0-20200524213453-619810f75817
 	golang.org/x/image v0.0.0-20190321063152-3fc05d484e9f
 )
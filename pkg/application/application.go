@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/akosgarai/playground_engine/pkg/config"
+	"github.com/akosgarai/playground_engine/pkg/input"
 	"github.com/akosgarai/playground_engine/pkg/interfaces"
 	"github.com/akosgarai/playground_engine/pkg/pointer"
 	"github.com/akosgarai/playground_engine/pkg/screen"
@@ -14,6 +15,7 @@ import (
 	"github.com/akosgarai/playground_engine/pkg/texture"
 	"github.com/akosgarai/playground_engine/pkg/theme"
 	"github.com/akosgarai/playground_engine/pkg/transformations"
+	"github.com/akosgarai/playground_engine/pkg/ui"
 
 	"github.com/akosgarai/coldet"
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -62,11 +64,37 @@ type Application struct {
 
 	keyDowns interfaces.KeyStore
 
+	// gamepad state and the joystick that it is polled from.
+	gamepad     interfaces.GamepadStore
+	joystick    glfw.Joystick
+	joystickSet bool
+
+	// the action map of the application. Without it the hardcoded keys are used.
+	actions *input.ActionMap
+	// the input recorder. It is nil, if the input is not recorded.
+	recorder *recorder
+
 	// screens
 	activeScreen interfaces.Screen
 	screens      []interfaces.Screen
 	menuScreen   interfaces.Screen
 	menuSet      bool
+	// the screens that are displayed on top of the active screen, eg. the hud.
+	overlays []overlay
+	// the paused screens of the navigation, the last one is the previous screen.
+	history []interfaces.Screen
+	// the transition of the back navigation.
+	backTransition Transition
+	// the running transition, it is nil if the screens are not changing.
+	transition *transitionState
+	// the render targets and the shader of the transitions. They are created for the first transition.
+	transitionRenderer *transitionRenderer
+	transitionShader   interfaces.Shader
+
+	// the store of the settings. In watch mode the reloaded values are pushed to the forms.
+	configStore *config.Store
+	// it is called with the error of the config reload.
+	configErrorCallback func(error)
 
 	// wrapper for char callback
 	wrapper interfaces.GLWrapper
@@ -82,6 +110,7 @@ func New(wrapper interfaces.GLWrapper) *Application {
 	return &Application{
 		mouseDowns: store.NewGlfwMouseStore(),
 		keyDowns:   store.NewGlfwKeyStore(),
+		gamepad:    store.NewGlfwGamepadStore(),
 		menuSet:    false,
 		wrapper:    wrapper,
 		window:     nil,
@@ -127,6 +156,10 @@ func (a *Application) SetWindow(w Window) {
 	MousePosX, MousePosY := a.window.GetCursorPos()
 	WindowWidth, WindowHeight := a.window.GetSize()
 	a.mouseUpdatePositionX, a.mouseUpdatePositionY = transformations.MouseCoordinates(MousePosX, MousePosY, float64(WindowWidth), float64(WindowHeight))
+	// the glfw window means that the glfw is initialized, the connected gamepads could be detected.
+	if _, ok := w.(*glfw.Window); ok {
+		a.DetectJoystick()
+	}
 }
 
 // GetWindow returns the current window of the application.
@@ -144,16 +177,226 @@ func (a *Application) GetClosestModelMeshDistance() (interfaces.Model, interface
 	return a.activeScreen.GetClosestModelMeshDistance()
 }
 
-// Update sets up the mouse coordinates and calls Update function on the activeScreen.
+// Update sets up the mouse coordinates and calls Update function on the overlays from the
+// top one, then on the activeScreen. The overlays could hide the input from the screens below them.
 func (a *Application) Update(dt float64) {
+	a.update(dt, a.pollGamepad())
+}
+
+// update is the Update with the given gamepad state. The replay uses the recorded state
+// instead of polling the joystick.
+func (a *Application) update(dt float64, gamepad *GamepadFrame) {
+	if a.configStore != nil {
+		if err := a.configStore.Update(dt); err != nil && a.configErrorCallback != nil {
+			a.configErrorCallback(err)
+		}
+	}
 	MousePosX, MousePosY := a.window.GetCursorPos()
 	WindowWidth, WindowHeight := a.window.GetSize()
+	if a.recorder != nil {
+		a.recorder.record(dt, MousePosX, MousePosY, WindowWidth, WindowHeight, gamepad)
+	}
 	mX, mY := transformations.MouseCoordinates(MousePosX, MousePosY, float64(WindowWidth), float64(WindowHeight))
 	// Pointer
 	p := pointer.New(mX, mY, a.mouseUpdatePositionX-mX, a.mouseUpdatePositionY-mY)
 	a.mouseUpdatePositionX = mX
 	a.mouseUpdatePositionY = mY
-	a.activeScreen.Update(dt, p, a.keyDowns, a.mouseDowns)
+	var actions interfaces.RoActionMap
+	if a.actions != nil {
+		a.actions.Update(dt, a.keyDowns, a.mouseDowns, a.gamepad)
+		// the running transition can't be interrupted with another navigation.
+		if a.actions.IsActive(input.ACTION_MENU) && !a.IsTransitionRunning() && !a.capturingKey() {
+			a.back()
+		}
+		actions = a.actions
+	}
+	keyStore, buttonStore, gamepadStore, actions := a.updateOverlays(dt, p, actions)
+	// the input is ignored during the transitions.
+	if a.updateTransition(dt) {
+		keyStore = store.NewGlfwKeyStore()
+		buttonStore = store.NewGlfwMouseStore()
+		gamepadStore = store.NewGlfwGamepadStore()
+		actions = nil
+	}
+	if gs, ok := a.activeScreen.(interfaces.GamepadScreen); ok {
+		gs.SetGamepadStore(gamepadStore)
+	}
+	if as, ok := a.activeScreen.(interfaces.ActionScreen); ok {
+		as.SetActionMap(actions)
+	}
+	a.activeScreen.Update(dt, p, keyStore, buttonStore)
+}
+
+// SetConfigStore sets the store of the settings. Its watch mode is updated by the Update
+// function, the reloaded values are pushed to the form screens of the application.
+func (a *Application) SetConfigStore(s *config.Store) {
+	a.configStore = s
+	s.OnReload(a.reloadConfig)
+}
+
+// OnConfigError sets the callback, that is called with the error of the config reload,
+// eg. the edited file is invalid. The values of the invalid file are not applied.
+func (a *Application) OnConfigError(callback func(error)) {
+	a.configErrorCallback = callback
+}
+
+// GetConfigStore returns the store of the settings.
+func (a *Application) GetConfigStore() *config.Store {
+	return a.configStore
+}
+
+// reloadConfig calls the ReloadConfig function of the form screens with the changed config keys.
+func (a *Application) reloadConfig(keys []string) {
+	for i := 0; i < len(a.screens); i++ {
+		if form, ok := a.screens[i].(*screen.FormScreen); ok {
+			form.ReloadConfig(keys)
+		}
+	}
+}
+
+// SetActionMap sets the action map of the application. The menu action opens the menu
+// instead of the escape key, the screens use the actions instead of their hardcoded keys.
+// The context of the map follows the active screen: it is menu for the menu and form
+// screens, game for the others. The nil map turns back the hardcoded keys.
+func (a *Application) SetActionMap(m *input.ActionMap) {
+	a.actions = m
+	if a.actions != nil && a.activeScreen != nil {
+		a.actions.SetContext(screenInputContext(a.activeScreen))
+	}
+}
+
+// GetActionMap returns the action map of the application.
+func (a *Application) GetActionMap() *input.ActionMap {
+	return a.actions
+}
+
+// screenInputContext returns the context of the actions for the given screen.
+func screenInputContext(s interfaces.Screen) string {
+	switch s.(type) {
+	case *screen.MenuScreen, *screen.FormScreen, *ui.Layer:
+		return input.CONTEXT_MENU
+	case *ui.OverlayScreen:
+		return screenInputContext(s.(*ui.OverlayScreen).GetBase())
+	}
+	return input.CONTEXT_GAME
+}
+
+// SetJoystick sets the joystick, that the gamepad state is polled from.
+func (a *Application) SetJoystick(joy glfw.Joystick) {
+	a.joystick = joy
+	a.joystickSet = true
+}
+
+// UnsetJoystick stops the gamepad polling and releases the gamepad state.
+func (a *Application) UnsetJoystick() {
+	a.joystickSet = false
+	a.gamepad.Clear()
+}
+
+// GetJoystick returns the current joystick and true, or false if it is not set.
+func (a *Application) GetJoystick() (glfw.Joystick, bool) {
+	return a.joystick, a.joystickSet
+}
+
+// JoystickCallback is responsible for the joystick connection event handling.
+// The first connected joystick is used, it is unset when it is disconnected.
+func (a *Application) JoystickCallback(joy glfw.Joystick, event glfw.PeripheralEvent) {
+	switch event {
+	case glfw.Connected:
+		if !a.joystickSet {
+			a.SetJoystick(joy)
+		}
+		break
+	case glfw.Disconnected:
+		if a.joystickSet && a.joystick == joy {
+			a.UnsetJoystick()
+		}
+		break
+	}
+}
+
+// DetectJoystick sets the first connected gamepad as joystick, if the joystick is not set yet.
+// The JoystickCallback is called only for the later connections, so that the gamepads, that are
+// connected before the start, have to be detected. It is called by the SetWindow function.
+func (a *Application) DetectJoystick() {
+	a.detectJoystick(glfw.Joystick.IsGamepad)
+}
+
+// detectJoystick sets the first joystick, that is gamepad by the given function.
+func (a *Application) detectJoystick(isGamepad func(glfw.Joystick) bool) {
+	if a.joystickSet {
+		return
+	}
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		if isGamepad(joy) {
+			a.SetJoystick(joy)
+			return
+		}
+	}
+}
+
+// UpdateGamepadMappings adds the given SDL_GameControllerDB mappings to the
+// standard mappings of glfw. It returns false if the mapping is invalid.
+func (a *Application) UpdateGamepadMappings(mapping string) bool {
+	return glfw.UpdateGamepadMappings(mapping)
+}
+
+// pollGamepad updates the gamepad state from the joystick. It returns the polled state
+// for the recording, or nil if the joystick is not set.
+func (a *Application) pollGamepad() *GamepadFrame {
+	if !a.joystickSet {
+		return nil
+	}
+	g := readGamepad(a.joystick)
+	a.applyGamepad(g)
+	return g
+}
+
+// readGamepad returns the state of the joystick. The joysticks with standard mapping are
+// read as gamepad, the others are read as raw joystick.
+func readGamepad(joy glfw.Joystick) *GamepadFrame {
+	g := &GamepadFrame{Present: joy.Present()}
+	if !g.Present {
+		return g
+	}
+	var axes []float32
+	var buttons []glfw.Action
+	if joy.IsGamepad() {
+		state := joy.GetGamepadState()
+		if state == nil {
+			return g
+		}
+		axes, buttons = state.Axes[:], state.Buttons[:]
+	} else {
+		axes, buttons = joy.GetAxes(), joy.GetButtons()
+	}
+	g.Axes = append([]float32{}, axes...)
+	g.Buttons = make([]bool, len(buttons))
+	for i := range buttons {
+		g.Buttons[i] = buttons[i] != glfw.Release
+	}
+	return g
+}
+
+// applyGamepad updates the gamepad store with the given state. The disconnected
+// gamepad is released.
+func (a *Application) applyGamepad(g *GamepadFrame) {
+	if !g.Present {
+		a.gamepad.Clear()
+		return
+	}
+	buttons := make([]glfw.Action, len(g.Buttons))
+	for i := range g.Buttons {
+		if g.Buttons[i] {
+			buttons[i] = glfw.Press
+		}
+	}
+	a.gamepad.SetJoystickState(g.Axes, buttons)
+}
+
+// SetGamepadDeadZone sets the dead zone of the gamepad axes.
+func (a *Application) SetGamepadDeadZone(deadZone float32) {
+	a.gamepad.SetDeadZone(deadZone)
 }
 
 // AddScreen appends the screen to screens.
@@ -166,9 +409,37 @@ func (a *Application) AddScreen(s interfaces.Screen) {
 	a.screens = append(a.screens, s)
 }
 
-// ActivateScreen sets the given screen to active screen
+// ActivateScreen sets the given screen to active screen without transition. The navigation
+// history is cleared, the screens that are leaving get the OnExit hook, the new screen gets
+// the OnEnter hook (or OnResume, if it was paused).
 func (a *Application) ActivateScreen(s interfaces.Screen) {
+	resumed := false
+	for i := range a.history {
+		if a.history[i] == s {
+			resumed = true
+		} else {
+			callHook(a.history[i], interfaces.LifecycleScreen.OnExit)
+		}
+	}
+	a.history = nil
+	if s == a.activeScreen {
+		return
+	}
+	callHook(a.activeScreen, interfaces.LifecycleScreen.OnExit)
+	a.changeScreen(a.activeScreen, s, NewTransition(TRANSITION_NONE, 0))
+	if resumed {
+		callHook(s, interfaces.LifecycleScreen.OnResume)
+	} else {
+		callHook(s, interfaces.LifecycleScreen.OnEnter)
+	}
+}
+
+// activateScreen sets the given screen to active screen and updates the context of the action map.
+func (a *Application) activateScreen(s interfaces.Screen) {
 	a.activeScreen = s
+	if a.actions != nil {
+		a.actions.SetContext(screenInputContext(s))
+	}
 }
 
 // MenuScreen sets the given screen to menu screen and the menuSet
@@ -178,23 +449,34 @@ func (a *Application) MenuScreen(s interfaces.Screen) {
 	a.menuSet = true
 }
 
-// Draw calls Draw function on the activeScreen.
+// Draw calls Draw function on the activeScreen, then on the overlays. During the
+// transitions the previous and the active screens are drawn with the transition effect.
 func (a *Application) Draw(wrapper interfaces.GLWrapper) {
-	a.activeScreen.Draw(wrapper)
+	if a.transition != nil {
+		a.drawTransition(wrapper)
+	} else {
+		a.activeScreen.Draw(wrapper)
+	}
+	a.drawOverlays(wrapper)
 }
 
 // KeyCallback is responsible for the keyboard event handling.
 // After configuring the keyboard well, the esc character seems to
-// be working well.
+// be working well. If the action map is set, the escape key is
+// stored as the other keys and the menu action navigates back.
 func (a *Application) KeyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-	switch key {
-	case ESCAPE:
-		if a.menuSet {
-			a.window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
-			a.window.SetInputMode(glfw.RawMouseMotion, 0)
-			a.ActivateScreen(a.menuScreen)
-		} else {
-			a.window.SetShouldClose(true)
+	a.keyEvent(key, action, mods)
+}
+
+// keyEvent handles the key event of the KeyCallback or the replay.
+func (a *Application) keyEvent(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) {
+	if a.recorder != nil {
+		a.recorder.addEvent(RecordedEvent{Type: EVENT_KEY, Code: int(key), Action: int(action), Mods: int(mods)})
+	}
+	switch {
+	case key == ESCAPE && a.actions == nil && !a.capturingKey():
+		if action == glfw.Press && !a.IsTransitionRunning() {
+			a.back()
 		}
 		break
 	default:
@@ -203,9 +485,42 @@ func (a *Application) KeyCallback(w *glfw.Window, key glfw.Key, scancode int, ac
 	}
 }
 
+// capturingKey returns true, if the active screen waits for a key, so that the escape key
+// has to be passed to it instead of the back navigation.
+func (a *Application) capturingKey() bool {
+	cs, ok := a.activeScreen.(interfaces.CaptureScreen)
+	return ok && cs.IsCapturingKey()
+}
+
+// back returns to the previous screen with the back transition. Without history it pushes
+// the menu screen if it is set, otherwise it closes the window.
+func (a *Application) back() {
+	if a.PopScreen(a.backTransition) {
+		return
+	}
+	if a.menuSet {
+		a.window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+		a.window.SetInputMode(glfw.RawMouseMotion, 0)
+		if a.activeScreen != a.menuScreen {
+			a.PushScreen(a.menuScreen, a.backTransition)
+		}
+	} else {
+		a.window.SetShouldClose(true)
+	}
+}
+
 // MouseButtonCallback is responsible for the mouse button event handling.
 func (a *Application) MouseButtonCallback(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
-	a.MousePosX, a.MousePosY = w.GetCursorPos()
+	x, y := w.GetCursorPos()
+	a.mouseButtonEvent(button, action, mods, x, y)
+}
+
+// mouseButtonEvent handles the mouse button event of the MouseButtonCallback or the replay.
+func (a *Application) mouseButtonEvent(button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey, x, y float64) {
+	if a.recorder != nil {
+		a.recorder.addEvent(RecordedEvent{Type: EVENT_MOUSE_BUTTON, Code: int(button), Action: int(action), Mods: int(mods), X: x, Y: y})
+	}
+	a.MousePosX, a.MousePosY = x, y
 	switch button {
 	default:
 		a.SetButtonState(button, action)
@@ -215,6 +530,17 @@ func (a *Application) MouseButtonCallback(w *glfw.Window, button glfw.MouseButto
 
 // CharCallback is responsible for the character stream input (typing on keyboard)
 func (a *Application) CharCallback(w *glfw.Window, char rune) {
+	a.charEvent(char)
+}
+
+// charEvent handles the character event of the CharCallback or the replay.
+func (a *Application) charEvent(char rune) {
+	if a.recorder != nil {
+		a.recorder.addEvent(RecordedEvent{Type: EVENT_CHAR, Char: char})
+	}
+	if a.overlayCharCallback(char) {
+		return
+	}
 	if a.activeScreen != nil {
 		a.activeScreen.CharCallback(char, a.wrapper)
 	}
@@ -252,23 +578,39 @@ func (a *Application) GetKeyState(key glfw.Key) bool {
 	return a.keyDowns.Get(key)
 }
 
-// SetUniformFloat loops on screens and sets the given float value to the given string key in
+// GetGamepadButtonState returns the state of the given gamepad button
+func (a *Application) GetGamepadButtonState(button glfw.GamepadButton) bool {
+	return a.gamepad.Get(button)
+}
+
+// GetGamepadAxisState returns the value of the given gamepad axis with the dead zone applied.
+func (a *Application) GetGamepadAxisState(axis glfw.GamepadAxis) float32 {
+	return a.gamepad.GetAxis(axis)
+}
+
+// SetUniformFloat loops on screens and overlays and sets the given float value to the given string key in
 // the uniformFloat map of the screen.
 func (a *Application) SetUniformFloat(key string, value float32) {
 	for i, _ := range a.screens {
 		a.screens[i].SetUniformFloat(key, value)
 	}
+	for i := range a.overlays {
+		a.overlays[i].screen.SetUniformFloat(key, value)
+	}
 	if a.activeScreen != nil {
 		a.activeScreen.SetUniformFloat(key, value)
 	}
 }
 
-// SetUniformVector loops on screens and sets the given mgl32.Vec3 value to the given string key in
+// SetUniformVector loops on screens and overlays and sets the given mgl32.Vec3 value to the given string key in
 // the uniformVector map of the screen.
 func (a *Application) SetUniformVector(key string, value mgl32.Vec3) {
 	for i, _ := range a.screens {
 		a.screens[i].SetUniformVector(key, value)
 	}
+	for i := range a.overlays {
+		a.overlays[i].screen.SetUniformVector(key, value)
+	}
 	if a.activeScreen != nil {
 		a.activeScreen.SetUniformVector(key, value)
 	}
@@ -347,3 +689,19 @@ func (a *Application) BuildFormScreen(settings config.Config, order []string, la
 	builder.SetConfigOrder(order)
 	return builder.Build()
 }
+
+// BuildUILayer creates an empty ui layer based on the current theme. The layer could
+// be used as a screen or on top of other screens with the ui.OverlayScreen. In case of
+// missing window it panics.
+func (a *Application) BuildUILayer() *ui.Layer {
+	if a.window == nil {
+		panic("Window is missing.")
+	}
+	ww, wh := a.window.GetSize()
+	t := a.ui
+	builder := ui.NewLayerBuilder()
+	builder.SetWrapper(a.wrapper)
+	builder.SetWindowSize(float32(ww), float32(wh))
+	builder.SetTheme(&t)
+	return builder.Build()
+}
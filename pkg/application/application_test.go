@@ -1,9 +1,15 @@
 package application
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/akosgarai/playground_engine/pkg/camera"
+	"github.com/akosgarai/playground_engine/pkg/config"
+	"github.com/akosgarai/playground_engine/pkg/input"
 	"github.com/akosgarai/playground_engine/pkg/screen"
 	"github.com/akosgarai/playground_engine/pkg/testhelper"
 
@@ -260,6 +266,44 @@ func TestSetWrapper(t *testing.T) {
 		t.Error("Invalid wrapper mock")
 	}
 }
+func TestSetConfigStore(t *testing.T) {
+	app := New(wrapperMock)
+	s := config.NewStore(config.New(), "settings.json")
+	app.SetConfigStore(s)
+	if app.GetConfigStore() != s {
+		t.Error("Invalid config store")
+	}
+}
+func TestOnConfigError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("Temp dir error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "settings.json")
+	s := config.NewStore(config.New(), path)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save error: %s", err.Error())
+	}
+	s.Watch(10)
+	var drawLog, hookLog []string
+	app := New(wrapperMock)
+	app.SetWindow(wm)
+	app.ActivateScreen(newLifecycleMock("game", &drawLog, &hookLog))
+	app.SetConfigStore(s)
+	var reloadErr error
+	app.OnConfigError(func(err error) { reloadErr = err })
+	if err := ioutil.WriteFile(path, []byte(`{"values": `), 0644); err != nil {
+		t.Fatalf("Write error: %s", err.Error())
+	}
+	// the modification time has to be different from the time of the save.
+	future := time.Now().Add(time.Second)
+	os.Chtimes(path, future, future)
+	app.Update(20)
+	if reloadErr == nil {
+		t.Error("The reload error should be passed to the callback.")
+	}
+}
 func TestGetWrapper(t *testing.T) {
 	app := New(wrapperMock)
 	app.SetWrapper(wrapperMock)
@@ -267,3 +311,109 @@ func TestGetWrapper(t *testing.T) {
 		t.Error("Invalid wrapper mock")
 	}
 }
+func TestDetectJoystick(t *testing.T) {
+	app := New(wrapperMock)
+	app.detectJoystick(func(joy glfw.Joystick) bool { return false })
+	if _, ok := app.GetJoystick(); ok {
+		t.Error("Joystick shouldn't be set without gamepad.")
+	}
+	app.detectJoystick(func(joy glfw.Joystick) bool { return joy >= glfw.Joystick3 })
+	if joy, ok := app.GetJoystick(); !ok || joy != glfw.Joystick3 {
+		t.Errorf("Invalid joystick. '%v', '%v'.", joy, ok)
+	}
+	// the set joystick is kept.
+	app.detectJoystick(func(joy glfw.Joystick) bool { return true })
+	if joy, _ := app.GetJoystick(); joy != glfw.Joystick3 {
+		t.Errorf("The joystick shouldn't be changed. '%v'.", joy)
+	}
+}
+func TestJoystickCallback(t *testing.T) {
+	app := New(wrapperMock)
+	if _, ok := app.GetJoystick(); ok {
+		t.Error("Joystick shouldn't be set.")
+	}
+	app.JoystickCallback(glfw.Joystick2, glfw.Connected)
+	if joy, ok := app.GetJoystick(); !ok || joy != glfw.Joystick2 {
+		t.Errorf("Invalid joystick. '%v', '%v'.", joy, ok)
+	}
+	// the first joystick is kept.
+	app.JoystickCallback(glfw.Joystick3, glfw.Connected)
+	if joy, _ := app.GetJoystick(); joy != glfw.Joystick2 {
+		t.Errorf("Invalid joystick. '%v'.", joy)
+	}
+	app.JoystickCallback(glfw.Joystick3, glfw.Disconnected)
+	if _, ok := app.GetJoystick(); !ok {
+		t.Error("Joystick should be kept.")
+	}
+	app.gamepad.Set(glfw.ButtonA, true)
+	app.JoystickCallback(glfw.Joystick2, glfw.Disconnected)
+	if _, ok := app.GetJoystick(); ok {
+		t.Error("Joystick should be unset.")
+	}
+	if app.GetGamepadButtonState(glfw.ButtonA) {
+		t.Error("Gamepad should be released after disconnect.")
+	}
+}
+func TestGetGamepadState(t *testing.T) {
+	app := New(wrapperMock)
+	app.gamepad.Set(glfw.ButtonX, true)
+	if !app.GetGamepadButtonState(glfw.ButtonX) {
+		t.Error("X should be pressed")
+	}
+	app.SetGamepadDeadZone(0.5)
+	app.gamepad.SetAxis(glfw.AxisLeftX, 0.4)
+	if app.GetGamepadAxisState(glfw.AxisLeftX) != 0.0 {
+		t.Error("Axis should be in the dead zone")
+	}
+	app.gamepad.SetAxis(glfw.AxisLeftX, 1.0)
+	if app.GetGamepadAxisState(glfw.AxisLeftX) != 1.0 {
+		t.Error("Axis should be pushed")
+	}
+}
+func TestSetActionMap(t *testing.T) {
+	app := New(wrapperMock)
+	if app.GetActionMap() != nil {
+		t.Error("Action map shouldn't be set.")
+	}
+	actions := input.DefaultActionMap()
+	actions.SetContext(input.CONTEXT_MENU)
+	s := screen.New()
+	app.ActivateScreen(s)
+	app.SetActionMap(actions)
+	if app.GetActionMap() != actions {
+		t.Error("Invalid action map.")
+	}
+	if !actions.IsContextEnabled(input.CONTEXT_GAME) || actions.IsContextEnabled(input.CONTEXT_MENU) {
+		t.Error("The context should follow the active screen.")
+	}
+}
+func TestUpdateWithActionMap(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("Shouldn't have panic.")
+			}
+		}()
+		app := New(wrapperMock)
+		app.SetWindow(wm)
+		s := screen.New()
+		app.AddScreen(s)
+		app.ActivateScreen(s)
+		menu := screen.New()
+		app.MenuScreen(menu)
+		app.SetActionMap(input.DefaultActionMap())
+		app.SetKeyState(glfw.KeyW, glfw.Press)
+		app.Update(10)
+		if !app.GetActionMap().IsActive(input.ACTION_FORWARD) {
+			t.Error("Forward should be active.")
+		}
+		// the menu action opens the menu screen.
+		app.SetKeyState(glfw.KeyEscape, glfw.Press)
+		app.Update(10)
+		if app.activeScreen != menu {
+			t.Error("Menu should be activated.")
+		}
+		app.SetActionMap(nil)
+		app.Update(10)
+	}()
+}
@@ -0,0 +1,243 @@
+package application
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/primitives/rectangle"
+	"github.com/akosgarai/playground_engine/pkg/shader"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The screen is changed instantly.
+	TRANSITION_NONE = iota
+	// The previous screen fades out to the color, then the next one fades in.
+	TRANSITION_FADE
+	// The previous screen is blended to the next one.
+	TRANSITION_CROSSFADE
+	// The next screen pushes out the previous one in the given direction.
+	TRANSITION_SLIDE_LEFT
+	TRANSITION_SLIDE_RIGHT
+	TRANSITION_SLIDE_UP
+	TRANSITION_SLIDE_DOWN
+)
+
+// The modes of the transition shader.
+const (
+	transitionModeFade      = 1
+	transitionModeCrossfade = 2
+	transitionModeSlide     = 3
+)
+
+// Transition describes the animation of a screen change.
+type Transition struct {
+	// One of the TRANSITION_* constants.
+	Type int
+	// The length of the animation in ms.
+	Duration float64
+	// The color of the fade transition.
+	Color mgl32.Vec3
+}
+
+// NewTransition returns a transition with the given type and duration (ms). The color
+// of the fade is black.
+func NewTransition(transitionType int, duration float64) Transition {
+	return Transition{
+		Type:     transitionType,
+		Duration: duration,
+		Color:    mgl32.Vec3{0, 0, 0},
+	}
+}
+
+// transitionState is the running transition.
+type transitionState struct {
+	transition Transition
+	from       interfaces.Screen
+	elapsed    float64
+}
+
+// progress returns the state of the transition in the [0-1] interval.
+func (t *transitionState) progress() float32 {
+	return float32(t.elapsed / t.transition.Duration)
+}
+
+// transitionRenderer holds the offscreen targets of the screens and the screen sized quad.
+type transitionRenderer struct {
+	from, to      *texture.RenderTarget
+	quad          *mesh.TexturedMesh
+	width, height int
+}
+
+// newTransitionRenderer creates the render targets with the given size and the quad.
+func newTransitionRenderer(width, height int, wrapper interfaces.GLWrapper) *transitionRenderer {
+	var textures texture.Textures
+	r := &transitionRenderer{
+		width:  width,
+		height: height,
+	}
+	r.from = textures.AddRenderTarget(width, height, "fromScreen", wrapper)
+	r.to = textures.AddRenderTarget(width, height, "toScreen", wrapper)
+	v, i, _ := rectangle.NewExact(2, 2).MeshInput()
+	r.quad = mesh.NewTexturedMesh(v, i, textures, wrapper)
+	r.quad.RotateX(-90)
+	return r
+}
+
+// delete releases the render targets and the quad.
+func (r *transitionRenderer) delete() {
+	r.from.Delete()
+	r.to.Delete()
+	r.quad.Delete()
+}
+
+// callHook calls the hook of the screen, if it implements the LifecycleScreen interface.
+func callHook(s interfaces.Screen, hook func(interfaces.LifecycleScreen)) {
+	if ls, ok := s.(interfaces.LifecycleScreen); ok {
+		hook(ls)
+	}
+}
+
+// PushScreen pauses the active screen, saves it to the history and activates the given
+// screen with the transition. The PopScreen returns to the paused screen.
+func (a *Application) PushScreen(s interfaces.Screen, t Transition) {
+	from := a.activeScreen
+	if from != nil {
+		callHook(from, interfaces.LifecycleScreen.OnPause)
+		a.history = append(a.history, from)
+	}
+	a.changeScreen(from, s, t)
+	callHook(s, interfaces.LifecycleScreen.OnEnter)
+}
+
+// PopScreen exits the active screen and resumes the last screen of the history with the
+// transition. It returns false, if the history is empty.
+func (a *Application) PopScreen(t Transition) bool {
+	if len(a.history) == 0 {
+		return false
+	}
+	from := a.activeScreen
+	s := a.history[len(a.history)-1]
+	a.history = a.history[:len(a.history)-1]
+	callHook(from, interfaces.LifecycleScreen.OnExit)
+	a.changeScreen(from, s, t)
+	callHook(s, interfaces.LifecycleScreen.OnResume)
+	return true
+}
+
+// ReplaceScreen exits the active screen and activates the given screen with the transition.
+// The history is not changed.
+func (a *Application) ReplaceScreen(s interfaces.Screen, t Transition) {
+	from := a.activeScreen
+	callHook(from, interfaces.LifecycleScreen.OnExit)
+	a.changeScreen(from, s, t)
+	callHook(s, interfaces.LifecycleScreen.OnEnter)
+}
+
+// GetHistory returns the paused screens. The last one is the previous screen.
+func (a *Application) GetHistory() []interfaces.Screen {
+	return append([]interfaces.Screen{}, a.history...)
+}
+
+// SetBackTransition sets the transition of the back navigation (menu action, escape key).
+func (a *Application) SetBackTransition(t Transition) {
+	a.backTransition = t
+}
+
+// SetTransitionShader sets the shader of the transitions. Without it the shader of the
+// NewTransitionShader function is created for the first transition.
+func (a *Application) SetTransitionShader(s interfaces.Shader) {
+	a.transitionShader = s
+}
+
+// IsTransitionRunning returns true, if the screens are changing.
+func (a *Application) IsTransitionRunning() bool {
+	return a.transition != nil
+}
+
+// changeScreen activates the screen and starts the transition from the given screen.
+// Without window, previous screen or duration the change is instant.
+func (a *Application) changeScreen(from, to interfaces.Screen, t Transition) {
+	a.activateScreen(to)
+	if a.window == nil || from == nil || from == to || t.Type == TRANSITION_NONE || t.Duration <= 0 {
+		a.transition = nil
+		return
+	}
+	a.transition = &transitionState{
+		transition: t,
+		from:       from,
+	}
+}
+
+// updateTransition moves the running transition forward with dt ms. It returns true,
+// if the transition was running in this update.
+func (a *Application) updateTransition(dt float64) bool {
+	if a.transition == nil {
+		return false
+	}
+	a.transition.elapsed += dt
+	if a.transition.elapsed >= a.transition.transition.Duration {
+		a.transition = nil
+	}
+	return true
+}
+
+// drawTransition draws the screens of the transition to the render targets, then it draws
+// the screen sized quad with the transition shader.
+func (a *Application) drawTransition(wrapper interfaces.GLWrapper) {
+	width, height := a.window.GetSize()
+	if a.transitionRenderer != nil && (a.transitionRenderer.width != width || a.transitionRenderer.height != height) {
+		a.transitionRenderer.delete()
+		a.transitionRenderer = nil
+	}
+	if a.transitionRenderer == nil {
+		a.transitionRenderer = newTransitionRenderer(width, height, wrapper)
+	}
+	if a.transitionShader == nil {
+		a.transitionShader = shader.NewTransitionShader(wrapper)
+	}
+	r := a.transitionRenderer
+	r.from.Bind()
+	wrapper.Clear(glwrapper.COLOR_BUFFER_BIT | glwrapper.DEPTH_BUFFER_BIT)
+	a.transition.from.Draw(wrapper)
+	r.to.Bind()
+	wrapper.Clear(glwrapper.COLOR_BUFFER_BIT | glwrapper.DEPTH_BUFFER_BIT)
+	a.activeScreen.Draw(wrapper)
+	r.to.UnBind(int32(width), int32(height))
+
+	t := a.transition.transition
+	sh := a.transitionShader
+	sh.Use()
+	sh.SetUniformMat4("view", mgl32.Ident4())
+	sh.SetUniformMat4("projection", mgl32.Ident4())
+	sh.SetUniform1f("progress", a.transition.progress())
+	sh.SetUniform3f("color", t.Color.X(), t.Color.Y(), t.Color.Z())
+	direction := mgl32.Vec3{0, 0, 0}
+	mode := int32(transitionModeSlide)
+	switch t.Type {
+	case TRANSITION_FADE:
+		mode = transitionModeFade
+		break
+	case TRANSITION_SLIDE_LEFT:
+		direction = mgl32.Vec3{-1, 0, 0}
+		break
+	case TRANSITION_SLIDE_RIGHT:
+		direction = mgl32.Vec3{1, 0, 0}
+		break
+	case TRANSITION_SLIDE_UP:
+		direction = mgl32.Vec3{0, 1, 0}
+		break
+	case TRANSITION_SLIDE_DOWN:
+		direction = mgl32.Vec3{0, -1, 0}
+		break
+	default:
+		mode = transitionModeCrossfade
+		break
+	}
+	sh.SetUniform1i("mode", mode)
+	sh.SetUniform3f("direction", direction.X(), direction.Y(), direction.Z())
+	wrapper.Clear(glwrapper.DEPTH_BUFFER_BIT)
+	r.quad.Draw(sh)
+}
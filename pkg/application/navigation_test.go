@@ -0,0 +1,240 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/input"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/testhelper"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// lifecycleMock is a screen that logs its lifecycle hooks.
+type lifecycleMock struct {
+	overlayMock
+	hookLog *[]string
+}
+
+func (s *lifecycleMock) OnEnter()  { *s.hookLog = append(*s.hookLog, s.name+":enter") }
+func (s *lifecycleMock) OnExit()   { *s.hookLog = append(*s.hookLog, s.name+":exit") }
+func (s *lifecycleMock) OnPause()  { *s.hookLog = append(*s.hookLog, s.name+":pause") }
+func (s *lifecycleMock) OnResume() { *s.hookLog = append(*s.hookLog, s.name+":resume") }
+
+// captureMock stops capturing, when it gets the escape key.
+type captureMock struct {
+	lifecycleMock
+	capturing bool
+}
+
+func (s *captureMock) IsCapturingKey() bool { return s.capturing }
+func (s *captureMock) Update(dt float64, p interfaces.Pointer, ks interfaces.RoKeyStore, bs interfaces.RoButtonStore) {
+	s.lifecycleMock.Update(dt, p, ks, bs)
+	if ks.Get(glfw.KeyEscape) {
+		s.capturing = false
+	}
+}
+
+func newLifecycleMock(name string, drawLog, hookLog *[]string) *lifecycleMock {
+	return &lifecycleMock{overlayMock: overlayMock{name: name, drawLog: drawLog}, hookLog: hookLog}
+}
+func checkHooks(t *testing.T, hookLog *[]string, expected ...string) {
+	if len(*hookLog) != len(expected) {
+		t.Errorf("Invalid hooks. Instead of '%v', we have '%v'.", expected, *hookLog)
+	} else {
+		for i := range expected {
+			if (*hookLog)[i] != expected[i] {
+				t.Errorf("Invalid hooks. Instead of '%v', we have '%v'.", expected, *hookLog)
+				break
+			}
+		}
+	}
+	*hookLog = nil
+}
+
+func TestNavigation(t *testing.T) {
+	var drawLog, hookLog []string
+	app := New(wrapperMock)
+	game := newLifecycleMock("game", &drawLog, &hookLog)
+	inventory := newLifecycleMock("inventory", &drawLog, &hookLog)
+	settings := newLifecycleMock("settings", &drawLog, &hookLog)
+	app.ActivateScreen(game)
+	checkHooks(t, &hookLog, "game:enter")
+	app.PushScreen(inventory, NewTransition(TRANSITION_NONE, 0))
+	checkHooks(t, &hookLog, "game:pause", "inventory:enter")
+	app.PushScreen(settings, NewTransition(TRANSITION_NONE, 0))
+	checkHooks(t, &hookLog, "inventory:pause", "settings:enter")
+	if history := app.GetHistory(); len(history) != 2 || history[1] != inventory || app.activeScreen != settings {
+		t.Error("Invalid history.")
+	}
+	if !app.PopScreen(NewTransition(TRANSITION_NONE, 0)) || app.activeScreen != inventory {
+		t.Error("The inventory should be resumed.")
+	}
+	checkHooks(t, &hookLog, "settings:exit", "inventory:resume")
+	app.ReplaceScreen(settings, NewTransition(TRANSITION_NONE, 0))
+	checkHooks(t, &hookLog, "inventory:exit", "settings:enter")
+	if len(app.GetHistory()) != 1 || app.activeScreen != settings {
+		t.Error("The replace shouldn't change the history.")
+	}
+	app.PopScreen(NewTransition(TRANSITION_NONE, 0))
+	checkHooks(t, &hookLog, "settings:exit", "game:resume")
+	if app.PopScreen(NewTransition(TRANSITION_NONE, 0)) || app.activeScreen != game {
+		t.Error("The pop shouldn't work without history.")
+	}
+	checkHooks(t, &hookLog)
+	// the activation clears the history.
+	app.PushScreen(inventory, NewTransition(TRANSITION_NONE, 0))
+	app.PushScreen(settings, NewTransition(TRANSITION_NONE, 0))
+	hookLog = nil
+	app.ActivateScreen(game)
+	checkHooks(t, &hookLog, "inventory:exit", "settings:exit", "game:resume")
+	if len(app.GetHistory()) != 0 || app.activeScreen != game {
+		t.Error("The history should be cleared.")
+	}
+}
+func TestBackNavigation(t *testing.T) {
+	var drawLog, hookLog []string
+	app := New(wrapperMock)
+	app.SetWindow(wm)
+	game := newLifecycleMock("game", &drawLog, &hookLog)
+	inventory := newLifecycleMock("inventory", &drawLog, &hookLog)
+	menu := newLifecycleMock("menu", &drawLog, &hookLog)
+	app.MenuScreen(menu)
+	app.ActivateScreen(game)
+	app.PushScreen(inventory, NewTransition(TRANSITION_NONE, 0))
+	// the escape returns to the previous screen.
+	app.keyEvent(glfw.KeyEscape, glfw.Press, 0)
+	app.keyEvent(glfw.KeyEscape, glfw.Release, 0)
+	if app.activeScreen != game {
+		t.Error("The escape should return to the game.")
+	}
+	// without history it opens the menu, then it returns to the game.
+	app.keyEvent(glfw.KeyEscape, glfw.Press, 0)
+	if app.activeScreen != menu || len(app.GetHistory()) != 1 {
+		t.Error("The escape should open the menu.")
+	}
+	app.keyEvent(glfw.KeyEscape, glfw.Press, 0)
+	if app.activeScreen != game {
+		t.Error("The escape should close the menu.")
+	}
+}
+func TestTransition(t *testing.T) {
+	var drawLog, hookLog []string
+	app := New(wrapperMock)
+	app.SetWindow(wm)
+	app.SetTransitionShader(testhelper.ShaderMock{})
+	game := newLifecycleMock("game", &drawLog, &hookLog)
+	menu := newLifecycleMock("menu", &drawLog, &hookLog)
+	app.ActivateScreen(game)
+	for _, transitionType := range []int{TRANSITION_FADE, TRANSITION_CROSSFADE, TRANSITION_SLIDE_LEFT, TRANSITION_SLIDE_RIGHT, TRANSITION_SLIDE_UP, TRANSITION_SLIDE_DOWN} {
+		app.PushScreen(menu, NewTransition(transitionType, 100))
+		if !app.IsTransitionRunning() || app.activeScreen != menu {
+			t.Fatal("The transition should be started.")
+		}
+		drawLog = nil
+		app.Draw(wrapperMock)
+		if len(drawLog) != 2 || drawLog[0] != "game" || drawLog[1] != "menu" {
+			t.Errorf("Both screens should be drawn. '%v'.", drawLog)
+		}
+		// the input is ignored during the transition.
+		app.SetKeyState(glfw.KeyW, glfw.Press)
+		app.Update(60)
+		if menu.keyStore.Get(glfw.KeyW) || !app.IsTransitionRunning() {
+			t.Error("The transition should be running without input.")
+		}
+		if progress := app.transition.progress(); progress != 0.6 {
+			t.Errorf("Invalid progress '%f'.", progress)
+		}
+		app.Update(60)
+		if app.IsTransitionRunning() {
+			t.Error("The transition should be finished.")
+		}
+		app.Update(10)
+		if !menu.keyStore.Get(glfw.KeyW) {
+			t.Error("The screen should get the input after the transition.")
+		}
+		drawLog = nil
+		app.Draw(wrapperMock)
+		if len(drawLog) != 1 || drawLog[0] != "menu" {
+			t.Errorf("Only the active screen should be drawn. '%v'.", drawLog)
+		}
+		app.SetKeyState(glfw.KeyW, glfw.Release)
+		app.PopScreen(NewTransition(TRANSITION_NONE, 0))
+	}
+	// the render targets are created once.
+	renderer := app.transitionRenderer
+	app.PushScreen(menu, NewTransition(TRANSITION_FADE, 100))
+	app.Draw(wrapperMock)
+	if renderer == nil || app.transitionRenderer != renderer {
+		t.Error("The renderer should be reused.")
+	}
+}
+func TestTransitionIgnoresActions(t *testing.T) {
+	var drawLog, hookLog []string
+	app := New(wrapperMock)
+	app.SetWindow(wm)
+	app.SetTransitionShader(testhelper.ShaderMock{})
+	game := newLifecycleMock("game", &drawLog, &hookLog)
+	inventory := newLifecycleMock("inventory", &drawLog, &hookLog)
+	app.ActivateScreen(game)
+	app.SetActionMap(input.DefaultActionMap())
+	app.PushScreen(inventory, NewTransition(TRANSITION_FADE, 100))
+	// the menu action doesn't start another navigation, the screen doesn't get the actions.
+	app.SetKeyState(glfw.KeyEscape, glfw.Press)
+	app.Update(10)
+	if app.activeScreen != inventory || len(app.GetHistory()) != 1 {
+		t.Error("The menu action shouldn't navigate during the transition.")
+	}
+	if inventory.actions != nil || inventory.gamepad.Get(glfw.ButtonA) {
+		t.Error("The screen shouldn't get the actions during the transition.")
+	}
+	app.SetKeyState(glfw.KeyEscape, glfw.Release)
+	app.Update(100)
+	app.Update(10)
+	if inventory.actions == nil {
+		t.Error("The screen should get the actions after the transition.")
+	}
+	// without action map the escape key is also ignored.
+	app.SetActionMap(nil)
+	app.PushScreen(game, NewTransition(TRANSITION_FADE, 100))
+	app.keyEvent(glfw.KeyEscape, glfw.Press, 0)
+	if app.activeScreen != game || len(app.GetHistory()) != 2 {
+		t.Error("The escape key shouldn't navigate during the transition.")
+	}
+}
+func TestBackWhileCapturingKey(t *testing.T) {
+	var drawLog, hookLog []string
+	app := New(wrapperMock)
+	app.SetWindow(wm)
+	game := newLifecycleMock("game", &drawLog, &hookLog)
+	form := &captureMock{lifecycleMock: *newLifecycleMock("form", &drawLog, &hookLog), capturing: true}
+	app.ActivateScreen(game)
+	app.PushScreen(form, NewTransition(TRANSITION_NONE, 0))
+	// without action map the escape key is passed to the capturing screen.
+	app.keyEvent(glfw.KeyEscape, glfw.Press, 0)
+	app.Update(10)
+	if app.activeScreen != form || form.capturing {
+		t.Error("The escape key should stop the capture instead of the back navigation.")
+	}
+	app.keyEvent(glfw.KeyEscape, glfw.Release, 0)
+	app.keyEvent(glfw.KeyEscape, glfw.Press, 0)
+	if app.activeScreen != game {
+		t.Error("The escape key should navigate back after the capture.")
+	}
+	// with action map the menu action is also skipped.
+	app.SetActionMap(input.DefaultActionMap())
+	app.PushScreen(form, NewTransition(TRANSITION_NONE, 0))
+	form.capturing = true
+	app.SetKeyState(glfw.KeyEscape, glfw.Press)
+	app.Update(10)
+	if app.activeScreen != form || form.capturing {
+		t.Error("The menu action should stop the capture instead of the back navigation.")
+	}
+	app.SetKeyState(glfw.KeyEscape, glfw.Release)
+	app.Update(10)
+	app.SetKeyState(glfw.KeyEscape, glfw.Press)
+	app.Update(10)
+	if app.activeScreen != game {
+		t.Error("The menu action should navigate back after the capture.")
+	}
+}
@@ -0,0 +1,160 @@
+package application
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/input"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/store"
+)
+
+const (
+	// The overlay gets the input, and the screens below it also get it.
+	OVERLAY_INPUT_PASS = iota
+	// The overlay gets the input, the screens below it don't.
+	OVERLAY_INPUT_BLOCK
+	// The overlay gets the input, the screens below it get the pointer or the keyboard and
+	// their actions only if the overlay doesn't want it. The overlay has to implement the InputScreen interface,
+	// otherwise it is handled as OVERLAY_INPUT_PASS.
+	OVERLAY_INPUT_UNHANDLED
+	// The overlay doesn't get input, it is only displayed, eg. a fps counter.
+	OVERLAY_INPUT_NONE
+)
+
+// overlay is a screen that is displayed on top of the active screen.
+type overlay struct {
+	screen interfaces.Screen
+	order  int
+	input  int
+}
+
+// AddOverlay inserts the screen to the overlay stack. The overlays are drawn after the active
+// screen in ascending order, the overlays with the same order are drawn in insertion order.
+// The input mode (OVERLAY_INPUT_*) describes how the input is passed to the screens below.
+// If the screen is already in the stack, its order and input mode are updated.
+func (a *Application) AddOverlay(s interfaces.Screen, order, inputMode int) {
+	a.RemoveOverlay(s)
+	if a.window != nil {
+		WindowWidth, WindowHeight := a.window.GetSize()
+		s.SetWindowSize(float32(WindowWidth), float32(WindowHeight))
+		s.SetWrapper(a.wrapper)
+	}
+	position := len(a.overlays)
+	for position > 0 && a.overlays[position-1].order > order {
+		position--
+	}
+	a.overlays = append(a.overlays, overlay{})
+	copy(a.overlays[position+1:], a.overlays[position:])
+	a.overlays[position] = overlay{screen: s, order: order, input: inputMode}
+}
+
+// RemoveOverlay deletes the screen from the overlay stack.
+func (a *Application) RemoveOverlay(s interfaces.Screen) {
+	for i := range a.overlays {
+		if a.overlays[i].screen == s {
+			a.overlays = append(a.overlays[:i], a.overlays[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetOverlays returns the overlay screens in draw order.
+func (a *Application) GetOverlays() []interfaces.Screen {
+	var result []interfaces.Screen
+	for i := range a.overlays {
+		result = append(result, a.overlays[i].screen)
+	}
+	return result
+}
+
+// overlaysHidden returns true if the menu screen is active. The overlays belong to the
+// screens below the menu, so that they are not drawn and they don't get input over it.
+func (a *Application) overlaysHidden() bool {
+	return a.menuSet && a.activeScreen == a.menuScreen
+}
+
+// drawOverlays draws the overlays in ascending order. The depth buffer is cleared before
+// every overlay, so that the overlays are always visible over the screens below them.
+// The overlays are hidden while the menu screen is active.
+func (a *Application) drawOverlays(wrapper interfaces.GLWrapper) {
+	if a.overlaysHidden() {
+		return
+	}
+	for i := range a.overlays {
+		wrapper.Clear(glwrapper.DEPTH_BUFFER_BIT)
+		a.overlays[i].screen.Draw(wrapper)
+	}
+}
+
+// updateOverlays updates the overlays from the top one and returns the key, button and gamepad
+// stores and the action map of the active screen. The blocked input is replaced with empty
+// stores, the blocked action map is nil, so that the screens fall back to the empty key stores.
+// If the overlay wants only the keyboard or the pointer, the actions of that device are hidden.
+// The hidden overlays are not updated.
+func (a *Application) updateOverlays(dt float64, p interfaces.Pointer, actions interfaces.RoActionMap) (interfaces.RoKeyStore, interfaces.RoButtonStore, interfaces.RoGamepadStore, interfaces.RoActionMap) {
+	var keyStore interfaces.RoKeyStore = a.keyDowns
+	var buttonStore interfaces.RoButtonStore = a.mouseDowns
+	var gamepad interfaces.RoGamepadStore = a.gamepad
+	if a.overlaysHidden() {
+		return keyStore, buttonStore, gamepad, actions
+	}
+	for i := len(a.overlays) - 1; i >= 0; i-- {
+		o := a.overlays[i]
+		if gs, ok := o.screen.(interfaces.GamepadScreen); ok {
+			gs.SetGamepadStore(gamepad)
+		}
+		if as, ok := o.screen.(interfaces.ActionScreen); ok {
+			as.SetActionMap(actions)
+		}
+		if o.input == OVERLAY_INPUT_NONE {
+			o.screen.Update(dt, p, store.NewGlfwKeyStore(), store.NewGlfwMouseStore())
+			continue
+		}
+		o.screen.Update(dt, p, keyStore, buttonStore)
+		switch o.input {
+		case OVERLAY_INPUT_BLOCK:
+			keyStore = store.NewGlfwKeyStore()
+			buttonStore = store.NewGlfwMouseStore()
+			gamepad = store.NewGlfwGamepadStore()
+			actions = nil
+			break
+		case OVERLAY_INPUT_UNHANDLED:
+			if is, ok := o.screen.(interfaces.InputScreen); ok {
+				if is.WantsPointer() {
+					buttonStore = store.NewGlfwMouseStore()
+				}
+				if is.WantsKeyboard() {
+					keyStore = store.NewGlfwKeyStore()
+				}
+				actions = input.BlockActions(actions, is.WantsKeyboard(), is.WantsPointer())
+			}
+			break
+		}
+	}
+	return keyStore, buttonStore, gamepad, actions
+}
+
+// overlayCharCallback passes the character to the overlays from the top one. It returns
+// true, if an overlay consumed it, so that the screens below it don't get it.
+func (a *Application) overlayCharCallback(char rune) bool {
+	if a.overlaysHidden() {
+		return false
+	}
+	for i := len(a.overlays) - 1; i >= 0; i-- {
+		o := a.overlays[i]
+		switch o.input {
+		case OVERLAY_INPUT_PASS:
+			o.screen.CharCallback(char, a.wrapper)
+			break
+		case OVERLAY_INPUT_BLOCK:
+			o.screen.CharCallback(char, a.wrapper)
+			return true
+		case OVERLAY_INPUT_UNHANDLED:
+			o.screen.CharCallback(char, a.wrapper)
+			if is, ok := o.screen.(interfaces.InputScreen); ok && is.WantsKeyboard() {
+				return true
+			}
+			break
+		}
+	}
+	return false
+}
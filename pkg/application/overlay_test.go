@@ -0,0 +1,237 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/camera"
+	"github.com/akosgarai/playground_engine/pkg/input"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/screen"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// overlayMock stores the inputs of the last update and the order of the draw calls.
+type overlayMock struct {
+	name          string
+	drawLog       *[]string
+	keyStore      interfaces.RoKeyStore
+	buttonStore   interfaces.RoButtonStore
+	gamepad       interfaces.RoGamepadStore
+	actions       interfaces.RoActionMap
+	chars         []rune
+	width         float32
+	wantsPointer  bool
+	wantsKeyboard bool
+}
+
+func (s *overlayMock) Log() string { return s.name }
+func (s *overlayMock) Draw(interfaces.GLWrapper) {
+	*s.drawLog = append(*s.drawLog, s.name)
+}
+func (s *overlayMock) Export(string)                {}
+func (s *overlayMock) GetCamera() interfaces.Camera { return nil }
+func (s *overlayMock) GetClosestModelMeshDistance() (interfaces.Model, interfaces.Mesh, float32) {
+	return nil, nil, 0
+}
+func (s *overlayMock) SetUniformFloat(string, float32)             {}
+func (s *overlayMock) SetUniformVector(string, mgl32.Vec3)         {}
+func (s *overlayMock) CharCallback(c rune, _ interfaces.GLWrapper) { s.chars = append(s.chars, c) }
+func (s *overlayMock) SetWindowSize(w, h float32)                  { s.width = w }
+func (s *overlayMock) SetWrapper(interfaces.GLWrapper)             {}
+func (s *overlayMock) Update(dt float64, p interfaces.Pointer, ks interfaces.RoKeyStore, bs interfaces.RoButtonStore) {
+	s.keyStore = ks
+	s.buttonStore = bs
+}
+func (s *overlayMock) SetGamepadStore(gamepad interfaces.RoGamepadStore) { s.gamepad = gamepad }
+func (s *overlayMock) SetActionMap(actions interfaces.RoActionMap)       { s.actions = actions }
+func (s *overlayMock) WantsPointer() bool                                { return s.wantsPointer }
+func (s *overlayMock) WantsKeyboard() bool                               { return s.wantsKeyboard }
+
+func newOverlayApp() (*Application, *overlayMock, *[]string) {
+	var drawLog []string
+	app := New(wrapperMock)
+	app.SetWindow(wm)
+	base := &overlayMock{name: "base", drawLog: &drawLog}
+	app.AddScreen(base)
+	app.ActivateScreen(base)
+	return app, base, &drawLog
+}
+
+func TestAddOverlay(t *testing.T) {
+	app, _, drawLog := newOverlayApp()
+	hud := &overlayMock{name: "hud", drawLog: drawLog}
+	minimap := &overlayMock{name: "minimap", drawLog: drawLog}
+	crosshair := &overlayMock{name: "crosshair", drawLog: drawLog}
+	app.AddOverlay(hud, 1, OVERLAY_INPUT_PASS)
+	app.AddOverlay(crosshair, 2, OVERLAY_INPUT_NONE)
+	app.AddOverlay(minimap, 1, OVERLAY_INPUT_PASS)
+	if hud.width != 800 {
+		t.Error("The window size of the overlay should be set.")
+	}
+	app.Draw(wrapperMock)
+	expected := []string{"base", "hud", "minimap", "crosshair"}
+	if len(*drawLog) != len(expected) {
+		t.Fatalf("Invalid draw log '%v'.", *drawLog)
+	}
+	for i := range expected {
+		if (*drawLog)[i] != expected[i] {
+			t.Errorf("Invalid draw order. Instead of '%v', we have '%v'.", expected, *drawLog)
+			break
+		}
+	}
+	// the existing overlay is moved.
+	app.AddOverlay(hud, 3, OVERLAY_INPUT_PASS)
+	overlays := app.GetOverlays()
+	if len(overlays) != 3 || overlays[2] != hud {
+		t.Error("The hud should be moved to the top.")
+	}
+	app.RemoveOverlay(minimap)
+	app.RemoveOverlay(minimap)
+	if len(app.GetOverlays()) != 2 {
+		t.Error("The minimap should be removed.")
+	}
+}
+func TestUpdateOverlays(t *testing.T) {
+	app, base, drawLog := newOverlayApp()
+	hud := &overlayMock{name: "hud", drawLog: drawLog}
+	fps := &overlayMock{name: "fps", drawLog: drawLog}
+	app.AddOverlay(hud, 0, OVERLAY_INPUT_PASS)
+	app.AddOverlay(fps, 1, OVERLAY_INPUT_NONE)
+	app.SetKeyState(glfw.KeyW, glfw.Press)
+	app.SetButtonState(glfw.MouseButtonLeft, glfw.Press)
+	app.Update(10)
+	if fps.keyStore.Get(glfw.KeyW) || fps.buttonStore.Get(glfw.MouseButtonLeft) {
+		t.Error("The display only overlay shouldn't get the input.")
+	}
+	if !hud.keyStore.Get(glfw.KeyW) || !base.keyStore.Get(glfw.KeyW) || !base.buttonStore.Get(glfw.MouseButtonLeft) {
+		t.Error("The input should be passed to the screens below.")
+	}
+	// the unhandled input is passed.
+	hud.wantsPointer = true
+	app.AddOverlay(hud, 0, OVERLAY_INPUT_UNHANDLED)
+	app.Update(10)
+	if !base.keyStore.Get(glfw.KeyW) || base.buttonStore.Get(glfw.MouseButtonLeft) {
+		t.Error("Only the keyboard should be passed to the base screen.")
+	}
+	hud.wantsPointer = false
+	hud.wantsKeyboard = true
+	app.Update(10)
+	if base.keyStore.Get(glfw.KeyW) || !base.buttonStore.Get(glfw.MouseButtonLeft) {
+		t.Error("Only the pointer should be passed to the base screen.")
+	}
+	// the blocking overlay hides everything.
+	app.AddOverlay(hud, 0, OVERLAY_INPUT_BLOCK)
+	app.Update(10)
+	if base.keyStore.Get(glfw.KeyW) || base.buttonStore.Get(glfw.MouseButtonLeft) || base.gamepad == app.gamepad {
+		t.Error("The base screen shouldn't get the input.")
+	}
+	if hud.gamepad != app.gamepad || !hud.keyStore.Get(glfw.KeyW) {
+		t.Error("The blocking overlay should get the input.")
+	}
+	// the action map is hidden from the base screen.
+	app.SetActionMap(input.DefaultActionMap())
+	app.Update(10)
+	if base.actions != nil || hud.actions == nil {
+		t.Error("Only the blocking overlay should get the action map.")
+	}
+}
+func TestUpdateOverlaysActions(t *testing.T) {
+	app, base, drawLog := newOverlayApp()
+	app.SetActionMap(input.DefaultActionMap())
+	app.GetActionMap().EnableContext(input.CONTEXT_MENU)
+	hud := &overlayMock{name: "hud", drawLog: drawLog, wantsKeyboard: true}
+	app.AddOverlay(hud, 0, OVERLAY_INPUT_UNHANDLED)
+	app.SetKeyState(glfw.KeyW, glfw.Press)
+	app.SetButtonState(glfw.MouseButtonLeft, glfw.Press)
+	app.Update(10)
+	if !hud.actions.IsActive(input.ACTION_FORWARD) {
+		t.Error("The overlay should get the keyboard actions.")
+	}
+	if base.actions.IsActive(input.ACTION_FORWARD) || !base.actions.IsActive(input.ACTION_SELECT) {
+		t.Error("Only the pointer actions should be passed to the base screen.")
+	}
+	hud.wantsKeyboard = false
+	hud.wantsPointer = true
+	app.Update(10)
+	if !base.actions.IsActive(input.ACTION_FORWARD) || base.actions.IsActive(input.ACTION_SELECT) {
+		t.Error("Only the keyboard actions should be passed to the base screen.")
+	}
+}
+func TestUpdateOverlaysGameContext(t *testing.T) {
+	app, _, drawLog := newOverlayApp()
+	app.SetActionMap(input.DefaultActionMap())
+	hud := &overlayMock{name: "hud", drawLog: drawLog}
+	app.AddOverlay(hud, 0, OVERLAY_INPUT_UNHANDLED)
+	app.SetButtonState(glfw.MouseButtonLeft, glfw.Press)
+	app.Update(10)
+	// the ui layers fall back to the stores, if the menu actions are disabled.
+	if !hud.actions.HasAction(input.ACTION_SELECT) || hud.actions.IsEnabled(input.ACTION_SELECT) || !hud.buttonStore.Get(glfw.MouseButtonLeft) {
+		t.Error("The overlay of the game screen should get the click through the button store.")
+	}
+}
+func TestOverlaysHiddenWithMenu(t *testing.T) {
+	app, _, drawLog := newOverlayApp()
+	menu := &overlayMock{name: "menu", drawLog: drawLog}
+	app.AddScreen(menu)
+	app.MenuScreen(menu)
+	hud := &overlayMock{name: "hud", drawLog: drawLog}
+	app.AddOverlay(hud, 0, OVERLAY_INPUT_BLOCK)
+	app.keyEvent(glfw.KeyEscape, glfw.Press, 0)
+	if app.activeScreen != menu {
+		t.Fatal("The menu screen should be active.")
+	}
+	app.SetKeyState(glfw.KeyW, glfw.Press)
+	app.Update(10)
+	app.charEvent('a')
+	app.Draw(wrapperMock)
+	if len(*drawLog) != 1 || (*drawLog)[0] != "menu" {
+		t.Errorf("Only the menu should be drawn. '%v'.", *drawLog)
+	}
+	if hud.keyStore != nil || len(hud.chars) != 0 || !menu.keyStore.Get(glfw.KeyW) || len(menu.chars) != 1 {
+		t.Error("The menu screen should get the input instead of the hidden overlay.")
+	}
+}
+func TestOverlayCharCallback(t *testing.T) {
+	app, base, drawLog := newOverlayApp()
+	hud := &overlayMock{name: "hud", drawLog: drawLog}
+	fps := &overlayMock{name: "fps", drawLog: drawLog}
+	app.AddOverlay(hud, 0, OVERLAY_INPUT_UNHANDLED)
+	app.AddOverlay(fps, 1, OVERLAY_INPUT_NONE)
+	app.CharCallback(nil, 'a')
+	if len(base.chars) != 1 || len(hud.chars) != 1 || len(fps.chars) != 0 {
+		t.Error("The character should be passed to the base screen.")
+	}
+	hud.wantsKeyboard = true
+	app.CharCallback(nil, 'b')
+	if len(base.chars) != 1 || len(hud.chars) != 2 {
+		t.Error("The hud should consume the character.")
+	}
+	app.AddOverlay(hud, 0, OVERLAY_INPUT_BLOCK)
+	hud.wantsKeyboard = false
+	app.CharCallback(nil, 'c')
+	if len(base.chars) != 1 || len(hud.chars) != 3 {
+		t.Error("The blocking hud should consume the character.")
+	}
+}
+func TestOverlayWithOrthographicCamera(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("Shouldn't have panic.")
+				t.Log(r)
+			}
+		}()
+		app, _, _ := newOverlayApp()
+		hud := screen.New()
+		hud.SetupCamera(camera.NewOrthographicCamera(0, 800, 0, 600, -1, 1), map[string]interface{}{"mode": "fps"})
+		app.AddOverlay(hud, 0, OVERLAY_INPUT_PASS)
+		app.SetKeyState(glfw.KeyW, glfw.Press)
+		app.Update(10)
+		app.Draw(wrapperMock)
+		if hud.GetCamera().GetPosition() != (mgl32.Vec3{0, 0, 0}) {
+			t.Error("The hud camera shouldn't move.")
+		}
+	}()
+}
@@ -0,0 +1,225 @@
+package application
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// The version of the recording format.
+	RECORDING_VERSION = 1
+)
+
+const (
+	// The types of the recorded events.
+	EVENT_KEY = iota
+	EVENT_MOUSE_BUTTON
+	EVENT_CHAR
+)
+
+var (
+	ErrorAlreadyRecording = errors.New("The input is already recorded")
+	ErrorNotRecording     = errors.New("The input is not recorded")
+	ErrorInvalidRecording = errors.New("Invalid recording")
+)
+
+// RecordedEvent is an input event of the callbacks. The code is the key or the mouse
+// button, the X, Y are the cursor position of the mouse button events.
+type RecordedEvent struct {
+	Type   int     `json:"type"`
+	Code   int     `json:"code,omitempty"`
+	Action int     `json:"action,omitempty"`
+	Mods   int     `json:"mods,omitempty"`
+	Char   rune    `json:"char,omitempty"`
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+}
+
+// GamepadFrame is the polled state of the joystick.
+type GamepadFrame struct {
+	Present bool      `json:"present"`
+	Axes    []float32 `json:"axes,omitempty"`
+	Buttons []bool    `json:"buttons,omitempty"`
+}
+
+// RecordedFrame is the input of an Update call. The events are the ones that
+// are received since the previous update.
+type RecordedFrame struct {
+	Dt      float64         `json:"dt"`
+	CursorX float64         `json:"cursorX"`
+	CursorY float64         `json:"cursorY"`
+	Width   int             `json:"width"`
+	Height  int             `json:"height"`
+	Events  []RecordedEvent `json:"events,omitempty"`
+	Gamepad *GamepadFrame   `json:"gamepad,omitempty"`
+}
+
+// RecordingHeader is the window state at the start of the recording.
+type RecordingHeader struct {
+	Version int     `json:"version"`
+	CursorX float64 `json:"cursorX"`
+	CursorY float64 `json:"cursorY"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+}
+
+// Recording is a recorded input session. It is stored as json lines, the first
+// line is the header, the others are the frames.
+type Recording struct {
+	Header RecordingHeader
+	Frames []RecordedFrame
+}
+
+// LoadRecording reads a recording. In case of invalid content, it returns ErrorInvalidRecording.
+func LoadRecording(r io.Reader) (*Recording, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	rec := &Recording{}
+	if err := decoder.Decode(&rec.Header); err != nil || rec.Header.Version != RECORDING_VERSION {
+		return nil, ErrorInvalidRecording
+	}
+	for {
+		var f RecordedFrame
+		err := decoder.Decode(&f)
+		if err == io.EOF {
+			return rec, nil
+		}
+		if err != nil {
+			return nil, ErrorInvalidRecording
+		}
+		rec.Frames = append(rec.Frames, f)
+	}
+}
+
+// recorder writes the frames of the application.
+type recorder struct {
+	encoder *json.Encoder
+	events  []RecordedEvent
+	// the first write error. The recording is stopped after it.
+	err error
+}
+
+// addEvent stores the event for the next frame.
+func (r *recorder) addEvent(e RecordedEvent) {
+	r.events = append(r.events, e)
+}
+
+// record writes the frame with the stored events.
+func (r *recorder) record(dt, cursorX, cursorY float64, width, height int, gamepad *GamepadFrame) {
+	if r.err != nil {
+		return
+	}
+	f := RecordedFrame{Dt: dt, CursorX: cursorX, CursorY: cursorY, Width: width, Height: height, Events: r.events, Gamepad: gamepad}
+	r.events = nil
+	r.err = r.encoder.Encode(f)
+}
+
+// StartRecording starts the recording of the input to the given writer. The header is
+// written immediately, the frames are written in the Update calls. In case of missing
+// window it panics.
+func (a *Application) StartRecording(w io.Writer) error {
+	if a.window == nil {
+		panic("Window is missing.")
+	}
+	if a.recorder != nil {
+		return ErrorAlreadyRecording
+	}
+	width, height := a.window.GetSize()
+	cursorX, cursorY := a.window.GetCursorPos()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(RecordingHeader{Version: RECORDING_VERSION, CursorX: cursorX, CursorY: cursorY, Width: width, Height: height}); err != nil {
+		return err
+	}
+	a.recorder = &recorder{encoder: encoder}
+	return nil
+}
+
+// StopRecording stops the recording. It returns the first write error of the recording.
+func (a *Application) StopRecording() error {
+	if a.recorder == nil {
+		return ErrorNotRecording
+	}
+	err := a.recorder.err
+	a.recorder = nil
+	return err
+}
+
+// IsRecording returns true if the input is recorded.
+func (a *Application) IsRecording() bool {
+	return a.recorder != nil
+}
+
+// ReplayWindow is a window without display. It returns the recorded cursor position
+// and window size, so that the recordings could be replayed without real window.
+type ReplayWindow struct {
+	cursorX, cursorY float64
+	width, height    int
+	shouldClose      bool
+}
+
+// NewReplayWindow returns a replay window with the initial state of the recording.
+func NewReplayWindow(h RecordingHeader) *ReplayWindow {
+	return &ReplayWindow{cursorX: h.CursorX, cursorY: h.CursorY, width: h.Width, height: h.Height}
+}
+func (w *ReplayWindow) GetCursorPos() (float64, float64) { return w.cursorX, w.cursorY }
+func (w *ReplayWindow) SetKeyCallback(cb glfw.KeyCallback) glfw.KeyCallback {
+	return cb
+}
+func (w *ReplayWindow) SetMouseButtonCallback(cb glfw.MouseButtonCallback) glfw.MouseButtonCallback {
+	return cb
+}
+func (w *ReplayWindow) SetCharCallback(cb glfw.CharCallback) glfw.CharCallback { return cb }
+func (w *ReplayWindow) SetSizeCallback(cb glfw.SizeCallback) glfw.SizeCallback { return cb }
+func (w *ReplayWindow) ShouldClose() bool                                      { return w.shouldClose }
+func (w *ReplayWindow) SwapBuffers()                                           {}
+func (w *ReplayWindow) GetSize() (int, int)                                    { return w.width, w.height }
+func (w *ReplayWindow) SetShouldClose(v bool)                                  { w.shouldClose = v }
+func (w *ReplayWindow) SetInputMode(glfw.InputMode, int)                       {}
+
+// ReplayFrame applies the recorded frame to the application: it moves the cursor of the
+// replay window, calls the event handlers and the gamepad update, then it calls the Update
+// with the recorded dt. The joystick isn't polled, the recorded gamepad state is used.
+// If the window of the application isn't a replay window, it panics.
+func (a *Application) ReplayFrame(f RecordedFrame) {
+	w, ok := a.window.(*ReplayWindow)
+	if !ok {
+		panic("Replay window is missing.")
+	}
+	w.cursorX, w.cursorY = f.CursorX, f.CursorY
+	w.width, w.height = f.Width, f.Height
+	for _, e := range f.Events {
+		switch e.Type {
+		case EVENT_KEY:
+			a.keyEvent(glfw.Key(e.Code), glfw.Action(e.Action), glfw.ModifierKey(e.Mods))
+			break
+		case EVENT_MOUSE_BUTTON:
+			a.mouseButtonEvent(glfw.MouseButton(e.Code), glfw.Action(e.Action), glfw.ModifierKey(e.Mods), e.X, e.Y)
+			break
+		case EVENT_CHAR:
+			a.charEvent(e.Char)
+			break
+		}
+	}
+	if f.Gamepad != nil {
+		a.applyGamepad(f.Gamepad)
+	}
+	a.update(f.Dt, f.Gamepad)
+}
+
+// Replay replays every frame of the recording. If the window of the application isn't a
+// replay window, it is replaced with a new one, that is created from the header. The replay
+// is stopped if the window is closed (eg. by the escape key without menu screen).
+func (a *Application) Replay(rec *Recording) {
+	if _, ok := a.window.(*ReplayWindow); !ok {
+		a.SetWindow(NewReplayWindow(rec.Header))
+	}
+	for _, f := range rec.Frames {
+		if a.window.ShouldClose() {
+			return
+		}
+		a.ReplayFrame(f)
+	}
+}
@@ -0,0 +1,190 @@
+package application
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/input"
+	"github.com/akosgarai/playground_engine/pkg/screen"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func newReplayApp(h RecordingHeader) (*Application, *screen.Screen) {
+	app := New(wrapperMock)
+	app.SetWindow(NewReplayWindow(h))
+	s := screen.New()
+	app.AddScreen(s)
+	app.ActivateScreen(s)
+	return app, s
+}
+func TestStartRecording(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Should have panic due to the missing window.")
+			}
+		}()
+		app := New(wrapperMock)
+		var buf bytes.Buffer
+		app.StartRecording(&buf)
+	}()
+	app, _ := newReplayApp(RecordingHeader{Width: 800, Height: 600, CursorX: 10, CursorY: 20})
+	var buf bytes.Buffer
+	if err := app.StartRecording(&buf); err != nil {
+		t.Errorf("Unexpected error: '%s'.", err.Error())
+	}
+	if !app.IsRecording() {
+		t.Error("Should be recording.")
+	}
+	if err := app.StartRecording(&buf); err != ErrorAlreadyRecording {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorAlreadyRecording, err)
+	}
+	if err := app.StopRecording(); err != nil {
+		t.Errorf("Unexpected error: '%s'.", err.Error())
+	}
+	if app.IsRecording() {
+		t.Error("Shouldn't be recording.")
+	}
+	if err := app.StopRecording(); err != ErrorNotRecording {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorNotRecording, err)
+	}
+	rec, err := LoadRecording(&buf)
+	if err != nil {
+		t.Errorf("Unexpected error: '%s'.", err.Error())
+	}
+	expected := RecordingHeader{Version: RECORDING_VERSION, Width: 800, Height: 600, CursorX: 10, CursorY: 20}
+	if rec.Header != expected {
+		t.Errorf("Invalid header. Instead of '%v', we have '%v'.", expected, rec.Header)
+	}
+	if len(rec.Frames) != 0 {
+		t.Errorf("Invalid number of frames. Instead of '0', we have '%d'.", len(rec.Frames))
+	}
+}
+func TestLoadRecording(t *testing.T) {
+	invalid := []string{
+		"",
+		"invalid",
+		"{\"version\":0}\n",
+		"{\"version\":1}\n{\"dt\":\"x\"}\n",
+	}
+	for _, content := range invalid {
+		if _, err := LoadRecording(strings.NewReader(content)); err != ErrorInvalidRecording {
+			t.Errorf("Invalid error for '%s'. Instead of '%v', we have '%v'.", content, ErrorInvalidRecording, err)
+		}
+	}
+	rec, err := LoadRecording(strings.NewReader("{\"version\":1,\"width\":800,\"height\":600}\n{\"dt\":10,\"events\":[{\"type\":0,\"code\":87,\"action\":1}]}\n{\"dt\":20}\n"))
+	if err != nil {
+		t.Errorf("Unexpected error: '%s'.", err.Error())
+	}
+	if len(rec.Frames) != 2 {
+		t.Errorf("Invalid number of frames. Instead of '2', we have '%d'.", len(rec.Frames))
+	}
+	if rec.Frames[0].Dt != 10 || len(rec.Frames[0].Events) != 1 || rec.Frames[0].Events[0].Code != int(glfw.KeyW) {
+		t.Errorf("Invalid first frame: '%v'.", rec.Frames[0])
+	}
+}
+func TestRecordAndReplay(t *testing.T) {
+	header := RecordingHeader{Width: 800, Height: 600, CursorX: 400, CursorY: 300}
+	app, _ := newReplayApp(header)
+	app.SetActionMap(input.DefaultActionMap())
+	var buf bytes.Buffer
+	if err := app.StartRecording(&buf); err != nil {
+		t.Errorf("Unexpected error: '%s'.", err.Error())
+	}
+	app.keyEvent(glfw.KeyW, glfw.Press, 0)
+	app.Update(10)
+	app.GetWindow().(*ReplayWindow).cursorX = 410
+	app.mouseButtonEvent(glfw.MouseButtonLeft, glfw.Press, 0, 410, 300)
+	app.charEvent('a')
+	app.Update(20)
+	app.keyEvent(glfw.KeyW, glfw.Release, 0)
+	app.applyGamepad(&GamepadFrame{Present: true, Axes: []float32{0, -1, 0, 0, -1, -1}, Buttons: make([]bool, 15)})
+	app.Update(30)
+	if err := app.StopRecording(); err != nil {
+		t.Errorf("Unexpected error: '%s'.", err.Error())
+	}
+	rec, err := LoadRecording(&buf)
+	if err != nil {
+		t.Errorf("Unexpected error: '%s'.", err.Error())
+	}
+	if len(rec.Frames) != 3 {
+		t.Errorf("Invalid number of frames. Instead of '3', we have '%d'.", len(rec.Frames))
+	}
+	expectedEvents := []int{1, 2, 1}
+	for i, f := range rec.Frames {
+		if len(f.Events) != expectedEvents[i] {
+			t.Errorf("Invalid number of events in frame '%d'. Instead of '%d', we have '%d'.", i, expectedEvents[i], len(f.Events))
+		}
+		if f.Dt != float64(10*(i+1)) {
+			t.Errorf("Invalid dt in frame '%d'. Instead of '%f', we have '%f'.", i, float64(10*(i+1)), f.Dt)
+		}
+	}
+	if rec.Frames[1].CursorX != 410 || rec.Frames[1].Events[1].Char != 'a' {
+		t.Errorf("Invalid second frame: '%v'.", rec.Frames[1])
+	}
+	// replay it step by step
+	replay, _ := newReplayApp(rec.Header)
+	replay.SetActionMap(input.DefaultActionMap())
+	replay.ReplayFrame(rec.Frames[0])
+	if !replay.GetKeyState(glfw.KeyW) || !replay.GetActionMap().IsActive(input.ACTION_FORWARD) {
+		t.Error("W should be pressed.")
+	}
+	replay.ReplayFrame(rec.Frames[1])
+	if !replay.GetMouseButtonState(glfw.MouseButtonLeft) {
+		t.Error("Left button should be pressed.")
+	}
+	if x, _ := replay.GetWindow().GetCursorPos(); x != 410 {
+		t.Errorf("Invalid cursor position. Instead of '410', we have '%f'.", x)
+	}
+	replay.ReplayFrame(RecordedFrame{Dt: 30, Width: 800, Height: 600, CursorX: 410, CursorY: 300, Events: rec.Frames[2].Events, Gamepad: &GamepadFrame{Present: true, Axes: []float32{0, -1, 0, 0, -1, -1}, Buttons: make([]bool, 15)}})
+	if replay.GetKeyState(glfw.KeyW) {
+		t.Error("W should be released.")
+	}
+	if replay.GetGamepadAxisState(glfw.AxisLeftY) != -1 || !replay.GetActionMap().IsActive(input.ACTION_FORWARD) {
+		t.Error("Forward should be active from the gamepad.")
+	}
+}
+func TestReplayFrameWithJoystick(t *testing.T) {
+	replay, _ := newReplayApp(RecordingHeader{Width: 800, Height: 600})
+	replay.SetActionMap(input.DefaultActionMap())
+	// the joystick isn't polled during the replay, so it can't overwrite the recorded state.
+	replay.SetJoystick(glfw.Joystick1)
+	replay.ReplayFrame(RecordedFrame{Dt: 10, Width: 800, Height: 600, Gamepad: &GamepadFrame{Present: true, Axes: []float32{0, -1, 0, 0, -1, -1}, Buttons: make([]bool, 15)}})
+	if replay.GetGamepadAxisState(glfw.AxisLeftY) != -1 || !replay.GetActionMap().IsActive(input.ACTION_FORWARD) {
+		t.Error("Forward should be active from the recorded gamepad.")
+	}
+}
+func TestReplay(t *testing.T) {
+	rec, err := LoadRecording(strings.NewReader("{\"version\":1,\"width\":800,\"height\":600}\n{\"dt\":10,\"width\":800,\"height\":600,\"events\":[{\"type\":0,\"code\":256,\"action\":1}]}\n{\"dt\":10,\"width\":800,\"height\":600,\"events\":[{\"type\":0,\"code\":87,\"action\":1}]}\n"))
+	if err != nil {
+		t.Errorf("Unexpected error: '%s'.", err.Error())
+	}
+	app := New(wrapperMock)
+	app.SetWindow(wm)
+	s := screen.New()
+	app.AddScreen(s)
+	app.ActivateScreen(s)
+	app.Replay(rec)
+	if _, ok := app.GetWindow().(*ReplayWindow); !ok {
+		t.Error("The window should be replaced with replay window.")
+	}
+	// the escape without menu closes the window, so that the second frame is skipped.
+	if !app.GetWindow().ShouldClose() {
+		t.Error("The window should be closed.")
+	}
+	if app.GetKeyState(glfw.KeyW) {
+		t.Error("W shouldn't be pressed.")
+	}
+}
+func TestReplayFrameWoReplayWindow(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Should have panic due to the missing replay window.")
+		}
+	}()
+	app := New(wrapperMock)
+	app.SetWindow(wm)
+	app.ReplayFrame(RecordedFrame{})
+}
@@ -0,0 +1,126 @@
+package audio
+
+import (
+	"math"
+
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The sample rate of the mixers, if it is not set.
+	DEFAULT_SAMPLE_RATE = 44100
+)
+
+// Mixer mixes the playing sources to a stereo stream in software, and writes it to the sink.
+// The positional sources are heard from the position of the listener camera.
+type Mixer struct {
+	sampleRate int
+	sink       Sink
+	listener   interfaces.Camera
+	sources    []*Source
+	// the volume multiplier of the mixed stream.
+	masterVolume float32
+	// the not mixed part of the previous updates in frames.
+	pending float64
+}
+
+// NewMixer returns a mixer with the given sample rate and sink. Without sample rate
+// DEFAULT_SAMPLE_RATE is used, without sink the mixed stream is dropped.
+func NewMixer(sampleRate int, sink Sink) *Mixer {
+	if sampleRate <= 0 {
+		sampleRate = DEFAULT_SAMPLE_RATE
+	}
+	if sink == nil {
+		sink = NewNullSink()
+	}
+	return &Mixer{
+		sampleRate:   sampleRate,
+		sink:         sink,
+		sources:      []*Source{},
+		masterVolume: 1,
+	}
+}
+
+// GetSampleRate returns the sample rate of the mixed stream.
+func (m *Mixer) GetSampleRate() int {
+	return m.sampleRate
+}
+
+// SetListener sets the camera, that the positional sources are heard from.
+// Without listener, the positional sources are heard from the origo, facing to -Z.
+func (m *Mixer) SetListener(c interfaces.Camera) {
+	m.listener = c
+}
+
+// SetMasterVolume updates the volume multiplier of the mixed stream.
+func (m *Mixer) SetMasterVolume(v float32) {
+	m.masterVolume = v
+}
+
+// AddSource adds the source to the mixer.
+func (m *Mixer) AddSource(s *Source) {
+	m.sources = append(m.sources, s)
+}
+
+// RemoveSource removes the source from the mixer.
+func (m *Mixer) RemoveSource(s *Source) {
+	for i := range m.sources {
+		if m.sources[i] == s {
+			m.sources = append(m.sources[:i], m.sources[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetSources returns the sources of the mixer.
+func (m *Mixer) GetSources() []*Source {
+	return m.sources
+}
+
+// listenerState returns the position and the right vector of the listener.
+func (m *Mixer) listenerState() (mgl32.Vec3, mgl32.Vec3) {
+	if m.listener == nil {
+		return mgl32.Vec3{0, 0, 0}, mgl32.Vec3{1, 0, 0}
+	}
+	// the first row of the view matrix is the right vector of the camera.
+	view := m.listener.GetViewMatrix()
+	right := mgl32.Vec3{view.At(0, 0), view.At(0, 1), view.At(0, 2)}
+	return m.listener.GetPosition(), right.Normalize()
+}
+
+// Mix returns the next frames of the mixed stream. The samples are interleaved
+// (left, right), they are clipped to [-1, 1]. The volume and the panning of the
+// positional sources are calculated once per call.
+func (m *Mixer) Mix(frames int) []float32 {
+	buffer := make([]float32, 2*frames)
+	position, right := m.listenerState()
+	for _, s := range m.sources {
+		if !s.IsPlaying() {
+			continue
+		}
+		left, r := s.gains(position, right)
+		s.mix(buffer, m.sampleRate, left*m.masterVolume, r*m.masterVolume)
+	}
+	for i, v := range buffer {
+		buffer[i] = float32(math.Max(-1, math.Min(1, float64(v))))
+	}
+	return buffer
+}
+
+// Update mixes the frames of the elapsed time (ms) and writes them to the sink.
+func (m *Mixer) Update(dt float64) error {
+	m.pending += dt * float64(m.sampleRate) / 1000
+	frames := int(m.pending)
+	if frames == 0 {
+		return nil
+	}
+	m.pending -= float64(frames)
+	return m.sink.Write(m.Mix(frames))
+}
+
+// Close closes the sink.
+func (m *Mixer) Close() error {
+	return m.sink.Close()
+}
@@ -0,0 +1,86 @@
+package audio
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/camera"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestNewMixer(t *testing.T) {
+	m := NewMixer(0, nil)
+	if m.GetSampleRate() != DEFAULT_SAMPLE_RATE {
+		t.Errorf("Invalid sample rate. '%d'.", m.GetSampleRate())
+	}
+	if _, ok := m.sink.(*NullSink); !ok {
+		t.Error("Missing null sink.")
+	}
+}
+
+func TestMixerListenerPanning(t *testing.T) {
+	m := NewMixer(1000, nil)
+	// looking to -Z, so +X is on the right.
+	m.SetListener(camera.NewCamera(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, -1, 0}, -90, 0))
+	s := NewPositionalSource(&Sound{SampleRate: 1000, Channels: 1, Samples: []float32{0.5, 0.5, 0.5, 0.5}}, mgl32.Vec3{1, 0, 0})
+	m.AddSource(s)
+	s.Play()
+	buffer := m.Mix(2)
+	if buffer[0] > 0.001 || buffer[1] < 0.49 {
+		t.Errorf("Source should be on the right. '%v'.", buffer)
+	}
+	m.RemoveSource(s)
+	if len(m.GetSources()) != 0 {
+		t.Error("Source should be removed.")
+	}
+}
+
+func TestMixerClipAndUpdate(t *testing.T) {
+	sink := NewNullSink()
+	m := NewMixer(1000, sink)
+	for i := 0; i < 3; i++ {
+		s := NewSource(&Sound{SampleRate: 1000, Channels: 1, Samples: []float32{0.5, 0.5, 0.5, 0.5}})
+		s.Loop = true
+		s.Play()
+		m.AddSource(s)
+	}
+	if buffer := m.Mix(1); buffer[0] != 1 || buffer[1] != 1 {
+		t.Errorf("Mixed stream should be clipped. '%v'.", buffer)
+	}
+	m.Update(2.5)
+	m.Update(2.5)
+	if sink.Frames() != 5 {
+		t.Errorf("Invalid number of written frames. '%d'.", sink.Frames())
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "out.wav")
+	sink, err := NewFileSink(path, 1000)
+	if err != nil {
+		t.Fatalf("Create failed. '%s'.", err.Error())
+	}
+	m := NewMixer(1000, sink)
+	s := NewSource(NewToneSound(WAVE_SQUARE, 100, 100, 0.5, 1000))
+	s.Play()
+	m.AddSource(s)
+	m.Update(50)
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close failed. '%s'.", err.Error())
+	}
+	decoded, err := LoadSound(path)
+	if err != nil {
+		t.Fatalf("Load failed. '%s'.", err.Error())
+	}
+	if decoded.Channels != 2 || decoded.SampleRate != 1000 || decoded.Frames() != 50 {
+		t.Errorf("Invalid written sound. '%d', '%d', '%d'.", decoded.Channels, decoded.SampleRate, decoded.Frames())
+	}
+}
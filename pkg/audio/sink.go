@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"os"
+)
+
+// Sink is the output of the mixer. The samples are interleaved stereo (left, right)
+// values between -1 and 1. The device outputs could be plugged in with this interface.
+type Sink interface {
+	Write(samples []float32) error
+	Close() error
+}
+
+// NullSink drops the samples, but it counts the written frames. It could be used
+// in the headless applications and in the tests.
+type NullSink struct {
+	frames int
+}
+
+// NewNullSink returns a null sink.
+func NewNullSink() *NullSink {
+	return &NullSink{}
+}
+
+// Write counts the frames of the samples.
+func (s *NullSink) Write(samples []float32) error {
+	s.frames += len(samples) / 2
+	return nil
+}
+
+// Close does nothing.
+func (s *NullSink) Close() error {
+	return nil
+}
+
+// Frames returns the number of the written frames.
+func (s *NullSink) Frames() int {
+	return s.frames
+}
+
+// FileSink writes the samples to a 16 bit stereo wav file. The header is finalized by the Close.
+type FileSink struct {
+	file       *os.File
+	sampleRate int
+	dataSize   int
+}
+
+// NewFileSink creates the given wav file for the stream with the given sample rate.
+func NewFileSink(path string, sampleRate int) (*FileSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileSink{file: f, sampleRate: sampleRate}
+	if err := writeWAVHeader(f, sampleRate, 2, 0); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write appends the samples to the file.
+func (s *FileSink) Write(samples []float32) error {
+	data := pcm16(samples)
+	n, err := s.file.Write(data)
+	s.dataSize += n
+	return err
+}
+
+// Close writes the final sizes to the header and closes the file.
+func (s *FileSink) Close() error {
+	if _, err := s.file.Seek(0, 0); err != nil {
+		s.file.Close()
+		return err
+	}
+	if err := writeWAVHeader(s.file, s.sampleRate, 2, s.dataSize); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
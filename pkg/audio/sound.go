@@ -0,0 +1,275 @@
+package audio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+
+	"github.com/jfreymuth/oggvorbis"
+)
+
+const (
+	// The waveforms of the generated sounds.
+	WAVE_SINE = iota
+	WAVE_SQUARE
+	WAVE_SAWTOOTH
+	WAVE_NOISE
+)
+
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+	// the max size of the fmt chunk. The extensible format has the longest one with 40 bytes.
+	wavMaxFmtChunkSize = 64
+)
+
+var (
+	ErrorUnknownAudioFormat = errors.New("Unknown audio format")
+	ErrorUnsupportedWav     = errors.New("Unsupported wav encoding")
+	ErrorInvalidWav         = errors.New("Invalid wav file")
+)
+
+// Sound is a decoded audio clip. The samples are interleaved by the channels, their
+// values are between -1 and 1.
+type Sound struct {
+	SampleRate int
+	Channels   int
+	Samples    []float32
+}
+
+// Frames returns the number of the sample frames (samples per channel).
+func (s *Sound) Frames() int {
+	if s.Channels == 0 {
+		return 0
+	}
+	return len(s.Samples) / s.Channels
+}
+
+// Duration returns the length of the sound in ms.
+func (s *Sound) Duration() float64 {
+	if s.SampleRate == 0 {
+		return 0
+	}
+	return float64(s.Frames()) * 1000 / float64(s.SampleRate)
+}
+
+// frame returns the mono and the stereo values of the given frame.
+func (s *Sound) frame(i int) (float32, float32, float32) {
+	if s.Channels == 1 {
+		v := s.Samples[i]
+		return v, v, v
+	}
+	l, r := s.Samples[i*s.Channels], s.Samples[i*s.Channels+1]
+	return (l + r) / 2, l, r
+}
+
+// LoadSound reads and decodes the given wav or ogg/vorbis file. The format is detected from the content.
+func LoadSound(path string) (*Sound, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f)
+}
+
+// Decode decodes a wav or ogg/vorbis stream. The format is detected from the first bytes.
+// It returns ErrorUnknownAudioFormat for the other formats.
+func Decode(r io.Reader) (*Sound, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return nil, ErrorUnknownAudioFormat
+	}
+	switch string(magic) {
+	case "RIFF":
+		return DecodeWAV(br)
+	case "OggS":
+		return DecodeOGG(br)
+	}
+	return nil, ErrorUnknownAudioFormat
+}
+
+// DecodeOGG decodes an ogg/vorbis stream.
+func DecodeOGG(r io.Reader) (*Sound, error) {
+	samples, format, err := oggvorbis.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Sound{SampleRate: format.SampleRate, Channels: format.Channels, Samples: samples}, nil
+}
+
+// DecodeWAV decodes a wav stream. The 8, 16, 24, 32 bit integer and the 32 bit float
+// encodings are supported, the others return ErrorUnsupportedWav.
+func DecodeWAV(r io.Reader) (*Sound, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil || string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, ErrorInvalidWav
+	}
+	var format, channels, bits uint16
+	var sampleRate uint32
+	fmtFound := false
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return nil, ErrorInvalidWav
+		}
+		// the sizes are not trusted, the chunks are not allocated before they are read.
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		padded := size + size%2
+		switch string(chunk[0:4]) {
+		case "fmt ":
+			if size < 16 || size > wavMaxFmtChunkSize {
+				return nil, ErrorInvalidWav
+			}
+			body := make([]byte, padded)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, ErrorInvalidWav
+			}
+			format = binary.LittleEndian.Uint16(body[0:2])
+			channels = binary.LittleEndian.Uint16(body[2:4])
+			sampleRate = binary.LittleEndian.Uint32(body[4:8])
+			bits = binary.LittleEndian.Uint16(body[14:16])
+			if format == wavFormatExtensible && size >= 26 {
+				// the first 2 bytes of the sub format guid is the format code.
+				format = binary.LittleEndian.Uint16(body[24:26])
+			}
+			fmtFound = true
+			break
+		case "data":
+			if !fmtFound || channels == 0 {
+				return nil, ErrorInvalidWav
+			}
+			// the truncated data chunk is accepted.
+			body, err := ioutil.ReadAll(io.LimitReader(r, size))
+			if err != nil {
+				return nil, ErrorInvalidWav
+			}
+			samples, err := decodePCM(body, format, bits)
+			if err != nil {
+				return nil, err
+			}
+			return &Sound{SampleRate: int(sampleRate), Channels: int(channels), Samples: samples}, nil
+		default:
+			// the other chunks are skipped, but they have to be complete.
+			if _, err := io.CopyN(ioutil.Discard, r, padded); err != nil {
+				return nil, ErrorInvalidWav
+			}
+			break
+		}
+	}
+}
+
+// decodePCM converts the raw wav data to float samples.
+func decodePCM(data []byte, format, bits uint16) ([]float32, error) {
+	bytesPerSample := int(bits) / 8
+	if format == wavFormatFloat && bits != 32 || format == wavFormatPCM && (bits < 8 || bits > 32 || bits%8 != 0) || format != wavFormatPCM && format != wavFormatFloat {
+		return nil, ErrorUnsupportedWav
+	}
+	samples := make([]float32, len(data)/bytesPerSample)
+	for i := range samples {
+		b := data[i*bytesPerSample:]
+		switch {
+		case format == wavFormatFloat:
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case bits == 8:
+			samples[i] = (float32(b[0]) - 128) / 128
+		case bits == 16:
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+		case bits == 24:
+			v := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+			samples[i] = float32(v) / (1 << 23)
+		case bits == 32:
+			samples[i] = float32(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+		}
+	}
+	return samples, nil
+}
+
+// writeWAVHeader writes the header of a 16 bit pcm wav file with the given number of data bytes.
+func writeWAVHeader(w io.Writer, sampleRate, channels, dataSize int) error {
+	header := struct {
+		Riff          [4]byte
+		Size          uint32
+		Wave          [4]byte
+		Fmt           [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		Riff:          [4]byte{'R', 'I', 'F', 'F'},
+		Size:          uint32(36 + dataSize),
+		Wave:          [4]byte{'W', 'A', 'V', 'E'},
+		Fmt:           [4]byte{'f', 'm', 't', ' '},
+		FmtSize:       16,
+		Format:        wavFormatPCM,
+		Channels:      uint16(channels),
+		SampleRate:    uint32(sampleRate),
+		ByteRate:      uint32(sampleRate * channels * 2),
+		BlockAlign:    uint16(channels * 2),
+		BitsPerSample: 16,
+		Data:          [4]byte{'d', 'a', 't', 'a'},
+		DataSize:      uint32(dataSize),
+	}
+	return binary.Write(w, binary.LittleEndian, header)
+}
+
+// pcm16 returns the 16 bit little endian encoding of the samples. The values are clipped to [-1, 1].
+func pcm16(samples []float32) []byte {
+	data := make([]byte, 2*len(samples))
+	for i, v := range samples {
+		v = float32(math.Max(-1, math.Min(1, float64(v))))
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(int16(v*math.MaxInt16)))
+	}
+	return data
+}
+
+// EncodeWAV writes the sound as a 16 bit pcm wav file.
+func EncodeWAV(w io.Writer, s *Sound) error {
+	data := pcm16(s.Samples)
+	if err := writeWAVHeader(w, s.SampleRate, s.Channels, len(data)); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// NewToneSound returns a generated mono sound with the given waveform, frequency (Hz),
+// duration (ms) and amplitude. It could be used for the simple effects, like the humming
+// lamps or the buzzing bugs, without sound files. The noise waveform ignores the frequency.
+func NewToneSound(waveform int, frequency float32, duration float64, amplitude float32, sampleRate int) *Sound {
+	frames := int(duration * float64(sampleRate) / 1000)
+	s := &Sound{SampleRate: sampleRate, Channels: 1, Samples: make([]float32, frames)}
+	rnd := rand.New(rand.NewSource(int64(frames)))
+	for i := range s.Samples {
+		phase := math.Mod(float64(frequency)*float64(i)/float64(sampleRate), 1)
+		var v float64
+		switch waveform {
+		case WAVE_SINE:
+			v = math.Sin(2 * math.Pi * phase)
+		case WAVE_SQUARE:
+			v = 1
+			if phase >= 0.5 {
+				v = -1
+			}
+		case WAVE_SAWTOOTH:
+			v = 2*phase - 1
+		case WAVE_NOISE:
+			v = 2*rnd.Float64() - 1
+		}
+		s.Samples[i] = amplitude * float32(v)
+	}
+	return s
+}
@@ -0,0 +1,105 @@
+package audio
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestEncodeDecodeWAV(t *testing.T) {
+	s := &Sound{SampleRate: 22050, Channels: 2, Samples: []float32{0, 0, 0.5, -0.5, 1, -1}}
+	var buf bytes.Buffer
+	if err := EncodeWAV(&buf, s); err != nil {
+		t.Fatalf("Encode failed. '%s'.", err.Error())
+	}
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode failed. '%s'.", err.Error())
+	}
+	if decoded.SampleRate != s.SampleRate || decoded.Channels != s.Channels {
+		t.Errorf("Invalid format. '%d', '%d'.", decoded.SampleRate, decoded.Channels)
+	}
+	if len(decoded.Samples) != len(s.Samples) {
+		t.Fatalf("Invalid number of samples. '%d'.", len(decoded.Samples))
+	}
+	for i := range s.Samples {
+		if math.Abs(float64(decoded.Samples[i]-s.Samples[i])) > 0.001 {
+			t.Errorf("Invalid sample %d. '%f' instead of '%f'.", i, decoded.Samples[i], s.Samples[i])
+		}
+	}
+	if decoded.Frames() != 3 {
+		t.Errorf("Invalid frames. '%d'.", decoded.Frames())
+	}
+}
+
+func TestDecodeUnknownFormat(t *testing.T) {
+	if _, err := Decode(bytes.NewReader([]byte("ID3 not a sound"))); err != ErrorUnknownAudioFormat {
+		t.Errorf("Missing unknown format error. '%v'.", err)
+	}
+	if _, err := DecodeWAV(bytes.NewReader([]byte("RIFF"))); err != ErrorInvalidWav {
+		t.Errorf("Missing invalid wav error. '%v'.", err)
+	}
+}
+
+// testWavChunks returns a wav stream with the given chunks after the header.
+func testWavChunks(chunks ...[]byte) *bytes.Reader {
+	data := []byte("RIFF\x00\x00\x00\x00WAVE")
+	for _, c := range chunks {
+		data = append(data, c...)
+	}
+	return bytes.NewReader(data)
+}
+
+func TestDecodeWAVChunkSize(t *testing.T) {
+	// 16 bit pcm, mono, 8000 Hz.
+	fmtChunk := []byte("fmt \x10\x00\x00\x00\x01\x00\x01\x00\x40\x1f\x00\x00\x80\x3e\x00\x00\x02\x00\x10\x00")
+	testData := [][]byte{
+		[]byte("fmt \xff\xff\xff\xff"),
+		[]byte("fmt \x10\x00\x00\x00\x01\x00"),
+		[]byte("LIST\xff\xff\xff\xff\x00\x00"),
+		[]byte("LIST\x03\x00\x00\x00\x00\x00\x00"),
+	}
+	for i, chunk := range testData {
+		if _, err := DecodeWAV(testWavChunks(chunk)); err != ErrorInvalidWav {
+			t.Errorf("Missing invalid wav error in case %d. '%v'.", i, err)
+		}
+		if _, err := DecodeWAV(testWavChunks(fmtChunk, chunk)); err != ErrorInvalidWav {
+			t.Errorf("Missing invalid wav error after the fmt chunk in case %d. '%v'.", i, err)
+		}
+	}
+	// the truncated data chunk is decoded until the end of the stream.
+	s, err := DecodeWAV(testWavChunks(fmtChunk, []byte("LIST\x01\x00\x00\x00\x00\x00"), []byte("data\xff\xff\xff\xff\x00\x40\x00\xc0")))
+	if err != nil {
+		t.Fatalf("Decode failed. '%s'.", err.Error())
+	}
+	if s.SampleRate != 8000 || s.Channels != 1 || len(s.Samples) != 2 || s.Samples[0] != 0.5 || s.Samples[1] != -0.5 {
+		t.Errorf("Invalid sound. '%v'.", s)
+	}
+}
+
+func TestLoadSoundOGG(t *testing.T) {
+	s, err := LoadSound("testdata/test.ogg")
+	if err != nil {
+		t.Fatalf("Load failed. '%s'.", err.Error())
+	}
+	if s.SampleRate <= 0 || s.Channels <= 0 || s.Frames() == 0 {
+		t.Errorf("Invalid sound. '%d', '%d', '%d'.", s.SampleRate, s.Channels, s.Frames())
+	}
+}
+
+func TestNewToneSound(t *testing.T) {
+	s := NewToneSound(WAVE_SQUARE, 100, 100, 0.5, 1000)
+	if s.Frames() != 100 {
+		t.Errorf("Invalid frames. '%d'.", s.Frames())
+	}
+	if s.Duration() != 100 {
+		t.Errorf("Invalid duration. '%f'.", s.Duration())
+	}
+	if s.Samples[0] != 0.5 || s.Samples[5] != -0.5 {
+		t.Errorf("Invalid samples. '%f', '%f'.", s.Samples[0], s.Samples[5])
+	}
+	sine := NewToneSound(WAVE_SINE, 250, 4, 1, 1000)
+	if math.Abs(float64(sine.Samples[1]-1)) > 0.0001 {
+		t.Errorf("Invalid sine sample. '%f'.", sine.Samples[1])
+	}
+}
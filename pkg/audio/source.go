@@ -0,0 +1,216 @@
+package audio
+
+import (
+	"math"
+
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	defaultMinDistance = float32(1.0)
+	defaultMaxDistance = float32(50.0)
+	defaultRolloff     = float32(1.0)
+	// the pitch is clamped to this value, the playback can't stop or go backwards.
+	MinPitch = float32(0.01)
+)
+
+// Positioner is anything that has a world position, like the lights or the cameras.
+type Positioner interface {
+	GetPosition() mgl32.Vec3
+}
+
+// meshContainer is implemented by the models of the model package.
+type meshContainer interface {
+	GetMeshByIndex(int) (interfaces.Mesh, error)
+}
+
+// Source plays a sound. The positional sources are attenuated by the distance from the
+// listener and panned by the direction, the others are played as they are.
+type Source struct {
+	sound *Sound
+	// the position of the source, if it isn't attached.
+	position mgl32.Vec3
+	// the position getter of the attached mesh, model or positioner.
+	attached func() mgl32.Vec3
+	// the volume multiplier.
+	Volume float32
+	// the playback speed multiplier. It also changes the pitch. The values under the
+	// MinPitch are played with the MinPitch.
+	Pitch float32
+	Loop  bool
+	// if it is true, the source is attenuated and panned.
+	Positional bool
+	// the source is played with full volume in the min distance, it is silent after the max distance.
+	// Between them the volume is min / (min + rolloff * (distance - min)).
+	MinDistance float32
+	MaxDistance float32
+	Rolloff     float32
+	// the current position of the playback in source frames.
+	cursor  float64
+	playing bool
+}
+
+// NewSource returns a stopped, not positional source of the given sound.
+func NewSource(s *Sound) *Source {
+	return &Source{
+		sound:       s,
+		Volume:      1,
+		Pitch:       1,
+		MinDistance: defaultMinDistance,
+		MaxDistance: defaultMaxDistance,
+		Rolloff:     defaultRolloff,
+	}
+}
+
+// NewPositionalSource returns a stopped, positional source of the given sound at the given position.
+func NewPositionalSource(s *Sound, position mgl32.Vec3) *Source {
+	src := NewSource(s)
+	src.Positional = true
+	src.position = position
+	return src
+}
+
+// SetPosition updates the position of the source and detaches it.
+func (s *Source) SetPosition(p mgl32.Vec3) {
+	s.position = p
+	s.attached = nil
+}
+
+// Attach attaches the source to the given positioner, like a light source.
+func (s *Source) Attach(p Positioner) {
+	s.attached = p.GetPosition
+}
+
+// AttachToMesh attaches the source to the world position of the given mesh. The
+// position of the child meshes is transformed with the parent transformations.
+func (s *Source) AttachToMesh(m interfaces.Mesh) {
+	s.attached = func() mgl32.Vec3 {
+		if m.IsParentMesh() {
+			return m.GetPosition()
+		}
+		return mgl32.TransformCoordinate(m.GetPosition(), m.GetParentTranslationTransformation())
+	}
+}
+
+// AttachToModel attaches the source to the first mesh of the given model. The models
+// of the model package are supported. It returns false if the model doesn't have mesh.
+func (s *Source) AttachToModel(m interfaces.Model) bool {
+	container, ok := m.(meshContainer)
+	if !ok {
+		return false
+	}
+	msh, err := container.GetMeshByIndex(0)
+	if err != nil {
+		return false
+	}
+	s.AttachToMesh(msh)
+	return true
+}
+
+// GetPosition returns the current world position of the source.
+func (s *Source) GetPosition() mgl32.Vec3 {
+	if s.attached != nil {
+		return s.attached()
+	}
+	return s.position
+}
+
+// GetSound returns the sound of the source.
+func (s *Source) GetSound() *Sound {
+	return s.sound
+}
+
+// Play starts the playback. The paused source is continued.
+func (s *Source) Play() {
+	s.playing = true
+}
+
+// Pause stops the playback, but the position is kept.
+func (s *Source) Pause() {
+	s.playing = false
+}
+
+// Stop stops the playback and rewinds the source.
+func (s *Source) Stop() {
+	s.playing = false
+	s.cursor = 0
+}
+
+// IsPlaying returns true if the source is played.
+func (s *Source) IsPlaying() bool {
+	return s.playing
+}
+
+// attenuation returns the distance based volume multiplier of the positional source.
+func (s *Source) attenuation(distance float32) float32 {
+	if distance > s.MaxDistance {
+		return 0
+	}
+	if distance <= s.MinDistance {
+		return 1
+	}
+	return s.MinDistance / (s.MinDistance + s.Rolloff*(distance-s.MinDistance))
+}
+
+// gains returns the volume multipliers of the left and the right channel. The positional
+// sources are panned with equal power panning by the direction of the source relative to
+// the right vector of the listener.
+func (s *Source) gains(listener, right mgl32.Vec3) (float32, float32) {
+	if !s.Positional {
+		return s.Volume, s.Volume
+	}
+	toSource := s.GetPosition().Sub(listener)
+	distance := toSource.Len()
+	gain := s.Volume * s.attenuation(distance)
+	pan := float32(0)
+	if distance > 0 {
+		pan = toSource.Normalize().Dot(right)
+	}
+	angle := float64(pan+1) * math.Pi / 4
+	return gain * float32(math.Cos(angle)), gain * float32(math.Sin(angle))
+}
+
+// mix adds the next frames of the source to the interleaved stereo buffer, that is
+// played with the given sample rate. The sound is resampled with linear interpolation.
+func (s *Source) mix(buffer []float32, sampleRate int, leftGain, rightGain float32) {
+	frames := s.sound.Frames()
+	if !s.playing || frames == 0 || sampleRate <= 0 {
+		return
+	}
+	pitch := s.Pitch
+	if pitch < MinPitch {
+		pitch = MinPitch
+	}
+	step := float64(s.sound.SampleRate) / float64(sampleRate) * float64(pitch)
+	for i := 0; i+1 < len(buffer); i += 2 {
+		if s.cursor >= float64(frames) {
+			if !s.Loop {
+				s.Stop()
+				return
+			}
+			s.cursor = math.Mod(s.cursor, float64(frames))
+		}
+		index := int(s.cursor)
+		next := index + 1
+		if next >= frames {
+			next = index
+			if s.Loop {
+				next = 0
+			}
+		}
+		t := float32(s.cursor - float64(index))
+		mono1, l1, r1 := s.sound.frame(index)
+		mono2, l2, r2 := s.sound.frame(next)
+		if s.Positional {
+			v := mono1 + (mono2-mono1)*t
+			buffer[i] += v * leftGain
+			buffer[i+1] += v * rightGain
+		} else {
+			buffer[i] += (l1 + (l2-l1)*t) * leftGain
+			buffer[i+1] += (r1 + (r2-r1)*t) * rightGain
+		}
+		s.cursor += step
+	}
+}
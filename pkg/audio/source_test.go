@@ -0,0 +1,120 @@
+package audio
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSourceAttenuation(t *testing.T) {
+	s := NewPositionalSource(NewToneSound(WAVE_SINE, 440, 10, 1, 1000), mgl32.Vec3{0, 0, 0})
+	s.MinDistance = 2
+	s.MaxDistance = 10
+	s.Rolloff = 1
+	testData := []struct {
+		distance float32
+		expected float32
+	}{
+		{0, 1},
+		{2, 1},
+		{4, 0.5},
+		{10, 0.2},
+		{11, 0},
+	}
+	for _, tt := range testData {
+		if v := s.attenuation(tt.distance); v != tt.expected {
+			t.Errorf("Invalid attenuation at %f. '%f' instead of '%f'.", tt.distance, v, tt.expected)
+		}
+	}
+}
+
+func TestSourceGains(t *testing.T) {
+	s := NewPositionalSource(NewToneSound(WAVE_SINE, 440, 10, 1, 1000), mgl32.Vec3{1, 0, 0})
+	right := mgl32.Vec3{1, 0, 0}
+	l, r := s.gains(mgl32.Vec3{}, right)
+	if l > 0.0001 || r < 0.999 {
+		t.Errorf("Source on the right should be heard on the right. '%f', '%f'.", l, r)
+	}
+	s.SetPosition(mgl32.Vec3{-1, 0, 0})
+	l, r = s.gains(mgl32.Vec3{}, right)
+	if r > 0.0001 || l < 0.999 {
+		t.Errorf("Source on the left should be heard on the left. '%f', '%f'.", l, r)
+	}
+	s.SetPosition(mgl32.Vec3{0, 0, -1})
+	l, r = s.gains(mgl32.Vec3{}, right)
+	if !mgl32.FloatEqualThreshold(l, r, 0.0001) || !mgl32.FloatEqualThreshold(l*l+r*r, 1, 0.0001) {
+		t.Errorf("Source in front should be centered with equal power. '%f', '%f'.", l, r)
+	}
+	s.Positional = false
+	s.Volume = 0.3
+	l, r = s.gains(mgl32.Vec3{100, 0, 0}, right)
+	if l != 0.3 || r != 0.3 {
+		t.Errorf("Not positional source should use the volume. '%f', '%f'.", l, r)
+	}
+}
+
+type testPositioner struct {
+	position mgl32.Vec3
+}
+
+func (p *testPositioner) GetPosition() mgl32.Vec3 {
+	return p.position
+}
+
+func TestSourceAttach(t *testing.T) {
+	s := NewPositionalSource(NewToneSound(WAVE_SINE, 440, 10, 1, 1000), mgl32.Vec3{1, 2, 3})
+	p := &testPositioner{position: mgl32.Vec3{4, 5, 6}}
+	s.Attach(p)
+	if s.GetPosition() != p.position {
+		t.Errorf("Invalid attached position. '%v'.", s.GetPosition())
+	}
+	p.position = mgl32.Vec3{7, 8, 9}
+	if s.GetPosition() != p.position {
+		t.Errorf("Attached position should follow the positioner. '%v'.", s.GetPosition())
+	}
+	s.SetPosition(mgl32.Vec3{1, 1, 1})
+	if s.GetPosition() != (mgl32.Vec3{1, 1, 1}) {
+		t.Errorf("SetPosition should detach. '%v'.", s.GetPosition())
+	}
+}
+
+func TestSourcePlayback(t *testing.T) {
+	s := NewSource(&Sound{SampleRate: 10, Channels: 1, Samples: []float32{1, 1, 1, 1}})
+	buffer := make([]float32, 12)
+	s.mix(buffer, 10, 1, 1)
+	if buffer[0] != 0 {
+		t.Error("Stopped source should be silent.")
+	}
+	s.Play()
+	s.mix(buffer, 10, 1, 1)
+	if s.IsPlaying() {
+		t.Error("Finished source should be stopped.")
+	}
+	if buffer[7] != 1 || buffer[8] != 0 {
+		t.Errorf("Invalid mixed samples. '%v'.", buffer)
+	}
+	s.Loop = true
+	s.Play()
+	buffer = make([]float32, 12)
+	s.mix(buffer, 10, 1, 1)
+	if !s.IsPlaying() || buffer[11] != 1 {
+		t.Errorf("Looped source should keep playing. '%v'.", buffer)
+	}
+	s.Stop()
+	if s.IsPlaying() || s.cursor != 0 {
+		t.Error("Stop should rewind the source.")
+	}
+}
+func TestSourceInvalidPitch(t *testing.T) {
+	s := NewSource(&Sound{SampleRate: 10, Channels: 1, Samples: []float32{1, 1, 1, 1}})
+	for _, pitch := range []float32{0, -1} {
+		s.Pitch = pitch
+		s.Play()
+		buffer := make([]float32, 12)
+		s.mix(buffer, 10, 1, 1)
+		if s.cursor <= 0 {
+			t.Errorf("Invalid cursor with pitch '%f'. '%f'.", pitch, s.cursor)
+		}
+		s.Stop()
+	}
+}
@@ -276,3 +276,126 @@ func (c *Camera) GetRotationStep() float32 {
 func (c *Camera) SetRotationStep(v float32) {
 	c.rotationStep = v
 }
+
+// OrthographicCamera is a fixed camera with orthographic projection. It looks to the -Z
+// direction from its position. It is designed for the hud overlays, so that the movement
+// and rotation functions don't change it.
+type OrthographicCamera struct {
+	cameraPosition mgl32.Vec3
+	// Projection options.
+	projectionOptions struct {
+		left   float32
+		right  float32
+		bottom float32
+		top    float32
+
+		near float32
+		far  float32
+	}
+}
+
+// NewOrthographicCamera returns an orthographic camera in the origin with the given projection.
+func NewOrthographicCamera(left, right, bottom, top, near, far float32) *OrthographicCamera {
+	c := &OrthographicCamera{
+		cameraPosition: mgl32.Vec3{0, 0, 0},
+	}
+	c.SetupProjection(left, right, bottom, top, near, far)
+	return c
+}
+
+// Log returns the string representation of this object.
+func (c *OrthographicCamera) Log() string {
+	logString := "cameraPosition: Vector{" + transformations.Vec3ToString(c.cameraPosition) + "}\n"
+	logString += "ProjectionOptions:\n"
+	logString += " - left : " + transformations.Float32ToString(c.projectionOptions.left) + "\n"
+	logString += " - right : " + transformations.Float32ToString(c.projectionOptions.right) + "\n"
+	logString += " - bottom : " + transformations.Float32ToString(c.projectionOptions.bottom) + "\n"
+	logString += " - top : " + transformations.Float32ToString(c.projectionOptions.top) + "\n"
+	logString += " - near : " + transformations.Float32ToString(c.projectionOptions.near) + "\n"
+	logString += " - far : " + transformations.Float32ToString(c.projectionOptions.far) + "\n"
+	return logString
+}
+
+// SetupProjection sets the visible box of the camera. It could be used after the window resize.
+func (c *OrthographicCamera) SetupProjection(left, right, bottom, top, near, far float32) {
+	c.projectionOptions.left = left
+	c.projectionOptions.right = right
+	c.projectionOptions.bottom = bottom
+	c.projectionOptions.top = top
+	c.projectionOptions.near = near
+	c.projectionOptions.far = far
+}
+
+// GetProjectionMatrix returns the orthographic projection matrix of the camera.
+func (c *OrthographicCamera) GetProjectionMatrix() mgl32.Mat4 {
+	return mgl32.Ortho(
+		c.projectionOptions.left,
+		c.projectionOptions.right,
+		c.projectionOptions.bottom,
+		c.projectionOptions.top,
+		c.projectionOptions.near,
+		c.projectionOptions.far)
+}
+
+// GetViewMatrix returns the viewMatrix of the camera. It is a translation to the camera position.
+func (c *OrthographicCamera) GetViewMatrix() mgl32.Mat4 {
+	return mgl32.Translate3D(-c.cameraPosition.X(), -c.cameraPosition.Y(), -c.cameraPosition.Z())
+}
+
+// Walk doesn't move the orthographic camera.
+func (c *OrthographicCamera) Walk(amount float32) {
+}
+
+// Strafe doesn't move the orthographic camera.
+func (c *OrthographicCamera) Strafe(amount float32) {
+}
+
+// Lift doesn't move the orthographic camera.
+func (c *OrthographicCamera) Lift(amount float32) {
+}
+
+// UpdateDirection doesn't rotate the orthographic camera.
+func (c *OrthographicCamera) UpdateDirection(amountX, amountY float32) {
+}
+
+// GetPosition returns the current position of the camera.
+func (c *OrthographicCamera) GetPosition() mgl32.Vec3 {
+	return c.cameraPosition
+}
+
+// SetPosition updates the position of the camera.
+func (c *OrthographicCamera) SetPosition(p mgl32.Vec3) {
+	c.cameraPosition = p
+}
+
+// GetVelocity returns 0, the orthographic camera doesn't move.
+func (c *OrthographicCamera) GetVelocity() float32 {
+	return 0
+}
+
+// GetRotationStep returns 0, the orthographic camera doesn't rotate.
+func (c *OrthographicCamera) GetRotationStep() float32 {
+	return 0
+}
+
+// GetBoundingObject returns the bounding object of the camera. It is defined as a sphere.
+func (c *OrthographicCamera) GetBoundingObject() *coldet.Sphere {
+	return coldet.NewBoundingSphere(
+		[3]float32{c.cameraPosition.X(), c.cameraPosition.Y(), c.cameraPosition.Z()},
+		defaultCameraRadius)
+}
+
+// BoundingObjectAfterWalk returns the current bounding object, the camera doesn't move.
+func (c *OrthographicCamera) BoundingObjectAfterWalk(amount float32) *coldet.Sphere {
+	return c.GetBoundingObject()
+}
+
+// BoundingObjectAfterStrafe returns the current bounding object, the camera doesn't move.
+func (c *OrthographicCamera) BoundingObjectAfterStrafe(amount float32) *coldet.Sphere {
+	return c.GetBoundingObject()
+}
+
+// BoundingObjectAfterLift returns the current bounding object, the camera doesn't move.
+func (c *OrthographicCamera) BoundingObjectAfterLift(amount float32) *coldet.Sphere {
+	return c.GetBoundingObject()
+}
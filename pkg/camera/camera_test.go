@@ -396,3 +396,44 @@ func TestSetPosition(t *testing.T) {
 		t.Errorf("Invalid position. Instead of '%v', we have '%v'.\n", newPosition, cam.cameraPosition)
 	}
 }
+func TestNewOrthographicCamera(t *testing.T) {
+	cam := NewOrthographicCamera(0, 800, 0, 600, -1, 1)
+	if cam.GetPosition() != DefaultCameraPosition {
+		t.Errorf("Invalid position")
+	}
+	if len(cam.Log()) < 10 {
+		t.Error("Log too short.")
+	}
+	// the corners of the box are mapped to the corners of the screen.
+	m := cam.GetProjectionMatrix().Mul4(cam.GetViewMatrix())
+	if p := mgl32.TransformCoordinate(mgl32.Vec3{800, 600, 0}, m); !p.ApproxEqual(mgl32.Vec3{1, 1, 0}) {
+		t.Errorf("Invalid transformation. Instead of '%v', we have '%v'.\n", mgl32.Vec3{1, 1, 0}, p)
+	}
+	cam.SetPosition(mgl32.Vec3{400, 300, 0})
+	m = cam.GetProjectionMatrix().Mul4(cam.GetViewMatrix())
+	if p := mgl32.TransformCoordinate(mgl32.Vec3{400, 300, 0}, m); !p.ApproxEqual(mgl32.Vec3{-1, -1, 0}) {
+		t.Errorf("Invalid transformation. Instead of '%v', we have '%v'.\n", mgl32.Vec3{-1, -1, 0}, p)
+	}
+}
+func TestOrthographicCameraMovement(t *testing.T) {
+	cam := NewOrthographicCamera(-1, 1, -1, 1, -1, 1)
+	cam.Walk(1)
+	cam.Strafe(1)
+	cam.Lift(1)
+	cam.UpdateDirection(10, 10)
+	if cam.GetPosition() != DefaultCameraPosition || cam.GetViewMatrix() != mgl32.Ident4() {
+		t.Error("The orthographic camera shouldn't move.")
+	}
+	if cam.GetVelocity() != 0 || cam.GetRotationStep() != 0 {
+		t.Error("Invalid velocity or rotation step.")
+	}
+	for _, bo := range []interface{ X() float32 }{cam.BoundingObjectAfterWalk(1), cam.BoundingObjectAfterStrafe(1), cam.BoundingObjectAfterLift(1)} {
+		if bo.X() != 0 {
+			t.Error("Invalid bounding object.")
+		}
+	}
+	cam.SetupProjection(0, 2, 0, 2, -1, 1)
+	if p := mgl32.TransformCoordinate(mgl32.Vec3{1, 1, 0}, cam.GetProjectionMatrix()); !p.ApproxEqual(mgl32.Vec3{0, 0, 0}) {
+		t.Errorf("Invalid projection '%v'.", p)
+	}
+}
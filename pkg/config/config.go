@@ -13,12 +13,28 @@ const (
 	ValueTypeText
 	ValueTypeVector
 	ValueTypeBool
+	ValueTypeEnum
+	ValueTypeSlider
+	ValueTypeColor
+	ValueTypeFilePath
+	ValueTypeKeyBinding
 )
 
 var (
-	InvalidType = errors.New("Invalid type")
+	InvalidType  = errors.New("Invalid type")
+	InvalidValue = errors.New("Invalid value")
 )
 
+// Color is the value of the color config items. Its components are in the [0-1] interval.
+type Color mgl32.Vec3
+
+// FilePath is the value of the file path config items.
+type FilePath string
+
+// KeyBinding is the value of the key binder config items. It is in the format of the
+// keyboard bindings of the input package, like 'key:ctrl+W'.
+type KeyBinding string
+
 type ConfigItem struct {
 	key               string
 	label             string
@@ -27,6 +43,10 @@ type ConfigItem struct {
 	defaultValue      interface{}
 	currentValue      interface{}
 	validatorFunction interface{}
+	// the options of the enum items.
+	options []string
+	// the range and the step of the slider items.
+	min, max, step float32
 }
 
 // NewConfigItem returns a ConfigItem. It gets the valueType from the type of the defaultValue.
@@ -52,6 +72,15 @@ func NewConfigItem(key, label, description string, defaultValue interface{}, val
 	case mgl32.Vec3:
 		valueType = ValueTypeVector
 		break
+	case Color:
+		valueType = ValueTypeColor
+		break
+	case FilePath:
+		valueType = ValueTypeFilePath
+		break
+	case KeyBinding:
+		valueType = ValueTypeKeyBinding
+		break
 	default:
 		panic("Unhandled value type.")
 	}
@@ -66,6 +95,49 @@ func NewConfigItem(key, label, description string, defaultValue interface{}, val
 	}
 }
 
+// NewEnumConfigItem returns a ConfigItem, that could have one of the given options as value.
+// In case of missing options or if the default value is not an option, it panics.
+func NewEnumConfigItem(key, label, description string, defaultValue string, options []string) *ConfigItem {
+	ci := NewConfigItem(key, label, description, defaultValue, nil)
+	ci.valueType = ValueTypeEnum
+	ci.options = options
+	if !ci.isOption(defaultValue) {
+		panic("The default value has to be an option.")
+	}
+	return ci
+}
+
+// NewSliderConfigItem returns a float ConfigItem, that has value in the [min-max] interval.
+// The step is the granularity of the value, the 0 step means continuous value. In case of
+// invalid range or if the default value is out of the range, it panics.
+func NewSliderConfigItem(key, label, description string, defaultValue, min, max, step float32) *ConfigItem {
+	ci := NewConfigItem(key, label, description, defaultValue, nil)
+	ci.valueType = ValueTypeSlider
+	ci.min = min
+	ci.max = max
+	ci.step = step
+	if min >= max || step < 0 || !ci.inRange(defaultValue) {
+		panic("Invalid slider range.")
+	}
+	return ci
+}
+func (ci *ConfigItem) isOption(v string) bool {
+	for i := 0; i < len(ci.options); i++ {
+		if ci.options[i] == v {
+			return true
+		}
+	}
+	return false
+}
+func (ci *ConfigItem) inRange(v float32) bool {
+	return v >= ci.min && v <= ci.max
+}
+
+// GetKey returns the key of the ConfigItem
+func (ci *ConfigItem) GetKey() string {
+	return ci.key
+}
+
 // GetLabel returns the label of the ConfigItem
 func (ci *ConfigItem) GetLabel() string {
 	return ci.label
@@ -96,6 +168,16 @@ func (ci *ConfigItem) GetValidatorFunction() interface{} {
 	return ci.validatorFunction
 }
 
+// GetOptions returns the options of the enum ConfigItem.
+func (ci *ConfigItem) GetOptions() []string {
+	return ci.options
+}
+
+// GetRange returns the min, max values and the step of the slider ConfigItem.
+func (ci *ConfigItem) GetRange() (float32, float32, float32) {
+	return ci.min, ci.max, ci.step
+}
+
 // IsConfigOf gets a key as input and returns true, if the key is equal with
 // the key of the item
 func (ci *ConfigItem) IsConfigOf(key string) bool {
@@ -104,10 +186,26 @@ func (ci *ConfigItem) IsConfigOf(key string) bool {
 
 // SetCurrentValue gets a value interface input. It checks that the current value
 // type and the default value type is same or not. In case of difference, the value
-// will not be updated.
+// will not be updated. The value of the enum items has to be an option, the value of
+// the slider items has to be in the range, otherwise the InvalidValue is returned.
 func (ci *ConfigItem) SetCurrentValue(v interface{}) error {
+	if err := ci.checkValue(v); err != nil {
+		return err
+	}
+	ci.currentValue = v
+	return nil
+}
+
+// checkValue returns error, if the value can't be the current value of the item.
+func (ci *ConfigItem) checkValue(v interface{}) error {
 	switch v.(type) {
 	case string:
+		if ci.valueType == ValueTypeEnum {
+			if !ci.isOption(v.(string)) {
+				return InvalidValue
+			}
+			break
+		}
 		if ci.valueType != ValueTypeText {
 			return InvalidType
 		}
@@ -123,6 +221,12 @@ func (ci *ConfigItem) SetCurrentValue(v interface{}) error {
 		}
 		break
 	case float32:
+		if ci.valueType == ValueTypeSlider {
+			if !ci.inRange(v.(float32)) {
+				return InvalidValue
+			}
+			break
+		}
 		if ci.valueType != ValueTypeFloat {
 			return InvalidType
 		}
@@ -137,10 +241,24 @@ func (ci *ConfigItem) SetCurrentValue(v interface{}) error {
 			return InvalidType
 		}
 		break
+	case Color:
+		if ci.valueType != ValueTypeColor {
+			return InvalidType
+		}
+		break
+	case FilePath:
+		if ci.valueType != ValueTypeFilePath {
+			return InvalidType
+		}
+		break
+	case KeyBinding:
+		if ci.valueType != ValueTypeKeyBinding {
+			return InvalidType
+		}
+		break
 	default:
 		return InvalidType
 	}
-	ci.currentValue = v
 	return nil
 }
 
@@ -156,6 +274,18 @@ func (c Config) AddConfig(key, label, description string, defaultValue interface
 	c[key] = NewConfigItem(key, label, description, defaultValue, validator)
 }
 
+// AddEnumConfig inserts a new enum config item to the config. In case of invalid options
+// a panic will occure.
+func (c Config) AddEnumConfig(key, label, description string, defaultValue string, options []string) {
+	c[key] = NewEnumConfigItem(key, label, description, defaultValue, options)
+}
+
+// AddSliderConfig inserts a new slider config item to the config. In case of invalid range
+// a panic will occure.
+func (c Config) AddSliderConfig(key, label, description string, defaultValue, min, max, step float32) {
+	c[key] = NewSliderConfigItem(key, label, description, defaultValue, min, max, step)
+}
+
 // SetCurrentValue sets the current value of the config item for the given key.
 func (c Config) SetCurrentValue(key string, currentValue interface{}) error {
 	return c[key].SetCurrentValue(currentValue)
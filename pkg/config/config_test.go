@@ -21,12 +21,18 @@ func TestNewConfigItemValidValue(t *testing.T) {
 		{"key4", "label 4", "description 4", string("seven"), nil, ValueTypeText},
 		{"key5", "label 5", "description 5", true, nil, ValueTypeBool},
 		{"key6", "label 6", "description 6", mgl32.Vec3{0, 0, 0}, nil, ValueTypeVector},
+		{"key7", "label 7", "description 7", Color{1, 0, 0}, nil, ValueTypeColor},
+		{"key8", "label 8", "description 8", FilePath("assets"), nil, ValueTypeFilePath},
+		{"key9", "label 9", "description 9", KeyBinding("key:W"), nil, ValueTypeKeyBinding},
 	}
 	for _, tt := range testData {
 		fi := NewConfigItem(tt.key, tt.label, tt.desc, tt.value, tt.validator)
 		if fi.key != tt.key {
 			t.Errorf("Invalid key value. Instead of '%s', we have '%s'.", tt.key, fi.key)
 		}
+		if fi.GetKey() != tt.key {
+			t.Errorf("Invalid GetKey value. Instead of '%s', we have '%s'.", tt.key, fi.GetKey())
+		}
 		if fi.label != tt.label {
 			t.Errorf("Invalid label value. Instead of '%s', we have '%s'.", tt.label, fi.label)
 		}
@@ -84,6 +90,9 @@ func TestConfigItemSetCurrentValue(t *testing.T) {
 		{"key4", "label 4", "description 4", string("seven"), nil, string("nine")},
 		{"key5", "label 5", "description 5", true, nil, false},
 		{"key6", "label 6", "description 6", mgl32.Vec3{0, 0, 0}, nil, mgl32.Vec3{1, 1, 1}},
+		{"key7", "label 7", "description 7", Color{0, 0, 0}, nil, Color{1, 1, 1}},
+		{"key8", "label 8", "description 8", FilePath("assets"), nil, FilePath("fonts")},
+		{"key9", "label 9", "description 9", KeyBinding("key:W"), nil, KeyBinding("key:ctrl+S")},
 	}
 	for _, tt := range testData {
 		fi := NewConfigItem(tt.key, tt.label, tt.desc, tt.value, tt.validator)
@@ -110,6 +119,9 @@ func TestConfigItemSetCurrentValue(t *testing.T) {
 		{"key4", string("eleven")},
 		{"key5", false},
 		{"key6", mgl32.Vec3{0, 1, 0}},
+		{"key7", Color{0, 1, 0}},
+		{"key8", FilePath("models")},
+		{"key9", KeyBinding("key:E")},
 	}
 	for _, ed := range errorData {
 		for _, tt := range testData {
@@ -199,3 +211,54 @@ func TestConfigSetCurrentValue(t *testing.T) {
 		}
 	}
 }
+func TestEnumConfigItem(t *testing.T) {
+	options := []string{"low", "medium", "high"}
+	conf := New()
+	conf.AddEnumConfig("quality", "Quality", "desc", "medium", options)
+	ci := conf["quality"]
+	if ci.GetValueType() != ValueTypeEnum || len(ci.GetOptions()) != 3 || ci.GetCurrentValue() != "medium" {
+		t.Error("Invalid enum item.")
+	}
+	if err := ci.SetCurrentValue("high"); err != nil || ci.GetCurrentValue() != "high" {
+		t.Errorf("The option should be set. '%v'.", err)
+	}
+	if err := ci.SetCurrentValue("ultra"); err != InvalidValue || ci.GetCurrentValue() != "high" {
+		t.Error("The invalid option shouldn't be set.")
+	}
+	if err := ci.SetCurrentValue(3); err != InvalidType {
+		t.Error("The invalid type shouldn't be set.")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Invalid default value should lead to panic.")
+			}
+		}()
+		NewEnumConfigItem("key", "label", "desc", "ultra", options)
+	}()
+}
+func TestSliderConfigItem(t *testing.T) {
+	conf := New()
+	conf.AddSliderConfig("volume", "Volume", "desc", 0.5, 0, 1, 0.1)
+	ci := conf["volume"]
+	min, max, step := ci.GetRange()
+	if ci.GetValueType() != ValueTypeSlider || min != 0 || max != 1 || step != 0.1 {
+		t.Error("Invalid slider item.")
+	}
+	if err := ci.SetCurrentValue(float32(0.7)); err != nil || ci.GetCurrentValue() != float32(0.7) {
+		t.Errorf("The value should be set. '%v'.", err)
+	}
+	if err := ci.SetCurrentValue(float32(1.5)); err != InvalidValue {
+		t.Error("The value out of the range shouldn't be set.")
+	}
+	for _, invalid := range [][4]float32{{0.5, 1, 0, 0.1}, {2, 0, 1, 0.1}, {0.5, 0, 1, -1}} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Invalid range '%v' should lead to panic.", invalid)
+				}
+			}()
+			NewSliderConfigItem("key", "label", "desc", invalid[0], invalid[1], invalid[2], invalid[3])
+		}()
+	}
+}
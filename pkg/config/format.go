@@ -0,0 +1,509 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// The supported formats of the config files.
+	FormatJSON = iota
+	FormatYAML
+	FormatTOML
+)
+
+var (
+	ErrorUnknownFormat = errors.New("Unknown config file format")
+	ErrorInvalidFile   = errors.New("Invalid config file")
+)
+
+// fileData is the content of a config file. The values are mapped to the config keys,
+// they are in the form that is returned by the fileValue function of the items. In the
+// files the values are stored under the `values` key (toml: section), next to the version
+// of the schema.
+type fileData struct {
+	version int
+	values  map[string]interface{}
+}
+
+// FormatOfPath returns the format of the config file from the extension of the path.
+// The `.json`, `.yaml`, `.yml` and `.toml` extensions are supported, otherwise it
+// returns ErrorUnknownFormat.
+func FormatOfPath(path string) (int, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	}
+	return 0, ErrorUnknownFormat
+}
+
+// writeFileData writes the data to the writer in the given format.
+func writeFileData(w io.Writer, format int, d *fileData) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, d)
+	case FormatYAML:
+		return writeYAML(w, d)
+	case FormatTOML:
+		return writeTOML(w, d)
+	}
+	return ErrorUnknownFormat
+}
+
+// readFileData reads the data from the reader in the given format. In case of
+// syntax error, it returns ErrorInvalidFile.
+func readFileData(r io.Reader, format int) (*fileData, error) {
+	switch format {
+	case FormatJSON:
+		return readJSON(r)
+	case FormatYAML:
+		return readLines(r, yamlSyntax)
+	case FormatTOML:
+		return readLines(r, tomlSyntax)
+	}
+	return nil, ErrorUnknownFormat
+}
+
+// sortedKeys returns the keys of the values in alphabetical order, so that the
+// written files are stable.
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for k, _ := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeJSON(w io.Writer, d *fileData) error {
+	content := struct {
+		Version int                    `json:"version"`
+		Values  map[string]interface{} `json:"values"`
+	}{d.version, d.values}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(content)
+}
+func readJSON(r io.Reader) (*fileData, error) {
+	var content struct {
+		Version int                    `json:"version"`
+		Values  map[string]interface{} `json:"values"`
+	}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&content); err != nil {
+		return nil, ErrorInvalidFile
+	}
+	d := &fileData{version: content.Version, values: make(map[string]interface{})}
+	for k, v := range content.Values {
+		d.values[k] = jsonValue(v)
+	}
+	return d, nil
+}
+
+// jsonValue converts the numbers of the decoded json value to int64 or float64.
+func jsonValue(v interface{}) interface{} {
+	switch v.(type) {
+	case json.Number:
+		if i, err := v.(json.Number).Int64(); err == nil {
+			return i
+		}
+		f, _ := v.(json.Number).Float64()
+		return f
+	case []interface{}:
+		list := v.([]interface{})
+		for i := 0; i < len(list); i++ {
+			list[i] = jsonValue(list[i])
+		}
+		return list
+	}
+	return v
+}
+
+func writeYAML(w io.Writer, d *fileData) error {
+	var b bytes.Buffer
+	b.WriteString("version: " + strconv.Itoa(d.version) + "\n")
+	if len(d.values) == 0 {
+		b.WriteString("values: {}\n")
+	} else {
+		b.WriteString("values:\n")
+	}
+	for _, k := range sortedKeys(d.values) {
+		b.WriteString("  " + formatKey(k, yamlSyntax) + ": " + formatValue(d.values[k]) + "\n")
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+func writeTOML(w io.Writer, d *fileData) error {
+	var b bytes.Buffer
+	b.WriteString("version = " + strconv.Itoa(d.version) + "\n\n[values]\n")
+	for _, k := range sortedKeys(d.values) {
+		b.WriteString(formatKey(k, tomlSyntax) + " = " + formatValue(d.values[k]) + "\n")
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// syntax describes the differences of the yaml and toml subsets that are used by the
+// config files. Both of them are line based, a line contains a key-value pair, a section
+// header or a comment. The strings, numbers, booleans and the inline lists of them are
+// supported as values.
+type syntax struct {
+	// the separator of the key and the value.
+	separator string
+	// the characters of the keys, that could be written without quotes.
+	bareKey func(rune) bool
+	// plainStrings means that the strings could be written without quotes.
+	plainStrings bool
+}
+
+var (
+	yamlSyntax = &syntax{
+		separator: ":",
+		bareKey: func(r rune) bool {
+			return r == '_' || r == '-' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+		},
+		plainStrings: true,
+	}
+	tomlSyntax = &syntax{
+		separator: "=",
+		bareKey: func(r rune) bool {
+			return r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+		},
+		plainStrings: false,
+	}
+)
+
+// formatKey returns the key without quotes, if it contains only bare key characters.
+func formatKey(key string, s *syntax) string {
+	if key == "" || strings.IndexFunc(key, func(r rune) bool { return !s.bareKey(r) }) > -1 {
+		return quoteString(key)
+	}
+	return key
+}
+
+// formatValue returns the string form of the value. The strings are always quoted, the
+// floats always contain a decimal point or an exponent, so that they are not read as integers.
+func formatValue(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return quoteString(v.(string))
+	case bool:
+		return strconv.FormatBool(v.(bool))
+	case int64:
+		return strconv.FormatInt(v.(int64), 10)
+	case float64:
+		s := strconv.FormatFloat(v.(float64), 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eIN") {
+			s = s + ".0"
+		}
+		return s
+	case []interface{}:
+		list := v.([]interface{})
+		items := make([]string, len(list))
+		for i := 0; i < len(list); i++ {
+			items[i] = formatValue(list[i])
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return "\"\""
+}
+
+// quoteString returns the double quoted form of the string. The escape sequences are
+// understood by both of the yaml and the toml.
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString("\\\"")
+			break
+		case '\\':
+			b.WriteString("\\\\")
+			break
+		case '\b':
+			b.WriteString("\\b")
+			break
+		case '\t':
+			b.WriteString("\\t")
+			break
+		case '\n':
+			b.WriteString("\\n")
+			break
+		case '\f':
+			b.WriteString("\\f")
+			break
+		case '\r':
+			b.WriteString("\\r")
+			break
+		default:
+			if r < 0x20 || r == 0x7f {
+				b.WriteString("\\u" + strconv.FormatInt(int64(0x10000+r), 16)[1:])
+			} else {
+				b.WriteRune(r)
+			}
+			break
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// unquoteString returns the content of the double or single quoted string. The single quoted
+// yaml strings escape the quote with two quotes, the single quoted toml strings are literal.
+func unquoteString(s string, sx *syntax) (string, error) {
+	if len(s) < 2 || s[0] != s[len(s)-1] {
+		return "", ErrorInvalidFile
+	}
+	content := s[1 : len(s)-1]
+	if s[0] == '\'' {
+		if sx.plainStrings {
+			return strings.Replace(content, "''", "'", -1), nil
+		}
+		return content, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(content) {
+			return "", ErrorInvalidFile
+		}
+		switch content[i] {
+		case '"', '\\', '/':
+			b.WriteByte(content[i])
+			break
+		case 'b':
+			b.WriteByte('\b')
+			break
+		case 't':
+			b.WriteByte('\t')
+			break
+		case 'n':
+			b.WriteByte('\n')
+			break
+		case 'f':
+			b.WriteByte('\f')
+			break
+		case 'r':
+			b.WriteByte('\r')
+			break
+		case 'u', 'U':
+			size := 4
+			if content[i] == 'U' {
+				size = 8
+			}
+			if i+1+size > len(content) {
+				return "", ErrorInvalidFile
+			}
+			code, err := strconv.ParseUint(content[i+1:i+1+size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return "", ErrorInvalidFile
+			}
+			b.WriteRune(rune(code))
+			i = i + size
+			break
+		default:
+			return "", ErrorInvalidFile
+		}
+	}
+	return b.String(), nil
+}
+
+// readLines reads the yaml or toml config file. The `version` key is read from the top
+// level, the values are read from the `values` map (yaml) or section (toml). The other
+// keys and sections are skipped.
+func readLines(r io.Reader, sx *syntax) (*fileData, error) {
+	d := &fileData{values: make(map[string]interface{})}
+	inValues := false
+	inSection := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(stripComment(raw))
+		if line == "" || line == "---" {
+			continue
+		}
+		if !sx.plainStrings && strings.HasPrefix(line, "[") {
+			// toml section header
+			inValues = line == "[values]"
+			inSection = true
+			continue
+		}
+		indented := raw[0] == ' ' || raw[0] == '\t'
+		if sx.plainStrings && !indented {
+			inValues = false
+		}
+		key, value, err := splitKeyValue(line, sx)
+		if err != nil {
+			return nil, err
+		}
+		if sx.plainStrings && !indented && key == "values" {
+			if value != "" && value != "{}" {
+				return nil, ErrorInvalidFile
+			}
+			inValues = true
+			continue
+		}
+		if !inValues {
+			topLevel := !inSection && (!sx.plainStrings || !indented)
+			if topLevel && key == "version" {
+				version, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, ErrorInvalidFile
+				}
+				d.version = version
+			}
+			continue
+		}
+		v, err := parseValue(value, sx)
+		if err != nil {
+			return nil, err
+		}
+		d.values[key] = v
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// stripComment removes the comment from the end of the line. The '#' characters of
+// the quoted strings are kept.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == 0 && c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+			break
+		case quote == '"' && c == '\\':
+			i++
+			break
+		case quote != 0 && c == quote:
+			quote = 0
+			break
+		}
+	}
+	return line
+}
+
+// indexOutsideQuotes returns the index of the first character of the line, that is outside
+// of the quoted strings and accepted by the match function. It returns -1, if it is not found.
+func indexOutsideQuotes(line string, match func(i int) bool) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+			break
+		case quote == '"' && c == '\\':
+			i++
+			break
+		case quote != 0 && c == quote:
+			quote = 0
+			break
+		case quote == 0 && match(i):
+			return i
+		}
+	}
+	return -1
+}
+
+// splitKeyValue returns the unquoted key and the value string of the line. The yaml
+// separator has to be followed by a space or the end of the line.
+func splitKeyValue(line string, sx *syntax) (string, string, error) {
+	index := indexOutsideQuotes(line, func(i int) bool {
+		if !strings.HasPrefix(line[i:], sx.separator) {
+			return false
+		}
+		return !sx.plainStrings || i+1 == len(line) || line[i+1] == ' ' || line[i+1] == '\t'
+	})
+	if index < 1 {
+		return "", "", ErrorInvalidFile
+	}
+	key := strings.TrimSpace(line[:index])
+	value := strings.TrimSpace(line[index+len(sx.separator):])
+	if key[0] == '"' || key[0] == '\'' {
+		var err error
+		if key, err = unquoteString(key, sx); err != nil {
+			return "", "", err
+		}
+	} else if strings.IndexFunc(key, func(r rune) bool { return !sx.bareKey(r) && r != ' ' }) > -1 {
+		return "", "", ErrorInvalidFile
+	}
+	return key, value, nil
+}
+
+// parseValue returns the value of the string. The integers are returned as int64, the
+// floats as float64, the inline lists as []interface{}. The yaml plain scalars, that
+// are not numbers or booleans, are returned as string.
+func parseValue(s string, sx *syntax) (interface{}, error) {
+	if s == "" {
+		return nil, ErrorInvalidFile
+	}
+	switch s[0] {
+	case '"', '\'':
+		return unquoteString(s, sx)
+	case '[':
+		if s[len(s)-1] != ']' {
+			return nil, ErrorInvalidFile
+		}
+		content := strings.TrimSpace(s[1 : len(s)-1])
+		list := []interface{}{}
+		for content != "" {
+			end := indexOutsideQuotes(content, func(i int) bool { return content[i] == ',' })
+			if end < 0 {
+				end = len(content)
+			}
+			item, err := parseValue(strings.TrimSpace(content[:end]), sx)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+			if end == len(content) {
+				break
+			}
+			content = strings.TrimSpace(content[end+1:])
+		}
+		return list, nil
+	}
+	if s == "true" || s == "false" {
+		return s == "true", nil
+	}
+	number := s
+	if !sx.plainStrings {
+		number = strings.Replace(number, "_", "", -1)
+	}
+	if i, err := strconv.ParseInt(strings.TrimPrefix(number, "+"), 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, nil
+	}
+	if sx.plainStrings {
+		return s, nil
+	}
+	return nil, ErrorInvalidFile
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatOfPath(t *testing.T) {
+	testData := []struct {
+		path   string
+		format int
+		err    error
+	}{
+		{"settings.json", FormatJSON, nil},
+		{"dir/settings.YAML", FormatYAML, nil},
+		{"settings.yml", FormatYAML, nil},
+		{"settings.toml", FormatTOML, nil},
+		{"settings.ini", 0, ErrorUnknownFormat},
+		{"settings", 0, ErrorUnknownFormat},
+	}
+	for _, tt := range testData {
+		format, err := FormatOfPath(tt.path)
+		if format != tt.format || err != tt.err {
+			t.Errorf("Invalid format of '%s'. Instead of '%d, %v', we have '%d, %v'.", tt.path, tt.format, tt.err, format, err)
+		}
+	}
+}
+func TestFileDataWriteRead(t *testing.T) {
+	values := map[string]interface{}{
+		"int":           int64(-3),
+		"float":         float64(2.5),
+		"text":          "a \"quoted\" # text\n\twith 'escapes' \\ and ünicode",
+		"bool":          true,
+		"vector":        []interface{}{float64(0.1), float64(-2.5), float64(3e-8)},
+		"dotted.key":    "value",
+		"key with: gap": "",
+	}
+	for _, format := range []int{FormatJSON, FormatYAML, FormatTOML} {
+		var b bytes.Buffer
+		if err := writeFileData(&b, format, &fileData{version: 3, values: values}); err != nil {
+			t.Fatalf("Write error in format '%d': %s", format, err.Error())
+		}
+		d, err := readFileData(&b, format)
+		if err != nil {
+			t.Fatalf("Read error in format '%d': %s", format, err.Error())
+		}
+		if d.version != 3 {
+			t.Errorf("Invalid version in format '%d'. Instead of '3', we have '%d'.", format, d.version)
+		}
+		if !reflect.DeepEqual(d.values, values) {
+			t.Errorf("Invalid values in format '%d'. Instead of '%#v', we have '%#v'.", format, values, d.values)
+		}
+	}
+}
+func TestReadYAML(t *testing.T) {
+	content := `# settings
+---
+version: 2
+other:
+  version: 5
+values:
+  plain: some text # comment
+  single: 'it''s'
+  int: 12
+  float: -1.5
+  list: [1, 2.5, "three"]
+  "quoted key": false
+after: 1
+`
+	d, err := readFileData(strings.NewReader(content), FormatYAML)
+	if err != nil {
+		t.Fatalf("Read error: %s", err.Error())
+	}
+	expected := map[string]interface{}{
+		"plain":      "some text",
+		"single":     "it's",
+		"int":        int64(12),
+		"float":      float64(-1.5),
+		"list":       []interface{}{int64(1), float64(2.5), "three"},
+		"quoted key": false,
+	}
+	if d.version != 2 {
+		t.Errorf("Invalid version. Instead of '2', we have '%d'.", d.version)
+	}
+	if !reflect.DeepEqual(d.values, expected) {
+		t.Errorf("Invalid values. Instead of '%#v', we have '%#v'.", expected, d.values)
+	}
+	if _, err := readFileData(strings.NewReader("values:\n  key\n"), FormatYAML); err != ErrorInvalidFile {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorInvalidFile, err)
+	}
+}
+func TestReadTOML(t *testing.T) {
+	content := `version = 4 # comment
+
+[other]
+version = 5
+
+[values]
+int = 1_000
+literal = 'C:\path'
+list = [1.0, 2.0, 3.0]
+`
+	d, err := readFileData(strings.NewReader(content), FormatTOML)
+	if err != nil {
+		t.Fatalf("Read error: %s", err.Error())
+	}
+	expected := map[string]interface{}{
+		"int":     int64(1000),
+		"literal": "C:\\path",
+		"list":    []interface{}{float64(1), float64(2), float64(3)},
+	}
+	if d.version != 4 {
+		t.Errorf("Invalid version. Instead of '4', we have '%d'.", d.version)
+	}
+	if !reflect.DeepEqual(d.values, expected) {
+		t.Errorf("Invalid values. Instead of '%#v', we have '%#v'.", expected, d.values)
+	}
+	if _, err := readFileData(strings.NewReader("[values]\nkey = plain\n"), FormatTOML); err != ErrorInvalidFile {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorInvalidFile, err)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+)
+
+var (
+	ErrorUnsupportedVersion = errors.New("Unsupported config file version")
+)
+
+// Migration updates the values of a config file from a version to the next one. The
+// values are mapped to the config keys, they are in the form of the config files:
+// int64, float64, string, bool or the list of them.
+type Migration func(values map[string]interface{}) error
+
+// Schema is the version of the config files. The files of the older versions are migrated
+// to the current version during the load. The missing values get the default values of
+// the config items, so that the new items don't need migration.
+type Schema struct {
+	version    int
+	migrations map[int]Migration
+}
+
+// NewSchema returns a schema with the given version. The versions start from 1.
+// In case of smaller version, it panics.
+func NewSchema(version int) *Schema {
+	if version < 1 {
+		panic("The schema version has to be positive.")
+	}
+	return &Schema{
+		version:    version,
+		migrations: make(map[int]Migration),
+	}
+}
+
+// GetVersion returns the current version of the schema.
+func (s *Schema) GetVersion() int {
+	return s.version
+}
+
+// AddMigration sets the migration from the given version to the next one. In case of
+// version that isn't older than the current version, it panics.
+func (s *Schema) AddMigration(from int, m Migration) {
+	if from < 1 || from >= s.version {
+		panic("The migration has to start from an older version.")
+	}
+	s.migrations[from] = m
+}
+
+// Migrate runs the migrations on the values from the given version to the current one.
+// The versions without migration keep the values. The files without version are handled
+// as version 1 files. If the given version is newer than the current one, it returns
+// ErrorUnsupportedVersion.
+func (s *Schema) Migrate(version int, values map[string]interface{}) error {
+	if version < 1 {
+		version = 1
+	}
+	if version > s.version {
+		return ErrorUnsupportedVersion
+	}
+	for v := version; v < s.version; v++ {
+		if m, ok := s.migrations[v]; ok {
+			if err := m(values); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RenameKey returns a migration that moves the value of the old key to the new key.
+func RenameKey(oldKey, newKey string) Migration {
+	return func(values map[string]interface{}) error {
+		if v, ok := values[oldKey]; ok {
+			values[newKey] = v
+			delete(values, oldKey)
+		}
+		return nil
+	}
+}
+
+// RemoveKey returns a migration that removes the value of the key, so that the item
+// gets its default value.
+func RemoveKey(key string) Migration {
+	return func(values map[string]interface{}) error {
+		delete(values, key)
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewSchema(t *testing.T) {
+	s := NewSchema(3)
+	if s.GetVersion() != 3 {
+		t.Errorf("Invalid version. Instead of '3', we have '%d'.", s.GetVersion())
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("The 0 version should panic.")
+			}
+		}()
+		NewSchema(0)
+	}()
+}
+func TestSchemaAddMigration(t *testing.T) {
+	s := NewSchema(2)
+	s.AddMigration(1, RemoveKey("key"))
+	for _, from := range []int{0, 2, 3} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("The migration from '%d' should panic.", from)
+				}
+			}()
+			s.AddMigration(from, RemoveKey("key"))
+		}()
+	}
+}
+func TestSchemaMigrate(t *testing.T) {
+	s := NewSchema(4)
+	s.AddMigration(1, RenameKey("old", "new"))
+	s.AddMigration(3, RemoveKey("removed"))
+	values := map[string]interface{}{"old": int64(1), "removed": true, "kept": "value"}
+	if err := s.Migrate(0, values); err != nil {
+		t.Errorf("Migration error: %s", err.Error())
+	}
+	expected := map[string]interface{}{"new": int64(1), "kept": "value"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Invalid values. Instead of '%#v', we have '%#v'.", expected, values)
+	}
+	values = map[string]interface{}{"old": int64(1), "removed": true}
+	if err := s.Migrate(2, values); err != nil {
+		t.Errorf("Migration error: %s", err.Error())
+	}
+	expected = map[string]interface{}{"old": int64(1)}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Invalid values. Instead of '%#v', we have '%#v'.", expected, values)
+	}
+	if err := s.Migrate(5, values); err != ErrorUnsupportedVersion {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorUnsupportedVersion, err)
+	}
+	migrationError := errors.New("migration error")
+	s.AddMigration(2, func(values map[string]interface{}) error { return migrationError })
+	if err := s.Migrate(1, values); err != migrationError {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", migrationError, err)
+	}
+}
@@ -0,0 +1,344 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrorUnknownKey = errors.New("Unknown config key")
+)
+
+// Write writes the current values of the config to the writer in the given format.
+// The file gets the version of the schema.
+func (c Config) Write(w io.Writer, format int, schema *Schema) error {
+	d := &fileData{version: schema.GetVersion(), values: make(map[string]interface{})}
+	for key, item := range c {
+		d.values[key] = item.fileValue()
+	}
+	return writeFileData(w, format, d)
+}
+
+// Read reads the values of the config from the reader in the given format. The values
+// are migrated from the version of the file to the version of the schema. The missing
+// items get their default values, the unknown keys are skipped. The values have to match
+// the value types of the items. In case of error, it returns the error with the key of
+// the invalid item, and the config is not updated.
+func (c Config) Read(r io.Reader, format int, schema *Schema) error {
+	d, err := readFileData(r, format)
+	if err != nil {
+		return err
+	}
+	if err := schema.Migrate(d.version, d.values); err != nil {
+		return err
+	}
+	values := make(map[string]interface{})
+	for key, item := range c {
+		raw, ok := d.values[key]
+		if !ok {
+			values[key] = item.GetDefaultValue()
+			continue
+		}
+		v, err := item.valueFromFile(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		values[key] = v
+	}
+	for key, v := range values {
+		c[key].currentValue = v
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of the config in alphabetical order.
+func (c Config) sortedKeys() []string {
+	keys := make([]string, 0, len(c))
+	for k, _ := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnvironmentVariable returns the name of the environment variable, that overrides the
+// value of the given config key. It is the upper case key with the prefix, the characters
+// that are not letters or digits are replaced with '_'. Eg. the 'window.width' key with
+// the 'GAME_' prefix is overridden by the 'GAME_WINDOW_WIDTH' variable.
+func EnvironmentVariable(prefix, key string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// Store connects the config to a file. It saves and loads the values, applies the overrides
+// of the environment variables and the command line arguments, and in watch mode it reloads
+// the values, when the file is changed.
+type Store struct {
+	config Config
+	path   string
+	format int
+	schema *Schema
+	// the overridden values. They are applied again after the reloads.
+	overrides map[string]interface{}
+	// the watch mode. The interval is in ms, the 0 interval means that the mode is off.
+	watchInterval   float64
+	sinceLastCheck  float64
+	modTime         time.Time
+	reloadCallbacks []func([]string)
+}
+
+// NewStore returns a store of the config with the given file. The format is based on the
+// extension of the file, in case of unknown extension, it panics. The default schema has
+// version 1 without migrations.
+func NewStore(c Config, path string) *Store {
+	format, err := FormatOfPath(path)
+	if err != nil {
+		panic("Unknown config file format.")
+	}
+	return &Store{
+		config:    c,
+		path:      path,
+		format:    format,
+		schema:    NewSchema(1),
+		overrides: make(map[string]interface{}),
+	}
+}
+
+// GetConfig returns the config of the store.
+func (s *Store) GetConfig() Config {
+	return s.config
+}
+
+// GetPath returns the path of the config file.
+func (s *Store) GetPath() string {
+	return s.path
+}
+
+// SetFormat sets the format of the config file. In case of unknown format, it panics.
+func (s *Store) SetFormat(format int) {
+	if format != FormatJSON && format != FormatYAML && format != FormatTOML {
+		panic("Unknown config file format.")
+	}
+	s.format = format
+}
+
+// GetFormat returns the format of the config file.
+func (s *Store) GetFormat() int {
+	return s.format
+}
+
+// SetSchema sets the schema of the config file.
+func (s *Store) SetSchema(schema *Schema) {
+	s.schema = schema
+}
+
+// GetSchema returns the schema of the config file.
+func (s *Store) GetSchema() *Schema {
+	return s.schema
+}
+
+// Save writes the current values to the config file. The file is written next to the
+// original one, then it is renamed, so that the file is not broken in case of error.
+func (s *Store) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+	tmpPath := s.path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err := s.config.Write(f, s.format, s.schema); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return err
+	}
+	// the own changes are not reloaded in watch mode.
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// Load reads the values from the config file, then it applies the overrides. If the file
+// doesn't exist, the values are not changed. In case of invalid file, it returns the error
+// and the values are not changed.
+func (s *Store) Load() error {
+	_, err := s.load()
+	return err
+}
+
+// load reads the config file and returns the keys of the changed values in alphabetical order.
+func (s *Store) load() ([]string, error) {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil {
+		s.modTime = info.ModTime()
+	}
+	before := make(map[string]interface{})
+	for key, item := range s.config {
+		before[key] = item.GetCurrentValue()
+	}
+	if err := s.config.Read(f, s.format, s.schema); err != nil {
+		return nil, err
+	}
+	for key, v := range s.overrides {
+		s.config[key].currentValue = v
+	}
+	var changed []string
+	for _, key := range s.config.sortedKeys() {
+		if s.config[key].GetCurrentValue() != before[key] {
+			changed = append(changed, key)
+		}
+	}
+	return changed, nil
+}
+
+// Override sets the value of the config key from its string representation. The value
+// is kept after the reloads of the file. If the key is unknown, it returns ErrorUnknownKey,
+// in case of invalid value, it returns the error of the ParseValue function.
+func (s *Store) Override(key, value string) error {
+	item, ok := s.config[key]
+	if !ok {
+		return fmt.Errorf("%s: %w", key, ErrorUnknownKey)
+	}
+	v, err := item.ParseValue(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	item.currentValue = v
+	s.overrides[key] = v
+	return nil
+}
+
+// GetOverrides returns the overridden config keys in alphabetical order.
+func (s *Store) GetOverrides() []string {
+	keys := make([]string, 0, len(s.overrides))
+	for k, _ := range s.overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ApplyEnvironment overrides the values of the config keys, that have environment
+// variable. The names of the variables are returned by the EnvironmentVariable function.
+func (s *Store) ApplyEnvironment(prefix string) error {
+	for _, key := range s.config.sortedKeys() {
+		if value, ok := os.LookupEnv(EnvironmentVariable(prefix, key)); ok {
+			if err := s.Override(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyArguments overrides the values of the config keys from the command line arguments.
+// The overrides are given in the `-set key=value` or `--set=key=value` forms. It returns
+// the other arguments, so that they could be parsed by the application.
+func (s *Store) ApplyArguments(args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		name := strings.TrimLeft(args[i], "-")
+		if args[i] == name {
+			rest = append(rest, args[i])
+			continue
+		}
+		var override string
+		if name == "set" {
+			if i+1 >= len(args) {
+				return nil, InvalidValue
+			}
+			i++
+			override = args[i]
+		} else if strings.HasPrefix(name, "set=") {
+			override = strings.TrimPrefix(name, "set=")
+		} else {
+			rest = append(rest, args[i])
+			continue
+		}
+		separator := strings.Index(override, "=")
+		if separator < 1 {
+			return nil, InvalidValue
+		}
+		if err := s.Override(override[:separator], override[separator+1:]); err != nil {
+			return nil, err
+		}
+	}
+	return rest, nil
+}
+
+// Watch turns on the watch mode. The modification time of the file is checked in the
+// given interval (ms) by the Update function. The 0 interval turns off the watch mode.
+func (s *Store) Watch(interval float64) {
+	s.watchInterval = interval
+	s.sinceLastCheck = 0
+}
+
+// IsWatching returns true, if the watch mode is on.
+func (s *Store) IsWatching() bool {
+	return s.watchInterval > 0
+}
+
+// OnReload registers a callback, that is called with the changed config keys, when the
+// values are reloaded in watch mode.
+func (s *Store) OnReload(callback func([]string)) {
+	s.reloadCallbacks = append(s.reloadCallbacks, callback)
+}
+
+// Update increases the time since the last check with dt. In watch mode, it reloads the
+// values, if the file is changed since the last save or load, and calls the reload callbacks,
+// if a value is changed. It returns the error of the reload.
+func (s *Store) Update(dt float64) error {
+	if !s.IsWatching() {
+		return nil
+	}
+	s.sinceLastCheck = s.sinceLastCheck + dt
+	if s.sinceLastCheck < s.watchInterval {
+		return nil
+	}
+	s.sinceLastCheck = 0
+	info, err := os.Stat(s.path)
+	if err != nil || info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+	// the broken file is reported once.
+	s.modTime = info.ModTime()
+	changed, err := s.load()
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		for _, callback := range s.reloadCallbacks {
+			callback(changed)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testStoreConfig() Config {
+	c := New()
+	c.AddConfig("int", "int", "", int(1), nil)
+	c.AddConfig("int64", "int64", "", int64(2), nil)
+	c.AddConfig("float", "float", "", float32(0.5), nil)
+	c.AddConfig("text", "text", "", "text", nil)
+	c.AddConfig("bool", "bool", "", false, nil)
+	c.AddConfig("vector", "vector", "", mgl32.Vec3{1, 2, 3}, nil)
+	c.AddConfig("color", "color", "", Color{1, 0, 0}, nil)
+	c.AddConfig("path", "path", "", FilePath("assets"), nil)
+	c.AddConfig("binding", "binding", "", KeyBinding("key:W"), nil)
+	c.AddEnumConfig("enum", "enum", "", "low", []string{"low", "high"})
+	c.AddSliderConfig("slider", "slider", "", 2, 0, 10, 1)
+	return c
+}
+func testStoreValues() map[string]interface{} {
+	return map[string]interface{}{
+		"int":     int(-7),
+		"int64":   int64(1) << 40,
+		"float":   float32(0.1),
+		"text":    "new text",
+		"bool":    true,
+		"vector":  mgl32.Vec3{0.1, -2, 3},
+		"color":   Color{0, 0.5, 1},
+		"path":    FilePath("/tmp"),
+		"binding": KeyBinding("key:ctrl+S"),
+		"enum":    "high",
+		"slider":  float32(7),
+	}
+}
+func testStoreDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("Temp dir error: %s", err.Error())
+	}
+	return dir
+}
+func TestConfigWriteRead(t *testing.T) {
+	for _, format := range []int{FormatJSON, FormatYAML, FormatTOML} {
+		c := testStoreConfig()
+		for key, v := range testStoreValues() {
+			if err := c.SetCurrentValue(key, v); err != nil {
+				t.Fatalf("Set error of '%s': %s", key, err.Error())
+			}
+		}
+		var b bytes.Buffer
+		if err := c.Write(&b, format, NewSchema(1)); err != nil {
+			t.Fatalf("Write error in format '%d': %s", format, err.Error())
+		}
+		loaded := testStoreConfig()
+		if err := loaded.Read(&b, format, NewSchema(1)); err != nil {
+			t.Fatalf("Read error in format '%d': %s", format, err.Error())
+		}
+		for key, v := range testStoreValues() {
+			if loaded[key].GetCurrentValue() != v {
+				t.Errorf("Invalid value of '%s' in format '%d'. Instead of '%v', we have '%v'.", key, format, v, loaded[key].GetCurrentValue())
+			}
+		}
+	}
+}
+func TestConfigReadTypeCheck(t *testing.T) {
+	testData := []struct {
+		content string
+		err     error
+	}{
+		{`{"values": {"int": 1.5}}`, InvalidType},
+		{`{"values": {"int64": "1"}}`, InvalidType},
+		{`{"values": {"float": true}}`, InvalidType},
+		{`{"values": {"text": 1}}`, InvalidType},
+		{`{"values": {"vector": [1, 2]}}`, InvalidType},
+		{`{"values": {"color": [1, 2, "3"]}}`, InvalidType},
+		{`{"values": {"enum": "middle"}}`, InvalidValue},
+		{`{"values": {"slider": 11}}`, InvalidValue},
+		{`{"values": {"positive": -1}}`, InvalidValue},
+		{`{"version": 2, "values": {}}`, ErrorUnsupportedVersion},
+		{`{"values": `, ErrorInvalidFile},
+	}
+	for _, tt := range testData {
+		c := testStoreConfig()
+		c.AddConfig("positive", "positive", "", int(1), func(i int) bool { return i > 0 })
+		c.SetCurrentValue("text", "edited")
+		err := c.Read(strings.NewReader(tt.content), FormatJSON, NewSchema(1))
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Invalid error of '%s'. Instead of '%v', we have '%v'.", tt.content, tt.err, err)
+		}
+		if c["text"].GetCurrentValue() != "edited" {
+			t.Errorf("The config shouldn't be updated in case of error '%v'.", err)
+		}
+	}
+}
+func TestConfigReadDefaultsAndMigration(t *testing.T) {
+	c := testStoreConfig()
+	c.SetCurrentValue("text", "edited")
+	schema := NewSchema(2)
+	schema.AddMigration(1, RenameKey("number", "int"))
+	if err := c.Read(strings.NewReader("[values]\nnumber = 5\nunknown = 1\n"), FormatTOML, schema); err != nil {
+		t.Fatalf("Read error: %s", err.Error())
+	}
+	if c["int"].GetCurrentValue() != 5 {
+		t.Errorf("The value should be migrated. Instead of '5', we have '%v'.", c["int"].GetCurrentValue())
+	}
+	if c["text"].GetCurrentValue() != "text" {
+		t.Errorf("The missing value should be the default. Instead of 'text', we have '%v'.", c["text"].GetCurrentValue())
+	}
+}
+func TestEnvironmentVariable(t *testing.T) {
+	if name := EnvironmentVariable("GAME_", "window.width-px"); name != "GAME_WINDOW_WIDTH_PX" {
+		t.Errorf("Invalid name. Instead of 'GAME_WINDOW_WIDTH_PX', we have '%s'.", name)
+	}
+}
+func TestNewStore(t *testing.T) {
+	s := NewStore(testStoreConfig(), "settings.yaml")
+	if s.GetFormat() != FormatYAML || s.GetPath() != "settings.yaml" || s.GetSchema().GetVersion() != 1 {
+		t.Error("Invalid store.")
+	}
+	s.SetFormat(FormatTOML)
+	if s.GetFormat() != FormatTOML {
+		t.Error("Invalid format.")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("The unknown extension should panic.")
+			}
+		}()
+		NewStore(testStoreConfig(), "settings.ini")
+	}()
+}
+func TestStoreSaveLoad(t *testing.T) {
+	dir := testStoreDir(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"settings.json", "settings.yaml", "settings.toml"} {
+		path := filepath.Join(dir, "sub", name)
+		s := NewStore(testStoreConfig(), path)
+		if err := s.Load(); err != nil {
+			t.Errorf("The missing file shouldn't be an error. '%s'", err.Error())
+		}
+		for key, v := range testStoreValues() {
+			s.GetConfig().SetCurrentValue(key, v)
+		}
+		if err := s.Save(); err != nil {
+			t.Fatalf("Save error: %s", err.Error())
+		}
+		if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+			t.Error("The temporary file should be removed.")
+		}
+		loaded := NewStore(testStoreConfig(), path)
+		if err := loaded.Load(); err != nil {
+			t.Fatalf("Load error: %s", err.Error())
+		}
+		for key, v := range testStoreValues() {
+			if loaded.GetConfig()[key].GetCurrentValue() != v {
+				t.Errorf("Invalid value of '%s' in '%s'. Instead of '%v', we have '%v'.", key, name, v, loaded.GetConfig()[key].GetCurrentValue())
+			}
+		}
+	}
+}
+func TestStoreOverrides(t *testing.T) {
+	s := NewStore(testStoreConfig(), "settings.json")
+	os.Setenv("TEST_CONFIG_INT", "42")
+	os.Setenv("TEST_CONFIG_VECTOR", "[0, 1, 0]")
+	defer os.Unsetenv("TEST_CONFIG_INT")
+	defer os.Unsetenv("TEST_CONFIG_VECTOR")
+	if err := s.ApplyEnvironment("TEST_CONFIG_"); err != nil {
+		t.Fatalf("Environment error: %s", err.Error())
+	}
+	if s.GetConfig()["int"].GetCurrentValue() != 42 || s.GetConfig()["vector"].GetCurrentValue() != (mgl32.Vec3{0, 1, 0}) {
+		t.Error("The environment variables should override the values.")
+	}
+	rest, err := s.ApplyArguments([]string{"-record", "--set", "bool=true", "-set=enum=high", "file"})
+	if err != nil {
+		t.Fatalf("Argument error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(rest, []string{"-record", "file"}) {
+		t.Errorf("Invalid rest arguments '%v'.", rest)
+	}
+	if s.GetConfig()["bool"].GetCurrentValue() != true || s.GetConfig()["enum"].GetCurrentValue() != "high" {
+		t.Error("The arguments should override the values.")
+	}
+	if !reflect.DeepEqual(s.GetOverrides(), []string{"bool", "enum", "int", "vector"}) {
+		t.Errorf("Invalid overrides '%v'.", s.GetOverrides())
+	}
+	if _, err := s.ApplyArguments([]string{"-set", "missing=1"}); !errors.Is(err, ErrorUnknownKey) {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorUnknownKey, err)
+	}
+	if _, err := s.ApplyArguments([]string{"-set", "int=a"}); !errors.Is(err, InvalidValue) {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", InvalidValue, err)
+	}
+	if _, err := s.ApplyArguments([]string{"-set"}); err != InvalidValue {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", InvalidValue, err)
+	}
+	s.GetConfig().AddConfig("unit", "unit", "", mgl32.Vec3{0, 0, 0}, func(f float32) bool { return f >= 0 && f <= 1 })
+	if err := s.Override("unit", "0, 2, 0"); !errors.Is(err, InvalidValue) {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", InvalidValue, err)
+	}
+	if err := s.Override("unit", "0, 1, 0"); err != nil || s.GetConfig()["unit"].GetCurrentValue() != (mgl32.Vec3{0, 1, 0}) {
+		t.Errorf("The valid value should be accepted. '%v'.", err)
+	}
+}
+func TestStoreWatch(t *testing.T) {
+	dir := testStoreDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "settings.yaml")
+	s := NewStore(testStoreConfig(), path)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save error: %s", err.Error())
+	}
+	if err := s.Override("int", "3"); err != nil {
+		t.Fatalf("Override error: %s", err.Error())
+	}
+	var reloaded []string
+	s.OnReload(func(keys []string) { reloaded = keys })
+	s.Watch(100)
+	if !s.IsWatching() {
+		t.Error("The store should be in watch mode.")
+	}
+	if err := s.Update(200); err != nil || reloaded != nil {
+		t.Error("The own save shouldn't be reloaded.")
+	}
+	content := "version: 1\nvalues:\n  int: 5\n  text: \"edited\"\n  bool: true\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Write error: %s", err.Error())
+	}
+	// the modification time has to be different from the time of the save.
+	future := time.Now().Add(time.Second)
+	os.Chtimes(path, future, future)
+	if err := s.Update(50); err != nil || reloaded != nil {
+		t.Error("The file shouldn't be checked before the interval.")
+	}
+	if err := s.Update(50); err != nil {
+		t.Fatalf("Reload error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(reloaded, []string{"bool", "text"}) {
+		t.Errorf("Invalid reloaded keys '%v'.", reloaded)
+	}
+	if s.GetConfig()["int"].GetCurrentValue() != 3 {
+		t.Error("The override should be kept after the reload.")
+	}
+	future = future.Add(time.Second)
+	ioutil.WriteFile(path, []byte("values:\n  int: text\n"), 0644)
+	os.Chtimes(path, future, future)
+	if err := s.Update(100); !errors.Is(err, InvalidType) {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", InvalidType, err)
+	}
+	if err := s.Update(100); err != nil {
+		t.Error("The broken file should be reported once.")
+	}
+	s.Watch(0)
+	if s.IsWatching() {
+		t.Error("The watch mode should be turned off.")
+	}
+}
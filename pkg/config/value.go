@@ -0,0 +1,244 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// fileValue returns the current value of the item in the form of the config files.
+// The integers are int64, the floats are float64, the vectors and the colors are
+// lists of 3 float64 values, the other values are string or bool.
+func (ci *ConfigItem) fileValue() interface{} {
+	switch ci.currentValue.(type) {
+	case int:
+		return int64(ci.currentValue.(int))
+	case int64:
+		return ci.currentValue.(int64)
+	case float32:
+		return fileFloat(ci.currentValue.(float32))
+	case bool:
+		return ci.currentValue.(bool)
+	case mgl32.Vec3:
+		return fileVector(ci.currentValue.(mgl32.Vec3))
+	case Color:
+		return fileVector(mgl32.Vec3(ci.currentValue.(Color)))
+	case FilePath:
+		return string(ci.currentValue.(FilePath))
+	case KeyBinding:
+		return string(ci.currentValue.(KeyBinding))
+	}
+	return ci.currentValue
+}
+
+// fileFloat returns the float64 form of the float32 value with the shortest decimal
+// representation, so that the 0.1 is stored as 0.1 instead of 0.10000000149011612.
+func fileFloat(v float32) float64 {
+	result, _ := strconv.ParseFloat(strconv.FormatFloat(float64(v), 'f', -1, 32), 64)
+	return result
+}
+func fileVector(v mgl32.Vec3) []interface{} {
+	return []interface{}{fileFloat(v.X()), fileFloat(v.Y()), fileFloat(v.Z())}
+}
+
+// valueFromFile returns the value of the item from the form of the config files.
+// If the value doesn't match the value type of the item, it returns InvalidType, if
+// it isn't accepted by the item (eg. the value of a slider is out of range or the
+// validator function of the item returns false), it returns InvalidValue.
+func (ci *ConfigItem) valueFromFile(raw interface{}) (interface{}, error) {
+	var v interface{}
+	switch ci.valueType {
+	case ValueTypeInt:
+		i, ok := raw.(int64)
+		if !ok || int64(int(i)) != i {
+			return nil, InvalidType
+		}
+		v = int(i)
+		break
+	case ValueTypeInt64:
+		i, ok := raw.(int64)
+		if !ok {
+			return nil, InvalidType
+		}
+		v = i
+		break
+	case ValueTypeFloat, ValueTypeSlider:
+		f, ok := fileNumber(raw)
+		if !ok {
+			return nil, InvalidType
+		}
+		v = float32(f)
+		break
+	case ValueTypeText, ValueTypeEnum:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, InvalidType
+		}
+		v = s
+		break
+	case ValueTypeFilePath:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, InvalidType
+		}
+		v = FilePath(s)
+		break
+	case ValueTypeKeyBinding:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, InvalidType
+		}
+		v = KeyBinding(s)
+		break
+	case ValueTypeBool:
+		b, ok := raw.(bool)
+		if !ok {
+			return nil, InvalidType
+		}
+		v = b
+		break
+	case ValueTypeVector, ValueTypeColor:
+		list, ok := raw.([]interface{})
+		if !ok || len(list) != 3 {
+			return nil, InvalidType
+		}
+		var vector mgl32.Vec3
+		for i := 0; i < 3; i++ {
+			f, ok := fileNumber(list[i])
+			if !ok {
+				return nil, InvalidType
+			}
+			vector[i] = float32(f)
+		}
+		v = vector
+		if ci.valueType == ValueTypeColor {
+			v = Color(vector)
+		}
+		break
+	}
+	if err := ci.checkValue(v); err != nil {
+		return nil, err
+	}
+	if !ci.isValid(v) {
+		return nil, InvalidValue
+	}
+	return v, nil
+}
+
+// isValid returns false, if the validator function of the item doesn't accept the value.
+// The validator is a function with one parameter and bool return value, like the validators
+// of the form items. The components of the vectors are validated one by one. The validators
+// with different signature are not called.
+func (ci *ConfigItem) isValid(v interface{}) bool {
+	if ci.validatorFunction == nil {
+		return true
+	}
+	validator := reflect.ValueOf(ci.validatorFunction)
+	if validator.Kind() != reflect.Func || validator.IsNil() {
+		return true
+	}
+	validatorType := validator.Type()
+	if validatorType.NumIn() != 1 || validatorType.NumOut() != 1 || validatorType.Out(0).Kind() != reflect.Bool {
+		return true
+	}
+	values := []interface{}{v}
+	if vector, ok := v.(mgl32.Vec3); ok {
+		values = []interface{}{vector.X(), vector.Y(), vector.Z()}
+	}
+	for _, value := range values {
+		input := reflect.ValueOf(value)
+		if input.Kind() != validatorType.In(0).Kind() {
+			return true
+		}
+		if !validator.Call([]reflect.Value{input.Convert(validatorType.In(0))})[0].Bool() {
+			return false
+		}
+	}
+	return true
+}
+
+// fileNumber returns the float64 form of the int64 or float64 value.
+func fileNumber(raw interface{}) (float64, bool) {
+	switch raw.(type) {
+	case int64:
+		return float64(raw.(int64)), true
+	case float64:
+		return raw.(float64), true
+	}
+	return 0, false
+}
+
+// ParseValue returns the value of the item from its string representation. It is
+// used for the overrides of the environment variables and the command line arguments.
+// The vectors and the colors are given as comma separated lists, like '1, 0.5, 0'.
+// In case of invalid string or if the validator function of the item doesn't accept
+// the value, it returns InvalidValue.
+func (ci *ConfigItem) ParseValue(s string) (interface{}, error) {
+	var v interface{}
+	var err error
+	switch ci.valueType {
+	case ValueTypeInt:
+		v, err = strconv.Atoi(strings.TrimSpace(s))
+		break
+	case ValueTypeInt64:
+		v, err = strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		break
+	case ValueTypeFloat, ValueTypeSlider:
+		var f float64
+		f, err = strconv.ParseFloat(strings.TrimSpace(s), 32)
+		v = float32(f)
+		break
+	case ValueTypeBool:
+		v, err = strconv.ParseBool(strings.TrimSpace(s))
+		break
+	case ValueTypeText, ValueTypeEnum:
+		v = s
+		break
+	case ValueTypeFilePath:
+		v = FilePath(s)
+		break
+	case ValueTypeKeyBinding:
+		v = KeyBinding(s)
+		break
+	case ValueTypeVector, ValueTypeColor:
+		var vector mgl32.Vec3
+		vector, err = parseVector(s)
+		v = vector
+		if ci.valueType == ValueTypeColor {
+			v = Color(vector)
+		}
+		break
+	}
+	if err != nil {
+		return nil, InvalidValue
+	}
+	if err := ci.checkValue(v); err != nil {
+		return nil, err
+	}
+	if !ci.isValid(v) {
+		return nil, InvalidValue
+	}
+	return v, nil
+}
+
+// parseVector parses the comma separated components of a vector. The list could be
+// in brackets, like '[1, 0.5, 0]'.
+func parseVector(s string) (mgl32.Vec3, error) {
+	var v mgl32.Vec3
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	components := strings.Split(s, ",")
+	if len(components) != 3 {
+		return v, InvalidValue
+	}
+	for i := 0; i < 3; i++ {
+		f, err := strconv.ParseFloat(strings.TrimSpace(components[i]), 32)
+		if err != nil {
+			return v, err
+		}
+		v[i] = float32(f)
+	}
+	return v, nil
+}
@@ -12,6 +12,9 @@ import (
 const (
 	GL_MAJOR_VERSION = 4
 	GL_MINOR_VERSION = 1
+	// The compute shaders are the part of the 4.3 core profile.
+	GL_COMPUTE_MAJOR_VERSION = 4
+	GL_COMPUTE_MINOR_VERSION = 3
 )
 
 const (
@@ -37,6 +40,7 @@ const (
 	TRIANGLES                   = gl.TRIANGLES
 	TEXTURE_BORDER_COLOR        = gl.TEXTURE_BORDER_COLOR
 	CLAMP_TO_EDGE               = gl.CLAMP_TO_EDGE
+	REPEAT                      = gl.REPEAT
 	LINEAR                      = gl.LINEAR
 	COLOR_BUFFER_BIT            = gl.COLOR_BUFFER_BIT
 	DEPTH_BUFFER_BIT            = gl.DEPTH_BUFFER_BIT
@@ -49,6 +53,25 @@ const (
 	BLEND                       = gl.BLEND
 	SRC_APLHA                   = gl.SRC_ALPHA
 	ONE_MINUS_SRC_ALPHA         = gl.ONE_MINUS_SRC_ALPHA
+	GEOMETRY_SHADER             = gl.GEOMETRY_SHADER
+	TESS_CONTROL_SHADER         = gl.TESS_CONTROL_SHADER
+	TESS_EVALUATION_SHADER      = gl.TESS_EVALUATION_SHADER
+	COMPUTE_SHADER              = gl.COMPUTE_SHADER
+	PATCHES                     = gl.PATCHES
+	PATCH_VERTICES              = gl.PATCH_VERTICES
+	SHADER_STORAGE_BUFFER       = gl.SHADER_STORAGE_BUFFER
+	SHADER_STORAGE_BARRIER_BIT  = gl.SHADER_STORAGE_BARRIER_BIT
+	ONE                         = gl.ONE
+	NEAREST                     = gl.NEAREST
+	RED                         = gl.RED
+	R32F                        = gl.R32F
+	FRAMEBUFFER                 = gl.FRAMEBUFFER
+	FRAMEBUFFER_COMPLETE        = gl.FRAMEBUFFER_COMPLETE
+	COLOR_ATTACHMENT0           = gl.COLOR_ATTACHMENT0
+	DEPTH_ATTACHMENT            = gl.DEPTH_ATTACHMENT
+	RENDERBUFFER                = gl.RENDERBUFFER
+	DEPTH_COMPONENT24           = gl.DEPTH_COMPONENT24
+	SCISSOR_TEST                = gl.SCISSOR_TEST
 )
 
 type Wrapper struct {
@@ -68,6 +91,16 @@ func (w Wrapper) GenBuffers() uint32 {
 	return vertexBufferObject
 }
 
+// Wrapper for gl.DeleteVertexArrays function.
+func (w Wrapper) DeleteVertexArrays(vao uint32) {
+	gl.DeleteVertexArrays(1, &vao)
+}
+
+// Wrapper for gl.DeleteBuffers function.
+func (w Wrapper) DeleteBuffers(buffer uint32) {
+	gl.DeleteBuffers(1, &buffer)
+}
+
 // Wrapper for gl.BindVertexArray function.
 func (w Wrapper) BindVertexArray(vao uint32) {
 	gl.BindVertexArray(vao)
@@ -85,6 +118,12 @@ func (w Wrapper) ArrayBufferData(bufferData []float32) {
 	gl.BufferData(gl.ARRAY_BUFFER, 4*len(bufferData), gl.Ptr(bufferData), gl.STATIC_DRAW)
 }
 
+// Wrapper for gl.BufferSubData function but for ARRAY_BUFFER.
+// The offset is the number of the floats before the updated range.
+func (w Wrapper) ArrayBufferSubData(offset int, bufferData []float32) {
+	gl.BufferSubData(gl.ARRAY_BUFFER, 4*offset, 4*len(bufferData), gl.Ptr(bufferData))
+}
+
 // Wrapper for gl.BufferData function, but for ELEMENT_ARRAY_BUFFER.
 func (w Wrapper) ElementBufferData(bufferData []uint32) {
 	// a 32-bit uint has 4 bytes, so we are saying the size of the buffer,
@@ -285,3 +324,152 @@ func (w Wrapper) Viewport(x int32, y int32, width int32, height int32) {
 func (w Wrapper) BlendFunc(sfactor uint32, dfactor uint32) {
 	gl.BlendFunc(sfactor, dfactor)
 }
+
+// Wrapper for gl.DepthMask function.
+func (w Wrapper) DepthMask(flag bool) {
+	gl.DepthMask(flag)
+}
+
+// Wrapper for gl.PatchParameteri function.
+func (w Wrapper) PatchParameteri(pname uint32, value int32) {
+	gl.PatchParameteri(pname, value)
+}
+
+// Wrapper for gl.DrawElements function, but for PATCHES. It is used
+// with the tessellation shader stages.
+func (w Wrapper) DrawPatchElements(count int32) {
+	gl.DrawElements(gl.PATCHES, count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+}
+
+// Wrapper for gl.DispatchCompute function.
+func (w Wrapper) DispatchCompute(numGroupsX, numGroupsY, numGroupsZ uint32) {
+	gl.DispatchCompute(numGroupsX, numGroupsY, numGroupsZ)
+}
+
+// ComputeShaderSupported returns true, if the current context supports the compute shaders.
+// They are available from gl 4.3, the older contexts need the ARB_compute_shader extension.
+func (w Wrapper) ComputeShaderSupported() bool {
+	var major, minor, extensions int32
+	gl.GetIntegerv(gl.MAJOR_VERSION, &major)
+	gl.GetIntegerv(gl.MINOR_VERSION, &minor)
+	if major > GL_COMPUTE_MAJOR_VERSION || major == GL_COMPUTE_MAJOR_VERSION && minor >= GL_COMPUTE_MINOR_VERSION {
+		return true
+	}
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &extensions)
+	for i := int32(0); i < extensions; i++ {
+		if gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i))) == "GL_ARB_compute_shader" {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrapper for gl.MemoryBarrier function.
+func (w Wrapper) MemoryBarrier(barriers uint32) {
+	gl.MemoryBarrier(barriers)
+}
+
+// Wrapper for gl.BindBufferBase function.
+func (w Wrapper) BindBufferBase(target uint32, index uint32, buffer uint32) {
+	gl.BindBufferBase(target, index, buffer)
+}
+
+// Wrapper for gl.BufferData function, but for SHADER_STORAGE_BUFFER.
+// The buffer is written by the compute shaders, so that it uses the DYNAMIC_COPY usage.
+func (w Wrapper) StorageBufferData(bufferData []float32) {
+	// a 32-bit float has 4 bytes, so we are saying the size of the buffer,
+	// in bytes, is 4 times the number of points
+	gl.BufferData(gl.SHADER_STORAGE_BUFFER, 4*len(bufferData), gl.Ptr(bufferData), gl.DYNAMIC_COPY)
+}
+
+// Wrapper for gl.GetBufferSubData function, but for SHADER_STORAGE_BUFFER.
+// It reads len(bufferData) floats from the beginning of the bound buffer.
+func (w Wrapper) GetStorageBufferData(bufferData []float32) {
+	gl.GetBufferSubData(gl.SHADER_STORAGE_BUFFER, 0, 4*len(bufferData), gl.Ptr(bufferData))
+}
+
+// Wrapper for gl.TexSubImage2D function.
+func (w Wrapper) TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	gl.TexSubImage2D(target, level, xoffset, yoffset, width, height, format, xtype, pixels)
+}
+
+// Wrapper for gl.DeleteTextures function.
+func (w Wrapper) DeleteTextures(texture uint32) {
+	gl.DeleteTextures(1, &texture)
+}
+
+// Wrapper for gl.GenFramebuffers function.
+func (w Wrapper) GenFramebuffers() uint32 {
+	var framebuffer uint32
+	gl.GenFramebuffers(1, &framebuffer)
+	return framebuffer
+}
+
+// Wrapper for gl.BindFramebuffer function.
+func (w Wrapper) BindFramebuffer(target uint32, framebuffer uint32) {
+	gl.BindFramebuffer(target, framebuffer)
+}
+
+// Wrapper for gl.FramebufferTexture2D function.
+func (w Wrapper) FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+	gl.FramebufferTexture2D(target, attachment, textarget, texture, level)
+}
+
+// Wrapper for gl.CheckFramebufferStatus function.
+func (w Wrapper) CheckFramebufferStatus(target uint32) uint32 {
+	return gl.CheckFramebufferStatus(target)
+}
+
+// Wrapper for gl.DeleteFramebuffers function.
+func (w Wrapper) DeleteFramebuffers(framebuffer uint32) {
+	gl.DeleteFramebuffers(1, &framebuffer)
+}
+
+// Wrapper for gl.GenRenderbuffers function.
+func (w Wrapper) GenRenderbuffers() uint32 {
+	var renderbuffer uint32
+	gl.GenRenderbuffers(1, &renderbuffer)
+	return renderbuffer
+}
+
+// Wrapper for gl.BindRenderbuffer function.
+func (w Wrapper) BindRenderbuffer(target uint32, renderbuffer uint32) {
+	gl.BindRenderbuffer(target, renderbuffer)
+}
+
+// Wrapper for gl.RenderbufferStorage function.
+func (w Wrapper) RenderbufferStorage(target uint32, internalformat uint32, width int32, height int32) {
+	gl.RenderbufferStorage(target, internalformat, width, height)
+}
+
+// Wrapper for gl.FramebufferRenderbuffer function.
+func (w Wrapper) FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32) {
+	gl.FramebufferRenderbuffer(target, attachment, renderbuffertarget, renderbuffer)
+}
+
+// Wrapper for gl.DeleteRenderbuffers function.
+func (w Wrapper) DeleteRenderbuffers(renderbuffer uint32) {
+	gl.DeleteRenderbuffers(1, &renderbuffer)
+}
+
+// Wrapper for gl.VertexAttribDivisor function.
+func (w Wrapper) VertexAttribDivisor(index uint32, divisor uint32) {
+	gl.VertexAttribDivisor(index, divisor)
+}
+
+// Wrapper for gl.DrawElementsInstanced function. It draws the triangles
+// of the bound vertex array instanceCount times.
+func (w Wrapper) DrawTriangleElementsInstanced(count int32, instanceCount int32) {
+	gl.DrawElementsInstanced(gl.TRIANGLES, count, gl.UNSIGNED_INT, gl.PtrOffset(0), instanceCount)
+}
+
+// Wrapper for gl.Disable function.
+func (w Wrapper) Disable(cap uint32) {
+	gl.Disable(cap)
+}
+
+// Wrapper for gl.Scissor function. The coordinates are in window pixels,
+// the origin is the bottom left corner.
+func (w Wrapper) Scissor(x int32, y int32, width int32, height int32) {
+	gl.Scissor(x, y, width, height)
+}
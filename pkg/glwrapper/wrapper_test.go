@@ -74,6 +74,22 @@ func TestGenBuffers(t *testing.T) {
 		w.GenBuffers()
 	}()
 }
+func TestDeleteBuffers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		w.DeleteBuffers(w.GenBuffers())
+		w.DeleteVertexArrays(w.GenVertexArrays())
+	}()
+}
 func TestBindVertexArray(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping it in short mode")
@@ -124,6 +140,25 @@ func TestArrayBufferData(t *testing.T) {
 		w.ArrayBufferData(data)
 	}()
 }
+func TestArrayBufferSubData(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		id := w.GenBuffers()
+		w.BindBuffer(ARRAY_BUFFER, id)
+		data := []float32{0.0, 1.0, 2.0}
+		w.ArrayBufferData(data)
+		w.ArrayBufferSubData(1, []float32{3.0, 4.0})
+	}()
+}
 func TestElementBufferData(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping it in short mode")
@@ -958,3 +993,281 @@ func TestViewport(t *testing.T) {
 		w.Viewport(0, 0, 800, 800)
 	}()
 }
+func TestPatchParameteri(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Error("It shouldn't fail with setup.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		w.PatchParameteri(PATCH_VERTICES, 3)
+	}()
+}
+func TestComputeShaderSupported(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Error("It shouldn't fail with setup.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		w.ComputeShaderSupported()
+	}()
+}
+func TestStorageBufferData(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Error("It shouldn't fail with setup.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		id := w.GenBuffers()
+		w.BindBuffer(SHADER_STORAGE_BUFFER, id)
+		data := []float32{0.0, 1.0, 2.0}
+		w.StorageBufferData(data)
+		result := make([]float32, len(data))
+		w.GetStorageBufferData(result)
+	}()
+}
+func TestTexSubImage2D(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		var id uint32
+		w.GenTextures(1, &id)
+		w.BindTexture(TEXTURE_2D, id)
+		data := []float32{0, 1, 2, 3}
+		w.TexImage2D(TEXTURE_2D, 0, R32F, 2, 2, 0, RED, FLOAT, w.Ptr(data))
+		w.TexSubImage2D(TEXTURE_2D, 0, 1, 1, 1, 1, RED, FLOAT, w.Ptr([]float32{4}))
+	}()
+}
+func TestDeleteTextures(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		var id uint32
+		w.GenTextures(1, &id)
+		w.DeleteTextures(id)
+	}()
+}
+func TestGenFramebuffers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		w.GenFramebuffers()
+	}()
+}
+func TestBindFramebuffer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		id := w.GenFramebuffers()
+		w.BindFramebuffer(FRAMEBUFFER, id)
+		w.BindFramebuffer(FRAMEBUFFER, 0)
+	}()
+}
+func TestFramebufferTexture2D(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		id := w.GenFramebuffers()
+		w.BindFramebuffer(FRAMEBUFFER, id)
+		var tex uint32
+		w.GenTextures(1, &tex)
+		w.BindTexture(TEXTURE_2D, tex)
+		w.TexImage2D(TEXTURE_2D, 0, RGBA, 2, 2, 0, RGBA, UNSIGNED_BYTE, nil)
+		w.FramebufferTexture2D(FRAMEBUFFER, COLOR_ATTACHMENT0, TEXTURE_2D, tex, 0)
+		if status := w.CheckFramebufferStatus(FRAMEBUFFER); status != FRAMEBUFFER_COMPLETE {
+			t.Errorf("Invalid framebuffer status '%d'.", status)
+		}
+	}()
+}
+func TestDeleteFramebuffers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		id := w.GenFramebuffers()
+		w.DeleteFramebuffers(id)
+	}()
+}
+func TestGenRenderbuffers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		w.GenRenderbuffers()
+	}()
+}
+func TestRenderbufferStorage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		id := w.GenFramebuffers()
+		w.BindFramebuffer(FRAMEBUFFER, id)
+		rbo := w.GenRenderbuffers()
+		w.BindRenderbuffer(RENDERBUFFER, rbo)
+		w.RenderbufferStorage(RENDERBUFFER, DEPTH_COMPONENT24, 2, 2)
+		w.FramebufferRenderbuffer(FRAMEBUFFER, DEPTH_ATTACHMENT, RENDERBUFFER, rbo)
+		w.DeleteRenderbuffers(rbo)
+	}()
+}
+func TestVertexAttribDivisor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("It shouldn't fail.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		vao := w.GenVertexArrays()
+		w.BindVertexArray(vao)
+		id := w.GenBuffers()
+		w.BindBuffer(ARRAY_BUFFER, id)
+		w.ArrayBufferData([]float32{0.0, 1.0, 2.0})
+		w.VertexAttribPointer(3, 3, FLOAT, false, 4*3, w.PtrOffset(0))
+		w.VertexAttribDivisor(3, 1)
+	}()
+}
+func TestDrawTriangleElementsInstanced(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Error("It shouldn't fail with setup.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		vao := w.GenVertexArrays()
+		vbo := w.GenBuffers()
+		ebo := w.GenBuffers()
+		w.BindVertexArray(vao)
+		w.BindBuffer(ARRAY_BUFFER, vbo)
+		w.ArrayBufferData([]float32{0, 0, 0, 0, 1, 0, 1, 1, 0})
+		w.BindBuffer(ELEMENT_ARRAY_BUFFER, ebo)
+		w.ElementBufferData([]uint32{0, 1, 2})
+		w.VertexAttribPointer(0, 3, FLOAT, false, 4*3, w.PtrOffset(0))
+		w.DrawTriangleElementsInstanced(int32(3), int32(2))
+	}()
+}
+func TestDisable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Error("It shouldn't fail with setup.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		w.Enable(SCISSOR_TEST)
+		w.Disable(SCISSOR_TEST)
+	}()
+}
+func TestScissor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Error("It shouldn't fail with setup.")
+			}
+		}()
+		setup()
+		defer testhelper.GlfwTerminate()
+		w.Enable(SCISSOR_TEST)
+		w.Scissor(0, 0, 400, 400)
+	}()
+}
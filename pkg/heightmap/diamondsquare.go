@@ -0,0 +1,74 @@
+package heightmap
+
+import (
+	"math/rand"
+)
+
+// DiamondSquare is the midpoint displacement fractal generator. The roughness is the ratio of
+// the random displacement between the following steps, with the lower values the surface is smoother.
+// The map is generated on a (2^n+1) square grid that covers the height map, then the values
+// are scaled to the [MinHeight, MaxHeight] range.
+type DiamondSquare struct {
+	Seed      int64
+	Roughness float32
+	MinHeight float32
+	MaxHeight float32
+}
+
+// NewDiamondSquare returns a diamond-square generator.
+func NewDiamondSquare(seed int64, roughness, minH, maxH float32) *DiamondSquare {
+	return &DiamondSquare{
+		Seed:      seed,
+		Roughness: roughness,
+		MinHeight: minH,
+		MaxHeight: maxH,
+	}
+}
+
+// Generate sets the values of the height map.
+func (d *DiamondSquare) Generate(heightMap [][]float32) {
+	if len(heightMap) == 0 || len(heightMap[0]) == 0 {
+		return
+	}
+	size := 1
+	for size+1 < len(heightMap) || size+1 < len(heightMap[0]) {
+		size *= 2
+	}
+	r := rand.New(rand.NewSource(d.Seed))
+	random := func(amplitude float32) float32 {
+		return (r.Float32()*2 - 1) * amplitude
+	}
+	grid := New(size, size)
+	grid[0][0], grid[0][size], grid[size][0], grid[size][size] = random(1), random(1), random(1), random(1)
+	amplitude := float32(1.0)
+	for step := size; step > 1; step /= 2 {
+		half := step / 2
+		// diamond step: the centers of the squares.
+		for z := half; z < size; z += step {
+			for x := half; x < size; x += step {
+				average := (grid[z-half][x-half] + grid[z-half][x+half] + grid[z+half][x-half] + grid[z+half][x+half]) / 4
+				grid[z][x] = average + random(amplitude)
+			}
+		}
+		// square step: the midpoints of the edges. On the border there are only 3 neighbours.
+		for z := 0; z <= size; z += half {
+			for x := (z + half) % step; x <= size; x += step {
+				sum, count := float32(0), float32(0)
+				for _, n := range [4][2]int{{x - half, z}, {x + half, z}, {x, z - half}, {x, z + half}} {
+					if n[0] >= 0 && n[0] <= size && n[1] >= 0 && n[1] <= size {
+						sum += grid[n[1]][n[0]]
+						count++
+					}
+				}
+				grid[z][x] = sum/count + random(amplitude)
+			}
+		}
+		amplitude *= d.Roughness
+	}
+	for z := 0; z < len(heightMap); z++ {
+		for x := 0; x < len(heightMap[z]); x++ {
+			heightMap[z][x] = grid[z][x]
+		}
+	}
+	Normalize(heightMap, d.MinHeight, d.MaxHeight)
+}
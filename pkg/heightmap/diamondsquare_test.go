@@ -0,0 +1,26 @@
+package heightmap
+
+import (
+	"testing"
+)
+
+func TestDiamondSquare(t *testing.T) {
+	d := NewDiamondSquare(3, 0.5, -2, 4)
+	hm := New(20, 10)
+	d.Generate(hm)
+	low, high := bounds(hm)
+	if low != -2 || high != 4 {
+		t.Errorf("The heights should fill the range. We have '%f' - '%f'.", low, high)
+	}
+	other := New(20, 10)
+	d.Generate(other)
+	for z := 0; z < len(hm); z++ {
+		for x := 0; x < len(hm[z]); x++ {
+			if hm[z][x] != other[z][x] {
+				t.Fatal("The same seed should give the same map.")
+			}
+		}
+	}
+	// it doesn't panic with empty map.
+	d.Generate([][]float32{})
+}
@@ -0,0 +1,165 @@
+package heightmap
+
+import (
+	"math"
+	"math/rand"
+)
+
+// HydraulicErosion is a droplet based erosion pass. The droplets are dropped to random positions,
+// they flow downhill, they erode the steep parts and deposit the sediment on the flat parts.
+// It makes river beds and smooth valleys.
+type HydraulicErosion struct {
+	Seed     int64
+	Droplets int
+	// the max number of steps of a droplet.
+	MaxSteps int
+	// how much the droplet keeps its direction. With 0 it always follows the slope.
+	Inertia float32
+	// the sediment capacity multiplier.
+	Capacity float32
+	// the min slope that is used for the capacity calculation, so that the flat parts also erode.
+	MinSlope    float32
+	Erosion     float32
+	Deposition  float32
+	Evaporation float32
+	Gravity     float32
+}
+
+// NewHydraulicErosion returns a hydraulic erosion pass with default parameters.
+func NewHydraulicErosion(seed int64, droplets int) *HydraulicErosion {
+	return &HydraulicErosion{
+		Seed:        seed,
+		Droplets:    droplets,
+		MaxSteps:    30,
+		Inertia:     0.05,
+		Capacity:    4,
+		MinSlope:    0.01,
+		Erosion:     0.3,
+		Deposition:  0.3,
+		Evaporation: 0.01,
+		Gravity:     4,
+	}
+}
+
+// heightAndGradient returns the interpolated height and the gradient in the given point.
+func heightAndGradient(heightMap [][]float32, x, z float32) (float32, float32, float32) {
+	cx, cz := int(x), int(z)
+	ox, oz := x-float32(cx), z-float32(cz)
+	h00 := heightMap[cz][cx]
+	h10 := heightMap[cz][cx+1]
+	h01 := heightMap[cz+1][cx]
+	h11 := heightMap[cz+1][cx+1]
+	gx := (h10-h00)*(1-oz) + (h11-h01)*oz
+	gz := (h01-h00)*(1-ox) + (h11-h10)*ox
+	h := h00*(1-ox)*(1-oz) + h10*ox*(1-oz) + h01*(1-ox)*oz + h11*ox*oz
+	return h, gx, gz
+}
+
+// addBilinear adds the amount to the corners of the cell of the given point, weighted by the distance.
+func addBilinear(heightMap [][]float32, x, z, amount float32) {
+	cx, cz := int(x), int(z)
+	ox, oz := x-float32(cx), z-float32(cz)
+	heightMap[cz][cx] += amount * (1 - ox) * (1 - oz)
+	heightMap[cz][cx+1] += amount * ox * (1 - oz)
+	heightMap[cz+1][cx] += amount * (1 - ox) * oz
+	heightMap[cz+1][cx+1] += amount * ox * oz
+}
+
+// Generate runs the erosion on the current values of the height map.
+func (e *HydraulicErosion) Generate(heightMap [][]float32) {
+	if len(heightMap) < 2 || len(heightMap[0]) < 2 {
+		return
+	}
+	maxX, maxZ := float32(len(heightMap[0])-1), float32(len(heightMap)-1)
+	r := rand.New(rand.NewSource(e.Seed))
+	for d := 0; d < e.Droplets; d++ {
+		x, z := r.Float32()*maxX, r.Float32()*maxZ
+		dirX, dirZ := float32(0), float32(0)
+		speed, water, sediment := float32(1), float32(1), float32(0)
+		for step := 0; step < e.MaxSteps; step++ {
+			h, gx, gz := heightAndGradient(heightMap, x, z)
+			dirX = dirX*e.Inertia - gx*(1-e.Inertia)
+			dirZ = dirZ*e.Inertia - gz*(1-e.Inertia)
+			length := float32(math.Sqrt(float64(dirX*dirX + dirZ*dirZ)))
+			if length == 0 {
+				break
+			}
+			dirX, dirZ = dirX/length, dirZ/length
+			nx, nz := x+dirX, z+dirZ
+			if nx < 0 || nz < 0 || nx >= maxX || nz >= maxZ {
+				break
+			}
+			nh, _, _ := heightAndGradient(heightMap, nx, nz)
+			dh := nh - h
+			capacity := float32(math.Max(float64(-dh), float64(e.MinSlope))) * speed * water * e.Capacity
+			if sediment > capacity || dh > 0 {
+				// uphill it fills the pit, otherwise it drops the surplus.
+				amount := (sediment - capacity) * e.Deposition
+				if dh > 0 {
+					amount = float32(math.Min(float64(dh), float64(sediment)))
+				}
+				sediment -= amount
+				addBilinear(heightMap, x, z, amount)
+			} else {
+				// it can't dig deeper than the next point.
+				amount := float32(math.Min(float64((capacity-sediment)*e.Erosion), float64(-dh)))
+				sediment += amount
+				addBilinear(heightMap, x, z, -amount)
+			}
+			speed = float32(math.Sqrt(math.Max(0, float64(speed*speed-dh*e.Gravity))))
+			water *= 1 - e.Evaporation
+			x, z = nx, nz
+		}
+	}
+}
+
+// ThermalErosion is the talus erosion pass. If the height difference of the neighbour points
+// is greater than the talus, the material slides down. It makes scree slopes under the cliffs.
+// The sum of the heights doesn't change.
+type ThermalErosion struct {
+	Iterations int
+	Talus      float32
+	// the ratio of the moved material in an iteration, it has to be between 0 and 1.
+	Rate float32
+}
+
+// NewThermalErosion returns a thermal erosion pass with 0.5 rate.
+func NewThermalErosion(iterations int, talus float32) *ThermalErosion {
+	return &ThermalErosion{
+		Iterations: iterations,
+		Talus:      talus,
+		Rate:       0.5,
+	}
+}
+
+// Generate runs the erosion on the current values of the height map.
+func (e *ThermalErosion) Generate(heightMap [][]float32) {
+	if len(heightMap) == 0 {
+		return
+	}
+	delta := New(len(heightMap[0])-1, len(heightMap)-1)
+	for i := 0; i < e.Iterations; i++ {
+		for z := 0; z < len(heightMap); z++ {
+			for x := 0; x < len(heightMap[z]); x++ {
+				for _, n := range [4][2]int{{x - 1, z}, {x + 1, z}, {x, z - 1}, {x, z + 1}} {
+					if n[1] < 0 || n[1] >= len(heightMap) || n[0] < 0 || n[0] >= len(heightMap[z]) {
+						continue
+					}
+					diff := heightMap[z][x] - heightMap[n[1]][n[0]]
+					if diff > e.Talus {
+						// a cell could have 4 lower neighbours, so that the quarter is moved at most.
+						amount := e.Rate * (diff - e.Talus) / 4
+						delta[z][x] -= amount
+						delta[n[1]][n[0]] += amount
+					}
+				}
+			}
+		}
+		for z := 0; z < len(heightMap); z++ {
+			for x := 0; x < len(heightMap[z]); x++ {
+				heightMap[z][x] += delta[z][x]
+				delta[z][x] = 0
+			}
+		}
+	}
+}
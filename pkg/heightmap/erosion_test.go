@@ -0,0 +1,77 @@
+package heightmap
+
+import (
+	"math"
+	"testing"
+)
+
+func sum(heightMap [][]float32) float64 {
+	s := 0.0
+	for z := 0; z < len(heightMap); z++ {
+		for x := 0; x < len(heightMap[z]); x++ {
+			s += float64(heightMap[z][x])
+		}
+	}
+	return s
+}
+func maxSlope(heightMap [][]float32) float32 {
+	slope := float32(0)
+	for z := 0; z < len(heightMap); z++ {
+		for x := 0; x+1 < len(heightMap[z]); x++ {
+			if d := float32(math.Abs(float64(heightMap[z][x+1] - heightMap[z][x]))); d > slope {
+				slope = d
+			}
+		}
+	}
+	return slope
+}
+func TestThermalErosion(t *testing.T) {
+	hm := New(8, 8)
+	hm[4][4] = 10
+	before := sum(hm)
+	NewThermalErosion(50, 1).Generate(hm)
+	if math.Abs(sum(hm)-before) > 0.001 {
+		t.Errorf("The sum of the heights should be kept. Instead of '%f', we have '%f'.", before, sum(hm))
+	}
+	if hm[4][4] >= 10 {
+		t.Errorf("The peak should be eroded. We have '%f'.", hm[4][4])
+	}
+	if maxSlope(hm) > 2 {
+		t.Errorf("The slope should be reduced. We have '%f'.", maxSlope(hm))
+	}
+}
+func TestHydraulicErosion(t *testing.T) {
+	hm := New(32, 32)
+	NewFBm(NewPerlinNoise(0), 4, 1.0/16, 0, 8).Generate(hm)
+	original := New(32, 32)
+	for z := 0; z < len(hm); z++ {
+		copy(original[z], hm[z])
+	}
+	e := NewHydraulicErosion(1, 200)
+	e.Generate(hm)
+	changed := false
+	for z := 0; z < len(hm); z++ {
+		for x := 0; x < len(hm[z]); x++ {
+			if math.IsNaN(float64(hm[z][x])) {
+				t.Fatal("The erosion shouldn't make NaN values.")
+			}
+			if hm[z][x] != original[z][x] {
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		t.Error("The erosion should change the map.")
+	}
+	// the same seed gives the same result.
+	e2 := New(32, 32)
+	NewFBm(NewPerlinNoise(0), 4, 1.0/16, 0, 8).Generate(e2)
+	e.Generate(e2)
+	for z := 0; z < len(hm); z++ {
+		for x := 0; x < len(hm[z]); x++ {
+			if hm[z][x] != e2[z][x] {
+				t.Fatal("The same seed should give the same result.")
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package heightmap
+
+import (
+	"math"
+)
+
+const (
+	defaultPersistence = 0.5
+	defaultLacunarity  = 2.0
+	defaultRidgedGain  = 2.0
+)
+
+// FBm is the fractional brownian motion of a noise. The octaves are noise layers, the frequency
+// of a layer is lacunarity times the previous one, its amplitude is persistence times the
+// previous one. The values are scaled to the [MinHeight, MaxHeight] range.
+type FBm struct {
+	Noise       Noise
+	Octaves     int
+	Frequency   float32
+	Persistence float32
+	Lacunarity  float32
+	MinHeight   float32
+	MaxHeight   float32
+}
+
+// NewFBm returns a fBm generator with 0.5 persistence and 2 lacunarity. The frequency
+// is the frequency of the first octave, for example with 1/32 the hills are ~32 units wide.
+func NewFBm(noise Noise, octaves int, frequency, minH, maxH float32) *FBm {
+	return &FBm{
+		Noise:       noise,
+		Octaves:     octaves,
+		Frequency:   frequency,
+		Persistence: defaultPersistence,
+		Lacunarity:  defaultLacunarity,
+		MinHeight:   minH,
+		MaxHeight:   maxH,
+	}
+}
+
+// HeightAt returns the height in the given point.
+func (f *FBm) HeightAt(x, z float32) float32 {
+	sum, total := 0.0, 0.0
+	amplitude, frequency := 1.0, float64(f.Frequency)
+	for o := 0; o < f.Octaves; o++ {
+		sum += f.Noise.Noise2D(float64(x)*frequency, float64(z)*frequency) * amplitude
+		total += amplitude
+		amplitude *= float64(f.Persistence)
+		frequency *= float64(f.Lacunarity)
+	}
+	if total == 0 {
+		return f.MinHeight
+	}
+	value := float32((sum/total + 1) / 2)
+	return f.MinHeight + value*(f.MaxHeight-f.MinHeight)
+}
+
+// Generate sets the values of the height map.
+func (f *FBm) Generate(heightMap [][]float32) {
+	sampleGenerate(f, heightMap)
+}
+
+// Ridged is a multifractal noise with sharp ridges, like the mountain chains. The absolute value
+// of the noise is inverted, so that the zero crossings became peaks. The octaves are weighted
+// with the previous octave, so that the valleys stay smooth.
+type Ridged struct {
+	Noise       Noise
+	Octaves     int
+	Frequency   float32
+	Persistence float32
+	Lacunarity  float32
+	Gain        float32
+	MinHeight   float32
+	MaxHeight   float32
+}
+
+// NewRidged returns a ridged noise generator with 0.5 persistence, 2 lacunarity, 2 gain.
+func NewRidged(noise Noise, octaves int, frequency, minH, maxH float32) *Ridged {
+	return &Ridged{
+		Noise:       noise,
+		Octaves:     octaves,
+		Frequency:   frequency,
+		Persistence: defaultPersistence,
+		Lacunarity:  defaultLacunarity,
+		Gain:        defaultRidgedGain,
+		MinHeight:   minH,
+		MaxHeight:   maxH,
+	}
+}
+
+// HeightAt returns the height in the given point.
+func (r *Ridged) HeightAt(x, z float32) float32 {
+	sum, total := 0.0, 0.0
+	amplitude, frequency, weight := 1.0, float64(r.Frequency), 1.0
+	for o := 0; o < r.Octaves; o++ {
+		signal := 1 - math.Abs(r.Noise.Noise2D(float64(x)*frequency, float64(z)*frequency))
+		signal *= signal * weight
+		weight = math.Max(0, math.Min(1, signal*float64(r.Gain)))
+		sum += signal * amplitude
+		total += amplitude
+		amplitude *= float64(r.Persistence)
+		frequency *= float64(r.Lacunarity)
+	}
+	if total == 0 {
+		return r.MinHeight
+	}
+	return r.MinHeight + float32(sum/total)*(r.MaxHeight-r.MinHeight)
+}
+
+// Generate sets the values of the height map.
+func (r *Ridged) Generate(heightMap [][]float32) {
+	sampleGenerate(r, heightMap)
+}
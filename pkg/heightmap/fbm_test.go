@@ -0,0 +1,45 @@
+package heightmap
+
+import (
+	"testing"
+)
+
+func testSamplerRange(t *testing.T, s Sampler, minH, maxH float32) {
+	hm := New(32, 32)
+	s.Generate(hm)
+	low, high := bounds(hm)
+	if low < minH || high > maxH {
+		t.Errorf("The heights '%f' - '%f' are out of the range.", low, high)
+	}
+	if low == high {
+		t.Error("The surface shouldn't be flat.")
+	}
+	if hm[5][7] != s.HeightAt(7, 5) {
+		t.Errorf("The map value should be the same as the sampled one. '%f' '%f'.", hm[5][7], s.HeightAt(7, 5))
+	}
+}
+func TestNewFBm(t *testing.T) {
+	n := NewPerlinNoise(0)
+	f := NewFBm(n, 4, 0.1, -1, 3)
+	if f.Noise != n || f.Octaves != 4 || f.Frequency != 0.1 || f.MinHeight != -1 || f.MaxHeight != 3 {
+		t.Errorf("Invalid fbm '%v'.", f)
+	}
+	if f.Persistence != defaultPersistence || f.Lacunarity != defaultLacunarity {
+		t.Errorf("Invalid default persistence, lacunarity '%f', '%f'.", f.Persistence, f.Lacunarity)
+	}
+}
+func TestFBm(t *testing.T) {
+	testSamplerRange(t, NewFBm(NewPerlinNoise(0), 4, 1.0/16, -1, 3), -1, 3)
+	testSamplerRange(t, NewFBm(NewSimplexNoise(0), 4, 1.0/16, 0, 10), 0, 10)
+	f := NewFBm(NewPerlinNoise(0), 0, 1, 2, 3)
+	if h := f.HeightAt(1.5, 1.5); h != 2 {
+		t.Errorf("Without octaves the height should be the min height. We have '%f'.", h)
+	}
+}
+func TestRidged(t *testing.T) {
+	r := NewRidged(NewPerlinNoise(0), 4, 1.0/16, 0, 10)
+	if r.Gain != defaultRidgedGain {
+		t.Errorf("Invalid default gain. Instead of '%f', we have '%f'.", defaultRidgedGain, r.Gain)
+	}
+	testSamplerRange(t, r, 0, 10)
+}
@@ -0,0 +1,85 @@
+package heightmap
+
+// Generator sets the values of a height map. The map is indexed with [z][x], every row
+// has the same length. The generators could also modify the current values, like
+// the erosion passes, so that they could be chained.
+type Generator interface {
+	Generate(heightMap [][]float32)
+}
+
+// Sampler is a generator that could be evaluated in any point of the plane. The chunked
+// terrain needs this kind of generators, because it samples an infinite surface.
+type Sampler interface {
+	Generator
+	HeightAt(x, z float32) float32
+}
+
+// Chain is a generator that runs its generators one after the other.
+// It could be used for applying erosion passes on a noise generator.
+type Chain struct {
+	Generators []Generator
+}
+
+// NewChain returns a chain of the given generators.
+func NewChain(generators ...Generator) *Chain {
+	return &Chain{Generators: generators}
+}
+
+// Generate runs the generators in order.
+func (c *Chain) Generate(heightMap [][]float32) {
+	for _, g := range c.Generators {
+		g.Generate(heightMap)
+	}
+}
+
+// New returns a (length+1) x (width+1) height map with 0 values. It is the same
+// layout that the terrain builder uses.
+func New(width, length int) [][]float32 {
+	heightMap := make([][]float32, length+1)
+	for l := 0; l <= length; l++ {
+		heightMap[l] = make([]float32, width+1)
+	}
+	return heightMap
+}
+
+// sampleGenerate fills the height map with the values of the sampler.
+func sampleGenerate(s Sampler, heightMap [][]float32) {
+	for z := 0; z < len(heightMap); z++ {
+		for x := 0; x < len(heightMap[z]); x++ {
+			heightMap[z][x] = s.HeightAt(float32(x), float32(z))
+		}
+	}
+}
+
+// bounds returns the min and max values of the map.
+func bounds(heightMap [][]float32) (float32, float32) {
+	minValue, maxValue := heightMap[0][0], heightMap[0][0]
+	for z := 0; z < len(heightMap); z++ {
+		for x := 0; x < len(heightMap[z]); x++ {
+			if heightMap[z][x] < minValue {
+				minValue = heightMap[z][x]
+			}
+			if heightMap[z][x] > maxValue {
+				maxValue = heightMap[z][x]
+			}
+		}
+	}
+	return minValue, maxValue
+}
+
+// Normalize scales the values of the map to the [minH, maxH] range.
+func Normalize(heightMap [][]float32, minH, maxH float32) {
+	if len(heightMap) == 0 || len(heightMap[0]) == 0 {
+		return
+	}
+	minValue, maxValue := bounds(heightMap)
+	for z := 0; z < len(heightMap); z++ {
+		for x := 0; x < len(heightMap[z]); x++ {
+			if maxValue == minValue {
+				heightMap[z][x] = minH
+				continue
+			}
+			heightMap[z][x] = minH + (heightMap[z][x]-minValue)/(maxValue-minValue)*(maxH-minH)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package heightmap
+
+import (
+	"testing"
+)
+
+type constGenerator struct {
+	value float32
+}
+
+func (c constGenerator) Generate(heightMap [][]float32) {
+	for z := 0; z < len(heightMap); z++ {
+		for x := 0; x < len(heightMap[z]); x++ {
+			heightMap[z][x] += c.value
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	hm := New(3, 2)
+	if len(hm) != 3 {
+		t.Errorf("Invalid number of rows. Instead of '3', we have '%d'.", len(hm))
+	}
+	for z := 0; z < len(hm); z++ {
+		if len(hm[z]) != 4 {
+			t.Errorf("Invalid row length. Instead of '4', we have '%d'.", len(hm[z]))
+		}
+	}
+}
+func TestChain(t *testing.T) {
+	hm := New(2, 2)
+	NewChain(constGenerator{1}, constGenerator{2}).Generate(hm)
+	for z := 0; z < len(hm); z++ {
+		for x := 0; x < len(hm[z]); x++ {
+			if hm[z][x] != 3 {
+				t.Errorf("Invalid height. Instead of '3', we have '%f'.", hm[z][x])
+			}
+		}
+	}
+}
+func TestNormalize(t *testing.T) {
+	hm := [][]float32{{0, 1}, {2, 4}}
+	Normalize(hm, -1, 1)
+	expected := [][]float32{{-1, -0.5}, {0, 1}}
+	for z := 0; z < 2; z++ {
+		for x := 0; x < 2; x++ {
+			if hm[z][x] != expected[z][x] {
+				t.Errorf("Invalid height. Instead of '%f', we have '%f'.", expected[z][x], hm[z][x])
+			}
+		}
+	}
+	flat := [][]float32{{2, 2}}
+	Normalize(flat, 5, 6)
+	if flat[0][0] != 5 || flat[0][1] != 5 {
+		t.Errorf("Invalid flat heights. Instead of '5', we have '%v'.", flat)
+	}
+}
@@ -0,0 +1,110 @@
+package heightmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/png"
+	"io/ioutil"
+	"os"
+)
+
+var (
+	ErrorInvalidRawSize = errors.New("The size of the raw file doesn't match the given dimensions")
+)
+
+// Image is a generator that uses an external height map. The values of the source are
+// between 0 and 1, they are scaled to the [MinHeight, MaxHeight] range. If the size of the
+// source and the height map are different, the source is resampled with bilinear interpolation.
+type Image struct {
+	// the source values, indexed with [z][x].
+	values    [][]float32
+	MinHeight float32
+	MaxHeight float32
+}
+
+// NewImage returns an image generator from the given [z][x] indexed values between 0 and 1.
+func NewImage(values [][]float32, minH, maxH float32) *Image {
+	return &Image{values: values, MinHeight: minH, MaxHeight: maxH}
+}
+
+// LoadImage returns an image generator from a grayscale image file. The brightness of the
+// pixels is the height, the colored images are converted to grayscale. The png format is supported.
+func LoadImage(path string, minH, maxH float32) (*Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	values := New(b.Dx()-1, b.Dy()-1)
+	for z := 0; z < b.Dy(); z++ {
+		for x := 0; x < b.Dx(); x++ {
+			gray := color.Gray16Model.Convert(img.At(b.Min.X+x, b.Min.Y+z)).(color.Gray16)
+			values[z][x] = float32(gray.Y) / 65535
+		}
+	}
+	return NewImage(values, minH, maxH), nil
+}
+
+// LoadRaw returns an image generator from a 16 bit unsigned raw file, that is exported from a
+// terrain editor. The file contains width x length values, row by row. It returns error if
+// the size of the file is not 2 * width * length bytes.
+func LoadRaw(path string, width, length int, littleEndian bool, minH, maxH float32) (*Image, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if width <= 0 || length <= 0 || len(data) != 2*width*length {
+		return nil, ErrorInvalidRawSize
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if littleEndian {
+		order = binary.LittleEndian
+	}
+	values := New(width-1, length-1)
+	for z := 0; z < length; z++ {
+		for x := 0; x < width; x++ {
+			values[z][x] = float32(order.Uint16(data[2*(z*width+x):])) / 65535
+		}
+	}
+	return NewImage(values, minH, maxH), nil
+}
+
+// valueAt returns the bilinear interpolated source value in the given source coordinates.
+func (i *Image) valueAt(x, z float32) float32 {
+	x1, z1 := int(x), int(z)
+	x2, z2 := x1+1, z1+1
+	if x2 >= len(i.values[0]) {
+		x2 = x1
+	}
+	if z2 >= len(i.values) {
+		z2 = z1
+	}
+	wX, wZ := x-float32(x1), z-float32(z1)
+	return (i.values[z1][x1]*(1-wX)+i.values[z1][x2]*wX)*(1-wZ) + (i.values[z2][x1]*(1-wX)+i.values[z2][x2]*wX)*wZ
+}
+
+// Generate sets the values of the height map. The source is stretched to the height map.
+func (i *Image) Generate(heightMap [][]float32) {
+	if len(i.values) == 0 || len(i.values[0]) == 0 {
+		return
+	}
+	stepZ, stepX := float32(0), float32(0)
+	if len(heightMap) > 1 {
+		stepZ = float32(len(i.values)-1) / float32(len(heightMap)-1)
+	}
+	for z := 0; z < len(heightMap); z++ {
+		if len(heightMap[z]) > 1 {
+			stepX = float32(len(i.values[0])-1) / float32(len(heightMap[z])-1)
+		}
+		for x := 0; x < len(heightMap[z]); x++ {
+			heightMap[z][x] = i.MinHeight + i.valueAt(float32(x)*stepX, float32(z)*stepZ)*(i.MaxHeight-i.MinHeight)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package heightmap
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageGenerate(t *testing.T) {
+	img := NewImage([][]float32{{0, 1}, {1, 0}}, 2, 4)
+	hm := New(2, 2)
+	img.Generate(hm)
+	expected := [][]float32{{2, 3, 4}, {3, 3, 3}, {4, 3, 2}}
+	for z := 0; z < 3; z++ {
+		for x := 0; x < 3; x++ {
+			if hm[z][x] != expected[z][x] {
+				t.Errorf("Invalid height at '%d, %d'. Instead of '%f', we have '%f'.", x, z, expected[z][x], hm[z][x])
+			}
+		}
+	}
+}
+func TestLoadImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "heightmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "height.png")
+	src := image.NewGray16(image.Rect(0, 0, 2, 1))
+	src.SetGray16(0, 0, color.Gray16{0})
+	src.SetGray16(1, 0, color.Gray16{65535})
+	f, _ := os.Create(path)
+	png.Encode(f, src)
+	f.Close()
+	img, err := LoadImage(path, 0, 10)
+	if err != nil {
+		t.Fatalf("It shouldn't return error. '%v'.", err)
+	}
+	hm := New(1, 0)
+	img.Generate(hm)
+	if hm[0][0] != 0 || hm[0][1] != 10 {
+		t.Errorf("Invalid heights. Instead of '[0 10]', we have '%v'.", hm[0])
+	}
+	if _, err := LoadImage(filepath.Join(dir, "missing.png"), 0, 1); err == nil {
+		t.Error("It should return error for missing file.")
+	}
+}
+func TestLoadRaw(t *testing.T) {
+	dir, err := ioutil.TempDir("", "heightmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "height.raw")
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint16(data[0:], 65535)
+	binary.LittleEndian.PutUint16(data[2:], 0)
+	ioutil.WriteFile(path, data, 0644)
+	img, err := LoadRaw(path, 2, 1, true, 0, 1)
+	if err != nil {
+		t.Fatalf("It shouldn't return error. '%v'.", err)
+	}
+	hm := New(1, 0)
+	img.Generate(hm)
+	if hm[0][0] != 1 || hm[0][1] != 0 {
+		t.Errorf("Invalid heights. Instead of '[1 0]', we have '%v'.", hm[0])
+	}
+	if _, err := LoadRaw(path, 3, 1, true, 0, 1); err != ErrorInvalidRawSize {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorInvalidRawSize, err)
+	}
+}
@@ -0,0 +1,119 @@
+package heightmap
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Noise is a coherent 2D noise function. The return value is in the [-1, 1] range.
+type Noise interface {
+	Noise2D(x, y float64) float64
+}
+
+// permutation returns the doubled permutation table of the given seed.
+func permutation(seed int64) [512]int {
+	r := rand.New(rand.NewSource(seed))
+	p := r.Perm(256)
+	var perm [512]int
+	for i := 0; i < 512; i++ {
+		perm[i] = p[i&255]
+	}
+	return perm
+}
+
+// the gradient directions of the 2D noises.
+var gradients2D = [8][2]float64{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{math.Sqrt2 / 2, math.Sqrt2 / 2}, {-math.Sqrt2 / 2, math.Sqrt2 / 2},
+	{math.Sqrt2 / 2, -math.Sqrt2 / 2}, {-math.Sqrt2 / 2, -math.Sqrt2 / 2},
+}
+
+// PerlinNoise is the improved Perlin gradient noise.
+type PerlinNoise struct {
+	perm [512]int
+}
+
+// NewPerlinNoise returns a Perlin noise. The same seed gives the same noise.
+func NewPerlinNoise(seed int64) *PerlinNoise {
+	return &PerlinNoise{perm: permutation(seed)}
+}
+
+// fade is the 6t^5-15t^4+10t^3 curve of the improved Perlin noise.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// lerp is the linear interpolation between a and b.
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// grad returns the dot product of the gradient of the given hash and the (x, y) vector.
+func grad(hash int, x, y float64) float64 {
+	g := gradients2D[hash&7]
+	return g[0]*x + g[1]*y
+}
+
+// Noise2D returns the noise value in the given point.
+func (n *PerlinNoise) Noise2D(x, y float64) float64 {
+	fx, fy := math.Floor(x), math.Floor(y)
+	xi, yi := int(fx)&255, int(fy)&255
+	x, y = x-fx, y-fy
+	u, v := fade(x), fade(y)
+	aa := n.perm[n.perm[xi]+yi]
+	ab := n.perm[n.perm[xi]+yi+1]
+	ba := n.perm[n.perm[xi+1]+yi]
+	bb := n.perm[n.perm[xi+1]+yi+1]
+	value := lerp(v,
+		lerp(u, grad(aa, x, y), grad(ba, x-1, y)),
+		lerp(u, grad(ab, x, y-1), grad(bb, x-1, y-1)))
+	// the max value of the 2D noise is sqrt(0.5), it is scaled to [-1, 1].
+	return value * math.Sqrt2
+}
+
+// SimplexNoise is the 2D simplex noise. It has less directional artifacts than the Perlin noise.
+type SimplexNoise struct {
+	perm [512]int
+}
+
+// NewSimplexNoise returns a simplex noise. The same seed gives the same noise.
+func NewSimplexNoise(seed int64) *SimplexNoise {
+	return &SimplexNoise{perm: permutation(seed)}
+}
+
+// skewing factors of the 2D simplex grid.
+var (
+	simplexF2 = 0.5 * (math.Sqrt(3) - 1)
+	simplexG2 = (3 - math.Sqrt(3)) / 6
+)
+
+// simplexCorner returns the contribution of a simplex corner.
+func simplexCorner(hash int, x, y float64) float64 {
+	t := 0.5 - x*x - y*y
+	if t < 0 {
+		return 0
+	}
+	t *= t
+	return t * t * grad(hash, x, y)
+}
+
+// Noise2D returns the noise value in the given point.
+func (n *SimplexNoise) Noise2D(x, y float64) float64 {
+	s := (x + y) * simplexF2
+	i, j := math.Floor(x+s), math.Floor(y+s)
+	t := (i + j) * simplexG2
+	x0, y0 := x-(i-t), y-(j-t)
+	// the second corner depends on the triangle of the point.
+	i1, j1 := 0, 1
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	}
+	x1, y1 := x0-float64(i1)+simplexG2, y0-float64(j1)+simplexG2
+	x2, y2 := x0-1+2*simplexG2, y0-1+2*simplexG2
+	ii, jj := int(i)&255, int(j)&255
+	n0 := simplexCorner(n.perm[ii+n.perm[jj]], x0, y0)
+	n1 := simplexCorner(n.perm[ii+i1+n.perm[jj+j1]], x1, y1)
+	n2 := simplexCorner(n.perm[ii+1+n.perm[jj+1]], x2, y2)
+	// scale to [-1, 1].
+	return 70 * (n0 + n1 + n2)
+}
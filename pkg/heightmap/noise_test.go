@@ -0,0 +1,39 @@
+package heightmap
+
+import (
+	"testing"
+)
+
+func testNoise(t *testing.T, n1, n2, other Noise) {
+	different := false
+	for i := 0; i < 200; i++ {
+		x, y := float64(i)*0.37-20, float64(i)*0.53-30
+		v := n1.Noise2D(x, y)
+		if v < -1 || v > 1 {
+			t.Errorf("Noise value '%f' is out of range.", v)
+		}
+		if v != n2.Noise2D(x, y) {
+			t.Error("The same seed should give the same noise.")
+		}
+		if v != other.Noise2D(x, y) {
+			different = true
+		}
+	}
+	if !different {
+		t.Error("Different seed should give different noise.")
+	}
+	// it is continuous.
+	if d := n1.Noise2D(1.5, 2.5) - n1.Noise2D(1.5001, 2.5); d > 0.01 || d < -0.01 {
+		t.Errorf("The noise should be continuous. The difference is '%f'.", d)
+	}
+}
+func TestPerlinNoise(t *testing.T) {
+	testNoise(t, NewPerlinNoise(1), NewPerlinNoise(1), NewPerlinNoise(2))
+	// it is 0 in the lattice points.
+	if v := NewPerlinNoise(1).Noise2D(3, 4); v != 0 {
+		t.Errorf("Invalid value in the lattice point. Instead of '0', we have '%f'.", v)
+	}
+}
+func TestSimplexNoise(t *testing.T) {
+	testNoise(t, NewSimplexNoise(1), NewSimplexNoise(1), NewSimplexNoise(2))
+}
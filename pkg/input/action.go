@@ -0,0 +1,428 @@
+package input
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// The trigger types of the actions.
+	// The press action is active in the update, when one of its bindings is pressed.
+	TRIGGER_PRESS = iota
+	// The hold action is active while one of its bindings is held for the hold time.
+	TRIGGER_HOLD
+	// The release action is active in the update, when its last binding is released.
+	TRIGGER_RELEASE
+	// The double tap action is active in the update of the second press, if it is
+	// pressed twice in the double tap time.
+	TRIGGER_DOUBLE_TAP
+)
+
+const (
+	// The actions of the global context are always enabled.
+	CONTEXT_GLOBAL = ""
+	CONTEXT_MENU   = "menu"
+	CONTEXT_GAME   = "game"
+	// The default max time between the taps of the double tap actions in ms.
+	DEFAULT_DOUBLE_TAP_TIME = 300.0
+)
+
+const (
+	// The names of the default actions. The camera movement actions are named as the
+	// camera movement keys of the screens.
+	ACTION_MENU         = "menu"
+	ACTION_MENU_UP      = "menuUp"
+	ACTION_MENU_DOWN    = "menuDown"
+	ACTION_SELECT       = "select"
+	ACTION_DELETE       = "delete"
+	ACTION_NEXT_FIELD   = "nextField"
+	ACTION_PREV_FIELD   = "prevField"
+	ACTION_SAVE         = "save"
+	ACTION_CANCEL       = "cancel"
+	ACTION_RESET        = "reset"
+	ACTION_CURSOR_LEFT  = "cursorLeft"
+	ACTION_CURSOR_RIGHT = "cursorRight"
+	ACTION_CURSOR_HOME  = "cursorHome"
+	ACTION_CURSOR_END   = "cursorEnd"
+	ACTION_DELETE_NEXT  = "deleteNext"
+	ACTION_SELECT_ALL   = "selectAll"
+	ACTION_COPY         = "copy"
+	ACTION_CUT          = "cut"
+	ACTION_PASTE        = "paste"
+	ACTION_FORWARD      = "forward"
+	ACTION_BACK         = "back"
+	ACTION_LEFT         = "left"
+	ACTION_RIGHT        = "right"
+	ACTION_UP           = "up"
+	ACTION_DOWN         = "down"
+	ACTION_ROTATE_LEFT  = "rotateLeft"
+	ACTION_ROTATE_RIGHT = "rotateRight"
+	ACTION_ROTATE_UP    = "rotateUp"
+	ACTION_ROTATE_DOWN  = "rotateDown"
+)
+
+// Action is a named input event. It is triggered by its bindings, if its context is enabled.
+type Action struct {
+	name     string
+	context  string
+	trigger  int
+	bindings []Binding
+	// the min time of the hold in ms for the hold actions.
+	holdTime float64
+	// state
+	value          float32
+	down           bool
+	active         bool
+	heldFor        float64
+	sinceLastPress float64
+	tapped         bool
+}
+
+// GetName returns the name of the action.
+func (a *Action) GetName() string {
+	return a.name
+}
+
+// GetContext returns the context of the action.
+func (a *Action) GetContext() string {
+	return a.context
+}
+
+// GetTrigger returns the trigger type of the action.
+func (a *Action) GetTrigger() int {
+	return a.trigger
+}
+
+// GetBindings returns the bindings of the action.
+func (a *Action) GetBindings() []Binding {
+	return a.bindings
+}
+
+// SetHoldTime sets the min time of the hold in ms for the hold actions.
+func (a *Action) SetHoldTime(t float64) {
+	a.holdTime = t
+}
+
+// reset releases the action without triggering it.
+func (a *Action) reset() {
+	a.value = 0
+	a.down = false
+	a.active = false
+	a.heldFor = 0
+	a.tapped = false
+}
+
+// update updates the state of the action with the current value of its bindings.
+func (a *Action) update(dt float64, value float32, doubleTapTime float64) {
+	wasDown := a.down
+	a.value = value
+	a.down = value > 0
+	a.sinceLastPress += dt
+	if a.down {
+		a.heldFor += dt
+	} else {
+		a.heldFor = 0
+	}
+	pressed := a.down && !wasDown
+	switch a.trigger {
+	case TRIGGER_PRESS:
+		a.active = pressed
+		break
+	case TRIGGER_HOLD:
+		a.active = a.down && a.heldFor >= a.holdTime
+		break
+	case TRIGGER_RELEASE:
+		a.active = !a.down && wasDown
+		break
+	case TRIGGER_DOUBLE_TAP:
+		a.active = false
+		if pressed {
+			if a.tapped && a.sinceLastPress <= doubleTapTime {
+				a.active = true
+				a.tapped = false
+			} else {
+				a.tapped = true
+			}
+			a.sinceLastPress = 0
+		}
+		break
+	}
+}
+
+// ActionMap holds the actions and the enabled contexts. It has to be updated once
+// per frame with the input stores, then the actions could be queried.
+type ActionMap struct {
+	actions map[string]*Action
+	// the names of the actions in the order of the insertion.
+	order         []string
+	contexts      map[string]bool
+	doubleTapTime float64
+}
+
+// NewActionMap returns an empty action map with enabled game context.
+func NewActionMap() *ActionMap {
+	return &ActionMap{
+		actions:       make(map[string]*Action),
+		order:         []string{},
+		contexts:      map[string]bool{CONTEXT_GAME: true},
+		doubleTapTime: DEFAULT_DOUBLE_TAP_TIME,
+	}
+}
+
+// DefaultActionMap returns an action map with the default actions. The menu actions are
+// bound to the keys that are used by the menu and form screens, the camera movement
+// actions are bound to WASD, QE, the arrow keys and the standard gamepad layout.
+func DefaultActionMap() *ActionMap {
+	m := NewActionMap()
+	m.AddAction(ACTION_MENU, CONTEXT_GLOBAL, TRIGGER_PRESS, KeyBinding(glfw.KeyEscape, 0), GamepadButtonBinding(glfw.ButtonStart))
+	m.AddAction(ACTION_MENU_UP, CONTEXT_MENU, TRIGGER_HOLD, KeyBinding(glfw.KeyUp, 0), GamepadButtonBinding(glfw.ButtonDpadUp))
+	m.AddAction(ACTION_MENU_DOWN, CONTEXT_MENU, TRIGGER_HOLD, KeyBinding(glfw.KeyDown, 0), GamepadButtonBinding(glfw.ButtonDpadDown))
+	m.AddAction(ACTION_SELECT, CONTEXT_MENU, TRIGGER_HOLD, MouseBinding(glfw.MouseButtonLeft))
+	m.AddAction(ACTION_DELETE, CONTEXT_MENU, TRIGGER_HOLD, KeyBinding(glfw.KeyBackspace, 0))
+	m.AddAction(ACTION_NEXT_FIELD, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyTab, 0))
+	m.AddAction(ACTION_PREV_FIELD, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyTab, glfw.ModShift))
+	m.AddAction(ACTION_SAVE, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyEnter, 0))
+	m.AddAction(ACTION_CANCEL, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyZ, glfw.ModControl))
+	m.AddAction(ACTION_RESET, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyR, glfw.ModControl))
+	m.AddAction(ACTION_CURSOR_LEFT, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyLeft, 0))
+	m.AddAction(ACTION_CURSOR_RIGHT, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyRight, 0))
+	m.AddAction(ACTION_CURSOR_HOME, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyHome, 0))
+	m.AddAction(ACTION_CURSOR_END, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyEnd, 0))
+	m.AddAction(ACTION_DELETE_NEXT, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyDelete, 0))
+	m.AddAction(ACTION_SELECT_ALL, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyA, glfw.ModControl))
+	m.AddAction(ACTION_COPY, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyC, glfw.ModControl))
+	m.AddAction(ACTION_CUT, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyX, glfw.ModControl))
+	m.AddAction(ACTION_PASTE, CONTEXT_MENU, TRIGGER_PRESS, KeyBinding(glfw.KeyV, glfw.ModControl))
+	m.AddAction(ACTION_FORWARD, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyW, 0), GamepadAxisBinding(glfw.AxisLeftY, -1))
+	m.AddAction(ACTION_BACK, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyS, 0), GamepadAxisBinding(glfw.AxisLeftY, 1))
+	m.AddAction(ACTION_LEFT, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyA, 0), GamepadAxisBinding(glfw.AxisLeftX, -1))
+	m.AddAction(ACTION_RIGHT, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyD, 0), GamepadAxisBinding(glfw.AxisLeftX, 1))
+	m.AddAction(ACTION_UP, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyQ, 0), GamepadButtonBinding(glfw.ButtonRightBumper))
+	m.AddAction(ACTION_DOWN, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyE, 0), GamepadButtonBinding(glfw.ButtonLeftBumper))
+	m.AddAction(ACTION_ROTATE_LEFT, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyLeft, 0), GamepadAxisBinding(glfw.AxisRightX, -1))
+	m.AddAction(ACTION_ROTATE_RIGHT, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyRight, 0), GamepadAxisBinding(glfw.AxisRightX, 1))
+	m.AddAction(ACTION_ROTATE_UP, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyUp, 0), GamepadAxisBinding(glfw.AxisRightY, -1))
+	m.AddAction(ACTION_ROTATE_DOWN, CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyDown, 0), GamepadAxisBinding(glfw.AxisRightY, 1))
+	return m
+}
+
+// AddAction inserts a new action to the map, or replaces the one with the same name.
+func (m *ActionMap) AddAction(name, context string, trigger int, bindings ...Binding) *Action {
+	if _, ok := m.actions[name]; !ok {
+		m.order = append(m.order, name)
+	}
+	a := &Action{
+		name:     name,
+		context:  context,
+		trigger:  trigger,
+		bindings: append([]Binding{}, bindings...),
+	}
+	m.actions[name] = a
+	return a
+}
+
+// RemoveAction removes the action from the map.
+func (m *ActionMap) RemoveAction(name string) {
+	if _, ok := m.actions[name]; !ok {
+		return
+	}
+	delete(m.actions, name)
+	for i := range m.order {
+		if m.order[i] == name {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetAction returns the action with the given name or nil.
+func (m *ActionMap) GetAction(name string) *Action {
+	return m.actions[name]
+}
+
+// HasAction returns true if the action is in the map.
+func (m *ActionMap) HasAction(name string) bool {
+	_, ok := m.actions[name]
+	return ok
+}
+
+// IsEnabled returns true if the action is in the map and its context is enabled.
+func (m *ActionMap) IsEnabled(name string) bool {
+	a, ok := m.actions[name]
+	return ok && m.IsContextEnabled(a.context)
+}
+
+// GetActionNames returns the names of the actions in the order of the insertion.
+func (m *ActionMap) GetActionNames() []string {
+	return m.order
+}
+
+// Bind adds the binding to the action. It returns false if the action is missing.
+func (m *ActionMap) Bind(name string, b Binding) bool {
+	a, ok := m.actions[name]
+	if !ok {
+		return false
+	}
+	a.bindings = append(a.bindings, b)
+	return true
+}
+
+// SetBindings replaces the bindings of the action. It returns false if the action is missing.
+func (m *ActionMap) SetBindings(name string, bindings []Binding) bool {
+	a, ok := m.actions[name]
+	if !ok {
+		return false
+	}
+	a.bindings = append([]Binding{}, bindings...)
+	a.reset()
+	return true
+}
+
+// SetDoubleTapTime sets the max time between the taps of the double tap actions in ms.
+func (m *ActionMap) SetDoubleTapTime(t float64) {
+	m.doubleTapTime = t
+}
+
+// SetContext enables the given context and disables the others. The global context is always enabled.
+func (m *ActionMap) SetContext(context string) {
+	m.contexts = map[string]bool{context: true}
+}
+
+// EnableContext enables the given context.
+func (m *ActionMap) EnableContext(context string) {
+	m.contexts[context] = true
+}
+
+// DisableContext disables the given context. The actions of the disabled contexts are released.
+func (m *ActionMap) DisableContext(context string) {
+	delete(m.contexts, context)
+}
+
+// IsContextEnabled returns true if the actions of the given context are enabled.
+func (m *ActionMap) IsContextEnabled(context string) bool {
+	return context == CONTEXT_GLOBAL || m.contexts[context]
+}
+
+// Update updates the state of the actions from the stores. The actions of the disabled
+// contexts are released without triggering. The missing stores are handled as released inputs.
+// If the same key is bound with different modifiers, only the most specific pressed
+// binding is active, so the Shift+Tab doesn't trigger the Tab binding.
+func (m *ActionMap) Update(dt float64, keyStore interfaces.RoKeyStore, buttonStore interfaces.RoButtonStore, gamepad interfaces.RoGamepadStore) {
+	pressed := m.pressedKeyBindings(keyStore)
+	for _, a := range m.actions {
+		if !m.IsContextEnabled(a.context) {
+			a.reset()
+			continue
+		}
+		value := float32(0.0)
+		for _, b := range a.bindings {
+			if b.shadowedBy(pressed) {
+				continue
+			}
+			if v := b.Value(keyStore, buttonStore, gamepad); v > value {
+				value = v
+			}
+		}
+		a.update(dt, value, m.doubleTapTime)
+	}
+}
+
+// pressedKeyBindings returns the pressed keyboard bindings of the enabled actions.
+func (m *ActionMap) pressedKeyBindings(keyStore interfaces.RoKeyStore) []Binding {
+	pressed := []Binding{}
+	for _, a := range m.actions {
+		if !m.IsContextEnabled(a.context) {
+			continue
+		}
+		for _, b := range a.bindings {
+			if b.Device == DEVICE_KEYBOARD && b.Value(keyStore, nil, nil) > 0 {
+				pressed = append(pressed, b)
+			}
+		}
+	}
+	return pressed
+}
+
+// IsActive returns true if the action is triggered in the last update.
+func (m *ActionMap) IsActive(name string) bool {
+	a, ok := m.actions[name]
+	return ok && a.active
+}
+
+// Value returns the analog value of the active action in the [0-1] interval. The
+// digital inputs are 1, the inactive actions are 0.
+func (m *ActionMap) Value(name string) float32 {
+	a, ok := m.actions[name]
+	if !ok || !a.active {
+		return 0.0
+	}
+	if a.value > 0 {
+		return a.value
+	}
+	return 1.0
+}
+
+// BlockedActionMap is a read only view of an action map, that hides the actions with
+// keyboard or mouse bindings. The overlays pass it to the screens below them, if they
+// want the keyboard or the pointer, so that the actions don't leak through them.
+type BlockedActionMap struct {
+	actions  *ActionMap
+	keyboard bool
+	mouse    bool
+}
+
+// BlockActions returns the view of the action map without the actions of the blocked
+// devices. The views could be blocked further. The other action map implementations
+// are hidden completely, because their bindings are unknown.
+func BlockActions(actions interfaces.RoActionMap, keyboard, mouse bool) interfaces.RoActionMap {
+	if actions == nil || !keyboard && !mouse {
+		return actions
+	}
+	switch m := actions.(type) {
+	case *ActionMap:
+		return &BlockedActionMap{actions: m, keyboard: keyboard, mouse: mouse}
+	case *BlockedActionMap:
+		return &BlockedActionMap{actions: m.actions, keyboard: keyboard || m.keyboard, mouse: mouse || m.mouse}
+	}
+	return nil
+}
+
+// hidden returns true if the action has a binding of a blocked device.
+func (b *BlockedActionMap) hidden(name string) bool {
+	a := b.actions.GetAction(name)
+	if a == nil {
+		return false
+	}
+	for _, binding := range a.bindings {
+		if b.keyboard && binding.Device == DEVICE_KEYBOARD || b.mouse && binding.Device == DEVICE_MOUSE {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAction returns true if the action is in the map. The hidden actions are also
+// in the map, so that the screens don't fall back to the blocked stores.
+func (b *BlockedActionMap) HasAction(name string) bool {
+	return b.actions.HasAction(name)
+}
+
+// IsEnabled returns true if the context of the action is enabled. The hidden actions
+// are also enabled, so that the screens don't fall back to the blocked stores.
+func (b *BlockedActionMap) IsEnabled(name string) bool {
+	return b.actions.IsEnabled(name)
+}
+
+// IsActive returns true if the action isn't hidden and it is triggered in the last update.
+func (b *BlockedActionMap) IsActive(name string) bool {
+	return !b.hidden(name) && b.actions.IsActive(name)
+}
+
+// Value returns the analog value of the action, the hidden actions are 0.
+func (b *BlockedActionMap) Value(name string) float32 {
+	if b.hidden(name) {
+		return 0.0
+	}
+	return b.actions.Value(name)
+}
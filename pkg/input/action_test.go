@@ -0,0 +1,233 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/store"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func TestAddRemoveAction(t *testing.T) {
+	m := NewActionMap()
+	m.AddAction("jump", CONTEXT_GAME, TRIGGER_PRESS, KeyBinding(glfw.KeySpace, 0))
+	m.AddAction("fire", CONTEXT_GAME, TRIGGER_HOLD)
+	if !m.HasAction("jump") || m.GetAction("fire") == nil {
+		t.Error("Missing actions.")
+	}
+	if !m.Bind("fire", MouseBinding(glfw.MouseButtonLeft)) || len(m.GetAction("fire").GetBindings()) != 1 {
+		t.Error("Binding should be added.")
+	}
+	if m.Bind("missing", MouseBinding(glfw.MouseButtonLeft)) {
+		t.Error("Missing action shouldn't be bound.")
+	}
+	m.AddAction("jump", CONTEXT_GAME, TRIGGER_DOUBLE_TAP)
+	names := m.GetActionNames()
+	if len(names) != 2 || names[0] != "jump" || names[1] != "fire" {
+		t.Errorf("Invalid names. '%v'.", names)
+	}
+	if m.GetAction("jump").GetTrigger() != TRIGGER_DOUBLE_TAP {
+		t.Error("Action should be replaced.")
+	}
+	m.RemoveAction("jump")
+	if m.HasAction("jump") || len(m.GetActionNames()) != 1 {
+		t.Error("Action should be removed.")
+	}
+}
+func TestActionTriggers(t *testing.T) {
+	m := NewActionMap()
+	m.AddAction("press", CONTEXT_GAME, TRIGGER_PRESS, KeyBinding(glfw.KeySpace, 0))
+	m.AddAction("hold", CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeySpace, 0)).SetHoldTime(50)
+	m.AddAction("release", CONTEXT_GAME, TRIGGER_RELEASE, KeyBinding(glfw.KeySpace, 0))
+	keys := store.NewGlfwKeyStore()
+	expect := func(step string, press, hold, release bool) {
+		if m.IsActive("press") != press || m.IsActive("hold") != hold || m.IsActive("release") != release {
+			t.Errorf("Invalid states after %s. press: %v, hold: %v, release: %v.", step, m.IsActive("press"), m.IsActive("hold"), m.IsActive("release"))
+		}
+	}
+	m.Update(20, keys, nil, nil)
+	expect("idle", false, false, false)
+	keys.Set(glfw.KeySpace, true)
+	m.Update(20, keys, nil, nil)
+	expect("press", true, false, false)
+	m.Update(20, keys, nil, nil)
+	expect("second frame", false, false, false)
+	m.Update(20, keys, nil, nil)
+	expect("hold time", false, true, false)
+	keys.Set(glfw.KeySpace, false)
+	m.Update(20, keys, nil, nil)
+	expect("release", false, false, true)
+	m.Update(20, keys, nil, nil)
+	expect("after release", false, false, false)
+}
+func TestActionDoubleTap(t *testing.T) {
+	m := NewActionMap()
+	m.SetDoubleTapTime(100)
+	m.AddAction("dash", CONTEXT_GAME, TRIGGER_DOUBLE_TAP, GamepadButtonBinding(glfw.ButtonA))
+	gamepad := store.NewGlfwGamepadStore()
+	tap := func(gap float64) bool {
+		gamepad.Set(glfw.ButtonA, true)
+		m.Update(gap, nil, nil, gamepad)
+		active := m.IsActive("dash")
+		gamepad.Set(glfw.ButtonA, false)
+		m.Update(10, nil, nil, gamepad)
+		return active
+	}
+	if tap(10) {
+		t.Error("First tap shouldn't trigger.")
+	}
+	if !tap(50) {
+		t.Error("Second tap should trigger.")
+	}
+	if tap(50) {
+		t.Error("Third tap should start a new double tap.")
+	}
+	if tap(200) {
+		t.Error("Slow tap shouldn't trigger.")
+	}
+	if !tap(20) {
+		t.Error("Fast tap after the slow one should trigger.")
+	}
+}
+func TestActionContexts(t *testing.T) {
+	m := NewActionMap()
+	m.AddAction("menu", CONTEXT_GLOBAL, TRIGGER_HOLD, KeyBinding(glfw.KeyEscape, 0))
+	m.AddAction("up", CONTEXT_MENU, TRIGGER_HOLD, KeyBinding(glfw.KeyUp, 0))
+	m.AddAction("forward", CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyUp, 0))
+	keys := store.NewGlfwKeyStore()
+	keys.Set(glfw.KeyUp, true)
+	keys.Set(glfw.KeyEscape, true)
+	m.Update(10, keys, nil, nil)
+	if !m.IsActive("forward") || m.IsActive("up") || !m.IsActive("menu") {
+		t.Error("Only the game and global actions should be active.")
+	}
+	m.SetContext(CONTEXT_MENU)
+	m.Update(10, keys, nil, nil)
+	if m.IsActive("forward") || !m.IsActive("up") || !m.IsActive("menu") {
+		t.Error("Only the menu and global actions should be active.")
+	}
+	m.EnableContext(CONTEXT_GAME)
+	if !m.IsContextEnabled(CONTEXT_GAME) || !m.IsContextEnabled(CONTEXT_MENU) || !m.IsContextEnabled(CONTEXT_GLOBAL) {
+		t.Error("Every context should be enabled.")
+	}
+	m.DisableContext(CONTEXT_MENU)
+	m.Update(10, keys, nil, nil)
+	if !m.IsActive("forward") || m.IsActive("up") {
+		t.Error("Only the game actions should be active.")
+	}
+}
+func TestActionValue(t *testing.T) {
+	m := DefaultActionMap()
+	keys := store.NewGlfwKeyStore()
+	gamepad := store.NewGlfwGamepadStore()
+	gamepad.SetDeadZone(0)
+	gamepad.SetAxis(glfw.AxisLeftY, -0.25)
+	m.Update(10, keys, nil, gamepad)
+	if v := m.Value(ACTION_FORWARD); v != 0.25 {
+		t.Errorf("Invalid analog value. '%f'.", v)
+	}
+	keys.Set(glfw.KeyW, true)
+	m.Update(10, keys, nil, gamepad)
+	if v := m.Value(ACTION_FORWARD); v != 1 {
+		t.Errorf("The max of the bindings should be used. '%f'.", v)
+	}
+	if m.Value(ACTION_BACK) != 0 || m.Value("missing") != 0 {
+		t.Error("Inactive actions should be 0.")
+	}
+}
+func TestDefaultFormActions(t *testing.T) {
+	m := DefaultActionMap()
+	m.SetContext(CONTEXT_MENU)
+	keys := store.NewGlfwKeyStore()
+	keys.Set(glfw.KeyTab, true)
+	m.Update(10, keys, nil, nil)
+	if !m.IsActive(ACTION_NEXT_FIELD) || m.IsActive(ACTION_PREV_FIELD) {
+		t.Error("Only the next field action should be active.")
+	}
+	keys.Set(glfw.KeyTab, false)
+	m.Update(10, keys, nil, nil)
+	keys.Set(glfw.KeyLeftShift, true)
+	keys.Set(glfw.KeyTab, true)
+	m.Update(10, keys, nil, nil)
+	if !m.IsActive(ACTION_PREV_FIELD) || m.IsActive(ACTION_NEXT_FIELD) {
+		t.Error("Only the previous field action should be active with Shift+Tab.")
+	}
+	// the more specific binding of a disabled context doesn't shadow the binding.
+	m.DisableContext(CONTEXT_MENU)
+	m.AddAction("globalNext", CONTEXT_GLOBAL, TRIGGER_HOLD, KeyBinding(glfw.KeyTab, 0))
+	m.Update(10, keys, nil, nil)
+	if !m.IsActive("globalNext") || m.IsActive(ACTION_PREV_FIELD) {
+		t.Error("The Tab binding should be active if the Shift+Tab binding is disabled.")
+	}
+	m.RemoveAction("globalNext")
+	m.EnableContext(CONTEXT_MENU)
+	keys = store.NewGlfwKeyStore()
+	keys.Set(glfw.KeyRightControl, true)
+	keys.Set(glfw.KeyZ, true)
+	m.Update(10, keys, nil, nil)
+	if !m.IsActive(ACTION_CANCEL) || m.IsActive(ACTION_SAVE) || m.IsActive(ACTION_RESET) {
+		t.Error("Only the cancel action should be active.")
+	}
+}
+func TestDefaultTextEditActions(t *testing.T) {
+	m := DefaultActionMap()
+	m.SetContext(CONTEXT_MENU)
+	keys := store.NewGlfwKeyStore()
+	keys.Set(glfw.KeyLeftShift, true)
+	keys.Set(glfw.KeyLeft, true)
+	m.Update(10, keys, nil, nil)
+	if !m.IsActive(ACTION_CURSOR_LEFT) || m.IsActive(ACTION_CURSOR_RIGHT) {
+		t.Error("Only the cursor left action should be active.")
+	}
+	keys = store.NewGlfwKeyStore()
+	keys.Set(glfw.KeyV, true)
+	m.Update(10, keys, nil, nil)
+	if m.IsActive(ACTION_PASTE) {
+		t.Error("The paste action shouldn't be active without control.")
+	}
+	keys.Set(glfw.KeyV, false)
+	m.Update(10, keys, nil, nil)
+	keys.Set(glfw.KeyLeftControl, true)
+	keys.Set(glfw.KeyV, true)
+	m.Update(10, keys, nil, nil)
+	if !m.IsActive(ACTION_PASTE) || m.IsActive(ACTION_COPY) || m.IsActive(ACTION_CUT) || m.IsActive(ACTION_SELECT_ALL) {
+		t.Error("Only the paste action should be active.")
+	}
+}
+func TestBlockActions(t *testing.T) {
+	m := NewActionMap()
+	m.AddAction("forward", CONTEXT_GAME, TRIGGER_HOLD, KeyBinding(glfw.KeyW, 0))
+	m.AddAction("fire", CONTEXT_GAME, TRIGGER_HOLD, MouseBinding(glfw.MouseButtonLeft))
+	m.AddAction("jump", CONTEXT_GAME, TRIGGER_HOLD, GamepadButtonBinding(glfw.ButtonA))
+	keys := store.NewGlfwKeyStore()
+	keys.Set(glfw.KeyW, true)
+	buttons := store.NewGlfwMouseStore()
+	buttons.Set(glfw.MouseButtonLeft, true)
+	gamepad := store.NewGlfwGamepadStore()
+	gamepad.Set(glfw.ButtonA, true)
+	m.Update(10, keys, buttons, gamepad)
+	if BlockActions(m, false, false) != m || BlockActions(nil, true, true) != nil {
+		t.Error("The not blocked and the missing maps should be returned.")
+	}
+	keyboard := BlockActions(m, true, false)
+	if keyboard.IsActive("forward") || keyboard.Value("forward") != 0 || !keyboard.HasAction("forward") {
+		t.Error("The keyboard action should be hidden.")
+	}
+	if !keyboard.IsActive("fire") || keyboard.Value("jump") != 1 {
+		t.Error("The other actions should be visible.")
+	}
+	both := BlockActions(keyboard, false, true)
+	if both.IsActive("forward") || both.IsActive("fire") || !both.IsActive("jump") {
+		t.Error("The keyboard and the mouse actions should be hidden.")
+	}
+	if !keyboard.IsEnabled("forward") || keyboard.IsEnabled("missing") {
+		t.Error("The hidden actions should be enabled.")
+	}
+	m.SetContext(CONTEXT_MENU)
+	if m.IsEnabled("forward") || !m.HasAction("forward") {
+		t.Error("The actions of the disabled context shouldn't be enabled.")
+	}
+	if keyboard.IsActive("missing") || keyboard.Value("missing") != 0 {
+		t.Error("The missing action shouldn't be active.")
+	}
+}
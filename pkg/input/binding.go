@@ -0,0 +1,379 @@
+package input
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// The input devices of the bindings.
+	DEVICE_KEYBOARD = iota
+	DEVICE_MOUSE
+	DEVICE_GAMEPAD_BUTTON
+	DEVICE_GAMEPAD_AXIS
+)
+
+var (
+	ErrorInvalidBinding = errors.New("Invalid binding")
+)
+
+// Binding connects an input to an action. The Code is a glfw.Key, glfw.MouseButton,
+// glfw.GamepadButton or glfw.GamepadAxis depending on the Device. The keyboard bindings
+// could require modifiers, the axis bindings have direction (1 or -1).
+type Binding struct {
+	Device    int
+	Code      int
+	Mods      glfw.ModifierKey
+	Direction float32
+}
+
+// KeyBinding returns a keyboard binding with the given modifiers.
+func KeyBinding(key glfw.Key, mods glfw.ModifierKey) Binding {
+	return Binding{Device: DEVICE_KEYBOARD, Code: int(key), Mods: mods}
+}
+
+// MouseBinding returns a mouse button binding.
+func MouseBinding(button glfw.MouseButton) Binding {
+	return Binding{Device: DEVICE_MOUSE, Code: int(button)}
+}
+
+// GamepadButtonBinding returns a gamepad button binding.
+func GamepadButtonBinding(button glfw.GamepadButton) Binding {
+	return Binding{Device: DEVICE_GAMEPAD_BUTTON, Code: int(button)}
+}
+
+// GamepadAxisBinding returns a gamepad axis binding. The direction is 1 or -1.
+func GamepadAxisBinding(axis glfw.GamepadAxis, direction float32) Binding {
+	return Binding{Device: DEVICE_GAMEPAD_AXIS, Code: int(axis), Direction: direction}
+}
+
+// modsDown returns true if the required modifiers of the binding are pressed.
+func (b Binding) modsDown(keyStore interfaces.RoKeyStore) bool {
+	for _, m := range modifiers {
+		if b.Mods&m.mod != 0 && !keyStore.Get(m.left) && !keyStore.Get(m.right) {
+			return false
+		}
+	}
+	return true
+}
+
+// shadowedBy returns true if one of the given bindings has the same key with more
+// modifiers. The bindings without modifiers don't reject the held modifiers, but the
+// more specific binding of the same key wins.
+func (b Binding) shadowedBy(bindings []Binding) bool {
+	if b.Device != DEVICE_KEYBOARD {
+		return false
+	}
+	for _, other := range bindings {
+		if other.Device == DEVICE_KEYBOARD && other.Code == b.Code && other.Mods != b.Mods && other.Mods&b.Mods == b.Mods {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the value of the binding in the [0-1] interval. The digital
+// inputs return 0 or 1, the axes return the value in the direction of the binding.
+// The missing stores are handled as released inputs.
+func (b Binding) Value(keyStore interfaces.RoKeyStore, buttonStore interfaces.RoButtonStore, gamepad interfaces.RoGamepadStore) float32 {
+	switch b.Device {
+	case DEVICE_KEYBOARD:
+		if keyStore != nil && keyStore.Get(glfw.Key(b.Code)) && b.modsDown(keyStore) {
+			return 1.0
+		}
+		break
+	case DEVICE_MOUSE:
+		if buttonStore != nil && buttonStore.Get(glfw.MouseButton(b.Code)) {
+			return 1.0
+		}
+		break
+	case DEVICE_GAMEPAD_BUTTON:
+		if gamepad != nil && gamepad.Get(glfw.GamepadButton(b.Code)) {
+			return 1.0
+		}
+		break
+	case DEVICE_GAMEPAD_AXIS:
+		if gamepad != nil {
+			if v := gamepad.GetAxis(glfw.GamepadAxis(b.Code)) * b.Direction; v > 0 {
+				return v
+			}
+		}
+		break
+	}
+	return 0.0
+}
+
+// String returns the text representation of the binding, like "key:ctrl+W", "mouse:Left",
+// "button:A" or "axis:LeftY-". It could be parsed with the ParseBinding function.
+func (b Binding) String() string {
+	switch b.Device {
+	case DEVICE_KEYBOARD:
+		prefix := ""
+		for _, m := range modifiers {
+			if b.Mods&m.mod != 0 {
+				prefix += m.name + "+"
+			}
+		}
+		return "key:" + prefix + nameOf(keyNames, b.Code)
+	case DEVICE_MOUSE:
+		return "mouse:" + nameOf(mouseButtonNames, b.Code)
+	case DEVICE_GAMEPAD_BUTTON:
+		return "button:" + nameOf(gamepadButtonNames, b.Code)
+	case DEVICE_GAMEPAD_AXIS:
+		direction := "+"
+		if b.Direction < 0 {
+			direction = "-"
+		}
+		return "axis:" + nameOf(gamepadAxisNames, b.Code) + direction
+	}
+	return ""
+}
+
+// ParseBinding returns the binding of the given text representation. In case of
+// unknown device, input or modifier name, it returns ErrorInvalidBinding.
+func ParseBinding(s string) (Binding, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return Binding{}, ErrorInvalidBinding
+	}
+	switch strings.ToLower(parts[0]) {
+	case "key":
+		names := strings.Split(parts[1], "+")
+		var mods glfw.ModifierKey
+		for _, name := range names[:len(names)-1] {
+			found := false
+			for _, m := range modifiers {
+				if strings.EqualFold(m.name, name) {
+					mods |= m.mod
+					found = true
+				}
+			}
+			if !found {
+				return Binding{}, ErrorInvalidBinding
+			}
+		}
+		code, ok := codeOf(keyNames, names[len(names)-1])
+		if !ok {
+			return Binding{}, ErrorInvalidBinding
+		}
+		return KeyBinding(glfw.Key(code), mods), nil
+	case "mouse":
+		if code, ok := codeOf(mouseButtonNames, parts[1]); ok {
+			return MouseBinding(glfw.MouseButton(code)), nil
+		}
+		break
+	case "button":
+		if code, ok := codeOf(gamepadButtonNames, parts[1]); ok {
+			return GamepadButtonBinding(glfw.GamepadButton(code)), nil
+		}
+		break
+	case "axis":
+		name := parts[1]
+		direction := float32(1.0)
+		switch name[len(name)-1] {
+		case '-':
+			direction = -1.0
+			name = name[:len(name)-1]
+			break
+		case '+':
+			name = name[:len(name)-1]
+			break
+		}
+		if code, ok := codeOf(gamepadAxisNames, name); ok {
+			return GamepadAxisBinding(glfw.GamepadAxis(code), direction), nil
+		}
+		break
+	}
+	return Binding{}, ErrorInvalidBinding
+}
+
+// PressedKeyBinding returns the keyboard binding of the first pressed key with the held
+// modifiers. The modifier keys alone are not bindings. If no key is pressed, the second
+// return value is false.
+func PressedKeyBinding(keyStore interfaces.RoKeyStore) (Binding, bool) {
+	var mods glfw.ModifierKey
+	for _, m := range modifiers {
+		if keyStore.Get(m.left) || keyStore.Get(m.right) {
+			mods |= m.mod
+		}
+	}
+	for key := glfw.KeySpace; key <= glfw.KeyLast; key++ {
+		if isModifierKey(key) || !keyStore.Get(key) {
+			continue
+		}
+		return KeyBinding(key, mods), true
+	}
+	return Binding{}, false
+}
+func isModifierKey(key glfw.Key) bool {
+	for _, m := range modifiers {
+		if key == m.left || key == m.right {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatBindings returns the comma separated text representation of the bindings.
+func FormatBindings(bindings []Binding) string {
+	names := make([]string, len(bindings))
+	for i, b := range bindings {
+		names[i] = b.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseBindings parses the comma separated list of bindings. The empty string is an empty list.
+func ParseBindings(s string) ([]Binding, error) {
+	bindings := []Binding{}
+	if strings.TrimSpace(s) == "" {
+		return bindings, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		b, err := ParseBinding(part)
+		if err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, b)
+	}
+	return bindings, nil
+}
+
+type modifier struct {
+	mod   glfw.ModifierKey
+	name  string
+	left  glfw.Key
+	right glfw.Key
+}
+
+// the modifiers are checked in the key store, because it doesn't store the modifier flags.
+var modifiers = []modifier{
+	{glfw.ModControl, "ctrl", glfw.KeyLeftControl, glfw.KeyRightControl},
+	{glfw.ModShift, "shift", glfw.KeyLeftShift, glfw.KeyRightShift},
+	{glfw.ModAlt, "alt", glfw.KeyLeftAlt, glfw.KeyRightAlt},
+	{glfw.ModSuper, "super", glfw.KeyLeftSuper, glfw.KeyRightSuper},
+}
+
+var (
+	keyNames = map[int]string{
+		int(glfw.KeySpace):        "Space",
+		int(glfw.KeyApostrophe):   "Apostrophe",
+		int(glfw.KeyComma):        "Comma",
+		int(glfw.KeyMinus):        "Minus",
+		int(glfw.KeyPeriod):       "Period",
+		int(glfw.KeySlash):        "Slash",
+		int(glfw.KeySemicolon):    "Semicolon",
+		int(glfw.KeyEqual):        "Equal",
+		int(glfw.KeyLeftBracket):  "LeftBracket",
+		int(glfw.KeyBackslash):    "Backslash",
+		int(glfw.KeyRightBracket): "RightBracket",
+		int(glfw.KeyGraveAccent):  "GraveAccent",
+		int(glfw.KeyEscape):       "Escape",
+		int(glfw.KeyEnter):        "Enter",
+		int(glfw.KeyTab):          "Tab",
+		int(glfw.KeyBackspace):    "Backspace",
+		int(glfw.KeyInsert):       "Insert",
+		int(glfw.KeyDelete):       "Delete",
+		int(glfw.KeyRight):        "Right",
+		int(glfw.KeyLeft):         "Left",
+		int(glfw.KeyDown):         "Down",
+		int(glfw.KeyUp):           "Up",
+		int(glfw.KeyPageUp):       "PageUp",
+		int(glfw.KeyPageDown):     "PageDown",
+		int(glfw.KeyHome):         "Home",
+		int(glfw.KeyEnd):          "End",
+		int(glfw.KeyCapsLock):     "CapsLock",
+		int(glfw.KeyPrintScreen):  "PrintScreen",
+		int(glfw.KeyPause):        "Pause",
+		int(glfw.KeyKPDecimal):    "KPDecimal",
+		int(glfw.KeyKPDivide):     "KPDivide",
+		int(glfw.KeyKPMultiply):   "KPMultiply",
+		int(glfw.KeyKPSubtract):   "KPSubtract",
+		int(glfw.KeyKPAdd):        "KPAdd",
+		int(glfw.KeyKPEnter):      "KPEnter",
+		int(glfw.KeyLeftShift):    "LeftShift",
+		int(glfw.KeyLeftControl):  "LeftControl",
+		int(glfw.KeyLeftAlt):      "LeftAlt",
+		int(glfw.KeyLeftSuper):    "LeftSuper",
+		int(glfw.KeyRightShift):   "RightShift",
+		int(glfw.KeyRightControl): "RightControl",
+		int(glfw.KeyRightAlt):     "RightAlt",
+		int(glfw.KeyRightSuper):   "RightSuper",
+	}
+	mouseButtonNames = map[int]string{
+		int(glfw.MouseButtonLeft):   "Left",
+		int(glfw.MouseButtonRight):  "Right",
+		int(glfw.MouseButtonMiddle): "Middle",
+		int(glfw.MouseButton4):      "Button4",
+		int(glfw.MouseButton5):      "Button5",
+		int(glfw.MouseButton6):      "Button6",
+		int(glfw.MouseButton7):      "Button7",
+		int(glfw.MouseButton8):      "Button8",
+	}
+	gamepadButtonNames = map[int]string{
+		int(glfw.ButtonA):           "A",
+		int(glfw.ButtonB):           "B",
+		int(glfw.ButtonX):           "X",
+		int(glfw.ButtonY):           "Y",
+		int(glfw.ButtonLeftBumper):  "LeftBumper",
+		int(glfw.ButtonRightBumper): "RightBumper",
+		int(glfw.ButtonBack):        "Back",
+		int(glfw.ButtonStart):       "Start",
+		int(glfw.ButtonGuide):       "Guide",
+		int(glfw.ButtonLeftThumb):   "LeftThumb",
+		int(glfw.ButtonRightThumb):  "RightThumb",
+		int(glfw.ButtonDpadUp):      "DpadUp",
+		int(glfw.ButtonDpadRight):   "DpadRight",
+		int(glfw.ButtonDpadDown):    "DpadDown",
+		int(glfw.ButtonDpadLeft):    "DpadLeft",
+	}
+	gamepadAxisNames = map[int]string{
+		int(glfw.AxisLeftX):        "LeftX",
+		int(glfw.AxisLeftY):        "LeftY",
+		int(glfw.AxisRightX):       "RightX",
+		int(glfw.AxisRightY):       "RightY",
+		int(glfw.AxisLeftTrigger):  "LeftTrigger",
+		int(glfw.AxisRightTrigger): "RightTrigger",
+	}
+)
+
+func init() {
+	// the letters, digits, function and keypad digit keys are continuous.
+	for i := 0; i < 26; i++ {
+		keyNames[int(glfw.KeyA)+i] = string(rune('A' + i))
+	}
+	for i := 0; i < 10; i++ {
+		keyNames[int(glfw.Key0)+i] = strconv.Itoa(i)
+		keyNames[int(glfw.KeyKP0)+i] = "KP" + strconv.Itoa(i)
+	}
+	for i := 0; i < 25; i++ {
+		keyNames[int(glfw.KeyF1)+i] = "F" + strconv.Itoa(i+1)
+	}
+}
+
+// nameOf returns the name of the code. The unnamed codes are returned as numbers.
+func nameOf(names map[int]string, code int) string {
+	if name, ok := names[code]; ok {
+		return name
+	}
+	return strconv.Itoa(code)
+}
+
+// codeOf returns the code of the name. The names are case insensitive, the numbers are accepted as codes.
+func codeOf(names map[int]string, name string) (int, bool) {
+	name = strings.TrimSpace(name)
+	for code, n := range names {
+		if strings.EqualFold(n, name) {
+			return code, true
+		}
+	}
+	if code, err := strconv.Atoi(name); err == nil {
+		return code, true
+	}
+	return 0, false
+}
@@ -0,0 +1,124 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/store"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func TestBindingString(t *testing.T) {
+	testData := []struct {
+		binding  Binding
+		expected string
+	}{
+		{KeyBinding(glfw.KeyW, 0), "key:W"},
+		{KeyBinding(glfw.Key1, glfw.ModControl|glfw.ModShift), "key:ctrl+shift+1"},
+		{KeyBinding(glfw.KeyF12, 0), "key:F12"},
+		{KeyBinding(glfw.KeyEscape, glfw.ModAlt), "key:alt+Escape"},
+		{MouseBinding(glfw.MouseButtonLeft), "mouse:Left"},
+		{GamepadButtonBinding(glfw.ButtonDpadUp), "button:DpadUp"},
+		{GamepadAxisBinding(glfw.AxisLeftY, -1), "axis:LeftY-"},
+		{GamepadAxisBinding(glfw.AxisRightTrigger, 1), "axis:RightTrigger+"},
+	}
+	for _, tt := range testData {
+		if s := tt.binding.String(); s != tt.expected {
+			t.Errorf("Invalid string. Instead of '%s', it is '%s'.", tt.expected, s)
+		}
+		parsed, err := ParseBinding(tt.expected)
+		if err != nil {
+			t.Errorf("Parse of '%s' failed. '%s'.", tt.expected, err.Error())
+		}
+		if parsed != tt.binding {
+			t.Errorf("Invalid parsed binding. Instead of '%#v', it is '%#v'.", tt.binding, parsed)
+		}
+	}
+}
+func TestParseBindingInvalid(t *testing.T) {
+	for _, s := range []string{"", "W", "key:", "key:NoSuchKey", "key:hyper+W", "joy:A", "button:Z", "axis:Middle-"} {
+		if _, err := ParseBinding(s); err != ErrorInvalidBinding {
+			t.Errorf("Missing error for '%s'.", s)
+		}
+	}
+	// case insensitive names and axis without sign.
+	b, err := ParseBinding(" KEY:Ctrl+w ")
+	if err != nil || b != KeyBinding(glfw.KeyW, glfw.ModControl) {
+		t.Errorf("Invalid binding. '%#v', '%v'.", b, err)
+	}
+	b, err = ParseBinding("axis:leftx")
+	if err != nil || b != GamepadAxisBinding(glfw.AxisLeftX, 1) {
+		t.Errorf("Invalid binding. '%#v', '%v'.", b, err)
+	}
+}
+func TestParseBindings(t *testing.T) {
+	bindings, err := ParseBindings("key:W, button:A,axis:LeftY-")
+	if err != nil {
+		t.Fatalf("Parse failed. '%s'.", err.Error())
+	}
+	if len(bindings) != 3 {
+		t.Fatalf("Invalid number of bindings. '%d'.", len(bindings))
+	}
+	if FormatBindings(bindings) != "key:W, button:A, axis:LeftY-" {
+		t.Errorf("Invalid format. '%s'.", FormatBindings(bindings))
+	}
+	if bindings, err := ParseBindings(" "); err != nil || len(bindings) != 0 {
+		t.Error("Empty string should be an empty list.")
+	}
+	if _, err := ParseBindings("key:W, wrong"); err != ErrorInvalidBinding {
+		t.Error("Missing error.")
+	}
+}
+func TestBindingValue(t *testing.T) {
+	keys := store.NewGlfwKeyStore()
+	buttons := store.NewGlfwMouseStore()
+	gamepad := store.NewGlfwGamepadStore()
+	gamepad.SetDeadZone(0)
+
+	combo := KeyBinding(glfw.KeyS, glfw.ModControl)
+	keys.Set(glfw.KeyS, true)
+	if combo.Value(keys, buttons, gamepad) != 0 {
+		t.Error("Combo shouldn't be active without modifier.")
+	}
+	keys.Set(glfw.KeyRightControl, true)
+	if combo.Value(keys, buttons, gamepad) != 1 {
+		t.Error("Combo should be active with modifier.")
+	}
+	if MouseBinding(glfw.MouseButtonRight).Value(keys, buttons, gamepad) != 0 {
+		t.Error("Mouse button shouldn't be active.")
+	}
+	buttons.Set(glfw.MouseButtonRight, true)
+	if MouseBinding(glfw.MouseButtonRight).Value(keys, buttons, gamepad) != 1 {
+		t.Error("Mouse button should be active.")
+	}
+	gamepad.Set(glfw.ButtonB, true)
+	if GamepadButtonBinding(glfw.ButtonB).Value(keys, buttons, gamepad) != 1 {
+		t.Error("Gamepad button should be active.")
+	}
+	gamepad.SetAxis(glfw.AxisLeftY, -0.5)
+	if v := GamepadAxisBinding(glfw.AxisLeftY, -1).Value(keys, buttons, gamepad); v != 0.5 {
+		t.Errorf("Invalid axis value. '%f'.", v)
+	}
+	if v := GamepadAxisBinding(glfw.AxisLeftY, 1).Value(keys, buttons, gamepad); v != 0 {
+		t.Errorf("Invalid opposite axis value. '%f'.", v)
+	}
+	if GamepadButtonBinding(glfw.ButtonB).Value(nil, nil, nil) != 0 {
+		t.Error("Missing stores should be released.")
+	}
+}
+func TestPressedKeyBinding(t *testing.T) {
+	keys := store.NewGlfwKeyStore()
+	if _, ok := PressedKeyBinding(keys); ok {
+		t.Error("Without pressed key it shouldn't return binding.")
+	}
+	keys.Set(glfw.KeyLeftControl, true)
+	keys.Set(glfw.KeyLeftShift, true)
+	if _, ok := PressedKeyBinding(keys); ok {
+		t.Error("The modifiers alone shouldn't be binding.")
+	}
+	keys.Set(glfw.KeyF5, true)
+	b, ok := PressedKeyBinding(keys)
+	if !ok || b != KeyBinding(glfw.KeyF5, glfw.ModControl|glfw.ModShift) {
+		t.Errorf("Invalid binding '%s'.", b.String())
+	}
+}
@@ -0,0 +1,58 @@
+package input
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/config"
+)
+
+const (
+	// The prefix of the config keys of the actions.
+	CONFIG_KEY_PREFIX = "action_"
+)
+
+// Config returns the bindings of the actions as text config items. The config could be
+// edited with a form screen (the order of the items is returned by ConfigOrder), and
+// it could be applied with the LoadConfig function. The items don't have validator,
+// because the form validates the value after every typed character, the LoadConfig
+// validates the whole value.
+func (m *ActionMap) Config() config.Config {
+	c := config.New()
+	for _, name := range m.order {
+		c.AddConfig(CONFIG_KEY_PREFIX+name, name, "The bindings of the '"+name+"' action. Comma separated list, like 'key:ctrl+W, mouse:Left, button:A, axis:LeftY-'.", FormatBindings(m.actions[name].bindings), nil)
+	}
+	return c
+}
+
+// ConfigOrder returns the keys of the config items in the order of the actions.
+func (m *ActionMap) ConfigOrder() []string {
+	keys := make([]string, len(m.order))
+	for i, name := range m.order {
+		keys[i] = CONFIG_KEY_PREFIX + name
+	}
+	return keys
+}
+
+// LoadConfig updates the bindings of the actions from the current values of the config.
+// The missing items are skipped. In case of invalid value, it returns the error and the
+// bindings are not updated.
+func (m *ActionMap) LoadConfig(c config.Config) error {
+	bindings := make(map[string][]Binding)
+	for _, name := range m.order {
+		item, ok := c[CONFIG_KEY_PREFIX+name]
+		if !ok {
+			continue
+		}
+		value, ok := item.GetCurrentValue().(string)
+		if !ok {
+			return config.InvalidType
+		}
+		b, err := ParseBindings(value)
+		if err != nil {
+			return err
+		}
+		bindings[name] = b
+	}
+	for name, b := range bindings {
+		m.SetBindings(name, b)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/config"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func TestConfig(t *testing.T) {
+	m := NewActionMap()
+	m.AddAction("jump", CONTEXT_GAME, TRIGGER_PRESS, KeyBinding(glfw.KeySpace, 0), GamepadButtonBinding(glfw.ButtonA))
+	m.AddAction("fire", CONTEXT_GAME, TRIGGER_HOLD, MouseBinding(glfw.MouseButtonLeft))
+	c := m.Config()
+	order := m.ConfigOrder()
+	if len(order) != 2 || order[0] != CONFIG_KEY_PREFIX+"jump" {
+		t.Errorf("Invalid order. '%v'.", order)
+	}
+	if c[order[0]].GetCurrentValue() != "key:Space, button:A" {
+		t.Errorf("Invalid config value. '%v'.", c[order[0]].GetCurrentValue())
+	}
+	c.SetCurrentValue(order[0], "key:ctrl+J")
+	c.SetCurrentValue(order[1], "")
+	if err := m.LoadConfig(c); err != nil {
+		t.Fatalf("Load failed. '%s'.", err.Error())
+	}
+	if b := m.GetAction("jump").GetBindings(); len(b) != 1 || b[0] != KeyBinding(glfw.KeyJ, glfw.ModControl) {
+		t.Errorf("Invalid loaded bindings. '%v'.", b)
+	}
+	if len(m.GetAction("fire").GetBindings()) != 0 {
+		t.Error("Fire should be unbound.")
+	}
+}
+func TestLoadConfigInvalid(t *testing.T) {
+	m := NewActionMap()
+	m.AddAction("jump", CONTEXT_GAME, TRIGGER_PRESS, KeyBinding(glfw.KeySpace, 0))
+	m.AddAction("fire", CONTEXT_GAME, TRIGGER_HOLD, MouseBinding(glfw.MouseButtonLeft))
+	c := m.Config()
+	c.SetCurrentValue(CONFIG_KEY_PREFIX+"jump", "key:J")
+	c.SetCurrentValue(CONFIG_KEY_PREFIX+"fire", "key:NoSuchKey")
+	if err := m.LoadConfig(c); err != ErrorInvalidBinding {
+		t.Errorf("Missing error. '%v'.", err)
+	}
+	if m.GetAction("jump").GetBindings()[0] != KeyBinding(glfw.KeySpace, 0) {
+		t.Error("Bindings shouldn't be updated after error.")
+	}
+	wrongType := config.New()
+	wrongType.AddConfig(CONFIG_KEY_PREFIX+"jump", "jump", "", 3, nil)
+	if err := m.LoadConfig(wrongType); err != config.InvalidType {
+		t.Errorf("Missing type error. '%v'.", err)
+	}
+}
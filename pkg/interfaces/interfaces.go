@@ -49,9 +49,12 @@ type SpotLight interface {
 type GLWrapper interface {
 	GenVertexArrays() uint32
 	GenBuffers() uint32
+	DeleteVertexArrays(vao uint32)
+	DeleteBuffers(buffer uint32)
 	BindVertexArray(vao uint32)
 	BindBuffer(bufferType, vbo uint32)
 	ArrayBufferData(bufferData []float32)
+	ArrayBufferSubData(offset int, bufferData []float32)
 	ElementBufferData(bufferData []uint32)
 	VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer)
 	ActiveTexture(id uint32)
@@ -90,6 +93,31 @@ type GLWrapper interface {
 	DepthFunc(xfunc uint32)
 	Viewport(x int32, y int32, width int32, height int32)
 	BlendFunc(sfactor uint32, dfactor uint32)
+	DepthMask(flag bool)
+	PatchParameteri(pname uint32, value int32)
+	DrawPatchElements(count int32)
+	DispatchCompute(numGroupsX, numGroupsY, numGroupsZ uint32)
+	ComputeShaderSupported() bool
+	MemoryBarrier(barriers uint32)
+	BindBufferBase(target uint32, index uint32, buffer uint32)
+	StorageBufferData(bufferData []float32)
+	GetStorageBufferData(bufferData []float32)
+	TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer)
+	DeleteTextures(texture uint32)
+	GenFramebuffers() uint32
+	BindFramebuffer(target uint32, framebuffer uint32)
+	FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32)
+	CheckFramebufferStatus(target uint32) uint32
+	DeleteFramebuffers(framebuffer uint32)
+	GenRenderbuffers() uint32
+	BindRenderbuffer(target uint32, renderbuffer uint32)
+	RenderbufferStorage(target uint32, internalformat uint32, width int32, height int32)
+	FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32)
+	DeleteRenderbuffers(renderbuffer uint32)
+	VertexAttribDivisor(index uint32, divisor uint32)
+	DrawTriangleElementsInstanced(count int32, instanceCount int32)
+	Disable(cap uint32)
+	Scissor(x int32, y int32, width int32, height int32)
 }
 
 type Mesh interface {
@@ -141,15 +169,34 @@ type FormItem interface {
 type CharFormItem interface {
 	FormItem
 	DeleteLastCharacter()
+	AddCursor()
 	DeleteCursor()
 	GetCursorInitialPosition() mgl32.Vec3
 	CharCallback(rune, float32)
 	GetTarget() Mesh
+	DeleteNextCharacter()
+	DeleteSelection()
+	MoveCursor(int, bool)
+	MoveCursorToStart(bool)
+	MoveCursorToEnd(bool)
+	SetCursorFromPosition(float32, bool)
+	SelectAll()
+	GetCursorIndex() int
+	GetSelection() (int, int)
+	GetSelectedText() string
+	SetMaxLength(int)
+	GetMaxLength() int
+	SetPlaceholder(string)
+	GetPlaceholder() string
 }
 type VectorFormItem interface {
 	CharFormItem
 	SetTarget(int)
 }
+type Clipboard interface {
+	GetClipboardString() string
+	SetClipboardString(string)
+}
 
 type KeyStore interface {
 	Get(glfw.Key) bool
@@ -165,6 +212,26 @@ type ButtonStore interface {
 type RoButtonStore interface {
 	Get(glfw.MouseButton) bool
 }
+type GamepadStore interface {
+	Get(glfw.GamepadButton) bool
+	Set(glfw.GamepadButton, bool)
+	GetAxis(glfw.GamepadAxis) float32
+	SetAxis(glfw.GamepadAxis, float32)
+	SetState(*glfw.GamepadState)
+	SetJoystickState([]float32, []glfw.Action)
+	SetDeadZone(float32)
+	Clear()
+}
+type RoGamepadStore interface {
+	Get(glfw.GamepadButton) bool
+	GetAxis(glfw.GamepadAxis) float32
+}
+type RoActionMap interface {
+	HasAction(string) bool
+	IsEnabled(string) bool
+	IsActive(string) bool
+	Value(string) float32
+}
 
 type Camera interface {
 	Log() string
@@ -198,3 +265,22 @@ type Screen interface {
 	SetWindowSize(float32, float32)
 	SetWrapper(GLWrapper)
 }
+type GamepadScreen interface {
+	SetGamepadStore(RoGamepadStore)
+}
+type ActionScreen interface {
+	SetActionMap(RoActionMap)
+}
+type InputScreen interface {
+	WantsPointer() bool
+	WantsKeyboard() bool
+}
+type CaptureScreen interface {
+	IsCapturingKey() bool
+}
+type LifecycleScreen interface {
+	OnEnter()
+	OnExit()
+	OnPause()
+	OnResume()
+}
@@ -0,0 +1,270 @@
+package mesh
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The billboard faces the camera from every direction.
+	BILLBOARD_SPHERICAL = 1
+	// The billboard rotates only around its Y axis, it stays upright. It could be used for trees.
+	BILLBOARD_CYLINDRICAL = 2
+)
+
+type BillboardMesh struct {
+	Mesh
+	Textures texture.Textures
+	Color    []mgl32.Vec3
+	ebo      uint32
+	mode     int32
+	width    float32
+	height   float32
+	// the offset of the quad center from the position of the mesh, in the plane of the quad.
+	offset mgl32.Vec2
+	// texture atlas parameters. The frames are counted from the top left corner, row by row.
+	columns    int
+	rows       int
+	frame      int
+	frameTime  float64
+	frameDelta float64
+	animated   bool
+	// the texture region, that is used instead of the atlas frames, if it is set.
+	regionMin mgl32.Vec2
+	regionMax mgl32.Vec2
+	regionSet bool
+}
+
+// NewBillboardMesh gets the size of the quad, the textures, colors, glwrapper as inputs
+// and makes the necessary setup for a camera facing textured colored mesh before
+// returning it. The quad is in the X-Y plane. The default mode is spherical. The vbo, vao, ebo is also set.
+func NewBillboardMesh(width, height float32, t texture.Textures, color []mgl32.Vec3, wrapper interfaces.GLWrapper) *BillboardMesh {
+	mesh := &BillboardMesh{
+		Mesh: Mesh{
+			Indices: []uint32{0, 1, 2, 0, 2, 3},
+
+			position:  mgl32.Vec3{0, 0, 0},
+			direction: mgl32.Vec3{0, 0, 0},
+			velocity:  0,
+			yaw:       0,
+			pitch:     0,
+			roll:      0,
+			scale:     mgl32.Vec3{1, 1, 1},
+			wrapper:   wrapper,
+			parentSet: false,
+
+			boundingObjectSet: false,
+		},
+		Textures: t,
+		Color:    color,
+		mode:     BILLBOARD_SPHERICAL,
+		width:    width,
+		height:   height,
+		offset:   mgl32.Vec2{0, 0},
+		columns:  1,
+		rows:     1,
+		frame:    0,
+		animated: false,
+	}
+	mesh.Vertices = mesh.quadVertices()
+	mesh.setup()
+	return mesh
+}
+
+// quadVertices returns the vertices of the quad. The texture coordinates are
+// calculated from the current frame of the atlas.
+func (m *BillboardMesh) quadVertices() vertex.Vertices {
+	frameWidth := 1.0 / float32(m.columns)
+	frameHeight := 1.0 / float32(m.rows)
+	u := float32(m.frame%m.columns) * frameWidth
+	v := float32(m.frame/m.columns) * frameHeight
+	if m.regionSet {
+		u, v = m.regionMin.X(), m.regionMin.Y()
+		frameWidth = m.regionMax.X() - u
+		frameHeight = m.regionMax.Y() - v
+	}
+	points := [4]mgl32.Vec3{
+		mgl32.Vec3{m.offset.X() - m.width/2, m.offset.Y() - m.height/2, 0},
+		mgl32.Vec3{m.offset.X() + m.width/2, m.offset.Y() - m.height/2, 0},
+		mgl32.Vec3{m.offset.X() + m.width/2, m.offset.Y() + m.height/2, 0},
+		mgl32.Vec3{m.offset.X() - m.width/2, m.offset.Y() + m.height/2, 0},
+	}
+	textureCoords := [4]mgl32.Vec2{
+		{u, v + frameHeight},
+		{u + frameWidth, v + frameHeight},
+		{u + frameWidth, v},
+		{u, v},
+	}
+	colors := m.Color
+	if len(colors) == 0 {
+		colors = []mgl32.Vec3{mgl32.Vec3{1, 1, 1}}
+	}
+	var vertices vertex.Vertices
+	for i := 0; i < 4; i++ {
+		vertices = append(vertices, vertex.Vertex{
+			Position:  points[i],
+			Normal:    mgl32.Vec3{0, 0, 1},
+			TexCoords: textureCoords[i],
+			Color:     colors[i%len(colors)],
+		})
+	}
+	return vertices
+}
+func (m *BillboardMesh) setup() {
+	m.vao = m.wrapper.GenVertexArrays()
+	m.vbo = m.wrapper.GenBuffers()
+	m.ebo = m.wrapper.GenBuffers()
+
+	m.wrapper.BindVertexArray(m.vao)
+
+	m.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, m.vbo)
+	m.wrapper.ArrayBufferData(m.Vertices.Get(vertex.POSITION_COLOR_TEXCOORD))
+
+	m.wrapper.BindBuffer(glwrapper.ELEMENT_ARRAY_BUFFER, m.ebo)
+	m.wrapper.ElementBufferData(m.Indices)
+
+	// setup coordinates
+	m.wrapper.VertexAttribPointer(0, 3, glwrapper.FLOAT, false, 4*8, m.wrapper.PtrOffset(0))
+	// setup color
+	m.wrapper.VertexAttribPointer(1, 3, glwrapper.FLOAT, false, 4*8, m.wrapper.PtrOffset(4*3))
+	// setup texture position
+	m.wrapper.VertexAttribPointer(2, 2, glwrapper.FLOAT, false, 4*8, m.wrapper.PtrOffset(4*6))
+
+	// close
+	m.wrapper.BindVertexArray(0)
+}
+
+// updateVertices recalculates the vertices and uploads them to the vbo.
+func (m *BillboardMesh) updateVertices() {
+	m.Vertices = m.quadVertices()
+	m.wrapper.BindVertexArray(m.vao)
+	m.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, m.vbo)
+	m.wrapper.ArrayBufferData(m.Vertices.Get(vertex.POSITION_COLOR_TEXCOORD))
+	m.wrapper.BindVertexArray(0)
+}
+
+// SetMode updates the billboard mode. It could be BILLBOARD_SPHERICAL or BILLBOARD_CYLINDRICAL.
+func (m *BillboardMesh) SetMode(mode int32) {
+	m.mode = mode
+}
+
+// GetMode returns the billboard mode.
+func (m *BillboardMesh) GetMode() int32 {
+	return m.mode
+}
+
+// SetOffset updates the offset of the quad center in the plane of the quad.
+// It could be used for placing more billboards next to each other, like the characters of a text.
+func (m *BillboardMesh) SetOffset(o mgl32.Vec2) {
+	m.offset = o
+	m.updateVertices()
+}
+
+// GetOffset returns the offset of the quad center.
+func (m *BillboardMesh) GetOffset() mgl32.Vec2 {
+	return m.offset
+}
+
+// SetAtlas sets the layout of the texture atlas. The texture contains columns*rows
+// frames with the same size. The current frame is set to the first one.
+// It panics if the columns or the rows are not positive.
+func (m *BillboardMesh) SetAtlas(columns, rows int) {
+	if columns <= 0 || rows <= 0 {
+		panic("The atlas columns and rows has to be positive.")
+	}
+	m.columns = columns
+	m.rows = rows
+	m.frame = 0
+	m.regionSet = false
+	m.updateVertices()
+}
+
+// SetTextureRegion sets the displayed region of the texture with its top left and bottom right
+// texture coordinates. It could be used for the textures with different frame sizes, like the
+// glyph atlas of the charsets. The region is used instead of the atlas frames until the next SetAtlas call.
+func (m *BillboardMesh) SetTextureRegion(min, max mgl32.Vec2) {
+	m.regionMin = min
+	m.regionMax = max
+	m.regionSet = true
+	m.updateVertices()
+}
+
+// GetTextureRegion returns the texture region and a flag, that tells us that it is set or not.
+func (m *BillboardMesh) GetTextureRegion() (mgl32.Vec2, mgl32.Vec2, bool) {
+	return m.regionMin, m.regionMax, m.regionSet
+}
+
+// FrameCount returns the number of the frames in the atlas.
+func (m *BillboardMesh) FrameCount() int {
+	return m.columns * m.rows
+}
+
+// SetFrame updates the current frame of the atlas. The frame index is wrapped to the frame count.
+func (m *BillboardMesh) SetFrame(frame int) {
+	frame = frame % m.FrameCount()
+	if frame < 0 {
+		frame += m.FrameCount()
+	}
+	m.frame = frame
+	m.updateVertices()
+}
+
+// GetFrame returns the current frame of the atlas.
+func (m *BillboardMesh) GetFrame() int {
+	return m.frame
+}
+
+// Animate starts the frame animation. The frames are changed in every frameTime (ms), after the last frame it starts again.
+func (m *BillboardMesh) Animate(frameTime float64) {
+	m.frameTime = frameTime
+	m.frameDelta = 0
+	m.animated = frameTime > 0
+}
+
+// StopAnimation stops the frame animation. The current frame is kept.
+func (m *BillboardMesh) StopAnimation() {
+	m.animated = false
+}
+
+// IsAnimated returns true if the frame animation is running.
+func (m *BillboardMesh) IsAnimated() bool {
+	return m.animated
+}
+
+// Update calculates the position change as the base mesh, then it steps the frame animation.
+func (m *BillboardMesh) Update(dt float64) {
+	m.Mesh.Update(dt)
+	if !m.animated {
+		return
+	}
+	m.frameDelta += dt
+	steps := int(m.frameDelta / m.frameTime)
+	if steps > 0 {
+		m.frameDelta -= float64(steps) * m.frameTime
+		m.SetFrame(m.frame + steps)
+	}
+}
+
+// Draw function is responsible for the actual drawing. Its input is a shader.
+// First it binds the textures with the help of the shader (i expect that the shader
+// is activated with the UseProgram gl function). Then it sets up the model and the billboardMode uniforms.
+// Then it binds the vertex array and draws the mesh with triangles. Finally it cleans up.
+func (m *BillboardMesh) Draw(shader interfaces.Shader) {
+	for _, item := range m.Textures {
+		item.Bind()
+		shader.SetUniform1i(item.UniformName, int32(item.Id-glwrapper.TEXTURE0))
+	}
+	M := m.ModelTransformation()
+	shader.SetUniformMat4("model", M)
+	shader.SetUniform1i("billboardMode", m.mode)
+	m.wrapper.BindVertexArray(m.vao)
+	m.wrapper.DrawTriangleElements(int32(len(m.Indices)))
+
+	m.Textures.UnBind()
+	m.wrapper.BindVertexArray(0)
+	m.wrapper.ActiveTexture(0)
+}
@@ -0,0 +1,142 @@
+package mesh
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/texture"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestNewBillboardMesh(t *testing.T) {
+	col := []mgl32.Vec3{mgl32.Vec3{1.0, 1.0, 1.0}}
+	var tex *texture.Texture
+	textures := texture.Textures{tex}
+	mesh := NewBillboardMesh(2, 1, textures, col, wrapperMock)
+
+	CheckNewMesh(mesh.Mesh, t)
+	if mesh.GetMode() != BILLBOARD_SPHERICAL {
+		t.Errorf("Invalid mode. Instead of '%d', we have '%d'.", BILLBOARD_SPHERICAL, mesh.GetMode())
+	}
+	if len(mesh.Vertices) != 4 {
+		t.Errorf("Invalid number of vertices. Instead of '4', we have '%d'.", len(mesh.Vertices))
+	}
+	expected := mgl32.Vec3{-1, -0.5, 0}
+	if mesh.Vertices[0].Position != expected {
+		t.Errorf("Invalid position. Instead of '%v', we have '%v'.", expected, mesh.Vertices[0].Position)
+	}
+}
+func TestBillboardMeshSetMode(t *testing.T) {
+	mesh := NewBillboardMesh(1, 1, texture.Textures{}, []mgl32.Vec3{}, wrapperMock)
+	mesh.SetMode(BILLBOARD_CYLINDRICAL)
+	if mesh.GetMode() != BILLBOARD_CYLINDRICAL {
+		t.Errorf("Invalid mode. Instead of '%d', we have '%d'.", BILLBOARD_CYLINDRICAL, mesh.GetMode())
+	}
+}
+func TestBillboardMeshSetOffset(t *testing.T) {
+	mesh := NewBillboardMesh(1, 1, texture.Textures{}, []mgl32.Vec3{}, wrapperMock)
+	offset := mgl32.Vec2{1, 2}
+	mesh.SetOffset(offset)
+	if mesh.GetOffset() != offset {
+		t.Errorf("Invalid offset. Instead of '%v', we have '%v'.", offset, mesh.GetOffset())
+	}
+	expected := mgl32.Vec3{0.5, 1.5, 0}
+	if mesh.Vertices[0].Position != expected {
+		t.Errorf("Invalid position. Instead of '%v', we have '%v'.", expected, mesh.Vertices[0].Position)
+	}
+}
+func TestBillboardMeshAtlas(t *testing.T) {
+	mesh := NewBillboardMesh(1, 1, texture.Textures{}, []mgl32.Vec3{}, wrapperMock)
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("SetAtlas should have panicked due to the invalid columns.")
+			}
+		}()
+		mesh.SetAtlas(0, 1)
+	}()
+	mesh.SetAtlas(4, 2)
+	if mesh.FrameCount() != 8 {
+		t.Errorf("Invalid frame count. Instead of '8', we have '%d'.", mesh.FrameCount())
+	}
+	mesh.SetFrame(5)
+	if mesh.GetFrame() != 5 {
+		t.Errorf("Invalid frame. Instead of '5', we have '%d'.", mesh.GetFrame())
+	}
+	// the 5. frame is the second one in the second row.
+	expected := mgl32.Vec2{0.25, 0.5}
+	if mesh.Vertices[3].TexCoords != expected {
+		t.Errorf("Invalid texture coordinates. Instead of '%v', we have '%v'.", expected, mesh.Vertices[3].TexCoords)
+	}
+	mesh.SetFrame(9)
+	if mesh.GetFrame() != 1 {
+		t.Errorf("Invalid frame. Instead of '1', we have '%d'.", mesh.GetFrame())
+	}
+	mesh.SetFrame(-1)
+	if mesh.GetFrame() != 7 {
+		t.Errorf("Invalid frame. Instead of '7', we have '%d'.", mesh.GetFrame())
+	}
+}
+func TestBillboardMeshTextureRegion(t *testing.T) {
+	mesh := NewBillboardMesh(1, 1, texture.Textures{}, []mgl32.Vec3{}, wrapperMock)
+	if _, _, ok := mesh.GetTextureRegion(); ok {
+		t.Error("The region shouldn't be set.")
+	}
+	mesh.SetTextureRegion(mgl32.Vec2{0.25, 0.5}, mgl32.Vec2{0.5, 0.75})
+	if _, _, ok := mesh.GetTextureRegion(); !ok {
+		t.Error("The region should be set.")
+	}
+	expected := []mgl32.Vec2{{0.25, 0.75}, {0.5, 0.75}, {0.5, 0.5}, {0.25, 0.5}}
+	for i := range expected {
+		if mesh.Vertices[i].TexCoords != expected[i] {
+			t.Errorf("Invalid texture coordinates of the '%d' vertex. Instead of '%v', we have '%v'.", i, expected[i], mesh.Vertices[i].TexCoords)
+		}
+	}
+	// the atlas setup turns back the frames.
+	mesh.SetAtlas(1, 1)
+	if _, _, ok := mesh.GetTextureRegion(); ok {
+		t.Error("The region shouldn't be set after SetAtlas.")
+	}
+	if mesh.Vertices[0].TexCoords != (mgl32.Vec2{0, 1}) {
+		t.Errorf("Invalid texture coordinates. Instead of '%v', we have '%v'.", mgl32.Vec2{0, 1}, mesh.Vertices[0].TexCoords)
+	}
+}
+func TestBillboardMeshAnimation(t *testing.T) {
+	mesh := NewBillboardMesh(1, 1, texture.Textures{}, []mgl32.Vec3{}, wrapperMock)
+	mesh.SetAtlas(2, 2)
+	mesh.Animate(100)
+	if !mesh.IsAnimated() {
+		t.Error("The mesh should be animated.")
+	}
+	mesh.Update(50)
+	if mesh.GetFrame() != 0 {
+		t.Errorf("Invalid frame. Instead of '0', we have '%d'.", mesh.GetFrame())
+	}
+	mesh.Update(260)
+	if mesh.GetFrame() != 3 {
+		t.Errorf("Invalid frame. Instead of '3', we have '%d'.", mesh.GetFrame())
+	}
+	mesh.Update(100)
+	if mesh.GetFrame() != 0 {
+		t.Errorf("Invalid frame. Instead of '0', we have '%d'.", mesh.GetFrame())
+	}
+	mesh.StopAnimation()
+	mesh.Update(1000)
+	if mesh.GetFrame() != 0 {
+		t.Errorf("Invalid frame. Instead of '0', we have '%d'.", mesh.GetFrame())
+	}
+}
+func TestBillboardMeshDraw(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("Shouldn't have panicked. '%v'", r)
+			}
+		}()
+		col := []mgl32.Vec3{mgl32.Vec3{0.0, 0.0, 0.0}}
+		tex := texture.Texture{Id: 1, TextureName: 1, TargetId: 1, UniformName: "uniform", Wrapper: wrapperMock, FilePath: "path"}
+		textures := texture.Textures{&tex}
+		mesh := NewBillboardMesh(1, 1, textures, col, wrapperMock)
+		mesh.Draw(shaderMock)
+	}()
+}
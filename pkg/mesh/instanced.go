@@ -0,0 +1,181 @@
+package mesh
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/material"
+	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The first attribute location of the instance transformation. The mat4 uses 4 locations.
+	instanceAttributeLocation = 3
+)
+
+// InstancedMesh is a textured or material mesh, that is drawn more times with a single draw call.
+// The model transformations of the instances are stored in a vertex buffer. The instance transformation
+// is applied before the transformation of the mesh, so that the whole group could be moved, rotated
+// with the mesh. It has to be drawn with the instanced shaders.
+type InstancedMesh struct {
+	Mesh
+	Indices  []uint32
+	Textures texture.Textures
+	// the material of the material variant. It is nil in the textured variant.
+	Material    *material.Material
+	ebo         uint32
+	instanceVbo uint32
+	instances   []mgl32.Mat4
+}
+
+// NewInstancedTexturedMesh gets the vertices, indices, textures, glwrapper as inputs and makes the
+// necessary setup for a textured instanced mesh before returning it. It doesn't have instances,
+// they could be set with the SetInstances function.
+func NewInstancedTexturedMesh(v []vertex.Vertex, i []uint32, t texture.Textures, wrapper interfaces.GLWrapper) *InstancedMesh {
+	mesh := newInstancedMesh(v, i, wrapper)
+	mesh.Textures = t
+	mesh.setup()
+	return mesh
+}
+
+// NewInstancedMaterialMesh gets the vertices, indices, material, glwrapper as inputs and makes the
+// necessary setup for a material instanced mesh before returning it. It doesn't have instances,
+// they could be set with the SetInstances function.
+func NewInstancedMaterialMesh(v []vertex.Vertex, i []uint32, mat *material.Material, wrapper interfaces.GLWrapper) *InstancedMesh {
+	mesh := newInstancedMesh(v, i, wrapper)
+	mesh.Material = mat
+	mesh.setup()
+	return mesh
+}
+func newInstancedMesh(v []vertex.Vertex, i []uint32, wrapper interfaces.GLWrapper) *InstancedMesh {
+	return &InstancedMesh{
+		Mesh: Mesh{
+			Vertices: v,
+
+			position:  mgl32.Vec3{0, 0, 0},
+			direction: mgl32.Vec3{0, 0, 0},
+			velocity:  0,
+			yaw:       0,
+			pitch:     0,
+			roll:      0,
+			scale:     mgl32.Vec3{1, 1, 1},
+			wrapper:   wrapper,
+			parentSet: false,
+
+			boundingObjectSet: false,
+		},
+		Indices:   i,
+		instances: []mgl32.Mat4{},
+	}
+}
+func (m *InstancedMesh) setup() {
+	m.vao = m.wrapper.GenVertexArrays()
+	m.vbo = m.wrapper.GenBuffers()
+	m.ebo = m.wrapper.GenBuffers()
+	m.instanceVbo = m.wrapper.GenBuffers()
+
+	m.wrapper.BindVertexArray(m.vao)
+
+	m.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, m.vbo)
+	if m.Material == nil {
+		m.wrapper.ArrayBufferData(m.Vertices.Get(vertex.POSITION_NORMAL_TEXCOORD))
+	} else {
+		m.wrapper.ArrayBufferData(m.Vertices.Get(vertex.POSITION_NORMAL))
+	}
+
+	m.wrapper.BindBuffer(glwrapper.ELEMENT_ARRAY_BUFFER, m.ebo)
+	m.wrapper.ElementBufferData(m.Indices)
+
+	if m.Material == nil {
+		// setup coordinates
+		m.wrapper.VertexAttribPointer(0, 3, glwrapper.FLOAT, false, 4*8, m.wrapper.PtrOffset(0))
+		// setup normals
+		m.wrapper.VertexAttribPointer(1, 3, glwrapper.FLOAT, false, 4*8, m.wrapper.PtrOffset(4*3))
+		// setup texture position
+		m.wrapper.VertexAttribPointer(2, 2, glwrapper.FLOAT, false, 4*8, m.wrapper.PtrOffset(4*6))
+	} else {
+		// setup coordinates
+		m.wrapper.VertexAttribPointer(0, 3, glwrapper.FLOAT, false, 4*6, m.wrapper.PtrOffset(0))
+		// setup normal vector
+		m.wrapper.VertexAttribPointer(1, 3, glwrapper.FLOAT, false, 4*6, m.wrapper.PtrOffset(4*3))
+	}
+	// setup the instance transformation. The columns of the matrix are passed in
+	// separated attributes, that are stepped once per instance.
+	m.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, m.instanceVbo)
+	for i := 0; i < 4; i++ {
+		m.wrapper.VertexAttribPointer(uint32(instanceAttributeLocation+i), 4, glwrapper.FLOAT, false, 4*16, m.wrapper.PtrOffset(4*4*i))
+		m.wrapper.VertexAttribDivisor(uint32(instanceAttributeLocation+i), 1)
+	}
+
+	// close
+	m.wrapper.BindVertexArray(0)
+}
+
+// SetInstances updates the model transformations of the instances and uploads them to the
+// instance buffer.
+func (m *InstancedMesh) SetInstances(transformations []mgl32.Mat4) {
+	m.instances = append([]mgl32.Mat4{}, transformations...)
+	if len(m.instances) == 0 {
+		return
+	}
+	data := make([]float32, 0, 16*len(m.instances))
+	for _, tr := range m.instances {
+		data = append(data, tr[:]...)
+	}
+	m.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, m.instanceVbo)
+	m.wrapper.ArrayBufferData(data)
+	m.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, 0)
+}
+
+// GetInstances returns the model transformations of the instances.
+func (m *InstancedMesh) GetInstances() []mgl32.Mat4 {
+	return m.instances
+}
+
+// InstanceCount returns the number of the instances.
+func (m *InstancedMesh) InstanceCount() int {
+	return len(m.instances)
+}
+
+// Draw function is responsible for the actual drawing. It's input is a shader.
+// It binds the textures or sets the material uniforms, sets the model uniform,
+// then draws every instance with a single draw call. Without instances it does nothing.
+func (m *InstancedMesh) Draw(shader interfaces.Shader) {
+	if len(m.instances) == 0 {
+		return
+	}
+	for _, item := range m.Textures {
+		item.Bind()
+		shader.SetUniform1i(item.UniformName, int32(item.Id-glwrapper.TEXTURE0))
+	}
+	M := m.ModelTransformation()
+	shader.SetUniformMat4("model", M)
+	if m.Material != nil {
+		diffuse := m.Material.GetDiffuse()
+		ambient := m.Material.GetAmbient()
+		specular := m.Material.GetSpecular()
+		shader.SetUniform3f("material.diffuse", diffuse.X(), diffuse.Y(), diffuse.Z())
+		shader.SetUniform3f("material.ambient", ambient.X(), ambient.Y(), ambient.Z())
+		shader.SetUniform3f("material.specular", specular.X(), specular.Y(), specular.Z())
+		shader.SetUniform1f("material.shininess", m.Material.GetShininess())
+	} else {
+		shader.SetUniform1f("material.shininess", float32(32))
+	}
+	m.wrapper.BindVertexArray(m.vao)
+	m.wrapper.DrawTriangleElementsInstanced(int32(len(m.Indices)), int32(len(m.instances)))
+
+	m.Textures.UnBind()
+	m.wrapper.BindVertexArray(0)
+	m.wrapper.ActiveTexture(0)
+}
+
+// Delete releases the vao and the buffers of the mesh. The textures are not deleted,
+// because they could be shared with other meshes. The mesh can't be drawn after this.
+func (m *InstancedMesh) Delete() {
+	m.wrapper.DeleteBuffers(m.instanceVbo)
+	m.wrapper.DeleteBuffers(m.ebo)
+	m.wrapper.DeleteBuffers(m.vbo)
+	m.wrapper.DeleteVertexArrays(m.vao)
+}
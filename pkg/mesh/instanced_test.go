@@ -0,0 +1,66 @@
+package mesh
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/material"
+	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestNewInstancedTexturedMesh(t *testing.T) {
+	tex := texture.Texture{Id: 1, TextureName: 1, TargetId: 1, UniformName: "uniform", Wrapper: wrapperMock, FilePath: "path"}
+	mesh := NewInstancedTexturedMesh([]vertex.Vertex{}, []uint32{}, texture.Textures{&tex}, wrapperMock)
+
+	CheckNewMesh(mesh.Mesh, t)
+	if mesh.Material != nil || len(mesh.Textures) != 1 {
+		t.Error("Invalid textured instanced mesh.")
+	}
+	if mesh.InstanceCount() != 0 {
+		t.Errorf("Invalid instance count. Instead of '0', we have '%d'.", mesh.InstanceCount())
+	}
+}
+func TestNewInstancedMaterialMesh(t *testing.T) {
+	mesh := NewInstancedMaterialMesh([]vertex.Vertex{}, []uint32{}, material.Jade, wrapperMock)
+
+	CheckNewMesh(mesh.Mesh, t)
+	if mesh.Material != material.Jade || len(mesh.Textures) != 0 {
+		t.Error("Invalid material instanced mesh.")
+	}
+}
+func TestInstancedMeshSetInstances(t *testing.T) {
+	mesh := NewInstancedMaterialMesh([]vertex.Vertex{}, []uint32{}, material.Jade, wrapperMock)
+	transformations := []mgl32.Mat4{mgl32.Ident4(), mgl32.Translate3D(1, 2, 3)}
+	mesh.SetInstances(transformations)
+	if mesh.InstanceCount() != 2 {
+		t.Errorf("Invalid instance count. Instead of '2', we have '%d'.", mesh.InstanceCount())
+	}
+	transformations[0] = mgl32.Scale3D(2, 2, 2)
+	if mesh.GetInstances()[0] != mgl32.Ident4() || mesh.GetInstances()[1] != mgl32.Translate3D(1, 2, 3) {
+		t.Error("The instances should be copied.")
+	}
+	mesh.SetInstances(nil)
+	if mesh.InstanceCount() != 0 {
+		t.Errorf("Invalid instance count. Instead of '0', we have '%d'.", mesh.InstanceCount())
+	}
+}
+func TestInstancedMeshDraw(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("Shouldn't have panicked. '%v'", r)
+			}
+		}()
+		tex := texture.Texture{Id: 1, TextureName: 1, TargetId: 1, UniformName: "uniform", Wrapper: wrapperMock, FilePath: "path"}
+		textured := NewInstancedTexturedMesh([]vertex.Vertex{}, []uint32{}, texture.Textures{&tex}, wrapperMock)
+		textured.Draw(shaderMock)
+		textured.SetInstances([]mgl32.Mat4{mgl32.Ident4()})
+		textured.Draw(shaderMock)
+		mat := NewInstancedMaterialMesh([]vertex.Vertex{}, []uint32{}, material.Jade, wrapperMock)
+		mat.SetInstances([]mgl32.Mat4{mgl32.Ident4()})
+		mat.Draw(shaderMock)
+		mat.Delete()
+	}()
+}
@@ -39,6 +39,9 @@ type Mesh struct {
 
 	bo                *boundingobject.BoundingObject
 	boundingObjectSet bool
+	// the number of the vertices of a patch. If it is set, the indices are drawn as
+	// patches instead of triangles, so that the mesh could be drawn with tessellation shaders.
+	patchVertices int32
 }
 
 // SetScale updates the scale of the mesh.
@@ -46,6 +49,29 @@ func (m *Mesh) SetScale(s mgl32.Vec3) {
 	m.scale = s
 }
 
+// SetPatchVertices sets the number of the vertices of a patch. The meshes with patch size
+// are drawn as patches instead of triangles, it is necessary for the tessellation shaders.
+// The 0 value turns back the triangle drawing.
+func (m *Mesh) SetPatchVertices(n int32) {
+	m.patchVertices = n
+}
+
+// GetPatchVertices returns the number of the vertices of a patch. It is 0 for the triangle meshes.
+func (m *Mesh) GetPatchVertices() int32 {
+	return m.patchVertices
+}
+
+// drawElements draws the given number of indices of the bound vertex array as triangles
+// or as patches, if the patch size is set.
+func (m *Mesh) drawElements(count int32) {
+	if m.patchVertices > 0 {
+		m.wrapper.PatchParameteri(glwrapper.PATCH_VERTICES, m.patchVertices)
+		m.wrapper.DrawPatchElements(count)
+		return
+	}
+	m.wrapper.DrawTriangleElements(count)
+}
+
 // SetPosition updates the position of the mesh.
 func (m *Mesh) SetPosition(p mgl32.Vec3) {
 	m.position = p
@@ -272,13 +298,29 @@ func (m *TexturedMesh) Draw(shader interfaces.Shader) {
 	shader.SetUniformMat4("model", M)
 	shader.SetUniform1f("material.shininess", float32(32))
 	m.wrapper.BindVertexArray(m.vao)
-	m.wrapper.DrawTriangleElements(int32(len(m.Indices)))
+	m.drawElements(int32(len(m.Indices)))
 
 	m.Textures.UnBind()
 	m.wrapper.BindVertexArray(0)
 	m.wrapper.ActiveTexture(0)
 }
 
+// Delete releases the vao, vbo, ebo of the mesh. The textures are not deleted,
+// because they could be shared with other meshes. The mesh can't be drawn after this.
+func (m *TexturedMesh) Delete() {
+	m.wrapper.DeleteBuffers(m.ebo)
+	m.wrapper.DeleteBuffers(m.vbo)
+	m.wrapper.DeleteVertexArrays(m.vao)
+}
+
+// UpdateVertices uploads the [from, to) range of the Vertices to the vbo. It could be used
+// after the modification of some vertices, instead of uploading the whole buffer.
+func (m *TexturedMesh) UpdateVertices(from, to int) {
+	m.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, m.vbo)
+	m.wrapper.ArrayBufferSubData(8*from, m.Vertices[from:to].Get(vertex.POSITION_NORMAL_TEXCOORD))
+	m.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, 0)
+}
+
 // NewTexturedMesh gets the vertices, indices, textures, glwrapper as inputs and makes the
 // necessary setup for a standing (not moving) textured mesh before returning it.
 // The vbo, vao, ebo is also set.
@@ -377,7 +419,7 @@ func (m *MaterialMesh) Draw(shader interfaces.Shader) {
 	shader.SetUniform3f("material.specular", specular.X(), specular.Y(), specular.Z())
 	shader.SetUniform1f("material.shininess", shininess)
 	m.wrapper.BindVertexArray(m.vao)
-	m.wrapper.DrawTriangleElements(int32(len(m.Indices)))
+	m.drawElements(int32(len(m.Indices)))
 
 	m.wrapper.BindVertexArray(0)
 	m.wrapper.ActiveTexture(0)
@@ -513,7 +555,7 @@ func (m *ColorMesh) Draw(shader interfaces.Shader) {
 	M := m.ModelTransformation()
 	shader.SetUniformMat4("model", M)
 	m.wrapper.BindVertexArray(m.vao)
-	m.wrapper.DrawTriangleElements(int32(len(m.Indices)))
+	m.drawElements(int32(len(m.Indices)))
 
 	m.wrapper.BindVertexArray(0)
 	m.wrapper.ActiveTexture(0)
@@ -590,13 +632,35 @@ func (m *TexturedColoredMesh) Draw(shader interfaces.Shader) {
 	M := m.ModelTransformation()
 	shader.SetUniformMat4("model", M)
 	m.wrapper.BindVertexArray(m.vao)
-	m.wrapper.DrawTriangleElements(int32(len(m.Indices)))
+	m.drawElements(int32(len(m.Indices)))
 
 	m.Textures.UnBind()
 	m.wrapper.BindVertexArray(0)
 	m.wrapper.ActiveTexture(0)
 }
 
+// SetGeometry replaces the vertices and the indices of the mesh and uploads them to the
+// existing vbo and ebo, so that the mesh could be rebuilt without new gpu objects, like
+// the printed texts.
+func (m *TexturedColoredMesh) SetGeometry(v []vertex.Vertex, i []uint32) {
+	m.Vertices = v
+	m.Indices = i
+	m.wrapper.BindVertexArray(m.vao)
+	m.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, m.vbo)
+	m.wrapper.ArrayBufferData(m.Vertices.Get(vertex.POSITION_COLOR_TEXCOORD))
+	m.wrapper.BindBuffer(glwrapper.ELEMENT_ARRAY_BUFFER, m.ebo)
+	m.wrapper.ElementBufferData(m.Indices)
+	m.wrapper.BindVertexArray(0)
+}
+
+// Delete releases the vao, vbo, ebo of the mesh. The textures are not deleted,
+// because they could be shared with other meshes. The mesh can't be drawn after this.
+func (m *TexturedColoredMesh) Delete() {
+	m.wrapper.DeleteBuffers(m.ebo)
+	m.wrapper.DeleteBuffers(m.vbo)
+	m.wrapper.DeleteVertexArrays(m.vao)
+}
+
 type TexturedMaterialMesh struct {
 	Mesh
 	Indices  []uint32
@@ -677,7 +741,7 @@ func (m *TexturedMaterialMesh) Draw(shader interfaces.Shader) {
 	shader.SetUniform3f("material.specular", specular.X(), specular.Y(), specular.Z())
 	shader.SetUniform1f("material.shininess", shininess)
 	m.wrapper.BindVertexArray(m.vao)
-	m.wrapper.DrawTriangleElements(int32(len(m.Indices)))
+	m.drawElements(int32(len(m.Indices)))
 
 	m.Textures.UnBind()
 	m.wrapper.BindVertexArray(0)
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
 	"github.com/akosgarai/playground_engine/pkg/interfaces"
 	"github.com/akosgarai/playground_engine/pkg/material"
 	"github.com/akosgarai/playground_engine/pkg/primitives/boundingobject"
@@ -27,6 +28,25 @@ var (
 	DefaultVelocity  = float32(0.0)
 )
 
+// patchWrapperMock records the draw calls of the meshes.
+type patchWrapperMock struct {
+	testhelper.GLWrapperMock
+	triangles int32
+	patches   int32
+	patchSize int32
+}
+
+func (w *patchWrapperMock) DrawTriangleElements(count int32) {
+	w.triangles = count
+}
+func (w *patchWrapperMock) PatchParameteri(pname uint32, value int32) {
+	if pname == glwrapper.PATCH_VERTICES {
+		w.patchSize = value
+	}
+}
+func (w *patchWrapperMock) DrawPatchElements(count int32) {
+	w.patches = count
+}
 func TestSetScale(t *testing.T) {
 	var m Mesh
 	scale := mgl32.Vec3{2, 2, 2}
@@ -429,6 +449,24 @@ func TestMaterialMeshDraw(t *testing.T) {
 		mesh.Draw(shaderMock)
 	}()
 }
+func TestMaterialMeshDrawPatches(t *testing.T) {
+	wrapper := &patchWrapperMock{}
+	v := []vertex.Vertex{}
+	i := []uint32{0, 1, 2, 2, 1, 3}
+	mesh := NewMaterialMesh(v, i, material.Jade, wrapper)
+	mesh.Draw(shaderMock)
+	if wrapper.triangles != 6 || wrapper.patches != 0 {
+		t.Errorf("The mesh should be drawn with triangles. Triangles: '%d', patches: '%d'.", wrapper.triangles, wrapper.patches)
+	}
+	mesh.SetPatchVertices(3)
+	if mesh.GetPatchVertices() != 3 {
+		t.Errorf("Invalid patch vertices. Instead of '3', we have '%d'.", mesh.GetPatchVertices())
+	}
+	mesh.Draw(shaderMock)
+	if wrapper.patchSize != 3 || wrapper.patches != 6 {
+		t.Errorf("The mesh should be drawn with patches. Patch size: '%d', patches: '%d'.", wrapper.patchSize, wrapper.patches)
+	}
+}
 func TestNewPointMesh(t *testing.T) {
 	mesh := NewPointMesh(wrapperMock)
 
@@ -512,6 +550,39 @@ func TestTexturedMeshDraw(t *testing.T) {
 		mesh.Draw(shaderMock)
 	}()
 }
+func TestTexturedMeshDelete(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("Shouldn't have panicked. '%v'", r)
+			}
+		}()
+		mesh := NewTexturedMesh([]vertex.Vertex{}, []uint32{}, texture.Textures{}, wrapperMock)
+		mesh.Delete()
+	}()
+}
+func TestTexturedMeshUpdateVertices(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("Shouldn't have panicked. '%v'", r)
+			}
+		}()
+		v := []vertex.Vertex{vertex.Vertex{}, vertex.Vertex{}, vertex.Vertex{}}
+		mesh := NewTexturedMesh(v, []uint32{0, 1, 2}, texture.Textures{}, wrapperMock)
+		mesh.Vertices[1].Position = mgl32.Vec3{1, 2, 3}
+		mesh.UpdateVertices(1, 3)
+	}()
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Invalid range should panic.")
+			}
+		}()
+		mesh := NewTexturedMesh([]vertex.Vertex{vertex.Vertex{}}, []uint32{0}, texture.Textures{}, wrapperMock)
+		mesh.UpdateVertices(0, 2)
+	}()
+}
 func TestNewTexturedColoredMesh(t *testing.T) {
 	v := []vertex.Vertex{}
 	i := []uint32{}
@@ -540,6 +611,23 @@ func TestTexturedColoredMeshDraw(t *testing.T) {
 		mesh.Draw(shaderMock)
 	}()
 }
+func TestTexturedColoredMeshSetGeometry(t *testing.T) {
+	col := []mgl32.Vec3{mgl32.Vec3{0.0, 0.0, 0.0}}
+	mesh := NewTexturedColoredMesh([]vertex.Vertex{}, []uint32{}, texture.Textures{}, col, wrapperMock)
+	v := []vertex.Vertex{
+		vertex.Vertex{Position: mgl32.Vec3{0, 0, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{1, 0, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{1, 0, 1}},
+	}
+	mesh.SetGeometry(v, []uint32{0, 1, 2})
+	if len(mesh.Vertices) != 3 {
+		t.Errorf("Invalid number of vertices. Instead of '3', we have '%d'.", len(mesh.Vertices))
+	}
+	if len(mesh.Indices) != 3 {
+		t.Errorf("Invalid number of indices. Instead of '3', we have '%d'.", len(mesh.Indices))
+	}
+	mesh.Delete()
+}
 func TestNewTexturedMaterialMesh(t *testing.T) {
 	v := []vertex.Vertex{}
 	i := []uint32{}
@@ -0,0 +1,147 @@
+package mesh
+
+import (
+	"math"
+
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// clusterKey identifies a vertex cluster. The vertices are grouped by the grid cell of
+// their position and the dominant axis of their normal vector, so that the hard edges are kept.
+type clusterKey struct {
+	x, y, z int
+	normal  int
+}
+
+// normalBucket returns the index (0-5) of the dominant signed axis of the normal vector.
+func normalBucket(n mgl32.Vec3) int {
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(float64(n[i])) > math.Abs(float64(n[axis])) {
+			axis = i
+		}
+	}
+	if n[axis] < 0 {
+		return axis*2 + 1
+	}
+	return axis * 2
+}
+
+// SimplifyVertices reduces the number of the vertices with vertex clustering. The space is divided
+// to cells with the given size, the vertices of a cell are merged to their average. The triangles
+// that became degenerate are dropped. The indices have to describe triangles.
+func SimplifyVertices(v vertex.Vertices, indices []uint32, cellSize float32) (vertex.Vertices, []uint32) {
+	if cellSize <= 0 {
+		return v, indices
+	}
+	clusters := make(map[clusterKey]int)
+	var sums vertex.Vertices
+	var counts []float32
+	remap := make([]uint32, len(v))
+	for i := 0; i < len(v); i++ {
+		p := v[i].Position
+		key := clusterKey{
+			x:      int(math.Floor(float64(p.X() / cellSize))),
+			y:      int(math.Floor(float64(p.Y() / cellSize))),
+			z:      int(math.Floor(float64(p.Z() / cellSize))),
+			normal: normalBucket(v[i].Normal),
+		}
+		index, ok := clusters[key]
+		if !ok {
+			index = len(sums)
+			clusters[key] = index
+			sums = append(sums, vertex.Vertex{})
+			counts = append(counts, 0)
+		}
+		sums[index].Position = sums[index].Position.Add(v[i].Position)
+		sums[index].Normal = sums[index].Normal.Add(v[i].Normal)
+		sums[index].TexCoords = sums[index].TexCoords.Add(v[i].TexCoords)
+		sums[index].Color = sums[index].Color.Add(v[i].Color)
+		sums[index].PointSize += v[i].PointSize
+		counts[index]++
+		remap[i] = uint32(index)
+	}
+	for i := 0; i < len(sums); i++ {
+		c := counts[i]
+		sums[i].Position = sums[i].Position.Mul(1 / c)
+		if sums[i].Normal.Len() > 0 {
+			sums[i].Normal = sums[i].Normal.Normalize()
+		}
+		sums[i].TexCoords = sums[i].TexCoords.Mul(1 / c)
+		sums[i].Color = sums[i].Color.Mul(1 / c)
+		sums[i].PointSize = sums[i].PointSize / c
+	}
+	var newIndices []uint32
+	triangles := make(map[[3]uint32]bool)
+	for t := 0; t+2 < len(indices); t += 3 {
+		a, b, c := remap[indices[t]], remap[indices[t+1]], remap[indices[t+2]]
+		if a == b || b == c || a == c {
+			continue
+		}
+		// the same triangle could be generated from more triangles of the original mesh.
+		key := [3]uint32{a, b, c}
+		if b < a && b < c {
+			key = [3]uint32{b, c, a}
+		} else if c < a && c < b {
+			key = [3]uint32{c, a, b}
+		}
+		if triangles[key] {
+			continue
+		}
+		triangles[key] = true
+		newIndices = append(newIndices, a, b, c)
+	}
+	return sums, newIndices
+}
+
+// copyState copies the transformation, movement, parent and bounding object parameters.
+func (m *Mesh) copyState(src *Mesh) {
+	m.position = src.position
+	m.direction = src.direction
+	m.velocity = src.velocity
+	m.yaw = src.yaw
+	m.pitch = src.pitch
+	m.roll = src.roll
+	m.scale = src.scale
+	m.parent = src.parent
+	m.parentSet = src.parentSet
+	m.bo = src.bo
+	m.boundingObjectSet = src.boundingObjectSet
+}
+
+// Simplify returns a simplified copy of the given mesh. It could be used for the meshes of the imported
+// models. The vertices are simplified with the SimplifyVertices function, the textures, materials, colors
+// and the transformations are kept. The point meshes and the unknown mesh types are returned without change.
+func Simplify(msh interfaces.Mesh, cellSize float32) interfaces.Mesh {
+	switch m := msh.(type) {
+	case *MaterialMesh:
+		v, i := SimplifyVertices(m.Vertices, m.Indices, cellSize)
+		result := NewMaterialMesh(v, i, m.Material, m.wrapper)
+		result.copyState(&m.Mesh)
+		return result
+	case *TexturedMesh:
+		v, i := SimplifyVertices(m.Vertices, m.Indices, cellSize)
+		result := NewTexturedMesh(v, i, m.Textures, m.wrapper)
+		result.copyState(&m.Mesh)
+		return result
+	case *ColorMesh:
+		v, i := SimplifyVertices(m.Vertices, m.Indices, cellSize)
+		result := NewColorMesh(v, i, m.Color, m.wrapper)
+		result.copyState(&m.Mesh)
+		return result
+	case *TexturedColoredMesh:
+		v, i := SimplifyVertices(m.Vertices, m.Indices, cellSize)
+		result := NewTexturedColoredMesh(v, i, m.Textures, m.Color, m.wrapper)
+		result.copyState(&m.Mesh)
+		return result
+	case *TexturedMaterialMesh:
+		v, i := SimplifyVertices(m.Vertices, m.Indices, cellSize)
+		result := NewTexturedMaterialMesh(v, i, m.Textures, m.Material, m.wrapper)
+		result.copyState(&m.Mesh)
+		return result
+	}
+	return msh
+}
@@ -0,0 +1,83 @@
+package mesh
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/material"
+	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSimplifyVertices(t *testing.T) {
+	v := vertex.Vertices{
+		vertex.Vertex{Position: mgl32.Vec3{0, 0, 0}, Normal: mgl32.Vec3{0, 1, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{0.1, 0, 0}, Normal: mgl32.Vec3{0, 1, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{2, 0, 0}, Normal: mgl32.Vec3{0, 1, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{0, 0, 2}, Normal: mgl32.Vec3{0, 1, 0}},
+	}
+	i := []uint32{0, 1, 3, 1, 2, 3}
+	// without cell size, the input is returned.
+	sv, si := SimplifyVertices(v, i, 0)
+	if len(sv) != 4 || len(si) != 6 {
+		t.Errorf("Invalid result. Instead of '4, 6', we have '%d, %d'.", len(sv), len(si))
+	}
+	// the first 2 vertices are merged, so that the first triangle is dropped.
+	sv, si = SimplifyVertices(v, i, 1)
+	if len(sv) != 3 {
+		t.Errorf("Invalid number of vertices. Instead of '3', we have '%d'.", len(sv))
+	}
+	if len(si) != 3 {
+		t.Errorf("Invalid number of indices. Instead of '3', we have '%d'.", len(si))
+	}
+	expected := mgl32.Vec3{0.05, 0, 0}
+	if sv[0].Position != expected {
+		t.Errorf("Invalid position. Instead of '%v', we have '%v'.", expected, sv[0].Position)
+	}
+}
+func TestSimplifyVerticesKeepsHardEdges(t *testing.T) {
+	v := vertex.Vertices{
+		vertex.Vertex{Position: mgl32.Vec3{0, 0, 0}, Normal: mgl32.Vec3{0, 1, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{0, 0, 0}, Normal: mgl32.Vec3{1, 0, 0}},
+	}
+	sv, _ := SimplifyVertices(v, []uint32{}, 1)
+	if len(sv) != 2 {
+		t.Errorf("Invalid number of vertices. Instead of '2', we have '%d'.", len(sv))
+	}
+}
+func TestSimplify(t *testing.T) {
+	v := vertex.Vertices{
+		vertex.Vertex{Position: mgl32.Vec3{0, 0, 0}, Normal: mgl32.Vec3{0, 1, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{0.1, 0, 0}, Normal: mgl32.Vec3{0, 1, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{2, 0, 0}, Normal: mgl32.Vec3{0, 1, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{0, 0, 2}, Normal: mgl32.Vec3{0, 1, 0}},
+	}
+	i := []uint32{0, 1, 3, 1, 2, 3}
+	msh := NewMaterialMesh(v, i, material.Jade, wrapperMock)
+	position := mgl32.Vec3{1, 2, 3}
+	msh.SetPosition(position)
+	msh.RotateY(30)
+	simplified := Simplify(msh, 1)
+	result, ok := simplified.(*MaterialMesh)
+	if !ok {
+		t.Fatal("The result should be a material mesh.")
+	}
+	if len(result.Vertices) != 3 {
+		t.Errorf("Invalid number of vertices. Instead of '3', we have '%d'.", len(result.Vertices))
+	}
+	if result.GetPosition() != position {
+		t.Errorf("Invalid position. Instead of '%v', we have '%v'.", position, result.GetPosition())
+	}
+	if result.Material != msh.Material {
+		t.Error("Invalid material.")
+	}
+	_, yaw, _ := result.GetAngles()
+	_, originalYaw, _ := msh.GetAngles()
+	if yaw != originalYaw {
+		t.Errorf("Invalid yaw. Instead of '%f', we have '%f'.", originalYaw, yaw)
+	}
+	point := NewPointMesh(wrapperMock)
+	if Simplify(point, 1) != point {
+		t.Error("The point mesh should be returned without change.")
+	}
+}
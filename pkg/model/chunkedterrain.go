@@ -0,0 +1,595 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"runtime"
+	"sort"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/heightmap"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+
+	"github.com/akosgarai/coldet"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	defaultChunkSize       = 32
+	defaultViewDistance    = 2
+	defaultLODLevels       = 3
+	defaultLODDistance     = float32(64.0)
+	defaultChunksPerUpdate = 4
+	defaultFeatureSize     = 32
+	defaultOctaves         = 4
+	defaultTextureScale    = float32(1.0)
+)
+
+// HeightFunction returns the height of the terrain in the given grid point. The grid is
+// infinite, the chunks are sampling it, so that the neighbour chunks share the border values.
+type HeightFunction func(x, z int) float32
+
+// chunkKey is the coordinate of the chunk in the chunk grid.
+type chunkKey struct {
+	x, z int
+}
+
+// terrainChunk is a size x size cell part of the terrain.
+type terrainChunk struct {
+	key chunkKey
+	// the (size+1) x (size+1) height values of the chunk. The first index is the z coordinate.
+	heightMap [][]float32
+	msh       *mesh.TexturedMesh
+	// lod is the level of the mesh, the sampling step is 2^lod.
+	lod int
+	// edges contains the sampling steps of the -x, +x, -z, +z edges.
+	edges [4]int
+}
+
+// ChunkedTerrain is an infinite terrain, that is divided to square chunks. The chunks around the
+// camera are generated in the Update function, the far ones are deleted. The detail of the chunk meshes
+// depends on the camera distance (geomipmapping). The edges of the detailed chunks are fitted to the
+// less detailed neighbours, so that the surface doesn't have cracks.
+type ChunkedTerrain struct {
+	Model
+	chunks          map[chunkKey]*terrainChunk
+	height          HeightFunction
+	chunkSize       int
+	viewDistance    int
+	lodLevels       int
+	lodDistance     float32
+	chunksPerUpdate int
+	camera          interfaces.Camera
+	tex             texture.Textures
+	textureScale    float32
+	wrapper         interfaces.GLWrapper
+	scale           mgl32.Vec3
+	position        mgl32.Vec3
+	debugMode       bool
+}
+
+// SetCamera sets the camera. The chunks are loaded around its position.
+func (t *ChunkedTerrain) SetCamera(c interfaces.Camera) {
+	t.camera = c
+}
+
+// ChunkCount returns the number of the loaded chunks.
+func (t *ChunkedTerrain) ChunkCount() int {
+	return len(t.chunks)
+}
+
+// IsChunkLoaded returns true if the chunk with the given chunk coordinates is loaded.
+func (t *ChunkedTerrain) IsChunkLoaded(x, z int) bool {
+	_, ok := t.chunks[chunkKey{x, z}]
+	return ok
+}
+
+// ChunkLOD returns the level of detail of the given chunk. If the chunk is not loaded, it returns -1.
+func (t *ChunkedTerrain) ChunkLOD(x, z int) int {
+	if c, ok := t.chunks[chunkKey{x, z}]; ok {
+		return c.lod
+	}
+	return -1
+}
+
+// ChunkMesh returns the mesh of the given chunk. If the chunk is not loaded, it returns nil.
+func (t *ChunkedTerrain) ChunkMesh(x, z int) interfaces.Mesh {
+	if c, ok := t.chunks[chunkKey{x, z}]; ok {
+		return c.msh
+	}
+	return nil
+}
+
+// floorDiv returns the floor of a/b for positive b.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
+
+// chunkOf returns the key of the chunk that contains the given world position.
+func (t *ChunkedTerrain) chunkOf(pos mgl32.Vec3) chunkKey {
+	x := (pos.X() - t.position.X()) / (t.scale.X() * float32(t.chunkSize))
+	z := (pos.Z() - t.position.Z()) / (t.scale.Z() * float32(t.chunkSize))
+	return chunkKey{int(math.Floor(float64(x))), int(math.Floor(float64(z)))}
+}
+
+// chunkCenter returns the world position of the center of the given chunk.
+func (t *ChunkedTerrain) chunkCenter(k chunkKey) mgl32.Vec3 {
+	half := float32(t.chunkSize) / 2
+	return mgl32.Vec3{
+		t.position.X() + (float32(k.x*t.chunkSize)+half)*t.scale.X(),
+		t.position.Y(),
+		t.position.Z() + (float32(k.z*t.chunkSize)+half)*t.scale.Z(),
+	}
+}
+
+// focus returns the position of the camera, or the terrain position without camera.
+func (t *ChunkedTerrain) focus() mgl32.Vec3 {
+	if t.camera == nil {
+		return t.position
+	}
+	return t.camera.GetPosition()
+}
+
+// Update function loads the missing chunks around the camera (max chunksPerUpdate in a call)
+// and deletes the far ones. Then it updates the level of detail of the chunk meshes.
+func (t *ChunkedTerrain) Update(dt float64) {
+	t.stream(t.focus(), t.chunksPerUpdate)
+}
+
+// stream loads max limit chunks around the given position. If the limit is not positive, every missing chunk is loaded.
+func (t *ChunkedTerrain) stream(focus mgl32.Vec3, limit int) {
+	center := t.chunkOf(focus)
+	for k, c := range t.chunks {
+		if abs(k.x-center.x) > t.viewDistance+1 || abs(k.z-center.z) > t.viewDistance+1 {
+			if c.msh != nil {
+				c.msh.Delete()
+			}
+			delete(t.chunks, k)
+		}
+	}
+	var missing []chunkKey
+	for x := center.x - t.viewDistance; x <= center.x+t.viewDistance; x++ {
+		for z := center.z - t.viewDistance; z <= center.z+t.viewDistance; z++ {
+			k := chunkKey{x, z}
+			if _, ok := t.chunks[k]; !ok {
+				missing = append(missing, k)
+			}
+		}
+	}
+	// the closest chunks are loaded first.
+	sort.Slice(missing, func(i, j int) bool {
+		di := (missing[i].x-center.x)*(missing[i].x-center.x) + (missing[i].z-center.z)*(missing[i].z-center.z)
+		dj := (missing[j].x-center.x)*(missing[j].x-center.x) + (missing[j].z-center.z)*(missing[j].z-center.z)
+		return di < dj
+	})
+	if limit > 0 && len(missing) > limit {
+		missing = missing[:limit]
+	}
+	for _, k := range missing {
+		t.chunks[k] = t.generateChunk(k)
+		if t.debugMode {
+			fmt.Printf("ChunkedTerrain: chunk '%v' loaded.\n", k)
+		}
+	}
+	t.updateLOD(focus)
+}
+
+// generateChunk samples the height function for the given chunk.
+func (t *ChunkedTerrain) generateChunk(k chunkKey) *terrainChunk {
+	heightMap := make([][]float32, t.chunkSize+1)
+	for z := 0; z <= t.chunkSize; z++ {
+		heightMap[z] = make([]float32, t.chunkSize+1)
+		for x := 0; x <= t.chunkSize; x++ {
+			heightMap[z][x] = t.height(k.x*t.chunkSize+x, k.z*t.chunkSize+z)
+		}
+	}
+	return &terrainChunk{key: k, heightMap: heightMap, lod: -1}
+}
+
+// updateLOD calculates the level of the chunks from the distance of the focus point,
+// then it rebuilds the meshes where the level or the step of the edges has been changed.
+func (t *ChunkedTerrain) updateLOD(focus mgl32.Vec3) {
+	levels := make(map[chunkKey]int)
+	for k, _ := range t.chunks {
+		level := 0
+		if t.lodDistance > 0 {
+			level = int(focus.Sub(t.chunkCenter(k)).Len() / t.lodDistance)
+		}
+		if level >= t.lodLevels {
+			level = t.lodLevels - 1
+		}
+		levels[k] = level
+	}
+	neighbours := [4]chunkKey{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	changed := false
+	for k, c := range t.chunks {
+		step := 1 << uint(levels[k])
+		var edges [4]int
+		for i, n := range neighbours {
+			edges[i] = step
+			if level, ok := levels[chunkKey{k.x + n.x, k.z + n.z}]; ok && 1<<uint(level) > step {
+				edges[i] = 1 << uint(level)
+			}
+		}
+		if c.msh != nil && c.lod == levels[k] && c.edges == edges {
+			continue
+		}
+		if c.msh != nil {
+			c.msh.Delete()
+		}
+		c.lod = levels[k]
+		c.edges = edges
+		c.msh = t.buildChunkMesh(c)
+		changed = true
+	}
+	if changed || len(t.meshes) != len(t.chunks) {
+		t.collectMeshes()
+	}
+}
+
+// collectMeshes updates the mesh list of the model from the loaded chunks.
+func (t *ChunkedTerrain) collectMeshes() {
+	t.meshes = t.meshes[:0]
+	for _, c := range t.chunks {
+		t.meshes = append(t.meshes, c.msh)
+	}
+}
+
+// edgeHeight returns the height of the local grid point (x, z). On the edges with larger sampling
+// step, the value is interpolated between the samples of the neighbour chunk.
+func (t *ChunkedTerrain) edgeHeight(c *terrainChunk, x, z int) float32 {
+	along, edgeStep := -1, 1
+	switch {
+	case x == 0 && c.edges[0] > 1:
+		along, edgeStep = z, c.edges[0]
+	case x == t.chunkSize && c.edges[1] > 1:
+		along, edgeStep = z, c.edges[1]
+	case z == 0 && c.edges[2] > 1:
+		along, edgeStep = x, c.edges[2]
+	case z == t.chunkSize && c.edges[3] > 1:
+		along, edgeStep = x, c.edges[3]
+	}
+	if along < 0 || along%edgeStep == 0 {
+		return c.heightMap[z][x]
+	}
+	from := along - along%edgeStep
+	w := float32(along-from) / float32(edgeStep)
+	if x == 0 || x == t.chunkSize {
+		return c.heightMap[from][x]*(1-w) + c.heightMap[from+edgeStep][x]*w
+	}
+	return c.heightMap[z][from]*(1-w) + c.heightMap[z][from+edgeStep]*w
+}
+
+// buildChunkMesh returns the mesh of the chunk with the current level and edges.
+// The normal vectors are calculated from the full detail height function, so that
+// the lighting is continuous on the chunk borders. The texture coordinates are
+// calculated from the grid position, so that every level maps the texture the same way.
+func (t *ChunkedTerrain) buildChunkMesh(c *terrainChunk) *mesh.TexturedMesh {
+	step := 1 << uint(c.lod)
+	n := t.chunkSize / step
+	var vertices vertex.Vertices
+	for j := 0; j <= n; j++ {
+		for i := 0; i <= n; i++ {
+			x, z := i*step, j*step
+			gx, gz := c.key.x*t.chunkSize+x, c.key.z*t.chunkSize+z
+			dX := mgl32.Vec3{2, t.height(gx+1, gz) - t.height(gx-1, gz), 0}
+			dZ := mgl32.Vec3{0, t.height(gx, gz+1) - t.height(gx, gz-1), 2}
+			vertices = append(vertices, vertex.Vertex{
+				Position:  mgl32.Vec3{float32(x), t.edgeHeight(c, x, z), float32(z)},
+				Normal:    dX.Cross(dZ).Normalize(),
+				TexCoords: mgl32.Vec2{float32(gx) / t.textureScale, float32(gz) / t.textureScale},
+			})
+		}
+	}
+	var indices []uint32
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			i0 := uint32(j*(n+1) + i)
+			i1 := i0 + 1
+			i2 := i0 + uint32(n+1)
+			i3 := i2 + 1
+			indices = append(indices, i0, i1, i2, i2, i1, i3)
+		}
+	}
+	msh := mesh.NewTexturedMesh(vertices, indices, t.tex, t.wrapper)
+	msh.SetScale(t.scale)
+	msh.SetPosition(mgl32.Vec3{
+		t.position.X() + float32(c.key.x*t.chunkSize)*t.scale.X(),
+		t.position.Y(),
+		t.position.Z() + float32(c.key.z*t.chunkSize)*t.scale.Z(),
+	})
+	return msh
+}
+
+// gridHeight returns the height value of the given grid point from the loaded chunks.
+func (t *ChunkedTerrain) gridHeight(x, z int) (float32, bool) {
+	k := chunkKey{floorDiv(x, t.chunkSize), floorDiv(z, t.chunkSize)}
+	c, ok := t.chunks[k]
+	if !ok {
+		return 0, false
+	}
+	return c.heightMap[z-k.z*t.chunkSize][x-k.x*t.chunkSize], true
+}
+
+// HeightAtPos returns the world space height of the surface in the given world position, and nil.
+// The value is interpolated from the full detail height values, the same way as the Terrain does,
+// so that it is continuous on the chunk borders. If the chunks of the position are not loaded,
+// it returns -1 and error.
+func (t *ChunkedTerrain) HeightAtPos(pos mgl32.Vec3) (float32, error) {
+	posX := float64((pos.X() - t.position.X()) / t.scale.X())
+	posZ := float64((pos.Z() - t.position.Z()) / t.scale.Z())
+	x1, z1 := int(math.Floor(posX)), int(math.Floor(posZ))
+	wX, wZ := float32(posX-math.Floor(posX)), float32(posZ-math.Floor(posZ))
+	var h [4]float32
+	for i, p := range [4][2]int{{x1, z1}, {x1 + 1, z1}, {x1, z1 + 1}, {x1 + 1, z1 + 1}} {
+		value, ok := t.gridHeight(p[0], p[1])
+		if !ok {
+			return -1, ErrorNotAboveTheSurface
+		}
+		h[i] = value
+	}
+	height := (h[2]*(1.0-wX)+h[3]*wX)*wZ + (h[0]*(1.0-wX)+h[1]*wX)*(1-wZ)
+	return t.position.Y() + height*t.scale.Y(), nil
+}
+
+// CollideTestWithSphere is the collision detection function for chunked terrain vs sphere.
+func (t *ChunkedTerrain) CollideTestWithSphere(boundingSphere *coldet.Sphere) bool {
+	height, err := t.HeightAtPos(mgl32.Vec3{boundingSphere.X(), boundingSphere.Y(), boundingSphere.Z()})
+	if err != nil {
+		return false
+	}
+	boundingPoint := coldet.NewBoundingPoint([3]float32{boundingSphere.X(), height, boundingSphere.Z()})
+	return coldet.CheckPointInSphere(*boundingPoint, *boundingSphere)
+}
+
+// abs returns the absolute value of the given integer.
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// latticeValue returns a pseudo random value in [0, 1) for the given seed and grid point.
+// It is a hash, so that the value doesn't depend on the order of the calls.
+func latticeValue(seed int64, x, z int) float32 {
+	h := uint64(seed) ^ uint64(int64(x))*0x9E3779B97F4A7C15 ^ uint64(int64(z))*0xC2B2AE3D27D4EB4F
+	h ^= h >> 33
+	h *= 0xFF51AFD7ED558CCD
+	h ^= h >> 33
+	h *= 0xC4CEB9FE1A85EC53
+	h ^= h >> 33
+	return float32(h>>40) / float32(1<<24)
+}
+
+// valueNoise returns the smooth interpolated lattice value in the given point. The lattice
+// points are period grid units away from each other.
+func valueNoise(seed int64, x, z, period int) float32 {
+	cx, cz := floorDiv(x, period), floorDiv(z, period)
+	fx := float32(x-cx*period) / float32(period)
+	fz := float32(z-cz*period) / float32(period)
+	// smoothstep
+	fx = fx * fx * (3 - 2*fx)
+	fz = fz * fz * (3 - 2*fz)
+	v00 := latticeValue(seed, cx, cz)
+	v10 := latticeValue(seed, cx+1, cz)
+	v01 := latticeValue(seed, cx, cz+1)
+	v11 := latticeValue(seed, cx+1, cz+1)
+	return (v00*(1-fx)+v10*fx)*(1-fz) + (v01*(1-fx)+v11*fx)*fz
+}
+
+// NewValueNoiseHeightFunction returns a height function, that is the sum of octaves value noise layers.
+// The feature size is the distance of the lattice points of the first layer, it is halved in every
+// following layer, with half amplitude. The values are between minH and maxH. The same seed gives
+// the same surface.
+func NewValueNoiseHeightFunction(seed int64, minH, maxH float32, featureSize, octaves int) HeightFunction {
+	return func(x, z int) float32 {
+		sum, amplitude, total := float32(0), float32(1), float32(0)
+		period := featureSize
+		for o := 0; o < octaves && period > 0; o++ {
+			sum += valueNoise(seed+int64(o), x, z, period) * amplitude
+			total += amplitude
+			amplitude /= 2
+			period /= 2
+		}
+		if total == 0 {
+			return minH
+		}
+		return minH + sum/total*(maxH-minH)
+	}
+}
+
+// ChunkedTerrainBuilder is a helper structure for generating chunked terrain.
+type ChunkedTerrainBuilder struct {
+	textureLayers
+	chunkSize       int
+	viewDistance    int
+	lodLevels       int
+	lodDistance     float32
+	chunksPerUpdate int
+	seed            int64
+	minH, maxH      float32
+	featureSize     int
+	octaves         int
+	height          HeightFunction
+	camera          interfaces.Camera
+	wrapper         interfaces.GLWrapper
+	tex             texture.Textures
+	textureScale    float32
+	scale           mgl32.Vec3
+	position        mgl32.Vec3
+	debugMode       bool
+}
+
+// NewChunkedTerrainBuilder returns a ChunkedTerrainBuilder with default settings.
+func NewChunkedTerrainBuilder() *ChunkedTerrainBuilder {
+	return &ChunkedTerrainBuilder{
+		chunkSize:       defaultChunkSize,
+		viewDistance:    defaultViewDistance,
+		lodLevels:       defaultLODLevels,
+		lodDistance:     defaultLODDistance,
+		chunksPerUpdate: defaultChunksPerUpdate,
+		seed:            defaultSeed,
+		minH:            defaultMinHeight,
+		maxH:            defaultMaxHeight,
+		featureSize:     defaultFeatureSize,
+		octaves:         defaultOctaves,
+		textureScale:    defaultTextureScale,
+		scale:           mgl32.Vec3{1, 1, 1},
+		position:        mgl32.Vec3{0, 0, 0},
+		debugMode:       false,
+		textureLayers:   newTextureLayers(),
+	}
+}
+
+// SetChunkSize updates the number of the cells in a chunk row. It has to be power of two.
+func (t *ChunkedTerrainBuilder) SetChunkSize(size int) {
+	t.chunkSize = size
+}
+
+// SetViewDistance updates the number of the loaded chunks around the camera chunk in every direction.
+func (t *ChunkedTerrainBuilder) SetViewDistance(d int) {
+	t.viewDistance = d
+}
+
+// SetLODLevels updates the number of the detail levels. The sampling step of the level i is 2^i,
+// so that 2^(levels-1) can't be greater than the chunk size.
+func (t *ChunkedTerrainBuilder) SetLODLevels(levels int) {
+	t.lodLevels = levels
+}
+
+// SetLODDistance updates the distance of the level changes.
+func (t *ChunkedTerrainBuilder) SetLODDistance(d float32) {
+	t.lodDistance = d
+}
+
+// SetChunksPerUpdate updates the max number of the generated chunks in an Update call.
+// With 0, every missing chunk is generated immediately.
+func (t *ChunkedTerrainBuilder) SetChunksPerUpdate(n int) {
+	t.chunksPerUpdate = n
+}
+
+// SetSeed updates the seed of the default height function.
+func (t *ChunkedTerrainBuilder) SetSeed(seed int64) {
+	t.seed = seed
+}
+
+// SetMinHeight updates the minH of the default height function.
+func (t *ChunkedTerrainBuilder) SetMinHeight(height float32) {
+	t.minH = height
+}
+
+// SetMaxHeight updates the maxH of the default height function.
+func (t *ChunkedTerrainBuilder) SetMaxHeight(height float32) {
+	t.maxH = height
+}
+
+// SetFeatureSize updates the size of the largest hills of the default height function in grid units.
+func (t *ChunkedTerrainBuilder) SetFeatureSize(size int) {
+	t.featureSize = size
+}
+
+// SetOctaves updates the number of the noise layers of the default height function.
+func (t *ChunkedTerrainBuilder) SetOctaves(octaves int) {
+	t.octaves = octaves
+}
+
+// SetHeightFunction sets a custom height function. If it is set, the seed, minH, maxH,
+// featureSize, octaves values are not used.
+func (t *ChunkedTerrainBuilder) SetHeightFunction(f HeightFunction) {
+	t.height = f
+}
+
+// SetHeightSampler sets the height function to the given sampler generator, like the heightmap.FBm or heightmap.Ridged.
+func (t *ChunkedTerrainBuilder) SetHeightSampler(s heightmap.Sampler) {
+	t.height = func(x, z int) float32 {
+		return s.HeightAt(float32(x), float32(z))
+	}
+}
+
+// SetCamera sets the camera.
+func (t *ChunkedTerrainBuilder) SetCamera(c interfaces.Camera) {
+	t.camera = c
+}
+
+// SetGlWrapper sets the wrapper.
+func (t *ChunkedTerrainBuilder) SetGlWrapper(w interfaces.GLWrapper) {
+	t.wrapper = w
+}
+
+// SetScale sets the scale. The X, Z values are the size of a cell, the Y value is the height multiplier.
+func (t *ChunkedTerrainBuilder) SetScale(s mgl32.Vec3) {
+	t.scale = s
+}
+
+// SetPosition sets the position of the grid origin.
+func (t *ChunkedTerrainBuilder) SetPosition(p mgl32.Vec3) {
+	t.position = p
+}
+
+// SetDebugMode updates the debug flag
+func (t *ChunkedTerrainBuilder) SetDebugMode(v bool) {
+	t.debugMode = v
+}
+
+// SetTextureScale updates the number of the grid cells, that are covered by one repeat of
+// the surface texture. It panics if the scale is not positive.
+func (t *ChunkedTerrainBuilder) SetTextureScale(s float32) {
+	if s <= 0 {
+		panic("The texture scale has to be positive.")
+	}
+	t.textureScale = s
+}
+
+// SurfaceTextureGrass sets the surface texture to grass. The texture is repeated on the surface.
+func (t *ChunkedTerrainBuilder) SurfaceTextureGrass() {
+	_, filename, _, _ := runtime.Caller(1)
+	fileDir := path.Dir(filename)
+	t.tex.AddTexture(fileDir+"/assets/grass.jpg", glwrapper.REPEAT, glwrapper.REPEAT, glwrapper.LINEAR, glwrapper.LINEAR, "material.diffuse", t.wrapper)
+	t.tex.AddTexture(fileDir+"/assets/grass.jpg", glwrapper.REPEAT, glwrapper.REPEAT, glwrapper.LINEAR, glwrapper.LINEAR, "material.specular", t.wrapper)
+}
+
+// Build returns a ChunkedTerrain. The chunks around the camera (or the position without camera)
+// are generated immediately. It panics if the chunk size is not power of two, or the
+// lod levels don't fit to the chunk size.
+func (t *ChunkedTerrainBuilder) Build() *ChunkedTerrain {
+	if t.chunkSize <= 0 || t.chunkSize&(t.chunkSize-1) != 0 {
+		panic("The chunk size has to be power of two.")
+	}
+	if t.lodLevels < 1 || 1<<uint(t.lodLevels-1) > t.chunkSize {
+		panic("The number of the lod levels has to be positive and 2^(levels-1) can't be greater than the chunk size.")
+	}
+	height := t.height
+	if height == nil {
+		height = NewValueNoiseHeightFunction(t.seed, t.minH, t.maxH, t.featureSize, t.octaves)
+	}
+	terrain := &ChunkedTerrain{
+		Model:           *newModel(),
+		chunks:          make(map[chunkKey]*terrainChunk),
+		height:          height,
+		chunkSize:       t.chunkSize,
+		viewDistance:    t.viewDistance,
+		lodLevels:       t.lodLevels,
+		lodDistance:     t.lodDistance,
+		chunksPerUpdate: t.chunksPerUpdate,
+		camera:          t.camera,
+		tex:             t.textures(t.tex, t.wrapper),
+		textureScale:    t.textureScale,
+		wrapper:         t.wrapper,
+		scale:           t.scale,
+		position:        t.position,
+		debugMode:       t.debugMode,
+	}
+	// the chunked terrain is unbounded, a splat map can't cover it, so that the layers
+	// are blended by height and slope.
+	t.setUniforms(&terrain.Model)
+	terrain.stream(terrain.focus(), 0)
+	return terrain
+}
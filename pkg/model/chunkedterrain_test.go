@@ -0,0 +1,267 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/camera"
+	"github.com/akosgarai/playground_engine/pkg/heightmap"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func newTestChunkedTerrainBuilder() *ChunkedTerrainBuilder {
+	b := NewChunkedTerrainBuilder()
+	b.SetGlWrapper(wrapperMock)
+	b.SetChunkSize(8)
+	b.SetViewDistance(1)
+	b.SetLODLevels(3)
+	b.SetLODDistance(8)
+	b.SetMinHeight(-2)
+	b.SetMaxHeight(5)
+	b.SetFeatureSize(8)
+	return b
+}
+func TestNewChunkedTerrainBuilder(t *testing.T) {
+	b := NewChunkedTerrainBuilder()
+	if b.chunkSize != defaultChunkSize {
+		t.Errorf("Invalid default chunk size. Instead of '%d', we have '%d'.", defaultChunkSize, b.chunkSize)
+	}
+	if b.viewDistance != defaultViewDistance {
+		t.Errorf("Invalid default view distance. Instead of '%d', we have '%d'.", defaultViewDistance, b.viewDistance)
+	}
+	if b.lodLevels != defaultLODLevels {
+		t.Errorf("Invalid default lod levels. Instead of '%d', we have '%d'.", defaultLODLevels, b.lodLevels)
+	}
+	if b.height != nil {
+		t.Error("The default height function should be nil.")
+	}
+}
+func TestChunkedTerrainBuilderBuildPanics(t *testing.T) {
+	for _, tt := range []struct {
+		size, levels int
+	}{
+		{0, 1}, {6, 1}, {8, 0}, {8, 5},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Build should have panicked with size '%d' and levels '%d'.", tt.size, tt.levels)
+				}
+			}()
+			b := newTestChunkedTerrainBuilder()
+			b.SetChunkSize(tt.size)
+			b.SetLODLevels(tt.levels)
+			b.Build()
+		}()
+	}
+}
+func TestChunkedTerrainBuild(t *testing.T) {
+	terr := newTestChunkedTerrainBuilder().Build()
+	if terr.ChunkCount() != 9 {
+		t.Errorf("Invalid number of chunks. Instead of '9', we have '%d'.", terr.ChunkCount())
+	}
+	if len(terr.meshes) != 9 {
+		t.Errorf("Invalid number of meshes. Instead of '9', we have '%d'.", len(terr.meshes))
+	}
+	for x := -1; x <= 1; x++ {
+		for z := -1; z <= 1; z++ {
+			if !terr.IsChunkLoaded(x, z) {
+				t.Errorf("Chunk '%d, %d' should be loaded.", x, z)
+			}
+		}
+	}
+	if terr.ChunkMesh(5, 5) != nil || terr.ChunkLOD(5, 5) != -1 {
+		t.Error("Chunk '5, 5' shouldn't be loaded.")
+	}
+}
+func TestChunkedTerrainDeterministic(t *testing.T) {
+	b := newTestChunkedTerrainBuilder()
+	b.SetSeed(42)
+	t1 := b.Build()
+	t2 := b.Build()
+	b.SetSeed(43)
+	t3 := b.Build()
+	different := false
+	for x := -8; x < 16; x++ {
+		for z := -8; z < 16; z++ {
+			h1, _ := t1.gridHeight(x, z)
+			h2, _ := t2.gridHeight(x, z)
+			h3, _ := t3.gridHeight(x, z)
+			if h1 != h2 {
+				t.Errorf("The heights should be the same with the same seed. '%f' '%f'.", h1, h2)
+			}
+			if h1 != h3 {
+				different = true
+			}
+			if h1 < -2 || h1 > 5 {
+				t.Errorf("Height '%f' is out of the range.", h1)
+			}
+		}
+	}
+	if !different {
+		t.Error("The heights should be different with different seed.")
+	}
+}
+func TestChunkedTerrainStreaming(t *testing.T) {
+	b := newTestChunkedTerrainBuilder()
+	b.SetChunksPerUpdate(2)
+	cam := camera.NewCamera(mgl32.Vec3{4, 10, 4}, mgl32.Vec3{0, 1, 0}, 0, 0)
+	b.SetCamera(cam)
+	terr := b.Build()
+	// move the camera 5 chunks away, every loaded chunk is out of the unload distance.
+	cam.SetPosition(mgl32.Vec3{44, 10, 4})
+	terr.Update(10)
+	if terr.ChunkCount() != 2 {
+		t.Errorf("Invalid number of chunks. Instead of '2', we have '%d'.", terr.ChunkCount())
+	}
+	// the closest chunk has been loaded first.
+	if !terr.IsChunkLoaded(5, 0) {
+		t.Error("The camera chunk should be loaded first.")
+	}
+	for i := 0; i < 4; i++ {
+		terr.Update(10)
+	}
+	if terr.ChunkCount() != 9 {
+		t.Errorf("Invalid number of chunks. Instead of '9', we have '%d'.", terr.ChunkCount())
+	}
+	if terr.IsChunkLoaded(1, 0) {
+		t.Error("The far chunk should be unloaded.")
+	}
+	// the chunks in the unload distance are kept.
+	cam.SetPosition(mgl32.Vec3{52, 10, 4})
+	terr.Update(10)
+	if !terr.IsChunkLoaded(4, 0) {
+		t.Error("The chunk in the unload distance should be kept.")
+	}
+}
+func TestChunkedTerrainLOD(t *testing.T) {
+	b := newTestChunkedTerrainBuilder()
+	b.SetViewDistance(3)
+	b.SetCamera(camera.NewCamera(mgl32.Vec3{4, 0, 4}, mgl32.Vec3{0, 1, 0}, 0, 0))
+	terr := b.Build()
+	if terr.ChunkLOD(0, 0) != 0 {
+		t.Errorf("Invalid lod of the camera chunk. Instead of '0', we have '%d'.", terr.ChunkLOD(0, 0))
+	}
+	if terr.ChunkLOD(1, 0) != 1 {
+		t.Errorf("Invalid lod of the neighbour chunk. Instead of '1', we have '%d'.", terr.ChunkLOD(1, 0))
+	}
+	if terr.ChunkLOD(3, 3) != 2 {
+		t.Errorf("Invalid lod of the far chunk. Instead of '2', we have '%d'.", terr.ChunkLOD(3, 3))
+	}
+	if n := len(terr.ChunkMesh(3, 3).(*mesh.TexturedMesh).Vertices); n != 9 {
+		t.Errorf("Invalid number of vertices. Instead of '9', we have '%d'.", n)
+	}
+}
+func TestChunkedTerrainTexCoords(t *testing.T) {
+	b := newTestChunkedTerrainBuilder()
+	b.SetViewDistance(3)
+	b.SetTextureScale(4)
+	b.SetCamera(camera.NewCamera(mgl32.Vec3{4, 0, 4}, mgl32.Vec3{0, 1, 0}, 0, 0))
+	terr := b.Build()
+	// the texture coordinates depend only on the grid position, independently from the level.
+	for _, k := range []chunkKey{{0, 0}, {1, 0}, {3, 3}, {-2, 1}} {
+		for _, v := range terr.ChunkMesh(k.x, k.z).(*mesh.TexturedMesh).Vertices {
+			expected := mgl32.Vec2{(float32(k.x*8) + v.Position.X()) / 4, (float32(k.z*8) + v.Position.Z()) / 4}
+			if v.TexCoords != expected {
+				t.Errorf("Invalid texture coordinates of chunk '%v' at '%v'. Instead of '%v', we have '%v'.", k, v.Position, expected, v.TexCoords)
+			}
+		}
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("The not positive texture scale should panic.")
+		}
+	}()
+	b.SetTextureScale(0)
+}
+func TestChunkedTerrainSeams(t *testing.T) {
+	b := newTestChunkedTerrainBuilder()
+	b.SetViewDistance(3)
+	b.SetCamera(camera.NewCamera(mgl32.Vec3{4, 0, 4}, mgl32.Vec3{0, 1, 0}, 0, 0))
+	terr := b.Build()
+	fine := terr.ChunkMesh(0, 0).(*mesh.TexturedMesh)
+	coarse := terr.ChunkMesh(1, 0).(*mesh.TexturedMesh)
+	// the world space positions of the coarse edge vertices.
+	var coarseEdge []mgl32.Vec3
+	for _, v := range coarse.Vertices {
+		if v.Position.X() == 0 {
+			coarseEdge = append(coarseEdge, mgl32.TransformCoordinate(v.Position, coarse.ModelTransformation()))
+		}
+	}
+	for _, v := range fine.Vertices {
+		if v.Position.X() != 8 {
+			continue
+		}
+		p := mgl32.TransformCoordinate(v.Position, fine.ModelTransformation())
+		for i := 0; i+1 < len(coarseEdge); i++ {
+			a, c := coarseEdge[i], coarseEdge[i+1]
+			if p.Z() < a.Z() || p.Z() > c.Z() {
+				continue
+			}
+			w := (p.Z() - a.Z()) / (c.Z() - a.Z())
+			expected := a.Y()*(1-w) + c.Y()*w
+			if math.Abs(float64(expected-p.Y())) > 0.0001 {
+				t.Errorf("Crack on the chunk border at '%v'. Instead of '%f', we have '%f'.", p, expected, p.Y())
+			}
+		}
+	}
+}
+func TestChunkedTerrainHeightAtPos(t *testing.T) {
+	b := newTestChunkedTerrainBuilder()
+	b.SetScale(mgl32.Vec3{2, 3, 2})
+	b.SetPosition(mgl32.Vec3{1, 1, 1})
+	terr := b.Build()
+	// the grid point (2, 3) is at world (5, 7).
+	h, err := terr.HeightAtPos(mgl32.Vec3{5, 0, 7})
+	if err != nil {
+		t.Errorf("It shouldn't return error. '%v'.", err)
+	}
+	expected := 1 + terr.height(2, 3)*3
+	if math.Abs(float64(h-expected)) > 0.0001 {
+		t.Errorf("Invalid height. Instead of '%f', we have '%f'.", expected, h)
+	}
+	// the chunk border is at world x = 17.
+	h1, err1 := terr.HeightAtPos(mgl32.Vec3{16.999, 0, 5})
+	h2, err2 := terr.HeightAtPos(mgl32.Vec3{17.001, 0, 5})
+	if err1 != nil || err2 != nil {
+		t.Errorf("It shouldn't return error. '%v' '%v'.", err1, err2)
+	}
+	if math.Abs(float64(h1-h2)) > 0.01 {
+		t.Errorf("The height should be continuous on the border. '%f' '%f'.", h1, h2)
+	}
+	if _, err := terr.HeightAtPos(mgl32.Vec3{1000, 0, 0}); err != ErrorNotAboveTheSurface {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorNotAboveTheSurface, err)
+	}
+}
+func TestChunkedTerrainHeightFunction(t *testing.T) {
+	b := newTestChunkedTerrainBuilder()
+	b.SetHeightFunction(func(x, z int) float32 { return 2 })
+	terr := b.Build()
+	h, err := terr.HeightAtPos(mgl32.Vec3{-3.5, 0, 9.2})
+	if err != nil || h != 2 {
+		t.Errorf("Invalid height. Instead of '2', we have '%f', '%v'.", h, err)
+	}
+}
+func TestChunkedTerrainHeightSampler(t *testing.T) {
+	b := newTestChunkedTerrainBuilder()
+	s := heightmap.NewFBm(heightmap.NewSimplexNoise(1), 3, 1.0/16, 0, 4)
+	b.SetHeightSampler(s)
+	terr := b.Build()
+	h, err := terr.HeightAtPos(mgl32.Vec3{-5, 0, 3})
+	if err != nil || h != s.HeightAt(-5, 3) {
+		t.Errorf("Invalid height. Instead of '%f', we have '%f', '%v'.", s.HeightAt(-5, 3), h, err)
+	}
+}
+func TestFloorDiv(t *testing.T) {
+	for _, tt := range []struct {
+		a, b, result int
+	}{
+		{0, 8, 0}, {7, 8, 0}, {8, 8, 1}, {-1, 8, -1}, {-8, 8, -1}, {-9, 8, -2},
+	} {
+		if r := floorDiv(tt.a, tt.b); r != tt.result {
+			t.Errorf("Invalid floorDiv(%d, %d). Instead of '%d', we have '%d'.", tt.a, tt.b, tt.result, r)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"math"
 
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
 	"github.com/akosgarai/playground_engine/pkg/interfaces"
 	"github.com/akosgarai/playground_engine/pkg/material"
 	"github.com/akosgarai/playground_engine/pkg/mesh"
@@ -152,3 +153,31 @@ func (fi *FormItemBase) GetCursorInitialPosition() mgl32.Vec3 {
 func (fi *FormItemBase) GetVectorCursorInitialPosition() mgl32.Vec3 {
 	return mgl32.Vec3{(fi.GetVectorTargetWidth()*0.85 - fi.GetCursorWidth()) / 2, -0.01, 0.0}
 }
+
+// paperTexture returns the texture of the writable meshes.
+func paperTexture(wrapper interfaces.GLWrapper) texture.Textures {
+	var tex texture.Textures
+	tex.AddTexture(baseDirModel()+"/assets/paper.png", glwrapper.CLAMP_TO_EDGE, glwrapper.CLAMP_TO_EDGE, glwrapper.LINEAR, glwrapper.LINEAR, "tex.diffuse", wrapper)
+	tex.AddTexture(baseDirModel()+"/assets/paper.png", glwrapper.CLAMP_TO_EDGE, glwrapper.CLAMP_TO_EDGE, glwrapper.LINEAR, glwrapper.LINEAR, "tex.specular", wrapper)
+	return tex
+}
+
+// solidTexture returns a transparent texture with the given alpha value, the color of the mesh
+// is given by its material.
+func solidTexture(alpha uint8, wrapper interfaces.GLWrapper) texture.Textures {
+	var tex texture.Textures
+	tex.TransparentTexture(1, 1, alpha, "tex.diffuse", wrapper)
+	tex.TransparentTexture(1, 1, alpha, "tex.specular", wrapper)
+	return tex
+}
+
+// newChildMesh returns a rectangle mesh with the given size and texture, that is the child
+// of the parent mesh. The position is relative to the parent.
+func newChildMesh(width, height float32, tex texture.Textures, mat *material.Material, parent interfaces.Mesh, position mgl32.Vec3, wrapper interfaces.GLWrapper) *mesh.TexturedMaterialMesh {
+	v, i, bo := rectangle.NewExact(width, height).MeshInput()
+	msh := mesh.NewTexturedMaterialMesh(v, i, tex, mat, wrapper)
+	msh.SetParent(parent)
+	msh.SetPosition(position)
+	msh.SetBoundingObject(bo)
+	return msh
+}
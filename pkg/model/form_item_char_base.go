@@ -17,6 +17,13 @@ type FormItemCharBase struct {
 	charOffsets []float32
 	value       string
 	maxLen      int
+	edit        textEdit
+	placeholder string
+	// the rules of the form item, they are set by the constructors of the items.
+	length   func(string) int
+	accept   func(string) bool
+	validate func(string) bool
+	changed  func()
 }
 
 // NewFormItemCharBase returns a FormItemCharBase that could be the base of text based form items.
@@ -47,16 +54,31 @@ func NewFormItemCharBase(maxWidth, widthRatio, aspect float32, label, descriptio
 		charOffsets:  []float32{},
 		value:        "",
 		maxLen:       inputMaxLen,
+		placeholder:  "",
+		length:       func(s string) int { return len(s) },
+		accept:       func(s string) bool { return true },
+		validate:     func(s string) bool { return true },
 	}
 }
-func (fi *FormItemCharBase) cursorOffsetX() float32 {
-	result := float32(0.0)
-	if len(fi.charOffsets) > 0 {
-		for i := 0; i < len(fi.charOffsets); i++ {
-			result = result + fi.charOffsets[i]
-		}
+
+// input returns the editable input of the form item.
+func (fi *FormItemCharBase) input() *charInput {
+	return &charInput{
+		value:    &fi.value,
+		offsets:  &fi.charOffsets,
+		edit:     &fi.edit,
+		maxLen:   fi.maxLen,
+		length:   fi.length,
+		accept:   fi.accept,
+		validate: fi.validate,
+		changed:  fi.changed,
 	}
-	return result
+}
+
+// updateCursor moves the cursor mesh to the current position of the cursor.
+func (fi *FormItemCharBase) updateCursor() {
+	in := fi.input()
+	fi.cursor.SetPosition(mgl32.Vec3{fi.GetCursorInitialPosition().X() - in.offsetX(in.cursorIndex()), 0.0, -0.01})
 }
 
 // GetTarget returns the input target Mesh
@@ -67,7 +89,7 @@ func (fi *FormItemCharBase) GetTarget() interfaces.Mesh {
 // AddCursor displays a cursor on the target surface.
 func (fi *FormItemCharBase) AddCursor() {
 	fi.AddMesh(fi.cursor)
-	fi.cursor.SetPosition(mgl32.Vec3{fi.GetCursorInitialPosition().X() - fi.cursorOffsetX(), 0.0, -0.01})
+	fi.updateCursor()
 }
 
 // DeleteCursor removes the cursor from the meshes.
@@ -77,21 +99,105 @@ func (fi *FormItemCharBase) DeleteCursor() {
 	}
 }
 
-// MoveCursorWithOffset moves to cursor with the offset.
-// It adds the new offset to the offsets, increments the sum offset
-// and sets the cursor position.
-func (fi *FormItemCharBase) MoveCursorWithOffset(offsetX float32) {
-	fi.charOffsets = append(fi.charOffsets, offsetX)
-	fi.cursor.SetPosition(mgl32.Vec3{fi.GetCursorInitialPosition().X() - fi.cursorOffsetX(), 0.0, -0.01})
+// ValueToString returns the string representation of the value of the form item.
+func (fi *FormItemCharBase) ValueToString() string {
+	return fi.value
 }
 
-// StepBackCursor moves the cursor back after a character deletion.
-func (fi *FormItemCharBase) StepBackCursor() {
-	fi.charOffsets = fi.charOffsets[:len(fi.charOffsets)-1]
-	fi.cursor.SetPosition(mgl32.Vec3{fi.GetCursorInitialPosition().X() - fi.cursorOffsetX(), 0.0, -0.01})
+// CharCallback inserts the character to the position of the cursor, the selected
+// characters are replaced. If the new value is not accepted by the form item or
+// by its validator, the value is not updated.
+func (fi *FormItemCharBase) CharCallback(r rune, offsetX float32) {
+	fi.input().insert(r, offsetX)
+	fi.updateCursor()
 }
 
-// ValueToString returns the string representation of the value of the form item.
-func (fi *FormItemCharBase) ValueToString() string {
-	return fi.value
+// DeleteLastCharacter removes the selected characters or the character before the cursor.
+func (fi *FormItemCharBase) DeleteLastCharacter() {
+	fi.input().deleteBackward()
+	fi.updateCursor()
+}
+
+// DeleteNextCharacter removes the selected characters or the character after the cursor.
+func (fi *FormItemCharBase) DeleteNextCharacter() {
+	fi.input().deleteForward()
+	fi.updateCursor()
+}
+
+// DeleteSelection removes the selected characters.
+func (fi *FormItemCharBase) DeleteSelection() {
+	fi.input().deleteSelection()
+	fi.updateCursor()
+}
+
+// MoveCursor moves the cursor with the given number of characters. If the selecting
+// flag is set, the selection is extended, otherwise it is removed.
+func (fi *FormItemCharBase) MoveCursor(step int, selecting bool) {
+	in := fi.input()
+	in.moveTo(in.cursorIndex()+step, selecting)
+	fi.updateCursor()
+}
+
+// MoveCursorToStart moves the cursor before the first character.
+func (fi *FormItemCharBase) MoveCursorToStart(selecting bool) {
+	fi.input().moveTo(0, selecting)
+	fi.updateCursor()
+}
+
+// MoveCursorToEnd moves the cursor after the last character.
+func (fi *FormItemCharBase) MoveCursorToEnd(selecting bool) {
+	in := fi.input()
+	in.moveTo(in.numRunes(), selecting)
+	fi.updateCursor()
+}
+
+// SetCursorFromPosition moves the cursor to the character boundary, that is the closest
+// to the x coordinate of the pointer.
+func (fi *FormItemCharBase) SetCursorFromPosition(x float32, selecting bool) {
+	textStart := fi.GetSurface().GetPosition().X() + fi.GetTarget().GetPosition().X() + fi.GetCursorInitialPosition().X()
+	in := fi.input()
+	in.moveTo(in.indexAt(textStart-x), selecting)
+	fi.updateCursor()
+}
+
+// SelectAll selects the whole value.
+func (fi *FormItemCharBase) SelectAll() {
+	fi.input().selectAll()
+	fi.updateCursor()
+}
+
+// GetCursorIndex returns the position of the cursor in the value.
+func (fi *FormItemCharBase) GetCursorIndex() int {
+	return fi.input().cursorIndex()
+}
+
+// GetSelection returns the start and the end index of the selected characters.
+// Without selection, both of them are the cursor index.
+func (fi *FormItemCharBase) GetSelection() (int, int) {
+	return fi.input().selection()
+}
+
+// GetSelectedText returns the selected part of the value.
+func (fi *FormItemCharBase) GetSelectedText() string {
+	return fi.input().selectedText()
+}
+
+// SetMaxLength sets the max length of the value.
+func (fi *FormItemCharBase) SetMaxLength(maxLen int) {
+	fi.maxLen = maxLen
+}
+
+// GetMaxLength returns the max length of the value.
+func (fi *FormItemCharBase) GetMaxLength() int {
+	return fi.maxLen
+}
+
+// SetPlaceholder sets the text, that is displayed when the value is empty.
+func (fi *FormItemCharBase) SetPlaceholder(placeholder string) {
+	fi.placeholder = placeholder
+}
+
+// GetPlaceholder returns the text, that is displayed when the value is empty.
+func (fi *FormItemCharBase) GetPlaceholder() string {
+	return fi.placeholder
 }
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"strconv"
+)
+
+// textEdit is the editing state of a text input. The cursor and the selection anchor
+// are counted from the end of the value, so that the typed characters are appended
+// by default. If the anchor is equal with the cursor, nothing is selected.
+type textEdit struct {
+	cursor int
+	anchor int
+}
+
+// charInput connects the value, the char offsets and the editing state of a text input
+// with the rules of the form item. The FormItemCharBase has one input, the FormItemVector
+// has one for every component.
+type charInput struct {
+	value   *string
+	offsets *[]float32
+	edit    *textEdit
+	maxLen  int
+	// length returns the length of the value, that is compared to the max length.
+	length func(string) int
+	// accept returns true, if the value could be the value of the form item.
+	accept func(string) bool
+	// validate is the validator of the form item. It is called after the typed characters.
+	validate func(string) bool
+	// changed is called after the update of the value.
+	changed func()
+}
+
+// runeLength returns the number of the characters of the value.
+func runeLength(value string) int {
+	return len([]rune(value))
+}
+
+// numRunes returns the number of the characters of the input. The offsets are extended,
+// if the value was set without them.
+func (in *charInput) numRunes() int {
+	n := runeLength(*in.value)
+	for len(*in.offsets) < n {
+		*in.offsets = append(*in.offsets, 0.0)
+	}
+	if len(*in.offsets) > n {
+		*in.offsets = (*in.offsets)[:n]
+	}
+	return n
+}
+
+// cursorIndex returns the position of the cursor from the start of the value.
+func (in *charInput) cursorIndex() int {
+	return in.indexFromEnd(in.edit.cursor)
+}
+func (in *charInput) indexFromEnd(fromEnd int) int {
+	n := in.numRunes()
+	index := n - fromEnd
+	if index < 0 {
+		return 0
+	}
+	if index > n {
+		return n
+	}
+	return index
+}
+
+// selection returns the start and the end index of the selected characters.
+func (in *charInput) selection() (int, int) {
+	cursor := in.cursorIndex()
+	anchor := in.indexFromEnd(in.edit.anchor)
+	if anchor < cursor {
+		return anchor, cursor
+	}
+	return cursor, anchor
+}
+
+// moveTo moves the cursor to the given index. If the selecting flag is set, the
+// selection is extended to the new position, otherwise the selection is removed.
+func (in *charInput) moveTo(index int, selecting bool) {
+	n := in.numRunes()
+	if index < 0 {
+		index = 0
+	}
+	if index > n {
+		index = n
+	}
+	in.edit.cursor = n - index
+	if !selecting {
+		in.edit.anchor = in.edit.cursor
+	}
+}
+
+// selectAll selects the whole value. The cursor goes to the end.
+func (in *charInput) selectAll() {
+	in.edit.cursor = 0
+	in.edit.anchor = in.numRunes()
+}
+
+// selectedText returns the selected part of the value.
+func (in *charInput) selectedText() string {
+	start, end := in.selection()
+	return string([]rune(*in.value)[start:end])
+}
+
+// offsetX returns the sum of the char offsets before the given index.
+func (in *charInput) offsetX(index int) float32 {
+	in.numRunes()
+	result := float32(0.0)
+	for i := 0; i < index && i < len(*in.offsets); i++ {
+		result = result + (*in.offsets)[i]
+	}
+	return result
+}
+
+// indexAt returns the index of the character boundary that is the closest to the
+// given distance from the start of the text.
+func (in *charInput) indexAt(distance float32) int {
+	n := in.numRunes()
+	sum := float32(0.0)
+	for i := 0; i < n; i++ {
+		width := (*in.offsets)[i]
+		if distance < sum+width/2 {
+			return i
+		}
+		sum = sum + width
+	}
+	return n
+}
+
+// replace replaces the characters between the start and the end index with the text,
+// then it moves the cursor after the inserted text. If the new value isn't accepted,
+// the input is not updated and it returns false.
+func (in *charInput) replace(start, end int, text string, textOffsets []float32, typed bool) bool {
+	in.numRunes()
+	runes := []rune(*in.value)
+	newValue := string(runes[:start]) + text + string(runes[end:])
+	if in.length(newValue) > in.maxLen && in.length(newValue) > in.length(*in.value) {
+		return false
+	}
+	if !in.accept(newValue) {
+		return false
+	}
+	if typed && in.validate != nil && !in.validate(newValue) {
+		return false
+	}
+	var offsets []float32
+	offsets = append(offsets, (*in.offsets)[:start]...)
+	offsets = append(offsets, textOffsets...)
+	offsets = append(offsets, (*in.offsets)[end:]...)
+	*in.value = newValue
+	*in.offsets = offsets
+	in.moveTo(start+runeLength(text), false)
+	if in.changed != nil {
+		in.changed()
+	}
+	return true
+}
+
+// insert types the character to the position of the cursor. The selected characters
+// are replaced with it.
+func (in *charInput) insert(r rune, offsetX float32) bool {
+	start, end := in.selection()
+	return in.replace(start, end, string(r), []float32{offsetX}, true)
+}
+
+// deleteBackward deletes the selection or the character before the cursor.
+func (in *charInput) deleteBackward() bool {
+	start, end := in.selection()
+	if start == end {
+		if start == 0 {
+			return false
+		}
+		start--
+	}
+	return in.replace(start, end, "", nil, false)
+}
+
+// deleteForward deletes the selection or the character after the cursor.
+func (in *charInput) deleteForward() bool {
+	start, end := in.selection()
+	if start == end {
+		if end == in.numRunes() {
+			return false
+		}
+		end++
+	}
+	return in.replace(start, end, "", nil, false)
+}
+
+// deleteSelection deletes the selected characters.
+func (in *charInput) deleteSelection() bool {
+	start, end := in.selection()
+	if start == end {
+		return false
+	}
+	return in.replace(start, end, "", nil, false)
+}
+
+// replayStates runs the state machine of a numeric form item on the value. The machine
+// is given with its state variable and its functions. It returns the final state, and false,
+// if the value contains an invalid character. The state variable keeps its original value.
+func replayStates(value string, state *string, validRune func(rune) bool, pushState func(rune)) (string, bool) {
+	original := *state
+	defer func() { *state = original }()
+	*state = "P"
+	for _, r := range value {
+		if !validRune(r) {
+			return "", false
+		}
+		pushState(r)
+	}
+	return *state, true
+}
+
+// parseFloat32 returns the float value of the string, or 0.0 if it can't be parsed.
+func parseFloat32(value string) float32 {
+	valueFloat, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return 0.0
+	}
+	return float32(valueFloat)
+}
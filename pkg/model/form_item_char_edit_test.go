@@ -0,0 +1,170 @@
+package model
+
+import (
+	"testing"
+)
+
+func typeString(fi interface{ CharCallback(rune, float32) }, s string) {
+	for _, r := range s {
+		fi.CharCallback(r, 0.1)
+	}
+}
+func TestFormItemCharMoveCursor(t *testing.T) {
+	fi := testFormItemText(t)
+	fi.AddCursor()
+	typeString(fi, "abd")
+	if fi.GetCursorIndex() != 3 {
+		t.Errorf("Invalid cursor index. Instead of '3', we have '%d'.", fi.GetCursorIndex())
+	}
+	fi.MoveCursor(-1, false)
+	fi.CharCallback('c', 0.1)
+	if fi.value != "abcd" {
+		t.Errorf("Invalid value. Instead of 'abcd', we have '%s'.", fi.value)
+	}
+	if fi.GetCursorIndex() != 3 {
+		t.Errorf("Invalid cursor index. Instead of '3', we have '%d'.", fi.GetCursorIndex())
+	}
+	fi.MoveCursorToStart(false)
+	fi.CharCallback('_', 0.1)
+	if fi.value != "_abcd" {
+		t.Errorf("Invalid value. Instead of '_abcd', we have '%s'.", fi.value)
+	}
+	fi.MoveCursor(-10, false)
+	if fi.GetCursorIndex() != 0 {
+		t.Errorf("Invalid cursor index. Instead of '0', we have '%d'.", fi.GetCursorIndex())
+	}
+	fi.MoveCursorToEnd(false)
+	if fi.GetCursorIndex() != 5 {
+		t.Errorf("Invalid cursor index. Instead of '5', we have '%d'.", fi.GetCursorIndex())
+	}
+	if len(fi.charOffsets) != 5 {
+		t.Errorf("Invalid number of offsets. Instead of '5', we have '%d'.", len(fi.charOffsets))
+	}
+}
+func TestFormItemCharDelete(t *testing.T) {
+	fi := testFormItemText(t)
+	fi.AddCursor()
+	typeString(fi, "abcd")
+	fi.MoveCursorToStart(false)
+	fi.DeleteLastCharacter()
+	if fi.value != "abcd" {
+		t.Errorf("Invalid value. Instead of 'abcd', we have '%s'.", fi.value)
+	}
+	fi.DeleteNextCharacter()
+	if fi.value != "bcd" {
+		t.Errorf("Invalid value. Instead of 'bcd', we have '%s'.", fi.value)
+	}
+	fi.MoveCursor(2, false)
+	fi.DeleteLastCharacter()
+	if fi.value != "bd" {
+		t.Errorf("Invalid value. Instead of 'bd', we have '%s'.", fi.value)
+	}
+	fi.MoveCursorToEnd(false)
+	fi.DeleteNextCharacter()
+	if fi.value != "bd" {
+		t.Errorf("Invalid value. Instead of 'bd', we have '%s'.", fi.value)
+	}
+	intItem := testFormItemInt(t)
+	intItem.AddCursor()
+	typeString(intItem, "-12")
+	intItem.MoveCursorToStart(false)
+	intItem.MoveCursor(1, false)
+	intItem.DeleteLastCharacter()
+	if intItem.value != "12" {
+		t.Errorf("Invalid value. Instead of '12', we have '%s'.", intItem.value)
+	}
+	if intItem.typeState != "PI" {
+		t.Errorf("Invalid typeState. Instead of 'PI', we have '%s'.", intItem.typeState)
+	}
+}
+func TestFormItemCharSelection(t *testing.T) {
+	fi := testFormItemText(t)
+	fi.AddCursor()
+	typeString(fi, "abcde")
+	fi.MoveCursor(-2, true)
+	start, end := fi.GetSelection()
+	if start != 3 || end != 5 {
+		t.Errorf("Invalid selection. Instead of '3-5', we have '%d-%d'.", start, end)
+	}
+	if fi.GetSelectedText() != "de" {
+		t.Errorf("Invalid selected text. Instead of 'de', we have '%s'.", fi.GetSelectedText())
+	}
+	fi.CharCallback('x', 0.1)
+	if fi.value != "abcx" {
+		t.Errorf("Invalid value. Instead of 'abcx', we have '%s'.", fi.value)
+	}
+	fi.MoveCursorToStart(true)
+	if fi.GetSelectedText() != "abcx" {
+		t.Errorf("Invalid selected text. Instead of 'abcx', we have '%s'.", fi.GetSelectedText())
+	}
+	fi.MoveCursor(1, false)
+	if fi.GetSelectedText() != "" {
+		t.Errorf("Invalid selected text. Instead of '', we have '%s'.", fi.GetSelectedText())
+	}
+	fi.SelectAll()
+	fi.DeleteSelection()
+	if fi.value != "" {
+		t.Errorf("Invalid value. Instead of '', we have '%s'.", fi.value)
+	}
+	intItem := testFormItemInt(t)
+	intItem.AddCursor()
+	typeString(intItem, "-12")
+	intItem.MoveCursorToStart(false)
+	intItem.MoveCursor(2, true)
+	intItem.CharCallback('a', 0.1)
+	if intItem.value != "-12" {
+		t.Errorf("Invalid value. Instead of '-12', we have '%s'.", intItem.value)
+	}
+	intItem.CharCallback('3', 0.1)
+	if intItem.value != "32" {
+		t.Errorf("Invalid value. Instead of '32', we have '%s'.", intItem.value)
+	}
+}
+func TestFormItemCharSetCursorFromPosition(t *testing.T) {
+	fi := testFormItemText(t)
+	fi.AddCursor()
+	typeString(fi, "abcd")
+	textStart := fi.GetSurface().GetPosition().X() + fi.GetTarget().GetPosition().X() + fi.GetCursorInitialPosition().X()
+	fi.SetCursorFromPosition(textStart-0.12, false)
+	if fi.GetCursorIndex() != 1 {
+		t.Errorf("Invalid cursor index. Instead of '1', we have '%d'.", fi.GetCursorIndex())
+	}
+	fi.SetCursorFromPosition(textStart-0.28, true)
+	if fi.GetSelectedText() != "bc" {
+		t.Errorf("Invalid selected text. Instead of 'bc', we have '%s'.", fi.GetSelectedText())
+	}
+	fi.SetCursorFromPosition(textStart-10, false)
+	if fi.GetCursorIndex() != 4 {
+		t.Errorf("Invalid cursor index. Instead of '4', we have '%d'.", fi.GetCursorIndex())
+	}
+}
+func TestFormItemCharMaxLength(t *testing.T) {
+	fi := testFormItemText(t)
+	fi.AddCursor()
+	fi.SetMaxLength(3)
+	if fi.GetMaxLength() != 3 {
+		t.Errorf("Invalid max length. Instead of '3', we have '%d'.", fi.GetMaxLength())
+	}
+	typeString(fi, "abcd")
+	if fi.value != "abc" {
+		t.Errorf("Invalid value. Instead of 'abc', we have '%s'.", fi.value)
+	}
+	fi.MoveCursor(-1, true)
+	fi.CharCallback('x', 0.1)
+	if fi.value != "abx" {
+		t.Errorf("Invalid value. Instead of 'abx', we have '%s'.", fi.value)
+	}
+}
+func TestFormItemCharPlaceholder(t *testing.T) {
+	fi := testFormItemText(t)
+	if fi.GetPlaceholder() != "" {
+		t.Errorf("Invalid placeholder. Instead of '', we have '%s'.", fi.GetPlaceholder())
+	}
+	fi.SetPlaceholder("name")
+	if fi.GetPlaceholder() != "name" {
+		t.Errorf("Invalid placeholder. Instead of 'name', we have '%s'.", fi.GetPlaceholder())
+	}
+	if fi.ValueToString() != "" {
+		t.Errorf("Invalid valuestring. Instead of '', we have '%s'.", fi.ValueToString())
+	}
+}
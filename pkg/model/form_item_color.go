@@ -0,0 +1,95 @@
+package model
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/material"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/transformations"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type FormItemColor struct {
+	*FormItemBase
+	channels [3]*sliderTrack
+	value    mgl32.Vec3
+}
+
+// NewFormItemColor returns a form item that maintains a RGB color. The target area
+// is divided to 4 slots. The first 3 slots contain the tracks of the red, green and
+// blue components, the last one is the preview of the color.
+func NewFormItemColor(maxWidth, itemWidth, aspect float32, label, description string, mat *material.Material, position mgl32.Vec3, wrapper interfaces.GLWrapper) *FormItemColor {
+	m := NewFormItemBase(maxWidth, itemWidth, aspect, label, description, mat, wrapper)
+	m.GetSurface().SetPosition(position)
+	slotWidth := m.getWidthWithoutLabel() / 4.0
+	slotX := func(index int) float32 {
+		return -m.GetFormItemWidth()/2 + m.GetLabelAreaWidth() + slotWidth*(float32(index)+0.5)
+	}
+	preview := newChildMesh(slotWidth*0.9, m.GetTargetHeight(), solidTexture(255, wrapper), material.New(mgl32.Vec3{}, mgl32.Vec3{}, mgl32.Vec3{}, 32), m.GetSurface(), mgl32.Vec3{slotX(3), -0.01, 0.0}, wrapper)
+	m.AddMesh(preview)
+	var channels [3]*sliderTrack
+	for i := 0; i < 3; i++ {
+		channels[i] = newSliderTrack(m, slotWidth*0.9, mgl32.Vec3{slotX(i), -0.01, 0.0}, mat, wrapper)
+		m.AddMesh(channels[i].track)
+		m.AddMesh(channels[i].knob)
+	}
+	return &FormItemColor{
+		FormItemBase: m,
+		channels:     channels,
+		value:        mgl32.Vec3{},
+	}
+}
+
+// GetPreview returns the mesh that displays the color.
+func (fi *FormItemColor) GetPreview() interfaces.Mesh {
+	return fi.meshes[1]
+}
+
+// GetTrack returns the track mesh of the given component.
+func (fi *FormItemColor) GetTrack(component int) interfaces.Mesh {
+	return fi.channels[component].track
+}
+
+// GetComponent returns the index of the component of the given track or knob mesh.
+// If the mesh doesn't belong to a component, it returns -1.
+func (fi *FormItemColor) GetComponent(m interfaces.Mesh) int {
+	for i := 0; i < 3; i++ {
+		if fi.channels[i].track == m || fi.channels[i].knob == m {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetValue returns the color of the form item.
+func (fi *FormItemColor) GetValue() mgl32.Vec3 {
+	return fi.value
+}
+
+// SetValue clamps the components of the given color to the [0-1] interval, moves the
+// knobs and updates the material of the preview.
+func (fi *FormItemColor) SetValue(v mgl32.Vec3) {
+	for i := 0; i < 3; i++ {
+		if v[i] < 0 {
+			v[i] = 0
+		}
+		if v[i] > 1 {
+			v[i] = 1
+		}
+		fi.channels[i].setRatio(v[i])
+	}
+	fi.value = v
+	fi.GetPreview().(*mesh.TexturedMaterialMesh).Material = material.New(v, v, v, 32)
+}
+
+// SetComponentFromPosition sets the given component from the x coordinate of the pointer.
+func (fi *FormItemColor) SetComponentFromPosition(component int, x float32) {
+	v := fi.value
+	v[component] = fi.channels[component].ratioFromPosition(fi.GetSurface().GetPosition().X(), x)
+	fi.SetValue(v)
+}
+
+// ValueToString returns the string representation of the value of the form item.
+func (fi *FormItemColor) ValueToString() string {
+	return transformations.Vec3ToString(fi.value)
+}
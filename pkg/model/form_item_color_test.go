@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/material"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testFormItemColor(t *testing.T) *FormItemColor {
+	fi := NewFormItemColor(DefaultMaxWidth, ITEM_WIDTH_FULL, 1.0, DefaultFormItemLabel, DefaultFormItemDescription, material.Chrome, mgl32.Vec3{0, 0, 0}, wrapperMock)
+	if fi.label != DefaultFormItemLabel {
+		t.Errorf("Invalid form item label. Instead of '%s', we have '%s'.", DefaultFormItemLabel, fi.label)
+	}
+	return fi
+}
+func TestFormItemColorSetValue(t *testing.T) {
+	fi := testFormItemColor(t)
+	fi.SetValue(mgl32.Vec3{0.5, 2, -1})
+	expected := mgl32.Vec3{0.5, 1, 0}
+	if fi.GetValue() != expected {
+		t.Errorf("Invalid value '%v'.", fi.GetValue())
+	}
+	if fi.GetPreview().(*mesh.TexturedMaterialMesh).Material.GetDiffuse() != expected {
+		t.Error("The preview should display the color.")
+	}
+	if fi.ValueToString() == "" {
+		t.Error("Invalid value string.")
+	}
+}
+func TestFormItemColorSetComponentFromPosition(t *testing.T) {
+	fi := testFormItemColor(t)
+	track := fi.GetTrack(1)
+	if fi.GetComponent(track) != 1 || fi.GetComponent(fi.GetPreview()) != -1 {
+		t.Error("Invalid component.")
+	}
+	fi.SetComponentFromPosition(1, fi.GetSurface().GetPosition().X()+track.GetPosition().X())
+	if fi.GetValue() != (mgl32.Vec3{0, 0.5, 0}) {
+		t.Errorf("Invalid value '%v'.", fi.GetValue())
+	}
+}
@@ -30,11 +30,27 @@ func (fi *FormItemFloat) GetValue() float32 {
 // NewFormItemFloat returns a form item that maintains a float32 value.
 func NewFormItemFloat(maxWidth, itemWidth, aspect float32, label, description string, mat *material.Material, position mgl32.Vec3, wrapper interfaces.GLWrapper) *FormItemFloat {
 	base := NewFormItemCharBase(maxWidth, itemWidth, aspect, label, description, CHAR_NUM_FLOAT, mat, position, wrapper)
-	return &FormItemFloat{
+	fi := &FormItemFloat{
 		FormItemCharBase: base,
 		typeState:        "P",
 		validator:        nil,
 	}
+	base.accept = func(s string) bool {
+		_, ok := fi.replayState(s)
+		return ok
+	}
+	base.validate = func(s string) bool {
+		return fi.validator == nil || fi.validator(parseFloat32(s))
+	}
+	base.changed = func() {
+		fi.typeState, _ = fi.replayState(fi.value)
+	}
+	return fi
+}
+
+// replayState returns the state of the given value.
+func (fi *FormItemFloat) replayState(value string) (string, bool) {
+	return replayStates(value, &fi.typeState, fi.validRune, fi.pushState)
 }
 
 // SetValidator sets the validator function
@@ -84,53 +100,6 @@ func (fi *FormItemFloat) pushState(r rune) {
 		break
 	}
 }
-func (fi *FormItemFloat) popState(r rune) {
-	switch fi.typeState {
-	case "NF":
-		if r == rune('.') {
-			fi.typeState = "N."
-		}
-		break
-	case "N.":
-		if len(fi.value) == 2 && fi.value[1] == '0' {
-			fi.typeState = "N0"
-		} else {
-			fi.typeState = "NI"
-		}
-		break
-	case "N0":
-		fi.typeState = "N"
-		break
-	case "NI":
-		if len(fi.value) == 1 {
-			fi.typeState = "N"
-		}
-		break
-	case "N":
-		fi.typeState = "P"
-		break
-	case "PF":
-		if r == rune('.') {
-			fi.typeState = "P."
-		}
-		break
-	case "P.":
-		if len(fi.value) == 1 && fi.value[0] == '0' {
-			fi.typeState = "P0"
-		} else {
-			fi.typeState = "PI"
-		}
-		break
-	case "P0":
-		fi.typeState = "P"
-		break
-	case "PI":
-		if len(fi.value) == 0 {
-			fi.typeState = "P"
-		}
-		break
-	}
-}
 func (fi *FormItemFloat) validRune(r rune) bool {
 	var validRunes []rune
 	switch fi.typeState {
@@ -154,33 +123,3 @@ func (fi *FormItemFloat) validRune(r rune) bool {
 	}
 	return false
 }
-
-// CharCallback validates the input character and appends it to the value if valid.
-func (fi *FormItemFloat) CharCallback(r rune, offsetX float32) {
-	if !fi.validRune(r) || len(fi.value) >= fi.maxLen {
-		return
-	}
-	fi.value = fi.value + string(r)
-	fi.MoveCursorWithOffset(offsetX)
-	fi.pushState(r)
-	if fi.validator != nil {
-		if !fi.validator(fi.GetValue()) {
-			fi.DeleteLastCharacter()
-		}
-	}
-}
-
-// DeleteLastCharacter removes the last typed character from the form item.
-func (fi *FormItemFloat) DeleteLastCharacter() {
-	if len(fi.charOffsets) == 0 {
-		return
-	}
-	fi.value = fi.value[:len(fi.value)-1]
-	fi.StepBackCursor()
-	if len(fi.value) > 0 {
-		fi.popState(rune(fi.value[len(fi.value)-1]))
-	} else {
-		// dummy value for pop state.
-		fi.popState('.')
-	}
-}
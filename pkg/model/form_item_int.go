@@ -24,11 +24,28 @@ func (fi *FormItemInt) GetValue() int {
 // NewFormItemInt returns a form item that maintains an integer value.
 func NewFormItemInt(maxWidth, itemWidth, aspect float32, label, description string, mat *material.Material, position mgl32.Vec3, wrapper interfaces.GLWrapper) *FormItemInt {
 	base := NewFormItemCharBase(maxWidth, itemWidth, aspect, label, description, CHAR_NUM_INT, mat, position, wrapper)
-	return &FormItemInt{
+	fi := &FormItemInt{
 		FormItemCharBase: base,
 		typeState:        "P",
 		validator:        nil,
 	}
+	base.accept = func(s string) bool {
+		_, ok := fi.replayState(s)
+		return ok
+	}
+	base.validate = func(s string) bool {
+		val, _ := strconv.Atoi(s)
+		return fi.validator == nil || fi.validator(val)
+	}
+	base.changed = func() {
+		fi.typeState, _ = fi.replayState(fi.value)
+	}
+	return fi
+}
+
+// replayState returns the state of the given value.
+func (fi *FormItemInt) replayState(value string) (string, bool) {
+	return replayStates(value, &fi.typeState, fi.validRune, fi.pushState)
 }
 
 // SetValidator sets the validator function
@@ -56,26 +73,6 @@ func (fi *FormItemInt) validRune(r rune) bool {
 	}
 	return false
 }
-func (fi *FormItemInt) popState(r rune) {
-	switch fi.typeState {
-	case "N":
-		fi.typeState = "P"
-		break
-	case "NI":
-		if len(fi.value) == 1 {
-			fi.typeState = "N"
-		}
-		break
-	case "PI":
-		if len(fi.value) == 0 {
-			fi.typeState = "P"
-		}
-		break
-	case "P0":
-		fi.typeState = "P"
-		break
-	}
-}
 func (fi *FormItemInt) pushState(r rune) {
 	switch fi.typeState {
 	case "P":
@@ -92,33 +89,3 @@ func (fi *FormItemInt) pushState(r rune) {
 		break
 	}
 }
-
-// CharCallback validates the input character and appends it to the value if valid.
-func (fi *FormItemInt) CharCallback(r rune, offsetX float32) {
-	if !fi.validRune(r) || len(fi.value) >= fi.maxLen {
-		return
-	}
-	fi.value = fi.value + string(r)
-	fi.MoveCursorWithOffset(offsetX)
-	fi.pushState(r)
-	if fi.validator != nil {
-		if !fi.validator(fi.GetValue()) {
-			fi.DeleteLastCharacter()
-		}
-	}
-}
-
-// DeleteLastCharacter removes the last typed character from the form item.
-func (fi *FormItemInt) DeleteLastCharacter() {
-	if len(fi.charOffsets) == 0 {
-		return
-	}
-	fi.value = fi.value[:len(fi.value)-1]
-	fi.StepBackCursor()
-	if len(fi.value) > 0 {
-		fi.popState(rune(fi.value[len(fi.value)-1]))
-	} else {
-		// dummy value for pop state.
-		fi.popState('.')
-	}
-}
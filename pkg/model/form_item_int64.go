@@ -24,11 +24,28 @@ func (fi *FormItemInt64) GetValue() int64 {
 // NewFormItemInt64 returns a form item that maintains an int64 value.
 func NewFormItemInt64(maxWidth, itemWidth, aspect float32, label, description string, mat *material.Material, position mgl32.Vec3, wrapper interfaces.GLWrapper) *FormItemInt64 {
 	base := NewFormItemCharBase(maxWidth, itemWidth, aspect, label, description, CHAR_NUM_INT64, mat, position, wrapper)
-	return &FormItemInt64{
+	fi := &FormItemInt64{
 		FormItemCharBase: base,
 		typeState:        "P",
 		validator:        nil,
 	}
+	base.accept = func(s string) bool {
+		_, ok := fi.replayState(s)
+		return ok
+	}
+	base.validate = func(s string) bool {
+		val, _ := strconv.Atoi(s)
+		return fi.validator == nil || fi.validator(int64(val))
+	}
+	base.changed = func() {
+		fi.typeState, _ = fi.replayState(fi.value)
+	}
+	return fi
+}
+
+// replayState returns the state of the given value.
+func (fi *FormItemInt64) replayState(value string) (string, bool) {
+	return replayStates(value, &fi.typeState, fi.validRune, fi.pushState)
 }
 
 // SetValidator sets the validator function
@@ -57,26 +74,6 @@ func (fi *FormItemInt64) validRune(r rune) bool {
 	}
 	return false
 }
-func (fi *FormItemInt64) popState(r rune) {
-	switch fi.typeState {
-	case "N":
-		fi.typeState = "P"
-		break
-	case "NI":
-		if len(fi.value) == 1 {
-			fi.typeState = "N"
-		}
-		break
-	case "PI":
-		if len(fi.value) == 0 {
-			fi.typeState = "P"
-		}
-		break
-	case "P0":
-		fi.typeState = "P"
-		break
-	}
-}
 func (fi *FormItemInt64) pushState(r rune) {
 	switch fi.typeState {
 	case "P":
@@ -93,33 +90,3 @@ func (fi *FormItemInt64) pushState(r rune) {
 		break
 	}
 }
-
-// CharCallback validates the input character and appends it to the value if valid.
-func (fi *FormItemInt64) CharCallback(r rune, offsetX float32) {
-	if !fi.validRune(r) || len(fi.value) >= fi.maxLen {
-		return
-	}
-	fi.value = fi.value + string(r)
-	fi.MoveCursorWithOffset(offsetX)
-	fi.pushState(r)
-	if fi.validator != nil {
-		if !fi.validator(fi.GetValue()) {
-			fi.DeleteLastCharacter()
-		}
-	}
-}
-
-// DeleteLastCharacter removes the last typed character from the form item.
-func (fi *FormItemInt64) DeleteLastCharacter() {
-	if len(fi.charOffsets) == 0 {
-		return
-	}
-	fi.value = fi.value[:len(fi.value)-1]
-	fi.StepBackCursor()
-	if len(fi.value) > 0 {
-		fi.popState(rune(fi.value[len(fi.value)-1]))
-	} else {
-		// dummy value for pop state.
-		fi.popState('.')
-	}
-}
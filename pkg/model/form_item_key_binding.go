@@ -0,0 +1,69 @@
+package model
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/material"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The text of the key binder items, while they are waiting for the key.
+const KEY_BINDING_CAPTURE_TEXT = "Press a key"
+
+type FormItemKeyBinding struct {
+	*FormItemBase
+	value     string
+	capturing bool
+}
+
+// NewFormItemKeyBinding returns a form item that maintains a key binding. In capture mode
+// the form item waits for a key press, that will be its new value.
+func NewFormItemKeyBinding(maxWidth, itemWidth, aspect float32, label, description string, mat *material.Material, position mgl32.Vec3, wrapper interfaces.GLWrapper) *FormItemKeyBinding {
+	m := NewFormItemBase(maxWidth, itemWidth, aspect, label, description, mat, wrapper)
+	m.GetSurface().SetPosition(position)
+	m.AddMesh(newChildMesh(m.GetTargetWidth(), m.GetTargetHeight(), paperTexture(wrapper), mat, m.GetSurface(), m.GetTargetPosition(), wrapper))
+	return &FormItemKeyBinding{
+		FormItemBase: m,
+		value:        "",
+		capturing:    false,
+	}
+}
+
+// GetTarget returns the mesh, where the binding is displayed.
+func (fi *FormItemKeyBinding) GetTarget() interfaces.Mesh {
+	return fi.meshes[1]
+}
+
+// GetValue returns the binding of the form item.
+func (fi *FormItemKeyBinding) GetValue() string {
+	return fi.value
+}
+
+// SetValue sets the binding and stops the capture mode.
+func (fi *FormItemKeyBinding) SetValue(v string) {
+	fi.value = v
+	fi.capturing = false
+}
+
+// StartCapture turns on the capture mode.
+func (fi *FormItemKeyBinding) StartCapture() {
+	fi.capturing = true
+}
+
+// StopCapture turns off the capture mode without changing the value.
+func (fi *FormItemKeyBinding) StopCapture() {
+	fi.capturing = false
+}
+
+// IsCapturing returns true, if the form item waits for a key press.
+func (fi *FormItemKeyBinding) IsCapturing() bool {
+	return fi.capturing
+}
+
+// ValueToString returns the binding, or the capture text in capture mode.
+func (fi *FormItemKeyBinding) ValueToString() string {
+	if fi.capturing {
+		return KEY_BINDING_CAPTURE_TEXT
+	}
+	return fi.value
+}
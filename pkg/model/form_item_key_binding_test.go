@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/material"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestFormItemKeyBinding(t *testing.T) {
+	fi := NewFormItemKeyBinding(DefaultMaxWidth, ITEM_WIDTH_HALF, 1.0, DefaultFormItemLabel, DefaultFormItemDescription, material.Chrome, mgl32.Vec3{0, 0, 0}, wrapperMock)
+	if fi.GetTarget() != fi.meshes[1] || fi.IsCapturing() {
+		t.Error("Invalid initial state.")
+	}
+	fi.SetValue("key:W")
+	fi.StartCapture()
+	if !fi.IsCapturing() || fi.ValueToString() != KEY_BINDING_CAPTURE_TEXT || fi.GetValue() != "key:W" {
+		t.Error("The capture text should be displayed.")
+	}
+	fi.StopCapture()
+	if fi.ValueToString() != "key:W" {
+		t.Error("The stop shouldn't change the value.")
+	}
+	fi.StartCapture()
+	fi.SetValue("key:ctrl+S")
+	if fi.IsCapturing() || fi.ValueToString() != "key:ctrl+S" {
+		t.Error("The new value should stop the capture.")
+	}
+}
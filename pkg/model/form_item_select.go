@@ -0,0 +1,127 @@
+package model
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/material"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type FormItemSelect struct {
+	*FormItemBase
+	options      []string
+	selected     int
+	optionMeshes []interfaces.Mesh
+	open         bool
+}
+
+// NewFormItemSelect returns a form item that maintains one of the given options as value.
+// The selected option is displayed on the target mesh, the options are displayed under
+// the target, when the dropdown is open.
+func NewFormItemSelect(maxWidth, itemWidth, aspect float32, label, description string, options []string, mat *material.Material, position mgl32.Vec3, wrapper interfaces.GLWrapper) *FormItemSelect {
+	m := NewFormItemBase(maxWidth, itemWidth, aspect, label, description, mat, wrapper)
+	m.GetSurface().SetPosition(position)
+	target := newChildMesh(m.GetTargetWidth(), m.GetTargetHeight(), paperTexture(wrapper), mat, m.GetSurface(), m.GetTargetPosition(), wrapper)
+	m.AddMesh(target)
+	var optionMeshes []interfaces.Mesh
+	for i := 0; i < len(options); i++ {
+		optionMeshes = append(optionMeshes, newChildMesh(m.GetTargetWidth(), m.GetFormItemHeight(), paperTexture(wrapper), mat, m.GetSurface(), mgl32.Vec3{}, wrapper))
+	}
+	return &FormItemSelect{
+		FormItemBase: m,
+		options:      options,
+		selected:     0,
+		optionMeshes: optionMeshes,
+		open:         false,
+	}
+}
+
+// GetTarget returns the mesh of the selected option.
+func (fi *FormItemSelect) GetTarget() interfaces.Mesh {
+	return fi.meshes[1]
+}
+
+// GetOptions returns the options of the form item.
+func (fi *FormItemSelect) GetOptions() []string {
+	return fi.options
+}
+
+// GetSelected returns the index of the selected option.
+func (fi *FormItemSelect) GetSelected() int {
+	return fi.selected
+}
+
+// Select sets the selected option. The invalid index is skipped.
+func (fi *FormItemSelect) Select(index int) {
+	if index >= 0 && index < len(fi.options) {
+		fi.selected = index
+	}
+}
+
+// GetValue returns the selected option.
+func (fi *FormItemSelect) GetValue() string {
+	if len(fi.options) == 0 {
+		return ""
+	}
+	return fi.options[fi.selected]
+}
+
+// SetValue selects the given option. If it is not an option, the value is not updated.
+func (fi *FormItemSelect) SetValue(v string) {
+	for i := 0; i < len(fi.options); i++ {
+		if fi.options[i] == v {
+			fi.selected = i
+			return
+		}
+	}
+}
+
+// ValueToString returns the string representation of the value of the form item.
+func (fi *FormItemSelect) ValueToString() string {
+	return fi.GetValue()
+}
+
+// Open displays the option meshes under the target. The options are displayed after the
+// rotation of the form item, so that their positions are given in the rotated system like
+// the position of the cursor of the char based items.
+func (fi *FormItemSelect) Open() {
+	if fi.open {
+		return
+	}
+	fi.open = true
+	target := fi.GetTarget().GetPosition()
+	for i := 0; i < len(fi.optionMeshes); i++ {
+		fi.optionMeshes[i].SetPosition(mgl32.Vec3{target.X(), target.Y() - float32(i+1)*fi.GetFormItemHeight(), target.Z() - 0.01})
+		fi.AddMesh(fi.optionMeshes[i])
+	}
+}
+
+// Close removes the option meshes.
+func (fi *FormItemSelect) Close() {
+	if !fi.open {
+		return
+	}
+	fi.open = false
+	fi.meshes = fi.meshes[:2]
+}
+
+// IsOpen returns true, if the options are displayed.
+func (fi *FormItemSelect) IsOpen() bool {
+	return fi.open
+}
+
+// GetOptionMeshes returns the meshes of the options.
+func (fi *FormItemSelect) GetOptionMeshes() []interfaces.Mesh {
+	return fi.optionMeshes
+}
+
+// GetOptionIndex returns the index of the option of the given mesh. If the mesh is
+// not an option mesh, it returns -1.
+func (fi *FormItemSelect) GetOptionIndex(m interfaces.Mesh) int {
+	for i := 0; i < len(fi.optionMeshes); i++ {
+		if fi.optionMeshes[i] == m {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/material"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testFormItemSelect(t *testing.T) *FormItemSelect {
+	options := []string{"low", "medium", "high"}
+	fi := NewFormItemSelect(DefaultMaxWidth, ITEM_WIDTH_HALF, 1.0, DefaultFormItemLabel, DefaultFormItemDescription, options, material.Chrome, mgl32.Vec3{0, 0, 0}, wrapperMock)
+	if fi.label != DefaultFormItemLabel {
+		t.Errorf("Invalid form item label. Instead of '%s', we have '%s'.", DefaultFormItemLabel, fi.label)
+	}
+	if len(fi.GetOptions()) != 3 || len(fi.GetOptionMeshes()) != 3 {
+		t.Error("Invalid options.")
+	}
+	return fi
+}
+func TestFormItemSelectValue(t *testing.T) {
+	fi := testFormItemSelect(t)
+	if fi.GetValue() != "low" || fi.GetSelected() != 0 {
+		t.Errorf("Invalid initial value '%s'.", fi.GetValue())
+	}
+	fi.SetValue("high")
+	if fi.ValueToString() != "high" || fi.GetSelected() != 2 {
+		t.Errorf("Invalid value '%s'.", fi.ValueToString())
+	}
+	fi.SetValue("ultra")
+	fi.Select(5)
+	if fi.GetValue() != "high" {
+		t.Error("The invalid option shouldn't be selected.")
+	}
+	fi.Select(1)
+	if fi.GetValue() != "medium" {
+		t.Errorf("Invalid value '%s'.", fi.GetValue())
+	}
+}
+func TestFormItemSelectOpenClose(t *testing.T) {
+	fi := testFormItemSelect(t)
+	if fi.GetTarget() != fi.meshes[1] || fi.IsOpen() {
+		t.Error("Invalid initial state.")
+	}
+	fi.Open()
+	fi.Open()
+	if !fi.IsOpen() || len(fi.meshes) != 5 {
+		t.Errorf("The options should be displayed. '%d'.", len(fi.meshes))
+	}
+	option := fi.GetOptionMeshes()[1]
+	if fi.GetOptionIndex(option) != 1 || fi.GetOptionIndex(fi.GetTarget()) != -1 {
+		t.Error("Invalid option index.")
+	}
+	if option.GetPosition().Y() >= fi.GetOptionMeshes()[0].GetPosition().Y() {
+		t.Error("The options should be displayed under each other.")
+	}
+	fi.Close()
+	if fi.IsOpen() || len(fi.meshes) != 2 {
+		t.Error("The options should be removed.")
+	}
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/material"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The ratio of the track and the value area in the target area of the slider.
+	SLIDER_TRACK_RATIO = float32(0.7)
+	SLIDER_VALUE_RATIO = float32(0.25)
+)
+
+// sliderTrack is a track mesh with a knob. The value of the track is in the [0-1] interval.
+type sliderTrack struct {
+	track interfaces.Mesh
+	knob  interfaces.Mesh
+	width float32
+}
+
+// newSliderTrack creates the track and the knob meshes.
+func newSliderTrack(fi *FormItemBase, width float32, position mgl32.Vec3, mat *material.Material, wrapper interfaces.GLWrapper) *sliderTrack {
+	track := newChildMesh(width, fi.GetTargetHeight()*0.3, paperTexture(wrapper), mat, fi.GetSurface(), position, wrapper)
+	knob := newChildMesh(fi.GetCursorWidth()*2, fi.GetCursorHeight(), solidTexture(255, wrapper), material.Greenplastic, track, mgl32.Vec3{}, wrapper)
+	return &sliderTrack{
+		track: track,
+		knob:  knob,
+		width: width,
+	}
+}
+
+// setRatio moves the knob to the given ratio of the track. The knob position is given
+// in the rotated system like the position of the cursor of the char based items.
+func (t *sliderTrack) setRatio(ratio float32) {
+	t.knob.SetPosition(mgl32.Vec3{t.width/2 - ratio*t.width, 0.0, -0.01})
+}
+
+// ratioFromPosition returns the ratio of the given x coordinate on the track.
+// The parentX is the x coordinate of the surface.
+func (t *sliderTrack) ratioFromPosition(parentX, x float32) float32 {
+	ratio := (t.width/2 - (x - parentX - t.track.GetPosition().X())) / t.width
+	if ratio < 0 {
+		return 0
+	}
+	if ratio > 1 {
+		return 1
+	}
+	return ratio
+}
+
+type FormItemSlider struct {
+	*FormItemBase
+	slider   *sliderTrack
+	value    float32
+	min, max float32
+	step     float32
+}
+
+// NewFormItemSlider returns a form item that maintains a float value in the [min-max] interval.
+// The value is rounded to the step, the 0 step means continuous value. The target area
+// contains the track with the knob and the value area.
+func NewFormItemSlider(maxWidth, itemWidth, aspect float32, label, description string, min, max, step float32, mat *material.Material, position mgl32.Vec3, wrapper interfaces.GLWrapper) *FormItemSlider {
+	m := NewFormItemBase(maxWidth, itemWidth, aspect, label, description, mat, wrapper)
+	m.GetSurface().SetPosition(position)
+	targetPosition := m.GetTargetPosition()
+	targetWidth := m.GetTargetWidth()
+	trackWidth := targetWidth * SLIDER_TRACK_RATIO
+	valueWidth := targetWidth * SLIDER_VALUE_RATIO
+	slider := newSliderTrack(m, trackWidth, mgl32.Vec3{targetPosition.X() - (targetWidth-trackWidth)/2, targetPosition.Y(), targetPosition.Z()}, mat, wrapper)
+	valueArea := newChildMesh(valueWidth, m.GetTargetHeight(), paperTexture(wrapper), mat, m.GetSurface(), mgl32.Vec3{targetPosition.X() + (targetWidth-valueWidth)/2, targetPosition.Y(), targetPosition.Z()}, wrapper)
+	m.AddMesh(valueArea)
+	m.AddMesh(slider.track)
+	m.AddMesh(slider.knob)
+	return &FormItemSlider{
+		FormItemBase: m,
+		slider:       slider,
+		value:        min,
+		min:          min,
+		max:          max,
+		step:         step,
+	}
+}
+
+// GetTarget returns the value area mesh, where the value is displayed.
+func (fi *FormItemSlider) GetTarget() interfaces.Mesh {
+	return fi.meshes[1]
+}
+
+// GetTrack returns the track mesh.
+func (fi *FormItemSlider) GetTrack() interfaces.Mesh {
+	return fi.slider.track
+}
+
+// GetKnob returns the knob mesh.
+func (fi *FormItemSlider) GetKnob() interfaces.Mesh {
+	return fi.slider.knob
+}
+
+// GetRange returns the min, max values and the step of the form item.
+func (fi *FormItemSlider) GetRange() (float32, float32, float32) {
+	return fi.min, fi.max, fi.step
+}
+
+// GetValue returns the value of the form item.
+func (fi *FormItemSlider) GetValue() float32 {
+	return fi.value
+}
+
+// SetValue clamps the given value to the range, rounds it to the step and moves the knob.
+func (fi *FormItemSlider) SetValue(v float32) {
+	if fi.step > 0 {
+		v = fi.min + float32(math.Round(float64((v-fi.min)/fi.step)))*fi.step
+	}
+	if v < fi.min {
+		v = fi.min
+	}
+	if v > fi.max {
+		v = fi.max
+	}
+	fi.value = v
+	fi.slider.setRatio(fi.GetRatio())
+}
+
+// GetRatio returns the position of the value in the range. It is in the [0-1] interval.
+func (fi *FormItemSlider) GetRatio() float32 {
+	return (fi.value - fi.min) / (fi.max - fi.min)
+}
+
+// SetValueFromPosition sets the value from the x coordinate of the pointer.
+func (fi *FormItemSlider) SetValueFromPosition(x float32) {
+	ratio := fi.slider.ratioFromPosition(fi.GetSurface().GetPosition().X(), x)
+	fi.SetValue(fi.min + ratio*(fi.max-fi.min))
+}
+
+// ValueToString returns the string representation of the value of the form item.
+// The number of the decimals is based on the step.
+func (fi *FormItemSlider) ValueToString() string {
+	decimals := 2
+	if fi.step > 0 {
+		decimals = 0
+		stepString := strconv.FormatFloat(float64(fi.step), 'f', -1, 32)
+		if dot := strings.Index(stepString, "."); dot > -1 {
+			decimals = len(stepString) - dot - 1
+		}
+	}
+	return strconv.FormatFloat(float64(fi.value), 'f', decimals, 32)
+}
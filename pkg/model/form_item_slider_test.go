@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/material"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testFormItemSlider(t *testing.T, step float32) *FormItemSlider {
+	fi := NewFormItemSlider(DefaultMaxWidth, ITEM_WIDTH_HALF, 1.0, DefaultFormItemLabel, DefaultFormItemDescription, 0, 10, step, material.Chrome, mgl32.Vec3{0, 0, 0}, wrapperMock)
+	if fi.label != DefaultFormItemLabel {
+		t.Errorf("Invalid form item label. Instead of '%s', we have '%s'.", DefaultFormItemLabel, fi.label)
+	}
+	return fi
+}
+func TestFormItemSliderSetValue(t *testing.T) {
+	fi := testFormItemSlider(t, 0.5)
+	min, max, step := fi.GetRange()
+	if min != 0 || max != 10 || step != 0.5 || fi.GetTarget() != fi.meshes[1] || fi.GetTrack() != fi.meshes[2] || fi.GetKnob() != fi.meshes[3] {
+		t.Error("Invalid slider setup.")
+	}
+	testData := []struct {
+		value    float32
+		expected float32
+		str      string
+	}{
+		{3.3, 3.5, "3.5"},
+		{-2, 0, "0.0"},
+		{12, 10, "10.0"},
+		{7, 7, "7.0"},
+	}
+	for _, tt := range testData {
+		fi.SetValue(tt.value)
+		if fi.GetValue() != tt.expected || fi.ValueToString() != tt.str {
+			t.Errorf("Invalid value. Instead of '%f' ('%s'), we have '%f' ('%s').", tt.expected, tt.str, fi.GetValue(), fi.ValueToString())
+		}
+	}
+	if fi.GetRatio() != 0.7 {
+		t.Errorf("Invalid ratio '%f'.", fi.GetRatio())
+	}
+	if fi.GetKnob().GetPosition().X() != fi.slider.width/2-0.7*fi.slider.width {
+		t.Error("Invalid knob position.")
+	}
+	fi = testFormItemSlider(t, 0)
+	fi.SetValue(3.333)
+	if fi.ValueToString() != "3.33" {
+		t.Errorf("Invalid value string '%s'.", fi.ValueToString())
+	}
+}
+func TestFormItemSliderSetValueFromPosition(t *testing.T) {
+	fi := testFormItemSlider(t, 1)
+	trackX := fi.GetSurface().GetPosition().X() + fi.GetTrack().GetPosition().X()
+	fi.SetValueFromPosition(trackX + fi.slider.width/2)
+	if fi.GetValue() != 0 {
+		t.Errorf("The start of the track should be the min value. '%f'.", fi.GetValue())
+	}
+	fi.SetValueFromPosition(trackX)
+	if fi.GetValue() != 5 {
+		t.Errorf("The middle of the track should be the middle value. '%f'.", fi.GetValue())
+	}
+	fi.SetValueFromPosition(trackX - fi.slider.width)
+	if fi.GetValue() != 10 {
+		t.Errorf("The position out of the track should be clamped. '%f'.", fi.GetValue())
+	}
+}
@@ -24,6 +24,8 @@ type FormItemVector struct {
 	currentTarget int // the index of the currently edited mesh
 	validator     FloatValidator
 	typeStates    []string
+	edits         []textEdit
+	placeholder   string
 }
 
 func NewFormItemVector(maxWidth, widthRatio, aspect float32, label, description string, inputMaxLen int, mat *material.Material, position mgl32.Vec3, wrapper interfaces.GLWrapper) *FormItemVector {
@@ -44,6 +46,7 @@ func NewFormItemVector(maxWidth, widthRatio, aspect float32, label, description
 	var offsets [][]float32
 	var values []string
 	var typeState []string
+	var edits []textEdit
 	for index := 0; index < 3; index++ {
 		v, i, bo := writablePrimitive.MeshInput()
 		writableMesh := mesh.NewTexturedMaterialMesh(v, i, writableTexture, mat, wrapper)
@@ -60,6 +63,7 @@ func NewFormItemVector(maxWidth, widthRatio, aspect float32, label, description
 		offsets = append(offsets, []float32{})
 		values = append(values, "")
 		typeState = append(typeState, "P")
+		edits = append(edits, textEdit{})
 	}
 	return &FormItemVector{
 		FormItemBase: m,
@@ -69,17 +73,10 @@ func NewFormItemVector(maxWidth, widthRatio, aspect float32, label, description
 		maxLen:       inputMaxLen,
 		validator:    nil,
 		typeStates:   typeState,
+		edits:        edits,
+		placeholder:  "",
 	}
 }
-func (fi *FormItemVector) cursorOffsetX() float32 {
-	result := float32(0.0)
-	if len(fi.charOffsets[fi.currentTarget]) > 0 {
-		for i := 0; i < len(fi.charOffsets[fi.currentTarget]); i++ {
-			result = result + fi.charOffsets[fi.currentTarget][i]
-		}
-	}
-	return result
-}
 func (fi *FormItemVector) GetIndex(m interfaces.Mesh) int {
 	for i, _ := range fi.meshes {
 		if reflect.DeepEqual(m, reflect.ValueOf(fi.meshes[i]).Interface()) {
@@ -102,7 +99,7 @@ func (fi *FormItemVector) SetTarget(i int) {
 // AddCursor displays a cursor on the target surface.
 func (fi *FormItemVector) AddCursor() {
 	fi.AddMesh(fi.cursors[fi.currentTarget])
-	fi.cursors[fi.currentTarget].SetPosition(mgl32.Vec3{fi.GetVectorCursorInitialPosition().X() - fi.cursorOffsetX(), 0.0, -0.01})
+	fi.updateCursor()
 }
 
 // DeleteCursor removes the cursor from the meshes.
@@ -112,20 +109,6 @@ func (fi *FormItemVector) DeleteCursor() {
 	}
 }
 
-// MoveCursorWithOffset moves to cursor with the offset.
-// It adds the new offset to the offsets, increments the sum offset
-// and sets the cursor position.
-func (fi *FormItemVector) MoveCursorWithOffset(offsetX float32) {
-	fi.charOffsets[fi.currentTarget] = append(fi.charOffsets[fi.currentTarget], offsetX)
-	fi.cursors[fi.currentTarget].SetPosition(mgl32.Vec3{fi.GetVectorCursorInitialPosition().X() - fi.cursorOffsetX(), 0.0, -0.01})
-}
-
-// StepBackCursor moves the cursor back after a character deletion.
-func (fi *FormItemVector) StepBackCursor() {
-	fi.charOffsets[fi.currentTarget] = fi.charOffsets[fi.currentTarget][:len(fi.charOffsets[fi.currentTarget])-1]
-	fi.cursors[fi.currentTarget].SetPosition(mgl32.Vec3{fi.GetVectorCursorInitialPosition().X() - fi.cursorOffsetX(), 0.0, -0.01})
-}
-
 // ValueToString returns the string representation of the value of the form item.
 func (fi *FormItemVector) ValueToString() string {
 	return fi.values[fi.currentTarget]
@@ -136,17 +119,6 @@ func (fi *FormItemVector) SetValidator(validator FloatValidator) {
 	fi.validator = validator
 }
 
-// getValue returns the value of the current target form item. If the value can't parse as float32,
-// some error message is printed out to the console, and the returned value is 0.0
-func (fi *FormItemVector) getValue() float32 {
-	valueFloat, err := strconv.ParseFloat(fi.values[fi.currentTarget], 32)
-	if err != nil {
-		fmt.Printf("Can't format to float: '%s', err: '%s'\n", fi.values[fi.currentTarget], err.Error())
-		return 0.0
-	}
-	return float32(valueFloat)
-}
-
 // GetValue returns the value of the form item. If a component can't be parsed as float32,
 // some error message is printed out to the console, and '0.0' is used instead of the
 // wrong component.
@@ -163,35 +135,6 @@ func (fi *FormItemVector) GetValue() mgl32.Vec3 {
 	return mgl32.Vec3{components[0], components[1], components[2]}
 }
 
-// CharCallback validates the input character and appends it to the value if valid.
-func (fi *FormItemVector) CharCallback(r rune, offsetX float32) {
-	if !fi.validRune(r) || len(fi.values[fi.currentTarget]) >= fi.maxLen {
-		return
-	}
-	fi.values[fi.currentTarget] = fi.values[fi.currentTarget] + string(r)
-	fi.MoveCursorWithOffset(offsetX)
-	fi.pushState(r)
-	if fi.validator != nil {
-		if !fi.validator(fi.getValue()) {
-			fi.DeleteLastCharacter()
-		}
-	}
-}
-
-// DeleteLastCharacter removes the last typed character from the form item.
-func (fi *FormItemVector) DeleteLastCharacter() {
-	if len(fi.charOffsets[fi.currentTarget]) == 0 {
-		return
-	}
-	fi.values[fi.currentTarget] = fi.values[fi.currentTarget][:len(fi.values[fi.currentTarget])-1]
-	fi.StepBackCursor()
-	if len(fi.values[fi.currentTarget]) > 0 {
-		fi.popState(rune(fi.values[fi.currentTarget][len(fi.values[fi.currentTarget])-1]))
-	} else {
-		// dummy value for pop state.
-		fi.popState('.')
-	}
-}
 func (fi *FormItemVector) pushState(r rune) {
 	switch fi.typeStates[fi.currentTarget] {
 	case "P":
@@ -234,53 +177,6 @@ func (fi *FormItemVector) pushState(r rune) {
 		break
 	}
 }
-func (fi *FormItemVector) popState(r rune) {
-	switch fi.typeStates[fi.currentTarget] {
-	case "NF":
-		if r == rune('.') {
-			fi.typeStates[fi.currentTarget] = "N."
-		}
-		break
-	case "N.":
-		if len(fi.values[fi.currentTarget]) == 2 && fi.values[fi.currentTarget][1] == '0' {
-			fi.typeStates[fi.currentTarget] = "N0"
-		} else {
-			fi.typeStates[fi.currentTarget] = "NI"
-		}
-		break
-	case "N0":
-		fi.typeStates[fi.currentTarget] = "N"
-		break
-	case "NI":
-		if len(fi.values[fi.currentTarget]) == 1 {
-			fi.typeStates[fi.currentTarget] = "N"
-		}
-		break
-	case "N":
-		fi.typeStates[fi.currentTarget] = "P"
-		break
-	case "PF":
-		if r == rune('.') {
-			fi.typeStates[fi.currentTarget] = "P."
-		}
-		break
-	case "P.":
-		if len(fi.values[fi.currentTarget]) == 1 && fi.values[fi.currentTarget][0] == '0' {
-			fi.typeStates[fi.currentTarget] = "P0"
-		} else {
-			fi.typeStates[fi.currentTarget] = "PI"
-		}
-		break
-	case "P0":
-		fi.typeStates[fi.currentTarget] = "P"
-		break
-	case "PI":
-		if len(fi.values[fi.currentTarget]) == 0 {
-			fi.typeStates[fi.currentTarget] = "P"
-		}
-		break
-	}
-}
 func (fi *FormItemVector) validRune(r rune) bool {
 	var validRunes []rune
 	switch fi.typeStates[fi.currentTarget] {
@@ -304,3 +200,133 @@ func (fi *FormItemVector) validRune(r rune) bool {
 	}
 	return false
 }
+
+// input returns the editable input of the current target.
+func (fi *FormItemVector) input() *charInput {
+	return &charInput{
+		value:   &fi.values[fi.currentTarget],
+		offsets: &fi.charOffsets[fi.currentTarget],
+		edit:    &fi.edits[fi.currentTarget],
+		maxLen:  fi.maxLen,
+		length:  func(s string) int { return len(s) },
+		accept: func(s string) bool {
+			_, ok := fi.replayState(s)
+			return ok
+		},
+		validate: func(s string) bool {
+			return fi.validator == nil || fi.validator(parseFloat32(s))
+		},
+		changed: func() {
+			fi.typeStates[fi.currentTarget], _ = fi.replayState(fi.values[fi.currentTarget])
+		},
+	}
+}
+
+// replayState returns the state of the given value of the current target.
+func (fi *FormItemVector) replayState(value string) (string, bool) {
+	return replayStates(value, &fi.typeStates[fi.currentTarget], fi.validRune, fi.pushState)
+}
+
+// updateCursor moves the cursor mesh of the current target to the current position of the cursor.
+func (fi *FormItemVector) updateCursor() {
+	in := fi.input()
+	fi.cursors[fi.currentTarget].SetPosition(mgl32.Vec3{fi.GetVectorCursorInitialPosition().X() - in.offsetX(in.cursorIndex()), 0.0, -0.01})
+}
+
+// CharCallback inserts the character to the position of the cursor of the current target,
+// the selected characters are replaced. If the new value is not accepted by the form item
+// or by its validator, the value is not updated.
+func (fi *FormItemVector) CharCallback(r rune, offsetX float32) {
+	fi.input().insert(r, offsetX)
+	fi.updateCursor()
+}
+
+// DeleteLastCharacter removes the selected characters or the character before the cursor.
+func (fi *FormItemVector) DeleteLastCharacter() {
+	fi.input().deleteBackward()
+	fi.updateCursor()
+}
+
+// DeleteNextCharacter removes the selected characters or the character after the cursor.
+func (fi *FormItemVector) DeleteNextCharacter() {
+	fi.input().deleteForward()
+	fi.updateCursor()
+}
+
+// DeleteSelection removes the selected characters of the current target.
+func (fi *FormItemVector) DeleteSelection() {
+	fi.input().deleteSelection()
+	fi.updateCursor()
+}
+
+// MoveCursor moves the cursor with the given number of characters. If the selecting
+// flag is set, the selection is extended, otherwise it is removed.
+func (fi *FormItemVector) MoveCursor(step int, selecting bool) {
+	in := fi.input()
+	in.moveTo(in.cursorIndex()+step, selecting)
+	fi.updateCursor()
+}
+
+// MoveCursorToStart moves the cursor before the first character of the current target.
+func (fi *FormItemVector) MoveCursorToStart(selecting bool) {
+	fi.input().moveTo(0, selecting)
+	fi.updateCursor()
+}
+
+// MoveCursorToEnd moves the cursor after the last character of the current target.
+func (fi *FormItemVector) MoveCursorToEnd(selecting bool) {
+	in := fi.input()
+	in.moveTo(in.numRunes(), selecting)
+	fi.updateCursor()
+}
+
+// SetCursorFromPosition moves the cursor to the character boundary of the current target,
+// that is the closest to the x coordinate of the pointer.
+func (fi *FormItemVector) SetCursorFromPosition(x float32, selecting bool) {
+	textStart := fi.GetSurface().GetPosition().X() + fi.GetTarget().GetPosition().X() + fi.GetVectorCursorInitialPosition().X()
+	in := fi.input()
+	in.moveTo(in.indexAt(textStart-x), selecting)
+	fi.updateCursor()
+}
+
+// SelectAll selects the whole value of the current target.
+func (fi *FormItemVector) SelectAll() {
+	fi.input().selectAll()
+	fi.updateCursor()
+}
+
+// GetCursorIndex returns the position of the cursor in the value of the current target.
+func (fi *FormItemVector) GetCursorIndex() int {
+	return fi.input().cursorIndex()
+}
+
+// GetSelection returns the start and the end index of the selected characters of the
+// current target. Without selection, both of them are the cursor index.
+func (fi *FormItemVector) GetSelection() (int, int) {
+	return fi.input().selection()
+}
+
+// GetSelectedText returns the selected part of the value of the current target.
+func (fi *FormItemVector) GetSelectedText() string {
+	return fi.input().selectedText()
+}
+
+// SetMaxLength sets the max length of the components.
+func (fi *FormItemVector) SetMaxLength(maxLen int) {
+	fi.maxLen = maxLen
+}
+
+// GetMaxLength returns the max length of the components.
+func (fi *FormItemVector) GetMaxLength() int {
+	return fi.maxLen
+}
+
+// SetPlaceholder sets the text, that is displayed in the empty components.
+func (fi *FormItemVector) SetPlaceholder(placeholder string) {
+	fi.placeholder = placeholder
+}
+
+// GetPlaceholder returns the text, that is displayed in the empty components.
+func (fi *FormItemVector) GetPlaceholder() string {
+	return fi.placeholder
+}
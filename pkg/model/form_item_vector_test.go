@@ -444,3 +444,39 @@ func TestFormItemVectorDeleteLastCharacter(t *testing.T) {
 		t.Errorf("Invalid typeState. Instead of 'P', we have '%s'.", fi.typeStates[0])
 	}
 }
+func TestFormItemVectorMoveCursor(t *testing.T) {
+	fi := testFormItemVector(t)
+	fi.AddCursor()
+	typeString(fi, "13")
+	fi.MoveCursor(-1, false)
+	fi.CharCallback('.', 0.1)
+	if fi.values[0] != "1.3" {
+		t.Errorf("Invalid value. Instead of '1.3', we have '%s'.", fi.values[0])
+	}
+	if fi.typeStates[0] != "PF" {
+		t.Errorf("Invalid typeState. Instead of 'PF', we have '%s'.", fi.typeStates[0])
+	}
+	fi.MoveCursorToStart(false)
+	fi.CharCallback('.', 0.1)
+	if fi.values[0] != "1.3" {
+		t.Errorf("Invalid value. Instead of '1.3', we have '%s'.", fi.values[0])
+	}
+	fi.CharCallback('-', 0.1)
+	if fi.values[0] != "-1.3" {
+		t.Errorf("Invalid value. Instead of '-1.3', we have '%s'.", fi.values[0])
+	}
+	fi.SelectAll()
+	if fi.GetSelectedText() != "-1.3" {
+		t.Errorf("Invalid selected text. Instead of '-1.3', we have '%s'.", fi.GetSelectedText())
+	}
+	fi.DeleteCursor()
+	fi.SetTarget(1)
+	fi.AddCursor()
+	if fi.GetCursorIndex() != 0 {
+		t.Errorf("Invalid cursor index. Instead of '0', we have '%d'.", fi.GetCursorIndex())
+	}
+	fi.SetPlaceholder("0.0")
+	if fi.GetPlaceholder() != "0.0" {
+		t.Errorf("Invalid placeholder. Instead of '0.0', we have '%s'.", fi.GetPlaceholder())
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/akosgarai/playground_engine/pkg/glwrapper"
 	"github.com/akosgarai/playground_engine/pkg/interfaces"
 	"github.com/akosgarai/playground_engine/pkg/mesh"
-	"github.com/akosgarai/playground_engine/pkg/primitives/rectangle"
 	"github.com/akosgarai/playground_engine/pkg/texture"
 
 	"github.com/go-gl/mathgl/mgl32"
@@ -29,10 +28,33 @@ type Glyph struct {
 	BearingY int
 	Advance  int
 	tex      texture.Textures
+	// the position of the glyph in the atlas of the charset in pixels
+	// and its region in texture coordinates.
+	atlasX   int
+	atlasY   int
+	atlasMin mgl32.Vec2
+	atlasMax mgl32.Vec2
+	// the font of the charset, that the glyph is rendered from.
+	font *charsetFont
+	// the extension of the glyph image on every side. It is the spread of the sdf glyphs.
+	padding int
 }
 
-// Build setups the Glyph parameters.
+// Build setups the Glyph parameters and the texture of the glyph.
 func (g *Glyph) Build(ch rune, ttf *truetype.Font, options *truetype.Options, filePath string, wrapper interfaces.GLWrapper) error {
+	background, err := g.render(ch, ttf, options)
+	if err != nil {
+		return err
+	}
+	var tex texture.Textures
+	// Generate texture
+	tex.AddTextureRGBA(filePath, background, glwrapper.CLAMP_TO_EDGE, glwrapper.CLAMP_TO_EDGE, glwrapper.LINEAR, glwrapper.LINEAR, "tex", wrapper)
+	g.tex = tex
+	return nil
+}
+
+// render setups the Glyph parameters and returns the image of the glyph.
+func (g *Glyph) render(ch rune, ttf *truetype.Font, options *truetype.Options) (*image.RGBA, error) {
 	if g.Debug {
 		fmt.Printf("Glyph.Build: %v\t'%c'\n\tOptions: %#v\n", ch, ch, options)
 	}
@@ -41,7 +63,7 @@ func (g *Glyph) Build(ch rune, ttf *truetype.Font, options *truetype.Options, fi
 	if ok != true {
 		msg := fmt.Sprintf("ttfFace.GlyphBounds(ch) not OK. %v\t'%c' ... Skipping\n", ch, ch)
 		fmt.Printf("\t%s\n", msg)
-		return errors.New(msg)
+		return nil, errors.New(msg)
 	}
 	if g.Debug {
 		fmt.Printf("\tgBnd: %#v\n\tgAdv: %#v\n", gBnd, gAdv)
@@ -91,13 +113,14 @@ func (g *Glyph) Build(ch rune, ttf *truetype.Font, options *truetype.Options, fi
 	// Draw the text from mask to image
 	_, err := c.DrawString(string(ch), pt)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	var tex texture.Textures
-	// Generate texture
-	tex.AddTextureRGBA(filePath, background, glwrapper.CLAMP_TO_EDGE, glwrapper.CLAMP_TO_EDGE, glwrapper.LINEAR, glwrapper.LINEAR, "tex", wrapper)
-	g.tex = tex
-	return nil
+	return background, nil
+}
+
+// imageSize returns the size of the glyph image with the padding.
+func (g *Glyph) imageSize() (int, int) {
+	return g.Width + 2*g.padding, g.Height + 2*g.padding
 }
 func (g *Glyph) context(ttf *truetype.Font, background *image.RGBA, foreground *image.Uniform, dpi, size float64) *freetype.Context {
 	if g.Debug {
@@ -125,93 +148,313 @@ func (g *Glyph) rgba(bg *image.Uniform) *image.RGBA {
 	return rgba
 }
 
+const (
+	// The styles of the charset fonts.
+	STYLE_REGULAR = iota
+	STYLE_BOLD
+	STYLE_ITALIC
+	STYLE_BOLD_ITALIC
+)
+
+// charsetFont is a parsed font file of a charset.
+type charsetFont struct {
+	ttf  *truetype.Font
+	face font.Face
+}
+
+// glyphKey identifies a rendered glyph of the charset.
+type glyphKey struct {
+	r    rune
+	font *charsetFont
+}
+
 type Charset struct {
 	*BaseModel
-	fonts map[rune]*Glyph
+	fonts map[glyphKey]*Glyph
 	Debug bool
+	// the kerning of the font is applied between the glyphs, if it is true.
+	Kerning bool
+	// the font stacks of the styles. The first font of a stack is the primary one,
+	// the others are the fallback fonts of the missing glyphs.
+	stacks  map[int][]*charsetFont
+	options *truetype.Options
+	// the style of the following texts.
+	style int
+	// the texture and the image of the packed glyph images.
+	atlas      texture.Textures
+	atlasImage *glyphAtlas
+	// it is true if the atlas image has been modified since the last upload.
+	atlasDirty bool
+	// the world texts, that has to be updated after the growth of the atlas.
+	worldTexts map[*WorldText]bool
+	// the distance of the baselines of the lines in pixels.
+	lineHeight int
+	// the spread of the signed distance field glyphs in pixels. It is 0 for the bitmap glyphs.
+	sdfSpread int
+	// the text meshes that are removed from the model. They are reused by the PrintTo.
+	unused []*mesh.TexturedColoredMesh
 }
 
 // LoadCharset sets up a Charset based on the input values. On case of error, it returns it with an empty Charset. On case
-// of succes, it returns the initialized Charset and nil.
+// of succes, it returns the initialized Charset and nil. The glyphs of the [low, high] interval are rendered immediately,
+// the other glyphs are rendered on their first use.
 func LoadCharset(filePath string, low, high rune, scale float64, dpi float64, wrapper interfaces.GLWrapper) (*Charset, error) {
-	fd, err := os.Open(filePath)
-	if err != nil {
-		return &Charset{}, err
-	}
-	defer fd.Close()
+	return loadCharset(filePath, low, high, scale, dpi, 0, wrapper, false)
+}
 
-	data, err := ioutil.ReadAll(fd)
-	if err != nil {
-		return &Charset{}, err
-	}
+// LoadCharsetDebug sets up a Charset based on the input values. On case of error, it returns it with an empty Charset. On case
+// of succes, it returns the initialized Charset and nil. It prints out partial result informations to the console.
+func LoadCharsetDebug(filePath string, low, high rune, scale float64, dpi float64, wrapper interfaces.GLWrapper) (*Charset, error) {
+	return loadCharset(filePath, low, high, scale, dpi, 0, wrapper, true)
+}
 
-	ttf, err := truetype.Parse(data)
+// LoadSDFCharset sets up a Charset with signed distance field glyphs. The glyphs are rendered with the given
+// scale and dpi, then they are converted to distance fields with the given spread (in pixels). The sdf charsets
+// have to be drawn with the sdf font shaders, so that the texts are sharp at any size. In case of not positive
+// spread it panics. On case of error, it returns it with an empty Charset.
+func LoadSDFCharset(filePath string, low, high rune, scale float64, dpi float64, spread int, wrapper interfaces.GLWrapper) (*Charset, error) {
+	if spread <= 0 {
+		panic("The spread of the sdf charset has to be positive.")
+	}
+	return loadCharset(filePath, low, high, scale, dpi, spread, wrapper, false)
+}
+func loadCharset(filePath string, low, high rune, scale float64, dpi float64, sdfSpread int, wrapper interfaces.GLWrapper, debug bool) (*Charset, error) {
+	options := &truetype.Options{
+		Size:    scale,
+		DPI:     dpi,
+		Hinting: font.HintingFull,
+	}
+	f, err := parseFont(filePath, options, debug)
 	if err != nil {
 		return &Charset{}, err
 	}
-	fonts := make(map[rune]*Glyph)
+	c := &Charset{
+		BaseModel:  New(),
+		fonts:      make(map[glyphKey]*Glyph),
+		Debug:      debug,
+		Kerning:    true,
+		stacks:     map[int][]*charsetFont{STYLE_REGULAR: []*charsetFont{f}},
+		options:    options,
+		style:      STYLE_REGULAR,
+		lineHeight: f.face.Metrics().Height.Ceil(),
+		sdfSpread:  sdfSpread,
+	}
+	glyphs := make(map[rune]*Glyph)
+	images := make(map[rune]*image.RGBA)
 	for ch := low; ch <= high; ch++ {
-		g := &Glyph{Debug: false}
-		options := &truetype.Options{
-			Size:    scale,
-			DPI:     dpi,
-			Hinting: font.HintingFull,
-		}
-		err := g.Build(ch, ttf, options, filePath, wrapper)
+		g, img, err := c.renderGlyph(ch, f)
 		if err != nil {
 			continue
 		}
-		fonts[ch] = g
+		glyphs[ch] = g
+		images[ch] = img
 	}
-	m := New()
-	return &Charset{
-		BaseModel: m,
-		fonts:     fonts,
-		Debug:     false,
-	}, nil
+	c.atlasImage = packGlyphs(glyphs, images)
+	for ch, g := range glyphs {
+		c.fonts[glyphKey{ch, f}] = g
+	}
+	c.updateRegions()
+	if debug {
+		fmt.Printf("Atlas size: %v\n", c.atlasImage.image.Bounds().Size())
+	}
+	c.atlas.AddTextureRGBA(filePath, c.atlasImage.image, glwrapper.CLAMP_TO_EDGE, glwrapper.CLAMP_TO_EDGE, glwrapper.LINEAR, glwrapper.LINEAR, "tex", wrapper)
+	return c, nil
 }
 
-// LoadCharsetDebug sets up a Charset based on the input values. On case of error, it returns it with an empty Charset. On case
-// of succes, it returns the initialized Charset and nil. It prints out partial result informations to the console.
-func LoadCharsetDebug(filePath string, low, high rune, scale float64, dpi float64, wrapper interfaces.GLWrapper) (*Charset, error) {
-	fmt.Printf("Opening '%s'.\n", filePath)
+// parseFont reads and parses the given font file.
+func parseFont(filePath string, options *truetype.Options, debug bool) (*charsetFont, error) {
+	if debug {
+		fmt.Printf("Opening '%s'.\n", filePath)
+	}
 	fd, err := os.Open(filePath)
 	if err != nil {
-		return &Charset{}, err
+		return nil, err
 	}
 	defer fd.Close()
 
-	fmt.Printf("Reading '%s'.\n", filePath)
+	if debug {
+		fmt.Printf("Reading '%s'.\n", filePath)
+	}
 	data, err := ioutil.ReadAll(fd)
 	if err != nil {
-		return &Charset{}, err
+		return nil, err
 	}
 
-	fmt.Printf("Parsing '%s'.\n", filePath)
+	if debug {
+		fmt.Printf("Parsing '%s'.\n", filePath)
+	}
 	ttf, err := truetype.Parse(data)
 	if err != nil {
-		return &Charset{}, err
+		return nil, err
 	}
-	fonts := make(map[rune]*Glyph)
-	for ch := low; ch <= high; ch++ {
-		g := &Glyph{Debug: true}
-		options := &truetype.Options{
-			Size:    scale,
-			DPI:     dpi,
-			Hinting: font.HintingFull,
+	return &charsetFont{ttf: ttf, face: truetype.NewFace(ttf, options)}, nil
+}
+
+// AddFont appends the font file to the font stack of the given style. The first font of the
+// stack is the primary font of the style, the others are used for the glyphs that are missing
+// from the previous fonts. The glyphs that are missing from every font of a style stack are
+// searched in the regular stack. In case of error, the stack is not modified.
+func (c *Charset) AddFont(style int, filePath string) error {
+	f, err := parseFont(filePath, c.options, c.Debug)
+	if err != nil {
+		return err
+	}
+	c.stacks[style] = append(c.stacks[style], f)
+	return nil
+}
+
+// AddFontFamily adds the bold, italic and bold italic faces of the font to the stacks of their
+// styles. The empty file paths are skipped. In case of error, the stacks are not modified.
+func (c *Charset) AddFontFamily(bold, italic, boldItalic string) error {
+	files := map[int]string{STYLE_BOLD: bold, STYLE_ITALIC: italic, STYLE_BOLD_ITALIC: boldItalic}
+	fonts := make(map[int]*charsetFont)
+	for style, filePath := range files {
+		if filePath == "" {
+			continue
 		}
-		err := g.Build(ch, ttf, options, filePath, wrapper)
+		f, err := parseFont(filePath, c.options, c.Debug)
 		if err != nil {
-			continue
+			return err
+		}
+		fonts[style] = f
+	}
+	for style, f := range fonts {
+		c.stacks[style] = append(c.stacks[style], f)
+	}
+	return nil
+}
+
+// SetStyle sets the style of the following texts. The size calculations also use it.
+func (c *Charset) SetStyle(style int) {
+	c.style = style
+}
+
+// GetStyle returns the style of the following texts.
+func (c *Charset) GetStyle() int {
+	return c.style
+}
+
+// findFont returns the first font of the current style stack, that contains the given rune.
+// If it is missing from every font, the primary font is returned, that renders the missing
+// glyph symbol of the font.
+func (c *Charset) findFont(r rune) *charsetFont {
+	stacks := [][]*charsetFont{c.stacks[c.style]}
+	if c.style != STYLE_REGULAR {
+		stacks = append(stacks, c.stacks[STYLE_REGULAR])
+	}
+	var primary *charsetFont
+	for _, stack := range stacks {
+		for _, f := range stack {
+			if primary == nil {
+				primary = f
+			}
+			if f.ttf.Index(r) != 0 {
+				return f
+			}
 		}
-		fonts[ch] = g
 	}
-	m := New()
-	return &Charset{
-		BaseModel: m,
-		fonts:     fonts,
-		Debug:     true,
-	}, nil
+	return primary
+}
+
+// glyph returns the glyph of the rune in the current style. The glyph is rendered and
+// inserted to the atlas on its first use. It returns nil for the control characters and
+// for the glyphs that don't fit to the full atlas, so that they are skipped.
+func (c *Charset) glyph(r rune) *Glyph {
+	if r < 32 || r == 127 {
+		return nil
+	}
+	f := c.findFont(r)
+	if f == nil {
+		return nil
+	}
+	if g, ok := c.fonts[glyphKey{r, f}]; ok {
+		return g
+	}
+	g, img, err := c.renderGlyph(r, f)
+	if err != nil {
+		return nil
+	}
+	oldWidth, oldHeight := c.atlasImage.size()
+	grown, err := c.atlasImage.insert(g, img)
+	if err != nil {
+		return nil
+	}
+	c.fonts[glyphKey{r, f}] = g
+	if grown {
+		width, height := c.atlasImage.size()
+		c.updateRegions()
+		c.scaleTextureCoordinates(mgl32.Vec2{float32(oldWidth) / float32(width), float32(oldHeight) / float32(height)})
+	} else {
+		g.atlasMin, g.atlasMax = c.atlasImage.region(g)
+	}
+	c.atlasDirty = true
+	return g
+}
+
+// renderGlyph returns the glyph of the rune and its image. In case of sdf charset, the image
+// is the signed distance field of the glyph.
+func (c *Charset) renderGlyph(r rune, f *charsetFont) (*Glyph, *image.RGBA, error) {
+	g := &Glyph{Debug: c.Debug, font: f}
+	img, err := g.render(r, f.ttf, c.options)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.sdfSpread > 0 {
+		img = signedDistanceField(img, c.sdfSpread)
+		g.padding = c.sdfSpread
+	}
+	return g, img, nil
+}
+
+// IsSDF returns true if the glyphs of the charset are signed distance fields.
+func (c *Charset) IsSDF() bool {
+	return c.sdfSpread > 0
+}
+
+// GetSDFSpread returns the spread of the signed distance field glyphs in pixels.
+func (c *Charset) GetSDFSpread() int {
+	return c.sdfSpread
+}
+
+// SetTextEffects sets the outline, shadow and glow parameters of the texts. They are
+// applied by the sdf font shader, so that it is useful only for the sdf charsets.
+func (c *Charset) SetTextEffects(e TextEffects) {
+	e.setUniforms(&c.Model)
+}
+
+// updateRegions sets the atlas regions of the glyphs.
+func (c *Charset) updateRegions() {
+	for _, g := range c.fonts {
+		g.atlasMin, g.atlasMax = c.atlasImage.region(g)
+	}
+}
+
+// scaleTextureCoordinates updates the texture coordinates of the printed texts after the growth of the atlas.
+func (c *Charset) scaleTextureCoordinates(scale mgl32.Vec2) {
+	meshes := append([]*mesh.TexturedColoredMesh{}, c.unused...)
+	for i := range c.meshes {
+		if tcm, ok := c.meshes[i].(*mesh.TexturedColoredMesh); ok {
+			meshes = append(meshes, tcm)
+		}
+	}
+	for _, msh := range meshes {
+		for i := range msh.Vertices {
+			msh.Vertices[i].TexCoords = mgl32.Vec2{msh.Vertices[i].TexCoords.X() * scale.X(), msh.Vertices[i].TexCoords.Y() * scale.Y()}
+		}
+		msh.SetGeometry(msh.Vertices, msh.Indices)
+	}
+	for wt, _ := range c.worldTexts {
+		wt.scaleTextureRegions(scale)
+	}
+}
+
+// uploadAtlas uploads the atlas image to the texture, if it has been modified.
+func (c *Charset) uploadAtlas() {
+	if !c.atlasDirty {
+		return
+	}
+	c.atlas[0].SetRGBAData(c.atlasImage.image)
+	c.atlasDirty = false
 }
 
 // TextLength returns the width of the given text.
@@ -220,84 +463,106 @@ func (c *Charset) TextWidth(text string, scale float32) float32 {
 	return x
 }
 
-// TextContainerSize returns the minimum size of the text container mesh.
+// TextContainerSize returns the minimum size of the text container mesh. The width is the width
+// of the longest line, the height is the height of the highest glyph of the first line
+// extended with the line height for every other line.
 func (c *Charset) TextContainerSize(text string, scale float32) (float32, float32) {
 	x := float32(0.0)
 	y := float32(0.0)
-	indices := []rune(text)
-	if len(indices) == 0 {
-		return x, y
-	}
-	// the low rune value from the LoadCharset function.
-	lc := rune(32)
-	for i := range indices {
-		runeIndex := indices[i]
-		//skip runes that are not in font chacter range
-		if int(runeIndex)-int(lc) > len(c.fonts) || runeIndex < lc {
+	lines := c.layout(text, scale, ALIGN_LEFT)
+	for i := range lines {
+		if lines[i].width > x {
+			x = lines[i].width
+		}
+		if i > 0 {
 			continue
 		}
-		ch := c.fonts[runeIndex]
-		x += float32(ch.Advance) * scale
-		if float32(ch.Height)*scale > y {
-			y = float32(ch.Height) * scale
+		for _, q := range lines[i].glyphs {
+			if float32(q.glyph.Height)*scale > y {
+				y = float32(q.glyph.Height) * scale
+			}
 		}
 	}
+	if len(lines) > 1 {
+		y += float32(len(lines)-1) * c.LineHeight(scale)
+	}
 	return x, y
 }
 
-// PrintTo sets up the meshes for displaying text on a given surface.
+// LineHeight returns the distance of the baselines of the lines.
+func (c *Charset) LineHeight(scale float32) float32 {
+	return float32(c.lineHeight) * scale
+}
+
+// PrintTo sets up the mesh for displaying text on a given surface. The text is left aligned
+// to the given position.
 func (c *Charset) PrintTo(text string, x, y, z, scale float32, wrapper interfaces.GLWrapper, surface interfaces.Mesh, cols []mgl32.Vec3) {
-	indices := []rune(text)
+	c.PrintAlignedTo(text, x, y, z, scale, ALIGN_LEFT, wrapper, surface, cols)
+}
+
+// PrintAlignedTo sets up the mesh for displaying text on a given surface. The glyphs of the text
+// are batched to one mesh, that uses the atlas texture of the charset. The lines of the text
+// are aligned to the x position with the given alignment. The colors are applied per glyph,
+// in case of more colors than one, the glyphs get them in turn.
+func (c *Charset) PrintAlignedTo(text string, x, y, z, scale float32, align int, wrapper interfaces.GLWrapper, surface interfaces.Mesh, cols []mgl32.Vec3) {
 	if c.Debug {
-		fmt.Printf("The following text will be printed: '%s' as '%v'\n", text, indices)
+		fmt.Printf("The following text will be printed: '%s' as '%v'\n", text, []rune(text))
 	}
-	if len(indices) == 0 {
+	v, i := c.textVertices(text, x, y, z, scale, align, cols)
+	c.uploadAtlas()
+	if len(i) == 0 {
 		return
 	}
-	// the low rune value from the LoadCharset function.
-	lc := rune(32)
-	var mshStore []interfaces.Mesh
-	for i := range indices {
-		runeIndex := indices[i]
-		//skip runes that are not in font chacter range
-		if int(runeIndex)-int(lc) > len(c.fonts) || runeIndex < lc {
-			if c.Debug {
-				fmt.Printf("Skipping: %c %d\n", runeIndex, runeIndex)
-			}
-			continue
-		}
-		ch := c.fonts[runeIndex]
-		//calculate position and size for current rune
-		xpos := x + float32(ch.BearingX)*scale
-		ypos := y + float32(ch.Height-ch.BearingY)*scale
-		w := float32(ch.Width) * scale
-		h := float32(ch.Height) * scale
-		rect := rectangle.NewExact(w, h)
-		v, i, _ := rect.TexturedColoredMeshInput(cols)
-		rotTr := surface.RotationTransformation()
-		position := mgl32.Vec3{x + float32(ch.BearingX+ch.Width/2)*scale, z, y - float32(ch.BearingY-ch.Height/2)*scale}
-		msh := mesh.NewTexturedColoredMesh(v, i, ch.tex, cols, wrapper)
-		msh.SetPosition(mgl32.TransformCoordinate(position, rotTr))
-		msh.SetParent(surface)
-		mshStore = append(mshStore, msh)
-		if c.Debug {
-			fmt.Printf("pos: %#v\nch: %#v\nw: %f, h: %f, xpos: %f, ypos: %f, adv: %f\n\n", position.Mul(scale), ch, w, h, xpos, ypos, float32(ch.Advance)*scale)
-		}
-		x += float32(ch.Advance) * scale
-	}
-	for i := len(mshStore) - 1; i >= 0; i-- {
-		c.Model.AddMesh(mshStore[i])
+	var msh *mesh.TexturedColoredMesh
+	if len(c.unused) > 0 {
+		msh = c.unused[len(c.unused)-1]
+		c.unused = c.unused[:len(c.unused)-1]
+		msh.Color = cols
+		msh.SetGeometry(v, i)
+	} else {
+		msh = mesh.NewTexturedColoredMesh(v, i, c.atlas, cols, wrapper)
 	}
+	msh.SetParent(surface)
+	c.AddMesh(msh)
 }
 
-// CleanSurface deletes those printed texts where the parent mesh is the given surface
+// CleanSurface deletes those printed texts where the parent mesh is the given surface.
+// The meshes of the texts are kept for the next prints.
 func (c *Charset) CleanSurface(msh interfaces.Mesh) {
 	var meshes []interfaces.Mesh
 	for i, _ := range c.meshes {
 		parent := c.meshes[i].GetParent()
 		if parent != msh {
 			meshes = append(meshes, c.meshes[i])
+			continue
 		}
+		c.recycle(c.meshes[i])
 	}
 	c.meshes = meshes
 }
+
+// DrawSurface draws only the texts of the given surface. It could be used, when the
+// texts of the surfaces need different gl state, like the scissor box of the ui widgets.
+func (c *Charset) DrawSurface(s interfaces.Shader, surface interfaces.Mesh) {
+	c.customUniforms(s)
+	for i := range c.meshes {
+		if c.meshes[i].GetParent() == surface {
+			c.meshes[i].Draw(s)
+		}
+	}
+}
+
+// Clear deletes every printed text. The meshes of the texts are kept for the next prints.
+func (c *Charset) Clear() {
+	for i, _ := range c.meshes {
+		c.recycle(c.meshes[i])
+	}
+	c.Model.Clear()
+}
+
+// recycle stores the text mesh for the next prints.
+func (c *Charset) recycle(msh interfaces.Mesh) {
+	if tcm, ok := msh.(*mesh.TexturedColoredMesh); ok {
+		c.unused = append(c.unused, tcm)
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"image"
 	"testing"
 
 	"github.com/akosgarai/playground_engine/pkg/mesh"
@@ -38,13 +39,122 @@ func TestCharsetCleanSurface(t *testing.T) {
 	}
 	msh := mesh.NewPointMesh(wrapperMock)
 	fonts.PrintTo("Hello", 0, 0, 0.0, 1.0, wrapperMock, msh, cols)
-	if len(fonts.meshes) != 5 {
-		t.Errorf("Invalid number of meshes. Instead of '%d', we have '%d'.", 5, len(fonts.meshes))
+	if len(fonts.meshes) != 1 {
+		t.Errorf("Invalid number of meshes. Instead of '%d', we have '%d'.", 1, len(fonts.meshes))
 	}
+	printed := fonts.meshes[0]
 	fonts.CleanSurface(msh)
 	if len(fonts.meshes) != 0 {
 		t.Errorf("Invalid number of meshes. Instead of '%d', we have '%d'.", 0, len(fonts.meshes))
 	}
+	// the next print reuses the mesh of the cleaned text.
+	fonts.PrintTo("Hello world", 0, 0, 0.0, 1.0, wrapperMock, msh, cols)
+	if len(fonts.meshes) != 1 || fonts.meshes[0] != printed {
+		t.Error("The mesh of the cleaned text should be reused.")
+	}
+	if len(printed.(*mesh.TexturedColoredMesh).Vertices) != 44 {
+		t.Errorf("Invalid number of vertices. Instead of '%d', we have '%d'.", 44, len(printed.(*mesh.TexturedColoredMesh).Vertices))
+	}
+	fonts.Clear()
+	if len(fonts.meshes) != 0 || len(fonts.unused) != 1 {
+		t.Error("The cleared text should be stored for the next prints.")
+	}
+}
+func TestCharsetPrintToRotatedSurface(t *testing.T) {
+	cols := []mgl32.Vec3{mgl32.Vec3{0, 0, 1}}
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	msh := mesh.NewPointMesh(wrapperMock)
+	msh.RotateX(-90)
+	fonts.PrintTo("Hello", 0, 0, 0.5, 1.0, wrapperMock, msh, cols)
+	// the vertices are in the local space of the surface, the rotation is applied by the parent.
+	for _, v := range fonts.meshes[0].(*mesh.TexturedColoredMesh).Vertices {
+		if v.Position.Y() != 0.5 {
+			t.Errorf("Invalid vertex position. The y component should be '0.5', we have '%v'.", v.Position)
+		}
+	}
+	other := mesh.NewPointMesh(wrapperMock)
+	fonts.PrintTo("World", 0, 0, 0.0, 1.0, wrapperMock, other, cols)
+	fonts.DrawSurface(testhelper.ShaderMock{}, msh)
+}
+func TestCharsetAtlas(t *testing.T) {
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	if len(fonts.atlas) != 1 {
+		t.Errorf("Invalid number of atlas textures. Instead of '1', we have '%d'.", len(fonts.atlas))
+	}
+	for r, g := range fonts.fonts {
+		if g.atlasMin.X() < 0 || g.atlasMin.Y() < 0 || g.atlasMax.X() > 1 || g.atlasMax.Y() > 1 || g.atlasMin.X() >= g.atlasMax.X() || g.atlasMin.Y() >= g.atlasMax.Y() {
+			t.Errorf("Invalid atlas region of '%c': '%v' - '%v'.", r.r, g.atlasMin, g.atlasMax)
+		}
+		for r2, g2 := range fonts.fonts {
+			if r == r2 {
+				continue
+			}
+			if g.atlasMin.X() < g2.atlasMax.X() && g2.atlasMin.X() < g.atlasMax.X() && g.atlasMin.Y() < g2.atlasMax.Y() && g2.atlasMin.Y() < g.atlasMax.Y() {
+				t.Errorf("The atlas regions of '%c' and '%c' are overlapping.", r.r, r2.r)
+			}
+		}
+	}
+}
+func TestCharsetPrintAlignedTo(t *testing.T) {
+	cols := []mgl32.Vec3{mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 1, 0}}
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	msh := mesh.NewPointMesh(wrapperMock)
+	// the empty text doesn't need mesh.
+	fonts.PrintAlignedTo("", 0, 0, 0, 1, ALIGN_LEFT, wrapperMock, msh, cols)
+	if len(fonts.meshes) != 0 {
+		t.Errorf("Invalid number of meshes. Instead of '%d', we have '%d'.", 0, len(fonts.meshes))
+	}
+	minX := func(align int) float32 {
+		fonts.Clear()
+		fonts.PrintAlignedTo("ab", 0, 0, 0, 1, align, wrapperMock, msh, cols)
+		v := fonts.meshes[0].(*mesh.TexturedColoredMesh).Vertices
+		// the per glyph colors
+		if v[0].Color != cols[0] || v[3].Color != cols[0] || v[4].Color != cols[1] {
+			t.Error("Invalid glyph colors.")
+		}
+		return v[0].Position.X()
+	}
+	left := minX(ALIGN_LEFT)
+	width := fonts.TextWidth("ab", 1)
+	if center := minX(ALIGN_CENTER); !testhelper.Float32ApproxEqual(center, left-width/2, 0.0001) {
+		t.Errorf("Invalid centered position. Instead of '%f', we have '%f'.", left-width/2, center)
+	}
+	if right := minX(ALIGN_RIGHT); !testhelper.Float32ApproxEqual(right, left-width, 0.0001) {
+		t.Errorf("Invalid right aligned position. Instead of '%f', we have '%f'.", left-width, right)
+	}
+	// the second line starts at the same x, one line height lower.
+	fonts.Clear()
+	fonts.PrintAlignedTo("a\na", 0, 0, 0, 1, ALIGN_LEFT, wrapperMock, msh, cols)
+	v := fonts.meshes[0].(*mesh.TexturedColoredMesh).Vertices
+	if len(v) != 8 {
+		t.Errorf("Invalid number of vertices. Instead of '8', we have '%d'.", len(v))
+	}
+	if v[0].Position.X() != v[4].Position.X() {
+		t.Errorf("Invalid line start. Instead of '%f', we have '%f'.", v[0].Position.X(), v[4].Position.X())
+	}
+	if !testhelper.Float32ApproxEqual(v[0].Position.Z()-v[4].Position.Z(), fonts.LineHeight(1), 0.0001) {
+		t.Errorf("Invalid line distance. Instead of '%f', we have '%f'.", fonts.LineHeight(1), v[0].Position.Z()-v[4].Position.Z())
+	}
+}
+func TestCharsetKerning(t *testing.T) {
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	kerned := fonts.TextWidth("Spotlight editor", 1)
+	fonts.Kerning = false
+	if width := fonts.TextWidth("Spotlight editor", 1); width-kerned != 44 {
+		t.Errorf("Invalid kerning. Instead of '44', we have '%f'.", width-kerned)
+	}
 }
 func TestCharsetTextWidth(t *testing.T) {
 	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
@@ -87,7 +197,8 @@ func TestCharsetTextContainerSize(t *testing.T) {
 		{"b", 1, 22, 30},
 		{"1", 1, 15, 21},
 		{"b1", 1, 37, 30},
-		{"Spotlight editor", float32(3) / float32(800), 1.128750, 0.15},
+		{"Spotlight editor", float32(3) / float32(800), 0.963750, 0.15},
+		{"b1\nb", 1, 37, 30 + float32(fonts.lineHeight)},
 	}
 	for _, tt := range testData {
 		width, height := fonts.TextContainerSize(tt.text, tt.scale)
@@ -99,3 +210,189 @@ func TestCharsetTextContainerSize(t *testing.T) {
 		}
 	}
 }
+func TestCharsetLazyGlyphs(t *testing.T) {
+	cols := []mgl32.Vec3{mgl32.Vec3{0, 0, 1}}
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	loaded := len(fonts.fonts)
+	// the accented characters are out of the loaded range.
+	if width := fonts.TextWidth("éá", 1); width <= 0 {
+		t.Errorf("Invalid text width. It should be positive, we have '%f'.", width)
+	}
+	if len(fonts.fonts) != loaded+2 {
+		t.Errorf("Invalid number of glyphs. Instead of '%d', we have '%d'.", loaded+2, len(fonts.fonts))
+	}
+	if !fonts.atlasDirty {
+		t.Error("The atlas should be modified.")
+	}
+	msh := mesh.NewPointMesh(wrapperMock)
+	fonts.PrintTo("éá", 0, 0, 0, 1, wrapperMock, msh, cols)
+	if fonts.atlasDirty {
+		t.Error("The atlas should be uploaded.")
+	}
+	if len(fonts.meshes[0].(*mesh.TexturedColoredMesh).Vertices) != 8 {
+		t.Errorf("Invalid number of vertices. Instead of '8', we have '%d'.", len(fonts.meshes[0].(*mesh.TexturedColoredMesh).Vertices))
+	}
+	// the control characters are skipped.
+	if fonts.glyph('\t') != nil {
+		t.Error("The control characters shouldn't have glyph.")
+	}
+}
+func TestCharsetAtlasGrowth(t *testing.T) {
+	cols := []mgl32.Vec3{mgl32.Vec3{0, 0, 1}}
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 33, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	wt := NewWorldText(fonts)
+	msh := mesh.NewPointMesh(wrapperMock)
+	fonts.PrintTo("!", 0, 0, 0, 1, wrapperMock, msh, cols)
+	wt.Print("!", mgl32.Vec3{0, 0, 0}, 1, wrapperMock, nil, cols)
+	_, height := fonts.atlasImage.size()
+	fonts.PrintTo("The quick brown fox jumps over the lazy dog", 0, 0, 0, 1, wrapperMock, msh, cols)
+	_, newHeight := fonts.atlasImage.size()
+	if newHeight <= height {
+		t.Fatalf("The atlas should be grown. Old height: '%d', new height: '%d'.", height, newHeight)
+	}
+	g := fonts.glyph('!')
+	// the texture coordinates of the printed texts follow the growth.
+	v := fonts.meshes[0].(*mesh.TexturedColoredMesh).Vertices
+	if !testhelper.Float32ApproxEqual(v[3].TexCoords.Y(), g.atlasMin.Y(), 0.00001) || !testhelper.Float32ApproxEqual(v[0].TexCoords.Y(), g.atlasMax.Y(), 0.00001) {
+		t.Errorf("Invalid texture coordinates. Instead of '%v' - '%v', we have '%v' - '%v'.", g.atlasMin, g.atlasMax, v[3].TexCoords, v[0].TexCoords)
+	}
+	min, max, _ := wt.meshes[0].(*mesh.BillboardMesh).GetTextureRegion()
+	if !testhelper.Float32ApproxEqual(min.Y(), g.atlasMin.Y(), 0.00001) || !testhelper.Float32ApproxEqual(max.Y(), g.atlasMax.Y(), 0.00001) {
+		t.Errorf("Invalid billboard region. Instead of '%v' - '%v', we have '%v' - '%v'.", g.atlasMin, g.atlasMax, min, max)
+	}
+}
+func TestGlyphAtlasFull(t *testing.T) {
+	a := newGlyphAtlas(glyphAtlasMinSize, glyphAtlasMinSize)
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	if _, err := a.insert(&Glyph{Width: glyphAtlasMaxSize, Height: 10}, img); err != ErrorGlyphAtlasFull {
+		t.Errorf("Missing atlas full error. '%v'.", err)
+	}
+	if width, height := a.size(); width != glyphAtlasMinSize || height != glyphAtlasMinSize {
+		t.Errorf("The atlas shouldn't be grown. '%d', '%d'.", width, height)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := a.insert(&Glyph{Width: 4000, Height: 2000}, img); err != nil {
+			t.Fatalf("Unexpected error: '%s'.", err.Error())
+		}
+	}
+	if _, err := a.insert(&Glyph{Width: 4000, Height: 2000}, img); err != ErrorGlyphAtlasFull {
+		t.Errorf("Missing atlas full error. '%v'.", err)
+	}
+	// the small glyph still fits to the end of the last shelf.
+	g := &Glyph{Width: 10, Height: 10}
+	if _, err := a.insert(g, img); err != nil {
+		t.Fatalf("Unexpected error: '%s'.", err.Error())
+	}
+	if g.atlasX != 4002 || g.atlasY != 2002 {
+		t.Errorf("Invalid glyph position '%d', '%d'.", g.atlasX, g.atlasY)
+	}
+	if width, height := a.size(); width != glyphAtlasMaxSize || height != glyphAtlasMaxSize {
+		t.Errorf("Invalid atlas size '%d', '%d'.", width, height)
+	}
+}
+func TestCharsetFallbackFont(t *testing.T) {
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	primary := fonts.stacks[STYLE_REGULAR][0]
+	// the desyrel doesn't contain the hungarian long accents, it renders the missing glyph symbol.
+	if g := fonts.glyph('ő'); g.font != primary {
+		t.Error("The missing glyph should be rendered with the primary font.")
+	}
+	if err := fonts.AddFont(STYLE_REGULAR, "./assets/fonts/not-exists.ttf"); err == nil {
+		t.Error("Missing font file should return error.")
+	}
+	if err := fonts.AddFont(STYLE_REGULAR, "./assets/fonts/Go/Go-Regular.ttf"); err != nil {
+		t.Errorf("Error during font load: %s\n", err.Error())
+	}
+	fallback := fonts.stacks[STYLE_REGULAR][1]
+	if g := fonts.glyph('ű'); g.font != fallback {
+		t.Error("The missing glyph should be rendered with the fallback font.")
+	}
+	if g := fonts.glyph('a'); g.font != primary {
+		t.Error("The existing glyph should be rendered with the primary font.")
+	}
+	// the width is calculated with the same glyphs, that are printed.
+	if width := fonts.TextWidth("ű", 1); width != float32(fonts.glyph('ű').Advance) {
+		t.Errorf("Invalid text width. Instead of '%d', we have '%f'.", fonts.glyph('ű').Advance, width)
+	}
+}
+func TestCharsetStyles(t *testing.T) {
+	fonts, err := LoadCharset("./assets/fonts/Go/Go-Regular.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	if fonts.GetStyle() != STYLE_REGULAR {
+		t.Errorf("Invalid style. Instead of '%d', we have '%d'.", STYLE_REGULAR, fonts.GetStyle())
+	}
+	regular := fonts.TextWidth("Bold text", 1)
+	// without bold font the regular one is used.
+	fonts.SetStyle(STYLE_BOLD)
+	if width := fonts.TextWidth("Bold text", 1); width != regular {
+		t.Errorf("Invalid width without bold font. Instead of '%f', we have '%f'.", regular, width)
+	}
+	if err := fonts.AddFontFamily("./assets/fonts/Go/Go-Bold.ttf", "./assets/fonts/Go/not-exists.ttf", ""); err == nil {
+		t.Error("Missing font file should return error.")
+	}
+	if len(fonts.stacks[STYLE_BOLD]) != 0 {
+		t.Error("The stacks shouldn't be modified in case of error.")
+	}
+	if err := fonts.AddFontFamily("./assets/fonts/Go/Go-Bold.ttf", "./assets/fonts/Go/Go-Italic.ttf", "./assets/fonts/Go/Go-Bold-Italic.ttf"); err != nil {
+		t.Errorf("Error during font load: %s\n", err.Error())
+	}
+	bold := fonts.TextWidth("Bold text", 1)
+	if bold <= regular {
+		t.Errorf("The bold text should be wider. Regular: '%f', bold: '%f'.", regular, bold)
+	}
+	fonts.SetStyle(STYLE_ITALIC)
+	if g := fonts.glyph('a'); g.font != fonts.stacks[STYLE_ITALIC][0] {
+		t.Error("The glyph should be rendered with the italic font.")
+	}
+	fonts.SetStyle(STYLE_REGULAR)
+	if width := fonts.TextWidth("Bold text", 1); width != regular {
+		t.Errorf("Invalid regular width. Instead of '%f', we have '%f'.", regular, width)
+	}
+}
+func TestCharsetSDF(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("LoadSDFCharset should have panicked with 0 spread.")
+			}
+		}()
+		LoadSDFCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, 0, wrapperMock)
+	}()
+	bitmap, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	fonts, err := LoadSDFCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, DEFAULT_SDF_SPREAD, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	if bitmap.IsSDF() || !fonts.IsSDF() {
+		t.Error("Invalid sdf flag.")
+	}
+	if fonts.GetSDFSpread() != DEFAULT_SDF_SPREAD {
+		t.Errorf("Invalid spread. Instead of '%d', we have '%d'.", DEFAULT_SDF_SPREAD, fonts.GetSDFSpread())
+	}
+	g := fonts.glyph('a')
+	if g.padding != DEFAULT_SDF_SPREAD {
+		t.Errorf("Invalid padding. Instead of '%d', we have '%d'.", DEFAULT_SDF_SPREAD, g.padding)
+	}
+	w, h := g.imageSize()
+	if w != g.Width+2*DEFAULT_SDF_SPREAD || h != g.Height+2*DEFAULT_SDF_SPREAD {
+		t.Errorf("Invalid image size '%dx%d' for the '%dx%d' glyph.", w, h, g.Width, g.Height)
+	}
+	// the padding doesn't change the layout of the text.
+	if bitmap.TextWidth("Hello world", 1) != fonts.TextWidth("Hello world", 1) {
+		t.Errorf("Invalid text width. Instead of '%f', we have '%f'.", bitmap.TextWidth("Hello world", 1), fonts.TextWidth("Hello world", 1))
+	}
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The gap between the glyphs of the atlas in pixels. It prevents the
+	// bleeding of the neighbour glyphs due to the linear filtering.
+	glyphAtlasPadding = 1
+	// The min and the max size of the atlas image in pixels.
+	glyphAtlasMinSize = 64
+	glyphAtlasMaxSize = 4096
+)
+
+var (
+	ErrorGlyphAtlasFull = errors.New("The glyph doesn't fit to the glyph atlas")
+)
+
+// glyphAtlas is the image of the glyphs of a charset. The glyphs are placed to shelves
+// (rows with the height of their highest glyph). If the glyph doesn't fit, the atlas is
+// growing, its size is always power of two. The background of the atlas is black.
+type glyphAtlas struct {
+	image *image.RGBA
+	// the position of the next glyph and the height of the current shelf.
+	x, y        int
+	shelfHeight int
+}
+
+// newGlyphAtlas returns an empty atlas with the given size.
+func newGlyphAtlas(width, height int) *glyphAtlas {
+	a := &glyphAtlas{
+		image: image.NewRGBA(image.Rect(0, 0, width, height)),
+		x:     glyphAtlasPadding,
+		y:     glyphAtlasPadding,
+	}
+	draw.Draw(a.image, a.image.Bounds(), image.Black, image.ZP, draw.Src)
+	return a
+}
+
+// packGlyphs returns an atlas with the given glyphs. The glyphs are inserted in the order of
+// their heights. The width of the atlas is the smallest power of two, that makes the atlas
+// close to a square. The glyphs that don't fit to the max size atlas are removed from the map.
+func packGlyphs(glyphs map[rune]*Glyph, images map[rune]*image.RGBA) *glyphAtlas {
+	runes := make([]rune, 0, len(glyphs))
+	area := 0
+	for r, g := range glyphs {
+		runes = append(runes, r)
+		w, h := g.imageSize()
+		area += (w + glyphAtlasPadding) * (h + glyphAtlasPadding)
+	}
+	sort.Slice(runes, func(i, j int) bool {
+		if glyphs[runes[i]].Height != glyphs[runes[j]].Height {
+			return glyphs[runes[i]].Height > glyphs[runes[j]].Height
+		}
+		return runes[i] < runes[j]
+	})
+	width := glyphAtlasMinSize
+	for width*width < area && width < glyphAtlasMaxSize {
+		width *= 2
+	}
+	a := newGlyphAtlas(width, glyphAtlasMinSize)
+	for _, r := range runes {
+		if _, err := a.insert(glyphs[r], images[r]); err != nil {
+			delete(glyphs, r)
+		}
+	}
+	return a
+}
+
+// size returns the size of the atlas image.
+func (a *glyphAtlas) size() (int, int) {
+	s := a.image.Bounds().Size()
+	return s.X, s.Y
+}
+
+// insert draws the glyph image to the atlas and sets the pixel position of the glyph.
+// It returns true if the atlas has been grown, so that the texture coordinates of the
+// glyphs have to be updated. If the glyph doesn't fit to the max size atlas, it returns
+// ErrorGlyphAtlasFull and the atlas is not changed.
+func (a *glyphAtlas) insert(g *Glyph, img *image.RGBA) (bool, error) {
+	gWidth, gHeight := g.imageSize()
+	width, height := a.size()
+	newWidth, newHeight := width, height
+	for gWidth+2*glyphAtlasPadding > newWidth && newWidth < glyphAtlasMaxSize {
+		newWidth *= 2
+	}
+	x, y, shelfHeight := a.x, a.y, a.shelfHeight
+	if x+gWidth+glyphAtlasPadding > newWidth {
+		x = glyphAtlasPadding
+		y += shelfHeight + glyphAtlasPadding
+		shelfHeight = 0
+	}
+	for y+gHeight+glyphAtlasPadding > newHeight && newHeight < glyphAtlasMaxSize {
+		newHeight *= 2
+	}
+	if x+gWidth+glyphAtlasPadding > newWidth || y+gHeight+glyphAtlasPadding > newHeight {
+		return false, ErrorGlyphAtlasFull
+	}
+	a.x, a.y, a.shelfHeight = x, y, shelfHeight
+	grown := newWidth != width || newHeight != height
+	if grown {
+		a.resize(newWidth, newHeight)
+	}
+	g.atlasX, g.atlasY = a.x, a.y
+	draw.Draw(a.image, image.Rect(a.x, a.y, a.x+gWidth, a.y+gHeight), img, image.ZP, draw.Src)
+	a.x += gWidth + glyphAtlasPadding
+	if gHeight > a.shelfHeight {
+		a.shelfHeight = gHeight
+	}
+	return grown, nil
+}
+
+// resize copies the atlas image to a new image with the given size.
+func (a *glyphAtlas) resize(width, height int) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.Black, image.ZP, draw.Src)
+	draw.Draw(img, a.image.Bounds(), a.image, image.ZP, draw.Src)
+	a.image = img
+}
+
+// region returns the region of the glyph in texture coordinates.
+func (a *glyphAtlas) region(g *Glyph) (mgl32.Vec2, mgl32.Vec2) {
+	width, height := a.size()
+	gWidth, gHeight := g.imageSize()
+	return mgl32.Vec2{float32(g.atlasX) / float32(width), float32(g.atlasY) / float32(height)},
+		mgl32.Vec2{float32(g.atlasX+gWidth) / float32(width), float32(g.atlasY+gHeight) / float32(height)}
+}
@@ -0,0 +1,215 @@
+package model
+
+import (
+	"math"
+
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+
+	"github.com/akosgarai/coldet"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The speed of the waves in grid cells per ms.
+	defaultLiquidWaveSpeed = float32(0.02)
+	// The remaining ratio of the wave amplitude after a second.
+	defaultLiquidDamping = float32(0.3)
+	// The depth of the ripples, that are made by the touching spheres.
+	defaultLiquidRippleStrength = float32(0.05)
+	// The max length of a simulation step in ms.
+	waterSimulationMaxStep = float64(16)
+	// The max number of the simulation steps in an update, so that a long frame doesn't freeze the app.
+	waterSimulationMaxSteps = 10
+)
+
+// waterSimulation is a heightfield water simulation. The heights are the displacements of the surface
+// from the water level in local units, indexed with [l][w] like the liquid vertices.
+type waterSimulation struct {
+	height    [][]float32
+	velocity  [][]float32
+	waveSpeed float32
+	damping   float32
+	// the not simulated time from the previous updates.
+	accumulator float64
+	// the flat height values for the upload.
+	data []float32
+	// the float texture of the heights, that is used by the liquid vertex shader.
+	surfaceMap *texture.Texture
+}
+
+// newWaterSimulation returns a calm simulation for the given grid size.
+func newWaterSimulation(width, length int, waveSpeed, damping float32) *waterSimulation {
+	s := &waterSimulation{
+		height:    make([][]float32, length+1),
+		velocity:  make([][]float32, length+1),
+		waveSpeed: waveSpeed,
+		damping:   damping,
+		data:      make([]float32, (width+1)*(length+1)),
+	}
+	for l := 0; l <= length; l++ {
+		s.height[l] = make([]float32, width+1)
+		s.velocity[l] = make([]float32, width+1)
+	}
+	return s
+}
+
+// step moves the simulation forward with dt ms. The waves are reflected on the edges.
+func (s *waterSimulation) step(dt float32) {
+	length, width := len(s.height)-1, len(s.height[0])-1
+	c2 := s.waveSpeed * s.waveSpeed
+	damping := float32(math.Pow(float64(s.damping), float64(dt)/1000))
+	for l := 0; l <= length; l++ {
+		for w := 0; w <= width; w++ {
+			h := s.height[l][w]
+			laplace := s.height[l][max(0, w-1)] + s.height[l][min(width, w+1)] + s.height[max(0, l-1)][w] + s.height[min(length, l+1)][w] - 4*h
+			s.velocity[l][w] = (s.velocity[l][w] + c2*laplace*dt) * damping
+		}
+	}
+	for l := 0; l <= length; l++ {
+		for w := 0; w <= width; w++ {
+			s.height[l][w] += s.velocity[l][w] * dt
+		}
+	}
+}
+
+// update runs the fixed length steps for the given time. The step is short enough for
+// the stable simulation with the wave speed.
+func (s *waterSimulation) update(dt float64) {
+	stepTime := waterSimulationMaxStep
+	if s.waveSpeed > 0 {
+		stepTime = math.Min(stepTime, 0.5/float64(s.waveSpeed))
+	}
+	s.accumulator += dt
+	steps := 0
+	for s.accumulator >= stepTime && steps < waterSimulationMaxSteps {
+		s.step(float32(stepTime))
+		s.accumulator -= stepTime
+		steps++
+	}
+	if steps == waterSimulationMaxSteps {
+		s.accumulator = 0
+	}
+}
+
+// upload copies the heights to the surface map texture.
+func (s *waterSimulation) upload() {
+	width := len(s.height[0])
+	for l := range s.height {
+		copy(s.data[l*width:], s.height[l])
+	}
+	if s.surfaceMap != nil {
+		s.surfaceMap.SetFloatData(width, len(s.height), s.data)
+	}
+}
+
+// liquidMesh returns the textured mesh of the liquid.
+func (l *Liquid) liquidMesh() *mesh.TexturedMesh {
+	return l.meshes[0].(*mesh.TexturedMesh)
+}
+
+// worldToGrid returns the grid coordinates of the given world position.
+func (l *Liquid) worldToGrid(pos mgl32.Vec3) (float32, float32) {
+	lMesh := l.GetLiquid()
+	scaleTr := lMesh.ScaleTransformation()
+	local := pos.Sub(lMesh.GetPosition())
+	return local.X()/scaleTr[0] + float32(l.width)/2, local.Z()/scaleTr[10] + float32(l.length)/2
+}
+
+// IsSimulated returns true if the liquid has heightfield simulation.
+func (l *Liquid) IsSimulated() bool {
+	return l.simulation != nil
+}
+
+// SurfaceHeightAtPos returns the world space height of the water surface at the given position,
+// and nil. The height contains the simulated ripples, but it doesn't contain the shader based waves.
+// In case of the position is not under or above the surface, it returns -1 and error.
+func (l *Liquid) SurfaceHeightAtPos(pos mgl32.Vec3) (float32, error) {
+	gx, gz := l.worldToGrid(pos)
+	if gx < 0 || gz < 0 || gx > float32(l.width) || gz > float32(l.length) {
+		return -1, ErrorNotAboveTheSurface
+	}
+	displacement := float32(0)
+	if l.simulation != nil {
+		w1, l1 := min(int(gx), l.width-1), min(int(gz), l.length-1)
+		wX, wZ := gx-float32(w1), gz-float32(l1)
+		h := l.simulation.height
+		displacement = (h[l1][w1]*(1-wX)+h[l1][w1+1]*wX)*(1-wZ) + (h[l1+1][w1]*(1-wX)+h[l1+1][w1+1]*wX)*wZ
+	}
+	lMesh := l.GetLiquid()
+	scaleY := lMesh.ScaleTransformation()[5]
+	return lMesh.GetPosition().Y() + scaleY*(l.uniformFloat["waterLevel"]+displacement), nil
+}
+
+// AddRipple pushes down the simulated surface around the given world position. The radius is in
+// world units, the strength is the depth of the dent in the center in world units. The effect
+// fades with cosine curve. It does nothing without simulation.
+func (l *Liquid) AddRipple(pos mgl32.Vec3, radius, strength float32) {
+	if l.simulation == nil || radius <= 0 {
+		return
+	}
+	gx, gz := l.worldToGrid(pos)
+	scaleTr := l.GetLiquid().ScaleTransformation()
+	scaleX, scaleY, scaleZ := scaleTr[0], scaleTr[5], scaleTr[10]
+	minW := max(0, int(math.Floor(float64(gx-radius/scaleX))))
+	maxW := min(l.width, int(math.Ceil(float64(gx+radius/scaleX))))
+	minL := max(0, int(math.Floor(float64(gz-radius/scaleZ))))
+	maxL := min(l.length, int(math.Ceil(float64(gz+radius/scaleZ))))
+	for z := minL; z <= maxL; z++ {
+		for w := minW; w <= maxW; w++ {
+			dx, dz := (float32(w)-gx)*scaleX, (float32(z)-gz)*scaleZ
+			distance := float32(math.Sqrt(float64(dx*dx + dz*dz)))
+			if distance >= radius {
+				continue
+			}
+			weight := 0.5 * (1 + float32(math.Cos(math.Pi*float64(distance/radius))))
+			l.simulation.height[z][w] -= strength / scaleY * weight
+		}
+	}
+}
+
+// SubmergedVolume returns the volume of the sphere under the water surface. The
+// surface height is sampled under the center of the sphere.
+func (l *Liquid) SubmergedVolume(boundingSphere *coldet.Sphere) float32 {
+	surface, err := l.SurfaceHeightAtPos(mgl32.Vec3{boundingSphere.X(), boundingSphere.Y(), boundingSphere.Z()})
+	if err != nil {
+		return 0
+	}
+	r := boundingSphere.Radius()
+	// the height of the submerged spherical cap.
+	h := surface - (boundingSphere.Y() - r)
+	if h <= 0 {
+		return 0
+	}
+	if h >= 2*r {
+		return 4.0 / 3.0 * math.Pi * r * r * r
+	}
+	return math.Pi * h * h * (3*r - h) / 3
+}
+
+// Buoyancy returns the buoyant force of the liquid on the sphere. It points upwards, its
+// length is the weight of the displaced liquid: fluidDensity * gravity * submerged volume.
+func (l *Liquid) Buoyancy(boundingSphere *coldet.Sphere, fluidDensity, gravity float32) mgl32.Vec3 {
+	return mgl32.Vec3{0, fluidDensity * gravity * l.SubmergedVolume(boundingSphere), 0}
+}
+
+// GetReflectionTarget returns the reflection render target of the liquid. It is nil if the
+// render targets are not set up.
+func (l *Liquid) GetReflectionTarget() *texture.RenderTarget {
+	return l.reflection
+}
+
+// GetRefractionTarget returns the refraction render target of the liquid. It is nil if the
+// render targets are not set up.
+func (l *Liquid) GetRefractionTarget() *texture.RenderTarget {
+	return l.refraction
+}
+
+// ReflectionMatrix returns the transformation that mirrors the world to the plane of the
+// water level. The scene that is drawn with the view matrix multiplied with this matrix
+// is the reflection of the scene.
+func (l *Liquid) ReflectionMatrix() mgl32.Mat4 {
+	lMesh := l.GetLiquid()
+	level := lMesh.GetPosition().Y() + lMesh.ScaleTransformation()[5]*l.uniformFloat["waterLevel"]
+	return mgl32.Translate3D(0, level, 0).Mul4(mgl32.Scale3D(1, -1, 1)).Mul4(mgl32.Translate3D(0, -level, 0))
+}
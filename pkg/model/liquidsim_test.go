@@ -0,0 +1,171 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/testhelper"
+
+	"github.com/akosgarai/coldet"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func simulatedTestLiquid(simulation bool) *Liquid {
+	tb := NewTerrainBuilder()
+	tb.SetWidth(20)
+	tb.SetLength(20)
+	tb.SetIterations(0)
+	tb.SetLiquidWaterLevel(1)
+	tb.SetLiquidSimulation(simulation)
+	tb.SetGlWrapper(wrapperMock)
+	_, l := tb.BuildWithLiquid()
+	return l
+}
+func maxAbsHeight(s *waterSimulation, minL, maxL, minW, maxW int) float32 {
+	result := float32(0)
+	for l := minL; l <= maxL; l++ {
+		for w := minW; w <= maxW; w++ {
+			result = float32(math.Max(float64(result), math.Abs(float64(s.height[l][w]))))
+		}
+	}
+	return result
+}
+func TestLiquidWithoutSimulation(t *testing.T) {
+	l := simulatedTestLiquid(false)
+	if l.IsSimulated() {
+		t.Error("The liquid shouldn't be simulated.")
+	}
+	l.AddRipple(mgl32.Vec3{0, 0, 0}, 2, 1)
+	l.Update(100)
+	if l.CollideTestWithSphere(coldet.NewBoundingSphere([3]float32{0, 2, 0}, 1)) {
+		t.Error("The liquid shouldn't collide.")
+	}
+	// the mesh is moved up with the water level, the shader adds the water level again.
+	if h, err := l.SurfaceHeightAtPos(mgl32.Vec3{3, 0, -2}); err != nil || h != 2 {
+		t.Errorf("Invalid surface height. Instead of '2.0', we have '%f', '%v'.", h, err)
+	}
+	if _, err := l.SurfaceHeightAtPos(mgl32.Vec3{30, 0, 0}); err != ErrorNotAboveTheSurface {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorNotAboveTheSurface, err)
+	}
+	if _, ok := l.uniformFloat["useSurfaceMap"]; ok {
+		t.Error("The surface map shouldn't be used.")
+	}
+}
+func TestLiquidSimulationRipple(t *testing.T) {
+	l := simulatedTestLiquid(true)
+	if !l.IsSimulated() {
+		t.Fatal("The liquid should be simulated.")
+	}
+	if l.uniformFloat["useSurfaceMap"] != 1 {
+		t.Error("The surface map should be used.")
+	}
+	textures := l.liquidMesh().Textures
+	if len(textures) != 1 || textures[0].UniformName != "surfaceMap" {
+		t.Errorf("The surface map texture is missing. '%v'.", textures)
+	}
+	l.AddRipple(mgl32.Vec3{0, 0, 0}, 2, 0.5)
+	if l.simulation.height[10][10] != -0.5 {
+		t.Errorf("Invalid ripple center height. Instead of '-0.5', we have '%f'.", l.simulation.height[10][10])
+	}
+	if l.simulation.height[10][12] != 0 || l.simulation.height[0][0] != 0 {
+		t.Error("The ripple shouldn't modify the points outside of the radius.")
+	}
+	if h, _ := l.SurfaceHeightAtPos(mgl32.Vec3{0, 0, 0}); h != 1.5 {
+		t.Errorf("Invalid surface height. Instead of '1.5', we have '%f'.", h)
+	}
+	if maxAbsHeight(l.simulation, 10, 10, 16, 20) != 0 {
+		t.Fatal("The far points should be calm before the update.")
+	}
+	l.Update(500)
+	if maxAbsHeight(l.simulation, 10, 10, 16, 20) == 0 {
+		t.Error("The waves should reach the far points.")
+	}
+	before := maxAbsHeight(l.simulation, 0, 20, 0, 20)
+	for i := 0; i < 100; i++ {
+		l.Update(100)
+	}
+	if after := maxAbsHeight(l.simulation, 0, 20, 0, 20); after >= before/10 {
+		t.Errorf("The waves should be damped. Before: '%f', after: '%f'.", before, after)
+	}
+	for l1 := range l.simulation.height {
+		for w := range l.simulation.height[l1] {
+			if math.IsNaN(float64(l.simulation.height[l1][w])) {
+				t.Fatal("The simulation should be stable.")
+			}
+		}
+	}
+}
+func TestLiquidCollideTestWithSphereRipple(t *testing.T) {
+	l := simulatedTestLiquid(true)
+	// above the surface, it doesn't touch it.
+	if l.CollideTestWithSphere(coldet.NewBoundingSphere([3]float32{0, 4, 0}, 1)) || maxAbsHeight(l.simulation, 0, 20, 0, 20) != 0 {
+		t.Error("The sphere above the surface shouldn't make ripple.")
+	}
+	if l.CollideTestWithSphere(coldet.NewBoundingSphere([3]float32{0, 2, 0}, 1)) {
+		t.Error("The liquid shouldn't block the movement.")
+	}
+	if l.simulation.height[10][10] >= 0 {
+		t.Error("The touching sphere should make ripple.")
+	}
+}
+func TestLiquidBuoyancy(t *testing.T) {
+	l := simulatedTestLiquid(false)
+	r := float32(0.5)
+	full := float32(4.0 / 3.0 * math.Pi * 0.125)
+	testData := []struct {
+		y      float32
+		volume float32
+	}{
+		{4, 0},
+		{2.5, 0},
+		{2, full / 2},
+		{1.5, full},
+		{-3, full},
+	}
+	for _, tt := range testData {
+		sphere := coldet.NewBoundingSphere([3]float32{1, tt.y, 1}, r)
+		if v := l.SubmergedVolume(sphere); !testhelper.Float32ApproxEqual(v, tt.volume, 0.0001) {
+			t.Errorf("Invalid submerged volume at '%f'. Instead of '%f', we have '%f'.", tt.y, tt.volume, v)
+		}
+	}
+	force := l.Buoyancy(coldet.NewBoundingSphere([3]float32{1, 1.5, 1}, r), 1000, 9.81)
+	if !force.ApproxEqualThreshold(mgl32.Vec3{0, 1000 * 9.81 * full, 0}, 0.01) {
+		t.Errorf("Invalid buoyancy. '%v'.", force)
+	}
+	if l.SubmergedVolume(coldet.NewBoundingSphere([3]float32{30, 0, 0}, r)) != 0 {
+		t.Error("The sphere outside of the liquid shouldn't be submerged.")
+	}
+}
+func TestLiquidRenderTargets(t *testing.T) {
+	l := simulatedTestLiquid(false)
+	if l.GetReflectionTarget() != nil || l.GetRefractionTarget() != nil {
+		t.Error("The render targets shouldn't be set.")
+	}
+	tb := NewTerrainBuilder()
+	tb.SetWidth(4)
+	tb.SetLength(4)
+	tb.SetLiquidWaterLevel(1)
+	tb.SetLiquidSimulation(true)
+	tb.SetLiquidRenderTargets(320, 240)
+	tb.SetGlWrapper(wrapperMock)
+	_, l = tb.BuildWithLiquid()
+	if l.GetReflectionTarget() == nil || l.GetRefractionTarget() == nil {
+		t.Fatal("The render targets should be set.")
+	}
+	if l.uniformFloat["useRenderTargets"] != 1 {
+		t.Error("The render targets should be used.")
+	}
+	textures := l.GetLiquid().(*mesh.TexturedMesh).Textures
+	if len(textures) != 3 || textures[1] != l.GetReflectionTarget().Texture || textures[2].UniformName != "refractionTexture" {
+		t.Error("The render target textures should be added to the liquid mesh.")
+	}
+	if len(tb.liquidTex) != 0 {
+		t.Error("The builder textures shouldn't be modified.")
+	}
+	// the surface is on the 2.0 height.
+	reflected := mgl32.TransformCoordinate(mgl32.Vec3{1, 5, 2}, l.ReflectionMatrix())
+	if !reflected.ApproxEqualThreshold(mgl32.Vec3{1, -1, 2}, 0.0001) {
+		t.Errorf("Invalid reflection. '%v'.", reflected)
+	}
+}
@@ -0,0 +1,308 @@
+package model
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/material"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/primitives/cylinder"
+	"github.com/akosgarai/playground_engine/pkg/primitives/sphere"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The level is selected by the distance between the camera and the model.
+	// The thresholds are the max distances of the levels, in ascending order.
+	LOD_METRIC_DISTANCE = iota
+	// The level is selected by the projected size of the bounding sphere. The size is
+	// the ratio of the sphere diameter and the screen height. The thresholds are the
+	// min sizes of the levels, in descending order.
+	LOD_METRIC_SCREEN_SIZE
+)
+
+// LODModel holds more mesh variants of the same object. The first level is the most detailed one.
+// Only the active level is drawn. The meshes of every level are moving, rotating together.
+type LODModel struct {
+	*BaseModel
+	thresholds []float32
+	current    int
+	camera     interfaces.Camera
+	metric     int
+	// the switch is delayed until the metric is hysteresis * threshold away from the threshold.
+	hysteresis float32
+	// the radius of the bounding sphere that is used for the screen size metric.
+	radius float32
+	// cross-fade parameters. During the fade both level is drawn with blending, the `lodFade` uniform
+	// is set to the faded out ratio of the level before the draw. The wrapper is used for the blending.
+	crossFadeTime float64
+	fadeElapsed   float64
+	previous      int
+	wrapper       interfaces.GLWrapper
+}
+
+// NewLODModel returns an empty LOD model. The default metric is the distance.
+func NewLODModel() *LODModel {
+	return &LODModel{
+		BaseModel:     New(),
+		thresholds:    []float32{},
+		current:       0,
+		metric:        LOD_METRIC_DISTANCE,
+		hysteresis:    0.0,
+		radius:        1.0,
+		crossFadeTime: 0.0,
+		previous:      -1,
+	}
+}
+
+// AddLevel adds a new, less detailed level to the model. The threshold has to follow the
+// order of the metric, otherwise it panics.
+func (m *LODModel) AddLevel(msh interfaces.Mesh, threshold float32) {
+	if n := len(m.thresholds); n > 0 {
+		if (m.metric == LOD_METRIC_DISTANCE && threshold <= m.thresholds[n-1]) || (m.metric == LOD_METRIC_SCREEN_SIZE && threshold >= m.thresholds[n-1]) {
+			panic("The LOD thresholds have to follow the order of the metric.")
+		}
+	}
+	m.thresholds = append(m.thresholds, threshold)
+	m.AddMesh(msh)
+}
+
+// Levels returns the number of the levels.
+func (m *LODModel) Levels() int {
+	return len(m.meshes)
+}
+
+// CurrentLevel returns the index of the active level.
+func (m *LODModel) CurrentLevel() int {
+	return m.current
+}
+
+// SetCamera sets the camera, that is used for the level selection.
+func (m *LODModel) SetCamera(c interfaces.Camera) {
+	m.camera = c
+}
+
+// SetMetric updates the metric of the level selection. It could be LOD_METRIC_DISTANCE or
+// LOD_METRIC_SCREEN_SIZE. It has to be set before the levels are added.
+func (m *LODModel) SetMetric(metric int) {
+	m.metric = metric
+}
+
+// SetHysteresis updates the hysteresis ratio. For example with 0.1, the level is changed
+// when the metric is 10% over or under the threshold.
+func (m *LODModel) SetHysteresis(h float32) {
+	m.hysteresis = h
+}
+
+// SetBoundingRadius updates the radius of the bounding sphere of the model, that is used for the screen size metric.
+func (m *LODModel) SetBoundingRadius(r float32) {
+	m.radius = r
+}
+
+// SetCrossFadeTime updates the length of the cross-fade (ms). With 0, the levels are switched immediately.
+// The wrapper is used for turning on the blending of the fading levels, it panics if it is missing.
+func (m *LODModel) SetCrossFadeTime(t float64, wrapper interfaces.GLWrapper) {
+	if t > 0 && wrapper == nil {
+		panic("Wrapper is missing for the cross-fade.")
+	}
+	m.crossFadeTime = t
+	m.wrapper = wrapper
+}
+
+// SetPosition updates the position of every level.
+func (m *LODModel) SetPosition(p mgl32.Vec3) {
+	for i, _ := range m.meshes {
+		m.meshes[i].SetPosition(p)
+	}
+}
+
+// center returns the world space position of the active level.
+func (m *LODModel) center() mgl32.Vec3 {
+	return mgl32.TransformCoordinate(mgl32.Vec3{0, 0, 0}, m.meshes[m.current].ModelTransformation())
+}
+
+// metricValue returns the current value of the metric.
+func (m *LODModel) metricValue() float32 {
+	distance := m.camera.GetPosition().Sub(m.center()).Len()
+	if m.metric == LOD_METRIC_DISTANCE {
+		return distance
+	}
+	if distance == 0 {
+		return float32(1 << 30)
+	}
+	// The [1][1] item of the projection matrix is the cotangent of the half vertical fov.
+	return m.radius * m.camera.GetProjectionMatrix().At(1, 1) / distance
+}
+
+// selectLevel returns the level for the given metric value. It starts from the current level,
+// and steps to the less or more detailed levels, if the value is over the threshold with the hysteresis.
+func (m *LODModel) selectLevel(value float32) int {
+	level := m.current
+	last := len(m.thresholds) - 1
+	if m.metric == LOD_METRIC_DISTANCE {
+		for level < last && value > m.thresholds[level]*(1+m.hysteresis) {
+			level++
+		}
+		for level > 0 && value < m.thresholds[level-1]*(1-m.hysteresis) {
+			level--
+		}
+		return level
+	}
+	for level < last && value < m.thresholds[level]*(1-m.hysteresis) {
+		level++
+	}
+	for level > 0 && value > m.thresholds[level-1]*(1+m.hysteresis) {
+		level--
+	}
+	return level
+}
+
+// Update function calls the Update function of every level, then it selects the active level.
+// Without camera, the active level is not changed.
+func (m *LODModel) Update(dt float64) {
+	m.BaseModel.Update(dt)
+	if m.previous >= 0 {
+		m.fadeElapsed += dt
+		if m.fadeElapsed >= m.crossFadeTime {
+			m.previous = -1
+		}
+	}
+	if m.camera == nil || len(m.meshes) == 0 {
+		return
+	}
+	level := m.selectLevel(m.metricValue())
+	if level != m.current {
+		if m.crossFadeTime > 0 {
+			m.previous = m.current
+			m.fadeElapsed = 0
+		}
+		m.current = level
+	}
+}
+
+// IsFading returns true, if the cross-fade is in progress.
+func (m *LODModel) IsFading() bool {
+	return m.previous >= 0
+}
+
+// IsTransparent returns true during the cross-fade, so that the faded levels are drawn after the non transparent models.
+func (m *LODModel) IsTransparent() bool {
+	return m.transparent || m.IsFading()
+}
+
+// Draw draws the active level. During the cross-fade the previous level is also drawn with blending
+// and without depth writes. Before the draws, the `lodFade` uniform is set to the faded out ratio
+// of the level, the lit fragment shaders multiply the alpha with 1 - lodFade. After the fading
+// draws it is set back to 0, so that the other models of the shader are drawn opaque.
+func (m *LODModel) Draw(s interfaces.Shader) {
+	if len(m.meshes) == 0 {
+		return
+	}
+	m.customUniforms(s)
+	if m.previous >= 0 {
+		t := float32(m.fadeElapsed / m.crossFadeTime)
+		m.wrapper.Enable(glwrapper.BLEND)
+		m.wrapper.BlendFunc(glwrapper.SRC_APLHA, glwrapper.ONE_MINUS_SRC_ALPHA)
+		m.wrapper.DepthMask(false)
+		s.SetUniform1f("lodFade", t)
+		m.meshes[m.previous].Draw(s)
+		s.SetUniform1f("lodFade", 1-t)
+		m.meshes[m.current].Draw(s)
+		s.SetUniform1f("lodFade", 0)
+		m.wrapper.DepthMask(true)
+		return
+	}
+	m.meshes[m.current].Draw(s)
+}
+
+// Export exports the most detailed level.
+func (m *LODModel) Export(path string) {
+	if len(m.meshes) == 0 {
+		return
+	}
+	exportModel := New()
+	exportModel.AddMesh(m.meshes[0])
+	exportModel.Export(path)
+}
+
+// NewMaterialSphereLOD returns a LOD model with material sphere levels. The precisions and
+// the distance thresholds describe the levels, they have to have the same length.
+func NewMaterialSphereLOD(precisions []int, thresholds []float32, mat *material.Material, wrapper interfaces.GLWrapper) *LODModel {
+	checkLODInputs(len(precisions), len(thresholds))
+	m := NewLODModel()
+	for i := 0; i < len(precisions); i++ {
+		v, idx, bo := sphere.New(precisions[i]).MaterialMeshInput()
+		msh := mesh.NewMaterialMesh(v, idx, mat, wrapper)
+		msh.SetBoundingObject(bo)
+		m.AddLevel(msh, thresholds[i])
+	}
+	return m
+}
+
+// NewTexturedSphereLOD returns a LOD model with textured sphere levels. The precisions and
+// the distance thresholds describe the levels, they have to have the same length.
+func NewTexturedSphereLOD(precisions []int, thresholds []float32, t texture.Textures, wrapper interfaces.GLWrapper) *LODModel {
+	checkLODInputs(len(precisions), len(thresholds))
+	m := NewLODModel()
+	for i := 0; i < len(precisions); i++ {
+		v, idx, bo := sphere.New(precisions[i]).TexturedMeshInput()
+		msh := mesh.NewTexturedMesh(v, idx, t, wrapper)
+		msh.SetBoundingObject(bo)
+		m.AddLevel(msh, thresholds[i])
+	}
+	return m
+}
+
+// NewMaterialCylinderLOD returns a LOD model with material cylinder levels. The precisions and
+// the distance thresholds describe the levels, they have to have the same length.
+func NewMaterialCylinderLOD(rad, length float32, precisions []int, thresholds []float32, mat *material.Material, wrapper interfaces.GLWrapper) *LODModel {
+	checkLODInputs(len(precisions), len(thresholds))
+	m := NewLODModel()
+	for i := 0; i < len(precisions); i++ {
+		v, idx, bo := cylinder.New(rad, precisions[i], length).MaterialMeshInput()
+		msh := mesh.NewMaterialMesh(v, idx, mat, wrapper)
+		msh.SetBoundingObject(bo)
+		m.AddLevel(msh, thresholds[i])
+	}
+	return m
+}
+
+// NewTexturedCylinderLOD returns a LOD model with textured cylinder levels. The precisions and
+// the distance thresholds describe the levels, they have to have the same length.
+func NewTexturedCylinderLOD(rad, length float32, precisions []int, thresholds []float32, t texture.Textures, wrapper interfaces.GLWrapper) *LODModel {
+	checkLODInputs(len(precisions), len(thresholds))
+	m := NewLODModel()
+	for i := 0; i < len(precisions); i++ {
+		v, idx, bo := cylinder.New(rad, precisions[i], length).TexturedMeshInput()
+		msh := mesh.NewTexturedMesh(v, idx, t, wrapper)
+		msh.SetBoundingObject(bo)
+		m.AddLevel(msh, thresholds[i])
+	}
+	return m
+}
+
+// NewSimplifiedLOD returns a LOD model from an imported (or any other) mesh. The first level
+// is the given mesh, the following levels are simplified with the given cell sizes.
+// The thresholds has to contain one more item than the cell sizes. If the mesh type can't
+// be simplified, the levels are skipped, so that the model has only the given mesh.
+func NewSimplifiedLOD(msh interfaces.Mesh, cellSizes []float32, thresholds []float32) *LODModel {
+	checkLODInputs(len(cellSizes)+1, len(thresholds))
+	m := NewLODModel()
+	m.AddLevel(msh, thresholds[0])
+	for i := 0; i < len(cellSizes); i++ {
+		simplified := mesh.Simplify(msh, cellSizes[i])
+		if simplified == msh {
+			continue
+		}
+		m.AddLevel(simplified, thresholds[i+1])
+	}
+	return m
+}
+
+// checkLODInputs panics if the number of the levels and the thresholds are different or the levels are missing.
+func checkLODInputs(levels, thresholds int) {
+	if levels == 0 || levels != thresholds {
+		panic("The number of the LOD levels and thresholds has to be the same, and not 0.")
+	}
+}
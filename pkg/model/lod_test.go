@@ -0,0 +1,250 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/camera"
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/material"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/primitives/sphere"
+	"github.com/akosgarai/playground_engine/pkg/testhelper"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func newTestLODModel(metric int, thresholds []float32) *LODModel {
+	m := NewLODModel()
+	m.SetMetric(metric)
+	for i := 0; i < len(thresholds); i++ {
+		m.AddLevel(mesh.NewPointMesh(wrapperMock), thresholds[i])
+	}
+	return m
+}
+func newTestLODCamera(position mgl32.Vec3) *camera.DefaultCamera {
+	cam := camera.NewCamera(position, mgl32.Vec3{0, 1, 0}, 0, 0)
+	// with 90 degree fov the screen size is the radius / distance.
+	cam.SetupProjection(math.Pi/2, 1, 0.1, 100)
+	return cam
+}
+func TestNewLODModel(t *testing.T) {
+	m := NewLODModel()
+	if m.Levels() != 0 {
+		t.Errorf("Invalid number of levels. Instead of '0', we have '%d'.", m.Levels())
+	}
+	if m.metric != LOD_METRIC_DISTANCE {
+		t.Errorf("Invalid metric. Instead of '%d', we have '%d'.", LOD_METRIC_DISTANCE, m.metric)
+	}
+	if m.IsFading() {
+		t.Error("The new model shouldn't be fading.")
+	}
+}
+func TestLODModelAddLevelPanic(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("AddLevel should have panicked due to the invalid threshold order.")
+			}
+		}()
+		newTestLODModel(LOD_METRIC_DISTANCE, []float32{10, 5})
+	}()
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("AddLevel should have panicked due to the invalid threshold order.")
+			}
+		}()
+		newTestLODModel(LOD_METRIC_SCREEN_SIZE, []float32{0.1, 0.5})
+	}()
+}
+func TestLODModelDistanceSelection(t *testing.T) {
+	m := newTestLODModel(LOD_METRIC_DISTANCE, []float32{10, 20, 30})
+	cam := newTestLODCamera(mgl32.Vec3{0, 0, 5})
+	m.SetCamera(cam)
+	m.Update(10)
+	if m.CurrentLevel() != 0 {
+		t.Errorf("Invalid level. Instead of '0', we have '%d'.", m.CurrentLevel())
+	}
+	cam.SetPosition(mgl32.Vec3{0, 0, 25})
+	m.Update(10)
+	if m.CurrentLevel() != 2 {
+		t.Errorf("Invalid level. Instead of '2', we have '%d'.", m.CurrentLevel())
+	}
+	cam.SetPosition(mgl32.Vec3{0, 0, 15})
+	m.Update(10)
+	if m.CurrentLevel() != 1 {
+		t.Errorf("Invalid level. Instead of '1', we have '%d'.", m.CurrentLevel())
+	}
+}
+func TestLODModelHysteresis(t *testing.T) {
+	m := newTestLODModel(LOD_METRIC_DISTANCE, []float32{10, 20})
+	m.SetHysteresis(0.1)
+	cam := newTestLODCamera(mgl32.Vec3{0, 0, 10.5})
+	m.SetCamera(cam)
+	m.Update(10)
+	if m.CurrentLevel() != 0 {
+		t.Errorf("Invalid level. Instead of '0', we have '%d'.", m.CurrentLevel())
+	}
+	cam.SetPosition(mgl32.Vec3{0, 0, 11.5})
+	m.Update(10)
+	if m.CurrentLevel() != 1 {
+		t.Errorf("Invalid level. Instead of '1', we have '%d'.", m.CurrentLevel())
+	}
+	cam.SetPosition(mgl32.Vec3{0, 0, 9.5})
+	m.Update(10)
+	if m.CurrentLevel() != 1 {
+		t.Errorf("Invalid level. Instead of '1', we have '%d'.", m.CurrentLevel())
+	}
+	cam.SetPosition(mgl32.Vec3{0, 0, 8.5})
+	m.Update(10)
+	if m.CurrentLevel() != 0 {
+		t.Errorf("Invalid level. Instead of '0', we have '%d'.", m.CurrentLevel())
+	}
+}
+func TestLODModelScreenSizeSelection(t *testing.T) {
+	m := newTestLODModel(LOD_METRIC_SCREEN_SIZE, []float32{0.5, 0.1, 0.0})
+	m.SetBoundingRadius(1)
+	cam := newTestLODCamera(mgl32.Vec3{0, 0, 1.5})
+	m.SetCamera(cam)
+	m.Update(10)
+	if m.CurrentLevel() != 0 {
+		t.Errorf("Invalid level. Instead of '0', we have '%d'.", m.CurrentLevel())
+	}
+	cam.SetPosition(mgl32.Vec3{0, 0, 5})
+	m.Update(10)
+	if m.CurrentLevel() != 1 {
+		t.Errorf("Invalid level. Instead of '1', we have '%d'.", m.CurrentLevel())
+	}
+	cam.SetPosition(mgl32.Vec3{0, 0, 50})
+	m.Update(10)
+	if m.CurrentLevel() != 2 {
+		t.Errorf("Invalid level. Instead of '2', we have '%d'.", m.CurrentLevel())
+	}
+}
+
+// lodShaderMock records the values of the lodFade uniform.
+type lodShaderMock struct {
+	testhelper.ShaderMock
+	fades []float32
+}
+
+func (s *lodShaderMock) SetUniform1f(name string, v float32) {
+	if name == "lodFade" {
+		s.fades = append(s.fades, v)
+	}
+}
+
+// lodWrapperMock records the blending and the depth mask state.
+type lodWrapperMock struct {
+	testhelper.GLWrapperMock
+	blend     bool
+	depthMask []bool
+}
+
+func (w *lodWrapperMock) Enable(c uint32) {
+	if c == glwrapper.BLEND {
+		w.blend = true
+	}
+}
+func (w *lodWrapperMock) DepthMask(flag bool) {
+	w.depthMask = append(w.depthMask, flag)
+}
+func TestLODModelCrossFade(t *testing.T) {
+	m := newTestLODModel(LOD_METRIC_DISTANCE, []float32{10, 20})
+	wrapper := &lodWrapperMock{}
+	m.SetCrossFadeTime(100, wrapper)
+	cam := newTestLODCamera(mgl32.Vec3{0, 0, 15})
+	m.SetCamera(cam)
+	m.Update(10)
+	if !m.IsFading() || !m.IsTransparent() {
+		t.Error("The model should be fading.")
+	}
+	m.Update(25)
+	s := &lodShaderMock{}
+	m.Draw(s)
+	// the previous level is faded out by 25%, the current one by 75%, then the uniform is reset.
+	expected := []float32{0.25, 0.75, 0}
+	if len(s.fades) != len(expected) {
+		t.Fatalf("Invalid lodFade uniforms. Instead of '%v', we have '%v'.", expected, s.fades)
+	}
+	for i := 0; i < len(expected); i++ {
+		if s.fades[i] != expected[i] {
+			t.Errorf("Invalid lodFade uniforms. Instead of '%v', we have '%v'.", expected, s.fades)
+		}
+	}
+	if !wrapper.blend || len(wrapper.depthMask) != 2 || wrapper.depthMask[0] || !wrapper.depthMask[1] {
+		t.Errorf("The fading levels should be blended without depth writes. Blend: '%v', depth mask: '%v'.", wrapper.blend, wrapper.depthMask)
+	}
+	m.Update(100)
+	if m.IsFading() || m.IsTransparent() {
+		t.Error("The model shouldn't be fading.")
+	}
+	s = &lodShaderMock{}
+	m.Draw(s)
+	if len(s.fades) != 0 {
+		t.Errorf("The opaque draw shouldn't set the lodFade uniform, we have '%v'.", s.fades)
+	}
+}
+func TestLODModelSetPosition(t *testing.T) {
+	m := newTestLODModel(LOD_METRIC_DISTANCE, []float32{10, 20})
+	p := mgl32.Vec3{1, 2, 3}
+	m.SetPosition(p)
+	for i := 0; i < m.Levels(); i++ {
+		msh, _ := m.GetMeshByIndex(i)
+		if msh.GetPosition() != p {
+			t.Errorf("Invalid position. Instead of '%v', we have '%v'.", p, msh.GetPosition())
+		}
+	}
+}
+func TestLODHelpers(t *testing.T) {
+	precisions := []int{20, 10, 5}
+	thresholds := []float32{10, 20, 30}
+	var tex texture.Textures
+	models := []*LODModel{
+		NewMaterialSphereLOD(precisions, thresholds, material.Jade, wrapperMock),
+		NewTexturedSphereLOD(precisions, thresholds, tex, wrapperMock),
+		NewMaterialCylinderLOD(1, 2, precisions, thresholds, material.Jade, wrapperMock),
+		NewTexturedCylinderLOD(1, 2, precisions, thresholds, tex, wrapperMock),
+	}
+	for i := 0; i < len(models); i++ {
+		if models[i].Levels() != 3 {
+			t.Errorf("Invalid number of levels. Instead of '3', we have '%d'.", models[i].Levels())
+		}
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("It should have panicked due to the different input lengths.")
+			}
+		}()
+		NewMaterialSphereLOD(precisions, []float32{10}, material.Jade, wrapperMock)
+	}()
+}
+func TestNewSimplifiedLOD(t *testing.T) {
+	v, i, _ := sphere.New(20).MaterialMeshInput()
+	msh := mesh.NewMaterialMesh(v, i, material.Jade, wrapperMock)
+	m := NewSimplifiedLOD(msh, []float32{0.2, 0.5}, []float32{10, 20, 30})
+	if m.Levels() != 3 {
+		t.Errorf("Invalid number of levels. Instead of '3', we have '%d'.", m.Levels())
+	}
+	first, _ := m.GetMeshByIndex(0)
+	if first != msh {
+		t.Error("The first level should be the original mesh.")
+	}
+	m = NewSimplifiedLOD(mesh.NewPointMesh(wrapperMock), []float32{0.2, 0.5}, []float32{10, 20, 30})
+	if m.Levels() != 1 {
+		t.Errorf("The not simplified levels should be skipped. Instead of '1', we have '%d'.", m.Levels())
+	}
+}
+func TestLODModelSetCrossFadeTimePanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("SetCrossFadeTime should have panicked due to the missing wrapper.")
+		}
+	}()
+	m := NewLODModel()
+	m.SetCrossFadeTime(0, nil)
+	m.SetCrossFadeTime(100, nil)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/akosgarai/playground_engine/pkg/heightmap"
 	"github.com/akosgarai/playground_engine/pkg/mesh"
 	"github.com/akosgarai/playground_engine/pkg/primitives/boundingobject"
 	"github.com/akosgarai/playground_engine/pkg/testhelper"
@@ -984,3 +985,26 @@ func TestTerrainBuilderSetLiquidDetailMultiplier(t *testing.T) {
 		t.Errorf("Invalid liquid detail multiplier. Instead of '%d', we have '%d'.", dm, terr.liquidDetailMultiplier)
 	}
 }
+func TestTerrainBuilderSetGenerator(t *testing.T) {
+	g := heightmap.NewDiamondSquare(0, 0.5, 0, 1)
+	terr := NewTerrainBuilder()
+	terr.SetGenerator(g)
+	if terr.generator != g {
+		t.Error("Invalid generator.")
+	}
+}
+func TestTerrainBuilderBuildWithGenerator(t *testing.T) {
+	tb := NewTerrainBuilder()
+	tb.SetWidth(4)
+	tb.SetLength(4)
+	tb.SetGlWrapper(wrapperMock)
+	tb.SetGenerator(heightmap.NewImage([][]float32{{0, 1}, {0, 1}}, 0, 4))
+	terr := tb.Build()
+	for l := 0; l <= 4; l++ {
+		for w := 0; w <= 4; w++ {
+			if terr.heightMap[l][w] != float32(w) {
+				t.Errorf("Invalid height at '%d, %d'. Instead of '%d', we have '%f'.", w, l, w, terr.heightMap[l][w])
+			}
+		}
+	}
+}
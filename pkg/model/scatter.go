@@ -0,0 +1,376 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/akosgarai/playground_engine/pkg/heightmap"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+
+	"github.com/akosgarai/coldet"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	defaultScatterMinSpacing = float32(1.0)
+	// The number of the tested candidates around an active point of the poisson disk sampling.
+	scatterCandidates = 30
+	// The points are kept this ratio of a cell inside the terrain edges, so that the
+	// HeightAtPos doesn't index out of the height map.
+	scatterEdgeTolerance = float32(0.001)
+)
+
+// scatterPrototype is a scattered model with its placement rules. The height range is
+// in world space, the slope is 0 on the flat surface, 1 on the vertical one.
+type scatterPrototype struct {
+	mesh                 *mesh.InstancedMesh
+	weight               float32
+	minHeight, maxHeight float32
+	maxSlope             float32
+	minScale, maxScale   float32
+	collisionRadius      float32
+}
+
+// ScatterInstance is a placed instance of a prototype.
+type ScatterInstance struct {
+	// the index of the prototype.
+	Prototype int
+	// the world position of the instance. It is on the surface of the terrain.
+	Position mgl32.Vec3
+	// the rotation around the Y axis in degrees.
+	Rotation float32
+	Scale    float32
+}
+
+// Transformation returns the model transformation of the instance.
+func (i ScatterInstance) Transformation() mgl32.Mat4 {
+	return mgl32.Translate3D(i.Position.X(), i.Position.Y(), i.Position.Z()).Mul4(
+		mgl32.HomogRotate3DY(mgl32.DegToRad(i.Rotation))).Mul4(
+		mgl32.Scale3D(i.Scale, i.Scale, i.Scale))
+}
+
+// Scatter is the model of the scattered objects. Its meshes are the instanced meshes of
+// the prototypes, so that every prototype is drawn with a single draw call.
+type Scatter struct {
+	*BaseModel
+	instances  []ScatterInstance
+	prototypes []scatterPrototype
+}
+
+// Instances returns the placed instances in the order of the placement.
+func (s *Scatter) Instances() []ScatterInstance {
+	return s.instances
+}
+
+// CollideTestWithSphere is the collision detection function for the instances vs sphere.
+// The instance is a sphere with the collision radius of its prototype multiplied with its
+// scale. The prototypes without collision radius don't collide.
+func (s *Scatter) CollideTestWithSphere(boundingSphere *coldet.Sphere) bool {
+	for _, instance := range s.instances {
+		radius := s.prototypes[instance.Prototype].collisionRadius * instance.Scale
+		if radius <= 0 {
+			continue
+		}
+		bs := coldet.NewBoundingSphere([3]float32{instance.Position.X(), instance.Position.Y(), instance.Position.Z()}, radius)
+		if coldet.CheckSphereVsSphere(*boundingSphere, *bs) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScatterBuilder is a helper structure for placing objects, like trees, rocks, lamps on a
+// terrain. The positions are generated with poisson disk sampling, so that the objects
+// are at least minSpacing away from each other. The same setup with the same seed gives
+// the same placement.
+type ScatterBuilder struct {
+	terrain        *Terrain
+	seed           int64
+	minSpacing     float32
+	density        heightmap.Generator
+	randomRotation bool
+	prototypes     []scatterPrototype
+}
+
+// NewScatterBuilder returns a scatter builder with default values.
+func NewScatterBuilder() *ScatterBuilder {
+	return &ScatterBuilder{
+		seed:           defaultSeed,
+		minSpacing:     defaultScatterMinSpacing,
+		randomRotation: true,
+		prototypes:     []scatterPrototype{},
+	}
+}
+
+// SetTerrain sets the terrain, that the objects are placed on.
+func (b *ScatterBuilder) SetTerrain(t *Terrain) {
+	b.terrain = t
+}
+
+// SetSeed updates the seed of the placement.
+func (b *ScatterBuilder) SetSeed(s int64) {
+	b.seed = s
+}
+
+// SetMinSpacing updates the min distance of the objects in world units.
+func (b *ScatterBuilder) SetMinSpacing(d float32) {
+	b.minSpacing = d
+}
+
+// SetDensityMap sets the density map of the placement. The map is generated on the grid of
+// the terrain, the values are the probabilities of the placement between 0 and 1. For example
+// a grayscale image could be used with the heightmap.LoadImage(path, 0, 1) generator.
+// Without density map, every valid position is used.
+func (b *ScatterBuilder) SetDensityMap(g heightmap.Generator) {
+	b.density = g
+}
+
+// SetRandomRotation updates the random rotation flag. If it is true, the instances are
+// rotated around the Y axis with random angle. By default it is true.
+func (b *ScatterBuilder) SetRandomRotation(v bool) {
+	b.randomRotation = v
+}
+
+// AddPrototype registers a new prototype and returns its index. The weight is the relative
+// probability of the prototype, where more prototypes are valid. The new prototype could be
+// placed at every height and slope with the original scale.
+func (b *ScatterBuilder) AddPrototype(msh *mesh.InstancedMesh, weight float32) int {
+	b.prototypes = append(b.prototypes, scatterPrototype{
+		mesh:      msh,
+		weight:    weight,
+		minHeight: -unlimitedLayerHeight,
+		maxHeight: unlimitedLayerHeight,
+		maxSlope:  1,
+		minScale:  1,
+		maxScale:  1,
+	})
+	return len(b.prototypes) - 1
+}
+
+// prototype returns the prototype with the given index. It panics if the index is invalid.
+func (b *ScatterBuilder) prototype(index int) *scatterPrototype {
+	if index < 0 || index >= len(b.prototypes) {
+		panic(fmt.Sprintf("Invalid scatter prototype index '%d'.", index))
+	}
+	return &b.prototypes[index]
+}
+
+// SetPrototypeHeightRange updates the world space height range of the given prototype.
+func (b *ScatterBuilder) SetPrototypeHeightRange(index int, minH, maxH float32) {
+	p := b.prototype(index)
+	p.minHeight = minH
+	p.maxHeight = maxH
+}
+
+// SetPrototypeMaxSlope updates the max slope of the given prototype. The slope is 0 on the
+// flat surface, 1 on the vertical one.
+func (b *ScatterBuilder) SetPrototypeMaxSlope(index int, s float32) {
+	b.prototype(index).maxSlope = s
+}
+
+// SetPrototypeScaleRange updates the range of the random uniform scale of the given prototype.
+func (b *ScatterBuilder) SetPrototypeScaleRange(index int, minScale, maxScale float32) {
+	p := b.prototype(index)
+	p.minScale = minScale
+	p.maxScale = maxScale
+}
+
+// SetPrototypeCollisionRadius updates the collision radius of the given prototype. It is
+// multiplied with the scale of the instances. With 0, the instances don't collide.
+func (b *ScatterBuilder) SetPrototypeCollisionRadius(index int, r float32) {
+	b.prototype(index).collisionRadius = r
+}
+
+// Build generates the instances and returns the Scatter model. It panics if the terrain
+// or the prototypes are missing, or the min spacing is not positive.
+func (b *ScatterBuilder) Build() *Scatter {
+	if b.terrain == nil {
+		panic("The terrain of the scattering is missing.")
+	}
+	if len(b.prototypes) == 0 {
+		panic("The scattering needs at least one prototype.")
+	}
+	if b.minSpacing <= 0 {
+		panic("The min spacing of the scattering has to be positive.")
+	}
+	rnd := rand.New(rand.NewSource(b.seed))
+	s := &Scatter{
+		BaseModel:  New(),
+		instances:  []ScatterInstance{},
+		prototypes: append([]scatterPrototype{}, b.prototypes...),
+	}
+	surface := newScatterSurface(b.terrain, b.density)
+	for _, point := range poissonDisk(surface.minX, surface.minZ, surface.maxX, surface.maxZ, b.minSpacing, rnd) {
+		if b.density != nil && rnd.Float32() >= surface.density(point.X(), point.Y()) {
+			continue
+		}
+		height := surface.height(point.X(), point.Y())
+		prototype := b.selectPrototype(height, surface.slope(point.X(), point.Y()), rnd)
+		if prototype < 0 {
+			continue
+		}
+		instance := ScatterInstance{
+			Prototype: prototype,
+			Position:  surface.position.Add(mgl32.Vec3{point.X(), height - surface.position.Y(), point.Y()}),
+			Scale:     b.prototypes[prototype].minScale + rnd.Float32()*(b.prototypes[prototype].maxScale-b.prototypes[prototype].minScale),
+		}
+		if b.randomRotation {
+			instance.Rotation = rnd.Float32() * 360
+		}
+		s.instances = append(s.instances, instance)
+	}
+	transformations := make([][]mgl32.Mat4, len(b.prototypes))
+	for _, instance := range s.instances {
+		transformations[instance.Prototype] = append(transformations[instance.Prototype], instance.Transformation())
+	}
+	for i, p := range b.prototypes {
+		p.mesh.SetInstances(transformations[i])
+		s.AddMesh(p.mesh)
+	}
+	return s
+}
+
+// selectPrototype returns the index of a random prototype from the valid ones, by their weights.
+// It returns -1 if none of them is valid.
+func (b *ScatterBuilder) selectPrototype(height, slope float32, rnd *rand.Rand) int {
+	sum := float32(0)
+	valid := []int{}
+	for i, p := range b.prototypes {
+		if p.weight > 0 && height >= p.minHeight && height <= p.maxHeight && slope <= p.maxSlope {
+			valid = append(valid, i)
+			sum += p.weight
+		}
+	}
+	if len(valid) == 0 {
+		return -1
+	}
+	r := rnd.Float32() * sum
+	for _, i := range valid {
+		r -= b.prototypes[i].weight
+		if r < 0 {
+			return i
+		}
+	}
+	return valid[len(valid)-1]
+}
+
+// scatterSurface samples the terrain in the local coordinates of the terrain mesh,
+// that are scaled, but not moved.
+type scatterSurface struct {
+	terrain                *Terrain
+	position               mgl32.Vec3
+	scale                  mgl32.Vec3
+	minX, minZ, maxX, maxZ float32
+	densityMap             [][]float32
+}
+
+func newScatterSurface(t *Terrain, density heightmap.Generator) *scatterSurface {
+	tMesh := t.GetTerrain()
+	scaleTr := tMesh.ScaleTransformation()
+	s := &scatterSurface{
+		terrain:  t,
+		position: tMesh.GetPosition(),
+		scale:    mgl32.Vec3{scaleTr[0], scaleTr[5], scaleTr[10]},
+	}
+	s.minX, s.maxX = -float32(t.width)/2*s.scale.X(), (float32(t.width)/2-scatterEdgeTolerance)*s.scale.X()
+	s.minZ, s.maxZ = -float32(t.length)/2*s.scale.Z(), (float32(t.length)/2-scatterEdgeTolerance)*s.scale.Z()
+	if density != nil {
+		s.densityMap = heightmap.New(t.width, t.length)
+		density.Generate(s.densityMap)
+	}
+	return s
+}
+
+// clamp returns the given position moved inside the terrain.
+func (s *scatterSurface) clamp(x, z float32) (float32, float32) {
+	x = float32(math.Max(float64(s.minX), math.Min(float64(s.maxX), float64(x))))
+	z = float32(math.Max(float64(s.minZ), math.Min(float64(s.maxZ), float64(z))))
+	return x, z
+}
+
+// height returns the world space height of the terrain. The position is clamped to the terrain.
+func (s *scatterSurface) height(x, z float32) float32 {
+	x, z = s.clamp(x, z)
+	h, _ := s.terrain.HeightAtPos(mgl32.Vec3{x, 0, z})
+	return s.position.Y() + s.scale.Y()*h
+}
+
+// slope returns the slope of the surface, the same way as the terrain shader calculates it
+// from the normal vector: 1 - |normal.y|. The gradient is calculated with central differences,
+// that are one-sided on the edges.
+func (s *scatterSurface) slope(x, z float32) float32 {
+	x1, z1 := s.clamp(x-s.scale.X(), z-s.scale.Z())
+	x2, z2 := s.clamp(x+s.scale.X(), z+s.scale.Z())
+	gradX := (s.height(x2, z) - s.height(x1, z)) / (x2 - x1)
+	gradZ := (s.height(x, z2) - s.height(x, z1)) / (z2 - z1)
+	normal := mgl32.Vec3{-gradX, 1, -gradZ}.Normalize()
+	return 1 - float32(math.Abs(float64(normal.Y())))
+}
+
+// density returns the bilinear interpolated value of the density map, clamped to [0, 1].
+func (s *scatterSurface) density(x, z float32) float32 {
+	gx := x/s.scale.X() + float32(s.terrain.width)/2
+	gz := z/s.scale.Z() + float32(s.terrain.length)/2
+	w1 := min(max(0, int(gx)), s.terrain.width-1)
+	l1 := min(max(0, int(gz)), s.terrain.length-1)
+	wX, wZ := gx-float32(w1), gz-float32(l1)
+	d := s.densityMap
+	value := (d[l1][w1]*(1-wX)+d[l1][w1+1]*wX)*(1-wZ) + (d[l1+1][w1]*(1-wX)+d[l1+1][w1+1]*wX)*wZ
+	return float32(math.Max(0, math.Min(1, float64(value))))
+}
+
+// poissonDisk returns points in the given rectangle, that are at least r away from each
+// other (Bridson's algorithm). The points are returned in the order of the generation.
+func poissonDisk(minX, minZ, maxX, maxZ, r float32, rnd *rand.Rand) []mgl32.Vec2 {
+	cell := r / float32(math.Sqrt2)
+	columns := int((maxX-minX)/cell) + 1
+	rows := int((maxZ-minZ)/cell) + 1
+	grid := make([]int, columns*rows)
+	for i := range grid {
+		grid[i] = -1
+	}
+	cellOf := func(p mgl32.Vec2) (int, int) {
+		return int((p.X() - minX) / cell), int((p.Y() - minZ) / cell)
+	}
+	points := []mgl32.Vec2{}
+	add := func(p mgl32.Vec2) {
+		c, r := cellOf(p)
+		grid[r*columns+c] = len(points)
+		points = append(points, p)
+	}
+	farEnough := func(p mgl32.Vec2) bool {
+		c, row := cellOf(p)
+		for z := max(0, row-2); z <= min(rows-1, row+2); z++ {
+			for x := max(0, c-2); x <= min(columns-1, c+2); x++ {
+				if i := grid[z*columns+x]; i >= 0 && points[i].Sub(p).Len() < r {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	add(mgl32.Vec2{minX + rnd.Float32()*(maxX-minX), minZ + rnd.Float32()*(maxZ-minZ)})
+	active := []int{0}
+	for len(active) > 0 {
+		a := rnd.Intn(len(active))
+		center := points[active[a]]
+		found := false
+		for k := 0; k < scatterCandidates; k++ {
+			angle := rnd.Float64() * 2 * math.Pi
+			distance := r * (1 + rnd.Float32())
+			p := center.Add(mgl32.Vec2{distance * float32(math.Cos(angle)), distance * float32(math.Sin(angle))})
+			if p.X() < minX || p.X() > maxX || p.Y() < minZ || p.Y() > maxZ || !farEnough(p) {
+				continue
+			}
+			add(p)
+			active = append(active, len(points)-1)
+			found = true
+			break
+		}
+		if !found {
+			active = append(active[:a], active[a+1:]...)
+		}
+	}
+	return points
+}
@@ -0,0 +1,183 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/heightmap"
+	"github.com/akosgarai/playground_engine/pkg/material"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/primitives/cuboid"
+	"github.com/akosgarai/playground_engine/pkg/testhelper"
+
+	"github.com/akosgarai/coldet"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func scatterTestTerrain() *Terrain {
+	tb := NewTerrainBuilder()
+	tb.SetWidth(20)
+	tb.SetLength(20)
+	tb.SetIterations(0)
+	tb.SetPosition(mgl32.Vec3{5, 1, 0})
+	tb.SetGlWrapper(wrapperMock)
+	terr := tb.Build()
+	// the height grows in the X direction.
+	for l := range terr.heightMap {
+		for w := range terr.heightMap[l] {
+			terr.heightMap[l][w] = float32(w) / 4
+		}
+	}
+	return terr
+}
+func scatterTestPrototype() *mesh.InstancedMesh {
+	v, i, _ := cuboid.NewCube().MaterialMeshInput()
+	return mesh.NewInstancedMaterialMesh(v, i, material.Jade, wrapperMock)
+}
+func scatterTestBuilder() *ScatterBuilder {
+	b := NewScatterBuilder()
+	b.SetTerrain(scatterTestTerrain())
+	b.SetMinSpacing(1.5)
+	b.SetSeed(3)
+	b.AddPrototype(scatterTestPrototype(), 1)
+	return b
+}
+func TestScatterBuilderPanics(t *testing.T) {
+	testData := []struct {
+		name  string
+		setup func(*ScatterBuilder)
+	}{
+		{"missing terrain", func(b *ScatterBuilder) { b.SetTerrain(nil) }},
+		{"missing prototype", func(b *ScatterBuilder) { b.prototypes = nil }},
+		{"invalid spacing", func(b *ScatterBuilder) { b.SetMinSpacing(0) }},
+		{"invalid prototype index", func(b *ScatterBuilder) { b.SetPrototypeMaxSlope(1, 0.5) }},
+	}
+	for _, tt := range testData {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("It should have panicked due to the %s.", tt.name)
+				}
+			}()
+			b := scatterTestBuilder()
+			tt.setup(b)
+			b.Build()
+		}()
+	}
+}
+func TestScatterBuildSpacingAndSurface(t *testing.T) {
+	b := scatterTestBuilder()
+	s := b.Build()
+	instances := s.Instances()
+	// the 20x20 area with 1.5 spacing has place for lots of points.
+	if len(instances) < 50 {
+		t.Fatalf("Too few instances: '%d'.", len(instances))
+	}
+	for i := range instances {
+		for j := i + 1; j < len(instances); j++ {
+			a, c := instances[i].Position, instances[j].Position
+			if (mgl32.Vec2{a.X() - c.X(), a.Z() - c.Z()}).Len() < 1.5 {
+				t.Fatalf("The instances '%d', '%d' are too close.", i, j)
+			}
+		}
+		p := instances[i].Position
+		if p.X() < -5 || p.X() > 15 || p.Z() < -10 || p.Z() > 10 {
+			t.Errorf("The instance '%v' is outside of the terrain.", p)
+		}
+		h, err := b.terrain.HeightAtPos(p.Sub(mgl32.Vec3{5, 1, 0}))
+		if err != nil || !testhelper.Float32ApproxEqual(p.Y(), h+1, 0.0001) {
+			t.Errorf("The instance '%v' should be on the surface '%f'.", p, h+1)
+		}
+		if instances[i].Scale != 1 || instances[i].Rotation < 0 || instances[i].Rotation >= 360 {
+			t.Errorf("Invalid scale or rotation '%v'.", instances[i])
+		}
+	}
+	msh := s.meshes[0].(*mesh.InstancedMesh)
+	if msh.InstanceCount() != len(instances) || msh.GetInstances()[0] != instances[0].Transformation() {
+		t.Error("The instances should be uploaded to the prototype mesh.")
+	}
+}
+func TestScatterBuildDeterministic(t *testing.T) {
+	first := scatterTestBuilder().Build().Instances()
+	second := scatterTestBuilder().Build().Instances()
+	if !reflect.DeepEqual(first, second) {
+		t.Error("The same seed should give the same placement.")
+	}
+	b := scatterTestBuilder()
+	b.SetSeed(4)
+	if reflect.DeepEqual(first, b.Build().Instances()) {
+		t.Error("The different seed should give different placement.")
+	}
+}
+func TestScatterBuildRules(t *testing.T) {
+	b := scatterTestBuilder()
+	b.SetRandomRotation(false)
+	low := b.prototypes[0].mesh
+	high := scatterTestPrototype()
+	b.SetPrototypeHeightRange(0, 0, 3)
+	b.SetPrototypeScaleRange(0, 0.5, 2)
+	index := b.AddPrototype(high, 1)
+	b.SetPrototypeHeightRange(index, 3, 10)
+	s := b.Build()
+	for _, instance := range s.Instances() {
+		if instance.Rotation != 0 {
+			t.Errorf("The instance shouldn't be rotated. '%v'.", instance)
+		}
+		if instance.Prototype == 0 && (instance.Position.Y() > 3 || instance.Scale < 0.5 || instance.Scale > 2) {
+			t.Errorf("Invalid low instance '%v'.", instance)
+		}
+		if instance.Prototype == 1 && (instance.Position.Y() < 3 || instance.Scale != 1) {
+			t.Errorf("Invalid high instance '%v'.", instance)
+		}
+	}
+	if low.InstanceCount() == 0 || high.InstanceCount() == 0 || low.InstanceCount()+high.InstanceCount() != len(s.Instances()) {
+		t.Errorf("Invalid instance counts: '%d', '%d'.", low.InstanceCount(), high.InstanceCount())
+	}
+	// the slope of the terrain is 1 - 1/sqrt(1+1/16) ~ 0.03.
+	b = scatterTestBuilder()
+	b.SetPrototypeMaxSlope(0, 0.02)
+	if n := len(b.Build().Instances()); n != 0 {
+		t.Errorf("The steep surface shouldn't have instances. '%d'.", n)
+	}
+	b.SetPrototypeMaxSlope(0, 0.04)
+	if n := len(b.Build().Instances()); n == 0 {
+		t.Error("The surface should have instances.")
+	}
+}
+func TestScatterBuildDensityMap(t *testing.T) {
+	b := scatterTestBuilder()
+	full := len(b.Build().Instances())
+	// only the right half of the terrain is allowed.
+	values := [][]float32{{0, 0, 1, 1}, {0, 0, 1, 1}}
+	b.SetDensityMap(heightmap.NewImage(values, 0, 1))
+	instances := b.Build().Instances()
+	if len(instances) == 0 || len(instances) >= full {
+		t.Errorf("Invalid number of instances. Full: '%d', with density map: '%d'.", full, len(instances))
+	}
+	for _, instance := range instances {
+		// the center of the terrain is at 5.
+		if instance.Position.X() < 5-20.0/6 {
+			t.Errorf("The instance '%v' should be in the allowed part.", instance.Position)
+		}
+	}
+	b.SetDensityMap(heightmap.NewImage([][]float32{{0, 0}, {0, 0}}, 0, 1))
+	if n := len(b.Build().Instances()); n != 0 {
+		t.Errorf("The zero density shouldn't have instances. '%d'.", n)
+	}
+}
+func TestScatterCollideTestWithSphere(t *testing.T) {
+	b := scatterTestBuilder()
+	s := b.Build()
+	p := s.Instances()[0].Position
+	if s.CollideTestWithSphere(coldet.NewBoundingSphere([3]float32{p.X(), p.Y(), p.Z()}, 0.1)) {
+		t.Error("The instances without collision radius shouldn't collide.")
+	}
+	b.SetPrototypeCollisionRadius(0, 0.5)
+	s = b.Build()
+	if !s.CollideTestWithSphere(coldet.NewBoundingSphere([3]float32{p.X(), p.Y() + 0.5, p.Z()}, 0.1)) {
+		t.Error("The instance should collide.")
+	}
+	if s.CollideTestWithSphere(coldet.NewBoundingSphere([3]float32{p.X(), p.Y() + 5, p.Z()}, 0.1)) {
+		t.Error("The far sphere shouldn't collide.")
+	}
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The default distance of the signed distance fields in pixels. The distances are
+	// mapped to the [0, 1] interval in this range around the edge of the glyph.
+	DEFAULT_SDF_SPREAD = 4
+	// The value of the pixels without target pixel in the distance transform.
+	sdfInfinity = 1e20
+)
+
+// TextEffects are the parameters of the sdf font shader. The widths are in distance units,
+// where 0.5 is the spread of the charset. The shadow offset is in atlas pixels, it has to be
+// less than the spread. The zero value means plain text without effects.
+type TextEffects struct {
+	OutlineWidth   float32
+	OutlineColor   mgl32.Vec3
+	ShadowOffset   mgl32.Vec2
+	ShadowSoftness float32
+	ShadowColor    mgl32.Vec3
+	GlowWidth      float32
+	GlowColor      mgl32.Vec3
+}
+
+// setUniforms sets the effect parameters as the custom uniforms of the given model.
+func (e TextEffects) setUniforms(m *Model) {
+	m.SetUniformFloat("outlineWidth", e.OutlineWidth)
+	m.SetUniformVector("outlineColor", e.OutlineColor)
+	m.SetUniformVector("shadowOffset", mgl32.Vec3{e.ShadowOffset.X(), e.ShadowOffset.Y(), 0})
+	m.SetUniformFloat("shadowSoftness", e.ShadowSoftness)
+	m.SetUniformVector("shadowColor", e.ShadowColor)
+	m.SetUniformFloat("glowWidth", e.GlowWidth)
+	m.SetUniformVector("glowColor", e.GlowColor)
+}
+
+// signedDistanceField returns the signed distance field of the glyph image. The image is extended
+// with spread pixels on every side. The red component of the glyph image is used as coverage, the
+// pixels with higher than half coverage are inside. The distances are mapped to the [0, 1] interval,
+// the edge is 0.5, the inside is above it. The value is stored in every color component.
+func signedDistanceField(img *image.RGBA, spread int) *image.RGBA {
+	size := img.Bounds().Size()
+	width, height := size.X+2*spread, size.Y+2*spread
+	inside := make([]bool, width*height)
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			r, _, _, _ := img.At(img.Bounds().Min.X+x, img.Bounds().Min.Y+y).RGBA()
+			inside[(y+spread)*width+x+spread] = r > 0x7fff
+		}
+	}
+	// the distances of the outside pixels from the closest inside pixel and vice versa.
+	toInside := distanceTransform(inside, width, height, true)
+	toOutside := distanceTransform(inside, width, height, false)
+	result := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range inside {
+		var d float64
+		if inside[i] {
+			d = -(math.Sqrt(toOutside[i]) - 0.5)
+		} else {
+			d = math.Sqrt(toInside[i]) - 0.5
+		}
+		v := 0.5 - d/float64(2*spread)
+		if v < 0 {
+			v = 0
+		} else if v > 1 {
+			v = 1
+		}
+		c := uint8(math.Round(v * 255))
+		result.Set(i%width, i/width, color.RGBA{c, c, c, 255})
+	}
+	return result
+}
+
+// distanceTransform returns the squared euclidean distances of the pixels from the closest
+// pixel, that has the target value in the mask. The target pixels have 0 distance.
+// It is the separable algorithm of Felzenszwalb and Huttenlocher.
+func distanceTransform(mask []bool, width, height int, target bool) []float64 {
+	d := make([]float64, width*height)
+	for i := range mask {
+		if mask[i] == target {
+			d[i] = 0
+		} else {
+			d[i] = sdfInfinity
+		}
+	}
+	n := width
+	if height > n {
+		n = height
+	}
+	f := make([]float64, n)
+	out := make([]float64, n)
+	v := make([]int, n)
+	z := make([]float64, n+1)
+	// columns
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			f[y] = d[y*width+x]
+		}
+		distanceTransform1D(f[:height], out[:height], v, z)
+		for y := 0; y < height; y++ {
+			d[y*width+x] = out[y]
+		}
+	}
+	// rows
+	for y := 0; y < height; y++ {
+		copy(f[:width], d[y*width:(y+1)*width])
+		distanceTransform1D(f[:width], out[:width], v, z)
+		copy(d[y*width:(y+1)*width], out[:width])
+	}
+	return d
+}
+
+// distanceTransform1D calculates the lower envelope of the parabolas of the f values.
+// The v and z are working buffers with at least len(f) and len(f)+1 length.
+func distanceTransform1D(f, out []float64, v []int, z []float64) {
+	n := len(f)
+	k := 0
+	v[0] = 0
+	z[0] = -sdfInfinity
+	z[1] = sdfInfinity
+	for q := 1; q < n; q++ {
+		s := ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		for s <= z[k] {
+			k--
+			s = ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		}
+		k++
+		v[k] = q
+		z[k] = s
+		z[k+1] = sdfInfinity
+	}
+	k = 0
+	for q := 0; q < n; q++ {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		out[q] = float64((q-v[k])*(q-v[k])) + f[v[k]]
+	}
+}
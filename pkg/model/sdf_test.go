@@ -0,0 +1,82 @@
+package model
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestDistanceTransform(t *testing.T) {
+	// 5x3 mask with one target pixel in the middle.
+	mask := make([]bool, 15)
+	mask[7] = true
+	d := distanceTransform(mask, 5, 3, true)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 5; x++ {
+			expected := float64((x-2)*(x-2) + (y-1)*(y-1))
+			if d[y*5+x] != expected {
+				t.Errorf("Invalid distance at (%d, %d). Instead of '%f', we have '%f'.", x, y, expected, d[y*5+x])
+			}
+		}
+	}
+}
+func TestSignedDistanceField(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(img, img.Bounds(), image.Black, image.ZP, draw.Src)
+	draw.Draw(img, image.Rect(2, 2, 8, 8), image.White, image.ZP, draw.Src)
+	spread := 4
+	sdf := signedDistanceField(img, spread)
+	if size := sdf.Bounds().Size(); size.X != 18 || size.Y != 18 {
+		t.Errorf("Invalid size. Instead of '18x18', we have '%dx%d'.", size.X, size.Y)
+	}
+	center := sdf.At(9, 9).(color.RGBA)
+	if center.R <= 128 {
+		t.Errorf("The center has to be inside. We have '%d'.", center.R)
+	}
+	corner := sdf.At(0, 0).(color.RGBA)
+	if corner.R != 0 {
+		t.Errorf("The far corner has to be 0. We have '%d'.", corner.R)
+	}
+	if corner.A != 255 {
+		t.Errorf("Invalid alpha. Instead of '255', we have '%d'.", corner.A)
+	}
+	// the pixels next to the edge are close to the middle value.
+	inner := sdf.At(6, 9).(color.RGBA)
+	outer := sdf.At(5, 9).(color.RGBA)
+	if math.Abs(float64(inner.R)-128) > 20 || math.Abs(float64(outer.R)-128) > 20 || inner.R <= outer.R {
+		t.Errorf("Invalid edge values. Inner: '%d', outer: '%d'.", inner.R, outer.R)
+	}
+}
+func TestTextEffects(t *testing.T) {
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	e := TextEffects{
+		OutlineWidth:   0.1,
+		OutlineColor:   mgl32.Vec3{1, 0, 0},
+		ShadowOffset:   mgl32.Vec2{2, -2},
+		ShadowSoftness: 0.2,
+		ShadowColor:    mgl32.Vec3{0, 0, 0},
+		GlowWidth:      0.3,
+		GlowColor:      mgl32.Vec3{0, 1, 0},
+	}
+	fonts.SetTextEffects(e)
+	wt := NewWorldText(fonts)
+	wt.SetTextEffects(e)
+	for _, m := range []*Model{&fonts.Model, &wt.Model} {
+		if m.uniformFloat["outlineWidth"] != 0.1 || m.uniformFloat["shadowSoftness"] != 0.2 || m.uniformFloat["glowWidth"] != 0.3 {
+			t.Errorf("Invalid float uniforms: '%v'.", m.uniformFloat)
+		}
+		if m.uniformVector["outlineColor"] != e.OutlineColor || m.uniformVector["glowColor"] != e.GlowColor {
+			t.Errorf("Invalid color uniforms: '%v'.", m.uniformVector)
+		}
+		if m.uniformVector["shadowOffset"] != (mgl32.Vec3{2, -2, 0}) {
+			t.Errorf("Invalid shadow offset. We have '%v'.", m.uniformVector["shadowOffset"])
+		}
+	}
+}
@@ -3,12 +3,14 @@ package model
 import (
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"path"
 	"runtime"
 	"time"
 
 	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/heightmap"
 	"github.com/akosgarai/playground_engine/pkg/interfaces"
 	"github.com/akosgarai/playground_engine/pkg/mesh"
 	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
@@ -47,9 +49,17 @@ func min(a, b int) int {
 
 type Liquid struct {
 	Model
-	heightMap     [][]float32
-	width, length int
-	debugMode     bool
+	heightMap        [][]float32
+	width, length    int
+	debugMode        bool
+	detailMultiplier int
+	// the heightfield water simulation. It is nil if the simulation is not enabled.
+	simulation *waterSimulation
+	// the depth of the ripples of the touching spheres.
+	rippleStrength float32
+	// the reflection and refraction render targets. They are nil if not enabled.
+	reflection *texture.RenderTarget
+	refraction *texture.RenderTarget
 }
 
 // GetLiquid returns the liquid mesh
@@ -58,12 +68,31 @@ func (l *Liquid) GetLiquid() interfaces.Mesh {
 }
 
 // CollideTestWithSphere is the collision detection function for liquid vs sphere.
+// The liquid doesn't block the movement, so that it returns false, but the spheres that are
+// touching the simulated surface (eg. the camera entering the water) make ripples.
 func (l *Liquid) CollideTestWithSphere(boundingSphere *coldet.Sphere) bool {
+	if l.simulation == nil {
+		return false
+	}
+	surface, err := l.SurfaceHeightAtPos(mgl32.Vec3{boundingSphere.X(), boundingSphere.Y(), boundingSphere.Z()})
+	if err != nil {
+		return false
+	}
+	distance := float32(math.Abs(float64(boundingSphere.Y() - surface)))
+	if distance < boundingSphere.Radius() {
+		l.AddRipple(mgl32.Vec3{boundingSphere.X(), surface, boundingSphere.Z()}, boundingSphere.Radius(), l.rippleStrength*(1-distance/boundingSphere.Radius()))
+	}
 	return false
 }
 
-// Update function does nothing.
+// Update function steps the water simulation and uploads the surface heights.
+// Without simulation it does nothing.
 func (l *Liquid) Update(dt float64) {
+	if l.simulation == nil {
+		return
+	}
+	l.simulation.update(dt)
+	l.simulation.upload()
 }
 
 type Terrain struct {
@@ -71,6 +100,11 @@ type Terrain struct {
 	heightMap     [][]float32
 	width, length int
 	debugMode     bool
+	seed          int64
+	// the current brush stroke and the stacks of the finished strokes.
+	stroke    *terrainStroke
+	undoStack []*terrainStroke
+	redoStack []*terrainStroke
 }
 
 // GetTerrain returns the terrain mesh
@@ -142,6 +176,7 @@ func (t *Terrain) Update(dt float64) {
 // TerrainBuilder is a helper structure for generating terrain. It has a fluid API,
 // so that the settings could be chained.
 type TerrainBuilder struct {
+	textureLayers
 	width, length, iterations int
 	minH, maxH                float32
 	seed                      int64
@@ -160,6 +195,19 @@ type TerrainBuilder struct {
 	liquidEta                 float32
 	liquidWaterLevel          float32
 	liquidDetailMultiplier    int
+	generator                 heightmap.Generator
+	// the random generator of the height map generation. It is created from the seed, so that
+	// the other users of the math/rand package don't change the result.
+	rnd *rand.Rand
+	// the height map of the loaded terrain data. If it is set, the height map is not generated.
+	loadedHeightMap [][]float32
+	// the water simulation and render target setup of the liquid.
+	liquidSimulation         bool
+	liquidWaveSpeed          float32
+	liquidDamping            float32
+	liquidRippleStrength     float32
+	liquidRenderTargetWidth  int
+	liquidRenderTargetHeight int
 }
 
 // NewTerrainBuilder returns a TerrainBuilder with default settings.
@@ -182,6 +230,10 @@ func NewTerrainBuilder() *TerrainBuilder {
 		liquidFrequency:        0.0,
 		liquidWaterLevel:       0.0,
 		liquidDetailMultiplier: 1,
+		liquidWaveSpeed:        defaultLiquidWaveSpeed,
+		liquidDamping:          defaultLiquidDamping,
+		liquidRippleStrength:   defaultLiquidRippleStrength,
+		textureLayers:          newTextureLayers(),
 	}
 }
 
@@ -281,6 +333,47 @@ func (t *TerrainBuilder) SetLiquidDetailMultiplier(f int) {
 	t.liquidDetailMultiplier = f
 }
 
+// SetLiquidSimulation sets the liquidSimulation flag. If it is true, the liquid surface
+// is simulated as heightfield, so that it could have ripples.
+func (t *TerrainBuilder) SetLiquidSimulation(v bool) {
+	t.liquidSimulation = v
+}
+
+// SetLiquidWaveSpeed sets the speed of the simulated waves in grid cells per ms.
+func (t *TerrainBuilder) SetLiquidWaveSpeed(s float32) {
+	t.liquidWaveSpeed = s
+}
+
+// SetLiquidDamping sets the remaining ratio of the simulated wave amplitude after a second.
+func (t *TerrainBuilder) SetLiquidDamping(d float32) {
+	t.liquidDamping = d
+}
+
+// SetLiquidRippleStrength sets the depth of the ripples, that are made by the touching spheres.
+func (t *TerrainBuilder) SetLiquidRippleStrength(s float32) {
+	t.liquidRippleStrength = s
+}
+
+// SetLiquidRenderTargets sets the size of the reflection and refraction render targets of the liquid.
+// With 0 size, the render targets are not created.
+func (t *TerrainBuilder) SetLiquidRenderTargets(width, height int) {
+	t.liquidRenderTargetWidth = width
+	t.liquidRenderTargetHeight = height
+}
+
+// SetGenerator sets the heightmap generator. If it is set, the heightmap is generated with it
+// instead of the iterations, peakProbability, cliffProbability based elevation.
+func (t *TerrainBuilder) SetGenerator(g heightmap.Generator) {
+	t.generator = g
+}
+
+// SetSplatMap sets the splat map image of the texture layers. The rgba channels of the image are the
+// weights of the first 4 layers. The image covers the whole terrain. If it is set, the height and
+// slope ranges of the layers are not used.
+func (t *TerrainBuilder) SetSplatMap(filePath string) {
+	t.splatMap = filePath
+}
+
 // SurfaceTextureGrass sets the surface texture to grass.
 func (t *TerrainBuilder) SurfaceTextureGrass() {
 	_, filename, _, _ := runtime.Caller(1)
@@ -342,7 +435,7 @@ func (t *TerrainBuilder) buildTerrainHeightMap() {
 	if t.debugMode {
 		fmt.Printf("Setup random seed to '%d'.\n", t.seed)
 	}
-	rand.Seed(t.seed)
+	t.rnd = rand.New(rand.NewSource(t.seed))
 	defaultHeight := float32(0.0)
 	if t.minHIsDefault {
 		defaultHeight = t.minH
@@ -359,7 +452,7 @@ func (t *TerrainBuilder) buildTerrainHeightMap() {
 				if t.heightMap[l][w] != defaultHeight {
 					continue
 				}
-				random := rand.Intn(100)
+				random := t.rnd.Intn(100)
 				if t.debugMode {
 					fmt.Printf("TerrainBuilder.buildTerrainHeightMap current random: '%d'.\n", random)
 				}
@@ -374,12 +467,41 @@ func (t *TerrainBuilder) adjacentElevation(cW, cL int, elevation float32) bool {
 	for l := max(0, cL-1); l <= min(t.length-1, cL+1); l++ {
 		for w := max(0, cW-1); w <= min(t.width-1, cW+1); w++ {
 			if t.heightMap[l][w] == elevation {
-				return rand.Intn(100) > t.cliffProbability
+				return t.rnd.Intn(100) > t.cliffProbability
 			}
 		}
 	}
 	return false
 }
+
+// gridPosition returns the local position of the given point of the height map.
+func gridPosition(width, length int, heightMap [][]float32, w, l int) mgl32.Vec3 {
+	return mgl32.Vec3{
+		-float32(width)/2.0 + float32(w),
+		heightMap[l][w],
+		-float32(length)/2.0 + float32(l)}
+}
+
+// gridNormal returns the normal vector of the given point of the height map. It is calculated
+// from the next points in the X and Z directions, so that it depends on the (w, l), (w+1, l)
+// and (w, l+1) heights.
+func gridNormal(width, length int, heightMap [][]float32, w, l int) mgl32.Vec3 {
+	var iL, iW int
+	if l == length {
+		iL = l
+	} else {
+		iL = l + 1
+	}
+	if w == width {
+		iW = w
+	} else {
+		iW = w + 1
+	}
+	currentPos := gridPosition(width, length, heightMap, w, l)
+	nextPosX := gridPosition(width, length, heightMap, iW, l)
+	nextPosY := gridPosition(width, length, heightMap, w, iL)
+	return nextPosX.Sub(currentPos).Cross(nextPosY.Sub(currentPos)).Normalize()
+}
 func (t *TerrainBuilder) vertices(width, length, textureWidth, textureHeight int, heightMap [][]float32) []vertex.Vertex {
 	textureStepX := float32(1.0) / float32(textureWidth)
 	textureStepY := float32(1.0) / float32(textureHeight)
@@ -388,33 +510,9 @@ func (t *TerrainBuilder) vertices(width, length, textureWidth, textureHeight int
 		for w := 0; w <= width; w++ {
 			textureModX := w % (textureWidth + 1)
 			textureModY := l % (textureHeight + 1)
-			var iL, iW int
-			if l == length {
-				iL = l
-			} else {
-				iL = l + 1
-			}
-			if w == width {
-				iW = w
-			} else {
-				iW = w + 1
-			}
-			currentPos := mgl32.Vec3{
-				-float32(width)/2.0 + float32(w),
-				heightMap[l][w],
-				-float32(length)/2.0 + float32(l)}
-			nextPosX := mgl32.Vec3{
-				-float32(width)/2.0 + float32(iW),
-				heightMap[l][iW],
-				-float32(length)/2.0 + float32(l)}
-			nextPosY := mgl32.Vec3{
-				-float32(width)/2.0 + float32(w),
-				heightMap[iL][w],
-				-float32(length)/2.0 + float32(iL)}
-			normal := nextPosX.Sub(currentPos).Cross(nextPosY.Sub(currentPos)).Normalize()
 			vertices = append(vertices, vertex.Vertex{
-				Position:  currentPos,
-				Normal:    normal,
+				Position:  gridPosition(width, length, heightMap, w, l),
+				Normal:    gridNormal(width, length, heightMap, w, l),
 				TexCoords: mgl32.Vec2{float32(textureModX) * textureStepX, float32(textureModY) * textureStepY},
 			})
 		}
@@ -460,18 +558,34 @@ func (t *TerrainBuilder) buildTerrain() *Terrain {
 	if t.debugMode {
 		fmt.Printf("TerrainBuilder.buildTerrain.heightMap after init:\n'%v'\n", t.heightMap)
 	}
-	t.buildTerrainHeightMap()
+	if t.loadedHeightMap != nil {
+		if len(t.loadedHeightMap) != t.length+1 || len(t.loadedHeightMap[0]) != t.width+1 {
+			panic("The size of the loaded height map doesn't match the width and the length.")
+		}
+		for l := 0; l <= t.length; l++ {
+			copy(t.heightMap[l], t.loadedHeightMap[l])
+		}
+	} else if t.generator != nil {
+		t.generator.Generate(t.heightMap)
+	} else {
+		t.buildTerrainHeightMap()
+	}
 	if t.debugMode {
 		fmt.Printf("TerrainBuilder.heightMap after build:\n'%v'\n", t.heightMap)
 	}
 	v := t.vertices(t.width, t.length, 1, 1, t.heightMap)
 	i := t.indices(t.width, t.length)
-	terrainMesh := mesh.NewTexturedMesh(v, i, t.tex, t.wrapper)
+	terrainMesh := mesh.NewTexturedMesh(v, i, t.textures(t.tex, t.wrapper), t.wrapper)
 	terrainMesh.SetScale(t.scale)
 	terrainMesh.SetPosition(t.position)
 	m := newModel()
 	m.AddMesh(terrainMesh)
-	return &Terrain{Model: *m, heightMap: t.heightMap, width: t.width, length: t.length, debugMode: t.debugMode}
+	t.setUniforms(m)
+	if t.splatMap != "" {
+		// the splat map covers the whole terrain mesh.
+		m.SetUniformVector("terrainSize", mgl32.Vec3{float32(t.width), 0, float32(t.length)})
+	}
+	return &Terrain{Model: *m, heightMap: t.heightMap, width: t.width, length: t.length, debugMode: t.debugMode, seed: t.seed}
 }
 
 func (t *TerrainBuilder) buildLiquid() *Liquid {
@@ -483,7 +597,20 @@ func (t *TerrainBuilder) buildLiquid() *Liquid {
 	}
 	v := t.vertices(waterWidth, waterLength, waterWidth, waterLength, waterHeightMap)
 	i := t.indices(waterWidth, waterLength)
-	liquidMesh := mesh.NewTexturedMesh(v, i, t.liquidTex, t.wrapper)
+	// the builder textures are not modified, so that the builder could be used more times.
+	liquidTex := append(texture.Textures{}, t.liquidTex...)
+	var simulation *waterSimulation
+	if t.liquidSimulation {
+		simulation = newWaterSimulation(waterWidth, waterLength, t.liquidWaveSpeed, t.liquidDamping)
+		liquidTex.AddFloatTexture(waterWidth+1, waterLength+1, simulation.data, "surfaceMap", t.wrapper)
+		simulation.surfaceMap = liquidTex[len(liquidTex)-1]
+	}
+	var reflection, refraction *texture.RenderTarget
+	if t.liquidRenderTargetWidth > 0 && t.liquidRenderTargetHeight > 0 {
+		reflection = liquidTex.AddRenderTarget(t.liquidRenderTargetWidth, t.liquidRenderTargetHeight, "reflectionTexture", t.wrapper)
+		refraction = liquidTex.AddRenderTarget(t.liquidRenderTargetWidth, t.liquidRenderTargetHeight, "refractionTexture", t.wrapper)
+	}
+	liquidMesh := mesh.NewTexturedMesh(v, i, liquidTex, t.wrapper)
 	scaleValue := float32(1.0) / float32(t.liquidDetailMultiplier)
 	liquidMesh.SetScale(mgl32.Vec3{scaleValue, 1.0, scaleValue})
 	liquidMesh.SetPosition(mgl32.Vec3{t.position.X(), t.position.Y() + t.liquidWaterLevel, t.position.Z()})
@@ -495,6 +622,12 @@ func (t *TerrainBuilder) buildLiquid() *Liquid {
 	m.SetUniformFloat("amplitude", t.liquidAmplitude)
 	m.SetUniformFloat("frequency", t.liquidFrequency)
 	m.SetUniformFloat("waterLevel", t.liquidWaterLevel)
+	if simulation != nil {
+		m.SetUniformFloat("useSurfaceMap", 1)
+	}
+	if reflection != nil {
+		m.SetUniformFloat("useRenderTargets", 1)
+	}
 
 	return &Liquid{
 		Model:     *m,
@@ -502,5 +635,11 @@ func (t *TerrainBuilder) buildLiquid() *Liquid {
 		width:     waterWidth,
 		length:    waterLength,
 		debugMode: t.debugMode,
+
+		detailMultiplier: t.liquidDetailMultiplier,
+		simulation:       simulation,
+		rippleStrength:   t.liquidRippleStrength,
+		reflection:       reflection,
+		refraction:       refraction,
 	}
 }
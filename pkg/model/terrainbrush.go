@@ -0,0 +1,266 @@
+package model
+
+import (
+	"math"
+
+	"github.com/akosgarai/playground_engine/pkg/heightmap"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// BrushMode is the type of the terrain modification.
+type BrushMode int
+
+const (
+	// It raises the surface with the strength.
+	BRUSH_RAISE BrushMode = iota
+	// It lowers the surface with the strength.
+	BRUSH_LOWER
+	// It moves the heights towards the average of the neighbour heights.
+	BRUSH_SMOOTH
+	// It moves the heights towards the height of the brush.
+	BRUSH_FLATTEN
+	// It adds noise to the heights.
+	BRUSH_NOISE
+)
+
+const (
+	defaultBrushFalloff        = float32(0.5)
+	defaultBrushNoiseFrequency = float32(0.3)
+)
+
+// TerrainBrush describes a terrain modification, that could be applied with the
+// Terrain.ApplyBrush function. The effect is full in the inner part of the radius
+// and it fades in the falloff part.
+type TerrainBrush struct {
+	Mode BrushMode
+	// the radius of the brush in world units.
+	Radius float32
+	// the height change of an application in the center in height map units (raise, lower, noise),
+	// or the ratio of the change between 0 and 1 (smooth, flatten).
+	Strength float32
+	// the fading part of the radius between 0 and 1. With 0 the brush has hard edges,
+	// with 1 the effect fades from the center.
+	Falloff float32
+	// the target height of the flatten brush in height map units.
+	Height float32
+	// the noise of the noise brush. It is sampled in the height map coordinates.
+	Noise          heightmap.Noise
+	NoiseFrequency float32
+}
+
+// NewTerrainBrush returns a brush with the given mode, radius and strength. The falloff
+// is 0.5, the noise brush uses Perlin noise.
+func NewTerrainBrush(mode BrushMode, radius, strength float32) *TerrainBrush {
+	return &TerrainBrush{
+		Mode:           mode,
+		Radius:         radius,
+		Strength:       strength,
+		Falloff:        defaultBrushFalloff,
+		Noise:          heightmap.NewPerlinNoise(0),
+		NoiseFrequency: defaultBrushNoiseFrequency,
+	}
+}
+
+// weight returns the effect of the brush in the given distance from the center. It is 1 in
+// the inner part, it fades to 0 with smoothstep curve in the falloff part.
+func (b *TerrainBrush) weight(distance float32) float32 {
+	if distance >= b.Radius {
+		return 0
+	}
+	inner := b.Radius * (1 - b.Falloff)
+	if distance <= inner {
+		return 1
+	}
+	x := (distance - inner) / (b.Radius - inner)
+	return 1 - x*x*(3-2*x)
+}
+
+// terrainStroke is an undoable terrain modification. The maps contain the height values of
+// the modified points before and after the stroke, the keys are the vertex indices.
+type terrainStroke struct {
+	before map[int]float32
+	after  map[int]float32
+}
+
+// worldToGrid returns the height map coordinates of the given world position.
+func (t *Terrain) worldToGrid(pos mgl32.Vec3) (float32, float32) {
+	tMesh := t.GetTerrain()
+	scaleTr := tMesh.ScaleTransformation()
+	local := pos.Sub(tMesh.GetPosition())
+	return local.X()/scaleTr[0] + float32(t.width)/2, local.Z()/scaleTr[10] + float32(t.length)/2
+}
+
+// averageAround returns the average height of the given point and its neighbours.
+func averageAround(heightMap [][]float32, w, l int) float32 {
+	sum, count := float32(0), 0
+	for z := max(0, l-1); z <= min(len(heightMap)-1, l+1); z++ {
+		for x := max(0, w-1); x <= min(len(heightMap[z])-1, w+1); x++ {
+			sum += heightMap[z][x]
+			count++
+		}
+	}
+	return sum / float32(count)
+}
+
+// BeginStroke starts a new stroke. The brush applications until the EndStroke are
+// undone and redone together. The unfinished stroke is finished.
+func (t *Terrain) BeginStroke() {
+	t.EndStroke()
+	t.stroke = &terrainStroke{before: make(map[int]float32)}
+}
+
+// EndStroke finishes the current stroke. If the stroke modified the terrain, it is pushed
+// to the undo stack and the redo stack is cleared.
+func (t *Terrain) EndStroke() {
+	if t.stroke == nil {
+		return
+	}
+	s := t.stroke
+	t.stroke = nil
+	if len(s.before) == 0 {
+		return
+	}
+	s.after = make(map[int]float32, len(s.before))
+	for index := range s.before {
+		s.after[index] = t.heightMap[index/(t.width+1)][index%(t.width+1)]
+	}
+	t.undoStack = append(t.undoStack, s)
+	t.redoStack = nil
+}
+
+// ApplyBrush applies the brush at the given world position, for example at the hit point
+// of a mouse ray. It updates the height map, that is used by the HeightAtPos, recalculates
+// the normals and uploads the modified range of the vertices. If the position is not above
+// or under the surface, it returns ErrorNotAboveTheSurface. Without started stroke, the
+// application is an undoable stroke itself.
+func (t *Terrain) ApplyBrush(b *TerrainBrush, pos mgl32.Vec3) error {
+	gx, gz := t.worldToGrid(pos)
+	if gx < 0 || gz < 0 || gx > float32(t.width) || gz > float32(t.length) {
+		return ErrorNotAboveTheSurface
+	}
+	if t.stroke == nil {
+		t.BeginStroke()
+		defer t.EndStroke()
+	}
+	scaleTr := t.GetTerrain().ScaleTransformation()
+	scaleX, scaleZ := scaleTr[0], scaleTr[10]
+	minW := max(0, int(math.Floor(float64(gx-b.Radius/scaleX))))
+	maxW := min(t.width, int(math.Ceil(float64(gx+b.Radius/scaleX))))
+	minL := max(0, int(math.Floor(float64(gz-b.Radius/scaleZ))))
+	maxL := min(t.length, int(math.Ceil(float64(gz+b.Radius/scaleZ))))
+	var original [][]float32
+	if b.Mode == BRUSH_SMOOTH {
+		// the smoothing uses the heights before the application.
+		original = make([][]float32, len(t.heightMap))
+		for l := max(0, minL-1); l <= min(t.length, maxL+1); l++ {
+			original[l] = append([]float32{}, t.heightMap[l]...)
+		}
+	}
+	changed := false
+	for l := minL; l <= maxL; l++ {
+		for w := minW; w <= maxW; w++ {
+			dx, dz := (float32(w)-gx)*scaleX, (float32(l)-gz)*scaleZ
+			k := b.weight(float32(math.Sqrt(float64(dx*dx + dz*dz))))
+			if k == 0 {
+				continue
+			}
+			h := t.heightMap[l][w]
+			newH := h
+			switch b.Mode {
+			case BRUSH_RAISE:
+				newH = h + b.Strength*k
+			case BRUSH_LOWER:
+				newH = h - b.Strength*k
+			case BRUSH_SMOOTH:
+				newH = h + (averageAround(original, w, l)-h)*float32(math.Min(1, float64(b.Strength*k)))
+			case BRUSH_FLATTEN:
+				newH = h + (b.Height-h)*float32(math.Min(1, float64(b.Strength*k)))
+			case BRUSH_NOISE:
+				noise := b.Noise.Noise2D(float64(float32(w)*b.NoiseFrequency), float64(float32(l)*b.NoiseFrequency))
+				newH = h + b.Strength*k*float32(noise)
+			}
+			if newH == h {
+				continue
+			}
+			index := l*(t.width+1) + w
+			if _, ok := t.stroke.before[index]; !ok {
+				t.stroke.before[index] = h
+			}
+			t.heightMap[l][w] = newH
+			changed = true
+		}
+	}
+	if changed {
+		t.updateVertices(minW, minL, maxW, maxL)
+	}
+	return nil
+}
+
+// updateVertices updates the positions and the normals of the vertices that depend on
+// the heights of the given range, and uploads the modified range of the vertices.
+func (t *Terrain) updateVertices(minW, minL, maxW, maxL int) {
+	tMesh := t.GetTerrain().(*mesh.TexturedMesh)
+	// the normals depend on the next heights, so that the previous row and column also changes.
+	minW, minL = max(0, minW-1), max(0, minL-1)
+	for l := minL; l <= maxL; l++ {
+		for w := minW; w <= maxW; w++ {
+			index := l*(t.width+1) + w
+			tMesh.Vertices[index].Position = gridPosition(t.width, t.length, t.heightMap, w, l)
+			tMesh.Vertices[index].Normal = gridNormal(t.width, t.length, t.heightMap, w, l)
+		}
+	}
+	tMesh.UpdateVertices(minL*(t.width+1)+minW, maxL*(t.width+1)+maxW+1)
+}
+
+// setHeights sets the given height values and updates the vertices.
+func (t *Terrain) setHeights(heights map[int]float32) {
+	minW, minL, maxW, maxL := t.width, t.length, 0, 0
+	for index, h := range heights {
+		w, l := index%(t.width+1), index/(t.width+1)
+		t.heightMap[l][w] = h
+		minW, maxW = min(minW, w), max(maxW, w)
+		minL, maxL = min(minL, l), max(maxL, l)
+	}
+	t.updateVertices(minW, minL, maxW, maxL)
+}
+
+// CanUndo returns true if the terrain has stroke to undo.
+func (t *Terrain) CanUndo() bool {
+	return len(t.undoStack) > 0 || (t.stroke != nil && len(t.stroke.before) > 0)
+}
+
+// CanRedo returns true if the terrain has undone stroke to redo.
+func (t *Terrain) CanRedo() bool {
+	return len(t.redoStack) > 0 && (t.stroke == nil || len(t.stroke.before) == 0)
+}
+
+// Undo reverts the last stroke. The unfinished stroke is finished before.
+// It returns false if there is nothing to undo.
+func (t *Terrain) Undo() bool {
+	t.EndStroke()
+	if len(t.undoStack) == 0 {
+		return false
+	}
+	s := t.undoStack[len(t.undoStack)-1]
+	t.undoStack = t.undoStack[:len(t.undoStack)-1]
+	t.setHeights(s.before)
+	t.redoStack = append(t.redoStack, s)
+	return true
+}
+
+// Redo applies the last undone stroke again. The unfinished stroke is finished before,
+// so that it clears the redo stack, if it modified the terrain.
+// It returns false if there is nothing to redo.
+func (t *Terrain) Redo() bool {
+	t.EndStroke()
+	if len(t.redoStack) == 0 {
+		return false
+	}
+	s := t.redoStack[len(t.redoStack)-1]
+	t.redoStack = t.redoStack[:len(t.redoStack)-1]
+	t.setHeights(s.after)
+	t.undoStack = append(t.undoStack, s)
+	return true
+}
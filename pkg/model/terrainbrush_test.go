@@ -0,0 +1,176 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func flatTestTerrain() *Terrain {
+	tb := NewTerrainBuilder()
+	tb.SetWidth(10)
+	tb.SetLength(10)
+	tb.SetIterations(0)
+	tb.SetGlWrapper(wrapperMock)
+	return tb.Build()
+}
+func copyTestHeightMap(heightMap [][]float32) [][]float32 {
+	result := make([][]float32, len(heightMap))
+	for l := range heightMap {
+		result[l] = append([]float32{}, heightMap[l]...)
+	}
+	return result
+}
+func TestTerrainBrushWeight(t *testing.T) {
+	b := NewTerrainBrush(BRUSH_RAISE, 2, 1)
+	testData := []struct {
+		distance float32
+		weight   float32
+	}{
+		{0, 1},
+		{1, 1},
+		{1.5, 0.5},
+		{2, 0},
+		{3, 0},
+	}
+	for _, tt := range testData {
+		if w := b.weight(tt.distance); w != tt.weight {
+			t.Errorf("Invalid weight at '%f'. Instead of '%f', we have '%f'.", tt.distance, tt.weight, w)
+		}
+	}
+	b.Falloff = 0
+	if w := b.weight(1.9); w != 1 {
+		t.Errorf("Invalid hard edge weight. Instead of '1.0', we have '%f'.", w)
+	}
+}
+func TestTerrainApplyBrushRaiseLower(t *testing.T) {
+	terr := flatTestTerrain()
+	b := NewTerrainBrush(BRUSH_RAISE, 2, 1)
+	if err := terr.ApplyBrush(b, mgl32.Vec3{0, 0, 0}); err != nil {
+		t.Fatalf("ApplyBrush failed: '%s'.", err)
+	}
+	if terr.heightMap[5][5] != 1 {
+		t.Errorf("Invalid center height. Instead of '1.0', we have '%f'.", terr.heightMap[5][5])
+	}
+	if terr.heightMap[5][7] != 0 || terr.heightMap[0][0] != 0 {
+		t.Error("The points outside of the radius shouldn't be modified.")
+	}
+	if h, _ := terr.HeightAtPos(mgl32.Vec3{0, 0, 0}); h != 1 {
+		t.Errorf("Invalid HeightAtPos. Instead of '1.0', we have '%f'.", h)
+	}
+	vertices := terr.GetTerrain().(*mesh.TexturedMesh).Vertices
+	if vertices[5*11+5].Position.Y() != 1 {
+		t.Errorf("Invalid vertex position. '%v'.", vertices[5*11+5].Position)
+	}
+	// the normal of the previous point looks to the raised one.
+	expectedNormal := gridNormal(10, 10, terr.heightMap, 4, 5)
+	if vertices[5*11+4].Normal != expectedNormal || expectedNormal.Sub(mgl32.Vec3{0, -1, 0}).Len() < 0.0001 {
+		t.Errorf("Invalid vertex normal. '%v'.", vertices[5*11+4].Normal)
+	}
+	terr.ApplyBrush(NewTerrainBrush(BRUSH_LOWER, 2, 1), mgl32.Vec3{0, 0, 0})
+	if terr.heightMap[5][5] != 0 {
+		t.Errorf("Invalid center height after lower. Instead of '0.0', we have '%f'.", terr.heightMap[5][5])
+	}
+	if err := terr.ApplyBrush(b, mgl32.Vec3{20, 0, 0}); err != ErrorNotAboveTheSurface {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorNotAboveTheSurface, err)
+	}
+}
+func TestTerrainApplyBrushScaledAndMoved(t *testing.T) {
+	tb := NewTerrainBuilder()
+	tb.SetIterations(0)
+	tb.SetScale(mgl32.Vec3{2, 1, 2})
+	tb.SetPosition(mgl32.Vec3{10, 0, 0})
+	tb.SetGlWrapper(wrapperMock)
+	terr := tb.Build()
+	b := NewTerrainBrush(BRUSH_RAISE, 3, 1)
+	b.Falloff = 0
+	terr.ApplyBrush(b, mgl32.Vec3{12, 0, 0})
+	// the center is the (6, 5) point, the radius covers one point in each direction.
+	for _, p := range [][2]int{{6, 5}, {5, 5}, {7, 5}, {6, 4}, {6, 6}} {
+		if terr.heightMap[p[1]][p[0]] != 1 {
+			t.Errorf("The point '%v' should be raised.", p)
+		}
+	}
+	if terr.heightMap[5][8] != 0 || terr.heightMap[5][4] != 0 {
+		t.Error("The points outside of the radius shouldn't be modified.")
+	}
+}
+func TestTerrainApplyBrushSmoothFlattenNoise(t *testing.T) {
+	terr := flatTestTerrain()
+	terr.heightMap[5][5] = 9
+	terr.ApplyBrush(NewTerrainBrush(BRUSH_SMOOTH, 1.5, 1), mgl32.Vec3{0, 0, 0})
+	if terr.heightMap[5][5] != 1 {
+		t.Errorf("Invalid smoothed height. Instead of '1.0', we have '%f'.", terr.heightMap[5][5])
+	}
+	if terr.heightMap[5][6] <= 0 {
+		t.Error("The neighbour should be raised by the smoothing.")
+	}
+	flatten := NewTerrainBrush(BRUSH_FLATTEN, 3, 1)
+	flatten.Height = 4
+	flatten.Falloff = 0
+	terr.ApplyBrush(flatten, mgl32.Vec3{0, 0, 0})
+	for l := 4; l <= 6; l++ {
+		for w := 4; w <= 6; w++ {
+			if terr.heightMap[l][w] != 4 {
+				t.Errorf("Invalid flattened height at '%d, %d': '%f'.", w, l, terr.heightMap[l][w])
+			}
+		}
+	}
+	before := copyTestHeightMap(terr.heightMap)
+	terr.ApplyBrush(NewTerrainBrush(BRUSH_NOISE, 3, 1), mgl32.Vec3{0, 0, 0})
+	if reflect.DeepEqual(before, terr.heightMap) {
+		t.Error("The noise brush should modify the terrain.")
+	}
+	if !reflect.DeepEqual(before[0], terr.heightMap[0]) {
+		t.Error("The noise brush shouldn't modify the points outside of the radius.")
+	}
+}
+func TestTerrainUndoRedo(t *testing.T) {
+	terr := flatTestTerrain()
+	if terr.CanUndo() || terr.CanRedo() || terr.Undo() || terr.Redo() {
+		t.Error("The new terrain shouldn't have undo, redo.")
+	}
+	original := copyTestHeightMap(terr.heightMap)
+	b := NewTerrainBrush(BRUSH_RAISE, 2, 1)
+	// a stroke with more applications.
+	terr.BeginStroke()
+	terr.ApplyBrush(b, mgl32.Vec3{0, 0, 0})
+	terr.ApplyBrush(b, mgl32.Vec3{1, 0, 0})
+	terr.EndStroke()
+	afterFirst := copyTestHeightMap(terr.heightMap)
+	// a single application stroke.
+	terr.ApplyBrush(b, mgl32.Vec3{-3, 0, -3})
+	if len(terr.undoStack) != 2 {
+		t.Fatalf("Invalid undo stack length. Instead of '2', we have '%d'.", len(terr.undoStack))
+	}
+	if !terr.Undo() || !reflect.DeepEqual(terr.heightMap, afterFirst) {
+		t.Error("The first undo should revert the single application.")
+	}
+	if !terr.Undo() || !reflect.DeepEqual(terr.heightMap, original) {
+		t.Error("The second undo should revert the whole stroke.")
+	}
+	vertices := terr.GetTerrain().(*mesh.TexturedMesh).Vertices
+	if vertices[5*11+5].Position.Y() != 0 {
+		t.Error("The undo should update the vertices.")
+	}
+	if terr.CanUndo() || !terr.CanRedo() {
+		t.Error("Invalid undo, redo state.")
+	}
+	if !terr.Redo() || !reflect.DeepEqual(terr.heightMap, afterFirst) {
+		t.Error("The first redo should apply the stroke.")
+	}
+	// a new stroke clears the redo stack.
+	terr.ApplyBrush(b, mgl32.Vec3{3, 0, 3})
+	if terr.CanRedo() || terr.Redo() {
+		t.Error("The new stroke should clear the redo stack.")
+	}
+	// an empty stroke doesn't change the stacks.
+	terr.BeginStroke()
+	terr.EndStroke()
+	if len(terr.undoStack) != 2 {
+		t.Errorf("Invalid undo stack length. Instead of '2', we have '%d'.", len(terr.undoStack))
+	}
+}
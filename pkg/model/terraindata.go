@@ -0,0 +1,289 @@
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The current version of the terrain data format. The older versions could be loaded.
+	TERRAIN_DATA_VERSION = 1
+	// The max width and length of the stored terrains. The bigger sizes are rejected, so that
+	// a broken file couldn't allocate the memory.
+	TERRAIN_DATA_MAX_SIZE = 8192
+	// The first bytes of the binary terrain files.
+	terrainDataMagic = "PGTR"
+)
+
+var (
+	ErrorUnknownTerrainDataFormat  = errors.New("Unknown terrain data format")
+	ErrorUnsupportedTerrainVersion = errors.New("Unsupported terrain data version")
+	ErrorInvalidTerrainData        = errors.New("The size of the height map doesn't match the width and the length")
+)
+
+// LiquidData contains the parameters of the liquid surface of a terrain.
+type LiquidData struct {
+	Eta              float32 `json:"eta"`
+	Amplitude        float32 `json:"amplitude"`
+	Frequency        float32 `json:"frequency"`
+	WaterLevel       float32 `json:"waterLevel"`
+	DetailMultiplier int     `json:"detailMultiplier"`
+}
+
+// TerrainData is the storable form of a generated terrain. It contains the height map
+// and the setup of the terrain mesh, and optionally the parameters of the liquid.
+type TerrainData struct {
+	Version   int         `json:"version"`
+	Width     int         `json:"width"`
+	Length    int         `json:"length"`
+	Seed      int64       `json:"seed"`
+	Scale     mgl32.Vec3  `json:"scale"`
+	Position  mgl32.Vec3  `json:"position"`
+	HeightMap [][]float32 `json:"heightMap"`
+	Liquid    *LiquidData `json:"liquid,omitempty"`
+}
+
+// terrainDataHeader is the fixed size part of the binary format.
+type terrainDataHeader struct {
+	Magic     [4]byte
+	Version   uint16
+	Width     uint32
+	Length    uint32
+	Seed      int64
+	Scale     [3]float32
+	Position  [3]float32
+	HasLiquid uint8
+}
+
+// liquidDataBinary is the binary form of the liquid parameters.
+type liquidDataBinary struct {
+	Eta              float32
+	Amplitude        float32
+	Frequency        float32
+	WaterLevel       float32
+	DetailMultiplier uint32
+}
+
+// NewTerrainData returns the storable form of the given terrain. The liquid could be nil.
+func NewTerrainData(t *Terrain, l *Liquid) *TerrainData {
+	tMesh := t.GetTerrain()
+	scaleTr := tMesh.ScaleTransformation()
+	d := &TerrainData{
+		Version:   TERRAIN_DATA_VERSION,
+		Width:     t.width,
+		Length:    t.length,
+		Seed:      t.seed,
+		Scale:     mgl32.Vec3{scaleTr[0], scaleTr[5], scaleTr[10]},
+		Position:  tMesh.GetPosition(),
+		HeightMap: t.heightMap,
+	}
+	if l != nil {
+		d.Liquid = &LiquidData{
+			Eta:              l.uniformFloat["Eta"],
+			Amplitude:        l.uniformFloat["amplitude"],
+			Frequency:        l.uniformFloat["frequency"],
+			WaterLevel:       l.uniformFloat["waterLevel"],
+			DetailMultiplier: l.detailMultiplier,
+		}
+	}
+	return d
+}
+
+// validate checks the version and the size of the height map.
+func (d *TerrainData) validate() error {
+	if d.Version < 1 || d.Version > TERRAIN_DATA_VERSION {
+		return ErrorUnsupportedTerrainVersion
+	}
+	if d.Width < 0 || d.Length < 0 || d.Width > TERRAIN_DATA_MAX_SIZE || d.Length > TERRAIN_DATA_MAX_SIZE || len(d.HeightMap) != d.Length+1 {
+		return ErrorInvalidTerrainData
+	}
+	for l := 0; l < len(d.HeightMap); l++ {
+		if len(d.HeightMap[l]) != d.Width+1 {
+			return ErrorInvalidTerrainData
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the data in json format.
+func (d *TerrainData) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+// WriteBinary writes the data in the little endian binary format. The header
+// is followed by the liquid parameters (if the terrain has liquid) and the height values row by row.
+func (d *TerrainData) WriteBinary(w io.Writer) error {
+	if err := d.validate(); err != nil {
+		return err
+	}
+	header := terrainDataHeader{
+		Version:  uint16(d.Version),
+		Width:    uint32(d.Width),
+		Length:   uint32(d.Length),
+		Seed:     d.Seed,
+		Scale:    d.Scale,
+		Position: d.Position,
+	}
+	copy(header.Magic[:], terrainDataMagic)
+	if d.Liquid != nil {
+		header.HasLiquid = 1
+	}
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	if d.Liquid != nil {
+		liquid := liquidDataBinary{
+			Eta:              d.Liquid.Eta,
+			Amplitude:        d.Liquid.Amplitude,
+			Frequency:        d.Liquid.Frequency,
+			WaterLevel:       d.Liquid.WaterLevel,
+			DetailMultiplier: uint32(d.Liquid.DetailMultiplier),
+		}
+		if err := binary.Write(w, binary.LittleEndian, &liquid); err != nil {
+			return err
+		}
+	}
+	for l := 0; l < len(d.HeightMap); l++ {
+		if err := binary.Write(w, binary.LittleEndian, d.HeightMap[l]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBinary reads the binary format.
+func readBinary(r io.Reader) (*TerrainData, error) {
+	var header terrainDataHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if string(header.Magic[:]) != terrainDataMagic {
+		return nil, ErrorUnknownTerrainDataFormat
+	}
+	d := &TerrainData{
+		Version:  int(header.Version),
+		Width:    int(header.Width),
+		Length:   int(header.Length),
+		Seed:     header.Seed,
+		Scale:    header.Scale,
+		Position: header.Position,
+	}
+	if d.Version < 1 || d.Version > TERRAIN_DATA_VERSION {
+		return nil, ErrorUnsupportedTerrainVersion
+	}
+	if header.Width > TERRAIN_DATA_MAX_SIZE || header.Length > TERRAIN_DATA_MAX_SIZE {
+		return nil, ErrorInvalidTerrainData
+	}
+	if header.HasLiquid != 0 {
+		var liquid liquidDataBinary
+		if err := binary.Read(r, binary.LittleEndian, &liquid); err != nil {
+			return nil, err
+		}
+		d.Liquid = &LiquidData{
+			Eta:              liquid.Eta,
+			Amplitude:        liquid.Amplitude,
+			Frequency:        liquid.Frequency,
+			WaterLevel:       liquid.WaterLevel,
+			DetailMultiplier: int(liquid.DetailMultiplier),
+		}
+	}
+	// the rows are allocated as they are read, so that the truncated files fail before the
+	// whole height map is allocated.
+	d.HeightMap = [][]float32{}
+	for l := 0; l <= d.Length; l++ {
+		row := make([]float32, d.Width+1)
+		if err := binary.Read(r, binary.LittleEndian, row); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil, ErrorInvalidTerrainData
+			}
+			return nil, err
+		}
+		d.HeightMap = append(d.HeightMap, row)
+	}
+	return d, nil
+}
+
+// ReadTerrainData reads the terrain data from the reader. The format (json or binary)
+// is detected from the first bytes. It returns error if the format or the version is
+// not supported, or the height map doesn't match the size.
+func ReadTerrainData(r io.Reader) (*TerrainData, error) {
+	reader := bufio.NewReader(r)
+	start, err := reader.Peek(len(terrainDataMagic))
+	if err != nil {
+		return nil, ErrorUnknownTerrainDataFormat
+	}
+	var d *TerrainData
+	switch {
+	case string(start) == terrainDataMagic:
+		d, err = readBinary(reader)
+	case bytes.HasPrefix(bytes.TrimSpace(start), []byte("{")):
+		d = &TerrainData{}
+		err = json.NewDecoder(reader).Decode(d)
+	default:
+		return nil, ErrorUnknownTerrainDataFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Save writes the data to the given file. If the extension of the file is `.json`,
+// the json format is used, otherwise the binary.
+func (d *TerrainData) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		return d.WriteJSON(f)
+	}
+	return d.WriteBinary(f)
+}
+
+// LoadTerrainData reads the terrain data from the given file.
+func LoadTerrainData(path string) (*TerrainData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadTerrainData(f)
+}
+
+// SetTerrainData sets up the builder from the stored terrain. The size, seed, scale, position
+// and the liquid parameters are updated, and the stored height map is used instead of the generation.
+// If the data doesn't have liquid, the liquid parameters are reset to the defaults, so that the
+// previous setup is not kept. The height map could be tweaked before the Build.
+func (t *TerrainBuilder) SetTerrainData(d *TerrainData) {
+	t.width = d.Width
+	t.length = d.Length
+	t.seed = d.Seed
+	t.scale = d.Scale
+	t.position = d.Position
+	t.loadedHeightMap = d.HeightMap
+	liquid := d.Liquid
+	if liquid == nil {
+		liquid = &LiquidData{DetailMultiplier: 1}
+	}
+	t.liquidEta = liquid.Eta
+	t.liquidAmplitude = liquid.Amplitude
+	t.liquidFrequency = liquid.Frequency
+	t.liquidWaterLevel = liquid.WaterLevel
+	t.liquidDetailMultiplier = liquid.DetailMultiplier
+}
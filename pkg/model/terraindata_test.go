@@ -0,0 +1,202 @@
+package model
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testTerrainWithLiquid() (*Terrain, *Liquid) {
+	tb := NewTerrainBuilder()
+	tb.SetWidth(4)
+	tb.SetLength(3)
+	tb.SetIterations(10)
+	tb.SetSeed(7)
+	tb.SetScale(mgl32.Vec3{2, 1, 2})
+	tb.SetPosition(mgl32.Vec3{1, 2, 3})
+	tb.SetLiquidEta(0.75)
+	tb.SetLiquidAmplitude(0.5)
+	tb.SetLiquidFrequency(0.25)
+	tb.SetLiquidWaterLevel(1.5)
+	tb.SetLiquidDetailMultiplier(2)
+	tb.SetGlWrapper(wrapperMock)
+	return tb.BuildWithLiquid()
+}
+func TestNewTerrainData(t *testing.T) {
+	terr, liquid := testTerrainWithLiquid()
+	d := NewTerrainData(terr, liquid)
+	if d.Version != TERRAIN_DATA_VERSION {
+		t.Errorf("Invalid version. Instead of '%d', we have '%d'.", TERRAIN_DATA_VERSION, d.Version)
+	}
+	if d.Width != 4 || d.Length != 3 || d.Seed != 7 {
+		t.Errorf("Invalid size or seed. '%d', '%d', '%d'.", d.Width, d.Length, d.Seed)
+	}
+	if d.Scale != (mgl32.Vec3{2, 1, 2}) {
+		t.Errorf("Invalid scale. '%v'.", d.Scale)
+	}
+	if d.Position != (mgl32.Vec3{1, 2, 3}) {
+		t.Errorf("Invalid position. '%v'.", d.Position)
+	}
+	if !reflect.DeepEqual(d.HeightMap, terr.heightMap) {
+		t.Error("Invalid height map.")
+	}
+	expectedLiquid := LiquidData{Eta: 0.75, Amplitude: 0.5, Frequency: 0.25, WaterLevel: 1.5, DetailMultiplier: 2}
+	if d.Liquid == nil || *d.Liquid != expectedLiquid {
+		t.Errorf("Invalid liquid data. Instead of '%v', we have '%v'.", expectedLiquid, d.Liquid)
+	}
+	if NewTerrainData(terr, nil).Liquid != nil {
+		t.Error("The liquid data should be nil without liquid.")
+	}
+}
+func TestTerrainDataRoundTrip(t *testing.T) {
+	terr, liquid := testTerrainWithLiquid()
+	d := NewTerrainData(terr, liquid)
+	var jsonBuf, binaryBuf bytes.Buffer
+	if err := d.WriteJSON(&jsonBuf); err != nil {
+		t.Fatalf("WriteJSON failed: '%s'.", err)
+	}
+	if err := d.WriteBinary(&binaryBuf); err != nil {
+		t.Fatalf("WriteBinary failed: '%s'.", err)
+	}
+	for name, buf := range map[string]*bytes.Buffer{"json": &jsonBuf, "binary": &binaryBuf} {
+		loaded, err := ReadTerrainData(buf)
+		if err != nil {
+			t.Errorf("Read %s failed: '%s'.", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(loaded, d) {
+			t.Errorf("Invalid %s data. Instead of '%v', we have '%v'.", name, d, loaded)
+		}
+	}
+}
+func TestTerrainDataSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terraindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	terr, _ := testTerrainWithLiquid()
+	d := NewTerrainData(terr, nil)
+	for _, name := range []string{"terrain.json", "terrain.bin"} {
+		path := filepath.Join(dir, name)
+		if err := d.Save(path); err != nil {
+			t.Fatalf("Save '%s' failed: '%s'.", name, err)
+		}
+		loaded, err := LoadTerrainData(path)
+		if err != nil {
+			t.Fatalf("Load '%s' failed: '%s'.", name, err)
+		}
+		if !reflect.DeepEqual(loaded, d) {
+			t.Errorf("Invalid '%s' data. Instead of '%v', we have '%v'.", name, d, loaded)
+		}
+	}
+	content, _ := ioutil.ReadFile(filepath.Join(dir, "terrain.bin"))
+	if string(content[:4]) != terrainDataMagic {
+		t.Errorf("The binary file should start with the magic. We have '%s'.", content[:4])
+	}
+	if _, err := LoadTerrainData(filepath.Join(dir, "not-existing.bin")); err == nil {
+		t.Error("Missing file should be an error.")
+	}
+}
+func TestReadTerrainDataErrors(t *testing.T) {
+	testData := []struct {
+		content string
+		err     error
+	}{
+		{"xyzw1234", ErrorUnknownTerrainDataFormat},
+		{"ab", ErrorUnknownTerrainDataFormat},
+		{`{"version": 2, "width": 0, "length": 0, "heightMap": [[0]]}`, ErrorUnsupportedTerrainVersion},
+		{`{"version": 0, "width": 0, "length": 0, "heightMap": [[0]]}`, ErrorUnsupportedTerrainVersion},
+		{`{"version": 1, "width": 1, "length": 0, "heightMap": [[0]]}`, ErrorInvalidTerrainData},
+		{`{"version": 1, "width": 0, "length": 1, "heightMap": [[0]]}`, ErrorInvalidTerrainData},
+	}
+	for _, tt := range testData {
+		if _, err := ReadTerrainData(bytes.NewBufferString(tt.content)); err != tt.err {
+			t.Errorf("Invalid error for '%s'. Instead of '%v', we have '%v'.", tt.content, tt.err, err)
+		}
+	}
+	d := &TerrainData{Version: TERRAIN_DATA_VERSION + 1, HeightMap: [][]float32{{0}}}
+	var buf bytes.Buffer
+	if err := d.WriteBinary(&buf); err != ErrorUnsupportedTerrainVersion {
+		t.Errorf("Invalid error. Instead of '%v', we have '%v'.", ErrorUnsupportedTerrainVersion, err)
+	}
+	// truncated binary
+	d = &TerrainData{Version: TERRAIN_DATA_VERSION, Width: 1, Length: 1, HeightMap: [][]float32{{0, 1}, {2, 3}}}
+	if err := d.WriteBinary(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadTerrainData(bytes.NewBuffer(buf.Bytes()[:buf.Len()-2])); err != ErrorInvalidTerrainData {
+		t.Errorf("Invalid error of the truncated binary. Instead of '%v', we have '%v'.", ErrorInvalidTerrainData, err)
+	}
+	// the sizes of the header are bigger than the max size or the content.
+	for _, size := range [][2]uint32{{TERRAIN_DATA_MAX_SIZE + 1, 1}, {1, 0xFFFFFFFF}, {TERRAIN_DATA_MAX_SIZE, TERRAIN_DATA_MAX_SIZE}} {
+		header := terrainDataHeader{Version: TERRAIN_DATA_VERSION, Width: size[0], Length: size[1]}
+		copy(header.Magic[:], terrainDataMagic)
+		var hbuf bytes.Buffer
+		binary.Write(&hbuf, binary.LittleEndian, &header)
+		hbuf.Write([]byte{0, 0, 0, 0})
+		if _, err := ReadTerrainData(&hbuf); err != ErrorInvalidTerrainData {
+			t.Errorf("Invalid error of the size '%v'. Instead of '%v', we have '%v'.", size, ErrorInvalidTerrainData, err)
+		}
+	}
+}
+func TestTerrainBuilderSetTerrainData(t *testing.T) {
+	terr, liquid := testTerrainWithLiquid()
+	d := NewTerrainData(terr, liquid)
+	d.HeightMap[1][1] = 42
+	tb := NewTerrainBuilder()
+	tb.SetGlWrapper(wrapperMock)
+	tb.SetTerrainData(d)
+	if tb.width != 4 || tb.length != 3 || tb.seed != 7 || tb.scale != d.Scale || tb.position != d.Position {
+		t.Error("Invalid builder setup.")
+	}
+	if tb.liquidEta != 0.75 || tb.liquidAmplitude != 0.5 || tb.liquidFrequency != 0.25 || tb.liquidWaterLevel != 1.5 || tb.liquidDetailMultiplier != 2 {
+		t.Error("Invalid liquid setup.")
+	}
+	loaded, loadedLiquid := tb.BuildWithLiquid()
+	if !reflect.DeepEqual(loaded.heightMap, d.HeightMap) {
+		t.Errorf("Invalid height map. Instead of '%v', we have '%v'.", d.HeightMap, loaded.heightMap)
+	}
+	if !reflect.DeepEqual(NewTerrainData(loaded, loadedLiquid), d) {
+		t.Error("The rebuilt terrain should have the same data.")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Build with different size should panic.")
+			}
+		}()
+		tb.SetWidth(8)
+		tb.Build()
+	}()
+	// the data without liquid resets the liquid parameters.
+	d.Liquid = nil
+	tb.SetTerrainData(d)
+	if tb.liquidEta != 0 || tb.liquidAmplitude != 0 || tb.liquidFrequency != 0 || tb.liquidWaterLevel != 0 || tb.liquidDetailMultiplier != 1 {
+		t.Error("The liquid setup should be reset.")
+	}
+}
+func TestTerrainBuilderDoesNotUseGlobalRand(t *testing.T) {
+	rand.Seed(5)
+	expected := rand.Int63()
+	rand.Seed(5)
+	testTerrainWithLiquid()
+	if value := rand.Int63(); value != expected {
+		t.Errorf("The build changed the global random state. Instead of '%d', we have '%d'.", expected, value)
+	}
+	// the same seed gives the same terrain, independently from the global state.
+	first, _ := testTerrainWithLiquid()
+	rand.Seed(99)
+	rand.Int63()
+	second, _ := testTerrainWithLiquid()
+	if !reflect.DeepEqual(first.heightMap, second.heightMap) {
+		t.Error("The same seed should give the same height map.")
+	}
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+)
+
+const (
+	// The max number of the texture layers of a terrain. It is the same as in the terrain shader.
+	MAX_TERRAIN_LAYERS = 4
+	// The height range of the new layers. It covers every reasonable height.
+	unlimitedLayerHeight    = float32(1e9)
+	defaultLayerHeightBlend = float32(0.5)
+	defaultLayerSlopeBlend  = float32(0.05)
+)
+
+// terrainLayer is a texture layer of the terrain. The height range is in world space,
+// the slope is 0 on the flat surface, 1 on the vertical one.
+type terrainLayer struct {
+	filePath             string
+	tiling               float32
+	minHeight, maxHeight float32
+	minSlope, maxSlope   float32
+}
+
+// textureLayers holds the texture layer setup of the terrain builders. The layers are drawn
+// with the terrain shader, that blends them by height and slope or by the splat map.
+type textureLayers struct {
+	layers      []terrainLayer
+	heightBlend float32
+	slopeBlend  float32
+	splatMap    string
+}
+
+// newTextureLayers returns an empty layer setup with the default blend widths.
+func newTextureLayers() textureLayers {
+	return textureLayers{
+		heightBlend: defaultLayerHeightBlend,
+		slopeBlend:  defaultLayerSlopeBlend,
+	}
+}
+
+// AddTextureLayer registers a new texture layer and returns its index. The tiling is the number
+// of the texture repeats in a world unit. The new layer covers every height and slope, the ranges
+// could be set with the SetLayerHeightRange, SetLayerSlopeRange functions. It panics if the
+// terrain already has MAX_TERRAIN_LAYERS layers.
+func (l *textureLayers) AddTextureLayer(filePath string, tiling float32) int {
+	if len(l.layers) >= MAX_TERRAIN_LAYERS {
+		panic(fmt.Sprintf("The terrain could have max %d texture layers.", MAX_TERRAIN_LAYERS))
+	}
+	l.layers = append(l.layers, terrainLayer{
+		filePath:  filePath,
+		tiling:    tiling,
+		minHeight: -unlimitedLayerHeight,
+		maxHeight: unlimitedLayerHeight,
+		minSlope:  0,
+		maxSlope:  1,
+	})
+	return len(l.layers) - 1
+}
+
+// layer returns the layer with the given index. It panics if the index is invalid.
+func (l *textureLayers) layer(index int) *terrainLayer {
+	if index < 0 || index >= len(l.layers) {
+		panic(fmt.Sprintf("Invalid texture layer index '%d'.", index))
+	}
+	return &l.layers[index]
+}
+
+// SetLayerHeightRange updates the world space height range of the given layer.
+func (l *textureLayers) SetLayerHeightRange(index int, minH, maxH float32) {
+	layer := l.layer(index)
+	layer.minHeight = minH
+	layer.maxHeight = maxH
+}
+
+// SetLayerSlopeRange updates the slope range of the given layer. The slope is 0 on the
+// flat surface, 1 on the vertical one.
+func (l *textureLayers) SetLayerSlopeRange(index int, minSlope, maxSlope float32) {
+	layer := l.layer(index)
+	layer.minSlope = minSlope
+	layer.maxSlope = maxSlope
+}
+
+// SetLayerBlend updates the width of the transitions between the layers. The height
+// blend is in world units, the slope blend is in slope units.
+func (l *textureLayers) SetLayerBlend(height, slope float32) {
+	l.heightBlend = height
+	l.slopeBlend = slope
+}
+
+// textures returns the given textures extended with the layer textures and the splat map.
+// The given container is not modified, so that the builder could be used more times.
+func (l *textureLayers) textures(tex texture.Textures, wrapper interfaces.GLWrapper) texture.Textures {
+	result := append(texture.Textures{}, tex...)
+	for i, layer := range l.layers {
+		result.AddTexture(layer.filePath, glwrapper.REPEAT, glwrapper.REPEAT, glwrapper.LINEAR, glwrapper.LINEAR, fmt.Sprintf("layerTexture[%d]", i), wrapper)
+	}
+	if l.splatMap != "" {
+		result.AddTexture(l.splatMap, glwrapper.CLAMP_TO_EDGE, glwrapper.CLAMP_TO_EDGE, glwrapper.LINEAR, glwrapper.LINEAR, "splatMap", wrapper)
+	}
+	return result
+}
+
+// setUniforms sets the layer uniforms of the terrain shader to the model. The size of the splat map
+// (terrainSize uniform) is set by the terrain builder, that supports it.
+func (l *textureLayers) setUniforms(m *Model) {
+	if len(l.layers) == 0 {
+		return
+	}
+	m.SetUniformFloat("numberOfLayers", float32(len(l.layers)))
+	m.SetUniformFloat("layerHeightBlend", l.heightBlend)
+	m.SetUniformFloat("layerSlopeBlend", l.slopeBlend)
+	for i, layer := range l.layers {
+		m.SetUniformFloat(fmt.Sprintf("layerTiling[%d]", i), layer.tiling)
+		m.SetUniformFloat(fmt.Sprintf("layerMinHeight[%d]", i), layer.minHeight)
+		m.SetUniformFloat(fmt.Sprintf("layerMaxHeight[%d]", i), layer.maxHeight)
+		m.SetUniformFloat(fmt.Sprintf("layerMinSlope[%d]", i), layer.minSlope)
+		m.SetUniformFloat(fmt.Sprintf("layerMaxSlope[%d]", i), layer.maxSlope)
+	}
+	useSplatMap := float32(0)
+	if l.splatMap != "" {
+		useSplatMap = 1
+	}
+	m.SetUniformFloat("useSplatMap", useSplatMap)
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	testLayerTexture = "assets/grass.jpg"
+)
+
+func TestTextureLayersAddTextureLayer(t *testing.T) {
+	l := newTextureLayers()
+	if l.heightBlend != defaultLayerHeightBlend || l.slopeBlend != defaultLayerSlopeBlend {
+		t.Errorf("Invalid default blend. We have '%f', '%f'.", l.heightBlend, l.slopeBlend)
+	}
+	for i := 0; i < MAX_TERRAIN_LAYERS; i++ {
+		if index := l.AddTextureLayer(testLayerTexture, 0.5); index != i {
+			t.Errorf("Invalid layer index. Instead of '%d', we have '%d'.", i, index)
+		}
+	}
+	if l.layers[0].tiling != 0.5 || l.layers[0].minSlope != 0 || l.layers[0].maxSlope != 1 {
+		t.Errorf("Invalid layer '%v'.", l.layers[0])
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("AddTextureLayer should have panicked due to the max layers.")
+			}
+		}()
+		l.AddTextureLayer(testLayerTexture, 1)
+	}()
+}
+func TestTextureLayersRanges(t *testing.T) {
+	l := newTextureLayers()
+	index := l.AddTextureLayer(testLayerTexture, 1)
+	l.SetLayerHeightRange(index, 2, 5)
+	l.SetLayerSlopeRange(index, 0.3, 0.8)
+	l.SetLayerBlend(1, 0.1)
+	layer := l.layers[index]
+	if layer.minHeight != 2 || layer.maxHeight != 5 || layer.minSlope != 0.3 || layer.maxSlope != 0.8 {
+		t.Errorf("Invalid layer ranges '%v'.", layer)
+	}
+	if l.heightBlend != 1 || l.slopeBlend != 0.1 {
+		t.Errorf("Invalid blend. We have '%f', '%f'.", l.heightBlend, l.slopeBlend)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("SetLayerHeightRange should have panicked due to the invalid index.")
+			}
+		}()
+		l.SetLayerHeightRange(1, 0, 1)
+	}()
+}
+func TestTerrainBuilderBuildWithLayers(t *testing.T) {
+	tb := NewTerrainBuilder()
+	tb.SetWidth(4)
+	tb.SetLength(6)
+	tb.SetGlWrapper(wrapperMock)
+	sand := tb.AddTextureLayer(testLayerTexture, 0.25)
+	tb.SetLayerHeightRange(sand, -1, 1)
+	tb.AddTextureLayer(testLayerTexture, 0.5)
+	tb.SetSplatMap(testLayerTexture)
+	terr := tb.Build()
+	textures := terr.GetTerrain().(*mesh.TexturedMesh).Textures
+	if len(textures) != 3 || textures[2].UniformName != "splatMap" {
+		t.Errorf("Invalid textures. Instead of 2 layers and the splat map, we have '%v'.", textures)
+	}
+	if len(tb.tex) != 0 {
+		t.Errorf("The builder textures shouldn't be modified. We have '%d'.", len(tb.tex))
+	}
+	expected := map[string]float32{
+		"numberOfLayers":    2,
+		"layerTiling[0]":    0.25,
+		"layerMinHeight[0]": -1,
+		"layerMaxHeight[0]": 1,
+		"layerTiling[1]":    0.5,
+		"useSplatMap":       1,
+	}
+	for name, value := range expected {
+		if terr.uniformFloat[name] != value {
+			t.Errorf("Invalid '%s' uniform. Instead of '%f', we have '%f'.", name, value, terr.uniformFloat[name])
+		}
+	}
+	if terr.uniformVector["terrainSize"] != (mgl32.Vec3{4, 0, 6}) {
+		t.Errorf("Invalid terrain size '%v'.", terr.uniformVector["terrainSize"])
+	}
+}
+func TestChunkedTerrainBuilderBuildWithLayers(t *testing.T) {
+	b := newTestChunkedTerrainBuilder()
+	b.AddTextureLayer(testLayerTexture, 1)
+	terr := b.Build()
+	if len(terr.tex) != 1 || terr.tex[0].UniformName != "layerTexture[0]" {
+		t.Errorf("Invalid textures '%v'.", terr.tex)
+	}
+	if terr.uniformFloat["useSplatMap"] != 0 {
+		t.Error("The splat map shouldn't be used.")
+	}
+	if _, ok := terr.uniformVector["terrainSize"]; ok {
+		t.Error("The size of the splat map shouldn't be set.")
+	}
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The alignments of the printed text lines.
+	ALIGN_LEFT = iota
+	ALIGN_CENTER
+	ALIGN_RIGHT
+)
+
+// glyphPlacement is a glyph of the layout with its pen position.
+type glyphPlacement struct {
+	glyph *Glyph
+	x     float32
+}
+
+// textLine is a line of the layout.
+type textLine struct {
+	glyphs []glyphPlacement
+	width  float32
+	// the start position of the line, relative to the aligned position.
+	offset float32
+}
+
+// layout splits the text to lines and calculates the pen positions of the glyphs with
+// the kerning of the font, if it is enabled. The kerning is applied only between the
+// glyphs of the same font. The missing glyphs are rendered, the control characters are skipped.
+func (c *Charset) layout(text string, scale float32, align int) []textLine {
+	var lines []textLine
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return lines
+	}
+	line := textLine{}
+	var prev rune
+	var prevFont *charsetFont
+	closeLine := func() {
+		switch align {
+		case ALIGN_CENTER:
+			line.offset = -line.width / 2
+			break
+		case ALIGN_RIGHT:
+			line.offset = -line.width
+			break
+		}
+		lines = append(lines, line)
+		line = textLine{}
+		prevFont = nil
+	}
+	for _, r := range runes {
+		if r == '\n' {
+			closeLine()
+			continue
+		}
+		ch := c.glyph(r)
+		if ch == nil {
+			if c.Debug {
+				fmt.Printf("Skipping: %c %d\n", r, r)
+			}
+			continue
+		}
+		if c.Kerning && prevFont == ch.font {
+			line.width += float32(ch.font.face.Kern(prev, r)) / 64 * scale
+		}
+		line.glyphs = append(line.glyphs, glyphPlacement{glyph: ch, x: line.width})
+		line.width += float32(ch.Advance) * scale
+		prev = r
+		prevFont = ch.font
+	}
+	closeLine()
+	return lines
+}
+
+// textVertices returns the vertices and the indices of the text mesh. The glyph quads are in the
+// x-z plane of the surface, the lines go down in the -z direction. The vertices are in the local
+// space of the surface, its rotation is applied by the model transformation of the text mesh.
+func (c *Charset) textVertices(text string, x, y, z, scale float32, align int, cols []mgl32.Vec3) (vertex.Vertices, []uint32) {
+	var vertices vertex.Vertices
+	var indices []uint32
+	if len(cols) == 0 {
+		cols = []mgl32.Vec3{mgl32.Vec3{1, 1, 1}}
+	}
+	glyphIndex := 0
+	for l, line := range c.layout(text, scale, align) {
+		lineY := y - float32(l)*c.LineHeight(scale)
+		for _, p := range line.glyphs {
+			ch := p.glyph
+			imageWidth, imageHeight := ch.imageSize()
+			w := float32(imageWidth) * scale
+			h := float32(imageHeight) * scale
+			position := mgl32.Vec3{
+				x + line.offset + p.x + float32(ch.BearingX+ch.Width/2)*scale,
+				z,
+				lineY - float32(ch.BearingY-ch.Height/2)*scale,
+			}
+			corners := [4]mgl32.Vec3{
+				mgl32.Vec3{-w / 2, 0, -h / 2},
+				mgl32.Vec3{w / 2, 0, -h / 2},
+				mgl32.Vec3{w / 2, 0, h / 2},
+				mgl32.Vec3{-w / 2, 0, h / 2},
+			}
+			textureCoords := [4]mgl32.Vec2{
+				{ch.atlasMin.X(), ch.atlasMax.Y()},
+				{ch.atlasMax.X(), ch.atlasMax.Y()},
+				{ch.atlasMax.X(), ch.atlasMin.Y()},
+				{ch.atlasMin.X(), ch.atlasMin.Y()},
+			}
+			base := uint32(len(vertices))
+			for i := 0; i < 4; i++ {
+				vertices = append(vertices, vertex.Vertex{
+					Position:  position.Add(corners[i]),
+					Color:     cols[glyphIndex%len(cols)],
+					TexCoords: textureCoords[i],
+				})
+			}
+			indices = append(indices, base, base+1, base+2, base, base+2, base+3)
+			glyphIndex++
+		}
+	}
+	return vertices, indices
+}
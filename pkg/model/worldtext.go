@@ -0,0 +1,101 @@
+package model
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// WorldText is a model for the camera facing texts of the 3D scene, like the names above the
+// bugs or lamps. The characters are billboard meshes with the glyph regions of the charset atlas.
+// It has to be drawn with the billboard font shader.
+type WorldText struct {
+	*BaseModel
+	charset *Charset
+	mode    int32
+}
+
+// NewWorldText returns a world text model, that uses the glyphs of the given charset.
+// The default billboard mode is spherical.
+func NewWorldText(c *Charset) *WorldText {
+	wt := &WorldText{
+		BaseModel: New(),
+		charset:   c,
+		mode:      mesh.BILLBOARD_SPHERICAL,
+	}
+	if c.worldTexts == nil {
+		c.worldTexts = make(map[*WorldText]bool)
+	}
+	c.worldTexts[wt] = true
+	return wt
+}
+
+// Release removes the world text from the charset, its texts are not updated after the growth
+// of the atlas. It has to be called when the world text is not used anymore, otherwise
+// the charset keeps it.
+func (wt *WorldText) Release() {
+	delete(wt.charset.worldTexts, wt)
+}
+
+// scaleTextureRegions updates the texture regions of the characters after the growth of the charset atlas.
+func (wt *WorldText) scaleTextureRegions(scale mgl32.Vec2) {
+	for i := range wt.meshes {
+		if bb, ok := wt.meshes[i].(*mesh.BillboardMesh); ok {
+			min, max, _ := bb.GetTextureRegion()
+			bb.SetTextureRegion(mgl32.Vec2{min.X() * scale.X(), min.Y() * scale.Y()}, mgl32.Vec2{max.X() * scale.X(), max.Y() * scale.Y()})
+		}
+	}
+}
+
+// SetMode updates the billboard mode of the texts, that will be printed.
+// It could be mesh.BILLBOARD_SPHERICAL or mesh.BILLBOARD_CYLINDRICAL.
+func (wt *WorldText) SetMode(mode int32) {
+	wt.mode = mode
+}
+
+// SetTextEffects sets the outline, shadow and glow parameters of the texts. They are
+// applied by the billboard sdf font shader.
+func (wt *WorldText) SetTextEffects(e TextEffects) {
+	e.setUniforms(&wt.Model)
+}
+
+// Print sets up the meshes of the given text. The lines of the text are centered to the given position.
+// If the parent mesh is not nil, the position is relative to the parent, so that the
+// text follows it. The scale is the size of a glyph pixel in world space.
+func (wt *WorldText) Print(text string, position mgl32.Vec3, scale float32, wrapper interfaces.GLWrapper, parent interfaces.Mesh, cols []mgl32.Vec3) {
+	lines := wt.charset.layout(text, scale, ALIGN_CENTER)
+	wt.charset.uploadAtlas()
+	for l, line := range lines {
+		lineY := -float32(l) * wt.charset.LineHeight(scale)
+		for _, p := range line.glyphs {
+			ch := p.glyph
+			imageWidth, imageHeight := ch.imageSize()
+			w := float32(imageWidth) * scale
+			h := float32(imageHeight) * scale
+			msh := mesh.NewBillboardMesh(w, h, wt.charset.atlas, cols, wrapper)
+			msh.SetTextureRegion(ch.atlasMin, ch.atlasMax)
+			msh.SetMode(wt.mode)
+			msh.SetOffset(mgl32.Vec2{
+				line.offset + p.x + float32(ch.BearingX)*scale + float32(ch.Width)*scale/2,
+				lineY + float32(ch.Height)*scale/2 - float32(ch.BearingY)*scale,
+			})
+			msh.SetPosition(position)
+			if parent != nil {
+				msh.SetParent(parent)
+			}
+			wt.AddMesh(msh)
+		}
+	}
+}
+
+// CleanParent deletes those printed texts where the parent mesh is the given mesh.
+func (wt *WorldText) CleanParent(msh interfaces.Mesh) {
+	var meshes []interfaces.Mesh
+	for i, _ := range wt.meshes {
+		if wt.meshes[i].GetParent() != msh {
+			meshes = append(meshes, wt.meshes[i])
+		}
+	}
+	wt.meshes = meshes
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestNewWorldText(t *testing.T) {
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	wt := NewWorldText(fonts)
+	if wt.charset != fonts {
+		t.Error("Invalid charset.")
+	}
+	if wt.mode != mesh.BILLBOARD_SPHERICAL {
+		t.Errorf("Invalid mode. Instead of '%d', we have '%d'.", mesh.BILLBOARD_SPHERICAL, wt.mode)
+	}
+	wt.SetMode(mesh.BILLBOARD_CYLINDRICAL)
+	if wt.mode != mesh.BILLBOARD_CYLINDRICAL {
+		t.Errorf("Invalid mode. Instead of '%d', we have '%d'.", mesh.BILLBOARD_CYLINDRICAL, wt.mode)
+	}
+	if !fonts.worldTexts[wt] {
+		t.Error("The world text should be registered in the charset.")
+	}
+	wt.Release()
+	if _, ok := fonts.worldTexts[wt]; ok {
+		t.Error("The released world text should be removed from the charset.")
+	}
+}
+func TestWorldTextPrint(t *testing.T) {
+	cols := []mgl32.Vec3{mgl32.Vec3{0, 0, 1}}
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	wt := NewWorldText(fonts)
+	wt.SetMode(mesh.BILLBOARD_CYLINDRICAL)
+	position := mgl32.Vec3{0, 2, 0}
+	wt.Print("Hello", position, 0.01, wrapperMock, nil, cols)
+	if len(wt.meshes) != 5 {
+		t.Errorf("Invalid number of meshes. Instead of '%d', we have '%d'.", 5, len(wt.meshes))
+	}
+	first := wt.meshes[0].(*mesh.BillboardMesh)
+	last := wt.meshes[4].(*mesh.BillboardMesh)
+	if first.GetMode() != mesh.BILLBOARD_CYLINDRICAL {
+		t.Errorf("Invalid mode. Instead of '%d', we have '%d'.", mesh.BILLBOARD_CYLINDRICAL, first.GetMode())
+	}
+	if first.GetPosition() != position {
+		t.Errorf("Invalid position. Instead of '%v', we have '%v'.", position, first.GetPosition())
+	}
+	// The text is centered, so that the first character is on the left, the last one is on the right side.
+	if first.GetOffset().X() >= 0 || last.GetOffset().X() <= 0 {
+		t.Errorf("The text is not centered. First offset: '%v', last offset: '%v'.", first.GetOffset(), last.GetOffset())
+	}
+}
+func TestWorldTextCleanParent(t *testing.T) {
+	cols := []mgl32.Vec3{mgl32.Vec3{0, 0, 1}}
+	fonts, err := LoadCharset("./assets/fonts/Desyrel/desyrel.ttf", 32, 127, 40, 72, wrapperMock)
+	if err != nil {
+		t.Errorf("Error during load: %s\n", err.Error())
+	}
+	wt := NewWorldText(fonts)
+	msh := mesh.NewPointMesh(wrapperMock)
+	wt.Print("Hello", mgl32.Vec3{0, 1, 0}, 0.01, wrapperMock, msh, cols)
+	wt.Print("Bug", mgl32.Vec3{0, 1, 0}, 0.01, wrapperMock, nil, cols)
+	if len(wt.meshes) != 8 {
+		t.Errorf("Invalid number of meshes. Instead of '%d', we have '%d'.", 8, len(wt.meshes))
+	}
+	wt.CleanParent(msh)
+	if len(wt.meshes) != 3 {
+		t.Errorf("Invalid number of meshes. Instead of '%d', we have '%d'.", 3, len(wt.meshes))
+	}
+}
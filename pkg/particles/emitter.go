@@ -0,0 +1,169 @@
+package particles
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Emitter describes the shape that the particles are born from.
+// The Emit function returns the position of the new particle relative to the
+// emitter origin and the unit direction of its initial velocity.
+type Emitter interface {
+	Emit(r *rand.Rand) (mgl32.Vec3, mgl32.Vec3)
+}
+
+// randomDirectionInCone returns a random unit vector around the given axis.
+// The spread is the half angle of the cone in degrees. In case of zero length
+// axis it returns a random direction from the whole sphere.
+func randomDirectionInCone(r *rand.Rand, axis mgl32.Vec3, spread float32) mgl32.Vec3 {
+	if axis.Len() == 0 {
+		return randomUnitVector(r)
+	}
+	axis = axis.Normalize()
+	// random point on the spherical cap around the z axis, then rotate it to the axis.
+	cosMax := float32(math.Cos(float64(mgl32.DegToRad(spread))))
+	z := cosMax + r.Float32()*(1-cosMax)
+	phi := r.Float32() * 2 * math.Pi
+	sinTheta := float32(math.Sqrt(float64(1 - z*z)))
+	local := mgl32.Vec3{sinTheta * float32(math.Cos(float64(phi))), sinTheta * float32(math.Sin(float64(phi))), z}
+	return mgl32.QuatBetweenVectors(mgl32.Vec3{0, 0, 1}, axis).Rotate(local)
+}
+
+// randomUnitVector returns a uniformly distributed random unit vector.
+func randomUnitVector(r *rand.Rand) mgl32.Vec3 {
+	z := r.Float32()*2 - 1
+	phi := r.Float32() * 2 * math.Pi
+	sinTheta := float32(math.Sqrt(float64(1 - z*z)))
+	return mgl32.Vec3{sinTheta * float32(math.Cos(float64(phi))), sinTheta * float32(math.Sin(float64(phi))), z}
+}
+
+// PointEmitter emits every particle from the origin. The direction of the
+// particles are in the cone that is described by the Direction and the Spread (degrees).
+// With zero Direction the particles are emitted to every direction.
+type PointEmitter struct {
+	Direction mgl32.Vec3
+	Spread    float32
+}
+
+// NewPointEmitter returns a point emitter with the given direction and spread.
+func NewPointEmitter(direction mgl32.Vec3, spread float32) *PointEmitter {
+	return &PointEmitter{
+		Direction: direction,
+		Spread:    spread,
+	}
+}
+
+// Emit returns the origin and a random direction from the cone.
+func (e *PointEmitter) Emit(r *rand.Rand) (mgl32.Vec3, mgl32.Vec3) {
+	return mgl32.Vec3{0, 0, 0}, randomDirectionInCone(r, e.Direction, e.Spread)
+}
+
+// SphereEmitter emits the particles from the surface of a sphere, or
+// from its volume if the Volume flag is set. The particles are moving outwards.
+type SphereEmitter struct {
+	Radius float32
+	Volume bool
+}
+
+// NewSphereEmitter returns a sphere emitter with the given radius.
+func NewSphereEmitter(radius float32, volume bool) *SphereEmitter {
+	return &SphereEmitter{
+		Radius: radius,
+		Volume: volume,
+	}
+}
+
+// Emit returns a random point of the sphere and the outward direction.
+func (e *SphereEmitter) Emit(r *rand.Rand) (mgl32.Vec3, mgl32.Vec3) {
+	direction := randomUnitVector(r)
+	radius := e.Radius
+	if e.Volume {
+		radius = radius * float32(math.Cbrt(float64(r.Float32())))
+	}
+	return direction.Mul(radius), direction
+}
+
+// BoxEmitter emits the particles from the volume of an axis aligned box,
+// that is centered to the origin. The Size is the full width, height, length of the box.
+// The direction is calculated the same way as in the PointEmitter. It could be used
+// for rain or snow with a flat box above the scene.
+type BoxEmitter struct {
+	Size      mgl32.Vec3
+	Direction mgl32.Vec3
+	Spread    float32
+}
+
+// NewBoxEmitter returns a box emitter with the given size, direction and spread.
+func NewBoxEmitter(size, direction mgl32.Vec3, spread float32) *BoxEmitter {
+	return &BoxEmitter{
+		Size:      size,
+		Direction: direction,
+		Spread:    spread,
+	}
+}
+
+// Emit returns a random point of the box and a random direction from the cone.
+func (e *BoxEmitter) Emit(r *rand.Rand) (mgl32.Vec3, mgl32.Vec3) {
+	position := mgl32.Vec3{
+		(r.Float32() - 0.5) * e.Size.X(),
+		(r.Float32() - 0.5) * e.Size.Y(),
+		(r.Float32() - 0.5) * e.Size.Z(),
+	}
+	return position, randomDirectionInCone(r, e.Direction, e.Spread)
+}
+
+// MeshSurfaceEmitter emits the particles from the surface of a triangle mesh.
+// The triangles are chosen with probability proportional to their area, the
+// particles are moving to the direction of the triangle normal, with the given spread.
+type MeshSurfaceEmitter struct {
+	vertices vertex.Vertices
+	indices  []uint32
+	// the cumulated area of the triangles, used for the weighted selection.
+	areas  []float32
+	Spread float32
+}
+
+// NewMeshSurfaceEmitter returns a mesh surface emitter. Its inputs are the vertices and the
+// indices of the mesh, as they are used in the mesh constructors. Every 3 indices describes a triangle.
+// It panics if there isn't any triangle.
+func NewMeshSurfaceEmitter(v vertex.Vertices, i []uint32, spread float32) *MeshSurfaceEmitter {
+	if len(i) < 3 {
+		panic("The mesh surface emitter needs at least one triangle.")
+	}
+	e := &MeshSurfaceEmitter{
+		vertices: v,
+		indices:  i,
+		Spread:   spread,
+	}
+	sum := float32(0.0)
+	for t := 0; t+2 < len(i); t += 3 {
+		a, b, c := v[i[t]].Position, v[i[t+1]].Position, v[i[t+2]].Position
+		sum += b.Sub(a).Cross(c.Sub(a)).Len() / 2
+		e.areas = append(e.areas, sum)
+	}
+	return e
+}
+
+// Emit returns a random point of a random triangle and the normal direction of the triangle.
+func (e *MeshSurfaceEmitter) Emit(r *rand.Rand) (mgl32.Vec3, mgl32.Vec3) {
+	target := r.Float32() * e.areas[len(e.areas)-1]
+	triangle := 0
+	for triangle < len(e.areas)-1 && e.areas[triangle] < target {
+		triangle++
+	}
+	a := e.vertices[e.indices[triangle*3]].Position
+	b := e.vertices[e.indices[triangle*3+1]].Position
+	c := e.vertices[e.indices[triangle*3+2]].Position
+	// uniform random barycentric coordinates
+	u, v := r.Float32(), r.Float32()
+	if u+v > 1 {
+		u, v = 1-u, 1-v
+	}
+	position := a.Add(b.Sub(a).Mul(u)).Add(c.Sub(a).Mul(v))
+	normal := b.Sub(a).Cross(c.Sub(a))
+	return position, randomDirectionInCone(r, normal, e.Spread)
+}
@@ -0,0 +1,445 @@
+package particles
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+
+	"github.com/akosgarai/coldet"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// The particles are blended with the common alpha blending. It is the default mode.
+	BLEND_ALPHA = iota
+	// The particles are blended with additive blending. It could be used for fire, sparks.
+	BLEND_ADDITIVE
+)
+
+const (
+	// center (3) + corner (2) + color (4) + size (1)
+	floatsPerVertex   = 10
+	verticesPerSprite = 6
+)
+
+// The corners of the camera facing quad, as 2 triangles.
+var spriteCorners = [verticesPerSprite]mgl32.Vec2{
+	{-0.5, -0.5}, {0.5, -0.5}, {0.5, 0.5},
+	{0.5, 0.5}, {-0.5, 0.5}, {-0.5, -0.5},
+}
+
+// Particle is the state of a simulated particle. The age and the
+// lifetime are in ms, the velocity is the movement in a ms.
+type Particle struct {
+	Position mgl32.Vec3
+	Velocity mgl32.Vec3
+	Age      float32
+	Lifetime float32
+}
+
+// MeshContainer is implemented by the models, that can return their meshes.
+// The particle system could be attached to the first (root) mesh of these models.
+type MeshContainer interface {
+	GetMeshByIndex(int) (interfaces.Mesh, error)
+}
+
+// System is a pooled cpu side particle simulation. The particles are emitted
+// by the emitter, they are moving with their velocity and the gravity, their
+// color and size are interpolated between the start and end values during their life.
+// It implements the interfaces.Model, so that it could be added to a screen with
+// the AddModelToShader function. The shader has to be the particle shader.
+type System struct {
+	emitter      Emitter
+	pool         []Particle
+	alive        int
+	emissionRate float32
+	// the fraction of the particle that has to be emitted in the next update.
+	emissionDebt float32
+	emitting     bool
+	lifetimeMin  float32
+	lifetimeMax  float32
+	speedMin     float32
+	speedMax     float32
+	gravity      mgl32.Vec3
+	startColor   mgl32.Vec4
+	endColor     mgl32.Vec4
+	startSize    float32
+	endSize      float32
+	blendMode    int
+	rng          *rand.Rand
+
+	position mgl32.Vec3
+	parent   interfaces.Mesh
+
+	wrapper    interfaces.GLWrapper
+	vao        uint32
+	vbo        uint32
+	vertexData []float32
+}
+
+// Builder is the builder of the particle System.
+type Builder struct {
+	emitter      Emitter
+	maxParticles int
+	emissionRate float32
+	lifetimeMin  float32
+	lifetimeMax  float32
+	speedMin     float32
+	speedMax     float32
+	gravity      mgl32.Vec3
+	startColor   mgl32.Vec4
+	endColor     mgl32.Vec4
+	startSize    float32
+	endSize      float32
+	blendMode    int
+	seed         int64
+	position     mgl32.Vec3
+	wrapper      interfaces.GLWrapper
+}
+
+// NewBuilder returns a particle system builder with default values.
+// The default emitter is a point emitter that emits to every direction.
+func NewBuilder() *Builder {
+	return &Builder{
+		emitter:      NewPointEmitter(mgl32.Vec3{0, 0, 0}, 0),
+		maxParticles: 1000,
+		emissionRate: 100,
+		lifetimeMin:  1000,
+		lifetimeMax:  2000,
+		speedMin:     0.001,
+		speedMax:     0.002,
+		gravity:      mgl32.Vec3{0, 0, 0},
+		startColor:   mgl32.Vec4{1, 1, 1, 1},
+		endColor:     mgl32.Vec4{1, 1, 1, 0},
+		startSize:    0.1,
+		endSize:      0.1,
+		blendMode:    BLEND_ALPHA,
+		seed:         0,
+		position:     mgl32.Vec3{0, 0, 0},
+		wrapper:      nil,
+	}
+}
+
+// SetEmitter updates the emitter.
+func (b *Builder) SetEmitter(e Emitter) {
+	b.emitter = e
+}
+
+// SetMaxParticles updates the size of the particle pool. When every particle is alive,
+// the new particles are not emitted.
+func (b *Builder) SetMaxParticles(n int) {
+	b.maxParticles = n
+}
+
+// SetEmissionRate updates the number of particles that are emitted in a second.
+func (b *Builder) SetEmissionRate(r float32) {
+	b.emissionRate = r
+}
+
+// SetLifetime updates the min and max lifetime (ms) of the particles.
+func (b *Builder) SetLifetime(min, max float32) {
+	b.lifetimeMin = min
+	b.lifetimeMax = max
+}
+
+// SetSpeed updates the min and max initial speed of the particles.
+func (b *Builder) SetSpeed(min, max float32) {
+	b.speedMin = min
+	b.speedMax = max
+}
+
+// SetGravity updates the acceleration that is applied to every particle.
+func (b *Builder) SetGravity(g mgl32.Vec3) {
+	b.gravity = g
+}
+
+// SetColor updates the color of the particles at the beginning and at the end of their life.
+func (b *Builder) SetColor(start, end mgl32.Vec4) {
+	b.startColor = start
+	b.endColor = end
+}
+
+// SetSize updates the size of the particles at the beginning and at the end of their life.
+func (b *Builder) SetSize(start, end float32) {
+	b.startSize = start
+	b.endSize = end
+}
+
+// SetBlendMode updates the blend mode. It could be BLEND_ALPHA or BLEND_ADDITIVE.
+func (b *Builder) SetBlendMode(mode int) {
+	b.blendMode = mode
+}
+
+// SetSeed updates the seed of the random generator of the system.
+func (b *Builder) SetSeed(seed int64) {
+	b.seed = seed
+}
+
+// SetPosition updates the position of the emitter origin.
+func (b *Builder) SetPosition(p mgl32.Vec3) {
+	b.position = p
+}
+
+// SetWrapper sets the wrapper.
+func (b *Builder) SetWrapper(w interfaces.GLWrapper) {
+	b.wrapper = w
+}
+
+// Build returns the particle system. It panics if the wrapper is missing or the pool size is not positive.
+func (b *Builder) Build() *System {
+	if b.wrapper == nil {
+		panic("Wrapper is missing for the build process.")
+	}
+	if b.maxParticles <= 0 {
+		panic("The max particles has to be positive.")
+	}
+	s := &System{
+		emitter:      b.emitter,
+		pool:         make([]Particle, b.maxParticles),
+		alive:        0,
+		emissionRate: b.emissionRate,
+		emitting:     true,
+		lifetimeMin:  b.lifetimeMin,
+		lifetimeMax:  b.lifetimeMax,
+		speedMin:     b.speedMin,
+		speedMax:     b.speedMax,
+		gravity:      b.gravity,
+		startColor:   b.startColor,
+		endColor:     b.endColor,
+		startSize:    b.startSize,
+		endSize:      b.endSize,
+		blendMode:    b.blendMode,
+		rng:          rand.New(rand.NewSource(b.seed)),
+		position:     b.position,
+		wrapper:      b.wrapper,
+		vertexData:   make([]float32, b.maxParticles*verticesPerSprite*floatsPerVertex),
+	}
+	s.setup()
+	return s
+}
+
+// setup creates the vao, vbo and the vertex attributes. The buffer data is uploaded in every Draw call.
+func (s *System) setup() {
+	s.vao = s.wrapper.GenVertexArrays()
+	s.vbo = s.wrapper.GenBuffers()
+
+	s.wrapper.BindVertexArray(s.vao)
+	s.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, s.vbo)
+	s.wrapper.ArrayBufferData(s.vertexData)
+
+	// setup center coordinates
+	s.wrapper.VertexAttribPointer(0, 3, glwrapper.FLOAT, false, 4*floatsPerVertex, s.wrapper.PtrOffset(0))
+	// setup corner offset
+	s.wrapper.VertexAttribPointer(1, 2, glwrapper.FLOAT, false, 4*floatsPerVertex, s.wrapper.PtrOffset(4*3))
+	// setup color vector
+	s.wrapper.VertexAttribPointer(2, 4, glwrapper.FLOAT, false, 4*floatsPerVertex, s.wrapper.PtrOffset(4*5))
+	// setup size
+	s.wrapper.VertexAttribPointer(3, 1, glwrapper.FLOAT, false, 4*floatsPerVertex, s.wrapper.PtrOffset(4*9))
+
+	// close
+	s.wrapper.BindVertexArray(0)
+}
+
+// AttachTo sets the parent mesh of the system. The position of the system
+// is handled in the model space of the parent, so that the emitter follows it.
+func (s *System) AttachTo(parent interfaces.Mesh) {
+	s.parent = parent
+}
+
+// AttachToModel attaches the system to the first mesh of the given model. It panics if the model is empty.
+func (s *System) AttachToModel(m MeshContainer) {
+	msh, err := m.GetMeshByIndex(0)
+	if err != nil {
+		panic(err)
+	}
+	s.AttachTo(msh)
+}
+
+// Detach removes the parent mesh.
+func (s *System) Detach() {
+	s.parent = nil
+}
+
+// SetPosition updates the position of the emitter origin. If the system is attached, it is in the parent's space.
+func (s *System) SetPosition(p mgl32.Vec3) {
+	s.position = p
+}
+
+// GetPosition returns the position of the emitter origin.
+func (s *System) GetPosition() mgl32.Vec3 {
+	return s.position
+}
+
+// SetEmitting starts or stops the emission. The living particles are simulated until the end of their life.
+func (s *System) SetEmitting(e bool) {
+	s.emitting = e
+}
+
+// IsEmitting returns true if the system emits new particles.
+func (s *System) IsEmitting() bool {
+	return s.emitting
+}
+
+// SetEmitter updates the emitter of the system.
+func (s *System) SetEmitter(e Emitter) {
+	s.emitter = e
+}
+
+// AliveParticles returns the number of the living particles.
+func (s *System) AliveParticles() int {
+	return s.alive
+}
+
+// GetParticles returns the living particles. The returned slice is the part of
+// the pool, it is valid until the next Update call.
+func (s *System) GetParticles() []Particle {
+	return s.pool[:s.alive]
+}
+
+// origin returns the emitter origin and its transformation in world space.
+func (s *System) origin() (mgl32.Vec3, mgl32.Mat4) {
+	if s.parent == nil {
+		return s.position, mgl32.Ident4()
+	}
+	transformation := s.parent.ModelTransformation()
+	return mgl32.TransformCoordinate(s.position, transformation), transformation
+}
+
+// Burst emits n particles immediately, if the pool has enough space.
+func (s *System) Burst(n int) {
+	origin, transformation := s.origin()
+	for i := 0; i < n; i++ {
+		s.emit(origin, transformation)
+	}
+}
+
+// emit initializes a dead particle of the pool. If every particle is alive, it does nothing.
+func (s *System) emit(origin mgl32.Vec3, transformation mgl32.Mat4) {
+	if s.alive >= len(s.pool) {
+		return
+	}
+	position, direction := s.emitter.Emit(s.rng)
+	speed := s.speedMin + s.rng.Float32()*(s.speedMax-s.speedMin)
+	s.pool[s.alive] = Particle{
+		Position: origin.Add(mgl32.TransformNormal(position, transformation)),
+		Velocity: mgl32.TransformNormal(direction, transformation).Normalize().Mul(speed),
+		Age:      0,
+		Lifetime: s.lifetimeMin + s.rng.Float32()*(s.lifetimeMax-s.lifetimeMin),
+	}
+	s.alive++
+}
+
+// Update emits the new particles and simulates the living ones. The dead
+// particles are swapped to the end of the pool, so that the living ones are always
+// at the beginning. The dt is in ms.
+func (s *System) Update(dt float64) {
+	delta := float32(dt)
+	for i := 0; i < s.alive; {
+		p := &s.pool[i]
+		p.Age += delta
+		if p.Age >= p.Lifetime {
+			s.alive--
+			s.pool[i], s.pool[s.alive] = s.pool[s.alive], s.pool[i]
+			continue
+		}
+		p.Velocity = p.Velocity.Add(s.gravity.Mul(delta))
+		p.Position = p.Position.Add(p.Velocity.Mul(delta))
+		i++
+	}
+	if !s.emitting {
+		return
+	}
+	s.emissionDebt += s.emissionRate * delta / 1000.0
+	newParticles := int(math.Floor(float64(s.emissionDebt)))
+	s.emissionDebt -= float32(newParticles)
+	if newParticles > 0 {
+		s.Burst(newParticles)
+	}
+}
+
+// Draw function is responsible for the actual drawing. It's input is a shader.
+// It builds the vertex data of the living particles and uploads it to the vbo.
+// The particles are in world space, so that the model uniform is the identity.
+// The depth buffer is not written, because the particles are transparent.
+func (s *System) Draw(shader interfaces.Shader) {
+	if s.alive == 0 {
+		return
+	}
+	for i := 0; i < s.alive; i++ {
+		p := s.pool[i]
+		t := p.Age / p.Lifetime
+		color := s.startColor.Add(s.endColor.Sub(s.startColor).Mul(t))
+		size := s.startSize + (s.endSize-s.startSize)*t
+		for c := 0; c < verticesPerSprite; c++ {
+			offset := (i*verticesPerSprite + c) * floatsPerVertex
+			s.vertexData[offset] = p.Position.X()
+			s.vertexData[offset+1] = p.Position.Y()
+			s.vertexData[offset+2] = p.Position.Z()
+			s.vertexData[offset+3] = spriteCorners[c].X()
+			s.vertexData[offset+4] = spriteCorners[c].Y()
+			s.vertexData[offset+5] = color.X()
+			s.vertexData[offset+6] = color.Y()
+			s.vertexData[offset+7] = color.Z()
+			s.vertexData[offset+8] = color.W()
+			s.vertexData[offset+9] = size
+		}
+	}
+	shader.SetUniformMat4("model", mgl32.Ident4())
+	s.wrapper.BindVertexArray(s.vao)
+	s.wrapper.BindBuffer(glwrapper.ARRAY_BUFFER, s.vbo)
+	s.wrapper.ArrayBufferData(s.vertexData[:s.alive*verticesPerSprite*floatsPerVertex])
+
+	s.wrapper.Enable(glwrapper.BLEND)
+	if s.blendMode == BLEND_ADDITIVE {
+		s.wrapper.BlendFunc(glwrapper.SRC_APLHA, glwrapper.ONE)
+	} else {
+		s.wrapper.BlendFunc(glwrapper.SRC_APLHA, glwrapper.ONE_MINUS_SRC_ALPHA)
+	}
+	s.wrapper.DepthMask(false)
+	s.wrapper.DrawArrays(glwrapper.TRIANGLES, 0, int32(s.alive*verticesPerSprite))
+	s.wrapper.DepthMask(true)
+	s.wrapper.BlendFunc(glwrapper.SRC_APLHA, glwrapper.ONE_MINUS_SRC_ALPHA)
+
+	s.wrapper.BindVertexArray(0)
+}
+
+// Export does nothing, the particles are not exported.
+func (s *System) Export(path string) {}
+
+// CollideTestWithSphere returns false, the particles are not colliding.
+func (s *System) CollideTestWithSphere(boundingSphere *coldet.Sphere) bool {
+	return false
+}
+
+// IsTransparent returns true, so that the particles are drawn after the non transparent models.
+func (s *System) IsTransparent() bool {
+	return true
+}
+
+// ClosestMeshTo returns nil mesh and max float distance, because the system doesn't contain meshes.
+func (s *System) ClosestMeshTo(position mgl32.Vec3) (interfaces.Mesh, float32) {
+	return nil, float32(math.MaxFloat32)
+}
+
+// Clear kills every particle.
+func (s *System) Clear() {
+	s.alive = 0
+	s.emissionDebt = 0
+}
+
+// SetSpeed does nothing. The movement of the system follows its parent.
+func (s *System) SetSpeed(speed float32) {}
+
+// SetDirection does nothing. The movement of the system follows its parent.
+func (s *System) SetDirection(dir mgl32.Vec3) {}
+
+// AddMesh does nothing, the system doesn't contain meshes.
+func (s *System) AddMesh(m interfaces.Mesh) {}
+
+// RotateX does nothing, the particles are camera facing.
+func (s *System) RotateX(angleDeg float32) {}
+
+// RotateY does nothing, the particles are camera facing.
+func (s *System) RotateY(angleDeg float32) {}
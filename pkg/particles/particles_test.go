@@ -0,0 +1,287 @@
+package particles
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/model"
+	"github.com/akosgarai/playground_engine/pkg/primitives/vertex"
+	"github.com/akosgarai/playground_engine/pkg/testhelper"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var (
+	wrapperMock testhelper.GLWrapperMock
+)
+
+func newTestSystem() *System {
+	b := NewBuilder()
+	b.SetWrapper(wrapperMock)
+	b.SetMaxParticles(10)
+	b.SetEmissionRate(100)
+	b.SetLifetime(1000, 1000)
+	b.SetSpeed(0.01, 0.01)
+	return b.Build()
+}
+func TestNewBuilder(t *testing.T) {
+	b := NewBuilder()
+	if b.maxParticles != 1000 {
+		t.Errorf("Invalid default max particles. Instead of '1000', we have '%d'.", b.maxParticles)
+	}
+	if b.blendMode != BLEND_ALPHA {
+		t.Errorf("Invalid default blend mode. Instead of '%d', we have '%d'.", BLEND_ALPHA, b.blendMode)
+	}
+	if b.wrapper != nil {
+		t.Error("The default wrapper should be nil.")
+	}
+}
+func TestBuilderSetters(t *testing.T) {
+	b := NewBuilder()
+	e := NewSphereEmitter(2, false)
+	b.SetEmitter(e)
+	if b.emitter != e {
+		t.Error("Invalid emitter.")
+	}
+	b.SetMaxParticles(5)
+	if b.maxParticles != 5 {
+		t.Errorf("Invalid max particles. Instead of '5', we have '%d'.", b.maxParticles)
+	}
+	b.SetEmissionRate(3)
+	if b.emissionRate != 3 {
+		t.Errorf("Invalid emission rate. Instead of '3', we have '%f'.", b.emissionRate)
+	}
+	b.SetLifetime(10, 20)
+	if b.lifetimeMin != 10 || b.lifetimeMax != 20 {
+		t.Errorf("Invalid lifetime. Instead of '10, 20', we have '%f, %f'.", b.lifetimeMin, b.lifetimeMax)
+	}
+	b.SetSpeed(1, 2)
+	if b.speedMin != 1 || b.speedMax != 2 {
+		t.Errorf("Invalid speed. Instead of '1, 2', we have '%f, %f'.", b.speedMin, b.speedMax)
+	}
+	g := mgl32.Vec3{0, -1, 0}
+	b.SetGravity(g)
+	if b.gravity != g {
+		t.Errorf("Invalid gravity. Instead of '%v', we have '%v'.", g, b.gravity)
+	}
+	c1 := mgl32.Vec4{1, 0, 0, 1}
+	c2 := mgl32.Vec4{0, 0, 1, 0}
+	b.SetColor(c1, c2)
+	if b.startColor != c1 || b.endColor != c2 {
+		t.Errorf("Invalid color. Instead of '%v, %v', we have '%v, %v'.", c1, c2, b.startColor, b.endColor)
+	}
+	b.SetSize(1, 3)
+	if b.startSize != 1 || b.endSize != 3 {
+		t.Errorf("Invalid size. Instead of '1, 3', we have '%f, %f'.", b.startSize, b.endSize)
+	}
+	b.SetBlendMode(BLEND_ADDITIVE)
+	if b.blendMode != BLEND_ADDITIVE {
+		t.Errorf("Invalid blend mode. Instead of '%d', we have '%d'.", BLEND_ADDITIVE, b.blendMode)
+	}
+	b.SetSeed(42)
+	if b.seed != 42 {
+		t.Errorf("Invalid seed. Instead of '42', we have '%d'.", b.seed)
+	}
+	p := mgl32.Vec3{1, 2, 3}
+	b.SetPosition(p)
+	if b.position != p {
+		t.Errorf("Invalid position. Instead of '%v', we have '%v'.", p, b.position)
+	}
+}
+func TestBuildPanicsWithoutWrapper(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Build should have panicked due to the missing wrapper.")
+			}
+		}()
+		b := NewBuilder()
+		b.Build()
+	}()
+}
+func TestUpdateEmission(t *testing.T) {
+	s := newTestSystem()
+	s.Update(50)
+	if s.AliveParticles() != 5 {
+		t.Errorf("Invalid number of particles. Instead of '5', we have '%d'.", s.AliveParticles())
+	}
+	// the pool is full, the new ones are not emitted.
+	s.Update(100)
+	if s.AliveParticles() != 10 {
+		t.Errorf("Invalid number of particles. Instead of '10', we have '%d'.", s.AliveParticles())
+	}
+	s.SetEmitting(false)
+	if s.IsEmitting() {
+		t.Error("The system shouldn't be emitting.")
+	}
+	// every particle is older than its lifetime.
+	s.Update(1000)
+	if s.AliveParticles() != 0 {
+		t.Errorf("Invalid number of particles. Instead of '0', we have '%d'.", s.AliveParticles())
+	}
+}
+func TestUpdateMovement(t *testing.T) {
+	s := newTestSystem()
+	s.SetEmitting(false)
+	s.gravity = mgl32.Vec3{0, -0.001, 0}
+	s.Burst(1)
+	p := s.GetParticles()[0]
+	s.Update(10)
+	moved := s.GetParticles()[0]
+	expectedVelocity := p.Velocity.Add(mgl32.Vec3{0, -0.01, 0})
+	if moved.Velocity.Sub(expectedVelocity).Len() > 0.0001 {
+		t.Errorf("Invalid velocity. Instead of '%v', we have '%v'.", expectedVelocity, moved.Velocity)
+	}
+	expectedPosition := p.Position.Add(expectedVelocity.Mul(10))
+	if moved.Position.Sub(expectedPosition).Len() > 0.0001 {
+		t.Errorf("Invalid position. Instead of '%v', we have '%v'.", expectedPosition, moved.Position)
+	}
+}
+func TestBurstAndClear(t *testing.T) {
+	s := newTestSystem()
+	s.Burst(20)
+	if s.AliveParticles() != 10 {
+		t.Errorf("Invalid number of particles. Instead of '10', we have '%d'.", s.AliveParticles())
+	}
+	s.Clear()
+	if s.AliveParticles() != 0 {
+		t.Errorf("Invalid number of particles. Instead of '0', we have '%d'.", s.AliveParticles())
+	}
+}
+func TestAttachTo(t *testing.T) {
+	s := newTestSystem()
+	parent := mesh.NewPointMesh(wrapperMock)
+	parentPosition := mgl32.Vec3{5, 0, 0}
+	parent.SetPosition(parentPosition)
+	s.AttachTo(parent)
+	s.Burst(1)
+	if !s.GetParticles()[0].Position.ApproxEqual(parentPosition) {
+		t.Errorf("Invalid particle position. Instead of '%v', we have '%v'.", parentPosition, s.GetParticles()[0].Position)
+	}
+	s.Detach()
+	s.Clear()
+	s.Burst(1)
+	if !s.GetParticles()[0].Position.ApproxEqual(mgl32.Vec3{0, 0, 0}) {
+		t.Errorf("Invalid particle position. Instead of '%v', we have '%v'.", mgl32.Vec3{0, 0, 0}, s.GetParticles()[0].Position)
+	}
+}
+func TestAttachToModel(t *testing.T) {
+	s := newTestSystem()
+	m := model.New()
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("AttachToModel should have panicked due to the empty model.")
+			}
+		}()
+		s.AttachToModel(m)
+	}()
+	parent := mesh.NewPointMesh(wrapperMock)
+	m.AddMesh(parent)
+	s.AttachToModel(m)
+	if s.parent != parent {
+		t.Error("Invalid parent mesh.")
+	}
+}
+func TestDraw(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("Draw shouldn't have panicked. '%v'.", r)
+			}
+		}()
+		s := newTestSystem()
+		var shader testhelper.ShaderMock
+		s.Draw(shader)
+		s.Burst(3)
+		s.Draw(shader)
+	}()
+}
+func TestModelInterfaceFunctions(t *testing.T) {
+	s := newTestSystem()
+	if !s.IsTransparent() {
+		t.Error("The particle system should be transparent.")
+	}
+	if s.CollideTestWithSphere(nil) {
+		t.Error("The particle system shouldn't collide.")
+	}
+	msh, dist := s.ClosestMeshTo(mgl32.Vec3{0, 0, 0})
+	if msh != nil || dist != float32(math.MaxFloat32) {
+		t.Errorf("Invalid closest mesh. We have '%v', '%f'.", msh, dist)
+	}
+}
+func TestPointEmitter(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	e := NewPointEmitter(mgl32.Vec3{0, 1, 0}, 10)
+	minCos := float32(math.Cos(float64(mgl32.DegToRad(10)))) - 0.0001
+	for i := 0; i < 100; i++ {
+		pos, dir := e.Emit(r)
+		if pos != (mgl32.Vec3{0, 0, 0}) {
+			t.Errorf("Invalid position. Instead of origin, we have '%v'.", pos)
+		}
+		if dir.Dot(mgl32.Vec3{0, 1, 0}) < minCos {
+			t.Errorf("Direction '%v' is out of the cone.", dir)
+		}
+	}
+}
+func TestSphereEmitter(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	e := NewSphereEmitter(2, false)
+	for i := 0; i < 100; i++ {
+		pos, dir := e.Emit(r)
+		if math.Abs(float64(pos.Len()-2)) > 0.0001 {
+			t.Errorf("Position '%v' is not on the sphere surface.", pos)
+		}
+		if pos.Normalize().Sub(dir).Len() > 0.0001 {
+			t.Errorf("Direction '%v' is not outwards.", dir)
+		}
+	}
+	e.Volume = true
+	for i := 0; i < 100; i++ {
+		pos, _ := e.Emit(r)
+		if pos.Len() > 2.0001 {
+			t.Errorf("Position '%v' is out of the sphere.", pos)
+		}
+	}
+}
+func TestBoxEmitter(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	e := NewBoxEmitter(mgl32.Vec3{2, 0, 4}, mgl32.Vec3{0, -1, 0}, 0)
+	for i := 0; i < 100; i++ {
+		pos, dir := e.Emit(r)
+		if pos.X() < -1 || pos.X() > 1 || pos.Y() != 0 || pos.Z() < -2 || pos.Z() > 2 {
+			t.Errorf("Position '%v' is out of the box.", pos)
+		}
+		if dir.Sub(mgl32.Vec3{0, -1, 0}).Len() > 0.0001 {
+			t.Errorf("Invalid direction. Instead of '%v', we have '%v'.", mgl32.Vec3{0, -1, 0}, dir)
+		}
+	}
+}
+func TestMeshSurfaceEmitter(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("NewMeshSurfaceEmitter should have panicked due to the missing triangles.")
+			}
+		}()
+		NewMeshSurfaceEmitter(vertex.Vertices{}, []uint32{}, 0)
+	}()
+	r := rand.New(rand.NewSource(0))
+	v := vertex.Vertices{
+		vertex.Vertex{Position: mgl32.Vec3{0, 0, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{1, 0, 0}},
+		vertex.Vertex{Position: mgl32.Vec3{0, 1, 0}},
+	}
+	e := NewMeshSurfaceEmitter(v, []uint32{0, 1, 2}, 0)
+	for i := 0; i < 100; i++ {
+		pos, dir := e.Emit(r)
+		if pos.X() < 0 || pos.Y() < 0 || pos.X()+pos.Y() > 1.0001 || pos.Z() != 0 {
+			t.Errorf("Position '%v' is out of the triangle.", pos)
+		}
+		if dir.Sub(mgl32.Vec3{0, 0, 1}).Len() > 0.0001 {
+			t.Errorf("Invalid direction. Instead of '%v', we have '%v'.", mgl32.Vec3{0, 0, 1}, dir)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/akosgarai/playground_engine/pkg/config"
 	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/input"
 	"github.com/akosgarai/playground_engine/pkg/interfaces"
 	"github.com/akosgarai/playground_engine/pkg/material"
 	"github.com/akosgarai/playground_engine/pkg/mesh"
@@ -20,6 +21,9 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+// The fallback font of the default charset. It is used for the glyphs that are missing from the default font.
+const DefaultFallbackFontFile = "/assets/fonts/Go/Go-Regular.ttf"
+
 const (
 	DefaultFontFile    = "/assets/fonts/Frijole/Frijole-Regular.ttf"
 	TopLeftFrameWidth  = float32(0.1)
@@ -27,8 +31,11 @@ const (
 	BACK_SPACE         = glfw.KeyBackspace
 	KEY_UP             = glfw.KeyUp
 	KEY_DOWN           = glfw.KeyDown
+	KEY_TAB            = glfw.KeyTab
+	KEY_ENTER          = glfw.KeyEnter
 	LabelFontScale     = float32(1.0)
 	InputTextFontScale = float32(0.80)
+	ErrorFontScale     = float32(0.6)
 	ZFrame             = float32(0.0)
 	ZText              = float32(-0.01)
 	ZBackground        = float32(0.02)
@@ -37,6 +44,9 @@ const (
 	DefaultFrameLength = float32(0.02)
 )
 
+// The validation message of the form items, if the builder doesn't set one for the config.
+const DefaultValidationMessage = "Invalid value"
+
 var (
 	DirectionalLightDirection = mgl32.Vec3{0.0, 0.0, 1.0}.Normalize()
 	DirectionalLightAmbient   = mgl32.Vec3{0.5, 0.5, 0.5}
@@ -49,7 +59,11 @@ var (
 	DefaultFormScreenHeaderLabelColor   = mgl32.Vec3{0, 0, 1}
 	DefaultFormScreenFormItemLabelColor = mgl32.Vec3{0, 0, 1}
 	DefaultFormScreenFormItemInputColor = mgl32.Vec3{0, 0.5, 0}
-	DefaultClearColor                   = mgl32.Vec3{0.55, 0.55, 0.55}
+	DefaultFormScreenFormItemErrorColor = mgl32.Vec3{0.8, 0, 0}
+	// the color of the selected characters and the color of the placeholders of the text inputs.
+	DefaultFormScreenFormItemSelectionColor   = mgl32.Vec3{0.9, 0.5, 0}
+	DefaultFormScreenFormItemPlaceholderColor = mgl32.Vec3{0.6, 0.6, 0.6}
+	DefaultClearColor                         = mgl32.Vec3{0.55, 0.55, 0.55}
 )
 
 type FormScreenBuilder struct {
@@ -65,7 +79,14 @@ type FormScreenBuilder struct {
 	headerLabelColor          mgl32.Vec3
 	formItemLabelColor        mgl32.Vec3
 	formItemInputColor        mgl32.Vec3
+	formItemErrorColor        mgl32.Vec3
+	formItemSelectionColor    mgl32.Vec3
+	formItemPlaceholderColor  mgl32.Vec3
 	clearColor                mgl32.Vec3
+	validationMessages        map[string]string
+	placeholders              map[string]string
+	maxLengths                map[string]int
+	clipboard                 interfaces.Clipboard
 }
 
 func NewFormScreenBuilder() *FormScreenBuilder {
@@ -86,7 +107,14 @@ func NewFormScreenBuilder() *FormScreenBuilder {
 		headerLabelColor:          DefaultFormScreenHeaderLabelColor,
 		formItemLabelColor:        DefaultFormScreenFormItemLabelColor,
 		formItemInputColor:        DefaultFormScreenFormItemInputColor,
+		formItemErrorColor:        DefaultFormScreenFormItemErrorColor,
+		formItemSelectionColor:    DefaultFormScreenFormItemSelectionColor,
+		formItemPlaceholderColor:  DefaultFormScreenFormItemPlaceholderColor,
 		clearColor:                DefaultClearColor,
+		validationMessages:        make(map[string]string),
+		placeholders:              make(map[string]string),
+		maxLengths:                make(map[string]int),
+		clipboard:                 glfwClipboard{},
 	}
 }
 
@@ -141,11 +169,50 @@ func (b *FormScreenBuilder) SetFormItemInputColor(c mgl32.Vec3) {
 	b.formItemInputColor = c
 }
 
+// SetFormItemErrorColor sets the color of the validation messages.
+func (b *FormScreenBuilder) SetFormItemErrorColor(c mgl32.Vec3) {
+	b.formItemErrorColor = c
+}
+
+// SetFormItemSelectionColor sets the color of the selected characters of the text inputs.
+func (b *FormScreenBuilder) SetFormItemSelectionColor(c mgl32.Vec3) {
+	b.formItemSelectionColor = c
+}
+
+// SetFormItemPlaceholderColor sets the color of the placeholders of the text inputs.
+func (b *FormScreenBuilder) SetFormItemPlaceholderColor(c mgl32.Vec3) {
+	b.formItemPlaceholderColor = c
+}
+
 // SetClearColor sets the color of the background.
 func (b *FormScreenBuilder) SetClearColor(c mgl32.Vec3) {
 	b.clearColor = c
 }
 
+// SetValidationMessage sets the message that is displayed next to the form item
+// of the given config key, when its value is invalid.
+func (b *FormScreenBuilder) SetValidationMessage(key, message string) {
+	b.validationMessages[key] = message
+}
+
+// SetPlaceholder sets the text that is displayed in the empty input of the character
+// based form item of the given config key.
+func (b *FormScreenBuilder) SetPlaceholder(key, text string) {
+	b.placeholders[key] = text
+}
+
+// SetMaxLength sets the max length of the input of the character based form item
+// of the given config key.
+func (b *FormScreenBuilder) SetMaxLength(key string, maxLen int) {
+	b.maxLengths[key] = maxLen
+}
+
+// SetClipboard sets the clipboard of the copy, cut and paste events. By default
+// it is the system clipboard through glfw.
+func (b *FormScreenBuilder) SetClipboard(c interfaces.Clipboard) {
+	b.clipboard = c
+}
+
 // AddConfigBool is for adding a bool config item to the configs. It returns the key of the config.
 func (b *FormScreenBuilder) AddConfigBool(formLabel, formDescription string, defaultValue bool) string {
 	key := "bool_config_item_" + strconv.Itoa(len(b.config))
@@ -188,6 +255,46 @@ func (b *FormScreenBuilder) AddConfigText(formLabel, formDescription string, def
 	return key
 }
 
+// AddConfigEnum is for adding an enum config item to the configs. The form item displays the
+// options in a dropdown. It returns the key of the config.
+func (b *FormScreenBuilder) AddConfigEnum(formLabel, formDescription string, defaultValue string, options []string) string {
+	key := "enum_config_item_" + strconv.Itoa(len(b.config))
+	b.config.AddEnumConfig(key, formLabel, formDescription, defaultValue, options)
+	return key
+}
+
+// AddConfigSlider is for adding a slider config item to the configs. The value is clamped
+// to the [min-max] interval and rounded to the step. It returns the key of the config.
+func (b *FormScreenBuilder) AddConfigSlider(formLabel, formDescription string, defaultValue, min, max, step float32) string {
+	key := "slider_config_item_" + strconv.Itoa(len(b.config))
+	b.config.AddSliderConfig(key, formLabel, formDescription, defaultValue, min, max, step)
+	return key
+}
+
+// AddConfigColor is for adding a color config item to the configs. The form item displays
+// a preview of the color. It returns the key of the config.
+func (b *FormScreenBuilder) AddConfigColor(formLabel, formDescription string, defaultValue mgl32.Vec3) string {
+	key := "color_config_item_" + strconv.Itoa(len(b.config))
+	b.config.AddConfig(key, formLabel, formDescription, config.Color(defaultValue), nil)
+	return key
+}
+
+// AddConfigFilePath is for adding a file path config item to the configs. The validator
+// is checked on save. It returns the key of the config.
+func (b *FormScreenBuilder) AddConfigFilePath(formLabel, formDescription string, defaultValue string, validator model.StringValidator) string {
+	key := "file_path_config_item_" + strconv.Itoa(len(b.config))
+	b.config.AddConfig(key, formLabel, formDescription, config.FilePath(defaultValue), validator)
+	return key
+}
+
+// AddConfigKeyBinding is for adding a key binding config item to the configs. The value is
+// in the format of the keyboard bindings of the input package. It returns the key of the config.
+func (b *FormScreenBuilder) AddConfigKeyBinding(formLabel, formDescription string, defaultValue string) string {
+	key := "key_binding_config_item_" + strconv.Itoa(len(b.config))
+	b.config.AddConfig(key, formLabel, formDescription, config.KeyBinding(defaultValue), nil)
+	return key
+}
+
 // It builds a form screen with the given setup. In case of missing wrapper, it panics.
 func (b *FormScreenBuilder) Build() *FormScreen {
 	if b.wrapper == nil {
@@ -230,8 +337,17 @@ func (b *FormScreenBuilder) Build() *FormScreen {
 		sinceLastClick:            0,
 		currentScrollOffset:       float32(0.0),
 		formItemToConf:            make(map[interfaces.FormItem]*config.ConfigItem),
+		formItemErrors:            make(map[interfaces.FormItem]string),
+		validationMessages:        b.validationMessages,
+		changeCallbacks:           make(map[string][]func(interface{})),
 		formItemLabelColor:        b.formItemLabelColor,
 		formItemInputColor:        b.formItemInputColor,
+		formItemErrorColor:        b.formItemErrorColor,
+		formItemSelectionColor:    b.formItemSelectionColor,
+		formItemPlaceholderColor:  b.formItemPlaceholderColor,
+		placeholders:              b.placeholders,
+		maxLengths:                b.maxLengths,
+		clipboard:                 b.clipboard,
 		formItemDefaultMaterial:   b.formItemMaterial,
 		formItemHighlightMaterial: b.formItemHighlightMaterial,
 		clearColor:                b.clearColor,
@@ -260,6 +376,21 @@ func (b *FormScreenBuilder) Build() *FormScreen {
 			case config.ValueTypeVector:
 				formScreen.addFormItemFromConfigVector(b.config[key], b.itemPosition(model.ITEM_WIDTH_FULL, formScreen.GetFullWidth()*model.ITEM_HEIGHT_MULTIPLIER/aspRatio))
 				break
+			case config.ValueTypeEnum:
+				formScreen.addFormItemFromConfigEnum(b.config[key], b.itemPosition(model.ITEM_WIDTH_HALF, formScreen.GetFullWidth()*model.ITEM_HEIGHT_MULTIPLIER/aspRatio))
+				break
+			case config.ValueTypeSlider:
+				formScreen.addFormItemFromConfigSlider(b.config[key], b.itemPosition(model.ITEM_WIDTH_HALF, formScreen.GetFullWidth()*model.ITEM_HEIGHT_MULTIPLIER/aspRatio))
+				break
+			case config.ValueTypeColor:
+				formScreen.addFormItemFromConfigColor(b.config[key], b.itemPosition(model.ITEM_WIDTH_FULL, formScreen.GetFullWidth()*model.ITEM_HEIGHT_MULTIPLIER/aspRatio))
+				break
+			case config.ValueTypeFilePath:
+				formScreen.addFormItemFromConfigFilePath(b.config[key], b.itemPosition(model.ITEM_WIDTH_FULL, formScreen.GetFullWidth()*model.ITEM_HEIGHT_MULTIPLIER/aspRatio))
+				break
+			case config.ValueTypeKeyBinding:
+				formScreen.addFormItemFromConfigKeyBinding(b.config[key], b.itemPosition(model.ITEM_WIDTH_HALF, formScreen.GetFullWidth()*model.ITEM_HEIGHT_MULTIPLIER/aspRatio))
+				break
 			}
 		}
 	}
@@ -292,6 +423,9 @@ func (b *FormScreenBuilder) defaultCharset() {
 	if err != nil {
 		panic(err)
 	}
+	if err := cs.AddFont(model.STYLE_REGULAR, baseDirScreen()+DefaultFallbackFontFile); err != nil {
+		panic(err)
+	}
 	cs.SetTransparent(true)
 	b.charset = cs
 }
@@ -429,21 +563,53 @@ func (b *FormScreenBuilder) pushState(itemWidth float32) {
 	}
 }
 
+// glfwClipboard is the system clipboard, that is maintained by glfw.
+type glfwClipboard struct{}
+
+func (c glfwClipboard) GetClipboardString() string {
+	return glfw.GetClipboardString()
+}
+func (c glfwClipboard) SetClipboardString(text string) {
+	glfw.SetClipboardString(text)
+}
+
 type FormScreen struct {
 	*ScreenWithFrame
 	charset         *model.Charset
 	formItemShader  *shader.Shader
 	sinceLastClick  float64
 	sinceLastDelete float64
+	sinceLastAction float64
 	underEdit       interfaces.CharFormItem
+	// the select item with displayed options, the dragged slider or color item and
+	// the key binder item that waits for a key press.
+	openSelect       *model.FormItemSelect
+	dragged          interfaces.FormItem
+	draggedComponent int
+	capturing        *model.FormItemKeyBinding
 	// Item position
 	maxScrollOffset     float32
 	currentScrollOffset float32
 	// map for formItem-configItem
-	formItemToConf     map[interfaces.FormItem]*config.ConfigItem
+	formItemToConf map[interfaces.FormItem]*config.ConfigItem
+	// the form items in the order of the config. It is used for the focus traversal.
+	formItems []interfaces.FormItem
+	// the messages of the invalid form items.
+	formItemErrors     map[interfaces.FormItem]string
+	validationMessages map[string]string
+	// the callbacks of the config keys, that are called when the saved value is changed.
+	changeCallbacks    map[string][]func(interface{})
 	formItemLabelColor mgl32.Vec3
 	formItemInputColor mgl32.Vec3
+	formItemErrorColor mgl32.Vec3
 	clearColor         mgl32.Vec3
+	// the colors, the placeholders and the max lengths of the text inputs.
+	formItemSelectionColor   mgl32.Vec3
+	formItemPlaceholderColor mgl32.Vec3
+	placeholders             map[string]string
+	maxLengths               map[string]int
+	// the clipboard of the copy, cut and paste events.
+	clipboard interfaces.Clipboard
 	// materials
 	formItemDefaultMaterial   *material.Material
 	formItemHighlightMaterial *material.Material
@@ -502,16 +668,24 @@ func (f *FormScreen) initMaterialForTheFormItems() {
 				surfaceMesh := fi.GetSurface().(*mesh.TexturedMaterialMesh)
 				surfaceMesh.Material = f.formItemDefaultMaterial
 				break
+			case *model.FormItemSelect, *model.FormItemSlider, *model.FormItemColor, *model.FormItemKeyBinding:
+				fi := f.shaderMap[s][index].(interfaces.FormItem)
+				surfaceMesh := fi.GetSurface().(*mesh.TexturedMaterialMesh)
+				surfaceMesh.Material = f.formItemDefaultMaterial
+				break
 			}
 		}
 	}
 }
 
-// deleteCursor removes the cursor from the text form inputs.
+// deleteCursor removes the cursor from the text form inputs. The value is printed
+// again without the selection color.
 func (f *FormScreen) deleteCursor() {
 	if f.underEdit != nil {
-		f.underEdit.DeleteCursor()
+		fi := f.underEdit
+		fi.DeleteCursor()
 		f.underEdit = nil
+		f.printFormItemValue(fi)
 	}
 }
 func (f *FormScreen) wrapTextToLines(desc string, scale, maxLineWidth float32) []string {
@@ -544,7 +718,14 @@ func (f *FormScreen) wrapTextToLines(desc string, scale, maxLineWidth float32) [
 // highlightFormAction updates the material of the closest mesh.
 // It also prints the details of the form item to the detail content box.
 func (f *FormScreen) highlightFormAction() {
-	tmMesh := f.closestMesh.(*mesh.TexturedMaterialMesh)
+	highlighted := f.closestMesh
+	switch f.closestModel.(type) {
+	case *model.FormItemSelect, *model.FormItemSlider, *model.FormItemColor, *model.FormItemKeyBinding:
+		// The parts of these items have their own materials (knob, preview), so that the surface is highlighted.
+		highlighted = f.closestModel.(interfaces.FormItem).GetSurface()
+		break
+	}
+	tmMesh := highlighted.(*mesh.TexturedMaterialMesh)
 	tmMesh.Material = f.formItemHighlightMaterial
 	if f.detailContentBox == nil {
 		return
@@ -563,18 +744,22 @@ func (f *FormScreen) highlightFormAction() {
 }
 
 // Update function increases the time since the last events with dt. Handles the up / down scroll events.
-// Sets the form items to their initial state and then handles the mouse events and also the deletion events.
+// Sets the form items to their initial state and then handles the mouse events, the deletion events,
+// the focus traversal and the save / cancel / reset events.
 func (f *FormScreen) Update(dt float64, p interfaces.Pointer, keyStore interfaces.RoKeyStore, buttonStore interfaces.RoButtonStore) {
 	posX, posY := p.GetCurrent()
 	f.sinceLastClick = f.sinceLastClick + dt
 	f.sinceLastDelete = f.sinceLastDelete + dt
+	f.sinceLastAction = f.sinceLastAction + dt
 	aspRatio := f.GetAspectRatio()
 	cursorX := float32(-posX) * f.frameWidth / 2
 	cursorY := float32(posY) / aspRatio * f.frameWidth / 2
 	direction := mgl32.Vec3{0, 0, 0}
-	if keyStore.Get(KEY_UP) && !keyStore.Get(KEY_DOWN) {
+	up := f.actionActive(input.ACTION_MENU_UP, keyStore.Get(KEY_UP))
+	down := f.actionActive(input.ACTION_MENU_DOWN, keyStore.Get(KEY_DOWN))
+	if up && !down {
 		direction = mgl32.Vec3{0, -1, 0}
-	} else if keyStore.Get(KEY_DOWN) && !keyStore.Get(KEY_UP) {
+	} else if down && !up {
 		direction = mgl32.Vec3{0, 1, 0}
 	}
 	// If the Up key is pressed => direction: up, velocity: c
@@ -610,35 +795,37 @@ func (f *FormScreen) Update(dt float64, p interfaces.Pointer, keyStore interface
 	f.closestModel = closestModel
 	f.initMaterialForTheFormItems()
 	minDiff := float32(0.0)
+	shift := keyStore.Get(glfw.KeyLeftShift) || keyStore.Get(glfw.KeyRightShift)
+	control := keyStore.Get(glfw.KeyLeftControl) || keyStore.Get(glfw.KeyRightControl)
+	pressed := f.actionActive(input.ACTION_SELECT, buttonStore.Get(LEFT_MOUSE_BUTTON))
+	clicked := pressed && f.sinceLastClick > EventEpsilon
+	if clicked && f.openSelect != nil {
+		// The click on an option selects it, any other click closes the dropdown.
+		f.sinceLastClick = 0
+		clicked = false
+		f.selectOptionAt(coords)
+	}
 	if closestDistance <= minDiff+0.01 {
-		if buttonStore.Get(LEFT_MOUSE_BUTTON) && f.sinceLastClick > EventEpsilon {
+		if clicked {
 			f.deleteCursor()
+			f.stopCapture()
 			f.sinceLastClick = 0
 			switch f.closestModel.(type) {
 			case *model.FormItemBool:
 				formModel := f.closestModel.(*model.FormItemBool)
 				formModel.SetValue(!formModel.GetValue())
-				f.formItemToConf[formModel].SetCurrentValue(formModel.GetValue())
 				break
 			case *model.FormItemInt:
-				formModel := f.closestModel.(*model.FormItemInt)
-				formModel.AddCursor()
-				f.underEdit = formModel
+				f.startEditing(f.closestModel.(*model.FormItemInt), cursorX, shift)
 				break
 			case *model.FormItemFloat:
-				formModel := f.closestModel.(*model.FormItemFloat)
-				formModel.AddCursor()
-				f.underEdit = formModel
+				f.startEditing(f.closestModel.(*model.FormItemFloat), cursorX, shift)
 				break
 			case *model.FormItemText:
-				formModel := f.closestModel.(*model.FormItemText)
-				formModel.AddCursor()
-				f.underEdit = formModel
+				f.startEditing(f.closestModel.(*model.FormItemText), cursorX, shift)
 				break
 			case *model.FormItemInt64:
-				formModel := f.closestModel.(*model.FormItemInt64)
-				formModel.AddCursor()
-				f.underEdit = formModel
+				f.startEditing(f.closestModel.(*model.FormItemInt64), cursorX, shift)
 				break
 			case *model.FormItemVector:
 				formModel := f.closestModel.(*model.FormItemVector)
@@ -651,64 +838,637 @@ func (f *FormScreen) Update(dt float64, p interfaces.Pointer, keyStore interface
 						index++
 					}
 					formModel.SetTarget(index - 1)
-					formModel.AddCursor()
-					f.underEdit = formModel
+					f.startEditing(formModel, cursorX, shift)
 				}
 				break
+			case *model.FormItemSelect:
+				f.openOptions(f.closestModel.(*model.FormItemSelect))
+				break
+			case *model.FormItemSlider:
+				f.dragged = f.closestModel.(*model.FormItemSlider)
+				break
+			case *model.FormItemColor:
+				formModel := f.closestModel.(*model.FormItemColor)
+				if component := formModel.GetComponent(f.closestMesh); component > -1 {
+					f.dragged = formModel
+					f.draggedComponent = component
+				}
+				break
+			case *model.FormItemKeyBinding:
+				formModel := f.closestModel.(*model.FormItemKeyBinding)
+				formModel.StartCapture()
+				f.capturing = formModel
+				f.printFormItemText(formModel.GetTarget(), formModel.ValueToString())
+				break
 			}
 		}
 		f.highlightFormAction()
 	}
+	if f.dragged != nil {
+		if pressed {
+			f.dragFormItem(cursorX)
+		} else {
+			f.dragged = nil
+		}
+	}
+	// While the key binder waits for a key, the pressed key is not handled as form action.
+	if f.capturing != nil {
+		if f.captureKey(keyStore) {
+			f.sinceLastDelete = 0
+			f.sinceLastAction = 0
+		}
+		return
+	}
 
-	if keyStore.Get(BACK_SPACE) && f.sinceLastDelete > EventEpsilon {
+	if f.actionActive(input.ACTION_DELETE, keyStore.Get(BACK_SPACE)) && f.sinceLastDelete > EventEpsilon {
 		f.sinceLastDelete = 0
 		if f.underEdit != nil {
 			f.underEdit.DeleteLastCharacter()
-			f.syncFormItemValuesToConfigValue()
-			f.charset.CleanSurface(f.underEdit.GetTarget())
-			aspRatio := f.GetAspectRatio()
-			textScale := InputTextFontScale / f.windowWidth * aspRatio
-			_, hW := f.charset.TextContainerSize("W", textScale)
-			switch f.underEdit.(type) {
-			case *model.FormItemVector:
-				f.charset.PrintTo(f.underEdit.ValueToString(), -f.underEdit.(*model.FormItemVector).GetVectorCursorInitialPosition().X(), -hW/2, ZText, textScale, f.wrapper, f.underEdit.GetTarget(), []mgl32.Vec3{f.formItemInputColor})
-				break
-			default:
-				f.charset.PrintTo(f.underEdit.ValueToString(), -f.underEdit.GetCursorInitialPosition().X(), -hW/2, ZText, textScale, f.wrapper, f.underEdit.GetTarget(), []mgl32.Vec3{f.formItemInputColor})
-				break
+			f.validateFormItem(f.underEdit)
+			f.printFormItemValue(f.underEdit)
+		}
+	}
+	if f.underEdit != nil && f.sinceLastAction > EventEpsilon {
+		if f.editAction(keyStore, shift, control) {
+			f.sinceLastAction = 0
+		}
+	}
+	if f.sinceLastAction > EventEpsilon {
+		if f.actionActive(input.ACTION_PREV_FIELD, keyStore.Get(KEY_TAB) && shift) {
+			f.sinceLastAction = 0
+			f.moveFocus(-1)
+		} else if f.actionActive(input.ACTION_NEXT_FIELD, keyStore.Get(KEY_TAB)) {
+			f.sinceLastAction = 0
+			f.moveFocus(1)
+		} else if f.actionActive(input.ACTION_SAVE, keyStore.Get(KEY_ENTER)) {
+			f.sinceLastAction = 0
+			f.Save()
+		} else if f.actionActive(input.ACTION_CANCEL, keyStore.Get(glfw.KeyZ) && control) {
+			f.sinceLastAction = 0
+			f.Cancel()
+		} else if f.actionActive(input.ACTION_RESET, keyStore.Get(glfw.KeyR) && control) {
+			f.sinceLastAction = 0
+			f.ResetToDefault()
+		}
+	}
+}
+
+// startEditing displays the cursor of the character based form item and moves it to the
+// x coordinate of the pointer. With the shift key the selection is extended. The item is
+// dragged, so that the selection follows the pointer while the button is pressed.
+func (f *FormScreen) startEditing(fi interfaces.CharFormItem, x float32, selecting bool) {
+	fi.AddCursor()
+	fi.SetCursorFromPosition(x, selecting)
+	f.underEdit = fi
+	f.dragged = fi
+	f.printFormItemValue(fi)
+}
+
+// editAction handles the cursor movement, the forward delete, the selection and the clipboard
+// events of the edited form item. The shift key extends the selection. It returns true,
+// if an event is handled.
+func (f *FormScreen) editAction(keyStore interfaces.RoKeyStore, shift, control bool) bool {
+	fi := f.underEdit
+	if f.actionActive(input.ACTION_CURSOR_LEFT, keyStore.Get(glfw.KeyLeft)) {
+		fi.MoveCursor(-1, shift)
+	} else if f.actionActive(input.ACTION_CURSOR_RIGHT, keyStore.Get(glfw.KeyRight)) {
+		fi.MoveCursor(1, shift)
+	} else if f.actionActive(input.ACTION_CURSOR_HOME, keyStore.Get(glfw.KeyHome)) {
+		fi.MoveCursorToStart(shift)
+	} else if f.actionActive(input.ACTION_CURSOR_END, keyStore.Get(glfw.KeyEnd)) {
+		fi.MoveCursorToEnd(shift)
+	} else if f.actionActive(input.ACTION_DELETE_NEXT, keyStore.Get(glfw.KeyDelete)) {
+		fi.DeleteNextCharacter()
+		f.validateFormItem(fi)
+	} else if f.actionActive(input.ACTION_SELECT_ALL, keyStore.Get(glfw.KeyA) && control) {
+		fi.SelectAll()
+	} else if f.actionActive(input.ACTION_COPY, keyStore.Get(glfw.KeyC) && control) {
+		f.Copy()
+		return true
+	} else if f.actionActive(input.ACTION_CUT, keyStore.Get(glfw.KeyX) && control) {
+		f.Cut()
+		return true
+	} else if f.actionActive(input.ACTION_PASTE, keyStore.Get(glfw.KeyV) && control) {
+		f.Paste()
+		return true
+	} else {
+		return false
+	}
+	f.printFormItemValue(fi)
+	return true
+}
+
+// Copy puts the selected text of the edited form item to the clipboard.
+func (f *FormScreen) Copy() {
+	if f.underEdit == nil || f.clipboard == nil {
+		return
+	}
+	if text := f.underEdit.GetSelectedText(); text != "" {
+		f.clipboard.SetClipboardString(text)
+	}
+}
+
+// Cut puts the selected text of the edited form item to the clipboard, then it deletes
+// the selected characters.
+func (f *FormScreen) Cut() {
+	if f.underEdit == nil || f.clipboard == nil {
+		return
+	}
+	f.Copy()
+	f.underEdit.DeleteSelection()
+	f.validateFormItem(f.underEdit)
+	f.printFormItemValue(f.underEdit)
+}
+
+// Paste types the text of the clipboard to the position of the cursor of the edited form item.
+// The selected characters are replaced. The control characters, like the line breaks, are skipped.
+func (f *FormScreen) Paste() {
+	if f.underEdit == nil || f.clipboard == nil {
+		return
+	}
+	f.underEdit.DeleteSelection()
+	for _, r := range f.clipboard.GetClipboardString() {
+		if r < ' ' {
+			continue
+		}
+		f.CharCallback(r, f.wrapper)
+	}
+	f.printFormItemValue(f.underEdit)
+}
+
+// SetClipboard sets the clipboard of the copy, cut and paste events.
+func (f *FormScreen) SetClipboard(c interfaces.Clipboard) {
+	f.clipboard = c
+}
+
+// openOptions displays the options of the select item.
+func (f *FormScreen) openOptions(fi *model.FormItemSelect) {
+	fi.Open()
+	f.openSelect = fi
+	options := fi.GetOptionMeshes()
+	for i := 0; i < len(options); i++ {
+		f.charset.CleanSurface(options[i])
+		f.printFormItemText(options[i], fi.GetOptions()[i])
+	}
+}
+
+// closeOptions removes the options of the open select item.
+func (f *FormScreen) closeOptions() {
+	if f.openSelect == nil {
+		return
+	}
+	options := f.openSelect.GetOptionMeshes()
+	for i := 0; i < len(options); i++ {
+		f.charset.CleanSurface(options[i])
+	}
+	f.openSelect.Close()
+	f.openSelect = nil
+}
+
+// selectOptionAt selects the option of the open select item under the given coordinates, then
+// it closes the options. The options are in front of the form items, so that the coordinates
+// are moved with the offset of the options.
+func (f *FormScreen) selectOptionAt(coords mgl32.Vec3) {
+	fi := f.openSelect
+	msh, dist := fi.ClosestMeshTo(mgl32.Vec3{coords.X(), coords.Y(), coords.Z() - 0.02})
+	if index := fi.GetOptionIndex(msh); index > -1 && dist <= fi.GetFormItemHeight()/2 {
+		fi.Select(index)
+		f.printFormItemText(fi.GetTarget(), fi.ValueToString())
+		f.validateFormItem(fi)
+	}
+	f.closeOptions()
+}
+
+// dragFormItem sets the value of the dragged slider or color item from the x coordinate of the pointer.
+// In case of character based item, it extends the selection to the pointer.
+func (f *FormScreen) dragFormItem(x float32) {
+	switch f.dragged.(type) {
+	case interfaces.CharFormItem:
+		fi := f.dragged.(interfaces.CharFormItem)
+		if fi != f.underEdit {
+			return
+		}
+		start, end := fi.GetSelection()
+		fi.SetCursorFromPosition(x, true)
+		if startNew, endNew := fi.GetSelection(); start != startNew || end != endNew {
+			f.printFormItemValue(fi)
+		}
+		return
+	case *model.FormItemSlider:
+		fi := f.dragged.(*model.FormItemSlider)
+		fi.SetValueFromPosition(x)
+		f.printFormItemText(fi.GetTarget(), fi.ValueToString())
+		break
+	case *model.FormItemColor:
+		f.dragged.(*model.FormItemColor).SetComponentFromPosition(f.draggedComponent, x)
+		break
+	}
+	f.validateFormItem(f.dragged)
+}
+
+// captureKey sets the binding of the pressed key as the value of the capturing key binder item.
+// The escape key stops the capture without changing the value. It returns true, if the capture is finished.
+func (f *FormScreen) captureKey(keyStore interfaces.RoKeyStore) bool {
+	if keyStore.Get(glfw.KeyEscape) {
+		f.stopCapture()
+		return true
+	}
+	binding, ok := input.PressedKeyBinding(keyStore)
+	if !ok {
+		return false
+	}
+	fi := f.capturing
+	f.capturing = nil
+	f.SetFormItemValue(fi, binding.String())
+	return true
+}
+
+// stopCapture turns off the capture mode of the key binder item and displays its value.
+func (f *FormScreen) stopCapture() {
+	if f.capturing == nil {
+		return
+	}
+	f.capturing.StopCapture()
+	f.printFormItemText(f.capturing.GetTarget(), f.capturing.ValueToString())
+	f.capturing = nil
+}
+
+// moveFocus moves the edit mode to the next (step: 1) or to the previous (step: -1) input
+// of the character based form items. The components of the vector items are separate inputs.
+// Without edited item, it starts from the first or the last input.
+func (f *FormScreen) moveFocus(step int) {
+	var items []interfaces.CharFormItem
+	var targets []int
+	current := -1
+	for i := 0; i < len(f.formItems); i++ {
+		fi, ok := f.formItems[i].(interfaces.CharFormItem)
+		if !ok {
+			continue
+		}
+		numTargets := 1
+		currentTarget := 0
+		if vector, ok := fi.(*model.FormItemVector); ok {
+			numTargets = 3
+			currentTarget = vector.GetIndex(vector.GetTarget()) - 1
+		}
+		for t := 0; t < numTargets; t++ {
+			if fi == f.underEdit && t == currentTarget {
+				current = len(items)
 			}
+			items = append(items, fi)
+			targets = append(targets, t)
 		}
 	}
+	if len(items) == 0 {
+		return
+	}
+	next := 0
+	if current > -1 {
+		next = (current + step + len(items)) % len(items)
+	} else if step < 0 {
+		next = len(items) - 1
+	}
+	f.deleteCursor()
+	if vector, ok := items[next].(*model.FormItemVector); ok {
+		vector.SetTarget(targets[next])
+	}
+	items[next].AddCursor()
+	f.underEdit = items[next]
 }
-func (f *FormScreen) syncFormItemValuesToConfigValue() {
-	switch f.underEdit.(type) {
+
+// formItemValue returns the value of the form item with the type of its config.
+func (f *FormScreen) formItemValue(fi interfaces.FormItem) interface{} {
+	switch fi.(type) {
 	case *model.FormItemInt:
-		f.formItemToConf[f.underEdit].SetCurrentValue(f.underEdit.(*model.FormItemInt).GetValue())
-		break
+		return fi.(*model.FormItemInt).GetValue()
 	case *model.FormItemFloat:
-		f.formItemToConf[f.underEdit].SetCurrentValue(f.underEdit.(*model.FormItemFloat).GetValue())
-		break
+		return fi.(*model.FormItemFloat).GetValue()
 	case *model.FormItemText:
-		f.formItemToConf[f.underEdit].SetCurrentValue(f.underEdit.(*model.FormItemText).GetValue())
-		break
+		value := fi.(*model.FormItemText).GetValue()
+		if f.formItemToConf[fi].GetValueType() == config.ValueTypeFilePath {
+			return config.FilePath(value)
+		}
+		return value
 	case *model.FormItemInt64:
-		f.formItemToConf[f.underEdit].SetCurrentValue(f.underEdit.(*model.FormItemInt64).GetValue())
-		break
+		return fi.(*model.FormItemInt64).GetValue()
+	case *model.FormItemBool:
+		return fi.(*model.FormItemBool).GetValue()
 	case *model.FormItemVector:
-		f.formItemToConf[f.underEdit].SetCurrentValue(f.underEdit.(*model.FormItemVector).GetValue())
+		return fi.(*model.FormItemVector).GetValue()
+	case *model.FormItemSelect:
+		return fi.(*model.FormItemSelect).GetValue()
+	case *model.FormItemSlider:
+		return fi.(*model.FormItemSlider).GetValue()
+	case *model.FormItemColor:
+		return config.Color(fi.(*model.FormItemColor).GetValue())
+	case *model.FormItemKeyBinding:
+		return config.KeyBinding(fi.(*model.FormItemKeyBinding).GetValue())
+	}
+	return nil
+}
+
+// configValueToFormValue returns the given config value in the format of the SetFormItemValue function.
+func configValueToFormValue(configItem *config.ConfigItem, value interface{}) interface{} {
+	switch configItem.GetValueType() {
+	case config.ValueTypeInt:
+		return transformations.IntegerToString(value.(int))
+	case config.ValueTypeInt64:
+		return transformations.Integer64ToString(value.(int64))
+	case config.ValueTypeFloat:
+		return transformations.Float32ToStringExact(value.(float32))
+	case config.ValueTypeVector:
+		return transformations.VectorToString(value.(mgl32.Vec3))
+	case config.ValueTypeColor:
+		return mgl32.Vec3(value.(config.Color))
+	case config.ValueTypeFilePath:
+		return string(value.(config.FilePath))
+	case config.ValueTypeKeyBinding:
+		return string(value.(config.KeyBinding))
+	}
+	return value
+}
+
+// isValidFormItem returns true, if the value of the form item is accepted by the validator of its config.
+func (f *FormScreen) isValidFormItem(fi interfaces.FormItem) bool {
+	validator := f.formItemToConf[fi].GetValidatorFunction()
+	if validator == nil {
+		return true
+	}
+	switch fi.(type) {
+	case *model.FormItemInt:
+		v := validator.(model.IntValidator)
+		return v == nil || v(fi.(*model.FormItemInt).GetValue())
+	case *model.FormItemFloat:
+		v := validator.(model.FloatValidator)
+		return v == nil || v(fi.(*model.FormItemFloat).GetValue())
+	case *model.FormItemText:
+		v := validator.(model.StringValidator)
+		return v == nil || v(fi.(*model.FormItemText).GetValue())
+	case *model.FormItemInt64:
+		v := validator.(model.Int64Validator)
+		return v == nil || v(fi.(*model.FormItemInt64).GetValue())
+	case *model.FormItemVector:
+		v := validator.(model.FloatValidator)
+		if v == nil {
+			return true
+		}
+		value := fi.(*model.FormItemVector).GetValue()
+		for i := 0; i < 3; i++ {
+			if !v(value[i]) {
+				return false
+			}
+		}
 		break
 	}
+	return true
+}
+
+// validateFormItem displays the validation message next to the form item, if its value is
+// invalid, otherwise it removes the message.
+func (f *FormScreen) validateFormItem(fi interfaces.FormItem) bool {
+	if f.isValidFormItem(fi) {
+		f.setValidationError(fi, "")
+		return true
+	}
+	f.setValidationError(fi, f.validationMessage(fi))
+	return false
+}
+
+// validationMessage returns the message of the invalid form item.
+func (f *FormScreen) validationMessage(fi interfaces.FormItem) string {
+	if message, ok := f.validationMessages[f.formItemToConf[fi].GetKey()]; ok {
+		return message
+	}
+	return DefaultValidationMessage
+}
+
+// setValidationError updates the validation message of the form item. The empty message
+// means valid value. The label is reprinted only if the message is changed.
+func (f *FormScreen) setValidationError(fi interfaces.FormItem, message string) {
+	if f.formItemErrors[fi] == message {
+		return
+	}
+	if message == "" {
+		delete(f.formItemErrors, fi)
+	} else {
+		f.formItemErrors[fi] = message
+	}
+	f.printFormItemLabel(fi)
+}
+
+// printFormItemLabel prints the label to the label area of the form item. If the form item
+// has validation message, the label and the message are printed with smaller font in two lines.
+func (f *FormScreen) printFormItemLabel(fi interfaces.FormItem) {
+	f.charset.CleanSurface(fi.GetSurface())
+	aspRatio := f.GetAspectRatio()
+	message, invalid := f.formItemErrors[fi]
+	if !invalid {
+		textScale := LabelFontScale / f.windowWidth * aspRatio
+		wW, hW := f.charset.TextContainerSize("W", textScale)
+		f.charset.PrintTo(fi.GetLabel(), (-fi.GetFormItemWidth()+wW)/2, -hW/2, ZText, textScale, f.wrapper, fi.GetSurface(), []mgl32.Vec3{f.formItemLabelColor})
+		return
+	}
+	textScale := ErrorFontScale / f.windowWidth * aspRatio
+	wW, hW := f.charset.TextContainerSize("W", textScale)
+	f.charset.PrintTo(fi.GetLabel(), (-fi.GetFormItemWidth()+wW)/2, 0, ZText, textScale, f.wrapper, fi.GetSurface(), []mgl32.Vec3{f.formItemLabelColor})
+	f.charset.PrintTo(message, (-fi.GetFormItemWidth()+wW)/2, -hW, ZText, textScale, f.wrapper, fi.GetSurface(), []mgl32.Vec3{f.formItemErrorColor})
+}
+
+// printFormItemValue prints the value of the character based form item to its current target.
+// The selected characters of the edited item are printed with the selection color. The empty
+// value is replaced with the placeholder of the form item.
+func (f *FormScreen) printFormItemValue(fi interfaces.CharFormItem) {
+	f.charset.CleanSurface(fi.GetTarget())
+	textScale := InputTextFontScale / f.windowWidth * f.GetAspectRatio()
+	_, hW := f.charset.TextContainerSize("W", textScale)
+	x := -fi.GetCursorInitialPosition().X()
+	if vector, ok := fi.(*model.FormItemVector); ok {
+		x = -vector.GetVectorCursorInitialPosition().X()
+	}
+	value := fi.ValueToString()
+	if value == "" {
+		f.charset.PrintTo(fi.GetPlaceholder(), x, -hW/2, ZText, textScale, f.wrapper, fi.GetTarget(), []mgl32.Vec3{f.formItemPlaceholderColor})
+		return
+	}
+	cols := []mgl32.Vec3{f.formItemInputColor}
+	if start, end := fi.GetSelection(); fi == f.underEdit && start != end {
+		cols = nil
+		for i := 0; i < len([]rune(value)); i++ {
+			if i >= start && i < end {
+				cols = append(cols, f.formItemSelectionColor)
+			} else {
+				cols = append(cols, f.formItemInputColor)
+			}
+		}
+	}
+	f.charset.PrintTo(value, x, -hW/2, ZText, textScale, f.wrapper, fi.GetTarget(), cols)
+}
+
+// printFormItemText prints the text to the middle of the target mesh. It is used by the
+// form items that are not character based.
+func (f *FormScreen) printFormItemText(target interfaces.Mesh, text string) {
+	f.charset.CleanSurface(target)
+	textScale := InputTextFontScale / f.windowWidth * f.GetAspectRatio()
+	_, hW := f.charset.TextContainerSize("W", textScale)
+	f.charset.PrintAlignedTo(text, 0, -hW/2, ZText, textScale, model.ALIGN_CENTER, f.wrapper, target, []mgl32.Vec3{f.formItemInputColor})
+}
+
+// Save validates the form items. If all of them are valid, it applies their values to the config
+// and calls the change callbacks of the modified config items. Otherwise the validation
+// messages are displayed and the config is not updated. It returns true on success.
+func (f *FormScreen) Save() bool {
+	valid := true
+	for i := 0; i < len(f.formItems); i++ {
+		if !f.validateFormItem(f.formItems[i]) {
+			valid = false
+		}
+	}
+	if !valid {
+		return false
+	}
+	for i := 0; i < len(f.formItems); i++ {
+		configItem := f.formItemToConf[f.formItems[i]]
+		value := f.formItemValue(f.formItems[i])
+		if value == configItem.GetCurrentValue() {
+			continue
+		}
+		configItem.SetCurrentValue(value)
+		callbacks := f.changeCallbacks[configItem.GetKey()]
+		for j := 0; j < len(callbacks); j++ {
+			callbacks[j](value)
+		}
+	}
+	return true
+}
+
+// Cancel drops the unsaved modifications. The form items get the current values of the config.
+func (f *FormScreen) Cancel() {
+	for i := 0; i < len(f.formItems); i++ {
+		configItem := f.formItemToConf[f.formItems[i]]
+		f.SetFormItemValue(f.formItems[i], configValueToFormValue(configItem, configItem.GetCurrentValue()))
+	}
+}
+
+// ResetToDefault sets the default values of the config to the form items.
+// The config is updated only by the Save function.
+func (f *FormScreen) ResetToDefault() {
+	for i := 0; i < len(f.formItems); i++ {
+		configItem := f.formItemToConf[f.formItems[i]]
+		f.SetFormItemValue(f.formItems[i], configValueToFormValue(configItem, configItem.GetDefaultValue()))
+	}
+}
+
+// HasChanges returns true, if a form item has unsaved value.
+func (f *FormScreen) HasChanges() bool {
+	for i := 0; i < len(f.formItems); i++ {
+		if f.formItemValue(f.formItems[i]) != f.formItemToConf[f.formItems[i]].GetCurrentValue() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCapturingKey returns true, if a key binder item is waiting for a key. The application
+// doesn't navigate back with the escape key in this case, it stops the capture instead.
+func (f *FormScreen) IsCapturingKey() bool {
+	return f.capturing != nil
+}
+
+// OnChange registers a callback for the given config key. It is called with the new value,
+// when the saved value of the config is changed.
+func (f *FormScreen) OnChange(configKey string, callback func(interface{})) {
+	f.changeCallbacks[configKey] = append(f.changeCallbacks[configKey], callback)
+}
+
+// ReloadConfig is called when the config values of the given keys are changed outside of
+// the form, eg. the config file is reloaded. The form items get the current values of the
+// config and the change callbacks are called. The items that are used at the moment (edited,
+// dragged, opened or capturing) keep their values, so that the interaction is not broken.
+func (f *FormScreen) ReloadConfig(configKeys []string) {
+	for fi, configItem := range f.formItemToConf {
+		reloaded := false
+		for i := 0; i < len(configKeys); i++ {
+			if configItem.IsConfigOf(configKeys[i]) {
+				reloaded = true
+				break
+			}
+		}
+		if !reloaded {
+			continue
+		}
+		if !f.isInUse(fi) {
+			f.SetFormItemValue(fi, configValueToFormValue(configItem, configItem.GetCurrentValue()))
+		}
+		callbacks := f.changeCallbacks[configItem.GetKey()]
+		for j := 0; j < len(callbacks); j++ {
+			callbacks[j](configItem.GetCurrentValue())
+		}
+	}
+}
+
+// isInUse returns true, if the form item is edited, dragged, opened or waits for a key press.
+func (f *FormScreen) isInUse(fi interfaces.FormItem) bool {
+	if f.underEdit != nil && interfaces.FormItem(f.underEdit) == fi {
+		return true
+	}
+	if f.openSelect != nil && interfaces.FormItem(f.openSelect) == fi {
+		return true
+	}
+	if f.capturing != nil && interfaces.FormItem(f.capturing) == fi {
+		return true
+	}
+	return f.dragged != nil && f.dragged == fi
+}
+
+// GetValidationError returns the validation message of the form item of the given config key.
+// It returns empty string, if the value is valid.
+func (f *FormScreen) GetValidationError(configKey string) string {
+	return f.formItemErrors[f.GetFormItem(configKey)]
+}
+
+// OnEnter loads the current values of the config to the form items.
+func (f *FormScreen) OnEnter() {
+	f.Cancel()
+}
+
+// OnExit stops the editing. The unsaved values are dropped on the next enter.
+func (f *FormScreen) OnExit() {
+	f.stopEditing()
+}
+
+// OnPause stops the editing.
+func (f *FormScreen) OnPause() {
+	f.stopEditing()
+}
+
+// stopEditing removes the cursor, closes the options, stops the dragging and the key capture.
+func (f *FormScreen) stopEditing() {
+	f.deleteCursor()
+	f.closeOptions()
+	f.stopCapture()
+	f.dragged = nil
 }
 
+// OnResume is called, when the form is activated again. The values are kept.
+func (f *FormScreen) OnResume() {}
+
 func (f *FormScreen) addFormItem(fi interfaces.FormItem, defaultValue interface{}) {
 	fi.RotateX(-90)
 	fi.RotateY(180)
 	fi.SetSpeed(FormItemMoveSpeed)
 	f.AddModelToShader(fi, f.formItemShader)
-	aspRatio := f.GetAspectRatio()
-	textScale := LabelFontScale / f.windowWidth * aspRatio
-	wW, hW := f.charset.TextContainerSize("W", textScale)
-	f.charset.PrintTo(fi.GetLabel(), (-fi.GetFormItemWidth()+wW)/2, -hW/2, ZText, textScale, f.wrapper, fi.GetSurface(), []mgl32.Vec3{f.formItemLabelColor})
+	f.formItems = append(f.formItems, fi)
+	f.printFormItemLabel(fi)
+	if charItem, ok := fi.(interfaces.CharFormItem); ok {
+		if configItem, ok := f.formItemToConf[fi]; ok {
+			if placeholder, ok := f.placeholders[configItem.GetKey()]; ok {
+				charItem.SetPlaceholder(placeholder)
+			}
+			if maxLen, ok := f.maxLengths[configItem.GetKey()]; ok {
+				charItem.SetMaxLength(maxLen)
+			}
+		}
+	}
 	f.SetFormItemValue(fi, defaultValue)
 }
 
@@ -717,7 +1477,7 @@ func (f *FormScreen) addFormItemFromConfigBool(configItem *config.ConfigItem, po
 	asp := 1.0 / f.GetAspectRatio()
 	fi := model.NewFormItemBool(f.GetFullWidth(), model.ITEM_WIDTH_SHORT, asp, configItem.GetLabel(), configItem.GetDescription(), f.formItemDefaultMaterial, pos, f.wrapper)
 	f.formItemToConf[fi] = configItem
-	f.addFormItem(fi, configItem.GetDefaultValue())
+	f.addFormItem(fi, configItem.GetCurrentValue())
 }
 
 // addFormItemFromConfigInt sets up a FormItemInt from a ConfigItem structure.
@@ -728,7 +1488,7 @@ func (f *FormScreen) addFormItemFromConfigInt(configItem *config.ConfigItem, pos
 		fi.SetValidator(configItem.GetValidatorFunction().(model.IntValidator))
 	}
 	f.formItemToConf[fi] = configItem
-	f.addFormItem(fi, transformations.IntegerToString(configItem.GetDefaultValue().(int)))
+	f.addFormItem(fi, configValueToFormValue(configItem, configItem.GetCurrentValue()))
 }
 
 // addFormItemFromConfigFloat sets up a FormItemFloat from a ConfigItem structure.
@@ -739,7 +1499,7 @@ func (f *FormScreen) addFormItemFromConfigFloat(configItem *config.ConfigItem, p
 		fi.SetValidator(configItem.GetValidatorFunction().(model.FloatValidator))
 	}
 	f.formItemToConf[fi] = configItem
-	f.addFormItem(fi, transformations.Float32ToStringExact(configItem.GetDefaultValue().(float32)))
+	f.addFormItem(fi, configValueToFormValue(configItem, configItem.GetCurrentValue()))
 }
 
 // addFormItemFromConfigText sets up a FormItemText from a ConfigItem structure.
@@ -750,7 +1510,7 @@ func (f *FormScreen) addFormItemFromConfigText(configItem *config.ConfigItem, po
 		fi.SetValidator(configItem.GetValidatorFunction().(model.StringValidator))
 	}
 	f.formItemToConf[fi] = configItem
-	f.addFormItem(fi, configItem.GetDefaultValue())
+	f.addFormItem(fi, configItem.GetCurrentValue())
 }
 
 // addFormItemFromConfigInt64 sets up a FormItemInt64 from a ConfigItem structure.
@@ -761,10 +1521,10 @@ func (f *FormScreen) addFormItemFromConfigInt64(configItem *config.ConfigItem, p
 		fi.SetValidator(configItem.GetValidatorFunction().(model.Int64Validator))
 	}
 	f.formItemToConf[fi] = configItem
-	f.addFormItem(fi, transformations.Integer64ToString(configItem.GetDefaultValue().(int64)))
+	f.addFormItem(fi, configValueToFormValue(configItem, configItem.GetCurrentValue()))
 }
 
-// addFormItemFromConfigVector sets up a FormItemInt64 from a ConfigItem structure.
+// addFormItemFromConfigVector sets up a FormItemVector from a ConfigItem structure.
 func (f *FormScreen) addFormItemFromConfigVector(configItem *config.ConfigItem, pos mgl32.Vec3) {
 	asp := 1.0 / f.GetAspectRatio()
 	fi := model.NewFormItemVector(f.GetFullWidth(), model.ITEM_WIDTH_FULL, asp, configItem.GetLabel(), configItem.GetDescription(), model.CHAR_NUM_FLOAT, f.formItemDefaultMaterial, pos, f.wrapper)
@@ -772,7 +1532,50 @@ func (f *FormScreen) addFormItemFromConfigVector(configItem *config.ConfigItem,
 		fi.SetValidator(configItem.GetValidatorFunction().(model.FloatValidator))
 	}
 	f.formItemToConf[fi] = configItem
-	f.addFormItem(fi, transformations.VectorToString(configItem.GetDefaultValue().(mgl32.Vec3)))
+	f.addFormItem(fi, configValueToFormValue(configItem, configItem.GetCurrentValue()))
+}
+
+// addFormItemFromConfigEnum sets up a FormItemSelect from a ConfigItem structure.
+func (f *FormScreen) addFormItemFromConfigEnum(configItem *config.ConfigItem, pos mgl32.Vec3) {
+	asp := 1.0 / f.GetAspectRatio()
+	fi := model.NewFormItemSelect(f.GetFullWidth(), model.ITEM_WIDTH_HALF, asp, configItem.GetLabel(), configItem.GetDescription(), configItem.GetOptions(), f.formItemDefaultMaterial, pos, f.wrapper)
+	f.formItemToConf[fi] = configItem
+	f.addFormItem(fi, configItem.GetCurrentValue())
+}
+
+// addFormItemFromConfigSlider sets up a FormItemSlider from a ConfigItem structure.
+func (f *FormScreen) addFormItemFromConfigSlider(configItem *config.ConfigItem, pos mgl32.Vec3) {
+	asp := 1.0 / f.GetAspectRatio()
+	min, max, step := configItem.GetRange()
+	fi := model.NewFormItemSlider(f.GetFullWidth(), model.ITEM_WIDTH_HALF, asp, configItem.GetLabel(), configItem.GetDescription(), min, max, step, f.formItemDefaultMaterial, pos, f.wrapper)
+	f.formItemToConf[fi] = configItem
+	f.addFormItem(fi, configItem.GetCurrentValue())
+}
+
+// addFormItemFromConfigColor sets up a FormItemColor from a ConfigItem structure.
+func (f *FormScreen) addFormItemFromConfigColor(configItem *config.ConfigItem, pos mgl32.Vec3) {
+	asp := 1.0 / f.GetAspectRatio()
+	fi := model.NewFormItemColor(f.GetFullWidth(), model.ITEM_WIDTH_FULL, asp, configItem.GetLabel(), configItem.GetDescription(), f.formItemDefaultMaterial, pos, f.wrapper)
+	f.formItemToConf[fi] = configItem
+	f.addFormItem(fi, configValueToFormValue(configItem, configItem.GetCurrentValue()))
+}
+
+// addFormItemFromConfigFilePath sets up a FormItemText from a file path ConfigItem structure.
+// The validator is not set to the form item, because the path is valid only as a whole, so
+// that it is checked by the validation of the form.
+func (f *FormScreen) addFormItemFromConfigFilePath(configItem *config.ConfigItem, pos mgl32.Vec3) {
+	asp := 1.0 / f.GetAspectRatio()
+	fi := model.NewFormItemText(f.GetFullWidth(), model.ITEM_WIDTH_FULL, asp, configItem.GetLabel(), configItem.GetDescription(), f.formItemDefaultMaterial, pos, f.wrapper)
+	f.formItemToConf[fi] = configItem
+	f.addFormItem(fi, configValueToFormValue(configItem, configItem.GetCurrentValue()))
+}
+
+// addFormItemFromConfigKeyBinding sets up a FormItemKeyBinding from a ConfigItem structure.
+func (f *FormScreen) addFormItemFromConfigKeyBinding(configItem *config.ConfigItem, pos mgl32.Vec3) {
+	asp := 1.0 / f.GetAspectRatio()
+	fi := model.NewFormItemKeyBinding(f.GetFullWidth(), model.ITEM_WIDTH_HALF, asp, configItem.GetLabel(), configItem.GetDescription(), f.formItemDefaultMaterial, pos, f.wrapper)
+	f.formItemToConf[fi] = configItem
+	f.addFormItem(fi, configValueToFormValue(configItem, configItem.GetCurrentValue()))
 }
 
 func (f *FormScreen) setDefaultValueChar(input string) {
@@ -782,24 +1585,21 @@ func (f *FormScreen) setDefaultValueChar(input string) {
 	}
 }
 
-// CharCallback is the character stream input handler
+// CharCallback is the character stream input handler. If the form item doesn't accept
+// the character, the validation message is displayed next to it.
 func (f *FormScreen) CharCallback(char rune, wrapper interfaces.GLWrapper) {
 	if f.underEdit != nil {
 		aspRatio := f.GetAspectRatio()
 		textScale := InputTextFontScale / f.windowWidth * aspRatio
 		offsetX := f.charset.TextWidth(string(char), textScale)
+		valueBefore := f.underEdit.ValueToString()
 		f.underEdit.CharCallback(char, offsetX)
-		f.syncFormItemValuesToConfigValue()
-		f.charset.CleanSurface(f.underEdit.GetTarget())
-		_, hW := f.charset.TextContainerSize("W", textScale)
-		switch f.underEdit.(type) {
-		case *model.FormItemVector:
-			f.charset.PrintTo(f.underEdit.ValueToString(), -f.underEdit.(*model.FormItemVector).GetVectorCursorInitialPosition().X(), -hW/2, ZText, textScale, wrapper, f.underEdit.GetTarget(), []mgl32.Vec3{f.formItemInputColor})
-			break
-		default:
-			f.charset.PrintTo(f.underEdit.ValueToString(), -f.underEdit.GetCursorInitialPosition().X(), -hW/2, ZText, textScale, wrapper, f.underEdit.GetTarget(), []mgl32.Vec3{f.formItemInputColor})
-			break
+		if f.underEdit.ValueToString() == valueBefore {
+			f.setValidationError(f.underEdit, f.validationMessage(f.underEdit))
+		} else {
+			f.validateFormItem(f.underEdit)
 		}
+		f.printFormItemValue(f.underEdit)
 	}
 }
 
@@ -815,60 +1615,62 @@ func (f *FormScreen) GetFormItem(configKey string) interfaces.FormItem {
 }
 
 // SetFormItemValue gets a FormItem, a value and sets the value of the form item to the new one.
+// The value of the character based items is a string (the vector gets [3]string), the bool item
+// gets bool. The select and the key binding items get string, the slider gets float32 and the color
+// gets mgl32.Vec3. The config is not updated, it is done by the Save function.
 func (f *FormScreen) SetFormItemValue(item interfaces.FormItem, valueNew interface{}) {
+	edited := f.underEdit
 	switch item.(type) {
-	case *model.FormItemInt:
-		f.underEdit = item.(*model.FormItemInt)
-		value := f.underEdit.ValueToString()
-		valueLength := len(value) + strings.Count(value, " ")
-		for i := 0; i < valueLength; i++ {
-			f.underEdit.DeleteLastCharacter()
-		}
-		f.setDefaultValueChar(valueNew.(string))
+	case *model.FormItemBool:
+		item.(*model.FormItemBool).SetValue(valueNew.(bool))
 		break
-	case *model.FormItemFloat:
-		f.underEdit = item.(*model.FormItemFloat)
-		value := f.underEdit.ValueToString()
-		valueLength := len(value) + strings.Count(value, " ")
-		for i := 0; i < valueLength; i++ {
-			f.underEdit.DeleteLastCharacter()
-		}
-		f.setDefaultValueChar(valueNew.(string))
+	case *model.FormItemSelect:
+		fi := item.(*model.FormItemSelect)
+		fi.SetValue(valueNew.(string))
+		f.printFormItemText(fi.GetTarget(), fi.ValueToString())
 		break
-	case *model.FormItemText:
-		f.underEdit = item.(*model.FormItemText)
-		value := f.underEdit.ValueToString()
-		valueLength := len(value) + strings.Count(value, " ")
-		for i := 0; i < valueLength; i++ {
-			f.underEdit.DeleteLastCharacter()
-		}
-		f.setDefaultValueChar(valueNew.(string))
+	case *model.FormItemSlider:
+		fi := item.(*model.FormItemSlider)
+		fi.SetValue(valueNew.(float32))
+		f.printFormItemText(fi.GetTarget(), fi.ValueToString())
 		break
-	case *model.FormItemInt64:
-		f.underEdit = item.(*model.FormItemInt64)
-		value := f.underEdit.ValueToString()
-		valueLength := len(value) + strings.Count(value, " ")
-		for i := 0; i < valueLength; i++ {
-			f.underEdit.DeleteLastCharacter()
-		}
-		f.setDefaultValueChar(valueNew.(string))
+	case *model.FormItemColor:
+		item.(*model.FormItemColor).SetValue(valueNew.(mgl32.Vec3))
 		break
-	case *model.FormItemBool:
-		fi := item.(*model.FormItemBool)
-		fi.SetValue(valueNew.(bool))
+	case *model.FormItemKeyBinding:
+		fi := item.(*model.FormItemKeyBinding)
+		if f.capturing == fi {
+			f.capturing = nil
+		}
+		fi.SetValue(valueNew.(string))
+		f.printFormItemText(fi.GetTarget(), fi.ValueToString())
 		break
 	case *model.FormItemVector:
-		f.underEdit = item.(*model.FormItemVector)
+		fi := item.(*model.FormItemVector)
+		currentTarget := fi.GetIndex(fi.GetTarget()) - 1
 		newValues := valueNew.([3]string)
 		for i := 0; i < 3; i++ {
-			item.(*model.FormItemVector).SetTarget(i)
-			value := f.underEdit.ValueToString()
-			valueLength := len(value) + strings.Count(value, " ")
-			for i := 0; i < valueLength; i++ {
-				f.underEdit.DeleteLastCharacter()
-			}
-			f.setDefaultValueChar(newValues[i])
+			fi.SetTarget(i)
+			f.setFormItemChars(fi, newValues[i])
 		}
+		fi.SetTarget(currentTarget)
 		break
+	case interfaces.CharFormItem:
+		f.setFormItemChars(item.(interfaces.CharFormItem), valueNew.(string))
+		break
+	}
+	f.underEdit = edited
+	if _, ok := f.formItemToConf[item]; ok {
+		f.validateFormItem(item)
 	}
 }
+
+// setFormItemChars deletes the characters of the current target of the form item, then it
+// types the characters of the new value.
+func (f *FormScreen) setFormItemChars(fi interfaces.CharFormItem, valueNew string) {
+	f.underEdit = fi
+	fi.SelectAll()
+	fi.DeleteSelection()
+	f.setDefaultValueChar(valueNew)
+	f.printFormItemValue(fi)
+}
@@ -3,6 +3,7 @@ package screen
 import (
 	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/akosgarai/playground_engine/pkg/config"
@@ -13,6 +14,7 @@ import (
 	"github.com/akosgarai/playground_engine/pkg/store"
 	"github.com/akosgarai/playground_engine/pkg/testhelper"
 
+	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 )
 
@@ -87,6 +89,47 @@ func TestFormScreenBuilderSetClearColor(t *testing.T) {
 		t.Error("Invalid background color.")
 	}
 }
+func TestFormScreenBuilderSetFormItemSelectionColor(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	color := mgl32.Vec3{1, 0, 1}
+	builder.SetFormItemSelectionColor(color)
+	if builder.formItemSelectionColor != color {
+		t.Error("Invalid form item selection color.")
+	}
+}
+func TestFormScreenBuilderSetFormItemPlaceholderColor(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	color := mgl32.Vec3{1, 0, 1}
+	builder.SetFormItemPlaceholderColor(color)
+	if builder.formItemPlaceholderColor != color {
+		t.Error("Invalid form item placeholder color.")
+	}
+}
+func TestFormScreenBuilderSetPlaceholder(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	builder.SetPlaceholder("key", "placeholder")
+	if builder.placeholders["key"] != "placeholder" {
+		t.Error("Invalid placeholder.")
+	}
+}
+func TestFormScreenBuilderSetMaxLength(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	builder.SetMaxLength("key", 3)
+	if builder.maxLengths["key"] != 3 {
+		t.Error("Invalid max length.")
+	}
+}
+func TestFormScreenBuilderSetClipboard(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	if _, ok := builder.clipboard.(glfwClipboard); !ok {
+		t.Error("The default clipboard should be the glfw clipboard.")
+	}
+	clipboard := &clipboardMock{}
+	builder.SetClipboard(clipboard)
+	if builder.clipboard != clipboard {
+		t.Error("Invalid clipboard.")
+	}
+}
 func TestFormScreenBuilderSetConfig(t *testing.T) {
 	conf := config.New()
 	builder := NewFormScreenBuilder()
@@ -199,6 +242,41 @@ func TestFormScreenBuilderAddConfigVector(t *testing.T) {
 		t.Error("Invalid config length.")
 	}
 }
+func TestFormScreenBuilderAddConfigEnum(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	key := builder.AddConfigEnum("label", "desc", "b", []string{"a", "b"})
+	if len(builder.config) != 1 || builder.config[key].GetValueType() != config.ValueTypeEnum {
+		t.Error("Invalid enum config.")
+	}
+}
+func TestFormScreenBuilderAddConfigSlider(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	key := builder.AddConfigSlider("label", "desc", 0.5, 0, 1, 0.1)
+	if len(builder.config) != 1 || builder.config[key].GetValueType() != config.ValueTypeSlider {
+		t.Error("Invalid slider config.")
+	}
+}
+func TestFormScreenBuilderAddConfigColor(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	key := builder.AddConfigColor("label", "desc", mgl32.Vec3{1, 0, 0})
+	if len(builder.config) != 1 || builder.config[key].GetCurrentValue() != config.Color(mgl32.Vec3{1, 0, 0}) {
+		t.Error("Invalid color config.")
+	}
+}
+func TestFormScreenBuilderAddConfigFilePath(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	key := builder.AddConfigFilePath("label", "desc", "/tmp", nil)
+	if len(builder.config) != 1 || builder.config[key].GetCurrentValue() != config.FilePath("/tmp") {
+		t.Error("Invalid file path config.")
+	}
+}
+func TestFormScreenBuilderAddConfigKeyBinding(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	key := builder.AddConfigKeyBinding("label", "desc", "key:W")
+	if len(builder.config) != 1 || builder.config[key].GetCurrentValue() != config.KeyBinding("key:W") {
+		t.Error("Invalid key binding config.")
+	}
+}
 func TestFormScreenBuilderSetFrameSize(t *testing.T) {
 	builder := NewFormScreenBuilder()
 	w := float32(2)
@@ -742,3 +820,355 @@ func TestFormScreenCharCallback(t *testing.T) {
 		}
 	}()
 }
+func TestFormScreenBuilderSetFormItemErrorColor(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	color := mgl32.Vec3{1, 0, 0}
+	builder.SetFormItemErrorColor(color)
+	if builder.formItemErrorColor != color {
+		t.Error("Invalid error color.")
+	}
+}
+func TestFormScreenBuilderSetValidationMessage(t *testing.T) {
+	builder := NewFormScreenBuilder()
+	builder.SetValidationMessage("key", "message")
+	if builder.validationMessages["key"] != "message" {
+		t.Error("Invalid validation message.")
+	}
+}
+func TestFormScreenSaveCancelReset(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Errorf("Shouldn't have panic, %#v.", r)
+			}
+		}()
+		runtime.LockOSThread()
+		testhelper.GlfwInit(glwrapper.GL_MAJOR_VERSION, glwrapper.GL_MINOR_VERSION)
+		wrapperReal.InitOpenGL()
+		defer testhelper.GlfwTerminate()
+		builder := NewFormScreenBuilder()
+		builder.SetWrapper(wrapperReal)
+		builder.SetWindowSize(800, 800)
+		intKey := builder.AddConfigInt("int", DefaultFormItemDescription, 5, func(i int) bool { return i < 100 })
+		boolKey := builder.AddConfigBool("bool", DefaultFormItemDescription, false)
+		builder.SetValidationMessage(intKey, "Less than 100")
+		builder.SetConfigOrder([]string{intKey, boolKey})
+		conf := builder.config
+		form := builder.Build()
+		var changes []interface{}
+		form.OnChange(intKey, func(v interface{}) { changes = append(changes, v) })
+		form.SetFormItemValue(form.GetFormItem(intKey), "7")
+		form.SetFormItemValue(form.GetFormItem(boolKey), true)
+		if conf[intKey].GetCurrentValue() != 5 || !form.HasChanges() {
+			t.Error("The config should be updated only on save.")
+		}
+		form.Cancel()
+		if form.GetFormItem(intKey).ValueToString() != "5" || form.GetFormItem(boolKey).ValueToString() != "false" || form.HasChanges() {
+			t.Error("The cancel should restore the config values.")
+		}
+		form.SetFormItemValue(form.GetFormItem(intKey), "7")
+		if !form.Save() || conf[intKey].GetCurrentValue() != 7 || conf[boolKey].GetCurrentValue() != false {
+			t.Error("The save should update the config.")
+		}
+		if len(changes) != 1 || changes[0] != 7 {
+			t.Errorf("Invalid change callbacks '%v'.", changes)
+		}
+		form.ResetToDefault()
+		if form.GetFormItem(intKey).ValueToString() != "5" || conf[intKey].GetCurrentValue() != 7 {
+			t.Error("The reset should set the default values to the form items.")
+		}
+		// the rejected character displays the validation message.
+		form.underEdit = form.GetFormItem(intKey).(*model.FormItemInt)
+		form.CharCallback('0', wrapperReal)
+		form.CharCallback('0', wrapperReal)
+		if form.GetValidationError(intKey) != "Less than 100" || form.GetFormItem(intKey).ValueToString() != "50" {
+			t.Errorf("Invalid validation error '%s'.", form.GetValidationError(intKey))
+		}
+		form.CharCallback('a', wrapperReal)
+		if form.GetValidationError(intKey) == "" {
+			t.Error("The invalid character should display the message.")
+		}
+		form.underEdit.DeleteLastCharacter()
+		form.CharCallback('1', wrapperReal)
+		if form.GetValidationError(intKey) != "" {
+			t.Error("The valid value should remove the message.")
+		}
+	}()
+}
+func TestFormScreenReloadConfig(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Errorf("Shouldn't have panic, %#v.", r)
+			}
+		}()
+		runtime.LockOSThread()
+		testhelper.GlfwInit(glwrapper.GL_MAJOR_VERSION, glwrapper.GL_MINOR_VERSION)
+		wrapperReal.InitOpenGL()
+		defer testhelper.GlfwTerminate()
+		builder := NewFormScreenBuilder()
+		builder.SetWrapper(wrapperReal)
+		builder.SetWindowSize(800, 800)
+		intKey := builder.AddConfigInt("int", DefaultFormItemDescription, 5, nil)
+		textKey := builder.AddConfigText("text", DefaultFormItemDescription, "text", nil)
+		builder.SetConfigOrder([]string{intKey, textKey})
+		conf := builder.config
+		form := builder.Build()
+		var changes []interface{}
+		form.OnChange(intKey, func(v interface{}) { changes = append(changes, v) })
+		conf.SetCurrentValue(intKey, 8)
+		conf.SetCurrentValue(textKey, "reloaded")
+		form.underEdit = form.GetFormItem(textKey).(*model.FormItemText)
+		form.ReloadConfig([]string{intKey, textKey})
+		if form.GetFormItem(intKey).ValueToString() != "8" {
+			t.Errorf("Invalid reloaded value '%s'.", form.GetFormItem(intKey).ValueToString())
+		}
+		if form.GetFormItem(textKey).ValueToString() != "text" {
+			t.Error("The edited item should keep its value.")
+		}
+		if len(changes) != 1 || changes[0] != 8 {
+			t.Errorf("Invalid change callbacks '%v'.", changes)
+		}
+	}()
+}
+func TestFormScreenFocusTraversal(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Errorf("Shouldn't have panic, %#v.", r)
+			}
+		}()
+		runtime.LockOSThread()
+		testhelper.GlfwInit(glwrapper.GL_MAJOR_VERSION, glwrapper.GL_MINOR_VERSION)
+		wrapperReal.InitOpenGL()
+		defer testhelper.GlfwTerminate()
+		builder := NewFormScreenBuilder()
+		builder.SetWrapper(wrapperReal)
+		builder.SetWindowSize(800, 800)
+		textKey := builder.AddConfigText("text", DefaultFormItemDescription, "", nil)
+		boolKey := builder.AddConfigBool("bool", DefaultFormItemDescription, false)
+		vectorKey := builder.AddConfigVector("vector", DefaultFormItemDescription, mgl32.Vec3{0, 1, 0}, nil)
+		builder.SetConfigOrder([]string{textKey, boolKey, vectorKey})
+		form := builder.Build()
+		if form.underEdit != nil {
+			t.Error("The form shouldn't be edited after the build.")
+		}
+		ks := store.NewGlfwKeyStore()
+		ms := store.NewGlfwMouseStore()
+		ptr := pointer.New(0.0, -0.9, 0.0, 0.0)
+		ks.Set(KEY_TAB, true)
+		form.Update(EventEpsilon+1, ptr, ks, ms)
+		if form.underEdit != form.GetFormItem(textKey) {
+			t.Error("The tab should focus the first input.")
+		}
+		form.Update(EventEpsilon+1, ptr, ks, ms)
+		vector := form.GetFormItem(vectorKey).(*model.FormItemVector)
+		if form.underEdit != vector || vector.GetIndex(vector.GetTarget()) != 1 {
+			t.Error("The bool item should be skipped.")
+		}
+		form.Update(EventEpsilon+1, ptr, ks, ms)
+		if vector.GetIndex(vector.GetTarget()) != 2 {
+			t.Error("The tab should focus the next component of the vector.")
+		}
+		ks.Set(KEY_TAB, false)
+		form.Update(EventEpsilon+1, ptr, ks, ms)
+		// shift tab moves backwards.
+		ks.Set(KEY_TAB, true)
+		ks.Set(glfw.KeyLeftShift, true)
+		form.Update(EventEpsilon+1, ptr, ks, ms)
+		form.Update(EventEpsilon+1, ptr, ks, ms)
+		if form.underEdit != form.GetFormItem(textKey) {
+			t.Error("The shift tab should focus the previous input.")
+		}
+		form.Update(EventEpsilon+1, ptr, ks, ms)
+		if form.underEdit != vector || vector.GetIndex(vector.GetTarget()) != 3 {
+			t.Error("The focus should be moved to the last input.")
+		}
+	}()
+}
+func TestFormScreenChoiceItems(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Errorf("Shouldn't have panic, %#v.", r)
+			}
+		}()
+		runtime.LockOSThread()
+		testhelper.GlfwInit(glwrapper.GL_MAJOR_VERSION, glwrapper.GL_MINOR_VERSION)
+		wrapperReal.InitOpenGL()
+		defer testhelper.GlfwTerminate()
+		builder := NewFormScreenBuilder()
+		builder.SetWrapper(wrapperReal)
+		builder.SetWindowSize(800, 800)
+		enumKey := builder.AddConfigEnum("enum", DefaultFormItemDescription, "low", []string{"low", "high"})
+		sliderKey := builder.AddConfigSlider("slider", DefaultFormItemDescription, 2, 0, 10, 1)
+		colorKey := builder.AddConfigColor("color", DefaultFormItemDescription, mgl32.Vec3{1, 0, 0})
+		pathKey := builder.AddConfigFilePath("path", DefaultFormItemDescription, "/tmp", func(s string) bool { return strings.HasPrefix(s, "/") })
+		bindingKey := builder.AddConfigKeyBinding("binding", DefaultFormItemDescription, "key:W")
+		builder.SetConfigOrder([]string{enumKey, sliderKey, colorKey, pathKey, bindingKey})
+		conf := builder.config
+		form := builder.Build()
+		if form.GetFormItem(enumKey).(*model.FormItemSelect).GetValue() != "low" || form.GetFormItem(sliderKey).(*model.FormItemSlider).GetValue() != 2 {
+			t.Error("The form items should get the config values.")
+		}
+		if form.GetFormItem(pathKey).ValueToString() != "/tmp" || form.GetFormItem(bindingKey).ValueToString() != "key:W" {
+			t.Error("The form items should get the config values.")
+		}
+		form.SetFormItemValue(form.GetFormItem(enumKey), "high")
+		form.SetFormItemValue(form.GetFormItem(sliderKey), float32(12))
+		form.SetFormItemValue(form.GetFormItem(colorKey), mgl32.Vec3{0, 1, 0})
+		form.SetFormItemValue(form.GetFormItem(bindingKey), "key:ctrl+S")
+		if !form.Save() {
+			t.Error("The save should be successful.")
+		}
+		if conf[enumKey].GetCurrentValue() != "high" || conf[sliderKey].GetCurrentValue() != float32(10) || conf[colorKey].GetCurrentValue() != config.Color(mgl32.Vec3{0, 1, 0}) || conf[bindingKey].GetCurrentValue() != config.KeyBinding("key:ctrl+S") {
+			t.Error("The save should update the config.")
+		}
+		form.SetFormItemValue(form.GetFormItem(pathKey), "tmp")
+		if form.GetValidationError(pathKey) == "" || form.Save() || conf[pathKey].GetCurrentValue() != config.FilePath("/tmp") {
+			t.Error("The invalid path shouldn't be saved.")
+		}
+		form.SetFormItemValue(form.GetFormItem(pathKey), "/var")
+		if !form.Save() || conf[pathKey].GetCurrentValue() != config.FilePath("/var") {
+			t.Error("The valid path should be saved.")
+		}
+		// the key binder captures the pressed key.
+		binder := form.GetFormItem(bindingKey).(*model.FormItemKeyBinding)
+		binder.StartCapture()
+		form.capturing = binder
+		ks := store.NewGlfwKeyStore()
+		ms := store.NewGlfwMouseStore()
+		ptr := pointer.New(0.0, -0.9, 0.0, 0.0)
+		ks.Set(glfw.KeyLeftShift, true)
+		form.Update(EventEpsilon+1, ptr, ks, ms)
+		if !binder.IsCapturing() {
+			t.Error("The modifier alone shouldn't stop the capture.")
+		}
+		ks.Set(KEY_TAB, true)
+		form.Update(EventEpsilon+1, ptr, ks, ms)
+		if binder.IsCapturing() || binder.GetValue() != "key:shift+Tab" || form.underEdit != nil {
+			t.Errorf("Invalid captured binding '%s'.", binder.GetValue())
+		}
+		// the select displays the options.
+		enum := form.GetFormItem(enumKey).(*model.FormItemSelect)
+		form.openOptions(enum)
+		if !enum.IsOpen() || form.openSelect != enum {
+			t.Error("The options should be displayed.")
+		}
+		form.OnExit()
+		if enum.IsOpen() || form.openSelect != nil {
+			t.Error("The exit should close the options.")
+		}
+	}()
+}
+
+type clipboardMock struct {
+	text string
+}
+
+func (c *clipboardMock) GetClipboardString() string     { return c.text }
+func (c *clipboardMock) SetClipboardString(text string) { c.text = text }
+
+func TestFormScreenTextEditing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer testhelper.GlfwTerminate()
+				t.Errorf("Shouldn't have panic, %#v.", r)
+			}
+		}()
+		runtime.LockOSThread()
+		testhelper.GlfwInit(glwrapper.GL_MAJOR_VERSION, glwrapper.GL_MINOR_VERSION)
+		wrapperReal.InitOpenGL()
+		defer testhelper.GlfwTerminate()
+		builder := NewFormScreenBuilder()
+		builder.SetWrapper(wrapperReal)
+		builder.SetWindowSize(800, 800)
+		textKey := builder.AddConfigText("text", DefaultFormItemDescription, "abcd", nil)
+		intKey := builder.AddConfigInt("int", DefaultFormItemDescription, 12, nil)
+		builder.SetConfigOrder([]string{textKey, intKey})
+		builder.SetMaxLength(textKey, 6)
+		builder.SetPlaceholder(textKey, "name")
+		clipboard := &clipboardMock{}
+		builder.SetClipboard(clipboard)
+		form := builder.Build()
+		text := form.GetFormItem(textKey).(*model.FormItemText)
+		if text.GetMaxLength() != 6 || text.GetPlaceholder() != "name" {
+			t.Error("The builder settings should be applied to the form item.")
+		}
+		form.moveFocus(1)
+		if form.underEdit != text {
+			t.Error("The text item should be edited.")
+		}
+		ks := store.NewGlfwKeyStore()
+		ms := store.NewGlfwMouseStore()
+		ptr := pointer.New(0.0, -0.9, 0.0, 0.0)
+		press := func(keys ...glfw.Key) {
+			for _, k := range keys {
+				ks.Set(k, true)
+			}
+			form.Update(EventEpsilon+1, ptr, ks, ms)
+			for _, k := range keys {
+				ks.Set(k, false)
+			}
+		}
+		press(glfw.KeyLeftShift, glfw.KeyLeft)
+		if text.GetSelectedText() != "d" {
+			t.Errorf("Invalid selected text '%s'.", text.GetSelectedText())
+		}
+		press(glfw.KeyLeftControl, glfw.KeyX)
+		if text.GetValue() != "abc" || clipboard.text != "d" {
+			t.Errorf("Invalid value '%s' or clipboard '%s' after cut.", text.GetValue(), clipboard.text)
+		}
+		press(glfw.KeyHome)
+		press(glfw.KeyLeftControl, glfw.KeyV)
+		if text.GetValue() != "dabc" {
+			t.Errorf("Invalid value '%s' after paste.", text.GetValue())
+		}
+		press(glfw.KeyDelete)
+		if text.GetValue() != "dbc" {
+			t.Errorf("Invalid value '%s' after delete.", text.GetValue())
+		}
+		clipboard.text = "xyzxyz"
+		press(glfw.KeyLeftControl, glfw.KeyV)
+		if text.GetValue() != "dxyzbc" {
+			t.Errorf("Invalid value '%s' after the paste over the max length.", text.GetValue())
+		}
+		press(glfw.KeyLeftControl, glfw.KeyA)
+		press(glfw.KeyLeftControl, glfw.KeyC)
+		if clipboard.text != "dxyzbc" {
+			t.Errorf("Invalid clipboard '%s' after copy.", clipboard.text)
+		}
+		press(BACK_SPACE)
+		if text.GetValue() != "" {
+			t.Errorf("Invalid value '%s' after the delete of the selection.", text.GetValue())
+		}
+		form.moveFocus(1)
+		number := form.GetFormItem(intKey).(*model.FormItemInt)
+		press(glfw.KeyEnd)
+		press(glfw.KeyLeft)
+		form.CharCallback('3', wrapperReal)
+		if number.GetValue() != 132 {
+			t.Errorf("Invalid value '%d' after the insert.", number.GetValue())
+		}
+	}()
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 
 	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/input"
 	"github.com/akosgarai/playground_engine/pkg/interfaces"
 	"github.com/akosgarai/playground_engine/pkg/material"
 	"github.com/akosgarai/playground_engine/pkg/mesh"
@@ -84,6 +85,9 @@ func (b *MenuScreenBuilder) defaultCharset() {
 	if err != nil {
 		panic(err)
 	}
+	if err := cs.AddFont(model.STYLE_REGULAR, baseDirScreen()+DefaultFallbackFontFile); err != nil {
+		panic(err)
+	}
 	cs.SetTransparent(true)
 	b.charset = cs
 }
@@ -268,9 +272,11 @@ func (s *MenuScreen) Update(dt float64, p interfaces.Pointer, keyStore interface
 	cursorX := float32(-posX) * s.frameWidth / 2
 	cursorY := float32(posY) / aspRatio * s.frameWidth / 2
 	direction := mgl32.Vec3{0, 0, 0}
-	if keyStore.Get(KEY_UP) && !keyStore.Get(KEY_DOWN) {
+	up := s.actionActive(input.ACTION_MENU_UP, keyStore.Get(KEY_UP))
+	down := s.actionActive(input.ACTION_MENU_DOWN, keyStore.Get(KEY_DOWN))
+	if up && !down {
 		direction = mgl32.Vec3{0, -1, 0}
-	} else if keyStore.Get(KEY_DOWN) && !keyStore.Get(KEY_UP) {
+	} else if down && !up {
 		direction = mgl32.Vec3{0, 1, 0}
 	}
 	newScrollOffset := s.currentScrollOffset + FormItemMoveSpeed*float32(dt)*direction.Y()
@@ -305,7 +311,7 @@ func (s *MenuScreen) Update(dt float64, p interfaces.Pointer, keyStore interface
 		tmMesh := s.closestMesh.(*mesh.TexturedMaterialMesh)
 		if s.closestDistance < 0.01 {
 			tmMesh.Material = s.hoverMaterial
-			if buttonStore.Get(LEFT_MOUSE_BUTTON) && s.surfaceToOption[s.closestMesh].clickEvent != nil {
+			if s.actionActive(input.ACTION_SELECT, buttonStore.Get(LEFT_MOUSE_BUTTON)) && s.surfaceToOption[s.closestMesh].clickEvent != nil {
 				s.surfaceToOption[s.closestMesh].clickEvent()
 			}
 		} else {
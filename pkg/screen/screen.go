@@ -9,7 +9,9 @@ import (
 	"runtime"
 	"strconv"
 
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
 	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/texture"
 
 	"github.com/akosgarai/coldet"
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -29,11 +31,59 @@ func baseDirScreen() string {
 
 type SetupFunction func(wrapper interfaces.GLWrapper)
 
+// GamepadBinding connects a gamepad input to a camera movement. If the Direction
+// is 0, the Button is used, otherwise the Axis in the given direction (1 or -1).
+type GamepadBinding struct {
+	Axis      glfw.GamepadAxis
+	Direction float32
+	Button    glfw.GamepadButton
+}
+
+// value returns the amount of the binding in the [0-1] interval.
+func (b GamepadBinding) value(store interfaces.RoGamepadStore) float32 {
+	if b.Direction == 0 {
+		if store.Get(b.Button) {
+			return 1.0
+		}
+		return 0.0
+	}
+	v := store.GetAxis(b.Axis) * b.Direction
+	if v < 0 {
+		return 0.0
+	}
+	return v
+}
+
+// DefaultGamepadMovementMap returns the camera movement bindings of the standard
+// gamepad layout. The left stick moves, the right stick rotates the camera, the
+// bumpers lift it.
+func DefaultGamepadMovementMap() map[string]GamepadBinding {
+	return map[string]GamepadBinding{
+		"forward":     {Axis: glfw.AxisLeftY, Direction: -1},
+		"back":        {Axis: glfw.AxisLeftY, Direction: 1},
+		"left":        {Axis: glfw.AxisLeftX, Direction: -1},
+		"right":       {Axis: glfw.AxisLeftX, Direction: 1},
+		"up":          {Button: glfw.ButtonRightBumper},
+		"down":        {Button: glfw.ButtonLeftBumper},
+		"rotateLeft":  {Axis: glfw.AxisRightX, Direction: -1},
+		"rotateRight": {Axis: glfw.AxisRightX, Direction: 1},
+		"rotateUp":    {Axis: glfw.AxisRightY, Direction: -1},
+		"rotateDown":  {Axis: glfw.AxisRightY, Direction: 1},
+	}
+}
+
 type ScreenBase struct {
 	camera     interfaces.Camera
 	cameraMode string
 	// it holds the keyMaps for the camera movement, multiple keys allowed.
 	cameraKeyboardMovementMap map[string][]glfw.Key
+	// it holds the gamepad bindings for the camera movement.
+	cameraGamepadMovementMap map[string]GamepadBinding
+	// the state of the gamepad. Without gamepad it is nil.
+	gamepad interfaces.RoGamepadStore
+	// the action map of the application. If it has the action of an input,
+	// it is used instead of the hardcoded keys.
+	actions interfaces.RoActionMap
 	// rotateOnEdgeDistance stores the variable
 	// that is checked for rotating the camera
 	// if the mouse is close to the window edge
@@ -72,6 +122,9 @@ func newScreenBase() *ScreenBase {
 		pointLightSources:         []PointLightSource{},
 		spotLightSources:          []SpotLightSource{},
 		cameraKeyboardMovementMap: make(map[string][]glfw.Key),
+		cameraGamepadMovementMap:  DefaultGamepadMovementMap(),
+		gamepad:                   nil,
+		actions:                   nil,
 		rotateOnEdgeDistance:      0.0,
 		uniformFloat:              make(map[string]float32),
 		uniformVector:             make(map[string]mgl32.Vec3),
@@ -141,6 +194,48 @@ func (s *ScreenBase) SetupCamera(c interfaces.Camera, opts map[string]interface{
 			s.cameraKeyboardMovementMap[movementKeys[i]] = value.([]glfw.Key)
 		}
 	}
+	// the gamepad bindings are optional, the default is the standard layout.
+	if value, ok := opts["gamepad"]; ok {
+		s.cameraGamepadMovementMap = value.(map[string]GamepadBinding)
+	}
+}
+
+// SetGamepadStore sets the gamepad state, that is used for the camera movement
+// next to the keyboard. It is set by the application, if a gamepad is connected.
+func (s *ScreenBase) SetGamepadStore(store interfaces.RoGamepadStore) {
+	s.gamepad = store
+}
+
+// SetActionMap sets the action map, that is used instead of the keyboard and gamepad
+// maps for the actions that it contains. It is set by the application in every update.
+func (s *ScreenBase) SetActionMap(actions interfaces.RoActionMap) {
+	s.actions = actions
+}
+
+// hasAction returns true if the action map is set and it contains the action.
+func (s *ScreenBase) hasAction(name string) bool {
+	return s.actions != nil && s.actions.HasAction(name)
+}
+
+// actionActive returns the state of the action from the action map. Without
+// action map or action it returns the fallback value.
+func (s *ScreenBase) actionActive(name string, fallback bool) bool {
+	if s.hasAction(name) {
+		return s.actions.IsActive(name)
+	}
+	return fallback
+}
+
+// gamepadValue returns the value of the gamepad binding of the given movement.
+// Without gamepad or binding it returns 0.
+func (s *ScreenBase) gamepadValue(movement string) float32 {
+	if s.gamepad == nil {
+		return 0.0
+	}
+	if binding, ok := s.cameraGamepadMovementMap[movement]; ok {
+		return binding.value(s.gamepad)
+	}
+	return 0.0
 }
 
 // GetCamera returns the current camera of the screen.
@@ -367,45 +462,41 @@ func (s *ScreenBase) Draw(wrapper interfaces.GLWrapper) {
 	if s.setupFunction != nil {
 		s.setupFunction(wrapper)
 	}
-	// Draw the non transparent models first
-	for sh, _ := range s.shaderMap {
-		sh.Use()
-		if s.camera != nil {
-			sh.SetUniformMat4("view", s.camera.GetViewMatrix())
-			sh.SetUniformMat4("projection", s.camera.GetProjectionMatrix())
-			cameraPos := s.camera.GetPosition()
-			sh.SetUniform3f("viewPosition", cameraPos.X(), cameraPos.Y(), cameraPos.Z())
-		} else {
-			sh.SetUniformMat4("view", mgl32.Ident4())
-			sh.SetUniformMat4("projection", mgl32.Ident4())
-		}
-		s.lightHandler(sh)
-		// custom uniform setup.
-		s.customUniforms(sh)
-		for index, _ := range s.shaderMap[sh] {
-			if !s.shaderMap[sh][index].IsTransparent() {
-				s.shaderMap[sh][index].Draw(sh)
+	s.drawModels(mgl32.Ident4(), nil)
+}
+
+// DrawToRenderTarget draws the models to the given render target. The view matrix of the camera
+// is multiplied with the viewTransform, so that the reflection of the scene could be drawn with the
+// reflection matrix of the liquid. The skip model (eg. the liquid itself) is not drawn. After the
+// drawing the default framebuffer and the viewport of the window are restored.
+func (s *ScreenBase) DrawToRenderTarget(wrapper interfaces.GLWrapper, target *texture.RenderTarget, viewTransform mgl32.Mat4, skip interfaces.Model) {
+	target.Bind()
+	wrapper.Clear(glwrapper.COLOR_BUFFER_BIT | glwrapper.DEPTH_BUFFER_BIT)
+	s.drawModels(viewTransform, skip)
+	target.UnBind(int32(s.windowWidth), int32(s.windowHeight))
+}
+
+// drawModels draws the models except the skip model. The non transparent models are drawn first.
+func (s *ScreenBase) drawModels(viewTransform mgl32.Mat4, skip interfaces.Model) {
+	for _, transparent := range []bool{false, true} {
+		for sh, _ := range s.shaderMap {
+			sh.Use()
+			if s.camera != nil {
+				sh.SetUniformMat4("view", s.camera.GetViewMatrix().Mul4(viewTransform))
+				sh.SetUniformMat4("projection", s.camera.GetProjectionMatrix())
+				cameraPos := s.camera.GetPosition()
+				sh.SetUniform3f("viewPosition", cameraPos.X(), cameraPos.Y(), cameraPos.Z())
+			} else {
+				sh.SetUniformMat4("view", viewTransform)
+				sh.SetUniformMat4("projection", mgl32.Ident4())
 			}
-		}
-	}
-	// Draw transparent models
-	for sh, _ := range s.shaderMap {
-		sh.Use()
-		if s.camera != nil {
-			sh.SetUniformMat4("view", s.camera.GetViewMatrix())
-			sh.SetUniformMat4("projection", s.camera.GetProjectionMatrix())
-			cameraPos := s.camera.GetPosition()
-			sh.SetUniform3f("viewPosition", cameraPos.X(), cameraPos.Y(), cameraPos.Z())
-		} else {
-			sh.SetUniformMat4("view", mgl32.Ident4())
-			sh.SetUniformMat4("projection", mgl32.Ident4())
-		}
-		s.lightHandler(sh)
-		// custom uniform setup.
-		s.customUniforms(sh)
-		for index, _ := range s.shaderMap[sh] {
-			if s.shaderMap[sh][index].IsTransparent() {
-				s.shaderMap[sh][index].Draw(sh)
+			s.lightHandler(sh)
+			// custom uniform setup.
+			s.customUniforms(sh)
+			for index, _ := range s.shaderMap[sh] {
+				if s.shaderMap[sh][index] != skip && s.shaderMap[sh][index].IsTransparent() == transparent {
+					s.shaderMap[sh][index].Draw(sh)
+				}
 			}
 		}
 	}
@@ -469,6 +560,12 @@ func (s *Screen) CharCallback(char rune, w interfaces.GLWrapper) {
 // that is also added as function input. In case of invalid function name,
 // it prints out some message to the console.
 func (s *ScreenBase) cameraKeyboardMovement(directionKey, oppositeKey, handlerName string, delta float64, store interfaces.RoKeyStore) {
+	if s.hasAction(directionKey) || s.hasAction(oppositeKey) {
+		// the actions of the action map are used instead of the keyboard and gamepad maps.
+		amount := s.actions.Value(directionKey) - s.actions.Value(oppositeKey)
+		s.cameraMovement(handlerName, amount*float32(delta)*s.camera.GetVelocity())
+		return
+	}
 	keyStateDirection := false
 	keyStateOpposite := false
 	if val, ok := s.cameraKeyboardMovementMap[directionKey]; ok {
@@ -490,7 +587,17 @@ func (s *ScreenBase) cameraKeyboardMovement(directionKey, oppositeKey, handlerNa
 		step = float32(delta) * s.camera.GetVelocity()
 	} else if keyStateOpposite && !keyStateDirection {
 		step = -float32(delta) * s.camera.GetVelocity()
+	} else if !keyStateDirection && !keyStateOpposite {
+		// without keyboard input, the gamepad axes are used with analog speed.
+		amount := s.gamepadValue(directionKey) - s.gamepadValue(oppositeKey)
+		step = amount * float32(delta) * s.camera.GetVelocity()
 	}
+	s.cameraMovement(handlerName, step)
+}
+
+// cameraMovement calls the handler function of the camera with the given step, if the
+// bounding object of the camera doesn't collide after the step.
+func (s *ScreenBase) cameraMovement(handlerName string, step float32) {
 	if step != 0 {
 		// Collision detection. The function for the test is prefixed by 'BoundingObjectAfter'
 		boundingObjectFunc := reflect.ValueOf(s.camera).MethodByName("BoundingObjectAfter" + handlerName)
@@ -523,6 +630,13 @@ func (s *ScreenBase) cameraKeyboardMovement(directionKey, oppositeKey, handlerNa
 // cameraKeyboardRotation is responsible for handling the rotation events generated by the keyboard.
 // The rotation(Up|Down|Left|Right) keys are checked from the maps
 func (s *ScreenBase) cameraKeyboardRotation(delta float64, store interfaces.RoKeyStore) {
+	if s.hasAction("rotateLeft") || s.hasAction("rotateRight") || s.hasAction("rotateUp") || s.hasAction("rotateDown") {
+		// the actions of the action map are used instead of the keyboard and gamepad maps.
+		amountX := s.actions.Value("rotateRight") - s.actions.Value("rotateLeft")
+		amountY := s.actions.Value("rotateDown") - s.actions.Value("rotateUp")
+		s.applyRotation(amountX, amountY, delta)
+		return
+	}
 	rotateUp := false
 	rotateDown := false
 	rotateLeft := false
@@ -555,24 +669,39 @@ func (s *ScreenBase) cameraKeyboardRotation(delta float64, store interfaces.RoKe
 			}
 		}
 	}
+	if !rotateLeft && !rotateRight && !rotateUp && !rotateDown {
+		// without keyboard input, the gamepad axes are used with analog speed.
+		amountX := s.gamepadValue("rotateRight") - s.gamepadValue("rotateLeft")
+		amountY := s.gamepadValue("rotateDown") - s.gamepadValue("rotateUp")
+		s.applyRotation(amountX, amountY, delta)
+		return
+	}
 	s.applyMouseRotation(rotateLeft, rotateRight, rotateUp, rotateDown, delta)
 }
 
 // applyMouseRotation calls the camera's UpdateDirection function if necessary.
 func (s *ScreenBase) applyMouseRotation(rotateLeft, rotateRight, rotateUp, rotateDown bool, delta float64) {
-	dX := float32(0.0)
-	dY := float32(0.0)
+	amountX := float32(0.0)
+	amountY := float32(0.0)
 
 	if rotateLeft && !rotateRight {
-		dX = -s.camera.GetRotationStep() * float32(delta)
+		amountX = -1.0
 	} else if rotateRight && !rotateLeft {
-		dX = s.camera.GetRotationStep() * float32(delta)
+		amountX = 1.0
 	}
 	if rotateUp && !rotateDown {
-		dY = -s.camera.GetRotationStep() * float32(delta)
+		amountY = -1.0
 	} else if rotateDown && !rotateUp {
-		dY = s.camera.GetRotationStep() * float32(delta)
+		amountY = 1.0
 	}
+	s.applyRotation(amountX, amountY, delta)
+}
+
+// applyRotation calls the camera's UpdateDirection function with the rotation step
+// multiplied by the given amounts, if necessary. The amounts are in the [-1, 1] interval.
+func (s *ScreenBase) applyRotation(amountX, amountY float32, delta float64) {
+	dX := amountX * s.camera.GetRotationStep() * float32(delta)
+	dY := amountY * s.camera.GetRotationStep() * float32(delta)
 	if dX != 0.0 || dY != 0.0 {
 		s.camera.UpdateDirection(dX, dY)
 	}
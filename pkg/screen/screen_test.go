@@ -13,6 +13,7 @@ import (
 	"github.com/akosgarai/playground_engine/pkg/primitives/boundingobject"
 	"github.com/akosgarai/playground_engine/pkg/store"
 	"github.com/akosgarai/playground_engine/pkg/testhelper"
+	"github.com/akosgarai/playground_engine/pkg/texture"
 
 	"github.com/akosgarai/coldet"
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -471,6 +472,35 @@ func TestDraw(t *testing.T) {
 		screen.Draw(wrapperMock)
 	}()
 }
+
+// drawCounterModel counts the Draw calls.
+type drawCounterModel struct {
+	*model.BaseModel
+	draws int
+}
+
+func (m *drawCounterModel) Draw(s interfaces.Shader) {
+	m.draws++
+}
+func TestDrawToRenderTarget(t *testing.T) {
+	screen := New()
+	screen.AddShader(sm)
+	drawn := &drawCounterModel{BaseModel: model.New()}
+	skipped := &drawCounterModel{BaseModel: model.New()}
+	screen.AddModelToShader(drawn, sm)
+	screen.AddModelToShader(skipped, sm)
+	var textures texture.Textures
+	target := textures.AddRenderTarget(64, 64, "reflectionTexture", wrapperMock)
+	screen.DrawToRenderTarget(wrapperMock, target, mgl32.Scale3D(1, -1, 1), skipped)
+	if drawn.draws != 1 || skipped.draws != 0 {
+		t.Errorf("Invalid draw counts. drawn: '%d', skipped: '%d'.", drawn.draws, skipped.draws)
+	}
+	screen.SetupCamera(cam, map[string]interface{}{"mode": "fps"})
+	screen.DrawToRenderTarget(wrapperMock, target, mgl32.Ident4(), nil)
+	if drawn.draws != 2 || skipped.draws != 1 {
+		t.Errorf("Invalid draw counts. drawn: '%d', skipped: '%d'.", drawn.draws, skipped.draws)
+	}
+}
 func TestUpdateDefaultCamera(t *testing.T) {
 	func() {
 		defer func() {
@@ -565,6 +595,139 @@ func TestCameraKeyboardMovement(t *testing.T) {
 	screen.cameraKeyboardMovement("forward", "back", "Wrong", 10, st)
 
 }
+func TestCameraGamepadMovement(t *testing.T) {
+	screen := New()
+	gamepadCam := camera.NewCamera(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 1, 0}, 0, 0)
+	gamepadCam.SetVelocity(1)
+	optionsForDefaultCamera := map[string]interface{}{
+		"mode":                 "default",
+		"rotateOnEdgeDistance": float32(0.1),
+	}
+	screen.SetupCamera(gamepadCam, optionsForDefaultCamera)
+	st := store.NewGlfwKeyStore()
+	// without gamepad nothing happens.
+	screen.cameraKeyboardMovement("up", "down", "Lift", 10, st)
+	if gamepadCam.GetPosition() != (mgl32.Vec3{0, 0, 0}) {
+		t.Errorf("Camera shouldn't move without gamepad. '%v'.", gamepadCam.GetPosition())
+	}
+	gamepad := store.NewGlfwGamepadStore()
+	gamepad.SetDeadZone(0.0)
+	screen.SetGamepadStore(gamepad)
+	gamepad.Set(glfw.ButtonRightBumper, true)
+	screen.cameraKeyboardMovement("up", "down", "Lift", 10, st)
+	lifted := gamepadCam.GetPosition()
+	if lifted == (mgl32.Vec3{0, 0, 0}) {
+		t.Error("Camera should be lifted by the bumper.")
+	}
+	gamepad.Set(glfw.ButtonRightBumper, false)
+	// the half pushed stick moves half as much as the full one.
+	gamepad.SetAxis(glfw.AxisLeftY, -0.5)
+	screen.cameraKeyboardMovement("forward", "back", "Walk", 10, st)
+	half := gamepadCam.GetPosition().Sub(lifted).Len()
+	gamepad.SetAxis(glfw.AxisLeftY, -1.0)
+	screen.cameraKeyboardMovement("forward", "back", "Walk", 10, st)
+	full := gamepadCam.GetPosition().Sub(lifted).Len() - half
+	if half == 0 || !mgl32.FloatEqualThreshold(2*half, full, 0.0001) {
+		t.Errorf("Invalid analog movement. '%f', '%f'.", half, full)
+	}
+	// custom bindings.
+	screen.SetupCamera(gamepadCam, map[string]interface{}{
+		"mode":    "fps",
+		"gamepad": map[string]GamepadBinding{},
+	})
+	before := gamepadCam.GetPosition()
+	screen.cameraKeyboardMovement("forward", "back", "Walk", 10, st)
+	if gamepadCam.GetPosition() != before {
+		t.Error("Camera shouldn't move without binding.")
+	}
+}
+
+type actionMapMock map[string]float32
+
+func (m actionMapMock) HasAction(name string) bool {
+	_, ok := m[name]
+	return ok
+}
+func (m actionMapMock) IsEnabled(name string) bool {
+	return m.HasAction(name)
+}
+func (m actionMapMock) IsActive(name string) bool {
+	return m[name] > 0
+}
+func (m actionMapMock) Value(name string) float32 {
+	return m[name]
+}
+
+func TestCameraActionMovement(t *testing.T) {
+	screen := New()
+	actionCam := camera.NewCamera(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 1, 0}, 0, 0)
+	actionCam.SetVelocity(1)
+	screen.SetupCamera(actionCam, map[string]interface{}{
+		"mode":    "fps",
+		"up":      []glfw.Key{glfw.KeyU},
+		"forward": []glfw.Key{glfw.KeyW},
+	})
+	st := store.NewGlfwKeyStore()
+	st.Set(glfw.KeyU, true)
+	// the action map overrides the keyboard map.
+	screen.SetActionMap(actionMapMock{"up": 0, "down": 0})
+	screen.cameraKeyboardMovement("up", "down", "Lift", 10, st)
+	if actionCam.GetPosition() != (mgl32.Vec3{0, 0, 0}) {
+		t.Errorf("Camera shouldn't move without active action. '%v'.", actionCam.GetPosition())
+	}
+	screen.SetActionMap(actionMapMock{"up": 0.5, "down": 0})
+	screen.cameraKeyboardMovement("up", "down", "Lift", 10, st)
+	if actionCam.GetPosition() == (mgl32.Vec3{0, 0, 0}) {
+		t.Error("Camera should be lifted by the action.")
+	}
+	// without the action, the keyboard map is used.
+	lifted := actionCam.GetPosition()
+	st.Set(glfw.KeyW, true)
+	screen.cameraKeyboardMovement("forward", "back", "Walk", 10, st)
+	if actionCam.GetPosition() == lifted {
+		t.Error("Camera should walk by the keyboard.")
+	}
+	if !screen.actionActive("up", false) || screen.actionActive("down", true) || !screen.actionActive("missing", true) {
+		t.Error("Invalid action states.")
+	}
+	screen.SetActionMap(nil)
+	if screen.actionActive("up", false) {
+		t.Error("Without action map the fallback should be used.")
+	}
+}
+func TestCameraActionRotation(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("Shouldn't have panic.")
+			}
+		}()
+		screen := New()
+		screen.SetupCamera(cam, map[string]interface{}{"mode": "fps"})
+		screen.SetActionMap(actionMapMock{"rotateLeft": 1})
+		screen.cameraKeyboardRotation(10, store.NewGlfwKeyStore())
+	}()
+}
+func TestCameraGamepadRotation(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Error("Shouldn't have panic.")
+			}
+		}()
+		screen := New()
+		optionsForDefaultCamera := map[string]interface{}{
+			"mode":                 "default",
+			"rotateOnEdgeDistance": float32(0.1),
+		}
+		screen.SetupCamera(cam, optionsForDefaultCamera)
+		gamepad := store.NewGlfwGamepadStore()
+		screen.SetGamepadStore(gamepad)
+		gamepad.SetAxis(glfw.AxisRightX, 0.8)
+		gamepad.SetAxis(glfw.AxisRightY, -0.3)
+		screen.cameraKeyboardRotation(10, store.NewGlfwKeyStore())
+	}()
+}
 func TestCameraKeyboardRotationWithoutKeymap(t *testing.T) {
 	func() {
 		defer func() {
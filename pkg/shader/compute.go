@@ -0,0 +1,93 @@
+package shader
+
+import (
+	"errors"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+)
+
+var (
+	ErrorComputeShaderNotSupported = errors.New("The compute shaders are not supported by the gl context")
+)
+
+// ComputeShader is a shader program with a single compute stage. It could be
+// used for the gpu side calculations, like the liquid wave simulation.
+// The uniform setter functions of the Shader are also available.
+type ComputeShader struct {
+	Shader
+}
+
+// NewComputeShader returns a ComputeShader. It's input is the filename of the compute shader.
+// It reads the file and compiles it. The shader is attached to the shader program.
+// The compute stage needs gl 4.3 or the ARB_compute_shader extension, if the context doesn't
+// support it, it returns ErrorComputeShaderNotSupported. The 4.3 context could be requested
+// with the SetContextVersion function of the window builder.
+func NewComputeShader(computeShaderPath string, wrapper interfaces.GLWrapper) (*ComputeShader, error) {
+	if !wrapper.ComputeShaderSupported() {
+		return nil, ErrorComputeShaderNotSupported
+	}
+	s := newShaderProgram([]string{computeShaderPath}, []uint32{glwrapper.COMPUTE_SHADER}, wrapper)
+	return &ComputeShader{
+		Shader: *s,
+	}, nil
+}
+
+// BindStorageBuffer binds the given storage buffer to the given binding point index.
+// The index has to match the `binding` layout qualifier of the buffer block in the shader.
+func (cs *ComputeShader) BindStorageBuffer(index uint32, buffer *StorageBuffer) {
+	cs.wrapper.BindBufferBase(glwrapper.SHADER_STORAGE_BUFFER, index, buffer.GetId())
+}
+
+// Dispatch runs the compute shader with the given number of work groups.
+// After the dispatch, it waits for the storage buffer writes with a memory barrier,
+// so that the results could be used in the following draw calls.
+func (cs *ComputeShader) Dispatch(numGroupsX, numGroupsY, numGroupsZ uint32) {
+	cs.Use()
+	cs.wrapper.DispatchCompute(numGroupsX, numGroupsY, numGroupsZ)
+	cs.wrapper.MemoryBarrier(glwrapper.SHADER_STORAGE_BARRIER_BIT)
+}
+
+// StorageBuffer is a float32 shader storage buffer object. It could be
+// read and written by the compute shaders.
+type StorageBuffer struct {
+	id      uint32
+	size    int
+	wrapper interfaces.GLWrapper
+}
+
+// NewStorageBuffer generates a new storage buffer and uploads the given data to it.
+func NewStorageBuffer(data []float32, wrapper interfaces.GLWrapper) *StorageBuffer {
+	sb := &StorageBuffer{
+		id:      wrapper.GenBuffers(),
+		wrapper: wrapper,
+	}
+	sb.Update(data)
+	return sb
+}
+
+// GetId returns the buffer object id.
+func (sb *StorageBuffer) GetId() uint32 {
+	return sb.id
+}
+
+// Len returns the number of the float32 values that are stored in the buffer.
+func (sb *StorageBuffer) Len() int {
+	return sb.size
+}
+
+// Update uploads the given data to the buffer. The size of the buffer is set to the length of the data.
+func (sb *StorageBuffer) Update(data []float32) {
+	sb.wrapper.BindBuffer(glwrapper.SHADER_STORAGE_BUFFER, sb.id)
+	sb.wrapper.StorageBufferData(data)
+	sb.size = len(data)
+}
+
+// Read returns the content of the buffer. It could be used for getting
+// the result of the compute shaders on the cpu side.
+func (sb *StorageBuffer) Read() []float32 {
+	data := make([]float32, sb.size)
+	sb.wrapper.BindBuffer(glwrapper.SHADER_STORAGE_BUFFER, sb.id)
+	sb.wrapper.GetStorageBufferData(data)
+	return data
+}
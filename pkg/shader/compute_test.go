@@ -0,0 +1,82 @@
+package shader
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/testhelper"
+)
+
+// computeWrapperMock records the compute related gl calls.
+type computeWrapperMock struct {
+	testhelper.GLWrapperMock
+	supported bool
+	calls     []string
+}
+
+func (w *computeWrapperMock) ComputeShaderSupported() bool {
+	return w.supported
+}
+func (w *computeWrapperMock) UseProgram(id uint32) {
+	w.calls = append(w.calls, fmt.Sprintf("UseProgram(%d)", id))
+}
+func (w *computeWrapperMock) BindBufferBase(target uint32, index uint32, buffer uint32) {
+	w.calls = append(w.calls, fmt.Sprintf("BindBufferBase(%d, %d, %d)", target, index, buffer))
+}
+func (w *computeWrapperMock) DispatchCompute(numGroupsX, numGroupsY, numGroupsZ uint32) {
+	w.calls = append(w.calls, fmt.Sprintf("DispatchCompute(%d, %d, %d)", numGroupsX, numGroupsY, numGroupsZ))
+}
+func (w *computeWrapperMock) MemoryBarrier(barriers uint32) {
+	w.calls = append(w.calls, fmt.Sprintf("MemoryBarrier(%d)", barriers))
+}
+
+func TestNewStorageBuffer(t *testing.T) {
+	data := []float32{0.0, 1.0, 2.0, 3.0}
+	sb := NewStorageBuffer(data, testGlWrapper)
+	if sb.Len() != len(data) {
+		t.Errorf("Invalid buffer length. Instead of '%d', we have '%d'.", len(data), sb.Len())
+	}
+	if sb.GetId() != sb.id {
+		t.Errorf("Invalid buffer id. Instead of '%d', we have '%d'.", sb.id, sb.GetId())
+	}
+}
+func TestStorageBufferUpdate(t *testing.T) {
+	sb := NewStorageBuffer([]float32{0.0}, testGlWrapper)
+	data := []float32{0.0, 1.0, 2.0}
+	sb.Update(data)
+	if sb.Len() != len(data) {
+		t.Errorf("Invalid buffer length. Instead of '%d', we have '%d'.", len(data), sb.Len())
+	}
+}
+func TestStorageBufferRead(t *testing.T) {
+	data := []float32{0.0, 1.0, 2.0}
+	sb := NewStorageBuffer(data, testGlWrapper)
+	result := sb.Read()
+	if len(result) != len(data) {
+		t.Errorf("Invalid result length. Instead of '%d', we have '%d'.", len(data), len(result))
+	}
+}
+func TestNewComputeShaderNotSupported(t *testing.T) {
+	cs, err := NewComputeShader("./shaders/missing.comp", &computeWrapperMock{supported: false})
+	if cs != nil || err != ErrorComputeShaderNotSupported {
+		t.Errorf("Invalid result. Instead of 'nil, %v', we have '%v, %v'.", ErrorComputeShaderNotSupported, cs, err)
+	}
+}
+func TestComputeShaderDispatch(t *testing.T) {
+	wrapper := &computeWrapperMock{supported: true}
+	cs := &ComputeShader{Shader{id: 1, wrapper: wrapper}}
+	sb := NewStorageBuffer([]float32{0.0, 1.0, 2.0}, wrapper)
+	cs.BindStorageBuffer(2, sb)
+	cs.Dispatch(4, 2, 1)
+	expected := []string{
+		fmt.Sprintf("BindBufferBase(%d, 2, %d)", glwrapper.SHADER_STORAGE_BUFFER, sb.GetId()),
+		"UseProgram(1)",
+		"DispatchCompute(4, 2, 1)",
+		fmt.Sprintf("MemoryBarrier(%d)", glwrapper.SHADER_STORAGE_BARRIER_BIT),
+	}
+	if !reflect.DeepEqual(wrapper.calls, expected) {
+		t.Errorf("Invalid gl calls. Instead of '%v', we have '%v'.", expected, wrapper.calls)
+	}
+}
@@ -22,26 +22,58 @@ type Shader struct {
 // NewShader returns a Shader. It's inputs are the filenames of the shaders.
 // It reads the files and compiles them. The shaders are attached to the shader program.
 func NewShader(vertexShaderPath, fragmentShaderPath string, wrapper interfaces.GLWrapper) *Shader {
-	vertexShaderSource, err := LoadShaderFromFile(vertexShaderPath)
-	if err != nil {
-		panic(err)
-	}
-	vertexShader, err := CompileShader(vertexShaderSource, glwrapper.VERTEX_SHADER, wrapper)
-	if err != nil {
-		panic(err)
-	}
-	fragmentShaderSource, err := LoadShaderFromFile(fragmentShaderPath)
-	if err != nil {
-		panic(err)
-	}
-	fragmentShader, err := CompileShader(fragmentShaderSource, glwrapper.FRAGMENT_SHADER, wrapper)
-	if err != nil {
-		panic(err)
+	return newShaderProgram([]string{vertexShaderPath, fragmentShaderPath}, []uint32{glwrapper.VERTEX_SHADER, glwrapper.FRAGMENT_SHADER}, wrapper)
+}
+
+// NewShaderWithGeometry returns a Shader with vertex, geometry and fragment stages.
+// It works the same as NewShader, but the geometry shader is also compiled and attached to the program.
+func NewShaderWithGeometry(vertexShaderPath, geometryShaderPath, fragmentShaderPath string, wrapper interfaces.GLWrapper) *Shader {
+	return newShaderProgram(
+		[]string{vertexShaderPath, geometryShaderPath, fragmentShaderPath},
+		[]uint32{glwrapper.VERTEX_SHADER, glwrapper.GEOMETRY_SHADER, glwrapper.FRAGMENT_SHADER},
+		wrapper)
+}
+
+// NewShaderWithTessellation returns a Shader with vertex, tessellation control, tessellation evaluation
+// and fragment stages. It works the same as NewShader. The meshes that are drawn with this shader
+// have to be drawn as patches, see the SetPatchVertices function of the meshes.
+func NewShaderWithTessellation(vertexShaderPath, tessControlShaderPath, tessEvaluationShaderPath, fragmentShaderPath string, wrapper interfaces.GLWrapper) *Shader {
+	return newShaderProgram(
+		[]string{vertexShaderPath, tessControlShaderPath, tessEvaluationShaderPath, fragmentShaderPath},
+		[]uint32{glwrapper.VERTEX_SHADER, glwrapper.TESS_CONTROL_SHADER, glwrapper.TESS_EVALUATION_SHADER, glwrapper.FRAGMENT_SHADER},
+		wrapper)
+}
+
+// NewShaderWithTessellationAndGeometry returns a Shader with every optional stage. The order of
+// the stages is vertex, tessellation control, tessellation evaluation, geometry, fragment.
+func NewShaderWithTessellationAndGeometry(vertexShaderPath, tessControlShaderPath, tessEvaluationShaderPath, geometryShaderPath, fragmentShaderPath string, wrapper interfaces.GLWrapper) *Shader {
+	return newShaderProgram(
+		[]string{vertexShaderPath, tessControlShaderPath, tessEvaluationShaderPath, geometryShaderPath, fragmentShaderPath},
+		[]uint32{glwrapper.VERTEX_SHADER, glwrapper.TESS_CONTROL_SHADER, glwrapper.TESS_EVALUATION_SHADER, glwrapper.GEOMETRY_SHADER, glwrapper.FRAGMENT_SHADER},
+		wrapper)
+}
+
+// newShaderProgram loads and compiles the given shader files as the given shader types,
+// attaches them to a new program and links it. It panics if a file is missing or
+// a stage fails to compile.
+func newShaderProgram(shaderPaths []string, shaderTypes []uint32, wrapper interfaces.GLWrapper) *Shader {
+	var shaders []uint32
+	for i := 0; i < len(shaderPaths); i++ {
+		shaderSource, err := LoadShaderFromFile(shaderPaths[i])
+		if err != nil {
+			panic(err)
+		}
+		shader, err := CompileShader(shaderSource, shaderTypes[i], wrapper)
+		if err != nil {
+			panic(err)
+		}
+		shaders = append(shaders, shader)
 	}
 
 	program := wrapper.CreateProgram()
-	wrapper.AttachShader(program, vertexShader)
-	wrapper.AttachShader(program, fragmentShader)
+	for i := 0; i < len(shaders); i++ {
+		wrapper.AttachShader(program, shaders[i])
+	}
 	wrapper.LinkProgram(program)
 
 	return &Shader{
@@ -136,12 +168,72 @@ func NewFontShader(wrapper interfaces.GLWrapper) *Shader {
 	return NewShader(baseDirShaders()+"font.vert", baseDirShaders()+"font.frag", wrapper)
 }
 
+// NewSDFFontShader returns a Shader, that could be used for rendering the texts of the
+// signed distance field charsets. The outline, shadow and glow parameters are uniforms.
+func NewSDFFontShader(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"font.vert", baseDirShaders()+"sdf_font.frag", wrapper)
+}
+
 // NewMenuBackgroundShader returns a Shader, that could be used for rendering the background
 // for the menu screen.
 func NewMenuBackgroundShader(wrapper interfaces.GLWrapper) *Shader {
 	return NewShader(baseDirShaders()+"menu-background.vert", baseDirShaders()+"menu-background.frag", wrapper)
 }
 
+// NewParticleShader returns a Shader, that could be used for rendering the camera facing
+// particles of the particles package.
+func NewParticleShader(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"particle.vert", baseDirShaders()+"particle.frag", wrapper)
+}
+
+// NewBillboardShader returns a Shader, that could be used for rendering the camera facing
+// textured, colored billboard meshes. The texture uniform name is `tex`.
+func NewBillboardShader(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"billboard.vert", baseDirShaders()+"billboard.frag", wrapper)
+}
+
+// NewBillboardFontShader returns a Shader, that could be used for rendering the camera facing
+// texts, that are built from billboard meshes with the glyph textures.
+func NewBillboardFontShader(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"billboard.vert", baseDirShaders()+"billboard_font.frag", wrapper)
+}
+
+// NewBillboardSDFFontShader returns a Shader, that could be used for rendering the camera facing
+// texts of the signed distance field charsets.
+func NewBillboardSDFFontShader(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"billboard.vert", baseDirShaders()+"sdf_font.frag", wrapper)
+}
+
+// NewTerrainShader returns a Shader, that could be used for rendering the terrains with texture
+// layers. The layers are blended by height and slope, or by the splat map.
+func NewTerrainShader(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"terrain.vert", baseDirShaders()+"terrain.frag", wrapper)
+}
+
+// NewTextureShaderInstanced returns a Shader, that could be used for rendering the textured
+// instanced meshes. It uses the default texture fragment shader.
+func NewTextureShaderInstanced(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"texture_instanced.vert", baseDirShaders()+"texture.frag", wrapper)
+}
+
+// NewTextureShaderInstancedWithFog returns a Shader, that could be used for rendering the textured
+// instanced meshes with fog. In this application, the `Fog` structure has to be filled.
+func NewTextureShaderInstancedWithFog(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"texture_instanced.vert", baseDirShaders()+"texture_with_fog.frag", wrapper)
+}
+
+// NewMaterialShaderInstanced returns a Shader, that could be used for rendering the material
+// instanced meshes. It uses the default material fragment shader.
+func NewMaterialShaderInstanced(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"material_instanced.vert", baseDirShaders()+"material.frag", wrapper)
+}
+
+// NewTransitionShader returns a Shader, that could be used for drawing the screen transitions.
+// It blends the fromScreen and toScreen render target textures based on the progress uniform.
+func NewTransitionShader(wrapper interfaces.GLWrapper) *Shader {
+	return NewShader(baseDirShaders()+"transition.vert", baseDirShaders()+"transition.frag", wrapper)
+}
+
 // Use is a wrapper for gl.UseProgram
 func (s *Shader) Use() {
 	s.wrapper.UseProgram(s.id)
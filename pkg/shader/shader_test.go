@@ -125,9 +125,63 @@ void main()
     FragColor = texture(textureOne, vSmoothTexCoord) * vec4(vSmoothColor, 1.0);
 }
     `
-	EmptyString            = ""
-	FragmentShaderFileName = "fragmentShader.frag"
-	VertexShaderFileName   = "vertexShader.vert"
+	ValidGeometryShaderString = `
+#version 410
+layout(triangles) in;
+layout(triangle_strip, max_vertices = 3) out;
+in vec4 vSmoothColor[];
+smooth out vec4 gSmoothColor;
+void main()
+{
+    for (int i = 0; i < 3; i++) {
+        gl_Position = gl_in[i].gl_Position;
+        gSmoothColor = vSmoothColor[i];
+        EmitVertex();
+    }
+    EndPrimitive();
+}
+    `
+	ValidGeometryFragmentShaderString = `
+#version 410
+smooth in vec4 gSmoothColor;
+layout(location=0) out vec4 vFragColor;
+void main()
+{
+    vFragColor = gSmoothColor;
+}
+    `
+	ValidTessControlShaderString = `
+#version 410
+layout(vertices = 3) out;
+in vec4 vSmoothColor[];
+out vec4 tcSmoothColor[];
+void main()
+{
+    gl_out[gl_InvocationID].gl_Position = gl_in[gl_InvocationID].gl_Position;
+    tcSmoothColor[gl_InvocationID] = vSmoothColor[gl_InvocationID];
+    gl_TessLevelOuter[0] = 2.0;
+    gl_TessLevelOuter[1] = 2.0;
+    gl_TessLevelOuter[2] = 2.0;
+    gl_TessLevelInner[0] = 2.0;
+}
+    `
+	ValidTessEvaluationShaderString = `
+#version 410
+layout(triangles, equal_spacing, ccw) in;
+in vec4 tcSmoothColor[];
+smooth out vec4 vSmoothColor;
+void main()
+{
+    gl_Position = gl_TessCoord.x * gl_in[0].gl_Position + gl_TessCoord.y * gl_in[1].gl_Position + gl_TessCoord.z * gl_in[2].gl_Position;
+    vSmoothColor = gl_TessCoord.x * tcSmoothColor[0] + gl_TessCoord.y * tcSmoothColor[1] + gl_TessCoord.z * tcSmoothColor[2];
+}
+    `
+	EmptyString                  = ""
+	FragmentShaderFileName       = "fragmentShader.frag"
+	VertexShaderFileName         = "vertexShader.vert"
+	GeometryShaderFileName       = "geometryShader.geom"
+	TessControlShaderFileName    = "tessControlShader.tesc"
+	TessEvaluationShaderFileName = "tessEvaluationShader.tese"
 )
 
 var (
@@ -352,6 +406,93 @@ func TestNewShader(t *testing.T) {
 		t.Error("Invalid shader program id")
 	}
 }
+func TestNewShaderWithGeometry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	CreateFileWithContent(FragmentShaderFileName, ValidGeometryFragmentShaderString)
+	defer DeleteFile(FragmentShaderFileName)
+	CreateFileWithContent(GeometryShaderFileName, ValidGeometryShaderString)
+	defer DeleteFile(GeometryShaderFileName)
+	CreateFileWithContent(VertexShaderFileName, ValidVertexShaderWithUniformsString)
+	defer DeleteFile(VertexShaderFileName)
+	runtime.LockOSThread()
+	InitGlfw()
+	defer glfw.Terminate()
+	realGlWrapper.InitOpenGL()
+	shader := NewShaderWithGeometry(VertexShaderFileName, GeometryShaderFileName, FragmentShaderFileName, realGlWrapper)
+	if shader.id == 0 || shader.GetId() == 0 {
+		t.Error("Invalid shader program id")
+	}
+}
+func TestNewShaderWithGeometryPanicOnGeometryContent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				defer glfw.Terminate()
+				t.Errorf("NewShaderWithGeometry should have panicked due to the invalid content!")
+			}
+		}()
+		CreateFileWithContent(FragmentShaderFileName, ValidGeometryFragmentShaderString)
+		defer DeleteFile(FragmentShaderFileName)
+		CreateFileWithContent(GeometryShaderFileName, InvalidShaderString)
+		defer DeleteFile(GeometryShaderFileName)
+		CreateFileWithContent(VertexShaderFileName, ValidVertexShaderWithUniformsString)
+		defer DeleteFile(VertexShaderFileName)
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		NewShaderWithGeometry(VertexShaderFileName, GeometryShaderFileName, FragmentShaderFileName, realGlWrapper)
+	}()
+}
+func TestNewShaderWithTessellation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	CreateFileWithContent(FragmentShaderFileName, ValidFragmentShaderString)
+	defer DeleteFile(FragmentShaderFileName)
+	CreateFileWithContent(TessControlShaderFileName, ValidTessControlShaderString)
+	defer DeleteFile(TessControlShaderFileName)
+	CreateFileWithContent(TessEvaluationShaderFileName, ValidTessEvaluationShaderString)
+	defer DeleteFile(TessEvaluationShaderFileName)
+	CreateFileWithContent(VertexShaderFileName, ValidVertexShaderWithUniformsString)
+	defer DeleteFile(VertexShaderFileName)
+	runtime.LockOSThread()
+	InitGlfw()
+	defer glfw.Terminate()
+	realGlWrapper.InitOpenGL()
+	shader := NewShaderWithTessellation(VertexShaderFileName, TessControlShaderFileName, TessEvaluationShaderFileName, FragmentShaderFileName, realGlWrapper)
+	if shader.id == 0 || shader.GetId() == 0 {
+		t.Error("Invalid shader program id")
+	}
+}
+func TestNewShaderWithTessellationAndGeometry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	CreateFileWithContent(FragmentShaderFileName, ValidGeometryFragmentShaderString)
+	defer DeleteFile(FragmentShaderFileName)
+	CreateFileWithContent(GeometryShaderFileName, ValidGeometryShaderString)
+	defer DeleteFile(GeometryShaderFileName)
+	CreateFileWithContent(TessControlShaderFileName, ValidTessControlShaderString)
+	defer DeleteFile(TessControlShaderFileName)
+	CreateFileWithContent(TessEvaluationShaderFileName, ValidTessEvaluationShaderString)
+	defer DeleteFile(TessEvaluationShaderFileName)
+	CreateFileWithContent(VertexShaderFileName, ValidVertexShaderWithUniformsString)
+	defer DeleteFile(VertexShaderFileName)
+	runtime.LockOSThread()
+	InitGlfw()
+	defer glfw.Terminate()
+	realGlWrapper.InitOpenGL()
+	shader := NewShaderWithTessellationAndGeometry(VertexShaderFileName, TessControlShaderFileName, TessEvaluationShaderFileName, GeometryShaderFileName, FragmentShaderFileName, realGlWrapper)
+	if shader.id == 0 || shader.GetId() == 0 {
+		t.Error("Invalid shader program id")
+	}
+}
 func TestNewTextureShader(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping it in short mode")
@@ -625,6 +766,27 @@ func TestNewFontShader(t *testing.T) {
 		}
 	}()
 }
+func TestNewSDFFontShader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewSDFFontShader shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewSDFFontShader(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
 func TestNewMenuBackgroundShader(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping it in short mode")
@@ -646,6 +808,111 @@ func TestNewMenuBackgroundShader(t *testing.T) {
 		}
 	}()
 }
+func TestNewParticleShader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewParticleShader shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewParticleShader(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
+func TestNewTerrainShader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewTerrainShader shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewTerrainShader(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
+func TestNewBillboardShader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewBillboardShader shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewBillboardShader(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
+func TestNewBillboardFontShader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewBillboardFontShader shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewBillboardFontShader(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
+func TestNewBillboardSDFFontShader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewBillboardSDFFontShader shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewBillboardSDFFontShader(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
 func TestUse(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping it in short mode")
@@ -744,3 +1011,87 @@ func TestSetUniform1i(t *testing.T) {
 	shader.Use()
 	shader.SetUniform1i("pointSize", valueToSet)
 }
+func TestNewTextureShaderInstanced(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewTextureShaderInstanced shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewTextureShaderInstanced(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
+func TestNewTextureShaderInstancedWithFog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewTextureShaderInstancedWithFog shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewTextureShaderInstancedWithFog(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
+func TestNewMaterialShaderInstanced(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewMaterialShaderInstanced shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewMaterialShaderInstanced(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
+func TestNewTransitionShader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Errorf("NewTransitionShader shouldn't have panicked!. %v", r)
+			}
+		}()
+		runtime.LockOSThread()
+		InitGlfw()
+		defer glfw.Terminate()
+		realGlWrapper.InitOpenGL()
+		shader := NewTransitionShader(realGlWrapper)
+		if shader.id == 0 || shader.GetId() == 0 {
+			t.Error("Invalid shader program id")
+		}
+	}()
+}
@@ -0,0 +1,17 @@
+#version 410
+in vec2 TexCoords;
+in vec3 Color;
+
+layout(location=0) out vec4 FragColor;
+
+uniform sampler2D tex;
+
+void main()
+{
+    vec4 sampled = texture(tex, TexCoords) * vec4(Color, 1.0);
+    // the transparent parts of the sprites are not written to the depth buffer.
+    if (sampled.a < 0.1) {
+        discard;
+    }
+    FragColor = sampled;
+}
@@ -0,0 +1,29 @@
+#version 410
+layout(location = 0) in vec3 vVertex;
+layout(location = 1) in vec3 vColor;
+layout(location = 2) in vec2 vTexCoord;
+
+out vec2 TexCoords;
+out vec3 Color;
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 projection;
+// 1 - spherical, 2 - cylindrical (Y locked)
+uniform int billboardMode;
+
+void main()
+{
+    mat4 modelView = view * model;
+    vec3 scale = vec3(length(model[0].xyz), length(model[1].xyz), length(model[2].xyz));
+    // The rotation part of the model view matrix is replaced with the scale,
+    // so that the quad is parallel to the view plane.
+    modelView[0].xyz = vec3(scale.x, 0.0, 0.0);
+    modelView[2].xyz = vec3(0.0, 0.0, scale.z);
+    if (billboardMode != 2) {
+        modelView[1].xyz = vec3(0.0, scale.y, 0.0);
+    }
+    TexCoords = vTexCoord;
+    Color = vColor;
+    gl_Position = projection * modelView * vec4(vVertex, 1.0);
+}
@@ -0,0 +1,20 @@
+#version 410
+layout(location = 0) in vec3 vVertex;
+layout(location = 1) in vec3 vNormal;
+// the model transformation of the instance. It uses the 3-6 locations.
+layout(location = 3) in mat4 instanceModel;
+
+out vec3 FragPos;
+out vec3 Normal;
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 projection;
+
+void main()
+{
+    mat4 instanceToWorld = model * instanceModel;
+    FragPos = vec3(instanceToWorld * vec4(vVertex, 1.0));
+    Normal = mat3(transpose(inverse(instanceToWorld))) * vNormal;
+    gl_Position = projection * view * vec4(FragPos,1.0);
+}
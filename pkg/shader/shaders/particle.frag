@@ -0,0 +1,16 @@
+#version 410
+smooth in vec4 vSmoothColor;
+smooth in vec2 vSmoothCorner;
+
+layout(location=0) out vec4 FragColor;
+
+void main()
+{
+    // The corners are in the [-0.5, 0.5] range, the particle is a soft disc.
+    float distanceFromCenter = length(vSmoothCorner) * 2.0;
+    float alpha = vSmoothColor.a * (1.0 - smoothstep(0.6, 1.0, distanceFromCenter));
+    if (alpha <= 0.0) {
+        discard;
+    }
+    FragColor = vec4(vSmoothColor.rgb, alpha);
+}
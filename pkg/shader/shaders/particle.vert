@@ -0,0 +1,22 @@
+#version 410
+layout(location = 0) in vec3 vCenter;
+layout(location = 1) in vec2 vCorner;
+layout(location = 2) in vec4 vColor;
+layout(location = 3) in float vSize;
+
+smooth out vec4 vSmoothColor;
+smooth out vec2 vSmoothCorner;
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 projection;
+
+void main()
+{
+    // The corner offset is applied in view space, so that the quad always faces the camera.
+    vec4 viewPosition = view * model * vec4(vCenter, 1.0);
+    viewPosition.xy += vCorner * vSize;
+    gl_Position = projection * viewPosition;
+    vSmoothColor = vColor;
+    vSmoothCorner = vCorner;
+}
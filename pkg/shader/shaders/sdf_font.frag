@@ -0,0 +1,55 @@
+#version 410
+in vec2 TexCoords;
+in vec3 Color;
+
+layout(location=0) out vec4 FragColor;
+
+uniform sampler2D tex;
+// the width of the outline in distance units. The 0.5 is the spread of the charset.
+uniform float outlineWidth;
+uniform vec3 outlineColor;
+// the offset of the shadow in atlas pixels. It has to be less than the spread.
+uniform vec3 shadowOffset;
+uniform float shadowSoftness;
+uniform vec3 shadowColor;
+// the width of the glow around the outline in distance units.
+uniform float glowWidth;
+uniform vec3 glowColor;
+
+const float edge = 0.5;
+
+// over returns the color with alpha of the front layer drawn over the back layer.
+vec4 over(vec4 front, vec4 back)
+{
+    float alpha = front.a + back.a * (1.0 - front.a);
+    if (alpha <= 0.0) {
+        return vec4(0.0);
+    }
+    vec3 color = (front.rgb * front.a + back.rgb * back.a * (1.0 - front.a)) / alpha;
+    return vec4(color, alpha);
+}
+
+void main()
+{
+    float dist = texture(tex, TexCoords).r;
+    float aa = max(fwidth(dist), 0.0001);
+    float outerEdge = edge - outlineWidth;
+
+    float textAlpha = smoothstep(edge - aa, edge + aa, dist);
+    float outlineAlpha = smoothstep(outerEdge - aa, outerEdge + aa, dist);
+    vec4 result = vec4(mix(outlineColor, Color, textAlpha), outlineAlpha);
+    if (glowWidth > 0.0) {
+        float glowAlpha = smoothstep(outerEdge - glowWidth, outerEdge, dist);
+        result = over(result, vec4(glowColor, glowAlpha));
+    }
+    if (length(shadowOffset.xy) > 0.0 || shadowSoftness > 0.0) {
+        vec2 offset = shadowOffset.xy / vec2(textureSize(tex, 0));
+        float shadowDist = texture(tex, TexCoords - offset).r;
+        float shadowAlpha = smoothstep(outerEdge - shadowSoftness - aa, outerEdge + aa, shadowDist);
+        result = over(result, vec4(shadowColor, shadowAlpha));
+    }
+    if (result.a < 0.01) {
+        discard;
+    }
+    FragColor = result;
+}
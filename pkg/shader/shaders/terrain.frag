@@ -0,0 +1,212 @@
+# version 410
+out vec4 FragColor;
+
+struct Material {
+    float shininess;
+};
+
+struct DirectionalLight {
+    vec3 direction;
+
+    vec3 ambient;
+    vec3 diffuse;
+    vec3 specular;
+};
+
+struct PointLight {
+    vec3 position;
+
+    vec3 ambient;
+    vec3 diffuse;
+    vec3 specular;
+
+    float constant;
+    float linear;
+    float quadratic;
+};
+
+struct SpotLight {
+    vec3 position;
+    vec3 direction;
+    float cutOff;
+    float outerCutOff;
+
+    vec3 ambient;
+    vec3 diffuse;
+    vec3 specular;
+
+    float constant;
+    float linear;
+    float quadratic;
+};
+
+in vec3 FragPos;
+in vec3 Normal;
+in vec3 LocalPos;
+
+#define MAX_DIRECTION_LIGHTS 16
+#define MAX_POINT_LIGHTS 16
+#define MAX_SPOT_LIGHTS 16
+#define MAX_TERRAIN_LAYERS 4
+
+uniform DirectionalLight dirLight[MAX_DIRECTION_LIGHTS];
+uniform PointLight pointLight[MAX_POINT_LIGHTS];
+uniform SpotLight spotLight[MAX_SPOT_LIGHTS];
+uniform Material material;
+uniform int NumberOfDirectionalLightSources;
+uniform int NumberOfPointLightSources;
+uniform int NumberOfSpotLightSources;
+
+uniform vec3 viewPosition;
+
+// The texture layers. The tiling is the number of the texture repeats in a world unit.
+// The height range is in world space, the slope is 0 on the flat surface, 1 on the vertical one.
+uniform sampler2D layerTexture[MAX_TERRAIN_LAYERS];
+uniform float layerTiling[MAX_TERRAIN_LAYERS];
+uniform float layerMinHeight[MAX_TERRAIN_LAYERS];
+uniform float layerMaxHeight[MAX_TERRAIN_LAYERS];
+uniform float layerMinSlope[MAX_TERRAIN_LAYERS];
+uniform float layerMaxSlope[MAX_TERRAIN_LAYERS];
+uniform float numberOfLayers;
+// The width of the transitions between the layers.
+uniform float layerHeightBlend;
+uniform float layerSlopeBlend;
+// If it is set, the rgba channels of the splat map are the weights of the layers.
+// The splat map covers the terrain mesh, its size is the terrainSize.
+uniform sampler2D splatMap;
+uniform float useSplatMap;
+uniform vec3 terrainSize;
+
+// function prototypes
+vec3 CalculateDirectionalLight(DirectionalLight light, vec3 normal, vec3 viewDir, vec3 albedo);
+vec3 CalculatePointLight(PointLight light, vec3 normal, vec3 fragPos, vec3 viewDir, vec3 albedo);
+vec3 CalculateSpotLight(SpotLight light, vec3 normal, vec3 fragPos, vec3 viewDir, vec3 albedo);
+
+// returns 1 inside the range, and fades out on its borders.
+float rangeWeight(float value, float minValue, float maxValue, float blend)
+{
+    float b = max(blend, 0.0001);
+    return smoothstep(minValue - b, minValue + b, value) * (1.0 - smoothstep(maxValue - b, maxValue + b, value));
+}
+
+vec3 LayerColor(vec3 normal)
+{
+    int n = min(int(numberOfLayers), MAX_TERRAIN_LAYERS);
+    float weights[MAX_TERRAIN_LAYERS];
+    float sum = 0.0;
+    if (useSplatMap > 0.5) {
+        vec4 splat = texture(splatMap, LocalPos.xz / terrainSize.xz + 0.5);
+        for (int i = 0; i < MAX_TERRAIN_LAYERS; i++) {
+            weights[i] = i < n ? splat[i] : 0.0;
+            sum += weights[i];
+        }
+    } else {
+        // the terrain normals could point downwards, so that the absolute value is used.
+        float slope = 1.0 - abs(normal.y);
+        for (int i = 0; i < MAX_TERRAIN_LAYERS; i++) {
+            weights[i] = 0.0;
+            if (i < n) {
+                weights[i] = rangeWeight(FragPos.y, layerMinHeight[i], layerMaxHeight[i], layerHeightBlend) *
+                    rangeWeight(slope, layerMinSlope[i], layerMaxSlope[i], layerSlopeBlend);
+            }
+            sum += weights[i];
+        }
+    }
+    if (sum < 0.0001) {
+        weights[0] = 1.0;
+        sum = 1.0;
+    }
+    vec3 color = vec3(0);
+    for (int i = 0; i < n; i++) {
+        color += weights[i] / sum * texture(layerTexture[i], FragPos.xz * layerTiling[i]).rgb;
+    }
+    return color;
+}
+
+void main()
+{
+    vec3 norm = normalize(Normal);
+    vec3 viewDirection = normalize(viewPosition - FragPos);
+    vec3 albedo = LayerColor(norm);
+
+    vec3 result = vec3(0);
+    // calculate Directional lighting
+    int nrDirLight = min(NumberOfDirectionalLightSources, MAX_DIRECTION_LIGHTS);
+    for (int i = 0; i < nrDirLight; i++) {
+        result += CalculateDirectionalLight(dirLight[i], norm, viewDirection, albedo);
+    }
+    // calculate Point lighting
+    int nrPointLight = min(NumberOfPointLightSources, MAX_POINT_LIGHTS);
+    for (int i = 0; i < nrPointLight; i++) {
+        result += CalculatePointLight(pointLight[i], norm, FragPos, viewDirection, albedo);
+    }
+    // calculate spot lighting
+    int nrSpotLight = min(NumberOfSpotLightSources, MAX_SPOT_LIGHTS);
+    for (int i = 0; i < nrSpotLight; i++) {
+        result += CalculateSpotLight(spotLight[i], norm, FragPos, viewDirection, albedo);
+    }
+    FragColor = vec4(result, 1.0);
+}
+
+// calculates the color when using a directional light.
+vec3 CalculateDirectionalLight(DirectionalLight light, vec3 normal, vec3 viewDir, vec3 albedo)
+{
+    vec3 lightDir = normalize(-light.direction);
+    // diffuse shading
+    float diff = max(dot(normal, lightDir), 0.0);
+    // specular shading
+    vec3 reflectDir = reflect(-lightDir, normal);
+    float spec = pow(max(dot(viewDir, reflectDir), 0.0), material.shininess);
+    // combine results
+    vec3 ambient = light.ambient * albedo;
+    vec3 diffuse = light.diffuse * diff * albedo;
+    vec3 specular = light.specular * spec * albedo * 0.2;
+    return (ambient + diffuse + specular);
+}
+// calculates the color when using a point light.
+vec3 CalculatePointLight(PointLight light, vec3 normal, vec3 fragPos, vec3 viewDir, vec3 albedo)
+{
+    vec3 lightDir = normalize(light.position - fragPos);
+    // diffuse shading
+    float diff = max(dot(normal, lightDir), 0.0);
+    // specular shading
+    vec3 reflectDir = reflect(-lightDir, normal);
+    float spec = pow(max(dot(viewDir, reflectDir), 0.0), material.shininess);
+    // attenuation
+    float distance = length(light.position - fragPos);
+    float attenuation = 1.0 / (light.constant + light.linear * distance + light.quadratic * (distance * distance));
+    // combine results
+    vec3 ambient = light.ambient * albedo;
+    vec3 diffuse = light.diffuse * diff * albedo;
+    vec3 specular = light.specular * spec * albedo * 0.2;
+    ambient *= attenuation;
+    diffuse *= attenuation;
+    specular *= attenuation;
+    return (ambient + diffuse + specular);
+}
+
+// calculates the color when using a spot light.
+vec3 CalculateSpotLight(SpotLight light, vec3 normal, vec3 fragPos, vec3 viewDir, vec3 albedo)
+{
+    vec3 lightDir = normalize(light.position - fragPos);
+    // diffuse shading
+    float diff = max(dot(normal, lightDir), 0.0);
+    // specular shading
+    vec3 reflectDir = reflect(-lightDir, normal);
+    float spec = pow(max(dot(viewDir, reflectDir), 0.0), material.shininess);
+    // attenuation
+    float distance = length(light.position - fragPos);
+    float attenuation = 1.0 / (light.constant + light.linear * distance + light.quadratic * (distance * distance));
+    // spotlight intensity
+    float theta = dot(lightDir, normalize(-light.direction));
+    float epsilon = light.cutOff - light.outerCutOff;
+    float intensity = clamp((theta - light.outerCutOff) / epsilon, 0.0, 1.0);
+    // combine results
+    vec3 ambient = light.ambient * albedo;
+    vec3 diffuse = light.diffuse * diff * albedo;
+    vec3 specular = light.specular * spec * albedo * 0.2;
+    ambient *= attenuation * intensity;
+    diffuse *= attenuation * intensity;
+    specular *= attenuation * intensity;
+    return (ambient + diffuse + specular);
+}
@@ -59,6 +59,8 @@ uniform int NumberOfPointLightSources;
 uniform int NumberOfSpotLightSources;
 
 uniform vec3 viewPosition;
+// the faded out ratio of the lod level during the cross-fade. It is 0 for the opaque draws.
+uniform float lodFade;
 
 // function prototypes
 vec4 CalculateDirectionalLight(DirectionalLight light, vec3 normal, vec3 viewDir);
@@ -86,7 +88,7 @@ void main()
     for (int i = 0; i < nrSpotLight; i++) {
         result += CalculateSpotLight(spotLight[i], norm, FragPos, viewDirection);
     }
-    FragColor = result;
+    FragColor = vec4(result.rgb, result.a * (1.0 - lodFade));
 }
 
 // calculates the color when using a directional light.
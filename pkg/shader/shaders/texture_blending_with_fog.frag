@@ -66,6 +66,8 @@ uniform int NumberOfSpotLightSources;
 uniform Fog fog;
 
 uniform vec3 viewPosition;
+// the faded out ratio of the lod level during the cross-fade. It is 0 for the opaque draws.
+uniform float lodFade;
 
 // function prototypes
 vec4 CalculateDirectionalLight(DirectionalLight light, vec3 normal, vec3 viewDir);
@@ -99,7 +101,7 @@ void main()
         fogFactor = clamp(fogFactor, 0.0, 1.0);
         result = vec4(mix(fog.color, result.xyz, fogFactor), result.w);
     }
-    FragColor = result;
+    FragColor = vec4(result.rgb, result.a * (1.0 - lodFade));
 }
 
 // calculates the color when using a directional light.
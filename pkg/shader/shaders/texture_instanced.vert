@@ -0,0 +1,23 @@
+# version 410
+layout(location = 0) in vec3 vVertex;
+layout(location = 1) in vec3 vNormal;
+layout(location = 2) in vec2 vTexCoord;
+// the model transformation of the instance. It uses the 3-6 locations.
+layout(location = 3) in mat4 instanceModel;
+
+out vec3 FragPos;
+out vec3 Normal;
+out vec2 TexCoords;
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 projection;
+
+void main()
+{
+    mat4 instanceToWorld = model * instanceModel;
+    FragPos = vec3(instanceToWorld * vec4(vVertex, 1.0));
+    Normal = mat3(transpose(inverse(instanceToWorld))) * vNormal;
+    TexCoords = vTexCoord;
+    gl_Position = projection * view * vec4(FragPos,1.0);
+}
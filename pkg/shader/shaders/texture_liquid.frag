@@ -47,6 +47,7 @@ in vec3 BottomPos;
 in vec3 Normal;
 in vec2 TexCoords;
 in float Depth;
+in vec4 ClipSpace;
 
 #define MAX_DIRECTION_LIGHTS 16
 #define MAX_POINT_LIGHTS 16
@@ -60,6 +61,10 @@ uniform int NumberOfDirectionalLightSources;
 uniform int NumberOfPointLightSources;
 uniform int NumberOfSpotLightSources;
 uniform float Eta;
+// the reflection and refraction render targets. They are used if the useRenderTargets is set.
+uniform sampler2D reflectionTexture;
+uniform sampler2D refractionTexture;
+uniform float useRenderTargets;
 
 uniform vec3 viewPosition;
 
@@ -69,6 +74,7 @@ vec4 CalculatePointLight(PointLight light, vec3 normal, vec3 fragPos, vec3 viewD
 vec4 CalculateSpotLight(SpotLight light, vec3 normal, vec3 fragPos, vec3 viewDir);
 
 const float FresnelPower = 5.0;
+const float DistortionStrength = 0.02;
 
 void main()
 {
@@ -114,6 +120,14 @@ void main()
         float F = ((1.0-eta) * (1.0-eta)) / ((1.0+eta) * (1.0+eta));
         float Ratio = F + (1.0 - F) * pow((1.0 - dot(-viewDirection, norm)), FresnelPower);
         vec4 mixed = mix(resultRefract, resultReflect, Ratio);
+        if (useRenderTargets > 0.5) {
+            // projective texture mapping, distorted by the surface normal.
+            vec2 ndc = (ClipSpace.xy / ClipSpace.w) / 2.0 + 0.5;
+            vec2 distorted = clamp(ndc + norm.xz * DistortionStrength, 0.001, 0.999);
+            vec4 reflectColor = texture(reflectionTexture, distorted);
+            vec4 refractColor = texture(refractionTexture, distorted);
+            mixed = vec4(mix(refractColor, reflectColor, Ratio).rgb, mixed.w);
+        }
         FragColor = vec4(vec3(mixed), mixed.w/(resultView.w+mixed.w));
     }
 }
@@ -8,6 +8,7 @@ out vec3 BottomPos;
 out vec3 Normal;
 out vec2 TexCoords;
 out float Depth;
+out vec4 ClipSpace;
 
 uniform mat4 model;
 uniform mat4 view;
@@ -17,12 +18,31 @@ uniform vec3 viewPosition;
 uniform float amplitude;
 uniform float frequency;
 uniform float waterLevel;
+// the simulated surface heights. It is used if the useSurfaceMap is set.
+uniform sampler2D surfaceMap;
+uniform float useSurfaceMap;
 
 const float PI = 3.14159;
 
 void main()
 {
-    vec3 Surface = vec3(vVertex.x, waterLevel, vVertex.z);
+    float simulated = 0.0;
+    // vNormal connects to the bottom normal vector.
+    // For the surface on the water level, we can expect that
+    // it points to the up direction.
+    vec3 surfaceNormal = vec3(0.0,-1.0,0.0);
+    if (useSurfaceMap > 0.5) {
+        // the texture coordinates of the liquid vertices are the grid coordinates between 0 and 1.
+        ivec2 size = textureSize(surfaceMap, 0);
+        ivec2 coord = ivec2(round(vTexCoord * vec2(size - ivec2(1))));
+        simulated = texelFetch(surfaceMap, coord, 0).r;
+        float hL = texelFetch(surfaceMap, max(coord - ivec2(1, 0), ivec2(0)), 0).r;
+        float hR = texelFetch(surfaceMap, min(coord + ivec2(1, 0), size - ivec2(1)), 0).r;
+        float hD = texelFetch(surfaceMap, max(coord - ivec2(0, 1), ivec2(0)), 0).r;
+        float hU = texelFetch(surfaceMap, min(coord + ivec2(0, 1), size - ivec2(1)), 0).r;
+        surfaceNormal = normalize(vec3(hR - hL, -2.0, hU - hD));
+    }
+    vec3 Surface = vec3(vVertex.x, waterLevel + simulated, vVertex.z);
     SurfacePos = vec3(model * vec4(Surface, 1.0));
     BottomPos =  vec3(model * vec4(vVertex, 1.0));
     float h = 0.0;
@@ -33,11 +53,9 @@ void main()
         h = amplitude*sin(-PI*distance*frequency+time);
     }
 
-    // vNormal connects to the bottom normal vector.
-    // For the surface on the water level, we can expect that
-    // it points to the up direction.
-    Normal = mat3(transpose(inverse(model))) * vec3(0.0,-1.0,0.0);
+    Normal = mat3(transpose(inverse(model))) * surfaceNormal;
     TexCoords = vTexCoord;
     SurfacePos = vec3(SurfacePos.x, SurfacePos.y+h, SurfacePos.z);
-    gl_Position = projection * view * vec4(SurfacePos, 1.0);
+    ClipSpace = projection * view * vec4(SurfacePos, 1.0);
+    gl_Position = ClipSpace;
 }
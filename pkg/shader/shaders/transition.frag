@@ -0,0 +1,36 @@
+# version 410
+out vec4 FragColor;
+
+in vec2 TexCoord;
+
+// the previous and the next screen.
+uniform sampler2D fromScreen;
+uniform sampler2D toScreen;
+// the state of the transition in the [0-1] interval.
+uniform float progress;
+// 1 - fade through the color, 2 - crossfade, 3 - slide.
+uniform int mode;
+// the fade color.
+uniform vec3 color;
+// the movement of the screens in the slide mode, eg. (-1, 0, 0) moves them to the left.
+uniform vec3 direction;
+
+void main()
+{
+    if (mode == 1) {
+        if (progress < 0.5) {
+            FragColor = mix(texture(fromScreen, TexCoord), vec4(color, 1.0), progress * 2.0);
+        } else {
+            FragColor = mix(vec4(color, 1.0), texture(toScreen, TexCoord), progress * 2.0 - 1.0);
+        }
+    } else if (mode == 3) {
+        vec2 fromCoord = TexCoord - direction.xy * progress;
+        if (fromCoord.x >= 0.0 && fromCoord.x <= 1.0 && fromCoord.y >= 0.0 && fromCoord.y <= 1.0) {
+            FragColor = texture(fromScreen, fromCoord);
+        } else {
+            FragColor = texture(toScreen, TexCoord + direction.xy * (1.0 - progress));
+        }
+    } else {
+        FragColor = mix(texture(fromScreen, TexCoord), texture(toScreen, TexCoord), progress);
+    }
+}
@@ -0,0 +1,18 @@
+# version 410
+layout (location = 0) in vec3 vVertex;
+layout (location = 1) in vec3 vNormal;
+layout (location = 2) in vec2 vTexCoord;
+
+out vec2 TexCoord;
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 projection;
+
+void main()
+{
+    gl_Position = projection * view * model * vec4(vVertex,1.0);
+    // the quad covers the screen, the texture coordinates are calculated from the
+    // position, so that the render targets are sampled without flipping.
+    TexCoord = (gl_Position.xy / gl_Position.w + 1.0) / 2.0;
+}
@@ -37,3 +37,104 @@ func (store GlfwMouseStore) Set(key glfw.MouseButton, value bool) {
 func (store GlfwMouseStore) Get(key glfw.MouseButton) bool {
 	return store[key]
 }
+
+const (
+	// The default dead zone of the gamepad axes.
+	DEFAULT_DEAD_ZONE = float32(0.15)
+)
+
+type GlfwGamepadStore struct {
+	buttons  map[glfw.GamepadButton]bool
+	axes     map[glfw.GamepadAxis]float32
+	deadZone float32
+}
+
+// NewGlfwGamepadStore returns an empty gamepad state store with the default dead zone.
+func NewGlfwGamepadStore() *GlfwGamepadStore {
+	return &GlfwGamepadStore{
+		buttons:  make(map[glfw.GamepadButton]bool),
+		axes:     make(map[glfw.GamepadAxis]float32),
+		deadZone: DEFAULT_DEAD_ZONE,
+	}
+}
+
+// SetDeadZone sets the dead zone of the axes. The axis values that are
+// closer to the rest position than the dead zone are returned as 0.
+func (store *GlfwGamepadStore) SetDeadZone(deadZone float32) {
+	store.deadZone = deadZone
+}
+
+// GetDeadZone returns the dead zone of the axes.
+func (store *GlfwGamepadStore) GetDeadZone() float32 {
+	return store.deadZone
+}
+
+// Set sets the given button to the given value.
+func (store *GlfwGamepadStore) Set(button glfw.GamepadButton, value bool) {
+	store.buttons[button] = value
+}
+
+// Get return the value for the given button
+func (store *GlfwGamepadStore) Get(button glfw.GamepadButton) bool {
+	return store.buttons[button]
+}
+
+// SetAxis sets the raw value of the given axis.
+func (store *GlfwGamepadStore) SetAxis(axis glfw.GamepadAxis, value float32) {
+	store.axes[axis] = value
+}
+
+// GetAxis returns the value of the given axis with the dead zone applied. The stick
+// values are in the [-1, 1] interval, the trigger values are in the [0, 1] interval
+// (the released trigger is 0). The values outside of the dead zone are rescaled, so
+// that the movement starts from 0 at the edge of the dead zone.
+func (store *GlfwGamepadStore) GetAxis(axis glfw.GamepadAxis) float32 {
+	value, ok := store.axes[axis]
+	if !ok {
+		return 0
+	}
+	if axis == glfw.AxisLeftTrigger || axis == glfw.AxisRightTrigger {
+		// the triggers are in the [-1, 1] interval, -1 is the released state.
+		value = (value + 1) / 2
+	}
+	sign := float32(1)
+	if value < 0 {
+		sign = -1
+		value = -value
+	}
+	if value <= store.deadZone || store.deadZone >= 1 {
+		return 0
+	}
+	if value > 1 {
+		value = 1
+	}
+	return sign * (value - store.deadZone) / (1 - store.deadZone)
+}
+
+// SetState updates the buttons and the axes from the state of a gamepad with standard mapping.
+func (store *GlfwGamepadStore) SetState(state *glfw.GamepadState) {
+	for i := 0; i < len(state.Buttons); i++ {
+		store.buttons[glfw.GamepadButton(i)] = state.Buttons[i] != glfw.Release
+	}
+	for i := 0; i < len(state.Axes); i++ {
+		store.axes[glfw.GamepadAxis(i)] = state.Axes[i]
+	}
+}
+
+// SetJoystickState updates the buttons and the axes from the raw state of a joystick
+// without standard mapping. The axes and the buttons are mapped by their indices, the
+// ones over the gamepad axes and buttons are skipped.
+func (store *GlfwGamepadStore) SetJoystickState(axes []float32, buttons []glfw.Action) {
+	for i := 0; i < len(buttons) && i <= int(glfw.ButtonLast); i++ {
+		store.buttons[glfw.GamepadButton(i)] = buttons[i] != glfw.Release
+	}
+	for i := 0; i < len(axes) && i <= int(glfw.AxisLast); i++ {
+		store.axes[glfw.GamepadAxis(i)] = axes[i]
+	}
+}
+
+// Clear releases every button and moves every axis to the rest position.
+func (store *GlfwGamepadStore) Clear() {
+	store.buttons = make(map[glfw.GamepadButton]bool)
+	store.axes = make(map[glfw.GamepadAxis]float32)
+}
@@ -54,3 +54,72 @@ func TestMouseStoreGet(t *testing.T) {
 		t.Errorf("Invalid value get. It is %v.", store.Get(btn))
 	}
 }
+func TestNewGlfwGamepadStore(t *testing.T) {
+	store := NewGlfwGamepadStore()
+	if len(store.buttons) != 0 || len(store.axes) != 0 {
+		t.Errorf("Invalid store length. It is %d, %d.", len(store.buttons), len(store.axes))
+	}
+	if store.GetDeadZone() != DEFAULT_DEAD_ZONE {
+		t.Errorf("Invalid dead zone. It is %f.", store.GetDeadZone())
+	}
+}
+func TestGamepadStoreSetGet(t *testing.T) {
+	store := NewGlfwGamepadStore()
+	btn := glfw.ButtonA
+	store.Set(btn, true)
+	if !store.Get(btn) {
+		t.Errorf("Invalid value get. It is %v.", store.Get(btn))
+	}
+	store.Clear()
+	if store.Get(btn) {
+		t.Error("Button should be released after clear.")
+	}
+}
+func TestGamepadStoreGetAxis(t *testing.T) {
+	testData := []struct {
+		axis     glfw.GamepadAxis
+		value    float32
+		expected float32
+	}{
+		{glfw.AxisLeftX, 0.0, 0.0},
+		{glfw.AxisLeftX, 0.2, 0.0},
+		{glfw.AxisLeftX, -0.2, 0.0},
+		{glfw.AxisLeftX, 0.6, 0.5},
+		{glfw.AxisLeftX, -0.6, -0.5},
+		{glfw.AxisLeftX, 1.0, 1.0},
+		{glfw.AxisLeftTrigger, -1.0, 0.0},
+		{glfw.AxisLeftTrigger, 0.2, 0.5},
+		{glfw.AxisRightTrigger, 1.0, 1.0},
+	}
+	store := NewGlfwGamepadStore()
+	store.SetDeadZone(0.2)
+	for _, tt := range testData {
+		store.SetAxis(tt.axis, tt.value)
+		if v := store.GetAxis(tt.axis); v < tt.expected-0.0001 || v > tt.expected+0.0001 {
+			t.Errorf("Invalid axis value for %f. Instead of '%f', it is '%f'.", tt.value, tt.expected, v)
+		}
+	}
+	if store.GetAxis(glfw.AxisRightY) != 0.0 {
+		t.Error("Missing axis should be 0.")
+	}
+}
+func TestGamepadStoreSetState(t *testing.T) {
+	store := NewGlfwGamepadStore()
+	var state glfw.GamepadState
+	state.Buttons[glfw.ButtonB] = glfw.Press
+	state.Axes[glfw.AxisLeftY] = -1.0
+	store.SetState(&state)
+	if !store.Get(glfw.ButtonB) || store.Get(glfw.ButtonA) {
+		t.Error("Invalid button states.")
+	}
+	if store.GetAxis(glfw.AxisLeftY) != -1.0 {
+		t.Errorf("Invalid axis value. It is %f.", store.GetAxis(glfw.AxisLeftY))
+	}
+	store.SetJoystickState([]float32{1.0}, []glfw.Action{glfw.Press, glfw.Release})
+	if !store.Get(glfw.ButtonA) || store.Get(glfw.ButtonB) {
+		t.Error("Invalid joystick button states.")
+	}
+	if store.GetAxis(glfw.AxisLeftX) != 1.0 {
+		t.Errorf("Invalid joystick axis value. It is %f.", store.GetAxis(glfw.AxisLeftX))
+	}
+}
@@ -50,12 +50,15 @@ func GlfwTerminate() {
 
 type GLWrapperMock struct{}
 
-func (g GLWrapperMock) GenVertexArrays() uint32               { return uint32(1) }
-func (g GLWrapperMock) GenBuffers() uint32                    { return uint32(1) }
-func (g GLWrapperMock) BindVertexArray(vao uint32)            {}
-func (g GLWrapperMock) BindBuffer(bufferType, vbo uint32)     {}
-func (g GLWrapperMock) ArrayBufferData(bufferData []float32)  {}
-func (g GLWrapperMock) ElementBufferData(bufferData []uint32) {}
+func (g GLWrapperMock) GenVertexArrays() uint32                             { return uint32(1) }
+func (g GLWrapperMock) GenBuffers() uint32                                  { return uint32(1) }
+func (g GLWrapperMock) DeleteVertexArrays(vao uint32)                       {}
+func (g GLWrapperMock) DeleteBuffers(buffer uint32)                         {}
+func (g GLWrapperMock) BindVertexArray(vao uint32)                          {}
+func (g GLWrapperMock) BindBuffer(bufferType, vbo uint32)                   {}
+func (g GLWrapperMock) ArrayBufferData(bufferData []float32)                {}
+func (g GLWrapperMock) ArrayBufferSubData(offset int, bufferData []float32) {}
+func (g GLWrapperMock) ElementBufferData(bufferData []uint32)               {}
 func (g GLWrapperMock) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer) {
 }
 func (g GLWrapperMock) ActiveTexture(id uint32)          {}
@@ -118,6 +121,38 @@ func (g GLWrapperMock) Enable(cap uint32)
 func (g GLWrapperMock) DepthFunc(xfunc uint32)                                             {}
 func (g GLWrapperMock) Viewport(x int32, y int32, width int32, height int32)               {}
 func (g GLWrapperMock) BlendFunc(sfactor uint32, dfactor uint32)                           {}
+func (g GLWrapperMock) DepthMask(flag bool)                                                {}
+func (g GLWrapperMock) PatchParameteri(pname uint32, value int32)                          {}
+func (g GLWrapperMock) DrawPatchElements(count int32)                                      {}
+func (g GLWrapperMock) DispatchCompute(numGroupsX, numGroupsY, numGroupsZ uint32)          {}
+func (g GLWrapperMock) ComputeShaderSupported() bool                                       { return true }
+func (g GLWrapperMock) MemoryBarrier(barriers uint32)                                      {}
+func (g GLWrapperMock) BindBufferBase(target uint32, index uint32, buffer uint32)          {}
+func (g GLWrapperMock) StorageBufferData(bufferData []float32)                             {}
+func (g GLWrapperMock) GetStorageBufferData(bufferData []float32)                          {}
+func (g GLWrapperMock) TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+}
+func (g GLWrapperMock) DeleteTextures(texture uint32)                     {}
+func (g GLWrapperMock) GenFramebuffers() uint32                           { return uint32(1) }
+func (g GLWrapperMock) BindFramebuffer(target uint32, framebuffer uint32) {}
+func (g GLWrapperMock) FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+}
+
+// CheckFramebufferStatus returns the value of the FRAMEBUFFER_COMPLETE constant.
+func (g GLWrapperMock) CheckFramebufferStatus(target uint32) uint32 { return uint32(0x8CD5) }
+func (g GLWrapperMock) DeleteFramebuffers(framebuffer uint32)       {}
+func (g GLWrapperMock) GenRenderbuffers() uint32                    { return uint32(1) }
+func (g GLWrapperMock) BindRenderbuffer(target uint32, renderbuffer uint32) {
+}
+func (g GLWrapperMock) RenderbufferStorage(target uint32, internalformat uint32, width int32, height int32) {
+}
+func (g GLWrapperMock) FramebufferRenderbuffer(target uint32, attachment uint32, renderbuffertarget uint32, renderbuffer uint32) {
+}
+func (g GLWrapperMock) DeleteRenderbuffers(renderbuffer uint32)                        {}
+func (g GLWrapperMock) VertexAttribDivisor(index uint32, divisor uint32)               {}
+func (g GLWrapperMock) DrawTriangleElementsInstanced(count int32, instanceCount int32) {}
+func (g GLWrapperMock) Disable(cap uint32)                                             {}
+func (g GLWrapperMock) Scissor(x int32, y int32, width int32, height int32)            {}
 
 type ShaderMock struct{}
 
@@ -0,0 +1,85 @@
+package texture
+
+import (
+	"fmt"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+)
+
+// RenderTarget is an offscreen framebuffer with a color texture and a depth buffer.
+// The scene could be drawn into it, and the color texture could be sampled by the shaders,
+// for example for the reflection and refraction of the liquids.
+type RenderTarget struct {
+	// The color texture of the framebuffer.
+	Texture *Texture
+	// The size of the framebuffer in pixels.
+	Width, Height int
+
+	framebuffer uint32
+	depthBuffer uint32
+}
+
+// AddRenderTarget creates a render target with the given dimensions, appends its color texture
+// and returns the render target. It panics if the framebuffer is not complete.
+func (t *Textures) AddRenderTarget(width, height int, uniformName string, wrapper interfaces.GLWrapper) *RenderTarget {
+	tex := &Texture{
+		TextureName: genTextures(wrapper),
+		TargetId:    glwrapper.TEXTURE_2D,
+		Id:          glwrapper.TEXTURE0 + uint32(len(*t)),
+		UniformName: uniformName,
+		Wrapper:     wrapper,
+		FilePath:    "render-target",
+	}
+	tex.Bind()
+	tex.Wrapper.TexParameteri(glwrapper.TEXTURE_2D, glwrapper.TEXTURE_WRAP_R, glwrapper.CLAMP_TO_EDGE)
+	tex.Wrapper.TexParameteri(glwrapper.TEXTURE_2D, glwrapper.TEXTURE_WRAP_S, glwrapper.CLAMP_TO_EDGE)
+	tex.Wrapper.TexParameteri(glwrapper.TEXTURE_2D, glwrapper.TEXTURE_MIN_FILTER, glwrapper.LINEAR)
+	tex.Wrapper.TexParameteri(glwrapper.TEXTURE_2D, glwrapper.TEXTURE_MAG_FILTER, glwrapper.LINEAR)
+	tex.Wrapper.TexImage2D(tex.TargetId, 0, glwrapper.RGBA, int32(width), int32(height), 0, glwrapper.RGBA, uint32(glwrapper.UNSIGNED_BYTE), nil)
+	tex.UnBind()
+
+	rt := &RenderTarget{
+		Texture: tex,
+		Width:   width,
+		Height:  height,
+	}
+	rt.framebuffer = wrapper.GenFramebuffers()
+	wrapper.BindFramebuffer(glwrapper.FRAMEBUFFER, rt.framebuffer)
+	wrapper.FramebufferTexture2D(glwrapper.FRAMEBUFFER, glwrapper.COLOR_ATTACHMENT0, glwrapper.TEXTURE_2D, tex.TextureName, 0)
+
+	rt.depthBuffer = wrapper.GenRenderbuffers()
+	wrapper.BindRenderbuffer(glwrapper.RENDERBUFFER, rt.depthBuffer)
+	wrapper.RenderbufferStorage(glwrapper.RENDERBUFFER, glwrapper.DEPTH_COMPONENT24, int32(width), int32(height))
+	wrapper.FramebufferRenderbuffer(glwrapper.FRAMEBUFFER, glwrapper.DEPTH_ATTACHMENT, glwrapper.RENDERBUFFER, rt.depthBuffer)
+
+	status := wrapper.CheckFramebufferStatus(glwrapper.FRAMEBUFFER)
+	wrapper.BindRenderbuffer(glwrapper.RENDERBUFFER, 0)
+	wrapper.BindFramebuffer(glwrapper.FRAMEBUFFER, 0)
+	if status != glwrapper.FRAMEBUFFER_COMPLETE {
+		panic(fmt.Sprintf("The render target framebuffer is not complete. Status: '%d'.", status))
+	}
+	*t = append(*t, tex)
+	return rt
+}
+
+// Bind binds the framebuffer and sets the viewport to its size, so that the
+// drawing goes to the render target until the UnBind call.
+func (r *RenderTarget) Bind() {
+	r.Texture.Wrapper.BindFramebuffer(glwrapper.FRAMEBUFFER, r.framebuffer)
+	r.Texture.Wrapper.Viewport(0, 0, int32(r.Width), int32(r.Height))
+}
+
+// UnBind binds the default framebuffer and sets the viewport to the given window size.
+func (r *RenderTarget) UnBind(windowWidth, windowHeight int32) {
+	r.Texture.Wrapper.BindFramebuffer(glwrapper.FRAMEBUFFER, 0)
+	r.Texture.Wrapper.Viewport(0, 0, windowWidth, windowHeight)
+}
+
+// Delete releases the framebuffer, the depth buffer and the color texture.
+// The render target can't be used after this.
+func (r *RenderTarget) Delete() {
+	r.Texture.Wrapper.DeleteFramebuffers(r.framebuffer)
+	r.Texture.Wrapper.DeleteRenderbuffers(r.depthBuffer)
+	r.Texture.Wrapper.DeleteTextures(r.Texture.TextureName)
+}
@@ -0,0 +1,25 @@
+package texture
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+)
+
+func TestAddRenderTarget(t *testing.T) {
+	var textures Textures
+	textures.TransparentTexture(1, 1, 128, "material.diffuse", testGlWrapper)
+	rt := textures.AddRenderTarget(320, 240, "reflectionTexture", testGlWrapper)
+	if len(textures) != 2 || textures[1] != rt.Texture {
+		t.Fatal("The texture of the render target should be appended.")
+	}
+	if rt.Texture.Id != glwrapper.TEXTURE1 || rt.Texture.UniformName != "reflectionTexture" {
+		t.Errorf("Invalid texture. '%v'.", rt.Texture)
+	}
+	if rt.Width != 320 || rt.Height != 240 {
+		t.Errorf("Invalid size. '%d x %d'.", rt.Width, rt.Height)
+	}
+	rt.Bind()
+	rt.UnBind(800, 600)
+	rt.Delete()
+}
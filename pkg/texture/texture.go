@@ -91,7 +91,7 @@ func (t *Textures) AddTextureRGBA(filePath string, rgba *image.RGBA, wrapR, wrap
 
 	tex.Wrapper.TexImage2D(tex.TargetId, 0, glwrapper.RGBA, int32(rgba.Rect.Size().X), int32(rgba.Rect.Size().Y), 0, glwrapper.RGBA, uint32(glwrapper.UNSIGNED_BYTE), tex.Wrapper.Ptr(rgba.Pix))
 
-	tex.Wrapper.GenerateMipmap(tex.TextureName)
+	tex.Wrapper.GenerateMipmap(tex.TargetId)
 
 	*t = append(*t, tex)
 }
@@ -110,6 +110,49 @@ func (t *Textures) AddTexture(filePath string, wrapR, wrapS, minificationFilter,
 	}
 	t.AddTextureRGBA(filePath, rgba, wrapR, wrapS, minificationFilter, magnificationFilter, uniformName, wrapper)
 }
+
+// AddFloatTexture creates a single channel float texture with the given dimensions and data,
+// and appends it. It could be used for passing height or displacement values to the shaders.
+// The texture is not filtered, the values are fetched as they are.
+func (t *Textures) AddFloatTexture(width, height int, data []float32, uniformName string, wrapper interfaces.GLWrapper) {
+	tex := &Texture{
+		TextureName: genTextures(wrapper),
+		TargetId:    glwrapper.TEXTURE_2D,
+		Id:          glwrapper.TEXTURE0 + uint32(len(*t)),
+		UniformName: uniformName,
+		Wrapper:     wrapper,
+		FilePath:    "float-gen",
+	}
+
+	tex.Bind()
+	defer tex.UnBind()
+
+	tex.Wrapper.TexParameteri(glwrapper.TEXTURE_2D, glwrapper.TEXTURE_WRAP_R, glwrapper.CLAMP_TO_EDGE)
+	tex.Wrapper.TexParameteri(glwrapper.TEXTURE_2D, glwrapper.TEXTURE_WRAP_S, glwrapper.CLAMP_TO_EDGE)
+	tex.Wrapper.TexParameteri(glwrapper.TEXTURE_2D, glwrapper.TEXTURE_MIN_FILTER, glwrapper.NEAREST)
+	tex.Wrapper.TexParameteri(glwrapper.TEXTURE_2D, glwrapper.TEXTURE_MAG_FILTER, glwrapper.NEAREST)
+
+	tex.Wrapper.TexImage2D(tex.TargetId, 0, glwrapper.R32F, int32(width), int32(height), 0, glwrapper.RED, uint32(glwrapper.FLOAT), tex.Wrapper.Ptr(data))
+
+	*t = append(*t, tex)
+}
+
+// SetFloatData updates the data of a float texture, that was created with the AddFloatTexture.
+// The dimensions has to be the same.
+func (t *Texture) SetFloatData(width, height int, data []float32) {
+	t.Bind()
+	defer t.UnBind()
+	t.Wrapper.TexSubImage2D(t.TargetId, 0, 0, 0, int32(width), int32(height), glwrapper.RED, uint32(glwrapper.FLOAT), t.Wrapper.Ptr(data))
+}
+
+// SetRGBAData replaces the image of a texture, that was created with the AddTextureRGBA.
+// The dimensions could be different, the mipmaps are regenerated.
+func (t *Texture) SetRGBAData(rgba *image.RGBA) {
+	t.Bind()
+	defer t.UnBind()
+	t.Wrapper.TexImage2D(t.TargetId, 0, glwrapper.RGBA, int32(rgba.Rect.Size().X), int32(rgba.Rect.Size().Y), 0, glwrapper.RGBA, uint32(glwrapper.UNSIGNED_BYTE), t.Wrapper.Ptr(rgba.Pix))
+	t.Wrapper.GenerateMipmap(t.TargetId)
+}
 func (t *Textures) AddCubeMapTexture(directoryPath string, wrapR, wrapS, wrapT, minificationFilter, magnificationFilter int32, uniformName string, wrapper interfaces.GLWrapper) {
 	fileNames := [6]string{"skybox-right.png", "skybox-left.png", "skybox-top.png", "skybox-bottom.png", "skybox-front.png", "skybox-back.png"}
 	t.AddCubeMapTextureWithFilenames(directoryPath, fileNames, wrapR, wrapS, wrapT, minificationFilter, magnificationFilter, uniformName, wrapper)
@@ -1,6 +1,7 @@
 package texture
 
 import (
+	"image"
 	"testing"
 
 	"github.com/akosgarai/playground_engine/pkg/glwrapper"
@@ -81,3 +82,38 @@ func TestTransparentTexture(t *testing.T) {
 		t.Error("TransparentTexture should be successful")
 	}
 }
+func TestAddFloatTexture(t *testing.T) {
+	var textures Textures
+	textures.TransparentTexture(1, 1, 128, "material.diffuse", testGlWrapper)
+	textures.AddFloatTexture(2, 2, []float32{0, 1, 2, 3}, "heightMap", testGlWrapper)
+	if len(textures) != 2 {
+		t.Fatal("AddFloatTexture should be successful")
+	}
+	if textures[1].Id != glwrapper.TEXTURE1 || textures[1].UniformName != "heightMap" {
+		t.Errorf("Invalid texture. '%v'.", textures[1])
+	}
+	textures[1].SetFloatData(2, 2, []float32{3, 2, 1, 0})
+}
+
+// mipmapWrapperMock records the targets of the mipmap generations.
+type mipmapWrapperMock struct {
+	testhelper.GLWrapperMock
+	targets []uint32
+}
+
+func (w *mipmapWrapperMock) GenerateMipmap(target uint32) {
+	w.targets = append(w.targets, target)
+}
+func TestSetRGBAData(t *testing.T) {
+	var textures Textures
+	var wrapper mipmapWrapperMock
+	textures.TransparentTexture(1, 1, 128, "tex", &wrapper)
+	textures[0].SetRGBAData(image.NewRGBA(image.Rect(0, 0, 4, 2)))
+	if len(textures) != 1 {
+		t.Error("SetRGBAData shouldn't add texture.")
+	}
+	// the mipmaps are generated for the bound target, not for the texture name.
+	if len(wrapper.targets) != 2 || wrapper.targets[0] != glwrapper.TEXTURE_2D || wrapper.targets[1] != glwrapper.TEXTURE_2D {
+		t.Errorf("Invalid mipmap targets '%v'.", wrapper.targets)
+	}
+}
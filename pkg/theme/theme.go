@@ -15,6 +15,7 @@ type Theme struct {
 	frameMaterial           *material.Material
 	menuItemDefaultMaterial *material.Material
 	menuItemHoverMaterial   *material.Material
+	menuItemPressedMaterial *material.Material
 	menuItemSurfaceTexture  texture.Textures
 	headerLabelColor        mgl32.Vec3
 	labelColor              mgl32.Vec3
@@ -92,6 +93,16 @@ func (t *Theme) GetMenuItemHoverMaterial() *material.Material {
 	return t.menuItemHoverMaterial
 }
 
+// SetMenuItemPressedMaterial sets the menuItemPressedMaterial.
+func (t *Theme) SetMenuItemPressedMaterial(m *material.Material) {
+	t.menuItemPressedMaterial = m
+}
+
+// GetMenuItemPressedMaterial returns the menuItemPressedMaterial.
+func (t *Theme) GetMenuItemPressedMaterial() *material.Material {
+	return t.menuItemPressedMaterial
+}
+
 // SetMenuItemSurfaceTexture sets the menuItemSurfaceTexture.
 func (t *Theme) SetMenuItemSurfaceTexture(tex texture.Textures) {
 	t.menuItemSurfaceTexture = tex
@@ -118,6 +118,22 @@ func TestThemeMenuItemHoverMaterial(t *testing.T) {
 		t.Error("Invalid menuItemHoverMaterial has been get.")
 	}
 }
+func TestThemeMenuItemPressedMaterial(t *testing.T) {
+	var th Theme
+	defaultMaterial := material.Jade
+	newMaterial := material.Redplastic
+	th.menuItemPressedMaterial = defaultMaterial
+	if th.menuItemPressedMaterial != defaultMaterial {
+		t.Error("Invalid menuItemPressedMaterial.")
+	}
+	th.SetMenuItemPressedMaterial(newMaterial)
+	if th.menuItemPressedMaterial != newMaterial {
+		t.Error("Invalid menuItemPressedMaterial has been set.")
+	}
+	if th.GetMenuItemPressedMaterial() != newMaterial {
+		t.Error("Invalid menuItemPressedMaterial has been get.")
+	}
+}
 func TestThemeMenuItemSurfaceTexture(t *testing.T) {
 	var th Theme
 	var newTexture, defaultTexture texture.Textures
@@ -15,6 +15,7 @@ var (
 		frameMaterial:           material.Jade,
 		menuItemDefaultMaterial: material.Whiteplastic,
 		menuItemHoverMaterial:   material.Ruby,
+		menuItemPressedMaterial: material.Redplastic,
 		menuItemSurfaceTexture:  nil,
 		headerLabelColor:        mgl32.Vec3{0, 0, 1},
 		labelColor:              mgl32.Vec3{0, 0, 1},
@@ -29,6 +30,7 @@ var (
 		frameMaterial:           material.Blackplastic,
 		menuItemDefaultMaterial: material.Obsidian,
 		menuItemHoverMaterial:   material.Greenplastic,
+		menuItemPressedMaterial: material.Greenrubber,
 		menuItemSurfaceTexture:  nil,
 		headerLabelColor:        mgl32.Vec3{1, 1, 1},
 		labelColor:              mgl32.Vec3{0, 1, 0},
@@ -0,0 +1,830 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/input"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/material"
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+	"github.com/akosgarai/playground_engine/pkg/model"
+	"github.com/akosgarai/playground_engine/pkg/primitives/rectangle"
+	"github.com/akosgarai/playground_engine/pkg/shader"
+	"github.com/akosgarai/playground_engine/pkg/texture"
+	"github.com/akosgarai/playground_engine/pkg/theme"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	DefaultFontFile  = "/assets/fonts/Go/Go-Regular.ttf"
+	DefaultFontScale = float32(0.4)
+)
+
+const (
+	// The positions of the root widgets in the window.
+	ANCHOR_TOP_LEFT = iota
+	ANCHOR_TOP
+	ANCHOR_TOP_RIGHT
+	ANCHOR_LEFT
+	ANCHOR_CENTER
+	ANCHOR_RIGHT
+	ANCHOR_BOTTOM_LEFT
+	ANCHOR_BOTTOM
+	ANCHOR_BOTTOM_RIGHT
+	// The widget fills the window, the offsets are the margins.
+	ANCHOR_FILL
+)
+
+// The keys, that are passed to the focused widget.
+var layerKeys = []glfw.Key{
+	glfw.KeyTab, glfw.KeyEnter, glfw.KeyKPEnter, glfw.KeySpace, glfw.KeyBackspace, glfw.KeyEscape,
+	glfw.KeyUp, glfw.KeyDown, glfw.KeyLeft, glfw.KeyRight,
+	glfw.KeyHome, glfw.KeyEnd, glfw.KeyPageUp, glfw.KeyPageDown,
+}
+
+// layerItem is a root widget of the layer with its position.
+type layerItem struct {
+	widget           Widget
+	anchor           int
+	offsetX, offsetY float32
+}
+
+type LayerBuilder struct {
+	wrapper                   interfaces.GLWrapper
+	windowWidth, windowHeight float32
+	charset                   *model.Charset
+	theme                     *theme.Theme
+	fontScale                 float32
+}
+
+// NewLayerBuilder returns a builder with the default theme and font scale.
+func NewLayerBuilder() *LayerBuilder {
+	return &LayerBuilder{
+		windowWidth:  800,
+		windowHeight: 600,
+		theme:        theme.Default,
+		fontScale:    DefaultFontScale,
+	}
+}
+
+// SetWrapper sets the glwrapper of the layer.
+func (b *LayerBuilder) SetWrapper(w interfaces.GLWrapper) {
+	b.wrapper = w
+}
+
+// SetWindowSize sets the size of the window in pixels.
+func (b *LayerBuilder) SetWindowSize(width, height float32) {
+	b.windowWidth = width
+	b.windowHeight = height
+}
+
+// SetCharset sets the charset of the texts. In case of sdf charset, the sdf font shader is used.
+func (b *LayerBuilder) SetCharset(c *model.Charset) {
+	b.charset = c
+}
+
+// SetTheme sets the theme, that provides the materials and the colors of the widgets.
+func (b *LayerBuilder) SetTheme(t *theme.Theme) {
+	b.theme = t
+}
+
+// SetFontScale sets the scale of the charset glyphs. The size of the texts in pixels
+// is the font size of the charset multiplied with this scale.
+func (b *LayerBuilder) SetFontScale(s float32) {
+	b.fontScale = s
+}
+
+// Build returns the layer. In case of missing wrapper it panics. Without charset
+// the Go font is loaded.
+func (b *LayerBuilder) Build() *Layer {
+	if b.wrapper == nil {
+		panic("Wrapper is missing for the build process.")
+	}
+	if b.charset == nil {
+		cs, err := model.LoadCharset(baseDirUi()+DefaultFontFile, 32, 127, 40.0, 72, b.wrapper)
+		if err != nil {
+			panic(err)
+		}
+		b.charset = cs
+	}
+	l := &Layer{
+		wrapper:          b.wrapper,
+		windowWidth:      b.windowWidth,
+		windowHeight:     b.windowHeight,
+		charset:          b.charset,
+		theme:            b.theme,
+		fontScale:        b.fontScale,
+		surfaceTexture:   b.theme.GetMenuItemSurfaceTexture(),
+		backgroundShader: shader.NewMenuBackgroundShader(b.wrapper),
+		keys:             make(map[glfw.Key]bool),
+		uniformFloat:     make(map[string]float32),
+		uniformVector:    make(map[string]mgl32.Vec3),
+	}
+	if b.charset.IsSDF() {
+		l.fontShader = shader.NewSDFFontShader(b.wrapper)
+	} else {
+		l.fontShader = shader.NewFontShader(b.wrapper)
+	}
+	if l.surfaceTexture == nil {
+		l.surfaceTexture.TransparentTexture(1, 1, 1, "paper", b.wrapper)
+	}
+	return l
+}
+
+// Layer is a screen, that displays a widget tree. It handles the layout, the focus, the
+// mouse and keyboard input of the widgets. It could be used as a standalone screen or
+// on top of other screens with the OverlayScreen.
+type Layer struct {
+	wrapper                   interfaces.GLWrapper
+	windowWidth, windowHeight float32
+	charset                   *model.Charset
+	theme                     *theme.Theme
+	fontScale                 float32
+	surfaceTexture            texture.Textures
+	backgroundShader          interfaces.Shader
+	fontShader                interfaces.Shader
+	items                     []*layerItem
+	// the popup is displayed above every other widget, like the list of the dropdown.
+	popup, popupOwner Widget
+	popupRect         Rect
+	// the visible widgets in drawing order.
+	drawList, popupList []Widget
+	dirty               bool
+	hovered             Widget
+	pressed             Widget
+	focused             Widget
+	pointerX, pointerY  float32
+	mouseDown           bool
+	keys                map[glfw.Key]bool
+	actions             interfaces.RoActionMap
+	uniformFloat        map[string]float32
+	uniformVector       map[string]mgl32.Vec3
+}
+
+// Add inserts the widget as a root of the layer. It is placed to the anchor position of
+// the window, the offsets are the distance from the anchored edges in pixels.
+func (l *Layer) Add(w Widget, anchor int, offsetX, offsetY float32) {
+	l.items = append(l.items, &layerItem{widget: w, anchor: anchor, offsetX: offsetX, offsetY: offsetY})
+	l.dirty = true
+}
+
+// Remove deletes the root widget from the layer.
+func (l *Layer) Remove(w Widget) {
+	for i := range l.items {
+		if l.items[i].widget == w {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+			l.dirty = true
+			return
+		}
+	}
+}
+
+// GetCharset returns the charset of the texts.
+func (l *Layer) GetCharset() *model.Charset {
+	return l.charset
+}
+
+// GetTheme returns the theme of the widgets.
+func (l *Layer) GetTheme() *theme.Theme {
+	return l.theme
+}
+
+// LineHeight returns the height of a text line in pixels.
+func (l *Layer) LineHeight() float32 {
+	return l.charset.LineHeight(l.fontScale)
+}
+
+// TextWidth returns the width of the text in pixels.
+func (l *Layer) TextWidth(text string) float32 {
+	return l.charset.TextWidth(text, l.fontScale)
+}
+
+// capHeight returns the height of the capital letters in pixels. It is used
+// for the vertical placement of the texts.
+func (l *Layer) capHeight() float32 {
+	_, h := l.charset.TextContainerSize("W", l.fontScale)
+	return h
+}
+
+// SizeOf returns the size of the widget. The fixed sizes are used if they are set,
+// otherwise the preferred size of the widget.
+func (l *Layer) SizeOf(w Widget) (float32, float32) {
+	b := w.Base()
+	width, height := b.width, b.height
+	if width == 0 || height == 0 {
+		pw, ph := w.PreferredSize(l)
+		if width == 0 {
+			width = pw
+		}
+		if height == 0 {
+			height = ph
+		}
+	}
+	return width, height
+}
+
+// Place sets the bounds of the widget and arranges its children. It has to be called
+// by the layouts.
+func (l *Layer) Place(w Widget, r Rect) {
+	b := w.Base()
+	b.bounds = r
+	b.needsLayout = false
+	b.needsBuild = true
+	w.Arrange(l)
+}
+
+// Surface adds a rectangle with the given material to the widget. It has to be called
+// from the Build function of the widgets. The surfaces are drawn in the order of the calls.
+func (l *Layer) Surface(b *WidgetBase, r Rect, mat *material.Material) {
+	var msh *mesh.TexturedMaterialMesh
+	if b.used < len(b.surfaces) {
+		msh = b.surfaces[b.used]
+		msh.Material = mat
+	} else {
+		v, i, _ := rectangle.NewExact(1, 1).MeshInput()
+		msh = mesh.NewTexturedMaterialMesh(v, i, l.surfaceTexture, mat, l.wrapper)
+		msh.RotateX(-90)
+		b.surfaces = append(b.surfaces, msh)
+	}
+	msh.SetScale(mgl32.Vec3{2 * r.Width / l.windowWidth, 1, 2 * r.Height / l.windowHeight})
+	msh.SetPosition(l.toNDC(r.X+r.Width/2, r.Y+r.Height/2))
+	b.used++
+}
+
+// Text prints the text to the widget. The x is the aligned position of the lines, the y is
+// the top of the text in pixels. It has to be called from the Build function of the widgets.
+func (l *Layer) Text(b *WidgetBase, text string, x, y float32, align int, col mgl32.Vec3) {
+	if b.textRoot == nil {
+		b.textRoot = mesh.NewPointMesh(l.wrapper)
+		b.textRoot.RotateX(-90)
+	}
+	centerX, centerY := b.bounds.X+b.bounds.Width/2, b.bounds.Y+b.bounds.Height/2
+	b.textRoot.SetScale(mgl32.Vec3{2 / l.windowWidth, 1, 2 / l.windowHeight})
+	b.textRoot.SetPosition(l.toNDC(centerX, centerY))
+	// the texts are printed to the baseline, the local up direction is the -y in pixels.
+	l.charset.PrintAlignedTo(text, x-centerX, centerY-(y+l.capHeight()), 0, l.fontScale, align, l.wrapper, b.textRoot, []mgl32.Vec3{col})
+}
+
+// TextY returns the top position of a single line text, that is vertically
+// centered in the given rectangle.
+func (l *Layer) TextY(r Rect) float32 {
+	return r.Y + (r.Height-l.capHeight())/2
+}
+
+// ControlMaterial returns the material of the interactive widgets based on their state.
+// The focused widgets are displayed with the hover material.
+func (l *Layer) ControlMaterial(w Widget) *material.Material {
+	b := w.Base()
+	if b.state == STATE_PRESSED {
+		return l.theme.GetMenuItemPressedMaterial()
+	}
+	if b.state == STATE_HOVER || l.focused == w {
+		return l.theme.GetMenuItemHoverMaterial()
+	}
+	return l.theme.GetMenuItemDefaultMaterial()
+}
+
+// toNDC returns the normalized device coordinates of the pixel position.
+func (l *Layer) toNDC(x, y float32) mgl32.Vec3 {
+	return mgl32.Vec3{x/l.windowWidth*2 - 1, 1 - y/l.windowHeight*2, 0}
+}
+
+// OpenPopup displays the popup widget in the given rectangle above every other widget.
+// The keys of the focused widget are passed to the owner until the popup is closed.
+// The popup is closed with a click outside of the popup and the owner.
+func (l *Layer) OpenPopup(owner, popup Widget, r Rect) {
+	l.ClosePopup()
+	l.popup = popup
+	l.popupOwner = owner
+	l.popupRect = r
+	l.dirty = true
+}
+
+// ClosePopup hides the current popup.
+func (l *Layer) ClosePopup() {
+	if l.popup == nil {
+		return
+	}
+	owner := l.popupOwner
+	l.popup = nil
+	l.popupOwner = nil
+	l.popupList = nil
+	l.dirty = true
+	owner.Base().Invalidate()
+}
+
+// GetPopup returns the displayed popup widget or nil.
+func (l *Layer) GetPopup() Widget {
+	return l.popup
+}
+
+// GetFocus returns the focused widget or nil.
+func (l *Layer) GetFocus() Widget {
+	return l.focused
+}
+
+// SetFocus focuses the given widget. The nil value removes the focus. The scroll views
+// of the widget are scrolled to make it visible.
+func (l *Layer) SetFocus(w Widget) {
+	if l.focused == w {
+		return
+	}
+	if l.focused != nil {
+		l.focused.Base().Invalidate()
+	}
+	l.focused = w
+	if w == nil {
+		return
+	}
+	w.Base().Invalidate()
+	path := l.pathTo(w)
+	for i := len(path) - 2; i >= 0; i-- {
+		if sv, ok := path[i].(*ScrollView); ok {
+			sv.scrollIntoView(w.Base().bounds)
+		}
+	}
+}
+
+// WantsPointer returns true if the pointer is over a widget or a widget is pressed.
+// The screens below the layer shouldn't get the mouse buttons in this case.
+func (l *Layer) WantsPointer() bool {
+	return l.pressed != nil || l.hit(l.pointerX, l.pointerY) != nil
+}
+
+// WantsKeyboard returns true if a widget is focused. The screens below the layer
+// shouldn't get the keys in this case.
+func (l *Layer) WantsKeyboard() bool {
+	return l.focused != nil || l.popup != nil
+}
+
+// layout places the root widgets and the popup, then it collects the drawing lists.
+func (l *Layer) layout() {
+	window := Rect{Width: l.windowWidth, Height: l.windowHeight}
+	l.drawList = nil
+	for _, item := range l.items {
+		if !item.widget.Base().visible {
+			continue
+		}
+		l.Place(item.widget, l.itemRect(item))
+		l.drawList = l.collect(item.widget, window, l.drawList)
+	}
+	l.popupList = nil
+	if l.popup != nil {
+		l.Place(l.popup, l.popupRect)
+		l.popupList = l.collect(l.popup, window, nil)
+	}
+	l.dirty = false
+	// the removed widgets lose their states.
+	if l.hovered != nil && !l.displayed(l.hovered) {
+		l.hovered = nil
+	}
+	if l.pressed != nil && !l.displayed(l.pressed) {
+		l.pressed = nil
+	}
+	if l.focused != nil && !l.displayed(l.focused) {
+		l.focused = nil
+	}
+}
+
+// itemRect returns the bounds of the root widget based on its anchor.
+func (l *Layer) itemRect(item *layerItem) Rect {
+	if item.anchor == ANCHOR_FILL {
+		return Rect{X: item.offsetX, Y: item.offsetY, Width: l.windowWidth - 2*item.offsetX, Height: l.windowHeight - 2*item.offsetY}
+	}
+	w, h := l.SizeOf(item.widget)
+	r := Rect{X: item.offsetX, Y: item.offsetY, Width: w, Height: h}
+	switch item.anchor % 3 {
+	case 1:
+		r.X = (l.windowWidth-w)/2 + item.offsetX
+		break
+	case 2:
+		r.X = l.windowWidth - w - item.offsetX
+		break
+	}
+	switch item.anchor / 3 {
+	case 1:
+		r.Y = (l.windowHeight-h)/2 + item.offsetY
+		break
+	case 2:
+		r.Y = l.windowHeight - h - item.offsetY
+		break
+	}
+	return r
+}
+
+// collect appends the visible widgets of the tree to the list and sets their clip rectangles.
+func (l *Layer) collect(w Widget, clip Rect, list []Widget) []Widget {
+	b := w.Base()
+	if !b.visible {
+		return list
+	}
+	b.clip = b.bounds.Intersect(clip)
+	list = append(list, w)
+	if b.clipChildren {
+		clip = b.clip
+	}
+	for _, c := range b.children {
+		list = l.collect(c, clip, list)
+	}
+	return list
+}
+
+// displayed returns true if the widget is in the drawing lists.
+func (l *Layer) displayed(w Widget) bool {
+	for _, list := range [][]Widget{l.drawList, l.popupList} {
+		for i := range list {
+			if list[i] == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// needsLayout returns true if a widget of the tree has been changed.
+func needsLayout(w Widget) bool {
+	b := w.Base()
+	if b.needsLayout {
+		return true
+	}
+	for _, c := range b.children {
+		if needsLayout(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// relayout places the widgets if it is necessary.
+func (l *Layer) relayout() {
+	dirty := l.dirty || (l.popup != nil && needsLayout(l.popup))
+	for i := 0; !dirty && i < len(l.items); i++ {
+		dirty = needsLayout(l.items[i].widget)
+	}
+	if dirty {
+		l.layout()
+	}
+}
+
+// build rebuilds the invalidated widgets.
+func (l *Layer) build() {
+	for _, list := range [][]Widget{l.drawList, l.popupList} {
+		for _, w := range list {
+			b := w.Base()
+			if !b.needsBuild {
+				continue
+			}
+			b.used = 0
+			if b.textRoot != nil {
+				l.charset.CleanSurface(b.textRoot)
+			}
+			w.Build(l)
+			b.needsBuild = false
+		}
+	}
+}
+
+// hit returns the topmost interactive widget under the given position.
+func (l *Layer) hit(x, y float32) Widget {
+	for _, list := range [][]Widget{l.popupList, l.drawList} {
+		for i := len(list) - 1; i >= 0; i-- {
+			b := list[i].Base()
+			if b.interactive && b.clip.Contains(x, y) {
+				return list[i]
+			}
+		}
+	}
+	return nil
+}
+
+// pathTo returns the widgets from the root to the given widget.
+func (l *Layer) pathTo(w Widget) []Widget {
+	var search func(node Widget, path []Widget) []Widget
+	search = func(node Widget, path []Widget) []Widget {
+		path = append(path, node)
+		if node == w {
+			return path
+		}
+		for _, c := range node.Base().children {
+			if result := search(c, path); result != nil {
+				return result
+			}
+		}
+		return nil
+	}
+	roots := make([]Widget, 0, len(l.items)+1)
+	if l.popup != nil {
+		roots = append(roots, l.popup)
+	}
+	for i := range l.items {
+		roots = append(roots, l.items[i].widget)
+	}
+	for _, r := range roots {
+		if path := search(r, nil); path != nil {
+			return path
+		}
+	}
+	return nil
+}
+
+// focusTarget returns the closest focusable widget of the path of the given widget.
+func (l *Layer) focusTarget(w Widget) Widget {
+	path := l.pathTo(w)
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].Base().focusable {
+			return path[i]
+		}
+	}
+	return nil
+}
+
+// focusNext moves the focus to the next (or the previous) focusable widget.
+func (l *Layer) focusNext(backward bool) {
+	var focusables []Widget
+	current := -1
+	for _, w := range l.drawList {
+		b := w.Base()
+		if !b.focusable {
+			continue
+		}
+		if w == l.focused {
+			current = len(focusables)
+		}
+		focusables = append(focusables, w)
+	}
+	if len(focusables) == 0 {
+		return
+	}
+	next := 0
+	if backward {
+		next = len(focusables) - 1
+		if current >= 0 {
+			next = (current - 1 + len(focusables)) % len(focusables)
+		}
+	} else if current >= 0 {
+		next = (current + 1) % len(focusables)
+	}
+	l.ClosePopup()
+	l.SetFocus(focusables[next])
+}
+
+// setState updates the state of the widget, and marks it for rebuild.
+func setState(w Widget, state int) {
+	if w == nil || w.Base().state == state {
+		return
+	}
+	w.Base().state = state
+	w.Base().Invalidate()
+}
+
+// Update handles the mouse and the keyboard input of the widgets, then it
+// rebuilds the changed widgets.
+func (l *Layer) Update(dt float64, p interfaces.Pointer, keyStore interfaces.RoKeyStore, buttonStore interfaces.RoButtonStore) {
+	l.relayout()
+	posX, posY := p.GetCurrent()
+	x := float32(posX+1) / 2 * l.windowWidth
+	y := float32(1-posY) / 2 * l.windowHeight
+	moved := x != l.pointerX || y != l.pointerY
+	l.pointerX, l.pointerY = x, y
+	down := l.actionDown(input.ACTION_SELECT, buttonStore.Get(glfw.MouseButtonLeft))
+	l.mouse(x, y, down, moved)
+	l.keyboard(keyStore)
+	l.relayout()
+	l.build()
+}
+
+// actionDown returns the state of the action, or the fallback state of the stores, if the
+// action is missing or its context is disabled. The layers are used over game screens,
+// where the menu actions are disabled.
+func (l *Layer) actionDown(name string, fallback bool) bool {
+	if l.actions == nil || !l.actions.IsEnabled(name) {
+		return fallback
+	}
+	return l.actions.IsActive(name)
+}
+
+// mouse handles the hover, press, drag and release of the widgets.
+func (l *Layer) mouse(x, y float32, down, moved bool) {
+	target := l.hit(x, y)
+	if down && !l.mouseDown {
+		if l.popup != nil && target != l.popupOwner && (target == nil || !l.inPopup(target)) {
+			l.ClosePopup()
+		}
+		// the popup doesn't take the focus from its owner.
+		if target == nil || !l.inPopup(target) {
+			l.SetFocus(nil)
+			if target != nil {
+				l.SetFocus(l.focusTarget(target))
+			}
+		}
+		if target != nil {
+			l.pressed = target
+			setState(target, STATE_PRESSED)
+			target.MouseDown(l, x, y)
+		}
+	} else if down && l.pressed != nil && moved {
+		l.pressed.MouseDrag(l, x, y)
+	} else if !down && l.mouseDown && l.pressed != nil {
+		pressed := l.pressed
+		l.pressed = nil
+		setState(pressed, STATE_NORMAL)
+		pressed.MouseUp(l, x, y, pressed == target)
+		// the popup could be opened or closed by the event.
+		l.relayout()
+		target = l.hit(x, y)
+	}
+	l.mouseDown = down
+	if target != l.hovered {
+		if l.hovered != nil && l.hovered != l.pressed {
+			setState(l.hovered, STATE_NORMAL)
+		}
+		l.hovered = target
+	}
+	if target != nil && target != l.pressed {
+		setState(target, STATE_HOVER)
+		if moved {
+			target.MouseMove(l, x, y)
+		}
+	}
+}
+
+// inPopup returns true if the widget is the part of the popup.
+func (l *Layer) inPopup(w Widget) bool {
+	for i := range l.popupList {
+		if l.popupList[i] == w {
+			return true
+		}
+	}
+	return false
+}
+
+// keyboard passes the pressed keys to the focused widget. The keys are bubbling up
+// to the parents of the widget until a widget handles them.
+func (l *Layer) keyboard(keyStore interfaces.RoKeyStore) {
+	for _, key := range layerKeys {
+		down := keyStore.Get(key)
+		switch key {
+		case glfw.KeyUp:
+			down = l.actionDown(input.ACTION_MENU_UP, down)
+			break
+		case glfw.KeyDown:
+			down = l.actionDown(input.ACTION_MENU_DOWN, down)
+			break
+		}
+		pressed := down && !l.keys[key]
+		l.keys[key] = down
+		if !pressed {
+			continue
+		}
+		if key == glfw.KeyTab {
+			l.focusNext(keyStore.Get(glfw.KeyLeftShift) || keyStore.Get(glfw.KeyRightShift))
+			continue
+		}
+		if !l.dispatchKey(key) && key == glfw.KeyEscape {
+			l.SetFocus(nil)
+		}
+	}
+}
+
+// dispatchKey passes the key to the owner of the popup or to the focus path.
+func (l *Layer) dispatchKey(key glfw.Key) bool {
+	if l.popupOwner != nil && l.popupOwner.Key(l, key) {
+		return true
+	}
+	if l.focused == nil {
+		return false
+	}
+	path := l.pathTo(l.focused)
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].Key(l, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// CharCallback passes the character to the focus path.
+func (l *Layer) CharCallback(char rune, wrapper interfaces.GLWrapper) {
+	if l.focused == nil {
+		return
+	}
+	path := l.pathTo(l.focused)
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].Char(l, char) {
+			break
+		}
+	}
+	l.relayout()
+	l.build()
+}
+
+// Draw displays the widgets without depth test. Every widget is clipped to its clip
+// rectangle with the scissor test. The popup is drawn after the other widgets.
+func (l *Layer) Draw(wrapper interfaces.GLWrapper) {
+	l.relayout()
+	l.build()
+	wrapper.Disable(glwrapper.DEPTH_TEST)
+	wrapper.Enable(glwrapper.BLEND)
+	wrapper.BlendFunc(glwrapper.SRC_APLHA, glwrapper.ONE_MINUS_SRC_ALPHA)
+	wrapper.Enable(glwrapper.SCISSOR_TEST)
+	l.drawWidgets(wrapper, l.drawList)
+	l.drawWidgets(wrapper, l.popupList)
+	wrapper.Disable(glwrapper.SCISSOR_TEST)
+	wrapper.Enable(glwrapper.DEPTH_TEST)
+}
+
+// drawWidgets draws the surfaces of the widgets, then their texts.
+func (l *Layer) drawWidgets(wrapper interfaces.GLWrapper, list []Widget) {
+	if len(list) == 0 {
+		return
+	}
+	for _, sh := range []interfaces.Shader{l.backgroundShader, l.fontShader} {
+		sh.Use()
+		sh.SetUniformMat4("view", mgl32.Ident4())
+		sh.SetUniformMat4("projection", mgl32.Ident4())
+		l.customUniforms(sh)
+		for _, w := range list {
+			b := w.Base()
+			if b.clip.IsEmpty() {
+				continue
+			}
+			if sh == l.backgroundShader && b.used == 0 || sh == l.fontShader && b.textRoot == nil {
+				continue
+			}
+			l.scissor(wrapper, b.clip)
+			if sh == l.backgroundShader {
+				for i := 0; i < b.used; i++ {
+					b.surfaces[i].Draw(sh)
+				}
+			} else {
+				l.charset.DrawSurface(sh, b.textRoot)
+			}
+		}
+	}
+}
+
+// scissor sets the scissor box to the given rectangle. The scissor box has bottom left origin.
+func (l *Layer) scissor(wrapper interfaces.GLWrapper, r Rect) {
+	x := float32(math.Floor(float64(r.X)))
+	y := float32(math.Floor(float64(l.windowHeight - r.Y - r.Height)))
+	wrapper.Scissor(int32(x), int32(y), int32(math.Ceil(float64(r.X+r.Width-x))), int32(math.Ceil(float64(l.windowHeight-r.Y-y))))
+}
+
+// customUniforms sets the custom uniforms of the layer.
+func (l *Layer) customUniforms(sh interfaces.Shader) {
+	for name, value := range l.uniformFloat {
+		sh.SetUniform1f(name, value)
+	}
+	for name, value := range l.uniformVector {
+		sh.SetUniform3f(name, value.X(), value.Y(), value.Z())
+	}
+}
+
+// Log returns the string representation of the layer.
+func (l *Layer) Log() string {
+	return "UI layer"
+}
+
+// Export does nothing, the widgets are not exported.
+func (l *Layer) Export(path string) {}
+
+// GetCamera returns nil, the layer is drawn in normalized device coordinates.
+func (l *Layer) GetCamera() interfaces.Camera {
+	return nil
+}
+
+// GetClosestModelMeshDistance returns nil values, the layer doesn't contain models.
+func (l *Layer) GetClosestModelMeshDistance() (interfaces.Model, interfaces.Mesh, float32) {
+	return nil, nil, math.MaxFloat32
+}
+
+// SetUniformFloat sets a float uniform of the shaders.
+func (l *Layer) SetUniformFloat(key string, value float32) {
+	l.uniformFloat[key] = value
+}
+
+// SetUniformVector sets a vector uniform of the shaders.
+func (l *Layer) SetUniformVector(key string, value mgl32.Vec3) {
+	l.uniformVector[key] = value
+}
+
+// SetWindowSize updates the window size. The widgets are placed again.
+func (l *Layer) SetWindowSize(width, height float32) {
+	l.windowWidth = width
+	l.windowHeight = height
+	l.dirty = true
+}
+
+// SetWrapper sets the glwrapper of the layer.
+func (l *Layer) SetWrapper(w interfaces.GLWrapper) {
+	l.wrapper = w
+}
+
+// SetActionMap sets the action map. The select action is used as the mouse button,
+// the menu up and down actions are used as the arrow keys.
+func (l *Layer) SetActionMap(actions interfaces.RoActionMap) {
+	l.actions = actions
+}
@@ -0,0 +1,335 @@
+package ui
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/input"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/testhelper"
+	"github.com/akosgarai/playground_engine/pkg/theme"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestNewLayerBuilder(t *testing.T) {
+	b := NewLayerBuilder()
+	if b.theme != theme.Default || b.fontScale != DefaultFontScale {
+		t.Error("Invalid default builder values.")
+	}
+	b.SetTheme(theme.Dark)
+	b.SetFontScale(0.5)
+	if b.theme != theme.Dark || b.fontScale != 0.5 {
+		t.Error("Invalid builder values.")
+	}
+}
+func TestLayerBuilderBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	runtime.LockOSThread()
+	testhelper.GlfwInit(glwrapper.GL_MAJOR_VERSION, glwrapper.GL_MINOR_VERSION)
+	wrapperReal.InitOpenGL()
+	defer testhelper.GlfwTerminate()
+	b := NewLayerBuilder()
+	// wo wrapper, it should panic
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("It should panic without wrapper.")
+			}
+		}()
+		b.Build()
+	}()
+	b.SetWrapper(wrapperReal)
+	b.SetWindowSize(testWindowWidth, testWindowHeight)
+	l := b.Build()
+	if l.GetCharset() == nil || l.GetTheme() != theme.Default || l.surfaceTexture == nil {
+		t.Error("Invalid layer.")
+	}
+}
+func TestLayerScreen(t *testing.T) {
+	l := newTestLayer()
+	if l.GetCamera() != nil {
+		t.Error("The layer shouldn't have camera.")
+	}
+	if _, msh, _ := l.GetClosestModelMeshDistance(); msh != nil {
+		t.Error("The layer shouldn't have closest mesh.")
+	}
+}
+func TestLayerAnchors(t *testing.T) {
+	l := newTestLayer()
+	testData := []struct {
+		anchor   int
+		expected Rect
+	}{
+		{ANCHOR_TOP_LEFT, Rect{X: 10, Y: 20, Width: 100, Height: 50}},
+		{ANCHOR_TOP, Rect{X: 360, Y: 20, Width: 100, Height: 50}},
+		{ANCHOR_RIGHT, Rect{X: 690, Y: 295, Width: 100, Height: 50}},
+		{ANCHOR_CENTER, Rect{X: 360, Y: 295, Width: 100, Height: 50}},
+		{ANCHOR_BOTTOM_RIGHT, Rect{X: 690, Y: 530, Width: 100, Height: 50}},
+		{ANCHOR_FILL, Rect{X: 10, Y: 20, Width: 780, Height: 560}},
+	}
+	for _, tt := range testData {
+		b := fixedButton(100, 50)
+		l.Add(b, tt.anchor, 10, 20)
+		newTestInput().update(l)
+		if b.GetBounds() != tt.expected {
+			t.Errorf("Invalid bounds '%v' for anchor '%d'. Instead of '%v'.", b.GetBounds(), tt.anchor, tt.expected)
+		}
+		l.Remove(b)
+	}
+	if len(l.items) != 0 {
+		t.Error("The widgets should be removed.")
+	}
+}
+func TestLayerWindowSize(t *testing.T) {
+	l := newTestLayer()
+	b := fixedButton(100, 50)
+	l.Add(b, ANCHOR_BOTTOM_RIGHT, 0, 0)
+	in := newTestInput()
+	in.update(l)
+	l.SetWindowSize(400, 300)
+	in.update(l)
+	if b.GetBounds() != (Rect{X: 300, Y: 250, Width: 100, Height: 50}) {
+		t.Errorf("Invalid bounds after resize '%v'.", b.GetBounds())
+	}
+	// the surface is in normalized device coordinates
+	if position := b.surfaces[0].GetPosition(); !testhelper.Float32ApproxEqual(position.X(), 0.75, 0.001) || !testhelper.Float32ApproxEqual(position.Y(), -5.0/6.0, 0.001) {
+		t.Errorf("Invalid surface position '%v'.", position)
+	}
+}
+func TestLayerHover(t *testing.T) {
+	l := newTestLayer()
+	b1, b2 := fixedButton(100, 50), fixedButton(100, 50)
+	l.Add(b1, ANCHOR_TOP_LEFT, 0, 0)
+	l.Add(b2, ANCHOR_TOP_LEFT, 50, 0)
+	in := newTestInput()
+	in.x, in.y = 75, 25
+	in.update(l)
+	// the later widget is above the former one
+	if b2.GetState() != STATE_HOVER || b1.GetState() != STATE_NORMAL {
+		t.Error("The top widget should be hovered.")
+	}
+	if !l.WantsPointer() || l.WantsKeyboard() {
+		t.Error("The layer should want only the pointer.")
+	}
+	in.x = 25
+	in.update(l)
+	if b2.GetState() != STATE_NORMAL || b1.GetState() != STATE_HOVER {
+		t.Error("The first widget should be hovered.")
+	}
+	if l.ControlMaterial(b1) != theme.Default.GetMenuItemHoverMaterial() || l.ControlMaterial(b2) != theme.Default.GetMenuItemDefaultMaterial() {
+		t.Error("Invalid control materials.")
+	}
+	in.x = 500
+	in.update(l)
+	if b1.GetState() != STATE_NORMAL || l.WantsPointer() {
+		t.Error("The widgets shouldn't be hovered.")
+	}
+}
+func TestLayerFocus(t *testing.T) {
+	l := newTestLayer()
+	clicked := ""
+	p := NewPanel(NewFlexLayout(FLEX_COLUMN, 0))
+	b1 := NewButton("b1", func() { clicked = "b1" })
+	b2 := NewButton("b2", func() { clicked = "b2" })
+	p.Add(NewLabel("label"), b1, b2)
+	l.Add(p, ANCHOR_CENTER, 0, 0)
+	in := newTestInput()
+	in.press(l, glfw.KeyTab)
+	if l.GetFocus() != b1 || !l.WantsKeyboard() {
+		t.Fatal("The first button should be focused.")
+	}
+	if l.ControlMaterial(b1) != theme.Default.GetMenuItemHoverMaterial() {
+		t.Error("The focused button should have hover material.")
+	}
+	in.press(l, glfw.KeyTab)
+	in.press(l, glfw.KeyEnter)
+	if l.GetFocus() != b2 || clicked != "b2" {
+		t.Error("The second button should be focused and clicked.")
+	}
+	in.press(l, glfw.KeyTab)
+	if l.GetFocus() != b1 {
+		t.Error("The focus should be moved to the first button.")
+	}
+	in.keys.Set(glfw.KeyLeftShift, true)
+	in.press(l, glfw.KeyTab)
+	in.keys.Set(glfw.KeyLeftShift, false)
+	if l.GetFocus() != b2 {
+		t.Error("The shift tab should move the focus backward.")
+	}
+	// the held key is pressed only once
+	in.keys.Set(glfw.KeyEnter, true)
+	in.update(l)
+	in.update(l)
+	in.keys.Set(glfw.KeyEnter, false)
+	clicked = ""
+	in.update(l)
+	if clicked != "" {
+		t.Error("The held key shouldn't click again.")
+	}
+	in.press(l, glfw.KeyEscape)
+	if l.GetFocus() != nil {
+		t.Error("The escape should remove the focus.")
+	}
+	// the removed widgets lose the focus
+	in.press(l, glfw.KeyTab)
+	p.Remove(b1)
+	in.update(l)
+	if l.GetFocus() != nil {
+		t.Error("The removed widget shouldn't be focused.")
+	}
+}
+func TestLayerDraw(t *testing.T) {
+	l := newTestLayer()
+	p := NewPanel(NewFlexLayout(FLEX_COLUMN, 0))
+	d := NewDropdown([]string{"a", "b"}, 0, nil)
+	p.Add(NewLabel("label"), NewButton("button", nil), d)
+	l.Add(p, ANCHOR_CENTER, 0, 0)
+	l.SetUniformFloat("float", 1)
+	l.SetUniformVector("vector", mgl32.Vec3{1, 2, 3})
+	l.Draw(wrapperMock)
+	if len(l.drawList) != 4 || p.used != 1 {
+		t.Errorf("Invalid draw list length '%d'.", len(l.drawList))
+	}
+	d.openList(l)
+	l.Draw(wrapperMock)
+	if len(l.popupList) != 1 {
+		t.Error("The popup should be drawn.")
+	}
+	l.SetWrapper(wrapperMock)
+	l.Export("")
+	if l.Log() == "" {
+		t.Error("Missing log.")
+	}
+}
+
+// screenMock is a base screen, that stores its inputs.
+type screenMock struct {
+	keyStore    interfaces.RoKeyStore
+	buttonStore interfaces.RoButtonStore
+	actions     interfaces.RoActionMap
+	chars       []rune
+	draws       int
+	width       float32
+}
+
+func (s *screenMock) Log() string                  { return "mock" }
+func (s *screenMock) Draw(interfaces.GLWrapper)    { s.draws++ }
+func (s *screenMock) Export(string)                {}
+func (s *screenMock) GetCamera() interfaces.Camera { return nil }
+func (s *screenMock) GetClosestModelMeshDistance() (interfaces.Model, interfaces.Mesh, float32) {
+	return nil, nil, 0
+}
+func (s *screenMock) SetUniformFloat(string, float32)             {}
+func (s *screenMock) SetUniformVector(string, mgl32.Vec3)         {}
+func (s *screenMock) CharCallback(c rune, _ interfaces.GLWrapper) { s.chars = append(s.chars, c) }
+func (s *screenMock) SetWindowSize(w, h float32)                  { s.width = w }
+func (s *screenMock) SetWrapper(interfaces.GLWrapper)             {}
+func (s *screenMock) Update(dt float64, p interfaces.Pointer, ks interfaces.RoKeyStore, bs interfaces.RoButtonStore) {
+	s.keyStore = ks
+	s.buttonStore = bs
+}
+func (s *screenMock) SetActionMap(actions interfaces.RoActionMap) { s.actions = actions }
+
+func TestOverlayScreen(t *testing.T) {
+	base := &screenMock{}
+	l := newTestLayer()
+	ta := NewTextArea("", true, nil)
+	ta.SetSize(100, 100)
+	l.Add(ta, ANCHOR_TOP_LEFT, 0, 0)
+	o := NewOverlayScreen(base, l)
+	if o.GetBase() != base || o.Log() != "mock" {
+		t.Error("Invalid base screen.")
+	}
+	in := newTestInput()
+	in.x, in.y = 500, 500
+	in.buttons.Set(glfw.MouseButtonLeft, true)
+	in.keys.Set(glfw.KeyW, true)
+	p := func() interfaces.Pointer {
+		return newTestPointer(in.x, in.y)
+	}
+	actions := input.DefaultActionMap()
+	actions.EnableContext(input.CONTEXT_MENU)
+	o.SetActionMap(actions)
+	update := func() {
+		actions.Update(10, in.keys, in.buttons, nil)
+		o.Update(10, p(), in.keys, in.buttons)
+	}
+	update()
+	if !base.buttonStore.Get(glfw.MouseButtonLeft) || !base.keyStore.Get(glfw.KeyW) {
+		t.Error("The base screen should get the input outside of the widgets.")
+	}
+	if !base.actions.IsActive(input.ACTION_FORWARD) || !base.actions.IsActive(input.ACTION_SELECT) {
+		t.Error("The base screen should get the actions outside of the widgets.")
+	}
+	o.CharCallback('a', wrapperMock)
+	if len(base.chars) != 1 {
+		t.Error("The base screen should get the characters without focus.")
+	}
+	// focus the text area
+	in.buttons.Set(glfw.MouseButtonLeft, false)
+	update()
+	in.x, in.y = 50, 50
+	in.buttons.Set(glfw.MouseButtonLeft, true)
+	update()
+	if base.buttonStore.Get(glfw.MouseButtonLeft) || base.keyStore.Get(glfw.KeyW) {
+		t.Error("The base screen shouldn't get the input of the layer.")
+	}
+	if base.actions.IsActive(input.ACTION_FORWARD) || base.actions.Value(input.ACTION_FORWARD) != 0 || !base.actions.HasAction(input.ACTION_FORWARD) {
+		t.Error("The base screen shouldn't get the keyboard actions of the focused layer.")
+	}
+	if base.actions.IsActive(input.ACTION_SELECT) {
+		t.Error("The base screen shouldn't get the pointer actions of the layer.")
+	}
+	o.CharCallback('b', wrapperMock)
+	if len(base.chars) != 1 || ta.GetText() != "b" {
+		t.Error("The layer should get the characters with focus.")
+	}
+	o.Draw(wrapperMock)
+	if base.draws != 1 {
+		t.Error("The base screen should be drawn.")
+	}
+	o.SetWindowSize(400, 300)
+	if base.width != 400 || l.windowWidth != 400 {
+		t.Error("The window size should be updated.")
+	}
+	o.RemoveLayer(l)
+	update()
+	if !base.buttonStore.Get(glfw.MouseButtonLeft) {
+		t.Error("The base screen should get the input without layers.")
+	}
+	o.AddLayer(l)
+	if len(o.layers) != 1 {
+		t.Error("The layer should be added.")
+	}
+}
+func TestOverlayScreenGameContext(t *testing.T) {
+	base := &screenMock{}
+	l := newTestLayer()
+	ta := NewTextArea("", true, nil)
+	ta.SetSize(100, 100)
+	l.Add(ta, ANCHOR_TOP_LEFT, 0, 0)
+	o := NewOverlayScreen(base, l)
+	// the menu actions are disabled in the game context, the layer uses the stores.
+	actions := input.DefaultActionMap()
+	o.SetActionMap(actions)
+	in := newTestInput()
+	in.x, in.y = 50, 50
+	in.keys.Set(glfw.KeyW, true)
+	in.buttons.Set(glfw.MouseButtonLeft, true)
+	actions.Update(10, in.keys, in.buttons, nil)
+	if !actions.HasAction(input.ACTION_SELECT) || actions.IsEnabled(input.ACTION_SELECT) {
+		t.Fatal("The select action should be disabled in the game context.")
+	}
+	o.Update(10, newTestPointer(in.x, in.y), in.keys, in.buttons)
+	if !l.WantsKeyboard() {
+		t.Error("The click should focus the text area in the game context.")
+	}
+	if base.actions.IsActive(input.ACTION_FORWARD) {
+		t.Error("The base screen shouldn't get the keyboard actions of the focused layer.")
+	}
+}
@@ -0,0 +1,233 @@
+package ui
+
+const (
+	// The directions of the flex layout.
+	FLEX_ROW = iota
+	FLEX_COLUMN
+)
+
+const (
+	// The alignments of the children in the cross direction of the flex layout.
+	CROSS_STRETCH = iota
+	CROSS_START
+	CROSS_CENTER
+	CROSS_END
+)
+
+const (
+	// The distributions of the free space in the main direction of the flex layout.
+	JUSTIFY_START = iota
+	JUSTIFY_CENTER
+	JUSTIFY_END
+	JUSTIFY_SPACE_BETWEEN
+)
+
+// Layout places the children of a widget. The sizes are in pixels.
+type Layout interface {
+	// PreferredSize returns the size, that the children need, extended with the padding.
+	PreferredSize(l *Layer, b *WidgetBase) (float32, float32)
+	// Arrange sets the bounds of the children inside the bounds of the widget.
+	Arrange(l *Layer, b *WidgetBase)
+}
+
+// visibleChildren returns the displayed children of the widget.
+func visibleChildren(b *WidgetBase) []Widget {
+	var result []Widget
+	for _, c := range b.children {
+		if c.Base().visible {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// FlexLayout places the children in a row or in a column. The free space of the main
+// direction is shared among the children with grow factor. Without growing children,
+// the free space is distributed with the justify option.
+type FlexLayout struct {
+	direction int
+	gap       float32
+	cross     int
+	justify   int
+}
+
+// NewFlexLayout returns a flex layout with the given direction and with the given gap
+// between the children. The children are stretched in the cross direction.
+func NewFlexLayout(direction int, gap float32) *FlexLayout {
+	return &FlexLayout{
+		direction: direction,
+		gap:       gap,
+		cross:     CROSS_STRETCH,
+		justify:   JUSTIFY_START,
+	}
+}
+
+// SetCross sets the alignment of the children in the cross direction.
+func (f *FlexLayout) SetCross(cross int) {
+	f.cross = cross
+}
+
+// SetJustify sets the distribution of the free space in the main direction.
+func (f *FlexLayout) SetJustify(justify int) {
+	f.justify = justify
+}
+
+// mainCross returns the main and the cross component of the size.
+func (f *FlexLayout) mainCross(w, h float32) (float32, float32) {
+	if f.direction == FLEX_ROW {
+		return w, h
+	}
+	return h, w
+}
+
+// PreferredSize returns the sum of the children sizes in the main direction and
+// the maximum of them in the cross direction.
+func (f *FlexLayout) PreferredSize(l *Layer, b *WidgetBase) (float32, float32) {
+	children := visibleChildren(b)
+	var main, cross float32
+	for i, c := range children {
+		m, cr := f.mainCross(l.SizeOf(c))
+		main += m
+		if i > 0 {
+			main += f.gap
+		}
+		cross = max32(cross, cr)
+	}
+	w, h := main, cross
+	if f.direction == FLEX_COLUMN {
+		w, h = cross, main
+	}
+	return w + 2*b.padding, h + 2*b.padding
+}
+
+// Arrange places the children one after the other.
+func (f *FlexLayout) Arrange(l *Layer, b *WidgetBase) {
+	children := visibleChildren(b)
+	if len(children) == 0 {
+		return
+	}
+	content := b.bounds.Shrink(b.padding)
+	mainSize, crossSize := f.mainCross(content.Width, content.Height)
+	sizes := make([][2]float32, len(children))
+	used := f.gap * float32(len(children)-1)
+	var grow float32
+	for i, c := range children {
+		m, cr := f.mainCross(l.SizeOf(c))
+		sizes[i] = [2]float32{m, cr}
+		used += m
+		grow += c.Base().grow
+	}
+	free := max32(mainSize-used, 0)
+	position, gap := float32(0), f.gap
+	if grow > 0 {
+		for i, c := range children {
+			sizes[i][0] += free * c.Base().grow / grow
+		}
+	} else {
+		switch f.justify {
+		case JUSTIFY_CENTER:
+			position = free / 2
+			break
+		case JUSTIFY_END:
+			position = free
+			break
+		case JUSTIFY_SPACE_BETWEEN:
+			if len(children) > 1 {
+				gap += free / float32(len(children)-1)
+			}
+			break
+		}
+	}
+	for i, c := range children {
+		m, cr := sizes[i][0], sizes[i][1]
+		offset := float32(0)
+		switch f.cross {
+		case CROSS_STRETCH:
+			cr = crossSize
+			break
+		case CROSS_CENTER:
+			offset = (crossSize - cr) / 2
+			break
+		case CROSS_END:
+			offset = crossSize - cr
+			break
+		}
+		if f.direction == FLEX_ROW {
+			l.Place(c, Rect{X: content.X + position, Y: content.Y + offset, Width: m, Height: cr})
+		} else {
+			l.Place(c, Rect{X: content.X + offset, Y: content.Y + position, Width: cr, Height: m})
+		}
+		position += m + gap
+	}
+}
+
+// GridLayout places the children in a grid with the given number of columns. The columns
+// share the width equally, the height of a row is the highest preferred height of its children.
+type GridLayout struct {
+	columns int
+	gap     float32
+}
+
+// NewGridLayout returns a grid layout with the given columns and gap. In case of less than
+// one column, it panics.
+func NewGridLayout(columns int, gap float32) *GridLayout {
+	if columns < 1 {
+		panic("Invalid number of columns.")
+	}
+	return &GridLayout{
+		columns: columns,
+		gap:     gap,
+	}
+}
+
+// rowHeights returns the heights of the rows and the widest child.
+func (g *GridLayout) rowHeights(l *Layer, children []Widget) ([]float32, float32) {
+	var heights []float32
+	var width float32
+	for i, c := range children {
+		w, h := l.SizeOf(c)
+		if i%g.columns == 0 {
+			heights = append(heights, 0)
+		}
+		heights[len(heights)-1] = max32(heights[len(heights)-1], h)
+		width = max32(width, w)
+	}
+	return heights, width
+}
+
+// PreferredSize returns the size of the grid, where every column is as wide as the widest child.
+func (g *GridLayout) PreferredSize(l *Layer, b *WidgetBase) (float32, float32) {
+	children := visibleChildren(b)
+	heights, width := g.rowHeights(l, children)
+	columns := g.columns
+	if len(children) < columns {
+		columns = len(children)
+	}
+	var w, h float32
+	if columns > 0 {
+		w = width*float32(columns) + g.gap*float32(columns-1)
+	}
+	for i := range heights {
+		h += heights[i]
+		if i > 0 {
+			h += g.gap
+		}
+	}
+	return w + 2*b.padding, h + 2*b.padding
+}
+
+// Arrange places the children to the cells of the grid row by row.
+func (g *GridLayout) Arrange(l *Layer, b *WidgetBase) {
+	children := visibleChildren(b)
+	heights, _ := g.rowHeights(l, children)
+	content := b.bounds.Shrink(b.padding)
+	cellWidth := max32((content.Width-g.gap*float32(g.columns-1))/float32(g.columns), 0)
+	y := content.Y
+	for i, c := range children {
+		row, column := i/g.columns, i%g.columns
+		if column == 0 && row > 0 {
+			y += heights[row-1] + g.gap
+		}
+		l.Place(c, Rect{X: content.X + float32(column)*(cellWidth+g.gap), Y: y, Width: cellWidth, Height: heights[row]})
+	}
+}
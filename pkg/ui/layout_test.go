@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"testing"
+)
+
+func fixedButton(w, h float32) *Button {
+	b := NewButton("", nil)
+	b.SetSize(w, h)
+	return b
+}
+
+func TestFlexLayoutRow(t *testing.T) {
+	l := newTestLayer()
+	f := NewFlexLayout(FLEX_ROW, 10)
+	p := NewPanel(f)
+	p.SetPadding(5)
+	b1, b2, b3 := fixedButton(50, 20), fixedButton(30, 40), fixedButton(20, 10)
+	p.Add(b1, b2, b3)
+	w, h := l.SizeOf(p)
+	if w != 50+30+20+2*10+2*5 || h != 40+2*5 {
+		t.Errorf("Invalid preferred size '%f, %f'.", w, h)
+	}
+	l.Place(p, Rect{X: 100, Y: 100, Width: 200, Height: 60})
+	if b1.GetBounds() != (Rect{X: 105, Y: 105, Width: 50, Height: 50}) {
+		t.Errorf("Invalid first bounds '%v'.", b1.GetBounds())
+	}
+	if b2.GetBounds() != (Rect{X: 165, Y: 105, Width: 30, Height: 50}) {
+		t.Errorf("Invalid second bounds '%v'.", b2.GetBounds())
+	}
+	// the grow factors share the free space
+	b2.SetGrow(1)
+	b3.SetGrow(3)
+	f.SetCross(CROSS_CENTER)
+	l.Place(p, Rect{X: 100, Y: 100, Width: 200, Height: 60})
+	if b2.GetBounds() != (Rect{X: 165, Y: 110, Width: 47.5, Height: 40}) {
+		t.Errorf("Invalid growing bounds '%v'.", b2.GetBounds())
+	}
+	if b3.GetBounds() != (Rect{X: 222.5, Y: 125, Width: 72.5, Height: 10}) {
+		t.Errorf("Invalid growing bounds '%v'.", b3.GetBounds())
+	}
+}
+func TestFlexLayoutColumn(t *testing.T) {
+	l := newTestLayer()
+	f := NewFlexLayout(FLEX_COLUMN, 0)
+	p := NewPanel(f)
+	p.SetPadding(0)
+	b1, b2 := fixedButton(50, 20), fixedButton(30, 40)
+	p.Add(b1, b2)
+	w, h := l.SizeOf(p)
+	if w != 50 || h != 60 {
+		t.Errorf("Invalid preferred size '%f, %f'.", w, h)
+	}
+	f.SetCross(CROSS_END)
+	f.SetJustify(JUSTIFY_END)
+	l.Place(p, Rect{X: 0, Y: 0, Width: 100, Height: 100})
+	if b1.GetBounds() != (Rect{X: 50, Y: 40, Width: 50, Height: 20}) {
+		t.Errorf("Invalid first bounds '%v'.", b1.GetBounds())
+	}
+	f.SetJustify(JUSTIFY_SPACE_BETWEEN)
+	f.SetCross(CROSS_START)
+	l.Place(p, Rect{X: 0, Y: 0, Width: 100, Height: 100})
+	if b2.GetBounds() != (Rect{X: 0, Y: 60, Width: 30, Height: 40}) {
+		t.Errorf("Invalid second bounds '%v'.", b2.GetBounds())
+	}
+	// the hidden children are skipped
+	b1.SetVisible(false)
+	f.SetJustify(JUSTIFY_CENTER)
+	l.Place(p, Rect{X: 0, Y: 0, Width: 100, Height: 100})
+	if b2.GetBounds() != (Rect{X: 0, Y: 30, Width: 30, Height: 40}) {
+		t.Errorf("Invalid centered bounds '%v'.", b2.GetBounds())
+	}
+}
+func TestGridLayout(t *testing.T) {
+	l := newTestLayer()
+	p := NewPanel(NewGridLayout(2, 10))
+	p.SetPadding(0)
+	b1, b2, b3 := fixedButton(50, 20), fixedButton(30, 40), fixedButton(60, 10)
+	p.Add(b1, b2, b3)
+	w, h := l.SizeOf(p)
+	if w != 2*60+10 || h != 40+10+10 {
+		t.Errorf("Invalid preferred size '%f, %f'.", w, h)
+	}
+	l.Place(p, Rect{X: 0, Y: 0, Width: 210, Height: 100})
+	if b2.GetBounds() != (Rect{X: 110, Y: 0, Width: 100, Height: 40}) {
+		t.Errorf("Invalid second bounds '%v'.", b2.GetBounds())
+	}
+	if b3.GetBounds() != (Rect{X: 0, Y: 50, Width: 100, Height: 10}) {
+		t.Errorf("Invalid third bounds '%v'.", b3.GetBounds())
+	}
+}
+func TestNewGridLayoutPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("It should panic without columns.")
+		}
+	}()
+	NewGridLayout(0, 0)
+}
@@ -0,0 +1,385 @@
+package ui
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/model"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// The default number of the visible rows of the dropdown list.
+	DefaultDropdownRows = 6
+)
+
+// List is a control with selectable text rows. If the rows don't fit, the list is scrolled
+// to the selected row. The hovered row is displayed with the hover material, the selected
+// one with the pressed material.
+type List struct {
+	WidgetBase
+	items      []string
+	selected   int
+	hovered    int
+	first      int
+	rows       int
+	onSelect   func(int, string)
+	onActivate func(int, string)
+}
+
+// NewList returns a list without selected item. The select event is called after the
+// selection change.
+func NewList(items []string, onSelect func(int, string)) *List {
+	lst := &List{
+		WidgetBase: newWidgetBase(),
+		items:      items,
+		selected:   -1,
+		hovered:    -1,
+		onSelect:   onSelect,
+	}
+	lst.padding = DefaultControlPadding / 2
+	lst.focusable = true
+	lst.interactive = true
+	return lst
+}
+
+// SetOnActivate sets the event, that is called on the click of a row or with the enter key.
+func (lst *List) SetOnActivate(onActivate func(int, string)) {
+	lst.onActivate = onActivate
+}
+
+// SetItems replaces the rows of the list. The selection is removed.
+func (lst *List) SetItems(items []string) {
+	lst.items = items
+	lst.selected = -1
+	lst.hovered = -1
+	lst.first = 0
+	lst.InvalidateLayout()
+}
+
+// GetItems returns the rows of the list.
+func (lst *List) GetItems() []string {
+	return lst.items
+}
+
+// SetVisibleRows sets the number of the rows of the preferred height. The 0 value
+// means every row.
+func (lst *List) SetVisibleRows(rows int) {
+	lst.rows = rows
+	lst.InvalidateLayout()
+}
+
+// GetSelected returns the index of the selected row or -1.
+func (lst *List) GetSelected() int {
+	return lst.selected
+}
+
+// Select selects the row and calls the select event. The invalid indices are ignored.
+func (lst *List) Select(index int) {
+	if index < 0 || index >= len(lst.items) || index == lst.selected {
+		return
+	}
+	lst.SetSelected(index)
+	if lst.onSelect != nil {
+		lst.onSelect(index, lst.items[index])
+	}
+}
+
+// SetSelected selects the row without calling the select event. The -1 removes the selection.
+func (lst *List) SetSelected(index int) {
+	if index < -1 || index >= len(lst.items) {
+		return
+	}
+	lst.selected = index
+	lst.scrollToSelected()
+	lst.Invalidate()
+}
+
+// activate calls the activate event with the selected row.
+func (lst *List) activate() {
+	if lst.selected >= 0 && lst.onActivate != nil {
+		lst.onActivate(lst.selected, lst.items[lst.selected])
+	}
+}
+
+// rowHeight returns the height of the rows.
+func (lst *List) rowHeight(l *Layer) float32 {
+	return l.LineHeight() + DefaultControlPadding
+}
+
+// visibleRows returns the number of the rows, that fit to the bounds.
+func (lst *List) visibleRows() int {
+	if lst.rows > 0 {
+		return lst.rows
+	}
+	return len(lst.items)
+}
+
+// scrollToSelected updates the first displayed row to make the selected row visible.
+func (lst *List) scrollToSelected() {
+	rows := lst.visibleRows()
+	if lst.selected < 0 || rows == 0 {
+		return
+	}
+	if lst.selected < lst.first {
+		lst.first = lst.selected
+	} else if lst.selected >= lst.first+rows {
+		lst.first = lst.selected - rows + 1
+	}
+}
+
+// rowAt returns the index of the row at the given position or -1.
+func (lst *List) rowAt(l *Layer, y float32) int {
+	content := lst.bounds.Shrink(lst.padding)
+	if y < content.Y {
+		return -1
+	}
+	index := lst.first + int((y-content.Y)/lst.rowHeight(l))
+	if index >= len(lst.items) {
+		return -1
+	}
+	return index
+}
+
+// PreferredSize returns the width of the longest row and the height of the visible rows.
+func (lst *List) PreferredSize(l *Layer) (float32, float32) {
+	var width float32
+	for _, item := range lst.items {
+		width = max32(width, l.TextWidth(item))
+	}
+	return width + 2*DefaultControlPadding + 2*lst.padding, float32(lst.visibleRows())*lst.rowHeight(l) + 2*lst.padding
+}
+
+// Build displays the background, the highlighted rows and the texts.
+func (lst *List) Build(l *Layer) {
+	l.Surface(&lst.WidgetBase, lst.bounds, l.GetTheme().GetMenuItemDefaultMaterial())
+	content := lst.bounds.Shrink(lst.padding)
+	rowHeight := lst.rowHeight(l)
+	for i := lst.first; i < len(lst.items); i++ {
+		row := Rect{X: content.X, Y: content.Y + float32(i-lst.first)*rowHeight, Width: content.Width, Height: rowHeight}
+		if row.Y >= content.Y+content.Height {
+			break
+		}
+		if i == lst.selected {
+			l.Surface(&lst.WidgetBase, row, l.GetTheme().GetMenuItemPressedMaterial())
+		} else if i == lst.hovered && lst.state == STATE_HOVER {
+			l.Surface(&lst.WidgetBase, row, l.GetTheme().GetMenuItemHoverMaterial())
+		}
+		l.Text(&lst.WidgetBase, lst.items[i], row.X+DefaultControlPadding, l.TextY(row), model.ALIGN_LEFT, l.GetTheme().GetLabelColor())
+	}
+}
+
+// MouseMove highlights the row under the pointer.
+func (lst *List) MouseMove(l *Layer, x, y float32) {
+	if row := lst.rowAt(l, y); row != lst.hovered {
+		lst.hovered = row
+		lst.Invalidate()
+	}
+}
+
+// MouseUp selects and activates the row under the pointer.
+func (lst *List) MouseUp(l *Layer, x, y float32, inside bool) {
+	if !inside {
+		return
+	}
+	if row := lst.rowAt(l, y); row >= 0 {
+		lst.Select(row)
+		lst.activate()
+	}
+}
+
+// Key moves the selection with the arrow, home, end and page keys. The enter key
+// activates the selected row.
+func (lst *List) Key(l *Layer, key glfw.Key) bool {
+	if len(lst.items) == 0 {
+		return false
+	}
+	page := lst.visibleRows()
+	switch key {
+	case glfw.KeyUp:
+		if lst.selected > 0 {
+			lst.Select(lst.selected - 1)
+		}
+		return true
+	case glfw.KeyDown:
+		lst.Select(lst.selected + 1)
+		return true
+	case glfw.KeyPageUp:
+		if lst.selected > page {
+			lst.Select(lst.selected - page)
+		} else {
+			lst.Select(0)
+		}
+		return true
+	case glfw.KeyPageDown:
+		if lst.selected+page < len(lst.items) {
+			lst.Select(lst.selected + page)
+		} else {
+			lst.Select(len(lst.items) - 1)
+		}
+		return true
+	case glfw.KeyHome:
+		lst.Select(0)
+		return true
+	case glfw.KeyEnd:
+		lst.Select(len(lst.items) - 1)
+		return true
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		lst.activate()
+		return true
+	}
+	return false
+}
+
+// Dropdown is a control, that displays the selected item. On activation the items
+// are displayed in a popup list.
+type Dropdown struct {
+	WidgetBase
+	items    []string
+	selected int
+	list     *List
+	onChange func(int, string)
+	// the layer of the opened list.
+	layer *Layer
+}
+
+// NewDropdown returns a dropdown with the given items, selection and change event.
+func NewDropdown(items []string, selected int, onChange func(int, string)) *Dropdown {
+	d := &Dropdown{
+		WidgetBase: newWidgetBase(),
+		items:      items,
+		selected:   -1,
+		onChange:   onChange,
+	}
+	if selected >= 0 && selected < len(items) {
+		d.selected = selected
+	}
+	d.padding = DefaultControlPadding
+	d.focusable = true
+	d.interactive = true
+	d.list = NewList(items, nil)
+	d.list.SetOnActivate(func(index int, item string) {
+		d.closeList()
+		d.change(index)
+	})
+	return d
+}
+
+// GetSelected returns the index of the selected item or -1.
+func (d *Dropdown) GetSelected() int {
+	return d.selected
+}
+
+// GetSelectedItem returns the selected item. Without selection it returns empty string.
+func (d *Dropdown) GetSelectedItem() string {
+	if d.selected < 0 {
+		return ""
+	}
+	return d.items[d.selected]
+}
+
+// SetSelected selects the item without calling the change event.
+func (d *Dropdown) SetSelected(index int) {
+	if index < -1 || index >= len(d.items) {
+		return
+	}
+	d.selected = index
+	d.Invalidate()
+}
+
+// IsOpen returns true if the list of the dropdown is displayed.
+func (d *Dropdown) IsOpen() bool {
+	return d.layer != nil && d.layer.GetPopup() == d.list
+}
+
+// change selects the item and calls the change event.
+func (d *Dropdown) change(index int) {
+	if index < 0 || index >= len(d.items) || index == d.selected {
+		return
+	}
+	d.SetSelected(index)
+	if d.onChange != nil {
+		d.onChange(index, d.items[index])
+	}
+}
+
+// openList displays the list below the dropdown, or above it, if the list doesn't fit.
+func (d *Dropdown) openList(l *Layer) {
+	rows := DefaultDropdownRows
+	if len(d.items) < rows {
+		rows = len(d.items)
+	}
+	d.list.SetVisibleRows(rows)
+	d.list.SetSelected(d.selected)
+	d.list.hovered = -1
+	_, height := l.SizeOf(d.list)
+	r := Rect{X: d.bounds.X, Y: d.bounds.Y + d.bounds.Height, Width: d.bounds.Width, Height: height}
+	if r.Y+r.Height > l.windowHeight && d.bounds.Y-height >= 0 {
+		r.Y = d.bounds.Y - height
+	}
+	l.OpenPopup(d, d.list, r)
+	d.layer = l
+}
+
+// closeList hides the list.
+func (d *Dropdown) closeList() {
+	if d.IsOpen() {
+		d.layer.ClosePopup()
+	}
+	d.layer = nil
+}
+
+// PreferredSize returns the width of the longest item with the arrow and the line height.
+func (d *Dropdown) PreferredSize(l *Layer) (float32, float32) {
+	var width float32
+	for _, item := range d.items {
+		width = max32(width, l.TextWidth(item))
+	}
+	return width + l.TextWidth("v") + 4*d.padding, l.LineHeight() + 2*d.padding
+}
+
+// Build displays the surface, the selected item and the arrow.
+func (d *Dropdown) Build(l *Layer) {
+	l.Surface(&d.WidgetBase, d.bounds, l.ControlMaterial(d))
+	content := d.bounds.Shrink(d.padding)
+	y := l.TextY(d.bounds)
+	l.Text(&d.WidgetBase, d.GetSelectedItem(), content.X, y, model.ALIGN_LEFT, l.GetTheme().GetInputColor())
+	l.Text(&d.WidgetBase, "v", content.X+content.Width, y, model.ALIGN_RIGHT, l.GetTheme().GetLabelColor())
+}
+
+// MouseUp toggles the list.
+func (d *Dropdown) MouseUp(l *Layer, x, y float32, inside bool) {
+	if !inside {
+		return
+	}
+	if d.IsOpen() {
+		d.closeList()
+	} else {
+		d.openList(l)
+	}
+}
+
+// Key opens the list with the enter and space keys. The up and down keys change the selection.
+// If the list is opened, the keys are passed to the list, the escape closes it.
+func (d *Dropdown) Key(l *Layer, key glfw.Key) bool {
+	if d.IsOpen() {
+		if key == glfw.KeyEscape {
+			d.closeList()
+			return true
+		}
+		if key == glfw.KeySpace {
+			key = glfw.KeyEnter
+		}
+		return d.list.Key(l, key)
+	}
+	switch key {
+	case glfw.KeyEnter, glfw.KeyKPEnter, glfw.KeySpace:
+		d.openList(l)
+		return true
+	case glfw.KeyUp:
+		d.change(d.selected - 1)
+		return true
+	case glfw.KeyDown:
+		d.change(d.selected + 1)
+		return true
+	}
+	return false
+}
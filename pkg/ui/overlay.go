@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"github.com/akosgarai/playground_engine/pkg/input"
+	"github.com/akosgarai/playground_engine/pkg/interfaces"
+	"github.com/akosgarai/playground_engine/pkg/store"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// OverlayScreen displays ui layers on top of a base screen. The layers get the input first,
+// from the top one to the bottom one. If a layer wants the pointer or the keyboard, the
+// screens below it get empty button or key stores and the actions of these devices are hidden.
+type OverlayScreen struct {
+	base    interfaces.Screen
+	layers  []*Layer
+	actions interfaces.RoActionMap
+}
+
+// NewOverlayScreen returns an overlay screen with the given base screen and layers.
+// The last layer is the top one.
+func NewOverlayScreen(base interfaces.Screen, layers ...*Layer) *OverlayScreen {
+	return &OverlayScreen{
+		base:   base,
+		layers: layers,
+	}
+}
+
+// GetBase returns the base screen.
+func (o *OverlayScreen) GetBase() interfaces.Screen {
+	return o.base
+}
+
+// AddLayer inserts the layer to the top.
+func (o *OverlayScreen) AddLayer(l *Layer) {
+	o.layers = append(o.layers, l)
+}
+
+// RemoveLayer deletes the layer.
+func (o *OverlayScreen) RemoveLayer(l *Layer) {
+	for i := range o.layers {
+		if o.layers[i] == l {
+			o.layers = append(o.layers[:i], o.layers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Log returns the log of the base screen.
+func (o *OverlayScreen) Log() string {
+	return o.base.Log()
+}
+
+// Draw draws the base screen, then the layers from the bottom one to the top one.
+func (o *OverlayScreen) Draw(wrapper interfaces.GLWrapper) {
+	o.base.Draw(wrapper)
+	for i := range o.layers {
+		o.layers[i].Draw(wrapper)
+	}
+}
+
+// Update updates the layers from the top one, then the base screen. The buttons and the
+// keys and their actions are hidden from the screens below the layer, that wants them.
+func (o *OverlayScreen) Update(dt float64, p interfaces.Pointer, keyStore interfaces.RoKeyStore, buttonStore interfaces.RoButtonStore) {
+	actions := o.actions
+	for i := len(o.layers) - 1; i >= 0; i-- {
+		o.layers[i].SetActionMap(actions)
+		o.layers[i].Update(dt, p, keyStore, buttonStore)
+		wantsPointer, wantsKeyboard := o.layers[i].WantsPointer(), o.layers[i].WantsKeyboard()
+		if wantsPointer {
+			buttonStore = store.NewGlfwMouseStore()
+		}
+		if wantsKeyboard {
+			keyStore = store.NewGlfwKeyStore()
+		}
+		actions = input.BlockActions(actions, wantsKeyboard, wantsPointer)
+	}
+	if as, ok := o.base.(interfaces.ActionScreen); ok {
+		as.SetActionMap(actions)
+	}
+	o.base.Update(dt, p, keyStore, buttonStore)
+}
+
+// Export exports the base screen.
+func (o *OverlayScreen) Export(path string) {
+	o.base.Export(path)
+}
+
+// GetCamera returns the camera of the base screen.
+func (o *OverlayScreen) GetCamera() interfaces.Camera {
+	return o.base.GetCamera()
+}
+
+// GetClosestModelMeshDistance returns the closest model and mesh of the base screen.
+func (o *OverlayScreen) GetClosestModelMeshDistance() (interfaces.Model, interfaces.Mesh, float32) {
+	return o.base.GetClosestModelMeshDistance()
+}
+
+// SetUniformFloat sets the uniform of the base screen.
+func (o *OverlayScreen) SetUniformFloat(key string, value float32) {
+	o.base.SetUniformFloat(key, value)
+}
+
+// SetUniformVector sets the uniform of the base screen.
+func (o *OverlayScreen) SetUniformVector(key string, value mgl32.Vec3) {
+	o.base.SetUniformVector(key, value)
+}
+
+// CharCallback passes the character to the top layer, that wants the keyboard.
+// Without such layer, the base screen gets it.
+func (o *OverlayScreen) CharCallback(char rune, wrapper interfaces.GLWrapper) {
+	for i := len(o.layers) - 1; i >= 0; i-- {
+		if o.layers[i].WantsKeyboard() {
+			o.layers[i].CharCallback(char, wrapper)
+			return
+		}
+	}
+	o.base.CharCallback(char, wrapper)
+}
+
+// SetWindowSize sets the window size of the base screen and the layers.
+func (o *OverlayScreen) SetWindowSize(width, height float32) {
+	o.base.SetWindowSize(width, height)
+	for i := range o.layers {
+		o.layers[i].SetWindowSize(width, height)
+	}
+}
+
+// SetWrapper sets the wrapper of the base screen and the layers.
+func (o *OverlayScreen) SetWrapper(w interfaces.GLWrapper) {
+	o.base.SetWrapper(w)
+	for i := range o.layers {
+		o.layers[i].SetWrapper(w)
+	}
+}
+
+// SetGamepadStore passes the gamepad store to the base screen, if it uses the gamepad.
+func (o *OverlayScreen) SetGamepadStore(gamepad interfaces.RoGamepadStore) {
+	if gs, ok := o.base.(interfaces.GamepadScreen); ok {
+		gs.SetGamepadStore(gamepad)
+	}
+}
+
+// SetActionMap passes the action map to the layers and to the base screen. The Update
+// hides the actions from the screens below the layers, that want their devices.
+func (o *OverlayScreen) SetActionMap(actions interfaces.RoActionMap) {
+	o.actions = actions
+	for i := range o.layers {
+		o.layers[i].SetActionMap(actions)
+	}
+	if as, ok := o.base.(interfaces.ActionScreen); ok {
+		as.SetActionMap(actions)
+	}
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// The width of the scrollbar in pixels.
+	ScrollbarWidth = float32(12)
+	// The minimal height of the scrollbar knob in pixels.
+	ScrollbarMinKnob = float32(16)
+)
+
+// ScrollView displays the visible part of its content. The content is clipped to the view,
+// it is scrolled with the scrollbar, with the keys or to the focused widget.
+type ScrollView struct {
+	WidgetBase
+	content Widget
+	offset  float32
+	// the height of the content, it is updated in the arrange.
+	contentHeight float32
+	// the state of the knob dragging.
+	dragging          bool
+	dragY, dragOffset float32
+}
+
+// NewScrollView returns a scroll view with the given content.
+func NewScrollView(content Widget) *ScrollView {
+	s := &ScrollView{WidgetBase: newWidgetBase()}
+	s.content = content
+	s.children = []Widget{content}
+	s.clipChildren = true
+	s.focusable = true
+	s.interactive = true
+	return s
+}
+
+// GetContent returns the content widget.
+func (s *ScrollView) GetContent() Widget {
+	return s.content
+}
+
+// GetOffset returns the scrolled distance in pixels.
+func (s *ScrollView) GetOffset() float32 {
+	return s.offset
+}
+
+// ScrollTo sets the scrolled distance. It is clamped to the size of the content.
+func (s *ScrollView) ScrollTo(offset float32) {
+	if offset == s.offset {
+		return
+	}
+	s.offset = offset
+	s.InvalidateLayout()
+}
+
+// ScrollBy changes the scrolled distance with the given value.
+func (s *ScrollView) ScrollBy(delta float32) {
+	s.ScrollTo(s.offset + delta)
+}
+
+// scrollIntoView scrolls the view to make the given area visible.
+func (s *ScrollView) scrollIntoView(r Rect) {
+	if r.Y < s.bounds.Y {
+		s.ScrollBy(r.Y - s.bounds.Y)
+	} else if r.Y+r.Height > s.bounds.Y+s.bounds.Height {
+		s.ScrollBy(min32(r.Y+r.Height-s.bounds.Y-s.bounds.Height, r.Y-s.bounds.Y))
+	}
+}
+
+// maxOffset returns the maximal scrolled distance.
+func (s *ScrollView) maxOffset() float32 {
+	return max32(s.contentHeight-s.bounds.Height, 0)
+}
+
+// PreferredSize returns the size of the content with the scrollbar.
+func (s *ScrollView) PreferredSize(l *Layer) (float32, float32) {
+	w, h := l.SizeOf(s.content)
+	return w + ScrollbarWidth, h
+}
+
+// Arrange places the content to the view with the scrolled offset. The content
+// is at least as high as the view.
+func (s *ScrollView) Arrange(l *Layer) {
+	_, h := l.SizeOf(s.content)
+	s.contentHeight = max32(h, s.bounds.Height)
+	s.offset = min32(max32(s.offset, 0), s.maxOffset())
+	l.Place(s.content, Rect{X: s.bounds.X, Y: s.bounds.Y - s.offset, Width: max32(s.bounds.Width-ScrollbarWidth, 0), Height: s.contentHeight})
+}
+
+// track returns the area of the scrollbar.
+func (s *ScrollView) track() Rect {
+	return Rect{X: s.bounds.X + s.bounds.Width - ScrollbarWidth, Y: s.bounds.Y, Width: ScrollbarWidth, Height: s.bounds.Height}
+}
+
+// knob returns the area of the scrollbar knob.
+func (s *ScrollView) knob() Rect {
+	track := s.track()
+	if s.contentHeight <= 0 {
+		return track
+	}
+	height := min32(max32(track.Height*track.Height/s.contentHeight, ScrollbarMinKnob), track.Height)
+	position := float32(0)
+	if limit := s.maxOffset(); limit > 0 {
+		position = s.offset / limit * (track.Height - height)
+	}
+	return Rect{X: track.X, Y: track.Y + position, Width: track.Width, Height: height}
+}
+
+// Build displays the scrollbar.
+func (s *ScrollView) Build(l *Layer) {
+	l.Surface(&s.WidgetBase, s.track(), l.GetTheme().GetFrameMaterial())
+	l.Surface(&s.WidgetBase, s.knob(), l.ControlMaterial(s))
+}
+
+// MouseDown starts the knob dragging, or pages the view, if the track is clicked.
+func (s *ScrollView) MouseDown(l *Layer, x, y float32) {
+	if !s.track().Contains(x, y) {
+		return
+	}
+	knob := s.knob()
+	if knob.Contains(x, y) {
+		s.dragging = true
+		s.dragY, s.dragOffset = y, s.offset
+	} else if y < knob.Y {
+		s.ScrollBy(-s.bounds.Height)
+	} else {
+		s.ScrollBy(s.bounds.Height)
+	}
+}
+
+// MouseDrag moves the knob with the pointer.
+func (s *ScrollView) MouseDrag(l *Layer, x, y float32) {
+	if !s.dragging {
+		return
+	}
+	track, knob := s.track(), s.knob()
+	if free := track.Height - knob.Height; free > 0 {
+		s.ScrollTo(s.dragOffset + (y-s.dragY)/free*s.maxOffset())
+	}
+}
+
+// MouseUp stops the knob dragging.
+func (s *ScrollView) MouseUp(l *Layer, x, y float32, inside bool) {
+	s.dragging = false
+}
+
+// Key scrolls the view with the arrow, page, home and end keys.
+func (s *ScrollView) Key(l *Layer, key glfw.Key) bool {
+	switch key {
+	case glfw.KeyUp:
+		s.ScrollBy(-l.LineHeight())
+		return true
+	case glfw.KeyDown:
+		s.ScrollBy(l.LineHeight())
+		return true
+	case glfw.KeyPageUp:
+		s.ScrollBy(-s.bounds.Height)
+		return true
+	case glfw.KeyPageDown:
+		s.ScrollBy(s.bounds.Height)
+		return true
+	case glfw.KeyHome:
+		s.ScrollTo(0)
+		return true
+	case glfw.KeyEnd:
+		s.ScrollTo(s.maxOffset())
+		return true
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/akosgarai/playground_engine/pkg/model"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// The default size of the text areas in characters.
+	DefaultTextAreaColumns = 24
+	DefaultTextAreaRows    = 4
+)
+
+// TextArea displays a wrapped multi line text. If it is editable, the characters are
+// appended to the text, the backspace deletes the last character, the enter starts
+// a new line. The cursor is displayed at the end of the focused editable text.
+type TextArea struct {
+	WidgetBase
+	text     string
+	editable bool
+	onChange func(string)
+}
+
+// NewTextArea returns a text area with the given text. The change event is called after
+// the edit of the text.
+func NewTextArea(text string, editable bool, onChange func(string)) *TextArea {
+	t := &TextArea{
+		WidgetBase: newWidgetBase(),
+		text:       text,
+		editable:   editable,
+		onChange:   onChange,
+	}
+	t.padding = DefaultControlPadding
+	t.focusable = editable
+	t.interactive = true
+	return t
+}
+
+// SetText updates the text without calling the change event.
+func (t *TextArea) SetText(text string) {
+	t.text = text
+	t.Invalidate()
+}
+
+// GetText returns the text.
+func (t *TextArea) GetText() string {
+	return t.text
+}
+
+// IsEditable returns true if the text could be edited.
+func (t *TextArea) IsEditable() bool {
+	return t.editable
+}
+
+// change updates the text and calls the change event.
+func (t *TextArea) change(text string) {
+	t.SetText(text)
+	if t.onChange != nil {
+		t.onChange(text)
+	}
+}
+
+// PreferredSize returns the size of the default columns and rows.
+func (t *TextArea) PreferredSize(l *Layer) (float32, float32) {
+	return DefaultTextAreaColumns*l.TextWidth("n") + 2*t.padding, DefaultTextAreaRows*l.LineHeight() + 2*t.padding
+}
+
+// Build displays the surface and the lines of the text. If the lines don't fit, the
+// last lines are displayed during the edit.
+func (t *TextArea) Build(l *Layer) {
+	l.Surface(&t.WidgetBase, t.bounds, l.ControlMaterial(t))
+	content := t.bounds.Shrink(t.padding)
+	text := t.text
+	editing := t.editable && l.GetFocus() == t
+	if editing {
+		text += "|"
+	}
+	lines := wrapText(l, text, content.Width)
+	rows := int(content.Height / l.LineHeight())
+	if editing && rows > 0 && len(lines) > rows {
+		lines = lines[len(lines)-rows:]
+	}
+	l.Text(&t.WidgetBase, strings.Join(lines, "\n"), content.X, content.Y+(l.LineHeight()-l.capHeight())/2, model.ALIGN_LEFT, l.GetTheme().GetInputColor())
+}
+
+// Char appends the character to the editable text.
+func (t *TextArea) Char(l *Layer, char rune) bool {
+	if !t.editable {
+		return false
+	}
+	t.change(t.text + string(char))
+	return true
+}
+
+// Key handles the backspace and the enter keys of the editable text.
+func (t *TextArea) Key(l *Layer, key glfw.Key) bool {
+	if !t.editable {
+		return false
+	}
+	switch key {
+	case glfw.KeyBackspace:
+		if runes := []rune(t.text); len(runes) > 0 {
+			t.change(string(runes[:len(runes)-1]))
+		}
+		return true
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		t.change(t.text + "\n")
+		return true
+	}
+	return false
+}
+
+// wrapText splits the text to lines, that fit to the given width. The words are not split,
+// the too long words have their own lines. The '\n' characters start new lines.
+func wrapText(l *Layer, text string, width float32) []string {
+	var result []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Split(paragraph, " ") {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && l.TextWidth(candidate) > width {
+				result = append(result, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		result = append(result, line)
+	}
+	return result
+}
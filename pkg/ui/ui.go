@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"path"
+	"runtime"
+
+	"github.com/akosgarai/playground_engine/pkg/mesh"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// The states of the widgets. The material of the widget surfaces depends on it.
+	STATE_NORMAL = iota
+	STATE_HOVER
+	STATE_PRESSED
+)
+
+func baseDirUi() string {
+	_, filename, _, _ := runtime.Caller(1)
+	return path.Dir(filename)
+}
+
+// Rect is an area of the window in pixels. The origin is the top left corner
+// of the window, the Y axis points down.
+type Rect struct {
+	X, Y          float32
+	Width, Height float32
+}
+
+// Contains returns true if the given point is inside the rectangle.
+func (r Rect) Contains(x, y float32) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// IsEmpty returns true if the rectangle has no area.
+func (r Rect) IsEmpty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Intersect returns the common area of the rectangles. If they don't overlap,
+// the result is an empty rectangle.
+func (r Rect) Intersect(o Rect) Rect {
+	x1, y1 := max32(r.X, o.X), max32(r.Y, o.Y)
+	x2, y2 := min32(r.X+r.Width, o.X+o.Width), min32(r.Y+r.Height, o.Y+o.Height)
+	if x2 <= x1 || y2 <= y1 {
+		return Rect{X: x1, Y: y1}
+	}
+	return Rect{X: x1, Y: y1, Width: x2 - x1, Height: y2 - y1}
+}
+
+// Shrink returns the rectangle without the given padding on every side.
+func (r Rect) Shrink(padding float32) Rect {
+	return Rect{X: r.X + padding, Y: r.Y + padding, Width: max32(r.Width-2*padding, 0), Height: max32(r.Height-2*padding, 0)}
+}
+
+// Widget is an element of the ui tree. The widgets embed the WidgetBase, that provides
+// the common state and the default behaviour, so that they override only the methods
+// that they need.
+type Widget interface {
+	// Base returns the common state of the widget.
+	Base() *WidgetBase
+	// PreferredSize returns the size in pixels, that the widget needs for its content.
+	PreferredSize(l *Layer) (float32, float32)
+	// Arrange places the children inside the bounds of the widget.
+	Arrange(l *Layer)
+	// Build sets up the surfaces and the texts of the widget.
+	Build(l *Layer)
+	// The mouse events of the interactive widgets. The coordinates are in pixels. The inside
+	// flag of the MouseUp is true if the button has been released over the widget.
+	MouseDown(l *Layer, x, y float32)
+	MouseDrag(l *Layer, x, y float32)
+	MouseUp(l *Layer, x, y float32, inside bool)
+	MouseMove(l *Layer, x, y float32)
+	// Key handles a key press of the focused widget. If it returns false, the key is
+	// passed to the parent widget.
+	Key(l *Layer, key glfw.Key) bool
+	// Char handles the character input of the focused widget.
+	Char(l *Layer, char rune) bool
+}
+
+// WidgetBase is the common part of the widgets. It holds the tree, the layout parameters,
+// the state and the meshes of the widget.
+type WidgetBase struct {
+	bounds Rect
+	// the visible part of the bounds. It is smaller than the bounds inside the scroll views.
+	clip     Rect
+	children []Widget
+	layout   Layout
+	// the fixed size of the widget. The 0 value means that the preferred size is used.
+	width, height float32
+	// the share of the widget from the free space of the flex layouts.
+	grow    float32
+	padding float32
+	visible bool
+	// the focusable widgets could be focused with the mouse or the tab key, the
+	// interactive widgets get the mouse events.
+	focusable   bool
+	interactive bool
+	// the children are clipped to the bounds, like the content of the scroll views.
+	clipChildren bool
+	state        int
+	needsLayout  bool
+	needsBuild   bool
+	// the surfaces of the widget. The first used surfaces are drawn.
+	surfaces []*mesh.TexturedMaterialMesh
+	used     int
+	// the parent mesh of the texts. It is in the center of the widget.
+	textRoot *mesh.PointMesh
+}
+
+// newWidgetBase returns a visible widget base without children.
+func newWidgetBase() WidgetBase {
+	return WidgetBase{
+		visible:     true,
+		needsLayout: true,
+	}
+}
+
+// Base returns the widget base itself. It makes the embedding widgets implement the Widget interface.
+func (b *WidgetBase) Base() *WidgetBase {
+	return b
+}
+
+// Add appends the widgets to the children.
+func (b *WidgetBase) Add(widgets ...Widget) {
+	b.children = append(b.children, widgets...)
+	b.InvalidateLayout()
+}
+
+// Remove deletes the widget from the children.
+func (b *WidgetBase) Remove(w Widget) {
+	for i := range b.children {
+		if b.children[i] == w {
+			b.children = append(b.children[:i], b.children[i+1:]...)
+			b.InvalidateLayout()
+			return
+		}
+	}
+}
+
+// Children returns the children of the widget.
+func (b *WidgetBase) Children() []Widget {
+	return b.children
+}
+
+// SetLayout sets the layout, that arranges the children.
+func (b *WidgetBase) SetLayout(layout Layout) {
+	b.layout = layout
+	b.InvalidateLayout()
+}
+
+// SetSize sets the fixed size of the widget. The 0 value means that the preferred
+// size is used in that direction.
+func (b *WidgetBase) SetSize(width, height float32) {
+	b.width = width
+	b.height = height
+	b.InvalidateLayout()
+}
+
+// SetGrow sets the share of the widget from the free space of the flex layouts.
+func (b *WidgetBase) SetGrow(grow float32) {
+	b.grow = grow
+	b.InvalidateLayout()
+}
+
+// SetPadding sets the space between the bounds and the content of the widget.
+func (b *WidgetBase) SetPadding(padding float32) {
+	b.padding = padding
+	b.InvalidateLayout()
+}
+
+// GetPadding returns the padding of the widget.
+func (b *WidgetBase) GetPadding() float32 {
+	return b.padding
+}
+
+// SetVisible shows or hides the widget with its children.
+func (b *WidgetBase) SetVisible(visible bool) {
+	b.visible = visible
+	b.InvalidateLayout()
+}
+
+// IsVisible returns true if the widget is displayed.
+func (b *WidgetBase) IsVisible() bool {
+	return b.visible
+}
+
+// GetBounds returns the area of the widget in pixels.
+func (b *WidgetBase) GetBounds() Rect {
+	return b.bounds
+}
+
+// GetState returns the state (normal, hover, pressed) of the widget.
+func (b *WidgetBase) GetState() int {
+	return b.state
+}
+
+// Invalidate marks the widget for rebuild. It has to be called after the change
+// of the content, that doesn't change the size of the widget.
+func (b *WidgetBase) Invalidate() {
+	b.needsBuild = true
+}
+
+// InvalidateLayout marks the widget for a new layout. It has to be called after the
+// change of the content, that could change the size of the widget.
+func (b *WidgetBase) InvalidateLayout() {
+	b.needsLayout = true
+}
+
+// PreferredSize returns the preferred size of the layout, or the size of the padding without layout.
+func (b *WidgetBase) PreferredSize(l *Layer) (float32, float32) {
+	if b.layout != nil {
+		return b.layout.PreferredSize(l, b)
+	}
+	return 2 * b.padding, 2 * b.padding
+}
+
+// Arrange places the children with the layout.
+func (b *WidgetBase) Arrange(l *Layer) {
+	if b.layout != nil {
+		b.layout.Arrange(l, b)
+	}
+}
+
+// The default implementations of the widget functions do nothing.
+func (b *WidgetBase) Build(l *Layer)                              {}
+func (b *WidgetBase) MouseDown(l *Layer, x, y float32)            {}
+func (b *WidgetBase) MouseDrag(l *Layer, x, y float32)            {}
+func (b *WidgetBase) MouseUp(l *Layer, x, y float32, inside bool) {}
+func (b *WidgetBase) MouseMove(l *Layer, x, y float32)            {}
+func (b *WidgetBase) Key(l *Layer, key glfw.Key) bool             { return false }
+func (b *WidgetBase) Char(l *Layer, char rune) bool               { return false }
+
+// min32 returns the smaller value.
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// max32 returns the greater value.
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+	"github.com/akosgarai/playground_engine/pkg/model"
+	"github.com/akosgarai/playground_engine/pkg/pointer"
+	"github.com/akosgarai/playground_engine/pkg/store"
+	"github.com/akosgarai/playground_engine/pkg/testhelper"
+	"github.com/akosgarai/playground_engine/pkg/theme"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	testWindowWidth  = float32(800)
+	testWindowHeight = float32(600)
+)
+
+var (
+	wrapperMock testhelper.GLWrapperMock
+	wrapperReal glwrapper.Wrapper
+)
+
+// newTestLayer returns a layer with mocked shaders, so that it could be used without gl context.
+func newTestLayer() *Layer {
+	cs, err := model.LoadCharset(baseDirUi()+DefaultFontFile, 32, 127, 40.0, 72, wrapperMock)
+	if err != nil {
+		panic(err)
+	}
+	l := &Layer{
+		wrapper:          wrapperMock,
+		windowWidth:      testWindowWidth,
+		windowHeight:     testWindowHeight,
+		charset:          cs,
+		theme:            theme.Default,
+		fontScale:        DefaultFontScale,
+		backgroundShader: testhelper.ShaderMock{},
+		fontShader:       testhelper.ShaderMock{},
+		keys:             make(map[glfw.Key]bool),
+		uniformFloat:     make(map[string]float32),
+		uniformVector:    make(map[string]mgl32.Vec3),
+	}
+	l.surfaceTexture.TransparentTexture(1, 1, 1, "paper", wrapperMock)
+	return l
+}
+
+// testInput is the input state of the layer updates. The position is in pixels.
+type testInput struct {
+	x, y    float32
+	keys    store.GlfwKeyStore
+	buttons store.GlfwMouseStore
+}
+
+func newTestInput() *testInput {
+	return &testInput{keys: store.NewGlfwKeyStore(), buttons: store.NewGlfwMouseStore()}
+}
+func (i *testInput) update(l *Layer) {
+	l.Update(10, newTestPointer(i.x, i.y), i.keys, i.buttons)
+}
+
+// newTestPointer returns the pointer of the given pixel position of the test window.
+func newTestPointer(x, y float32) *pointer.Pointer {
+	return pointer.New(float64(x/testWindowWidth*2-1), float64(1-y/testWindowHeight*2), 0, 0)
+}
+
+// click moves the pointer to the position, then presses and releases the left button.
+func (i *testInput) click(l *Layer, x, y float32) {
+	i.x, i.y = x, y
+	i.buttons.Set(glfw.MouseButtonLeft, true)
+	i.update(l)
+	i.buttons.Set(glfw.MouseButtonLeft, false)
+	i.update(l)
+}
+
+// press presses and releases the key.
+func (i *testInput) press(l *Layer, key glfw.Key) {
+	i.keys.Set(key, true)
+	i.update(l)
+	i.keys.Set(key, false)
+	i.update(l)
+}
+
+func TestRect(t *testing.T) {
+	r := Rect{X: 10, Y: 20, Width: 100, Height: 50}
+	if !r.Contains(10, 20) || !r.Contains(109, 69) {
+		t.Error("The rectangle should contain the points.")
+	}
+	if r.Contains(110, 20) || r.Contains(10, 70) || r.Contains(9, 30) {
+		t.Error("The rectangle shouldn't contain the points.")
+	}
+	if r.IsEmpty() {
+		t.Error("The rectangle shouldn't be empty.")
+	}
+	i := r.Intersect(Rect{X: 50, Y: 0, Width: 100, Height: 40})
+	if i != (Rect{X: 50, Y: 20, Width: 60, Height: 20}) {
+		t.Errorf("Invalid intersection '%v'.", i)
+	}
+	if !r.Intersect(Rect{X: 200, Y: 200, Width: 10, Height: 10}).IsEmpty() {
+		t.Error("The intersection should be empty.")
+	}
+	s := r.Shrink(5)
+	if s != (Rect{X: 15, Y: 25, Width: 90, Height: 40}) {
+		t.Errorf("Invalid shrinked rectangle '%v'.", s)
+	}
+}
+func TestWidgetBase(t *testing.T) {
+	p := NewPanel(nil)
+	b1 := NewButton("b1", nil)
+	b2 := NewButton("b2", nil)
+	p.needsLayout = false
+	p.Add(b1, b2)
+	if len(p.Children()) != 2 || !p.needsLayout {
+		t.Error("The children should be added.")
+	}
+	p.Remove(b1)
+	if len(p.Children()) != 1 || p.Children()[0] != b2 {
+		t.Error("The child should be removed.")
+	}
+	p.SetSize(10, 20)
+	p.SetGrow(2)
+	p.SetPadding(3)
+	p.SetVisible(false)
+	if p.width != 10 || p.height != 20 || p.grow != 2 || p.GetPadding() != 3 || p.IsVisible() {
+		t.Error("Invalid widget parameters.")
+	}
+	if p.GetState() != STATE_NORMAL {
+		t.Error("Invalid initial state.")
+	}
+}
@@ -0,0 +1,378 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/akosgarai/playground_engine/pkg/model"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// The default padding of the controls in pixels.
+	DefaultControlPadding = float32(6)
+	// The default width of the sliders in pixels.
+	DefaultSliderWidth = float32(160)
+)
+
+// isActivateKey returns true if the key activates the focused control.
+func isActivateKey(key glfw.Key) bool {
+	return key == glfw.KeyEnter || key == glfw.KeyKPEnter || key == glfw.KeySpace
+}
+
+// Panel is a container widget with the frame material background.
+type Panel struct {
+	WidgetBase
+}
+
+// NewPanel returns a panel, that arranges its children with the given layout.
+func NewPanel(layout Layout) *Panel {
+	p := &Panel{WidgetBase: newWidgetBase()}
+	p.layout = layout
+	p.padding = DefaultControlPadding
+	p.interactive = true
+	return p
+}
+
+// Build displays the background of the panel.
+func (p *Panel) Build(l *Layer) {
+	l.Surface(&p.WidgetBase, p.bounds, l.GetTheme().GetFrameMaterial())
+}
+
+// Label is a non interactive text. The lines of the text are separated with '\n'.
+type Label struct {
+	WidgetBase
+	text  string
+	align int
+}
+
+// NewLabel returns a left aligned label.
+func NewLabel(text string) *Label {
+	return &Label{
+		WidgetBase: newWidgetBase(),
+		text:       text,
+		align:      model.ALIGN_LEFT,
+	}
+}
+
+// SetText updates the text of the label.
+func (lb *Label) SetText(text string) {
+	lb.text = text
+	lb.InvalidateLayout()
+}
+
+// GetText returns the text of the label.
+func (lb *Label) GetText() string {
+	return lb.text
+}
+
+// SetAlign sets the horizontal alignment of the text.
+func (lb *Label) SetAlign(align int) {
+	lb.align = align
+	lb.Invalidate()
+}
+
+// PreferredSize returns the size of the text with the padding.
+func (lb *Label) PreferredSize(l *Layer) (float32, float32) {
+	lines := 1
+	for _, r := range lb.text {
+		if r == '\n' {
+			lines++
+		}
+	}
+	return l.TextWidth(lb.text) + 2*lb.padding, float32(lines)*l.LineHeight() + 2*lb.padding
+}
+
+// Build prints the text. The single line texts are vertically centered.
+func (lb *Label) Build(l *Layer) {
+	content := lb.bounds.Shrink(lb.padding)
+	x := content.X
+	switch lb.align {
+	case model.ALIGN_CENTER:
+		x += content.Width / 2
+		break
+	case model.ALIGN_RIGHT:
+		x += content.Width
+		break
+	}
+	y := l.TextY(Rect{Y: content.Y, Height: l.LineHeight()})
+	l.Text(&lb.WidgetBase, lb.text, x, y, lb.align, l.GetTheme().GetLabelColor())
+}
+
+// Button is a control with text. The click event is called on the release of the mouse
+// button over the button or with the enter and space keys.
+type Button struct {
+	WidgetBase
+	text    string
+	onClick func()
+}
+
+// NewButton returns a button with the given text and click event.
+func NewButton(text string, onClick func()) *Button {
+	b := &Button{
+		WidgetBase: newWidgetBase(),
+		text:       text,
+		onClick:    onClick,
+	}
+	b.padding = DefaultControlPadding
+	b.focusable = true
+	b.interactive = true
+	return b
+}
+
+// SetText updates the text of the button.
+func (b *Button) SetText(text string) {
+	b.text = text
+	b.InvalidateLayout()
+}
+
+// GetText returns the text of the button.
+func (b *Button) GetText() string {
+	return b.text
+}
+
+// SetOnClick sets the click event of the button.
+func (b *Button) SetOnClick(onClick func()) {
+	b.onClick = onClick
+}
+
+// PreferredSize returns the size of the text with the padding.
+func (b *Button) PreferredSize(l *Layer) (float32, float32) {
+	return l.TextWidth(b.text) + 4*b.padding, l.LineHeight() + 2*b.padding
+}
+
+// Build displays the button surface and the centered text.
+func (b *Button) Build(l *Layer) {
+	l.Surface(&b.WidgetBase, b.bounds, l.ControlMaterial(b))
+	l.Text(&b.WidgetBase, b.text, b.bounds.X+b.bounds.Width/2, l.TextY(b.bounds), model.ALIGN_CENTER, l.GetTheme().GetLabelColor())
+}
+
+// MouseUp clicks the button, if the mouse button is released over it.
+func (b *Button) MouseUp(l *Layer, x, y float32, inside bool) {
+	if inside {
+		b.Click()
+	}
+}
+
+// Key clicks the button with the enter and space keys.
+func (b *Button) Key(l *Layer, key glfw.Key) bool {
+	if isActivateKey(key) {
+		b.Click()
+		return true
+	}
+	return false
+}
+
+// Click calls the click event of the button.
+func (b *Button) Click() {
+	if b.onClick != nil {
+		b.onClick()
+	}
+}
+
+// Checkbox is a control with a box and a text. The box is marked with the pressed material
+// if the checkbox is checked. It is toggled with the mouse and with the enter and space keys.
+type Checkbox struct {
+	WidgetBase
+	text     string
+	checked  bool
+	onChange func(bool)
+}
+
+// NewCheckbox returns a checkbox with the given text, state and change event.
+func NewCheckbox(text string, checked bool, onChange func(bool)) *Checkbox {
+	c := &Checkbox{
+		WidgetBase: newWidgetBase(),
+		text:       text,
+		checked:    checked,
+		onChange:   onChange,
+	}
+	c.padding = DefaultControlPadding
+	c.focusable = true
+	c.interactive = true
+	return c
+}
+
+// IsChecked returns the state of the checkbox.
+func (c *Checkbox) IsChecked() bool {
+	return c.checked
+}
+
+// SetChecked sets the state of the checkbox without calling the change event.
+func (c *Checkbox) SetChecked(checked bool) {
+	c.checked = checked
+	c.Invalidate()
+}
+
+// Toggle inverts the state of the checkbox and calls the change event.
+func (c *Checkbox) Toggle() {
+	c.SetChecked(!c.checked)
+	if c.onChange != nil {
+		c.onChange(c.checked)
+	}
+}
+
+// PreferredSize returns the size of the box and the text with the padding.
+func (c *Checkbox) PreferredSize(l *Layer) (float32, float32) {
+	box := l.LineHeight()
+	return box + c.padding + l.TextWidth(c.text) + 2*c.padding, box + 2*c.padding
+}
+
+// Build displays the box, the mark and the text.
+func (c *Checkbox) Build(l *Layer) {
+	content := c.bounds.Shrink(c.padding)
+	box := l.LineHeight()
+	boxRect := Rect{X: content.X, Y: content.Y + (content.Height-box)/2, Width: box, Height: box}
+	l.Surface(&c.WidgetBase, boxRect, l.ControlMaterial(c))
+	if c.checked {
+		l.Surface(&c.WidgetBase, boxRect.Shrink(box/4), l.GetTheme().GetMenuItemPressedMaterial())
+	}
+	l.Text(&c.WidgetBase, c.text, content.X+box+c.padding, l.TextY(content), model.ALIGN_LEFT, l.GetTheme().GetLabelColor())
+}
+
+// MouseUp toggles the checkbox, if the mouse button is released over it.
+func (c *Checkbox) MouseUp(l *Layer, x, y float32, inside bool) {
+	if inside {
+		c.Toggle()
+	}
+}
+
+// Key toggles the checkbox with the enter and space keys.
+func (c *Checkbox) Key(l *Layer, key glfw.Key) bool {
+	if isActivateKey(key) {
+		c.Toggle()
+		return true
+	}
+	return false
+}
+
+// Slider is a control for selecting a value from a range. The value is set by the knob
+// dragging, by clicking the track, or with the left and right keys.
+type Slider struct {
+	WidgetBase
+	min, max, step float32
+	value          float32
+	onChange       func(float32)
+}
+
+// NewSlider returns a slider with the given range, value and change event. In case of
+// invalid range it panics.
+func NewSlider(min, max, value float32, onChange func(float32)) *Slider {
+	if max <= min {
+		panic("Invalid slider range.")
+	}
+	s := &Slider{
+		WidgetBase: newWidgetBase(),
+		min:        min,
+		max:        max,
+		onChange:   onChange,
+	}
+	s.value = s.clamp(value)
+	s.padding = DefaultControlPadding
+	s.focusable = true
+	s.interactive = true
+	return s
+}
+
+// SetStep sets the step of the value. The 0 value means continuous slider. The keys
+// change the value with the step, or with the tenth of the range without step.
+func (s *Slider) SetStep(step float32) {
+	s.step = step
+	s.value = s.clamp(s.value)
+	s.Invalidate()
+}
+
+// GetValue returns the value of the slider.
+func (s *Slider) GetValue() float32 {
+	return s.value
+}
+
+// SetValue sets the value without calling the change event.
+func (s *Slider) SetValue(value float32) {
+	s.value = s.clamp(value)
+	s.Invalidate()
+}
+
+// clamp returns the closest valid value.
+func (s *Slider) clamp(value float32) float32 {
+	if s.step > 0 {
+		value = s.min + float32(math.Round(float64((value-s.min)/s.step)))*s.step
+	}
+	return min32(max32(value, s.min), s.max)
+}
+
+// change sets the value and calls the change event, if the value has been changed.
+func (s *Slider) change(value float32) {
+	value = s.clamp(value)
+	if value == s.value {
+		return
+	}
+	s.value = value
+	s.Invalidate()
+	if s.onChange != nil {
+		s.onChange(value)
+	}
+}
+
+// knobSize returns the width of the knob.
+func (s *Slider) knobSize() float32 {
+	return s.bounds.Shrink(s.padding).Height
+}
+
+// valueAt returns the value at the given pixel position.
+func (s *Slider) valueAt(x float32) float32 {
+	content := s.bounds.Shrink(s.padding)
+	knob := s.knobSize()
+	track := content.Width - knob
+	if track <= 0 {
+		return s.min
+	}
+	return s.min + (x-content.X-knob/2)/track*(s.max-s.min)
+}
+
+// PreferredSize returns the default width and the line height with the padding.
+func (s *Slider) PreferredSize(l *Layer) (float32, float32) {
+	return DefaultSliderWidth, l.LineHeight() + 2*s.padding
+}
+
+// Build displays the track and the knob.
+func (s *Slider) Build(l *Layer) {
+	content := s.bounds.Shrink(s.padding)
+	knob := s.knobSize()
+	l.Surface(&s.WidgetBase, Rect{X: content.X, Y: content.Y + content.Height*3/8, Width: content.Width, Height: content.Height / 4}, l.GetTheme().GetFrameMaterial())
+	position := (s.value - s.min) / (s.max - s.min) * (content.Width - knob)
+	l.Surface(&s.WidgetBase, Rect{X: content.X + position, Y: content.Y, Width: knob, Height: knob}, l.ControlMaterial(s))
+}
+
+// MouseDown moves the knob to the pointer.
+func (s *Slider) MouseDown(l *Layer, x, y float32) {
+	s.change(s.valueAt(x))
+}
+
+// MouseDrag moves the knob with the pointer.
+func (s *Slider) MouseDrag(l *Layer, x, y float32) {
+	s.change(s.valueAt(x))
+}
+
+// Key changes the value with the left, right, home and end keys.
+func (s *Slider) Key(l *Layer, key glfw.Key) bool {
+	step := s.step
+	if step == 0 {
+		step = (s.max - s.min) / 10
+	}
+	switch key {
+	case glfw.KeyLeft:
+		s.change(s.value - step)
+		return true
+	case glfw.KeyRight:
+		s.change(s.value + step)
+		return true
+	case glfw.KeyHome:
+		s.change(s.min)
+		return true
+	case glfw.KeyEnd:
+		s.change(s.max)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,340 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/akosgarai/playground_engine/pkg/testhelper"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func TestButton(t *testing.T) {
+	l := newTestLayer()
+	clicks := 0
+	b := NewButton("button", func() { clicks++ })
+	b.SetSize(100, 40)
+	l.Add(b, ANCHOR_TOP_LEFT, 100, 100)
+	in := newTestInput()
+	in.click(l, 150, 120)
+	if clicks != 1 {
+		t.Errorf("Invalid number of clicks '%d'.", clicks)
+	}
+	if l.GetFocus() != b {
+		t.Error("The button should be focused.")
+	}
+	// release outside of the button
+	in.buttons.Set(glfw.MouseButtonLeft, true)
+	in.update(l)
+	if b.GetState() != STATE_PRESSED {
+		t.Error("The button should be pressed.")
+	}
+	in.x = 300
+	in.buttons.Set(glfw.MouseButtonLeft, false)
+	in.update(l)
+	if clicks != 1 || b.GetState() != STATE_NORMAL {
+		t.Error("The release outside of the button shouldn't click.")
+	}
+	in.press(l, glfw.KeyEnter)
+	in.press(l, glfw.KeySpace)
+	if clicks != 3 {
+		t.Errorf("Invalid number of clicks after keys '%d'.", clicks)
+	}
+	b.SetText("new")
+	if b.GetText() != "new" {
+		t.Error("Invalid text.")
+	}
+}
+func TestLabel(t *testing.T) {
+	l := newTestLayer()
+	lb := NewLabel("label")
+	w, h := l.SizeOf(lb)
+	if w != l.TextWidth("label") || h != l.LineHeight() {
+		t.Errorf("Invalid label size '%f, %f'.", w, h)
+	}
+	lb.SetText("label\nlabel")
+	if _, h = l.SizeOf(lb); h != 2*l.LineHeight() {
+		t.Errorf("Invalid multi line label height '%f'.", h)
+	}
+	if lb.GetText() != "label\nlabel" || !lb.needsLayout {
+		t.Error("The text should be updated.")
+	}
+	l.Add(lb, ANCHOR_CENTER, 0, 0)
+	newTestInput().update(l)
+	if lb.textRoot == nil {
+		t.Error("The text should be printed.")
+	}
+	if l.hit(400, 300) != nil {
+		t.Error("The label shouldn't be interactive.")
+	}
+}
+func TestCheckbox(t *testing.T) {
+	l := newTestLayer()
+	var state bool
+	c := NewCheckbox("check", false, func(v bool) { state = v })
+	c.SetSize(100, 30)
+	l.Add(c, ANCHOR_TOP_LEFT, 0, 0)
+	in := newTestInput()
+	in.click(l, 10, 10)
+	if !c.IsChecked() || !state {
+		t.Error("The checkbox should be checked.")
+	}
+	if c.used != 2 {
+		t.Errorf("The box and the mark should be displayed, instead of '%d' surfaces.", c.used)
+	}
+	in.press(l, glfw.KeySpace)
+	if c.IsChecked() || state {
+		t.Error("The checkbox shouldn't be checked.")
+	}
+	c.SetChecked(true)
+	if !c.IsChecked() || state {
+		t.Error("The SetChecked shouldn't call the change event.")
+	}
+}
+func TestSlider(t *testing.T) {
+	l := newTestLayer()
+	var value float32
+	s := NewSlider(0, 10, 5, func(v float32) { value = v })
+	// the knob is 20 pixels wide, the track is 100 pixels long.
+	s.SetSize(120, 20)
+	s.SetPadding(0)
+	l.Add(s, ANCHOR_TOP_LEFT, 0, 0)
+	in := newTestInput()
+	in.x, in.y = 10, 10
+	in.buttons.Set(glfw.MouseButtonLeft, true)
+	in.update(l)
+	if s.GetValue() != 0 || value != 0 {
+		t.Errorf("Invalid value after press '%f'.", s.GetValue())
+	}
+	in.x = 60
+	in.update(l)
+	if !testhelper.Float32ApproxEqual(s.GetValue(), 5, 0.001) || value != s.GetValue() {
+		t.Errorf("Invalid value after drag '%f'.", s.GetValue())
+	}
+	// the drag continues outside of the slider
+	in.x = 500
+	in.update(l)
+	if s.GetValue() != 10 {
+		t.Errorf("Invalid value after drag '%f'.", s.GetValue())
+	}
+	in.buttons.Set(glfw.MouseButtonLeft, false)
+	in.update(l)
+	s.SetStep(2)
+	in.press(l, glfw.KeyLeft)
+	if s.GetValue() != 8 || value != 8 {
+		t.Errorf("Invalid value after key '%f'.", s.GetValue())
+	}
+	in.press(l, glfw.KeyHome)
+	if s.GetValue() != 0 {
+		t.Errorf("Invalid value after home key '%f'.", s.GetValue())
+	}
+	s.SetValue(3.1)
+	if s.GetValue() != 4 {
+		t.Errorf("The value should be rounded to the step instead of '%f'.", s.GetValue())
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("It should panic with invalid range.")
+		}
+	}()
+	NewSlider(1, 1, 1, nil)
+}
+func TestList(t *testing.T) {
+	l := newTestLayer()
+	selected, activated := -1, -1
+	lst := NewList([]string{"a", "b", "c", "d"}, func(i int, s string) { selected = i })
+	lst.SetOnActivate(func(i int, s string) { activated = i })
+	lst.SetVisibleRows(2)
+	lst.SetPadding(0)
+	l.Add(lst, ANCHOR_TOP_LEFT, 0, 0)
+	_, h := l.SizeOf(lst)
+	if h != 2*lst.rowHeight(l) {
+		t.Errorf("Invalid list height '%f'.", h)
+	}
+	in := newTestInput()
+	in.click(l, 5, lst.rowHeight(l)*1.5)
+	if selected != 1 || activated != 1 || lst.GetSelected() != 1 {
+		t.Errorf("Invalid selection after click '%d', '%d'.", selected, activated)
+	}
+	in.press(l, glfw.KeyDown)
+	in.press(l, glfw.KeyDown)
+	if selected != 3 || lst.first != 2 {
+		t.Errorf("Invalid selection after keys '%d', first row '%d'.", selected, lst.first)
+	}
+	in.press(l, glfw.KeyDown)
+	in.press(l, glfw.KeyHome)
+	if selected != 0 || lst.first != 0 {
+		t.Errorf("Invalid selection after home key '%d'.", selected)
+	}
+	in.press(l, glfw.KeyEnter)
+	if activated != 0 {
+		t.Errorf("Invalid activation '%d'.", activated)
+	}
+	lst.SetItems([]string{"x"})
+	if lst.GetSelected() != -1 || len(lst.GetItems()) != 1 {
+		t.Error("The items should be replaced.")
+	}
+}
+func TestDropdown(t *testing.T) {
+	l := newTestLayer()
+	changed := -1
+	d := NewDropdown([]string{"a", "b", "c"}, 0, func(i int, s string) { changed = i })
+	d.SetSize(100, 30)
+	l.Add(d, ANCHOR_TOP_LEFT, 0, 0)
+	in := newTestInput()
+	in.click(l, 10, 10)
+	if !d.IsOpen() || l.GetPopup() != d.list {
+		t.Fatal("The list should be opened.")
+	}
+	if d.list.GetBounds().Y != 30 || d.list.GetBounds().Width != 100 {
+		t.Errorf("Invalid list bounds '%v'.", d.list.GetBounds())
+	}
+	// click on the third row
+	in.click(l, 10, 30+d.list.padding+d.list.rowHeight(l)*2.5)
+	if d.IsOpen() || changed != 2 || d.GetSelectedItem() != "c" {
+		t.Errorf("The third item should be selected instead of '%d'.", changed)
+	}
+	if l.GetFocus() != d {
+		t.Error("The dropdown should keep the focus.")
+	}
+	// keyboard selection
+	in.press(l, glfw.KeyEnter)
+	if !d.IsOpen() {
+		t.Fatal("The list should be opened with the enter.")
+	}
+	in.press(l, glfw.KeyUp)
+	in.press(l, glfw.KeyEnter)
+	if d.IsOpen() || d.GetSelected() != 1 || changed != 1 {
+		t.Errorf("The second item should be selected instead of '%d'.", d.GetSelected())
+	}
+	in.press(l, glfw.KeySpace)
+	in.press(l, glfw.KeyEscape)
+	if d.IsOpen() || l.GetFocus() != d {
+		t.Error("The escape should close the list.")
+	}
+	in.press(l, glfw.KeyDown)
+	if d.GetSelected() != 2 {
+		t.Error("The down key should select the next item.")
+	}
+	// click outside closes the list
+	in.press(l, glfw.KeyEnter)
+	in.click(l, 500, 500)
+	if d.IsOpen() || l.GetPopup() != nil || l.GetFocus() != nil {
+		t.Error("The click outside should close the list.")
+	}
+}
+func TestDropdownAbove(t *testing.T) {
+	l := newTestLayer()
+	d := NewDropdown([]string{"a", "b", "c"}, -1, nil)
+	d.SetSize(100, 30)
+	l.Add(d, ANCHOR_BOTTOM_LEFT, 0, 0)
+	in := newTestInput()
+	in.click(l, 10, testWindowHeight-10)
+	if !d.IsOpen() {
+		t.Fatal("The list should be opened.")
+	}
+	if bounds := d.list.GetBounds(); bounds.Y+bounds.Height != testWindowHeight-30 {
+		t.Errorf("The list should be above the dropdown instead of '%v'.", bounds)
+	}
+	if d.GetSelectedItem() != "" {
+		t.Error("The dropdown shouldn't have selected item.")
+	}
+}
+func TestScrollView(t *testing.T) {
+	l := newTestLayer()
+	content := NewPanel(NewFlexLayout(FLEX_COLUMN, 0))
+	content.SetPadding(0)
+	buttons := make([]*Button, 10)
+	for i := range buttons {
+		buttons[i] = fixedButton(50, 50)
+		content.Add(buttons[i])
+	}
+	s := NewScrollView(content)
+	s.SetSize(100, 200)
+	l.Add(s, ANCHOR_TOP_LEFT, 0, 0)
+	in := newTestInput()
+	in.update(l)
+	if content.GetBounds().Height != 500 || content.GetBounds().Width != 100-ScrollbarWidth {
+		t.Errorf("Invalid content bounds '%v'.", content.GetBounds())
+	}
+	if !buttons[5].clip.IsEmpty() {
+		t.Error("The hidden button should be clipped.")
+	}
+	if l.hit(10, 210) != nil {
+		t.Error("The clipped widgets shouldn't be hit.")
+	}
+	// the focus scrolls the view
+	// the scroll view, then the buttons get the focus.
+	for i := 0; i < 7; i++ {
+		in.press(l, glfw.KeyTab)
+	}
+	if l.GetFocus() != buttons[5] || s.GetOffset() != 100 {
+		t.Errorf("Invalid offset after focus change '%f'.", s.GetOffset())
+	}
+	if buttons[5].GetBounds().Y != 150 {
+		t.Errorf("Invalid button position '%v'.", buttons[5].GetBounds())
+	}
+	// the keys that the button doesn't use, scroll the view
+	in.press(l, glfw.KeyEnd)
+	if s.GetOffset() != 300 {
+		t.Errorf("Invalid offset after end key '%f'.", s.GetOffset())
+	}
+	s.ScrollBy(1000)
+	in.update(l)
+	if s.GetOffset() != 300 {
+		t.Errorf("The offset should be clamped instead of '%f'.", s.GetOffset())
+	}
+	// page up with the track click, then drag the knob
+	in.click(l, 95, 10)
+	if s.GetOffset() != 100 {
+		t.Errorf("Invalid offset after track click '%f'.", s.GetOffset())
+	}
+	knob := s.knob()
+	in.x, in.y = 95, knob.Y+1
+	in.buttons.Set(glfw.MouseButtonLeft, true)
+	in.update(l)
+	in.y = knob.Y + 1 + (s.track().Height-knob.Height)/3
+	in.update(l)
+	if !testhelper.Float32ApproxEqual(s.GetOffset(), 200, 0.01) {
+		t.Errorf("Invalid offset after knob drag '%f'.", s.GetOffset())
+	}
+}
+func TestTextArea(t *testing.T) {
+	l := newTestLayer()
+	var text string
+	ta := NewTextArea("", true, func(s string) { text = s })
+	ta.SetSize(200, 100)
+	l.Add(ta, ANCHOR_TOP_LEFT, 0, 0)
+	in := newTestInput()
+	in.click(l, 10, 10)
+	if l.GetFocus() != ta || !l.WantsKeyboard() {
+		t.Fatal("The text area should be focused.")
+	}
+	for _, r := range "ab c" {
+		l.CharCallback(r, wrapperMock)
+	}
+	in.press(l, glfw.KeyEnter)
+	l.CharCallback('d', wrapperMock)
+	if text != "ab c\nd" || ta.GetText() != text {
+		t.Errorf("Invalid text '%s'.", text)
+	}
+	in.press(l, glfw.KeyBackspace)
+	if text != "ab c\n" {
+		t.Errorf("Invalid text after backspace '%s'.", text)
+	}
+	readOnly := NewTextArea("text", false, nil)
+	if readOnly.Char(l, 'x') || readOnly.Key(l, glfw.KeyBackspace) || readOnly.GetText() != "text" || readOnly.IsEditable() {
+		t.Error("The read only text shouldn't be edited.")
+	}
+}
+func TestWrapText(t *testing.T) {
+	l := newTestLayer()
+	width := l.TextWidth("aaa bbb")
+	lines := wrapText(l, "aaa bbb ccc\nddd", width)
+	if len(lines) != 3 || lines[0] != "aaa bbb" || lines[1] != "ccc" || lines[2] != "ddd" {
+		t.Errorf("Invalid lines '%v'.", lines)
+	}
+	lines = wrapText(l, "aaaaaaaaaaaa b", width)
+	if len(lines) != 2 || lines[0] != "aaaaaaaaaaaa" {
+		t.Errorf("The long word should have its own line '%v'.", lines)
+	}
+}
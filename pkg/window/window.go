@@ -23,6 +23,9 @@ type WindowBuilder struct {
 	title          string
 	fullScreen     bool
 	decorated      bool
+	// the version of the gl context.
+	majorVersion int
+	minorVersion int
 }
 
 // NewWindowBuilder returns a WindowBuilder. If the glfw lib could not be initialized, it panics.
@@ -37,6 +40,8 @@ func NewWindowBuilder() *WindowBuilder {
 		title:          DefaultWindowTitle,
 		fullScreen:     DefaultWindowFullScreen,
 		decorated:      DefaultWindowDecorated,
+		majorVersion:   glwrapper.GL_MAJOR_VERSION,
+		minorVersion:   glwrapper.GL_MINOR_VERSION,
 	}
 }
 
@@ -55,6 +60,13 @@ func (b *WindowBuilder) SetDecorated(d bool) {
 	b.decorated = d
 }
 
+// The major and minor versions are requested for the gl context. The default is the GL_MAJOR_VERSION,
+// GL_MINOR_VERSION of the glwrapper, the compute shaders need the GL_COMPUTE_MAJOR_VERSION, GL_COMPUTE_MINOR_VERSION.
+func (b *WindowBuilder) SetContextVersion(major, minor int) {
+	b.majorVersion = major
+	b.minorVersion = minor
+}
+
 // The width and height values are used as the first 2 parameter of the CreateWindow function.
 func (b *WindowBuilder) SetWindowSize(w, h int) {
 	b.width = w
@@ -94,8 +106,8 @@ func (b *WindowBuilder) PrintCurrentMonitorData() {
 	fmt.Printf("Current monitor workarea: %d - %d, %d * %d\n", wax, way, waw, wah)
 }
 func (b *WindowBuilder) windowHints() {
-	glfw.WindowHint(glfw.ContextVersionMajor, glwrapper.GL_MAJOR_VERSION)
-	glfw.WindowHint(glfw.ContextVersionMinor, glwrapper.GL_MINOR_VERSION)
+	glfw.WindowHint(glfw.ContextVersionMajor, b.majorVersion)
+	glfw.WindowHint(glfw.ContextVersionMinor, b.minorVersion)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
 	// Specifies whether the window will be resizable by the user.
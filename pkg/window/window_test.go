@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/akosgarai/playground_engine/pkg/glwrapper"
+
 	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
@@ -114,6 +116,28 @@ func TestWindowBuilderSetDecorated(t *testing.T) {
 		}
 	}()
 }
+func TestWindowBuilderSetContextVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping it in short mode")
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				defer glfw.Terminate()
+				t.Error("SetContextVersion shouldn't be failed.")
+			}
+		}()
+		builder := NewWindowBuilder()
+		defer glfw.Terminate()
+		if builder.majorVersion != glwrapper.GL_MAJOR_VERSION || builder.minorVersion != glwrapper.GL_MINOR_VERSION {
+			t.Error("Invalid default context version")
+		}
+		builder.SetContextVersion(glwrapper.GL_COMPUTE_MAJOR_VERSION, glwrapper.GL_COMPUTE_MINOR_VERSION)
+		if builder.majorVersion != glwrapper.GL_COMPUTE_MAJOR_VERSION || builder.minorVersion != glwrapper.GL_COMPUTE_MINOR_VERSION {
+			t.Error("Failed set context version")
+		}
+	}()
+}
 func TestWindowBuilderSetWindowSize(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping it in short mode")